@@ -2,62 +2,446 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"flag"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
-	"time"
+
+	_ "github.com/lib/pq"
 
 	"meetsync/internal/config"
+	"meetsync/internal/database"
+	"meetsync/internal/directory"
+	"meetsync/internal/events"
+	"meetsync/internal/filestore"
+	"meetsync/internal/guest"
+	"meetsync/internal/health"
+	"meetsync/internal/listener"
+	"meetsync/internal/metering"
+	"meetsync/internal/metrics"
+	"meetsync/internal/middleware"
+	"meetsync/internal/models"
+	"meetsync/internal/notifications"
+	"meetsync/internal/policy"
+	"meetsync/internal/precompute"
+	"meetsync/internal/quota"
+	"meetsync/internal/reporting"
+	"meetsync/internal/repositories"
 	"meetsync/internal/router"
+	"meetsync/internal/rsvp"
+	"meetsync/internal/sanitize"
+	"meetsync/internal/services"
+	"meetsync/internal/version"
 	"meetsync/pkg/logs"
 )
 
 func main() {
+	showVersion := flag.Bool("version", false, "print the version and exit")
+	flag.Parse()
+	if *showVersion {
+		fmt.Println("meetsync", version.Get())
+		return
+	}
+
 	// Load configuration
 	cfg := config.Load()
 
 	// Initialize logger
 	logger := logs.New(cfg.Log.Level, os.Stdout)
-	logs.SetDefaultLogger(logger)
+	redaction, err := logs.ParseRedaction(cfg.Log.PIIRedaction)
+	if err != nil {
+		logger.Fatal("Invalid LOG_PII_REDACTION: %v", err)
+	}
+	logs.SetDefaultLogger(logger.WithRedaction(redaction))
+
+	logs.Info("Starting MeetSync API server %s", version.Get())
+	settings := cfg.Settings()
+	for _, setting := range settings {
+		logs.Info("Config %s=%q (%s)", setting.Name, setting.Value, setting.Source)
+	}
+
+	if cfg.Deployment.FaultInjection {
+		logs.Warn("Fault injection is enabled; do not use this configuration in production")
+	}
+	if cfg.Deployment.ReadOnly {
+		logs.Info("Read-only mode: requests that change state are rejected and background jobs are skipped")
+	}
+
+	// Open the storage driver; drivers other than memory register themselves when their
+	// package is imported
+	storage, err := repositories.Open(cfg.Storage.Driver, cfg.Storage.DSN)
+	if err != nil {
+		logs.Fatal("Failed to open storage: %v", err)
+	}
+	logs.Info("Storage driver: %s", cfg.Storage.Driver)
+
+	// Bring the schema of the Postgres database up to date when the storage runs on one,
+	// before any replica reads from it
+	var dbPool metrics.PoolStatser
+	if storage.DB != nil {
+		database.Configure(storage.DB, database.PoolConfig{
+			MaxOpenConns:    cfg.DB.MaxOpenConns,
+			MaxIdleConns:    cfg.DB.MaxIdleConns,
+			ConnMaxLifetime: cfg.DB.ConnMaxLifetime,
+			ConnMaxIdleTime: cfg.DB.ConnMaxIdleTime,
+		})
+		// Read replicas reject schema changes; the primary migrates them
+		if cfg.Deployment.ReadOnly {
+			logs.Info("Read-only mode: leaving database migrations to the primary instance")
+		} else {
+			applied, err := database.Migrate(storage.DB, cfg.DB.MigrationTimeout)
+			if err != nil {
+				logs.Fatal("Failed to migrate database: %v", err)
+			}
+			logs.Info("Database schema up to date (%d migrations applied)", applied)
+		}
+		dbPool = storage.DB
+	} else if cfg.DB.DSN != "" {
+		logs.Warn("DB_DSN is not used by the %s storage driver", cfg.Storage.Driver)
+	}
+
+	// Check that the storage backend fits the deployment; storage that is not shared
+	// would make every replica serve its own copy of the data. Webhooks and jobs stay in
+	// the memory of API instances; workers serve no client traffic, so they have none.
+	processLocal := health.ProcessLocal
+	if cfg.Deployment.Mode == "worker" {
+		processLocal = nil
+	}
+	stateCheck := health.CheckState(cfg.Storage.Driver, storage.Shared, cfg.Deployment.Replicas, processLocal)
+	if !stateCheck.OK {
+		switch cfg.Deployment.StateCheckMode {
+		case "warn":
+			logs.Warn("Unsafe deployment: %s", stateCheck.Message)
+		default:
+			logs.Fatal("Refusing to start: %s (set STATE_CHECK_MODE=warn to start anyway)", stateCheck.Message)
+		}
+	}
+
+	// Create event publisher
+	publisher, err := events.NewPublisher(cfg.Events.Backend, cfg.Events.URL, cfg.Events.SubjectPrefix)
+	if err != nil {
+		logs.Fatal("Failed to create event publisher: %v", err)
+	}
+	logs.Info("Event publishing backend: %s", cfg.Events.Backend)
 
-	logs.Info("Starting MeetSync API server")
-	logs.Info("Log level: %s", cfg.Log.Level)
+	// Hand off refreshing stored recommendations to scheduler workers when a precompute
+	// queue is configured; workers store what they compute, so recommendations must be
+	// materialized
+	var precomputeQueue *precompute.NATSQueue
+	var queue precompute.Queue
+	var consumer precompute.Consumer
+	if cfg.Scheduling.PrecomputeQueueURL != "" {
+		if !cfg.Scheduling.MaterializeRecommendations {
+			logs.Fatal("PRECOMPUTE_QUEUE_URL needs MATERIALIZE_RECOMMENDATIONS=true")
+		}
+		precomputeQueue = precompute.NewNATSQueue(cfg.Scheduling.PrecomputeQueueURL, cfg.Events.SubjectPrefix)
+		queue = precomputeQueue
+		logs.Info("Precompute queue: %s", cfg.Scheduling.PrecomputeQueueURL)
+	}
+	switch cfg.Deployment.Mode {
+	case "api":
+	case "worker":
+		if precomputeQueue == nil {
+			logs.Fatal("SERVICE_MODE=worker needs PRECOMPUTE_QUEUE_URL")
+		}
+		consumer = precomputeQueue
+		logs.Info("Running as a scheduler worker")
+	default:
+		logs.Fatal("Invalid SERVICE_MODE %q: use api or worker", cfg.Deployment.Mode)
+	}
+
+	// Create notifier
+	notifier, err := notifications.NewNotifier(cfg.Notifications.Backend, notifications.SMTPConfig{
+		Host:     cfg.Notifications.SMTPHost,
+		Port:     cfg.Notifications.SMTPPort,
+		Username: cfg.Notifications.SMTPUsername,
+		Password: cfg.Notifications.SMTPPassword,
+		From:     cfg.Notifications.SMTPFrom,
+	})
+	if err != nil {
+		logs.Fatal("Failed to create notifier: %v", err)
+	}
+	logs.Info("Notifications backend: %s", cfg.Notifications.Backend)
+
+	// Load notification templates, overriding the built-in ones
+	templates, err := notifications.LoadTemplates(cfg.Notifications.TemplatesDir)
+	if err != nil {
+		logs.Fatal("Failed to load notification templates: %v", err)
+	}
+
+	// Create error reporter
+	reporter, err := reporting.NewReporter(cfg.Reporting.Backend, cfg.Reporting.URL)
+	if err != nil {
+		logs.Fatal("Failed to create error reporter: %v", err)
+	}
+	middleware.SetErrorReporter(reporter)
+	logs.Info("Error reporting backend: %s", cfg.Reporting.Backend)
+
+	// Create metering sink
+	meteringSink, err := metering.NewSink(cfg.Metering.Backend, cfg.Metering.URL)
+	if err != nil {
+		logs.Fatal("Failed to create metering sink: %v", err)
+	}
+	logs.Info("Metering backend: %s", cfg.Metering.Backend)
+
+	// Create the signer of one-click RSVP links
+	rsvpSecret := []byte(cfg.Notifications.RSVPSecret)
+	if len(rsvpSecret) == 0 {
+		rsvpSecret = make([]byte, 32)
+		if _, err := rand.Read(rsvpSecret); err != nil {
+			logs.Fatal("Failed to generate RSVP link secret: %v", err)
+		}
+		logs.Warn("RSVP_LINK_SECRET is not set; RSVP links sent before a restart will stop working")
+	}
+	rsvpSigner := rsvp.NewSigner(rsvpSecret, cfg.Notifications.RSVPLinkTTL)
+
+	// Create the signer of guest access links, which shares the RSVP link secret
+	guestSigner := guest.NewSigner(rsvpSecret, cfg.Notifications.GuestLinkTTL)
+
+	// Create the file store exports and backups are downloaded from; local files are
+	// served by the API from URLs signed with the RSVP link secret
+	files, err := filestore.New(filestore.Config{
+		Backend:         cfg.Files.Backend,
+		Dir:             cfg.Files.Dir,
+		BaseURL:         strings.TrimSuffix(cfg.Server.PublicURL, "/") + "/api/files",
+		Secret:          rsvpSecret,
+		Bucket:          cfg.Files.Bucket,
+		Prefix:          cfg.Files.Prefix,
+		Endpoint:        cfg.Files.Endpoint,
+		Region:          cfg.Files.Region,
+		AccessKeyID:     cfg.Files.AccessKeyID,
+		SecretAccessKey: cfg.Files.SecretAccessKey,
+		CredentialsFile: cfg.Files.CredentialsFile,
+	})
+	if err != nil {
+		logs.Fatal("Failed to create file store: %v", err)
+	}
+	if cfg.Files.LinkTTL <= 0 || cfg.Files.LinkTTL > filestore.MaxURLTTL {
+		logs.Fatal("Invalid FILE_LINK_TTL %s: use a positive duration of at most %s", cfg.Files.LinkTTL, filestore.MaxURLTTL)
+	}
+	logs.Info("File storage backend: %s", cfg.Files.Backend)
+
+	// Resolve the poll page encoded by QR codes; without a web app of its own, polls
+	// default to a page under the public URL
+	pollURL := cfg.Server.PollURL
+	if pollURL == "" {
+		pollURL = strings.TrimSuffix(cfg.Server.PublicURL, "/") + "/poll/{id}"
+	}
+
+	// Create the directory users are synchronized from
+	var userDirectory directory.Directory
+	directorySync := models.DirectorySyncOptions{
+		OnConflict: models.DirectoryConflictPolicy(cfg.Directory.OnConflict),
+		OnMissing:  models.DirectoryMissingPolicy(cfg.Directory.OnMissing),
+	}
+	if cfg.Directory.URL != "" {
+		if !directorySync.OnConflict.Valid() {
+			logs.Fatal("Invalid LDAP_SYNC_ON_CONFLICT %q: use link or skip", cfg.Directory.OnConflict)
+		}
+		if !directorySync.OnMissing.Valid() {
+			logs.Fatal("Invalid LDAP_SYNC_ON_MISSING %q: use keep or deactivate", cfg.Directory.OnMissing)
+		}
+		ldapDirectory, err := directory.NewLDAP(directory.Config{
+			URL:            cfg.Directory.URL,
+			BindDN:         cfg.Directory.BindDN,
+			BindPassword:   cfg.Directory.BindPassword,
+			BaseDN:         cfg.Directory.BaseDN,
+			Filter:         cfg.Directory.UserFilter,
+			IDAttribute:    cfg.Directory.IDAttribute,
+			NameAttribute:  cfg.Directory.NameAttribute,
+			EmailAttribute: cfg.Directory.EmailAttribute,
+		})
+		if err != nil {
+			logs.Fatal("Failed to create LDAP directory: %v", err)
+		}
+		userDirectory = ldapDirectory
+		logs.Info("Directory sync: %s every %s", cfg.Directory.URL, cfg.Directory.SyncInterval)
+	}
+
+	// Create access log
+	var accessLog func(http.Handler) http.Handler
+	if cfg.Log.AccessLogFormat != "none" {
+		out := os.Stdout
+		if cfg.Log.AccessLogFile != "" {
+			out, err = os.OpenFile(cfg.Log.AccessLogFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+			if err != nil {
+				logs.Fatal("Failed to open access log: %v", err)
+			}
+			defer out.Close()
+		}
+		accessLog, err = middleware.AccessLog(out, cfg.Log.AccessLogFormat)
+		if err != nil {
+			logs.Fatal("Failed to create access log: %v", err)
+		}
+		logs.Info("Access log format: %s", cfg.Log.AccessLogFormat)
+	}
+
+	// Load scheduling policies
+	policies, err := policy.Load(cfg.Scheduling.PolicyFile)
+	if err != nil {
+		logs.Fatal("Failed to load scheduling policies: %v", err)
+	}
+
+	// Check proposed slots against working hours when they are configured
+	var workingHours *models.WorkingHours
+	if cfg.Scheduling.WorkingHoursStart != "" || cfg.Scheduling.WorkingHoursEnd != "" {
+		workingHours = &models.WorkingHours{
+			Start:           cfg.Scheduling.WorkingHoursStart,
+			End:             cfg.Scheduling.WorkingHoursEnd,
+			ExcludeWeekends: cfg.Scheduling.WorkingHoursWeekdaysOnly,
+		}
+	}
 
 	// Create router
-	r := router.New()
+	r := router.New(
+		router.WithStorage(storage),
+		router.WithEventPublisher(publisher),
+		router.WithNotifier(notifier),
+		router.WithNotificationTemplates(templates),
+		router.WithMeteringSink(meteringSink),
+		router.WithRSVPLinks(rsvpSigner, cfg.Server.PublicURL),
+		router.WithGuestLinks(guestSigner, cfg.Server.PublicURL),
+		router.WithPollURL(pollURL),
+		router.WithPolicies(policies),
+		router.WithFileStore(files, cfg.Files.LinkTTL),
+		router.WithAdminAPIKey(cfg.Admin.APIKey),
+		router.WithSCIM(cfg.Admin.SCIMToken, cfg.Server.PublicURL),
+		router.WithDirectorySync(userDirectory, directorySync, cfg.Directory.SyncInterval),
+		router.WithDigests(cfg.Notifications.DigestInterval),
+		router.WithEmailVerificationRequired(cfg.Accounts.RequireEmailVerification),
+		router.WithUserCache(cfg.Accounts.UserCacheTTL),
+		router.WithTextLimits(sanitize.Limits{
+			Title: cfg.Limits.MaxTitleLength,
+			Name:  cfg.Limits.MaxNameLength,
+		}),
+		router.WithMaterializedRecommendations(cfg.Scheduling.MaterializeRecommendations),
+		router.WithSlotGranularity(cfg.Scheduling.SlotGranularity),
+		router.WithAvailabilityDeduplication(cfg.Scheduling.AvailabilityDedupWindow),
+		router.WithWorkingHours(workingHours),
+		router.WithDuplicateDetection(cfg.Scheduling.DuplicateMeetingWindow),
+		router.WithQuotas(quota.Limits{
+			MeetingsPerMonth:       cfg.Quotas.MeetingsPerMonth,
+			ParticipantsPerMeeting: cfg.Quotas.ParticipantsPerMeeting,
+		}),
+		router.WithStateCheck(stateCheck),
+		router.WithStatusRateLimit(cfg.Server.StatusRateLimit),
+		router.WithMaxRequestTimeout(cfg.Server.MaxRequestTimeout),
+		router.WithFaultInjection(cfg.Deployment.FaultInjection),
+		router.WithReadOnly(cfg.Deployment.ReadOnly),
+		router.WithPrecomputeQueue(queue),
+		router.WithPrecomputeWorker(consumer),
+		router.WithAccessLog(accessLog),
+		router.WithDebugCapture(cfg.Log.DebugRoutes),
+		router.WithConfigSettings(settings),
+		router.WithDatabasePool(dbPool),
+	)
 	r.Setup()
 
+	// Declare the administrator and their access tokens; reapplying the same file on
+	// every start changes nothing
+	if cfg.Admin.BootstrapFile != "" {
+		bootstrap, err := services.LoadBootstrap(cfg.Admin.BootstrapFile)
+		if err != nil {
+			logs.Fatal("Failed to load bootstrap: %v", err)
+		}
+		if cfg.Deployment.ReadOnly {
+			logs.Warn("Skipping BOOTSTRAP_FILE on a read-only instance")
+		} else if _, err := r.Bootstrap(bootstrap); err != nil {
+			logs.Fatal("Failed to apply bootstrap: %v", err)
+		}
+	}
+
 	// Create server
+	socketMode, err := strconv.ParseUint(cfg.Server.SocketMode, 8, 32)
+	if err != nil {
+		logs.Fatal("Invalid SERVER_SOCKET_MODE %q: use octal permissions such as 0660", cfg.Server.SocketMode)
+	}
+	ln, err := listener.Listen(listener.Config{
+		Address:    cfg.Server.Listen,
+		Port:       cfg.Server.Port,
+		ReusePort:  cfg.Server.ReusePort,
+		SocketMode: os.FileMode(socketMode),
+	})
+	if err != nil {
+		logs.Fatal("Failed to listen: %v", err)
+	}
 	server := &http.Server{
-		Addr:         ":" + cfg.Server.Port,
-		Handler:      r,
-		ReadTimeout:  cfg.Server.ReadTimeout,
-		WriteTimeout: cfg.Server.WriteTimeout,
+		Handler:           r,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+		MaxHeaderBytes:    cfg.Server.MaxHeaderBytes,
 	}
+	server.SetKeepAlivesEnabled(cfg.Server.KeepAlives)
+	server.RegisterOnShutdown(r.CloseStreams)
 
 	// Start server in a goroutine
 	go func() {
-		logs.Info("Server listening on port %s", cfg.Server.Port)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		logs.Info("Server listening on %s %s", ln.Addr().Network(), ln.Addr())
+		if err := server.Serve(ln); err != nil && err != http.ErrServerClosed {
 			logs.Fatal("Failed to start server: %v", err)
 		}
 	}()
 
+	// Synchronize users from the directory in the background
+	syncCtx, stopSync := context.WithCancel(context.Background())
+	go r.RunDirectorySync(syncCtx)
+
+	// Send daily and weekly digests in the background
+	go r.RunDigests(syncCtx)
+
+	// Compute the recommendations API instances queued, when running as a worker
+	go r.RunPrecomputeWorker(syncCtx)
+
 	// Wait for interrupt signal to gracefully shut down the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	logs.Info("Shutting down server...")
+	stopSync()
 
-	// Create a deadline to wait for current operations to complete
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	// Stop accepting connections and wait for in-flight requests to complete
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
 		logs.Fatal("Server forced to shutdown: %v", err)
 	}
 
+	if err := r.Close(); err != nil {
+		logs.Error("Failed to finish background jobs: %v", err)
+	}
+
+	if storage.Close != nil {
+		if err := storage.Close(); err != nil {
+			logs.Error("Failed to close storage: %v", err)
+		}
+	}
+
+	if err := publisher.Close(); err != nil {
+		logs.Error("Failed to close event publisher: %v", err)
+	}
+
+	if precomputeQueue != nil {
+		if err := precomputeQueue.Close(); err != nil {
+			logs.Error("Failed to close precompute queue: %v", err)
+		}
+	}
+
+	if err := reporter.Close(); err != nil {
+		logs.Error("Failed to close error reporter: %v", err)
+	}
+
+	if err := meteringSink.Close(); err != nil {
+		logs.Error("Failed to close metering sink: %v", err)
+	}
+
 	logs.Info("Server exited gracefully")
 }
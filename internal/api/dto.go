@@ -1,21 +1,54 @@
 package api
 
 import (
+	"time"
+
+	"meetsync/internal/config"
+	"meetsync/internal/health"
+	"meetsync/internal/metering"
+	"meetsync/internal/metrics"
+	"meetsync/internal/middleware"
 	"meetsync/internal/models"
+	"meetsync/internal/quota"
+	"meetsync/pkg/errors"
 )
 
 // CreateMeetingRequest represents the request to create a meeting
 type CreateMeetingRequest struct {
-	Title             string            `json:"title"`
-	OrganizerID       string            `json:"organizerId"`
-	EstimatedDuration int               `json:"estimatedDuration"` // in minutes
-	ProposedSlots     []models.TimeSlot `json:"proposedSlots"`
-	ParticipantIDs    []string          `json:"participantIds,omitempty"`
+	Title                 string                 `json:"title"`
+	OrganizerID           string                 `json:"organizerId"`
+	EstimatedDuration     int                    `json:"estimatedDuration"`     // in minutes
+	MaxDuration           int                    `json:"maxDuration,omitempty"` // in minutes; makes estimatedDuration the minimum of a range
+	ProposedSlots         []models.TimeSlot      `json:"proposedSlots"`
+	ParticipantIDs        []string               `json:"participantIds,omitempty"`
+	Draft                 bool                   `json:"draft,omitempty"`                 // saves the meeting without validating or inviting participants
+	CoalesceAdjacentSlots bool                   `json:"coalesceAdjacentSlots,omitempty"` // merges proposed slots where one ends as the next starts
+	TimeZone              string                 `json:"timeZone,omitempty"`              // IANA zone of the organizer, e.g. Europe/Paris
+	OverrideFocusTime     bool                   `json:"overrideFocusTime,omitempty"`     // counts participants as available during their focus time
+	AvailabilityTTL       int                    `json:"availabilityTtl,omitempty"`       // hours before responses go stale
+	Tags                  []string               `json:"tags,omitempty"`
+	Priority              models.MeetingPriority `json:"priority,omitempty"`   // low, normal (default), high or urgent
+	ExternalID            string                 `json:"externalId,omitempty"` // identifier of the meeting in an integrator's system; unique
+	Metadata              models.Metadata        `json:"metadata,omitempty"`
+}
+
+// UpsertExternalMeetingRequest represents the request of a calendar sync to create or
+// update the meeting it knows under an external ID
+type UpsertExternalMeetingRequest struct {
+	CreateMeetingRequest
+	ExternalUpdatedAt time.Time `json:"externalUpdatedAt"` // when the calendar last changed the meeting
 }
 
 // CreateMeetingResponse represents the response after creating a meeting
 type CreateMeetingResponse struct {
-	Meeting models.Meeting `json:"meeting"`
+	Meeting  models.Meeting   `json:"meeting"`
+	Warnings []models.Warning `json:"warnings,omitempty"`
+}
+
+// ListMeetingsResponse represents a page of meetings
+type ListMeetingsResponse struct {
+	Meetings   []models.Meeting `json:"meetings"`
+	NextCursor string           `json:"nextCursor,omitempty"` // empty on the last page
 }
 
 // AddParticipantRequest represents the request to add a participant to a meeting
@@ -29,6 +62,7 @@ type AddAvailabilityRequest struct {
 	UserID         string            `json:"userId"`
 	MeetingID      string            `json:"meetingId"`
 	AvailableSlots []models.TimeSlot `json:"availableSlots"`
+	Tentative      bool              `json:"tentative,omitempty"` // marks the whole response as tentative
 }
 
 // GetRecommendationsRequest represents the request to get recommendations
@@ -38,7 +72,112 @@ type GetRecommendationsRequest struct {
 
 // GetRecommendationsResponse represents the response with recommendations
 type GetRecommendationsResponse struct {
-	RecommendedSlots []models.RecommendedSlot `json:"recommendedSlots"`
+	RecommendedSlots []models.RecommendedSlot     `json:"recommendedSlots"`
+	SplitSuggestions []models.SplitRecommendation `json:"splitSuggestions,omitempty"` // only with mode=split
+	ComputedAt       time.Time                    `json:"computedAt"`
+}
+
+// StreamError is the data of the error event ending a recommendation stream
+type StreamError struct {
+	Type    errors.ErrorType `json:"type"`
+	Message string           `json:"message"`
+}
+
+// BatchRecommendationsRequest represents the request to get recommendations for several meetings
+type BatchRecommendationsRequest struct {
+	MeetingIDs []string `json:"meetingIds"`
+}
+
+// MeetingRecommendations holds the recommendations of one meeting in a batch, or why they are missing
+type MeetingRecommendations struct {
+	MeetingID        string                   `json:"meetingId"`
+	RecommendedSlots []models.RecommendedSlot `json:"recommendedSlots,omitempty"`
+	ComputedAt       *time.Time               `json:"computedAt,omitempty"`
+	Error            string                   `json:"error,omitempty"`
+}
+
+// BatchRecommendationsResponse represents the response with recommendations for several meetings
+type BatchRecommendationsResponse struct {
+	Results []MeetingRecommendations `json:"results"`
+}
+
+// BatchOperation is one operation on a meeting in a batch request
+type BatchOperation struct {
+	Op        string   `json:"op"` // delete, publish, addTags or removeTag
+	MeetingID string   `json:"meetingId"`
+	Tags      []string `json:"tags,omitempty"` // for addTags, or the single tag of removeTag
+}
+
+// BatchRequest represents the request to apply several operations on meetings at once
+type BatchRequest struct {
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchOperationResult holds the outcome of one operation of a batch
+type BatchOperationResult struct {
+	Index     int    `json:"index"`
+	Op        string `json:"op"`
+	MeetingID string `json:"meetingId"`
+	Status    int    `json:"status"` // HTTP status the operation would have answered with on its own
+	ErrorType string `json:"errorType,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// BatchResponse represents the outcome of a batch, one result per operation in request order
+type BatchResponse struct {
+	Results   []BatchOperationResult `json:"results"`
+	Succeeded int                    `json:"succeeded"`
+	Failed    int                    `json:"failed"`
+}
+
+// JobResponse represents a background job, returned when a batch is accepted for
+// asynchronous processing and when the job is polled
+type JobResponse struct {
+	Job models.Job `json:"job"`
+}
+
+// CreateWebhookRequest represents the request to register a webhook
+type CreateWebhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events,omitempty"` // event types to deliver; every type when empty
+}
+
+// CreateWebhookResponse represents a registered webhook, including the secret signing its deliveries
+type CreateWebhookResponse struct {
+	Webhook models.Webhook `json:"webhook"`
+}
+
+// ListWebhooksResponse represents the registered webhooks
+type ListWebhooksResponse struct {
+	Webhooks []models.Webhook `json:"webhooks"`
+}
+
+// ListWebhookDeliveriesResponse represents the deliveries of a webhook, newest first
+type ListWebhookDeliveriesResponse struct {
+	Deliveries []models.WebhookDelivery `json:"deliveries"`
+}
+
+// ReplayWebhookRequest represents the request to replay deliveries of a webhook
+type ReplayWebhookRequest struct {
+	DeliveryIDs []string `json:"deliveryIds,omitempty"` // every failed delivery when empty
+}
+
+// ReplayWebhookResponse represents the deliveries queued again
+type ReplayWebhookResponse struct {
+	Deliveries []models.WebhookDelivery `json:"deliveries"`
+}
+
+// SimulateRecommendationsRequest represents the request to simulate recommendations under hypothetical changes
+type SimulateRecommendationsRequest struct {
+	MeetingID            string   `json:"meetingId"`
+	RemoveParticipantIDs []string `json:"removeParticipantIds,omitempty"`
+	ExtendWindow         int      `json:"extendWindow,omitempty"` // in minutes
+	Duration             int      `json:"duration,omitempty"`     // in minutes
+}
+
+// SimulateRecommendationsResponse represents the simulated recommendations and their difference from the current ones
+type SimulateRecommendationsResponse struct {
+	Simulation models.RecommendationSimulation `json:"simulation"`
 }
 
 // CreateUserRequest represents the request to create a new user
@@ -68,22 +207,164 @@ type UpdateUserRequest struct {
 	Email string `json:"email,omitempty"`
 }
 
+// ChangeEmailRequest represents the request to change a user's email address
+type ChangeEmailRequest struct {
+	Email string `json:"email"`
+}
+
+// ChangeEmailResponse represents the response after requesting an email change
+type ChangeEmailResponse struct {
+	PendingEmail string    `json:"pendingEmail"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+}
+
+// ConfirmEmailChangeRequest represents the request to confirm an email change
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token"`
+}
+
+// VerifyEmailRequest represents the request to verify a user's email address
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+// CreateAccessTokenRequest represents the request to mint a personal access token
+type CreateAccessTokenRequest struct {
+	Name   string         `json:"name"`
+	Scopes []models.Scope `json:"scopes"`
+}
+
+// CreateAccessTokenResponse represents the response after minting a personal access token
+type CreateAccessTokenResponse struct {
+	Token  models.PersonalAccessToken `json:"token"`
+	Secret string                     `json:"secret"` // shown only once
+}
+
+// SetFocusBlocksRequest represents the request to replace a user's focus blocks
+type SetFocusBlocksRequest struct {
+	FocusBlocks []models.FocusBlock `json:"focusBlocks"`
+}
+
+// SetDigestRequest represents the request to change how often a user receives a digest
+type SetDigestRequest struct {
+	Frequency string `json:"frequency"` // daily, weekly, or off for an email per notification
+}
+
+// SetDoNotDisturbRequest represents the request to set a user's quiet hours and snooze their notifications
+type SetDoNotDisturbRequest struct {
+	QuietHours   *models.QuietHours `json:"quietHours,omitempty"`   // cleared when absent
+	SnoozedUntil *time.Time         `json:"snoozedUntil,omitempty"` // cleared when absent
+}
+
+// SetUserMetadataRequest represents the request to replace a user's metadata
+type SetUserMetadataRequest struct {
+	Metadata models.Metadata `json:"metadata"` // cleared when absent or empty
+}
+
+// ListAccessTokensResponse represents the response when listing personal access tokens
+type ListAccessTokensResponse struct {
+	Tokens []models.PersonalAccessToken `json:"tokens"`
+}
+
+// ListSessionsResponse represents the response when listing where a user is signed in
+type ListSessionsResponse struct {
+	Sessions []models.Session `json:"sessions"`
+}
+
+// RevokeAccessTokensResponse represents the response after revoking all of a user's personal access tokens
+type RevokeAccessTokensResponse struct {
+	Revoked int `json:"revoked"`
+}
+
 // UpdateMeetingRequest represents the request to update a meeting
 type UpdateMeetingRequest struct {
-	Title             string            `json:"title,omitempty"`
-	EstimatedDuration int               `json:"estimatedDuration,omitempty"`
-	ProposedSlots     []models.TimeSlot `json:"proposedSlots,omitempty"`
-	ParticipantIDs    []string          `json:"participantIds,omitempty"`
+	Title                 string                 `json:"title,omitempty"`
+	EstimatedDuration     int                    `json:"estimatedDuration,omitempty"`
+	MaxDuration           int                    `json:"maxDuration,omitempty"`
+	ProposedSlots         []models.TimeSlot      `json:"proposedSlots,omitempty"`
+	ParticipantIDs        []string               `json:"participantIds,omitempty"`
+	CoalesceAdjacentSlots bool                   `json:"coalesceAdjacentSlots,omitempty"` // merges proposed slots where one ends as the next starts
+	TimeZone              string                 `json:"timeZone,omitempty"`              // IANA zone of the organizer, e.g. Europe/Paris
+	OverrideFocusTime     *bool                  `json:"overrideFocusTime,omitempty"`     // counts participants as available during their focus time
+	AvailabilityTTL       int                    `json:"availabilityTtl,omitempty"`       // hours before responses go stale
+	Tags                  []string               `json:"tags,omitempty"`                  // replaces the tags when present; an empty list clears them
+	Priority              models.MeetingPriority `json:"priority,omitempty"`              // low, normal, high or urgent
+	Metadata              models.Metadata        `json:"metadata,omitempty"`              // replaces the metadata when present; an empty object clears it
 }
 
 // UpdateMeetingResponse represents the response after updating a meeting
 type UpdateMeetingResponse struct {
+	Meeting  models.Meeting   `json:"meeting"`
+	Warnings []models.Warning `json:"warnings,omitempty"`
+}
+
+// SearchMeetingsResponse represents the meetings matching a search query, most relevant first
+type SearchMeetingsResponse struct {
+	Results []models.SearchResult `json:"results"`
+}
+
+// AddMeetingTagsRequest represents the request to add tags to a meeting
+type AddMeetingTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+// UpdateMeetingTagsResponse represents the response after adding or removing meeting tags
+type UpdateMeetingTagsResponse struct {
+	Meeting models.Meeting `json:"meeting"`
+}
+
+// GetTagStatsResponse represents the response when getting meeting statistics by tag
+type GetTagStatsResponse struct {
+	Tags []models.TagStats `json:"tags"`
+}
+
+// PublishMeetingResponse represents the response after publishing a draft meeting
+type PublishMeetingResponse struct {
+	Meeting models.Meeting `json:"meeting"`
+}
+
+// ConfirmMeetingRequest represents the request to confirm a meeting on a recommended slot
+type ConfirmMeetingRequest struct {
+	Slot models.TimeSlot `json:"slot"`
+}
+
+// ConfirmMeetingResponse represents the response after confirming a meeting
+type ConfirmMeetingResponse struct {
 	Meeting models.Meeting `json:"meeting"`
 }
 
+// CancelMeetingResponse represents the response after cancelling a meeting
+type CancelMeetingResponse struct {
+	Meeting models.Meeting `json:"meeting"`
+}
+
+// ProposeAlternativeRequest represents a participant's request to move a confirmed meeting
+type ProposeAlternativeRequest struct {
+	ParticipantID string          `json:"participantId"`
+	Slot          models.TimeSlot `json:"slot"`
+	Reason        string          `json:"reason,omitempty"`
+}
+
+// ProposeAlternativeResponse represents the response after proposing an alternative slot
+type ProposeAlternativeResponse struct {
+	Proposal models.Proposal `json:"proposal"`
+}
+
+// DecideProposalRequest represents the organizer's decision on a proposal: accept or decline
+type DecideProposalRequest struct {
+	Decision string `json:"decision"`
+}
+
+// DecideProposalResponse represents the response after deciding on a proposal
+type DecideProposalResponse struct {
+	Meeting  models.Meeting  `json:"meeting"`
+	Proposal models.Proposal `json:"proposal"`
+}
+
 // UpdateAvailabilityRequest represents the request to update availability
 type UpdateAvailabilityRequest struct {
 	AvailableSlots []models.TimeSlot `json:"availableSlots"`
+	Tentative      bool              `json:"tentative,omitempty"` // replaces the status like the slots; omitted means confirmed
 }
 
 // UpdateAvailabilityResponse represents the response after updating availability
@@ -91,7 +372,235 @@ type UpdateAvailabilityResponse struct {
 	Availability models.Availability `json:"availability"`
 }
 
+// RespondToInvitationResponse represents the availability recorded from an RSVP link
+type RespondToInvitationResponse struct {
+	Availability models.Availability `json:"availability"`
+}
+
+// ImportAvailabilityResponse represents the availability derived from imported busy times
+type ImportAvailabilityResponse struct {
+	Availability models.Availability `json:"availability"`
+	BusyTimes    int                 `json:"busyTimes"` // number of busy times read from the import
+}
+
+// ImportCalendarAvailabilityRequest represents the request to submit availability from
+// the busy times of a calendar the participant signed in to
+type ImportCalendarAvailabilityRequest struct {
+	UserID      string `json:"userId"`
+	AccessToken string `json:"accessToken"` // OAuth access token of the participant, used once and never stored
+	Tentative   bool   `json:"tentative,omitempty"`
+}
+
+// AddGuestRequest represents the request to invite a guest from outside the organization to a meeting
+type AddGuestRequest struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"` // defaults to the part of the email before the @
+}
+
+// AddGuestResponse represents the guest added to a meeting and their access link
+type AddGuestResponse struct {
+	Invitation models.GuestInvitation `json:"invitation"`
+}
+
+// GetGuestMeetingResponse represents what a guest sees of the meeting they were invited to
+type GetGuestMeetingResponse struct {
+	Meeting models.GuestMeeting `json:"meeting"`
+}
+
+// SubmitGuestAvailabilityRequest represents the availability a guest submits for their meeting
+type SubmitGuestAvailabilityRequest struct {
+	AvailableSlots []models.TimeSlot `json:"availableSlots"`
+	Tentative      bool              `json:"tentative,omitempty"`
+}
+
+// SubmitGuestAvailabilityResponse represents the availability recorded for a guest
+type SubmitGuestAvailabilityResponse struct {
+	Availability models.Availability `json:"availability"`
+}
+
+// ConfirmAvailabilityResponse represents the response after reconfirming availability
+type ConfirmAvailabilityResponse struct {
+	Availability models.Availability `json:"availability"`
+}
+
+// RequestReconfirmationResponse represents the response after asking participants to reconfirm stale availability
+type RequestReconfirmationResponse struct {
+	Stale []models.Availability `json:"stale"`
+}
+
+// SetAvailabilityMetadataRequest represents the request to replace the external ID and
+// metadata of an availability
+type SetAvailabilityMetadataRequest struct {
+	ExternalID string          `json:"externalId,omitempty"` // cleared when absent
+	Metadata   models.Metadata `json:"metadata,omitempty"`   // cleared when absent or empty
+}
+
+// ListAvailabilitiesResponse represents the availabilities submitted for a meeting
+type ListAvailabilitiesResponse struct {
+	Availabilities []models.Availability `json:"availabilities"`
+}
+
 // GetAvailabilityResponse represents the response when getting availability
 type GetAvailabilityResponse struct {
 	Availability models.Availability `json:"availability"`
 }
+
+// GetSLOStatsResponse represents the response when getting scheduling SLO statistics
+type GetSLOStatsResponse struct {
+	SLO metrics.SLOReport `json:"slo"`
+}
+
+// GetMeetingTimelineResponse represents the response when getting a meeting's activity timeline
+type GetMeetingTimelineResponse struct {
+	Events []models.TimelineEvent `json:"events"`
+}
+
+// BackupSummaryResponse represents the response after verifying or restoring a backup
+type BackupSummaryResponse struct {
+	Summary models.BackupSummary `json:"summary"`
+}
+
+// MergeUsersRequest represents the request to merge a duplicate user into another user
+type MergeUsersRequest struct {
+	SourceUserID string `json:"sourceUserId"`
+	TargetUserID string `json:"targetUserId"`
+}
+
+// MergeUsersResponse represents the response after merging users
+type MergeUsersResponse struct {
+	Result models.UserReassignment `json:"result"`
+}
+
+// ListAuditLogResponse represents the response when listing the audit log
+type ListAuditLogResponse struct {
+	Entries    []models.AuditEntry `json:"entries"`
+	NextCursor string              `json:"nextCursor,omitempty"` // empty on the last page
+}
+
+// SchedulingQueryRequest represents the request to find times that suit a group of users
+type SchedulingQueryRequest struct {
+	ParticipantIDs []string             `json:"participantIds"`
+	Duration       int                  `json:"duration"` // in minutes
+	Window         models.TimeSlot      `json:"window"`
+	TimeZone       string               `json:"timeZone,omitempty"`
+	WorkingHours   *models.WorkingHours `json:"workingHours,omitempty"`
+}
+
+// SchedulingQueryResponse represents the ranked candidate slots of a scheduling query
+type SchedulingQueryResponse struct {
+	Candidates []models.RecommendedSlot `json:"candidates"`
+}
+
+// FreeBusyResponse represents when a user is busy within a window
+type FreeBusyResponse struct {
+	UserID string           `json:"userId"`
+	From   time.Time        `json:"from"`
+	To     time.Time        `json:"to"`
+	Busy   []FreeBusyPeriod `json:"busy"`
+}
+
+// FreeBusyPeriod represents a period a user is busy
+type FreeBusyPeriod struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// HealthResponse represents the response of the liveness probe
+type HealthResponse struct {
+	Status  string               `json:"status"` // "ok", or "degraded" while storage is unreachable
+	Storage health.StorageStatus `json:"storage"`
+}
+
+// ReadinessResponse represents the response of the readiness probe
+type ReadinessResponse struct {
+	Status  string               `json:"status"` // "ready", or "degraded" when a check failed
+	State   health.StateCheck    `json:"state"`
+	Storage health.StorageStatus `json:"storage"`
+}
+
+// StatusResponse represents the public status of the service, for status pages and
+// external monitoring. It leaves out anything that would help an attacker, such as
+// error messages and the deployment layout.
+type StatusResponse struct {
+	Status        string             `json:"status"` // "operational", or "degraded" when a check failed
+	Version       string             `json:"version"`
+	Commit        string             `json:"commit,omitempty"`
+	StartedAt     time.Time          `json:"startedAt"`
+	UptimeSeconds int64              `json:"uptimeSeconds"`
+	Storage       string             `json:"storage"` // storage backend
+	Dependencies  []DependencyStatus `json:"dependencies"`
+}
+
+// DependencyStatus represents the health of a dependency of the service
+type DependencyStatus struct {
+	Name         string  `json:"name"`
+	Status       string  `json:"status"`       // "up" or "down"
+	Availability float64 `json:"availability"` // percentage of the last 24 hours the dependency was up, since the service started
+}
+
+// VersionResponse represents the build of the service that answered
+type VersionResponse struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildDate string `json:"buildDate,omitempty"`
+}
+
+// SetFaultsRequest represents the request to replace the fault injection rules
+type SetFaultsRequest struct {
+	Rules []middleware.FaultRule `json:"rules"` // an empty list stops injecting faults
+}
+
+// FaultsResponse represents the fault injection rules in effect
+type FaultsResponse struct {
+	Enabled bool                   `json:"enabled"` // whether the configuration allows fault injection
+	Rules   []middleware.FaultRule `json:"rules"`
+}
+
+// ImportUsersResponse represents the outcome of a bulk user import, row by row
+type ImportUsersResponse struct {
+	Result models.UserImportResult `json:"result"`
+}
+
+// ExportRecommendationsRequest represents the request to store a spreadsheet of the
+// recommended slots of a meeting
+type ExportRecommendationsRequest struct {
+	MeetingID string `json:"meetingId"`
+	Format    string `json:"format"` // csv or xlsx
+}
+
+// DownloadLinkResponse represents a signed link to download a stored file, such as an
+// export or a backup, directly from file storage
+type DownloadLinkResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// BootstrapResponse represents the outcome of applying a bootstrap
+type BootstrapResponse struct {
+	Result models.BootstrapResult `json:"result"`
+}
+
+// SyncDirectoryResponse represents the changes made, or planned by a dry run, by a directory sync
+type SyncDirectoryResponse struct {
+	Result models.DirectorySyncResult `json:"result"`
+}
+
+// SendDigestsResponse represents the outcome of sending the digests that were due
+type SendDigestsResponse struct {
+	Result models.DigestResult `json:"result"`
+}
+
+// GetConfigResponse represents the effective configuration, with secrets masked
+type GetConfigResponse struct {
+	Settings []config.Setting `json:"settings"`
+}
+
+// GetUsageResponse represents the organization's usage of the current month against its quotas
+type GetUsageResponse struct {
+	Usage quota.Usage `json:"usage"`
+}
+
+// GetUsageSummaryResponse represents the metered usage of a month
+type GetUsageSummaryResponse struct {
+	Summary metering.Summary `json:"summary"`
+}
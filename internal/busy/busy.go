@@ -0,0 +1,411 @@
+// Package busy reads the busy times of users whose calendars cannot be linked, from an
+// ICS export of their calendar or a CSV spreadsheet with start and end columns
+package busy
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+)
+
+// Format is the format of an export of busy times
+type Format string
+
+const (
+	// FormatICS is an iCalendar file of events or free/busy periods
+	FormatICS Format = "ics"
+	// FormatCSV is a spreadsheet with a header row naming its start and end columns
+	FormatCSV Format = "csv"
+)
+
+// MaxBusyTimes caps the number of busy times read from one export
+const MaxBusyTimes = 10000
+
+// csvLayouts are the layouts accepted for times in CSV exports, besides RFC 3339
+var csvLayouts = []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02T15:04:05", "2006-01-02T15:04"}
+
+// DetectFormat returns the format named by format or, when it is empty, the format
+// announced by contentType or recognized from the content itself
+func DetectFormat(format, contentType string, data []byte) (Format, error) {
+	switch strings.ToLower(format) {
+	case string(FormatICS), "ical", "icalendar":
+		return FormatICS, nil
+	case string(FormatCSV):
+		return FormatCSV, nil
+	case "":
+	default:
+		return "", errors.NewValidationError("Invalid format", "use ics or csv")
+	}
+
+	switch mediaType, _, _ := strings.Cut(contentType, ";"); strings.TrimSpace(strings.ToLower(mediaType)) {
+	case "text/calendar":
+		return FormatICS, nil
+	case "text/csv":
+		return FormatCSV, nil
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\ufeff"))), []byte("BEGIN:VCALENDAR")) {
+		return FormatICS, nil
+	}
+	return FormatCSV, nil
+}
+
+// Parse reads the busy times of an export in format, sorted by start time. Times
+// without a time zone are read in location.
+func Parse(data []byte, format Format, location *time.Location) ([]models.TimeSlot, error) {
+	var (
+		slots []models.TimeSlot
+		err   error
+	)
+	switch format {
+	case FormatICS:
+		slots, err = ParseICS(bytes.NewReader(data), location)
+	case FormatCSV:
+		slots, err = ParseCSV(bytes.NewReader(data), location)
+	default:
+		return nil, errors.NewValidationError("Invalid format", "use ics or csv")
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i].StartTime.Before(slots[j].StartTime) })
+	return slots, nil
+}
+
+// ParseICS reads the busy times of an iCalendar file: its events, except cancelled and
+// transparent ones, and the busy periods of its free/busy components. Recurring events
+// count as their first occurrence only; free/busy exports list every occurrence.
+func ParseICS(r io.Reader, location *time.Location) ([]models.TimeSlot, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		slots      []models.TimeSlot
+		event      map[string]icsProperty
+		inEvent    bool
+		inFreeBusy bool
+	)
+	add := func(slot models.TimeSlot) error {
+		if len(slots) == MaxBusyTimes {
+			return errors.NewValidationError("Too many busy times", fmt.Sprintf("import at most %d busy times at once", MaxBusyTimes))
+		}
+		slots = append(slots, slot)
+		return nil
+	}
+
+	for number, line := range lines {
+		property, ok := parseProperty(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case property.name == "BEGIN" && strings.EqualFold(property.value, "VEVENT"):
+			inEvent, event = true, map[string]icsProperty{}
+		case property.name == "END" && strings.EqualFold(property.value, "VEVENT"):
+			inEvent = false
+			slot, busy, err := eventSlot(event, location)
+			if err != nil {
+				return nil, errors.NewValidationError("Invalid event", fmt.Sprintf("event ending on line %d: %s", number+1, err))
+			}
+			if busy {
+				if err := add(slot); err != nil {
+					return nil, err
+				}
+			}
+		case property.name == "BEGIN" && strings.EqualFold(property.value, "VFREEBUSY"):
+			inFreeBusy = true
+		case property.name == "END" && strings.EqualFold(property.value, "VFREEBUSY"):
+			inFreeBusy = false
+		case inEvent:
+			event[property.name] = property
+		case inFreeBusy && property.name == "FREEBUSY":
+			if strings.EqualFold(property.params["FBTYPE"], "FREE") {
+				continue
+			}
+			for _, period := range strings.Split(property.value, ",") {
+				slot, err := parsePeriod(period, location)
+				if err != nil {
+					return nil, errors.NewValidationError("Invalid free/busy period", fmt.Sprintf("line %d: %s", number+1, err))
+				}
+				if err := add(slot); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return slots, nil
+}
+
+// ParseCSV reads the busy times of a CSV spreadsheet whose header row names its start
+// and end columns. Times are RFC 3339 or, read in location, YYYY-MM-DD HH:MM.
+func ParseCSV(r io.Reader, location *time.Location) ([]models.TimeSlot, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.NewValidationError("CSV is empty", "the first row must name the start and end columns")
+	}
+	if err != nil {
+		return nil, errors.NewValidationError("Invalid CSV", err.Error())
+	}
+	startColumn, endColumn := -1, -1
+	for i, column := range header {
+		switch strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))) {
+		case "start", "starttime", "start time":
+			startColumn = i
+		case "end", "endtime", "end time":
+			endColumn = i
+		}
+	}
+	if startColumn < 0 || endColumn < 0 {
+		return nil, errors.NewValidationError("CSV header must have start and end columns", "")
+	}
+
+	var slots []models.TimeSlot
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.NewValidationError("Invalid CSV", err.Error())
+		}
+		line, _ := reader.FieldPos(0)
+		if startColumn >= len(record) || endColumn >= len(record) {
+			return nil, errors.NewValidationError("Invalid busy time", fmt.Sprintf("line %d is missing the start or end column", line))
+		}
+		if len(slots) == MaxBusyTimes {
+			return nil, errors.NewValidationError("Too many busy times", fmt.Sprintf("import at most %d busy times at once", MaxBusyTimes))
+		}
+		start, err := parseCSVTime(record[startColumn], location)
+		if err != nil {
+			return nil, errors.NewValidationError("Invalid busy time", fmt.Sprintf("line %d: invalid start %q", line, record[startColumn]))
+		}
+		end, err := parseCSVTime(record[endColumn], location)
+		if err != nil {
+			return nil, errors.NewValidationError("Invalid busy time", fmt.Sprintf("line %d: invalid end %q", line, record[endColumn]))
+		}
+		if !end.After(start) {
+			return nil, errors.NewValidationError("Invalid busy time", fmt.Sprintf("line %d ends before it starts", line))
+		}
+		slots = append(slots, models.TimeSlot{StartTime: start, EndTime: end})
+	}
+	return slots, nil
+}
+
+// parseCSVTime reads a time of a CSV export
+func parseCSVTime(value string, location *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range csvLayouts {
+		if t, err := time.ParseInLocation(layout, value, location); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unsupported time %q", value)
+}
+
+// icsProperty is a content line of an iCalendar file
+type icsProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// unfold reads the content lines of an iCalendar file, joining lines folded onto the
+// following ones
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.NewValidationError("Invalid ICS", err.Error())
+	}
+	if len(lines) > 0 {
+		lines[0] = strings.TrimPrefix(lines[0], "\ufeff")
+	}
+	return lines, nil
+}
+
+// parseProperty splits a content line into its name, parameters and value
+func parseProperty(line string) (icsProperty, bool) {
+	head, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return icsProperty{}, false
+	}
+	parts := strings.Split(head, ";")
+	property := icsProperty{
+		name:   strings.ToUpper(parts[0]),
+		params: make(map[string]string, len(parts)-1),
+		value:  value,
+	}
+	for _, param := range parts[1:] {
+		if key, value, ok := strings.Cut(param, "="); ok {
+			property.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return property, true
+}
+
+// eventSlot returns the time taken by an event, and whether it makes its attendee busy
+func eventSlot(event map[string]icsProperty, location *time.Location) (models.TimeSlot, bool, error) {
+	if strings.EqualFold(event["STATUS"].value, "CANCELLED") || strings.EqualFold(event["TRANSP"].value, "TRANSPARENT") {
+		return models.TimeSlot{}, false, nil
+	}
+	dtstart, ok := event["DTSTART"]
+	if !ok {
+		return models.TimeSlot{}, false, fmt.Errorf("missing DTSTART")
+	}
+	start, allDay, err := parseICSTime(dtstart, location)
+	if err != nil {
+		return models.TimeSlot{}, false, err
+	}
+
+	var end time.Time
+	if dtend, ok := event["DTEND"]; ok {
+		if end, _, err = parseICSTime(dtend, location); err != nil {
+			return models.TimeSlot{}, false, err
+		}
+	} else if duration, ok := event["DURATION"]; ok {
+		length, err := parseDuration(duration.value)
+		if err != nil {
+			return models.TimeSlot{}, false, err
+		}
+		end = start.Add(length)
+	} else if allDay {
+		end = start.AddDate(0, 0, 1)
+	} else {
+		end = start
+	}
+	if !end.After(start) {
+		// Events without duration, such as reminders, take no time
+		return models.TimeSlot{}, false, nil
+	}
+	return models.TimeSlot{StartTime: start, EndTime: end}, true, nil
+}
+
+// parseICSTime reads a DATE or DATE-TIME property, and reports whether it is a date.
+// Times in an unknown TZID are read in location, like floating times.
+func parseICSTime(property icsProperty, location *time.Location) (time.Time, bool, error) {
+	value := strings.TrimSpace(property.value)
+	if strings.EqualFold(property.params["VALUE"], "DATE") || len(value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", value, location)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date %q", value)
+		}
+		return t, true, nil
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid time %q", value)
+		}
+		return t, false, nil
+	}
+	if tzid := property.params["TZID"]; tzid != "" {
+		if zone, err := time.LoadLocation(tzid); err == nil {
+			location = zone
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, location)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid time %q", value)
+	}
+	return t, false, nil
+}
+
+// parsePeriod reads a FREEBUSY period, given by its start and either its end or its
+// duration
+func parsePeriod(period string, location *time.Location) (models.TimeSlot, error) {
+	rawStart, rawEnd, ok := strings.Cut(strings.TrimSpace(period), "/")
+	if !ok {
+		return models.TimeSlot{}, fmt.Errorf("invalid period %q", period)
+	}
+	start, _, err := parseICSTime(icsProperty{value: rawStart}, location)
+	if err != nil {
+		return models.TimeSlot{}, err
+	}
+	var end time.Time
+	if strings.HasPrefix(rawEnd, "P") || strings.HasPrefix(rawEnd, "+P") {
+		length, err := parseDuration(rawEnd)
+		if err != nil {
+			return models.TimeSlot{}, err
+		}
+		end = start.Add(length)
+	} else if end, _, err = parseICSTime(icsProperty{value: rawEnd}, location); err != nil {
+		return models.TimeSlot{}, err
+	}
+	if !end.After(start) {
+		return models.TimeSlot{}, fmt.Errorf("period %q ends before it starts", period)
+	}
+	return models.TimeSlot{StartTime: start, EndTime: end}, nil
+}
+
+// parseDuration reads an iCalendar duration such as PT1H30M, P1D or P2W
+func parseDuration(value string) (time.Duration, error) {
+	raw := strings.TrimPrefix(strings.TrimSpace(value), "+")
+	if !strings.HasPrefix(raw, "P") || len(raw) < 3 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	var (
+		total  time.Duration
+		number string
+		inTime bool
+	)
+	for _, c := range raw[1:] {
+		switch {
+		case c >= '0' && c <= '9':
+			number += string(c)
+			continue
+		case c == 'T' && number == "" && !inTime:
+			inTime = true
+			continue
+		}
+		n, err := strconv.Atoi(number)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		number = ""
+		switch {
+		case c == 'W' && !inTime:
+			total += time.Duration(n) * 7 * 24 * time.Hour
+		case c == 'D' && !inTime:
+			total += time.Duration(n) * 24 * time.Hour
+		case c == 'H' && inTime:
+			total += time.Duration(n) * time.Hour
+		case c == 'M' && inTime:
+			total += time.Duration(n) * time.Minute
+		case c == 'S' && inTime:
+			total += time.Duration(n) * time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+	}
+	if number != "" {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return total, nil
+}
@@ -0,0 +1,118 @@
+package busy
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+)
+
+func TestParseICS(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"SUMMARY:Standup",
+		"DTSTART:20300107T090000Z",
+		"DTEND:20300107T091500Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"SUMMARY:Lunch with a very long description that the exporter folded onto the",
+		"  next line",
+		"DTSTART;TZID=Europe/Paris:20300107T120000",
+		"DURATION:PT1H30M",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"SUMMARY:Cancelled",
+		"STATUS:CANCELLED",
+		"DTSTART:20300107T150000Z",
+		"DTEND:20300107T160000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"SUMMARY:Holiday",
+		"DTSTART;VALUE=DATE:20300109",
+		"END:VEVENT",
+		"BEGIN:VFREEBUSY",
+		"FREEBUSY;FBTYPE=BUSY:20300108T080000Z/20300108T090000Z,20300108T100000Z/PT30M",
+		"FREEBUSY;FBTYPE=FREE:20300108T110000Z/20300108T120000Z",
+		"END:VFREEBUSY",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	format, err := DetectFormat("", "", []byte(ics))
+	require.NoError(t, err)
+	assert.Equal(t, FormatICS, format)
+
+	slots, err := Parse([]byte(ics), format, time.UTC)
+	require.NoError(t, err)
+	expected := []models.TimeSlot{
+		{StartTime: time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC), EndTime: time.Date(2030, 1, 7, 9, 15, 0, 0, time.UTC)},
+		{StartTime: time.Date(2030, 1, 7, 12, 0, 0, 0, paris), EndTime: time.Date(2030, 1, 7, 13, 30, 0, 0, paris)},
+		{StartTime: time.Date(2030, 1, 8, 8, 0, 0, 0, time.UTC), EndTime: time.Date(2030, 1, 8, 9, 0, 0, 0, time.UTC)},
+		{StartTime: time.Date(2030, 1, 8, 10, 0, 0, 0, time.UTC), EndTime: time.Date(2030, 1, 8, 10, 30, 0, 0, time.UTC)},
+		{StartTime: time.Date(2030, 1, 9, 0, 0, 0, 0, time.UTC), EndTime: time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC)},
+	}
+	require.Len(t, slots, len(expected))
+	for i := range expected {
+		assert.True(t, expected[i].StartTime.Equal(slots[i].StartTime), "start of busy time %d", i)
+		assert.True(t, expected[i].EndTime.Equal(slots[i].EndTime), "end of busy time %d", i)
+	}
+
+	_, err = Parse([]byte("BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:tomorrow\nEND:VEVENT\nEND:VCALENDAR"), FormatICS, time.UTC)
+	assert.True(t, errors.Is(err, errors.ErrValidation))
+}
+
+func TestParseCSV(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	csv := "Subject,Start,End\nStandup,2030-01-07T09:00:00Z,2030-01-07T09:15:00Z\nLunch,2030-01-07 12:00,2030-01-07 13:30\n"
+	format, err := DetectFormat("", "text/csv; charset=utf-8", []byte(csv))
+	require.NoError(t, err)
+	assert.Equal(t, FormatCSV, format)
+
+	slots, err := Parse([]byte(csv), format, paris)
+	require.NoError(t, err)
+	require.Len(t, slots, 2)
+	assert.True(t, time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC).Equal(slots[0].StartTime))
+	assert.True(t, time.Date(2030, 1, 7, 13, 30, 0, 0, paris).Equal(slots[1].EndTime))
+
+	for _, invalid := range []string{
+		"",
+		"from,to\n2030-01-07 09:00,2030-01-07 10:00\n",
+		"start,end\n2030-01-07 10:00,2030-01-07 09:00\n",
+		"start,end\nmonday,tuesday\n",
+	} {
+		_, err := Parse([]byte(invalid), FormatCSV, time.UTC)
+		assert.True(t, errors.Is(err, errors.ErrValidation), invalid)
+	}
+
+	_, err = DetectFormat("xlsx", "", nil)
+	assert.True(t, errors.Is(err, errors.ErrValidation))
+}
+
+func TestParseDuration(t *testing.T) {
+	for value, expected := range map[string]time.Duration{
+		"PT1H30M": 90 * time.Minute,
+		"P1D":     24 * time.Hour,
+		"P1W":     7 * 24 * time.Hour,
+		"P1DT2H":  26 * time.Hour,
+		"+PT45S":  45 * time.Second,
+	} {
+		duration, err := parseDuration(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, duration, value)
+	}
+	for _, value := range []string{"", "P", "PT", "1H", "PT1D", "P1H", "PT1H30"} {
+		_, err := parseDuration(value)
+		assert.Error(t, err, value)
+	}
+}
@@ -0,0 +1,194 @@
+package config
+
+import (
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SourceEnv marks settings read from an environment variable
+	SourceEnv = "env"
+	// SourceDefault marks settings using their default value
+	SourceDefault = "default"
+	// SourceInvalidEnv marks settings whose environment variable could not be parsed,
+	// so the default value is used
+	SourceInvalidEnv = "default (invalid env value ignored)"
+)
+
+// masked replaces secret values in settings
+const masked = "********"
+
+// Setting is a resolved configuration value and where it came from. Secrets are masked.
+type Setting struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// Settings returns the resolved configuration, one entry per environment variable, with
+// secrets masked so it can be logged or shown to operators
+func (c *Config) Settings() []Setting {
+	return []Setting{
+		stringSetting("SERVER_PORT", c.Server.Port),
+		stringSetting("SERVER_LISTEN", c.Server.Listen),
+		stringSetting("SERVER_SOCKET_MODE", c.Server.SocketMode),
+		boolSetting("SERVER_REUSE_PORT", c.Server.ReusePort),
+		durationSetting("SERVER_SHUTDOWN_TIMEOUT", c.Server.ShutdownTimeout),
+		durationSetting("SERVER_READ_TIMEOUT", c.Server.ReadTimeout),
+		durationSetting("SERVER_READ_HEADER_TIMEOUT", c.Server.ReadHeaderTimeout),
+		durationSetting("SERVER_WRITE_TIMEOUT", c.Server.WriteTimeout),
+		durationSetting("SERVER_IDLE_TIMEOUT", c.Server.IdleTimeout),
+		durationSetting("SERVER_MAX_REQUEST_TIMEOUT", c.Server.MaxRequestTimeout),
+		intSetting("SERVER_MAX_HEADER_BYTES", c.Server.MaxHeaderBytes),
+		boolSetting("SERVER_KEEP_ALIVES", c.Server.KeepAlives),
+		stringSetting("PUBLIC_URL", c.Server.PublicURL),
+		stringSetting("POLL_URL", c.Server.PollURL),
+		intSetting("STATUS_RATE_LIMIT", c.Server.StatusRateLimit),
+		stringSetting("STORAGE_DRIVER", c.Storage.Driver),
+		urlSetting("STORAGE_DSN", c.Storage.DSN),
+		urlSetting("DB_DSN", c.DB.DSN),
+		intSetting("DB_MAX_OPEN_CONNS", c.DB.MaxOpenConns),
+		intSetting("DB_MAX_IDLE_CONNS", c.DB.MaxIdleConns),
+		durationSetting("DB_CONN_MAX_LIFETIME", c.DB.ConnMaxLifetime),
+		durationSetting("DB_CONN_MAX_IDLE_TIME", c.DB.ConnMaxIdleTime),
+		durationSetting("DB_MIGRATION_TIMEOUT", c.DB.MigrationTimeout),
+		stringSetting("LOG_LEVEL", c.Log.Level),
+		stringSetting("LOG_PII_REDACTION", c.Log.PIIRedaction),
+		stringSetting("ACCESS_LOG_FORMAT", c.Log.AccessLogFormat),
+		stringSetting("ACCESS_LOG_FILE", c.Log.AccessLogFile),
+		stringSetting("DEBUG_CAPTURE_ROUTES", strings.Join(c.Log.DebugRoutes, ",")),
+		stringSetting("EVENTS_BACKEND", c.Events.Backend),
+		urlSetting("EVENTS_URL", c.Events.URL),
+		stringSetting("EVENTS_SUBJECT_PREFIX", c.Events.SubjectPrefix),
+		stringSetting("NOTIFICATIONS_BACKEND", c.Notifications.Backend),
+		stringSetting("SMTP_HOST", c.Notifications.SMTPHost),
+		stringSetting("SMTP_PORT", c.Notifications.SMTPPort),
+		stringSetting("SMTP_USERNAME", c.Notifications.SMTPUsername),
+		secretSetting("SMTP_PASSWORD", c.Notifications.SMTPPassword),
+		stringSetting("SMTP_FROM", c.Notifications.SMTPFrom),
+		secretSetting("RSVP_LINK_SECRET", c.Notifications.RSVPSecret),
+		durationSetting("RSVP_LINK_TTL", c.Notifications.RSVPLinkTTL),
+		durationSetting("GUEST_LINK_TTL", c.Notifications.GuestLinkTTL),
+		durationSetting("DIGEST_INTERVAL", c.Notifications.DigestInterval),
+		stringSetting("NOTIFICATION_TEMPLATES_DIR", c.Notifications.TemplatesDir),
+		secretSetting("ADMIN_API_KEY", c.Admin.APIKey),
+		secretSetting("SCIM_TOKEN", c.Admin.SCIMToken),
+		stringSetting("BOOTSTRAP_FILE", c.Admin.BootstrapFile),
+		urlSetting("LDAP_URL", c.Directory.URL),
+		stringSetting("LDAP_BIND_DN", c.Directory.BindDN),
+		secretSetting("LDAP_BIND_PASSWORD", c.Directory.BindPassword),
+		stringSetting("LDAP_BASE_DN", c.Directory.BaseDN),
+		stringSetting("LDAP_USER_FILTER", c.Directory.UserFilter),
+		stringSetting("LDAP_ID_ATTRIBUTE", c.Directory.IDAttribute),
+		stringSetting("LDAP_NAME_ATTRIBUTE", c.Directory.NameAttribute),
+		stringSetting("LDAP_EMAIL_ATTRIBUTE", c.Directory.EmailAttribute),
+		durationSetting("LDAP_SYNC_INTERVAL", c.Directory.SyncInterval),
+		stringSetting("LDAP_SYNC_ON_CONFLICT", c.Directory.OnConflict),
+		stringSetting("LDAP_SYNC_ON_MISSING", c.Directory.OnMissing),
+		boolSetting("REQUIRE_EMAIL_VERIFICATION", c.Accounts.RequireEmailVerification),
+		durationSetting("USER_CACHE_TTL", c.Accounts.UserCacheTTL),
+		intSetting("MAX_TITLE_LENGTH", c.Limits.MaxTitleLength),
+		intSetting("MAX_NAME_LENGTH", c.Limits.MaxNameLength),
+		intSetting("QUOTA_MEETINGS_PER_MONTH", c.Quotas.MeetingsPerMonth),
+		intSetting("QUOTA_PARTICIPANTS_PER_MEETING", c.Quotas.ParticipantsPerMeeting),
+		stringSetting("METERING_BACKEND", c.Metering.Backend),
+		urlSetting("METERING_URL", c.Metering.URL),
+		stringSetting("FILE_STORAGE_BACKEND", c.Files.Backend),
+		stringSetting("FILE_STORAGE_DIR", c.Files.Dir),
+		stringSetting("FILE_STORAGE_BUCKET", c.Files.Bucket),
+		stringSetting("FILE_STORAGE_PREFIX", c.Files.Prefix),
+		urlSetting("FILE_STORAGE_ENDPOINT", c.Files.Endpoint),
+		stringSetting("FILE_STORAGE_REGION", c.Files.Region),
+		stringSetting("FILE_STORAGE_ACCESS_KEY_ID", c.Files.AccessKeyID),
+		secretSetting("FILE_STORAGE_SECRET_ACCESS_KEY", c.Files.SecretAccessKey),
+		stringSetting("FILE_STORAGE_CREDENTIALS_FILE", c.Files.CredentialsFile),
+		durationSetting("FILE_LINK_TTL", c.Files.LinkTTL),
+		boolSetting("MATERIALIZE_RECOMMENDATIONS", c.Scheduling.MaterializeRecommendations),
+		durationSetting("SLOT_GRANULARITY", c.Scheduling.SlotGranularity),
+		stringSetting("SCHEDULING_POLICY_FILE", c.Scheduling.PolicyFile),
+		durationSetting("AVAILABILITY_DEDUP_WINDOW", c.Scheduling.AvailabilityDedupWindow),
+		stringSetting("WORKING_HOURS_START", c.Scheduling.WorkingHoursStart),
+		stringSetting("WORKING_HOURS_END", c.Scheduling.WorkingHoursEnd),
+		boolSetting("WORKING_HOURS_WEEKDAYS_ONLY", c.Scheduling.WorkingHoursWeekdaysOnly),
+		durationSetting("DUPLICATE_MEETING_WINDOW", c.Scheduling.DuplicateMeetingWindow),
+		urlSetting("PRECOMPUTE_QUEUE_URL", c.Scheduling.PrecomputeQueueURL),
+		stringSetting("SERVICE_MODE", c.Deployment.Mode),
+		intSetting("REPLICAS", c.Deployment.Replicas),
+		stringSetting("STATE_CHECK_MODE", c.Deployment.StateCheckMode),
+		boolSetting("FAULT_INJECTION_ENABLED", c.Deployment.FaultInjection),
+		boolSetting("READ_ONLY", c.Deployment.ReadOnly),
+		stringSetting("ERROR_REPORTING_BACKEND", c.Reporting.Backend),
+		urlSetting("ERROR_REPORTING_URL", c.Reporting.URL),
+	}
+}
+
+func stringSetting(name, value string) Setting {
+	return Setting{Name: name, Value: value, Source: source(name, nil)}
+}
+
+func intSetting(name string, value int) Setting {
+	return Setting{Name: name, Value: strconv.Itoa(value), Source: source(name, func(raw string) error {
+		_, err := strconv.Atoi(raw)
+		return err
+	})}
+}
+
+func boolSetting(name string, value bool) Setting {
+	return Setting{Name: name, Value: strconv.FormatBool(value), Source: source(name, func(raw string) error {
+		_, err := strconv.ParseBool(raw)
+		return err
+	})}
+}
+
+func durationSetting(name string, value time.Duration) Setting {
+	return Setting{Name: name, Value: value.String(), Source: source(name, func(raw string) error {
+		_, err := time.ParseDuration(raw)
+		return err
+	})}
+}
+
+// secretSetting masks the value, showing only whether it is set
+func secretSetting(name, value string) Setting {
+	if value != "" {
+		value = masked
+	}
+	return Setting{Name: name, Value: value, Source: source(name, nil)}
+}
+
+// urlSetting masks the password or key embedded in a URL, such as a database DSN or a
+// Sentry DSN
+func urlSetting(name, value string) Setting {
+	setting := stringSetting(name, value)
+	u, err := url.Parse(value)
+	if err != nil {
+		setting.Value = masked
+		return setting
+	}
+	if u.User != nil {
+		// The placeholder survives URL escaping, unlike the asterisks of the mask
+		const placeholder = "MASKED"
+		if _, hasPassword := u.User.Password(); hasPassword {
+			u.User = url.UserPassword(u.User.Username(), placeholder)
+		} else {
+			u.User = url.User(placeholder)
+		}
+		setting.Value = strings.Replace(u.String(), placeholder, masked, 1)
+	}
+	return setting
+}
+
+// source tells whether the named environment variable set a value; parse checks that
+// a set value was valid
+func source(name string, parse func(string) error) string {
+	raw, exists := os.LookupEnv(name)
+	if !exists {
+		return SourceDefault
+	}
+	if parse != nil && parse(raw) != nil {
+		return SourceInvalidEnv
+	}
+	return SourceEnv
+}
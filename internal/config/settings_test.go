@@ -0,0 +1,32 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSettings(t *testing.T) {
+	t.Setenv("DB_DSN", "postgres://meetsync:hunter2@db:5432/meetsync?sslmode=disable")
+	t.Setenv("ERROR_REPORTING_URL", "https://publickey@o1.ingest.sentry.io/42")
+	t.Setenv("ADMIN_API_KEY", "admin-secret")
+	t.Setenv("SMTP_PASSWORD", "smtp-secret")
+	t.Setenv("MAX_TITLE_LENGTH", "120")
+	t.Setenv("REPLICAS", "three")
+
+	settings := make(map[string]Setting)
+	for _, setting := range Load().Settings() {
+		settings[setting.Name] = setting
+	}
+
+	assert.Equal(t, Setting{Name: "DB_DSN", Value: "postgres://meetsync:********@db:5432/meetsync?sslmode=disable", Source: SourceEnv}, settings["DB_DSN"])
+	assert.Equal(t, Setting{Name: "STORAGE_DRIVER", Value: "postgres", Source: SourceDefault}, settings["STORAGE_DRIVER"], "DB_DSN selects the Postgres driver")
+	assert.Equal(t, settings["DB_DSN"].Value, settings["STORAGE_DSN"].Value)
+	assert.Equal(t, "https://********@o1.ingest.sentry.io/42", settings["ERROR_REPORTING_URL"].Value)
+	assert.Equal(t, masked, settings["ADMIN_API_KEY"].Value)
+	assert.Equal(t, masked, settings["SMTP_PASSWORD"].Value)
+	assert.Equal(t, Setting{Name: "MAX_TITLE_LENGTH", Value: "120", Source: SourceEnv}, settings["MAX_TITLE_LENGTH"])
+	assert.Equal(t, Setting{Name: "REPLICAS", Value: "1", Source: SourceInvalidEnv}, settings["REPLICAS"])
+	assert.Equal(t, SourceDefault, settings["SLOT_GRANULARITY"].Source)
+	assert.Equal(t, "15m0s", settings["SLOT_GRANULARITY"].Value)
+}
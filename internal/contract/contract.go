@@ -0,0 +1,388 @@
+// Package contract checks HTTP exchanges against the OpenAPI document of the API, so
+// tests fail when the API and its documentation drift apart: undocumented paths,
+// status codes, content types and fields, missing required fields and values of the
+// wrong type are all reported.
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// schemaRefPrefix prefixes the references to reusable schemas
+const schemaRefPrefix = "#/components/schemas/"
+
+// Spec is a parsed OpenAPI document
+type Spec struct {
+	paths   []pathTemplate
+	schemas map[string]any
+}
+
+// pathTemplate is a documented path, such as /api/meetings/{id}, and its operations
+type pathTemplate struct {
+	segments   []string
+	operations map[string]any // by lowercase method
+}
+
+// Load reads the OpenAPI document at path
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Paths      map[string]map[string]any `yaml:"paths"`
+		Components struct {
+			Schemas map[string]any `yaml:"schemas"`
+		} `yaml:"components"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	spec := &Spec{schemas: doc.Components.Schemas}
+	for template, operations := range doc.Paths {
+		spec.paths = append(spec.paths, pathTemplate{
+			segments:   strings.Split(strings.Trim(template, "/"), "/"),
+			operations: operations,
+		})
+	}
+	// Prefer literal segments over parameters, so /api/meetings/batch is not taken for
+	// /api/meetings/{id}
+	sort.Slice(spec.paths, func(i, j int) bool {
+		return spec.paths[i].literals() > spec.paths[j].literals()
+	})
+	return spec, nil
+}
+
+// literals counts the segments of the template that are not parameters
+func (p pathTemplate) literals() int {
+	n := 0
+	for _, segment := range p.segments {
+		if !strings.HasPrefix(segment, "{") {
+			n++
+		}
+	}
+	return n
+}
+
+// matches reports whether the template matches the segments of a request path
+func (p pathTemplate) matches(segments []string) bool {
+	if len(segments) != len(p.segments) {
+		return false
+	}
+	for i, segment := range p.segments {
+		if !strings.HasPrefix(segment, "{") && segment != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// operation returns the documented operation for a request
+func (s *Spec) operation(method, path string) (map[string]any, error) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for _, template := range s.paths {
+		if !template.matches(segments) {
+			continue
+		}
+		operation, ok := template.operations[strings.ToLower(method)].(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("undocumented method %s for path %s", method, path)
+		}
+		return operation, nil
+	}
+	return nil, fmt.Errorf("undocumented path %s", path)
+}
+
+// CheckRequest returns how a request departs from the documentation of its operation
+func (s *Spec) CheckRequest(method, path, contentType string, body []byte) []string {
+	operation, err := s.operation(method, path)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	requestBody, ok := operation["requestBody"].(map[string]any)
+	if !ok {
+		return []string{"undocumented request body"}
+	}
+	return s.checkContent(requestBody, contentType, body, "request")
+}
+
+// CheckResponse returns how a response departs from the documentation of the operation
+// of its request
+func (s *Spec) CheckResponse(method, path string, status int, contentType string, body []byte) []string {
+	operation, err := s.operation(method, path)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	responses, _ := operation["responses"].(map[string]any)
+	response, ok := responses[strconv.Itoa(status)].(map[string]any)
+	if !ok {
+		response, ok = responses[fmt.Sprintf("%dXX", status/100)].(map[string]any)
+	}
+	if !ok {
+		response, ok = responses["default"].(map[string]any)
+	}
+	if !ok {
+		return []string{fmt.Sprintf("undocumented status %d", status)}
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	return s.checkContent(response, contentType, body, "response")
+}
+
+// checkContent checks a body against the documented content of a request or response
+func (s *Spec) checkContent(documented map[string]any, contentType string, body []byte, at string) []string {
+	content, ok := documented["content"].(map[string]any)
+	if !ok {
+		return []string{fmt.Sprintf("undocumented %s body", at)}
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+	media, ok := content[mediaType].(map[string]any)
+	if !ok {
+		if _, anyType := content["*/*"]; anyType {
+			return nil
+		}
+		return []string{fmt.Sprintf("undocumented %s content type %q", at, contentType)}
+	}
+	schema, ok := media["schema"]
+	if !ok || mediaType != "application/json" {
+		return nil
+	}
+
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []string{fmt.Sprintf("invalid JSON %s body: %v", at, err)}
+	}
+	var violations []string
+	s.validate(schema, value, at, &violations)
+	return violations
+}
+
+// validate checks value against schema, appending violations
+func (s *Spec) validate(schema any, value any, at string, violations *[]string) {
+	resolved := s.flatten(schema)
+	if resolved == nil {
+		return
+	}
+	fail := func(format string, args ...any) {
+		*violations = append(*violations, fmt.Sprintf("%s: %s", at, fmt.Sprintf(format, args...)))
+	}
+
+	if enum, ok := resolved["enum"].([]any); ok && value != nil && !containsValue(enum, value) {
+		fail("%v is not one of %v", value, enum)
+	}
+
+	schemaType, _ := resolved["type"].(string)
+	if schemaType == "" && resolved["properties"] != nil {
+		schemaType = "object"
+	}
+	switch schemaType {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			fail("expected an object, got %s", describe(value))
+			return
+		}
+		properties, _ := resolved["properties"].(map[string]any)
+		additional := resolved["additionalProperties"]
+		for _, name := range sortedKeys(object) {
+			if property, ok := properties[name]; ok {
+				s.validate(property, object[name], at+"."+name, violations)
+				continue
+			}
+			switch additional := additional.(type) {
+			case bool:
+				if !additional {
+					fail("undocumented field %q", name)
+				}
+			case map[string]any:
+				s.validate(additional, object[name], at+"."+name, violations)
+			default:
+				// Objects without documented properties are free-form
+				if len(properties) > 0 {
+					fail("undocumented field %q", name)
+				}
+			}
+		}
+		required, _ := resolved["required"].([]any)
+		for _, name := range required {
+			if _, ok := object[name.(string)]; !ok {
+				fail("missing required field %q", name)
+			}
+		}
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			fail("expected an array, got %s", describe(value))
+			return
+		}
+		for i, item := range array {
+			s.validate(resolved["items"], item, fmt.Sprintf("%s[%d]", at, i), violations)
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			fail("expected a string, got %s", describe(value))
+			return
+		}
+		if resolved["format"] == "date-time" {
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				fail("%q is not an RFC 3339 date-time", str)
+			}
+		}
+	case "integer":
+		number, ok := value.(float64)
+		if !ok || number != float64(int64(number)) {
+			fail("expected an integer, got %s", describe(value))
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			fail("expected a number, got %s", describe(value))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("expected a boolean, got %s", describe(value))
+		}
+	}
+}
+
+// flatten resolves the references of a schema and merges its allOf schemas into one
+func (s *Spec) flatten(schema any) map[string]any {
+	object, ok := schema.(map[string]any)
+	if !ok {
+		return nil
+	}
+	if ref, ok := object["$ref"].(string); ok {
+		return s.flatten(s.schemas[strings.TrimPrefix(ref, schemaRefPrefix)])
+	}
+	parts, ok := object["allOf"].([]any)
+	if !ok {
+		return object
+	}
+
+	// The schema itself may add properties next to allOf
+	own := map[string]any{}
+	for key, value := range object {
+		if key != "allOf" {
+			own[key] = value
+		}
+	}
+
+	merged := map[string]any{}
+	properties := map[string]any{}
+	var required []any
+	for _, part := range append(parts, own) {
+		flat := s.flatten(part)
+		if flat == nil {
+			continue
+		}
+		for key, value := range flat {
+			switch key {
+			case "properties":
+				for name, property := range value.(map[string]any) {
+					properties[name] = property
+				}
+			case "required":
+				required = append(required, value.([]any)...)
+			default:
+				merged[key] = value
+			}
+		}
+	}
+	if len(properties) > 0 {
+		merged["properties"] = properties
+	}
+	if len(required) > 0 {
+		merged["required"] = required
+	}
+	return merged
+}
+
+// Handler wraps next, reporting to t every way its requests and responses depart from
+// the documentation
+func (s *Spec) Handler(t testing.TB, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Helper()
+		var requestBody []byte
+		if r.Body != nil {
+			requestBody, _ = io.ReadAll(r.Body)
+			r.Body = io.NopCloser(bytes.NewReader(requestBody))
+		}
+		for _, violation := range s.CheckRequest(r.Method, r.URL.Path, r.Header.Get("Content-Type"), requestBody) {
+			t.Errorf("%s %s: %s", r.Method, r.URL.Path, violation)
+		}
+
+		recorder := httptest.NewRecorder()
+		next.ServeHTTP(recorder, r)
+		for _, violation := range s.CheckResponse(r.Method, r.URL.Path, recorder.Code, recorder.Header().Get("Content-Type"), recorder.Body.Bytes()) {
+			t.Errorf("%s %s -> %d: %s", r.Method, r.URL.Path, recorder.Code, violation)
+		}
+
+		for key, values := range recorder.Header() {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(recorder.Code)
+		w.Write(recorder.Body.Bytes())
+	})
+}
+
+// containsValue reports whether a JSON value is among the values of an enum
+func containsValue(enum []any, value any) bool {
+	for _, candidate := range enum {
+		if fmt.Sprint(candidate) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// describe names the JSON type of a value in violations
+func describe(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "an object"
+	case []any:
+		return "an array"
+	case string:
+		return "a string"
+	case float64:
+		return "a number"
+	case bool:
+		return "a boolean"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// sortedKeys returns the keys of an object in order, for stable reports
+func sortedKeys(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,156 @@
+package contract
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSpec = `
+openapi: 3.1.0
+paths:
+  /api/meetings/{id}:
+    put:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/UpdateMeetingRequest'
+      responses:
+        '200':
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/MeetingResponse'
+        '404':
+          description: Not found
+  /api/meetings/batch:
+    post:
+      responses:
+        '202':
+          description: Accepted
+components:
+  schemas:
+    UpdateMeetingRequest:
+      type: object
+      properties:
+        title:
+          type: string
+    Meeting:
+      type: object
+      properties:
+        id:
+          type: string
+        estimatedDuration:
+          type: integer
+        status:
+          type: string
+          enum: [draft, pending]
+        createdAt:
+          type: string
+          format: date-time
+        metadata:
+          type: object
+          additionalProperties:
+            type: string
+      required:
+        - id
+    MeetingResponse:
+      allOf:
+        - type: object
+          properties:
+            meeting:
+              $ref: '#/components/schemas/Meeting'
+        - type: object
+          properties:
+            warnings:
+              type: array
+              items:
+                type: string
+`
+
+func loadTestSpec(t *testing.T) *Spec {
+	path := filepath.Join(t.TempDir(), "openapi.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(testSpec), 0o600))
+	spec, err := Load(path)
+	assert.NoError(t, err)
+	return spec
+}
+
+func TestCheckResponse(t *testing.T) {
+	spec := loadTestSpec(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+		body   string
+		want   []string
+	}{
+		{
+			name:   "documented",
+			method: "PUT", path: "/api/meetings/m1", status: 200,
+			body: `{"meeting":{"id":"m1","estimatedDuration":30,"status":"draft","createdAt":"2025-01-10T08:00:00Z","metadata":{"crm":"acme"}},"warnings":[]}`,
+		},
+		{
+			name:   "literal segments win over parameters",
+			method: "POST", path: "/api/meetings/batch", status: 202,
+		},
+		{
+			name:   "undocumented path",
+			method: "GET", path: "/api/nowhere", status: 200,
+			want: []string{"undocumented path /api/nowhere"},
+		},
+		{
+			name:   "undocumented method",
+			method: "DELETE", path: "/api/meetings/m1", status: 204,
+			want: []string{"undocumented method DELETE for path /api/meetings/m1"},
+		},
+		{
+			name:   "undocumented status",
+			method: "PUT", path: "/api/meetings/m1", status: 409, body: `{}`,
+			want: []string{"undocumented status 409"},
+		},
+		{
+			name:   "undocumented body",
+			method: "PUT", path: "/api/meetings/m1", status: 404, body: `{"error":{}}`,
+			want: []string{"undocumented response body"},
+		},
+		{
+			name:   "fields",
+			method: "PUT", path: "/api/meetings/m1", status: 200,
+			body: `{"meeting":{"estimatedDuration":1.5,"status":"done","createdAt":"yesterday","metadata":{"crm":1},"secret":true},"warnings":null}`,
+			want: []string{
+				`response.meeting.createdAt: "yesterday" is not an RFC 3339 date-time`,
+				"response.meeting.estimatedDuration: expected an integer, got a number",
+				"response.meeting.metadata.crm: expected a string, got a number",
+				`response.meeting: undocumented field "secret"`,
+				"response.meeting.status: done is not one of [draft pending]",
+				`response.meeting: missing required field "id"`,
+				"response.warnings: expected an array, got null",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := spec.CheckResponse(tt.method, tt.path, tt.status, "application/json", []byte(tt.body))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCheckRequest(t *testing.T) {
+	spec := loadTestSpec(t)
+
+	assert.Empty(t, spec.CheckRequest("PUT", "/api/meetings/m1", "application/json", []byte(`{"title":"Sync"}`)))
+	assert.Equal(t, []string{`request: undocumented field "titel"`},
+		spec.CheckRequest("PUT", "/api/meetings/m1", "application/json", []byte(`{"titel":"Sync"}`)))
+	assert.Equal(t, []string{`undocumented request content type "text/csv"`},
+		spec.CheckRequest("PUT", "/api/meetings/m1", "text/csv", []byte("title\nSync")))
+	assert.Equal(t, []string{"undocumented request body"},
+		spec.CheckRequest("POST", "/api/meetings/batch", "application/json", []byte(`{}`)))
+}
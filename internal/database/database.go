@@ -0,0 +1,45 @@
+// Package database opens connection pools to the SQL databases backing repositories
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// PoolConfig configures a connection pool; zero values keep the database/sql defaults
+type PoolConfig struct {
+	MaxOpenConns    int           // maximum open connections, unlimited when zero
+	MaxIdleConns    int           // maximum idle connections kept for reuse
+	ConnMaxLifetime time.Duration // connections are closed once this old, unlimited when zero
+	ConnMaxIdleTime time.Duration // idle connections are closed after this long, unlimited when zero
+}
+
+// Configure applies pool settings to db
+func Configure(db *sql.DB, pool PoolConfig) {
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	if pool.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(pool.MaxIdleConns)
+	}
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
+}
+
+// Open opens a connection pool with the driver, applies the pool settings and checks
+// the database can be reached within timeout
+func Open(driver, dsn string, pool PoolConfig, timeout time.Duration) (*sql.DB, error) {
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	Configure(db, pool)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to reach database: %w", err)
+	}
+	return db, nil
+}
@@ -0,0 +1,39 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// unreachableDriver is a driver whose database cannot be reached
+type unreachableDriver struct{}
+
+func (unreachableDriver) Open(name string) (driver.Conn, error) {
+	return nil, stderrors.New("connection refused")
+}
+
+func init() {
+	sql.Register("unreachable", unreachableDriver{})
+}
+
+func TestConfigure(t *testing.T) {
+	db, err := sql.Open("unreachable", "")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	Configure(db, PoolConfig{MaxOpenConns: 25, MaxIdleConns: 10, ConnMaxLifetime: time.Minute})
+	assert.Equal(t, 25, db.Stats().MaxOpenConnections)
+}
+
+func TestOpen(t *testing.T) {
+	_, err := Open("unknown", "", PoolConfig{}, time.Second)
+	assert.ErrorContains(t, err, "failed to open database")
+
+	_, err = Open("unreachable", "", PoolConfig{}, time.Second)
+	assert.ErrorContains(t, err, "failed to reach database")
+}
@@ -0,0 +1,119 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// migrationLockID serializes migrations across replicas starting at the same time
+const migrationLockID = 0x6d656574 // "meet"
+
+// migrationFiles holds the schema of the Postgres repositories, one file per version
+// named <version>_<description>.sql. Never edit a released migration; add a new one.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migration is one version of the schema
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+// Migrations returns the embedded migrations in version order
+func Migrations() ([]Migration, error) {
+	return readMigrations(migrationFiles, "migrations")
+}
+
+// readMigrations reads the migrations in dir, checking their versions run from 1
+// without gaps
+func readMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".sql")
+		if entry.IsDir() || name == entry.Name() {
+			continue
+		}
+		prefix, _, _ := strings.Cut(name, "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s does not start with a version number", entry.Name())
+		}
+		data, err := fs.ReadFile(fsys, dir+"/"+entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, Migration{Version: version, Name: name, SQL: string(data)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			return nil, fmt.Errorf("migration %s should be version %d", migration.Name, i+1)
+		}
+	}
+	return migrations, nil
+}
+
+// Migrate brings the schema of a Postgres database to the latest embedded version in
+// one transaction, recording applied versions in schema_migrations, and returns how many
+// migrations it applied. It holds an advisory lock while migrating, so replicas starting
+// together apply each migration once. A database migrated by a newer build is refused.
+func Migrate(db *sql.DB, timeout time.Duration) (int, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to start migrations: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationLockID); err != nil {
+		return 0, fmt.Errorf("failed to lock migrations: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		name       TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`); err != nil {
+		return 0, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	var current int
+	if err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return 0, fmt.Errorf("failed to read the schema version: %w", err)
+	}
+	if current > len(migrations) {
+		return 0, fmt.Errorf("database schema version %d is newer than this build supports (%d)", current, len(migrations))
+	}
+
+	for _, migration := range migrations[current:] {
+		if _, err := tx.ExecContext(ctx, migration.SQL); err != nil {
+			return 0, fmt.Errorf("migration %s failed: %w", migration.Name, err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, migration.Version, migration.Name); err != nil {
+			return 0, fmt.Errorf("failed to record migration %s: %w", migration.Name, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit migrations: %w", err)
+	}
+	return len(migrations) - current, nil
+}
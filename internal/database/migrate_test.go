@@ -0,0 +1,38 @@
+package database
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrations(t *testing.T) {
+	migrations, err := Migrations()
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	for i, migration := range migrations {
+		assert.Equal(t, i+1, migration.Version)
+		assert.NotEmpty(t, migration.SQL, migration.Name)
+	}
+}
+
+func TestReadMigrations(t *testing.T) {
+	migrations, err := readMigrations(fstest.MapFS{
+		"m/0002_second.sql": {Data: []byte("CREATE INDEX b;")},
+		"m/0001_first.sql":  {Data: []byte("CREATE TABLE a;")},
+		"m/README":          {Data: []byte("not a migration")},
+	}, "m")
+	require.NoError(t, err)
+	assert.Equal(t, []Migration{
+		{Version: 1, Name: "0001_first", SQL: "CREATE TABLE a;"},
+		{Version: 2, Name: "0002_second", SQL: "CREATE INDEX b;"},
+	}, migrations)
+
+	// Versions must run from 1 without gaps
+	_, err = readMigrations(fstest.MapFS{"m/0002_second.sql": {}}, "m")
+	assert.ErrorContains(t, err, "should be version 1")
+	_, err = readMigrations(fstest.MapFS{"m/second.sql": {}}, "m")
+	assert.ErrorContains(t, err, "does not start with a version number")
+}
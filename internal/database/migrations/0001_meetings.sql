@@ -0,0 +1,35 @@
+-- Meetings, availability, timelines and recommendations are stored as JSON documents
+-- next to the columns they are looked up by
+CREATE TABLE meetings (
+	id          TEXT PRIMARY KEY,
+	external_id TEXT UNIQUE,
+	created_at  TIMESTAMPTZ NOT NULL,
+	data        JSONB NOT NULL
+);
+CREATE INDEX meetings_created_at_idx ON meetings (created_at, id);
+
+CREATE TABLE availabilities (
+	id             TEXT PRIMARY KEY,
+	meeting_id     TEXT NOT NULL,
+	participant_id TEXT NOT NULL,
+	created_at     TIMESTAMPTZ NOT NULL,
+	data           JSONB NOT NULL
+);
+CREATE INDEX availabilities_meeting_idx ON availabilities (meeting_id, participant_id);
+CREATE INDEX availabilities_participant_idx ON availabilities (participant_id);
+
+CREATE TABLE timeline_events (
+	seq         BIGSERIAL PRIMARY KEY,
+	id          TEXT NOT NULL UNIQUE,
+	meeting_id  TEXT NOT NULL REFERENCES meetings (id) ON DELETE CASCADE,
+	user_id     TEXT NOT NULL DEFAULT '',
+	occurred_at TIMESTAMPTZ NOT NULL,
+	data        JSONB NOT NULL
+);
+CREATE INDEX timeline_events_meeting_idx ON timeline_events (meeting_id, occurred_at);
+
+CREATE TABLE recommendations (
+	meeting_id  TEXT PRIMARY KEY REFERENCES meetings (id) ON DELETE CASCADE,
+	computed_at TIMESTAMPTZ NOT NULL,
+	data        JSONB NOT NULL
+);
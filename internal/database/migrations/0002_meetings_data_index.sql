@@ -0,0 +1,2 @@
+-- The tag and metadata filters of meeting lists use containment on the documents
+CREATE INDEX meetings_data_idx ON meetings USING GIN (data jsonb_path_ops);
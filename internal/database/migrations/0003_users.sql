@@ -0,0 +1,29 @@
+-- Users, email tokens and personal access tokens. Email addresses are unique whatever
+-- their case.
+CREATE TABLE users (
+	id         TEXT PRIMARY KEY,
+	email      TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL,
+	data       JSONB NOT NULL
+);
+CREATE UNIQUE INDEX users_email_idx ON users (lower(email));
+CREATE INDEX users_created_at_idx ON users (created_at, id);
+
+CREATE TABLE user_tokens (
+	token      TEXT PRIMARY KEY,
+	user_id    TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	purpose    TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	data       JSONB NOT NULL
+);
+CREATE INDEX user_tokens_user_idx ON user_tokens (user_id, purpose);
+
+CREATE TABLE access_tokens (
+	id          TEXT PRIMARY KEY,
+	user_id     TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	secret_hash TEXT NOT NULL UNIQUE,
+	created_at  TIMESTAMPTZ NOT NULL,
+	data        JSONB NOT NULL
+);
+CREATE INDEX access_tokens_user_idx ON access_tokens (user_id, created_at);
@@ -0,0 +1,8 @@
+-- The audit log, listed in (occurred_at, id) order and restored in append order
+CREATE TABLE audit_entries (
+	seq         BIGSERIAL PRIMARY KEY,
+	id          TEXT NOT NULL UNIQUE,
+	occurred_at TIMESTAMPTZ NOT NULL,
+	data        JSONB NOT NULL
+);
+CREATE INDEX audit_entries_occurred_at_idx ON audit_entries (occurred_at, id);
@@ -0,0 +1,9 @@
+-- Meeting search looks up the words of titles, tags and organizer and participant names
+-- in a generated full-text column; the 'simple' configuration neither stems nor drops
+-- stop words, so prefixes of the stored words match as typed
+ALTER TABLE meetings ADD COLUMN search TSVECTOR GENERATED ALWAYS AS (
+	to_tsvector('simple', coalesce(data->>'title', '')) ||
+	to_tsvector('simple', coalesce(data->'tags', '[]'::jsonb)) ||
+	to_tsvector('simple', jsonb_path_query_array(data, '$.organizer.name') || jsonb_path_query_array(data, '$.participants[*].name'))
+) STORED;
+CREATE INDEX meetings_search_idx ON meetings USING GIN (search);
@@ -0,0 +1,121 @@
+// Package directory reads users from an external directory, such as LDAP or Active
+// Directory, so they can be synchronized into MeetSync
+package directory
+
+import (
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// Entry is a user read from a directory
+type Entry struct {
+	ID    string // stable ID of the entry, kept when it is renamed or moved
+	DN    string
+	Name  string
+	Email string
+}
+
+// Directory lists the users of an external directory
+type Directory interface {
+	Users() ([]Entry, error)
+}
+
+// Config configures an LDAP directory
+type Config struct {
+	URL            string // ldap://, ldaps:// or ldapi:// URL of the server
+	BindDN         string // anonymous bind when empty
+	BindPassword   string
+	BaseDN         string
+	Filter         string // filter selecting users, e.g. (objectClass=person)
+	IDAttribute    string // entryUUID for OpenLDAP, objectGUID for Active Directory
+	NameAttribute  string
+	EmailAttribute string
+	Timeout        time.Duration
+}
+
+// pageSize is the number of entries requested per page, staying below the 1000 entries
+// Active Directory returns at most per search
+const pageSize = 500
+
+// LDAP reads users from an LDAP server or Active Directory domain controller
+type LDAP struct {
+	config Config
+}
+
+// NewLDAP creates a directory reading users from the LDAP server described by config
+func NewLDAP(config Config) (*LDAP, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("LDAP URL is required")
+	}
+	if config.BaseDN == "" {
+		return nil, fmt.Errorf("LDAP base DN is required")
+	}
+	if config.IDAttribute == "" || config.NameAttribute == "" || config.EmailAttribute == "" {
+		return nil, fmt.Errorf("LDAP ID, name and email attributes are required")
+	}
+	if config.Filter == "" {
+		config.Filter = "(objectClass=person)"
+	}
+	if _, err := ldap.CompileFilter(config.Filter); err != nil {
+		return nil, fmt.Errorf("invalid LDAP filter %q: %w", config.Filter, err)
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	return &LDAP{config: config}, nil
+}
+
+// Users searches the directory for users, reading all pages of results
+func (d *LDAP) Users() ([]Entry, error) {
+	conn, err := ldap.DialURL(d.config.URL,
+		ldap.DialWithDialer(&net.Dialer{Timeout: d.config.Timeout}),
+		ldap.DialWithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to LDAP server: %w", err)
+	}
+	defer conn.Close()
+	conn.SetTimeout(d.config.Timeout)
+
+	if d.config.BindDN != "" {
+		err = conn.Bind(d.config.BindDN, d.config.BindPassword)
+	} else {
+		err = conn.UnauthenticatedBind("")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to LDAP server: %w", err)
+	}
+
+	request := ldap.NewSearchRequest(d.config.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		d.config.Filter, []string{d.config.IDAttribute, d.config.NameAttribute, d.config.EmailAttribute}, nil)
+	result, err := conn.SearchWithPaging(request, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search LDAP server: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(result.Entries))
+	for _, entry := range result.Entries {
+		entries = append(entries, Entry{
+			ID:    attributeID(entry.GetRawAttributeValue(d.config.IDAttribute)),
+			DN:    entry.DN,
+			Name:  strings.TrimSpace(entry.GetAttributeValue(d.config.NameAttribute)),
+			Email: strings.TrimSpace(entry.GetAttributeValue(d.config.EmailAttribute)),
+		})
+	}
+	return entries, nil
+}
+
+// attributeID returns an ID attribute as text. Binary IDs, such as the objectGUID of
+// Active Directory, are hex-encoded.
+func attributeID(raw []byte) string {
+	if utf8.Valid(raw) {
+		return string(raw)
+	}
+	return hex.EncodeToString(raw)
+}
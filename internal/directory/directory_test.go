@@ -0,0 +1,38 @@
+package directory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLDAP(t *testing.T) {
+	config := Config{
+		URL:            "ldap://localhost:389",
+		BaseDN:         "ou=people,dc=example,dc=com",
+		IDAttribute:    "entryUUID",
+		NameAttribute:  "cn",
+		EmailAttribute: "mail",
+	}
+	directory, err := NewLDAP(config)
+	assert.NoError(t, err)
+	assert.Equal(t, "(objectClass=person)", directory.config.Filter)
+
+	for name, mutate := range map[string]func(*Config){
+		"missing URL":       func(c *Config) { c.URL = "" },
+		"missing base DN":   func(c *Config) { c.BaseDN = "" },
+		"missing attribute": func(c *Config) { c.EmailAttribute = "" },
+		"invalid filter":    func(c *Config) { c.Filter = "(objectClass=person" },
+	} {
+		invalid := config
+		mutate(&invalid)
+		_, err := NewLDAP(invalid)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestAttributeID(t *testing.T) {
+	assert.Equal(t, "3f2504e0-4f89-11d3-9a0c-0305e82c3301", attributeID([]byte("3f2504e0-4f89-11d3-9a0c-0305e82c3301")))
+	// Active Directory objectGUIDs are binary
+	assert.Equal(t, "e004253f894fd3119a0c0305e82c3301", attributeID([]byte{0xe0, 0x04, 0x25, 0x3f, 0x89, 0x4f, 0xd3, 0x11, 0x9a, 0x0c, 0x03, 0x05, 0xe8, 0x2c, 0x33, 0x01}))
+}
@@ -0,0 +1,78 @@
+package events
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Type identifies the kind of scheduling activity an event describes
+type Type string
+
+const (
+	// MeetingCreated is emitted when a new meeting is created
+	MeetingCreated Type = "meeting.created"
+	// MeetingFinalized is emitted when a meeting is finalized, that is confirmed, on a
+	// single slot
+	MeetingFinalized Type = "meeting.finalized"
+	// MeetingConfirmed is another name for MeetingFinalized, matching the confirm
+	// endpoint. Events are always emitted as MeetingFinalized.
+	MeetingConfirmed Type = "meeting.confirmed"
+	// MeetingRescheduled is emitted when a confirmed meeting moves to another slot, such
+	// as one proposed by a participant
+	MeetingRescheduled Type = "meeting.rescheduled"
+	// MeetingDeleted is emitted when a meeting is deleted
+	MeetingDeleted Type = "meeting.deleted"
+	// AvailabilityAdded is emitted when a participant submits availability
+	AvailabilityAdded Type = "availability.added"
+	// RecommendationsViewed is emitted when the recommendations of a meeting are fetched
+	RecommendationsViewed Type = "recommendations.viewed"
+)
+
+// Types lists every type of event emitted
+var Types = []Type{MeetingCreated, MeetingFinalized, MeetingRescheduled, MeetingDeleted, AvailabilityAdded, RecommendationsViewed}
+
+// Aliases maps other accepted names of event types to the type emitted
+var Aliases = map[Type]Type{MeetingConfirmed: MeetingFinalized}
+
+// Lookup returns the emitted type named name, resolving aliases, and whether it exists
+func Lookup(name string) (Type, bool) {
+	eventType := Type(name)
+	if alias, ok := Aliases[eventType]; ok {
+		return alias, true
+	}
+	for _, known := range Types {
+		if known == eventType {
+			return known, true
+		}
+	}
+	return "", false
+}
+
+// Event represents a scheduling activity consumed by downstream systems
+type Event struct {
+	ID         string      `json:"id"`
+	Type       Type        `json:"type"`
+	MeetingID  string      `json:"meetingId"`
+	OccurredAt time.Time   `json:"occurredAt"`
+	Data       interface{} `json:"data,omitempty"`
+}
+
+// New creates a new Event of the given type for a meeting
+func New(eventType Type, meetingID string, data interface{}) Event {
+	return Event{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		MeetingID:  meetingID,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+}
+
+// Subject returns the subject (NATS) or topic (Kafka) an event is published to
+func Subject(prefix string, eventType Type) string {
+	if prefix == "" {
+		return string(eventType)
+	}
+	return prefix + "." + string(eventType)
+}
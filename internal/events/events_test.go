@@ -0,0 +1,172 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingPublisher records published events for assertions
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (p *recordingPublisher) Publish(event Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+	return nil
+}
+
+func (p *recordingPublisher) Close() error { return nil }
+
+func TestNewPublisher(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend string
+		url     string
+		wantErr bool
+	}{
+		{name: "none", backend: "none"},
+		{name: "empty backend", backend: ""},
+		{name: "nats", backend: "nats", url: "nats://localhost:4222"},
+		{name: "kafka", backend: "kafka", url: "http://localhost:8082"},
+		{name: "nats without url", backend: "nats", wantErr: true},
+		{name: "kafka without url", backend: "kafka", wantErr: true},
+		{name: "unknown backend", backend: "rabbitmq", url: "amqp://localhost", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher, err := NewPublisher(tt.backend, tt.url, "meetsync")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, publisher)
+		})
+	}
+}
+
+func TestAsyncPublisher_FlushesOnClose(t *testing.T) {
+	recorder := &recordingPublisher{}
+	publisher := NewAsyncPublisher(recorder, 10)
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, publisher.Publish(New(MeetingCreated, "meeting-1", nil)))
+	}
+	require.NoError(t, publisher.Close())
+
+	assert.Len(t, recorder.events, 5)
+	assert.Error(t, publisher.Publish(New(MeetingCreated, "meeting-1", nil)))
+}
+
+func TestNATSPublisher_Publish(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		io.WriteString(conn, "INFO {\"server_id\":\"test\"}\r\n")
+		reader := bufio.NewReader(conn)
+		var lines []string
+		for len(lines) < 3 {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				break
+			}
+			lines = append(lines, strings.TrimRight(line, "\r\n"))
+		}
+		received <- lines
+	}()
+
+	publisher := NewNATSPublisher("nats://"+listener.Addr().String(), "meetsync")
+	defer publisher.Close()
+
+	event := New(AvailabilityAdded, "meeting-1", map[string]string{"userId": "user-1"})
+	require.NoError(t, publisher.Publish(event))
+
+	select {
+	case lines := <-received:
+		require.Len(t, lines, 3)
+		assert.True(t, strings.HasPrefix(lines[0], "CONNECT "))
+		assert.True(t, strings.HasPrefix(lines[1], "PUB meetsync.availability.added "))
+
+		var decoded Event
+		require.NoError(t, json.Unmarshal([]byte(lines[2]), &decoded))
+		assert.Equal(t, event.ID, decoded.ID)
+		assert.Equal(t, AvailabilityAdded, decoded.Type)
+		assert.Equal(t, "meeting-1", decoded.MeetingID)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for NATS publish")
+	}
+}
+
+func TestKafkaPublisher_Publish(t *testing.T) {
+	var (
+		gotPath        string
+		gotContentType string
+		gotBody        kafkaProduceRequest
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotContentType = r.Header.Get("Content-Type")
+		json.NewDecoder(r.Body).Decode(&gotBody)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	publisher := NewKafkaPublisher(server.URL, "meetsync")
+	event := New(MeetingCreated, "meeting-1", nil)
+	require.NoError(t, publisher.Publish(event))
+
+	assert.Equal(t, "/topics/meetsync.meeting.created", gotPath)
+	assert.Equal(t, kafkaContentType, gotContentType)
+	require.Len(t, gotBody.Records, 1)
+	assert.Equal(t, "meeting-1", gotBody.Records[0].Key)
+	assert.Equal(t, event.ID, gotBody.Records[0].Value.ID)
+}
+
+func TestKafkaPublisher_PublishErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	publisher := NewKafkaPublisher(server.URL, "meetsync")
+	assert.Error(t, publisher.Publish(New(MeetingCreated, "meeting-1", nil)))
+}
+
+func TestLookup(t *testing.T) {
+	for _, eventType := range Types {
+		found, ok := Lookup(string(eventType))
+		assert.True(t, ok, eventType)
+		assert.Equal(t, eventType, found)
+	}
+
+	found, ok := Lookup("meeting.confirmed")
+	assert.True(t, ok)
+	assert.Equal(t, MeetingFinalized, found)
+
+	_, ok = Lookup("meeting.exploded")
+	assert.False(t, ok)
+}
@@ -0,0 +1,64 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const kafkaContentType = "application/vnd.kafka.json.v2+json"
+
+// KafkaPublisher publishes events to Kafka topics through a Kafka REST proxy
+type KafkaPublisher struct {
+	baseURL     string
+	topicPrefix string
+	client      *http.Client
+}
+
+// NewKafkaPublisher creates a KafkaPublisher for a REST proxy such as http://localhost:8082
+func NewKafkaPublisher(baseURL, topicPrefix string) *KafkaPublisher {
+	return &KafkaPublisher{
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		topicPrefix: topicPrefix,
+		client:      &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+type kafkaRecord struct {
+	Key   string `json:"key"`
+	Value Event  `json:"value"`
+}
+
+type kafkaProduceRequest struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+// Publish sends the event to the topic derived from its type, keyed by meeting ID
+func (p *KafkaPublisher) Publish(event Event) error {
+	body, err := json.Marshal(kafkaProduceRequest{
+		Records: []kafkaRecord{{Key: event.MeetingID, Value: event}},
+	})
+	if err != nil {
+		return fmt.Errorf("events: failed to encode event: %w", err)
+	}
+
+	topic := Subject(p.topicPrefix, event.Type)
+	resp, err := p.client.Post(p.baseURL+"/topics/"+topic, kafkaContentType, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("events: failed to publish to Kafka topic %s: %w", topic, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("events: Kafka REST proxy returned status %d for topic %s", resp.StatusCode, topic)
+	}
+	return nil
+}
+
+// Close implements Publisher
+func (p *KafkaPublisher) Close() error {
+	return nil
+}
@@ -0,0 +1,123 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const natsDialTimeout = 5 * time.Second
+
+// NATSPublisher publishes events to NATS subjects using the NATS text protocol
+type NATSPublisher struct {
+	address       string
+	subjectPrefix string
+	conn          net.Conn
+	mu            sync.Mutex
+}
+
+// NewNATSPublisher creates a NATSPublisher for a URL such as nats://localhost:4222
+func NewNATSPublisher(rawURL, subjectPrefix string) *NATSPublisher {
+	address := rawURL
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		address = u.Host
+	}
+	return &NATSPublisher{
+		address:       address,
+		subjectPrefix: subjectPrefix,
+	}
+}
+
+// Publish sends the event to the subject derived from its type
+func (p *NATSPublisher) Publish(event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("events: failed to encode event: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Retry once with a fresh connection if the existing one has gone stale
+	for attempt := 0; attempt < 2; attempt++ {
+		if p.conn == nil {
+			if err = p.connect(); err != nil {
+				continue
+			}
+		}
+
+		subject := Subject(p.subjectPrefix, event.Type)
+		p.conn.SetWriteDeadline(time.Now().Add(natsDialTimeout))
+		if _, err = fmt.Fprintf(p.conn, "PUB %s %d\r\n%s\r\n", subject, len(payload), payload); err == nil {
+			return nil
+		}
+		p.conn.Close()
+		p.conn = nil
+	}
+	return fmt.Errorf("events: failed to publish to NATS at %s: %w", p.address, err)
+}
+
+// Close closes the connection to the NATS server
+func (p *NATSPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.conn == nil {
+		return nil
+	}
+	err := p.conn.Close()
+	p.conn = nil
+	return err
+}
+
+// connect dials the server, consumes its INFO banner and sends CONNECT
+func (p *NATSPublisher) connect() error {
+	conn, err := net.DialTimeout("tcp", p.address, natsDialTimeout)
+	if err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(conn)
+	conn.SetReadDeadline(time.Now().Add(natsDialTimeout))
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	if !strings.HasPrefix(line, "INFO") {
+		conn.Close()
+		return fmt.Errorf("unexpected NATS greeting: %q", strings.TrimSpace(line))
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	if _, err := fmt.Fprint(conn, "CONNECT {\"verbose\":false,\"pedantic\":false,\"name\":\"meetsync\"}\r\n"); err != nil {
+		conn.Close()
+		return err
+	}
+
+	p.conn = conn
+	go p.keepAlive(conn, reader)
+	return nil
+}
+
+// keepAlive answers server PINGs so the connection is not considered stale
+func (p *NATSPublisher) keepAlive(conn net.Conn, reader *bufio.Reader) {
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		if strings.HasPrefix(line, "PING") {
+			p.mu.Lock()
+			if p.conn == conn {
+				fmt.Fprint(conn, "PONG\r\n")
+			}
+			p.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"meetsync/pkg/logs"
+)
+
+const (
+	// BackendNone disables event publishing
+	BackendNone = "none"
+	// BackendNATS publishes events to NATS subjects
+	BackendNATS = "nats"
+	// BackendKafka publishes events to Kafka topics through a Kafka REST proxy
+	BackendKafka = "kafka"
+)
+
+// Publisher defines the interface for emitting events to a message broker
+type Publisher interface {
+	Publish(event Event) error
+	Close() error
+}
+
+// NewPublisher creates a Publisher for the given backend. Events are
+// delivered asynchronously so a slow or unavailable broker never delays
+// API requests.
+func NewPublisher(backend, url, subjectPrefix string) (Publisher, error) {
+	switch strings.ToLower(backend) {
+	case "", BackendNone:
+		return NoopPublisher{}, nil
+	case BackendNATS:
+		if url == "" {
+			return nil, fmt.Errorf("events: NATS backend requires a URL")
+		}
+		return NewAsyncPublisher(NewNATSPublisher(url, subjectPrefix), defaultBufferSize), nil
+	case BackendKafka:
+		if url == "" {
+			return nil, fmt.Errorf("events: Kafka backend requires a REST proxy URL")
+		}
+		return NewAsyncPublisher(NewKafkaPublisher(url, subjectPrefix), defaultBufferSize), nil
+	default:
+		return nil, fmt.Errorf("events: unknown backend %q", backend)
+	}
+}
+
+// NoopPublisher discards all events
+type NoopPublisher struct{}
+
+// Publish implements Publisher
+func (NoopPublisher) Publish(event Event) error { return nil }
+
+// Close implements Publisher
+func (NoopPublisher) Close() error { return nil }
+
+const defaultBufferSize = 1024
+
+// AsyncPublisher buffers events and publishes them from a background goroutine
+type AsyncPublisher struct {
+	next   Publisher
+	queue  chan Event
+	done   chan struct{}
+	once   sync.Once
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewAsyncPublisher wraps a Publisher so that Publish never blocks the caller
+func NewAsyncPublisher(next Publisher, bufferSize int) *AsyncPublisher {
+	p := &AsyncPublisher{
+		next:  next,
+		queue: make(chan Event, bufferSize),
+		done:  make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// Publish enqueues the event, dropping it if the buffer is full
+func (p *AsyncPublisher) Publish(event Event) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return fmt.Errorf("events: publisher is closed")
+	}
+
+	select {
+	case p.queue <- event:
+		return nil
+	default:
+		return fmt.Errorf("events: buffer full, dropping %s event for meeting %s", event.Type, event.MeetingID)
+	}
+}
+
+// Close flushes buffered events and closes the underlying publisher
+func (p *AsyncPublisher) Close() error {
+	p.once.Do(func() {
+		p.mu.Lock()
+		p.closed = true
+		close(p.queue)
+		p.mu.Unlock()
+	})
+	<-p.done
+	return p.next.Close()
+}
+
+func (p *AsyncPublisher) run() {
+	defer close(p.done)
+	for event := range p.queue {
+		if err := p.next.Publish(event); err != nil {
+			logs.Error("Failed to publish %s event for meeting %s: %v", event.Type, event.MeetingID, err)
+		}
+	}
+}
+
+// FanoutPublisher publishes every event to several publishers
+type FanoutPublisher []Publisher
+
+// Publish implements Publisher, publishing to every publisher even when some fail
+func (f FanoutPublisher) Publish(event Event) error {
+	var errs []error
+	for _, publisher := range f {
+		if err := publisher.Publish(event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close implements Publisher
+func (f FanoutPublisher) Close() error {
+	var errs []error
+	for _, publisher := range f {
+		if err := publisher.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,179 @@
+package filestore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// uploadURLTTL is how long the URLs files are uploaded with stay valid
+const uploadURLTTL = 15 * time.Minute
+
+// bucketStore is a Store keeping files in an S3 or GCS bucket. Both services accept
+// requests authenticated by version 4 signed URLs, which differ only in naming and in
+// how the string to sign is signed, so files are uploaded with a signed URL too and no
+// client library is needed.
+type bucketStore struct {
+	client    *http.Client
+	objectURL func(key string) *url.URL // where the object of a prefixed key is
+	prefix    string
+	signer    signer
+	now       func() time.Time
+}
+
+// signer signs version 4 URLs for S3 (AWS4-HMAC-SHA256) or GCS (GOOG4-RSA-SHA256)
+type signer struct {
+	algorithm  string
+	param      string // prefix of the signing query parameters, X-Amz- or X-Goog-
+	credential string // access key ID or service account email
+	region     string
+	service    string
+	request    string // terminates the credential scope
+	sign       func(date, stringToSign string) (string, error)
+}
+
+// Put implements Store
+func (s *bucketStore) Put(ctx context.Context, key, contentType string, body []byte) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	target := s.objectURL(s.prefix + key)
+	signed, err := s.signer.presign(http.MethodPut, target, map[string]string{"content-type": contentType}, nil, uploadURLTTL, s.now())
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, signed, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("filestore: failed to store %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("filestore: failed to store %s: %s: %s", key, resp.Status, bytes.TrimSpace(message))
+	}
+	return nil
+}
+
+// SignedURL implements Store
+func (s *bucketStore) SignedURL(key string, ttl time.Duration) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	if err := validateTTL(ttl); err != nil {
+		return "", err
+	}
+	query := url.Values{"response-content-disposition": {fmt.Sprintf(`attachment; filename="%s"`, path.Base(key))}}
+	return s.signer.presign(http.MethodGet, s.objectURL(s.prefix+key), nil, query, ttl, s.now())
+}
+
+// presign returns target signed for method until ttl elapses from now. The request must
+// send the given headers, with lowercase names, besides the host.
+func (s signer) presign(method string, target *url.URL, headers map[string]string, query url.Values, ttl time.Duration, now time.Time) (string, error) {
+	now = now.UTC()
+	date := now.Format("20060102")
+	scope := strings.Join([]string{date, s.region, s.service, s.request}, "/")
+
+	signedHeaders := map[string]string{"host": target.Host}
+	for name, value := range headers {
+		signedHeaders[name] = value
+	}
+	names := make([]string, 0, len(signedHeaders))
+	for name := range signedHeaders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(signedHeaders[name]) + "\n")
+	}
+
+	params := url.Values{}
+	for name, values := range query {
+		params[name] = values
+	}
+	params.Set(s.param+"Algorithm", s.algorithm)
+	params.Set(s.param+"Credential", s.credential+"/"+scope)
+	params.Set(s.param+"Date", now.Format("20060102T150405Z"))
+	params.Set(s.param+"Expires", strconv.Itoa(int(ttl.Seconds())))
+	params.Set(s.param+"SignedHeaders", strings.Join(names, ";"))
+	canonicalQuery := canonicalQueryString(params)
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		uriEncode(target.Path, false),
+		canonicalQuery,
+		canonicalHeaders.String(),
+		strings.Join(names, ";"),
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{s.algorithm, now.Format("20060102T150405Z"), scope, hex.EncodeToString(hash[:])}, "\n")
+	signature, err := s.sign(date, stringToSign)
+	if err != nil {
+		return "", fmt.Errorf("filestore: failed to sign URL: %w", err)
+	}
+
+	return target.Scheme + "://" + target.Host + uriEncode(target.Path, false) + "?" +
+		canonicalQuery + "&" + s.param + "Signature=" + signature, nil
+}
+
+// canonicalQueryString encodes params sorted by name, as version 4 signatures expect
+func canonicalQueryString(params url.Values) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var pairs []string
+	for _, name := range names {
+		values := append([]string(nil), params[name]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes every byte of s but unreserved characters, and slashes
+// unless encodeSlash is set, as version 4 signatures expect
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// parseEndpoint parses the URL of a service endpoint
+func parseEndpoint(endpoint string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("filestore: invalid endpoint %q", endpoint)
+	}
+	return u, nil
+}
@@ -0,0 +1,92 @@
+// Package filestore keeps files clients download, such as spreadsheet exports and
+// backups, in a local directory or an S3 or GCS bucket, and signs short-lived URLs
+// clients download them from directly, without going through the API again.
+package filestore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// BackendNone keeps no files; features storing them are disabled
+	BackendNone = "none"
+	// BackendLocal keeps files in a local directory and serves them from the API
+	BackendLocal = "local"
+	// BackendS3 keeps files in an S3 bucket, or a bucket of an S3 compatible service
+	BackendS3 = "s3"
+	// BackendGCS keeps files in a Google Cloud Storage bucket
+	BackendGCS = "gcs"
+)
+
+// MaxURLTTL is the longest a signed URL can stay valid, the limit S3 and GCS put on
+// their signed URLs
+const MaxURLTTL = 7 * 24 * time.Hour
+
+// Store keeps files under slash-separated keys such as exports/42/report.xlsx
+type Store interface {
+	// Put stores body under key, replacing any file stored there
+	Put(ctx context.Context, key, contentType string, body []byte) error
+	// SignedURL returns a URL downloading the file stored under key as an attachment
+	// named after the last element of the key, until ttl elapses
+	SignedURL(key string, ttl time.Duration) (string, error)
+}
+
+// Config selects and configures the backend of a Store
+type Config struct {
+	Backend string
+
+	// Local backend
+	Dir     string // directory the files are kept in
+	BaseURL string // URL the API serves the files from
+	Secret  []byte // signs download URLs
+
+	// S3 and GCS backends
+	Bucket          string
+	Prefix          string // prepended to keys, such as meetsync/
+	Endpoint        string // overrides the service URL, for S3 compatible services and emulators
+	Region          string // S3 region
+	AccessKeyID     string // S3 credentials
+	SecretAccessKey string
+	CredentialsFile string // GCS service account key file, in JSON
+}
+
+// New creates the Store selected by cfg.Backend. It returns a nil Store for the none
+// backend.
+func New(cfg Config) (Store, error) {
+	switch strings.ToLower(cfg.Backend) {
+	case "", BackendNone:
+		return nil, nil
+	case BackendLocal:
+		return NewLocal(cfg.Dir, cfg.BaseURL, cfg.Secret)
+	case BackendS3:
+		return NewS3(cfg.Bucket, cfg.Prefix, cfg.Region, cfg.Endpoint, cfg.AccessKeyID, cfg.SecretAccessKey)
+	case BackendGCS:
+		return NewGCS(cfg.Bucket, cfg.Prefix, cfg.Endpoint, cfg.CredentialsFile)
+	default:
+		return nil, fmt.Errorf("filestore: unknown backend %q", cfg.Backend)
+	}
+}
+
+// validateKey rejects keys that are empty, absolute or climb out of the store with ..
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.HasSuffix(key, "/") {
+		return fmt.Errorf("filestore: invalid key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("filestore: invalid key %q", key)
+		}
+	}
+	return nil
+}
+
+// validateTTL rejects URL lifetimes S3 and GCS would refuse
+func validateTTL(ttl time.Duration) error {
+	if ttl <= 0 || ttl > MaxURLTTL {
+		return fmt.Errorf("filestore: URL lifetime %s must be positive and at most %s", ttl, MaxURLTTL)
+	}
+	return nil
+}
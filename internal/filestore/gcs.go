@@ -0,0 +1,110 @@
+package filestore
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// defaultGCSEndpoint is where the XML API of Google Cloud Storage is served
+const defaultGCSEndpoint = "https://storage.googleapis.com"
+
+// serviceAccountKey holds the fields of a service account key file used to sign URLs
+type serviceAccountKey struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+}
+
+// NewGCS creates a Store keeping files in a Google Cloud Storage bucket under prefix,
+// signing URLs with the key of a service account read from credentialsFile. The
+// service account needs permission to create and read objects of the bucket. Set
+// endpoint to use an emulator.
+func NewGCS(bucket, prefix, endpoint, credentialsFile string) (Store, error) {
+	if bucket == "" {
+		return nil, fmt.Errorf("filestore: GCS backend requires a bucket")
+	}
+	if credentialsFile == "" {
+		return nil, fmt.Errorf("filestore: GCS backend requires a service account key file")
+	}
+	data, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("filestore: failed to read service account key: %w", err)
+	}
+	var account serviceAccountKey
+	if err := json.Unmarshal(data, &account); err != nil || account.ClientEmail == "" {
+		return nil, fmt.Errorf("filestore: invalid service account key file %s", credentialsFile)
+	}
+	key, err := parsePrivateKey(account.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("filestore: invalid service account key file %s: %w", credentialsFile, err)
+	}
+
+	if endpoint == "" {
+		endpoint = defaultGCSEndpoint
+	}
+	base, err := parseEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bucketStore{
+		client: &http.Client{Timeout: 30 * time.Second},
+		objectURL: func(key string) *url.URL {
+			return &url.URL{Scheme: base.Scheme, Host: base.Host, Path: base.Path + "/" + bucket + "/" + key}
+		},
+		prefix: prefix,
+		signer: gcsSigner(account.ClientEmail, key),
+		now:    time.Now,
+	}, nil
+}
+
+// gcsSigner signs URLs with the RSA key of a service account
+func gcsSigner(email string, key *rsa.PrivateKey) signer {
+	return signer{
+		algorithm:  "GOOG4-RSA-SHA256",
+		param:      "X-Goog-",
+		credential: email,
+		region:     "auto",
+		service:    "storage",
+		request:    "goog4_request",
+		sign: func(date, stringToSign string) (string, error) {
+			hash := sha256.Sum256([]byte(stringToSign))
+			signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:])
+			if err != nil {
+				return "", err
+			}
+			return hex.EncodeToString(signature), nil
+		},
+	}
+}
+
+// parsePrivateKey parses a PEM encoded RSA key in PKCS #8 form, as service account key
+// files hold, or PKCS #1 form
+func parsePrivateKey(encoded string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(encoded))
+	if block == nil {
+		return nil, fmt.Errorf("no PEM encoded private key")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not an RSA key")
+	}
+	return key, nil
+}
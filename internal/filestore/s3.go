@@ -0,0 +1,75 @@
+package filestore
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// defaultS3Region is the region of S3 buckets when none is configured
+const defaultS3Region = "us-east-1"
+
+// NewS3 creates a Store keeping files in an S3 bucket under prefix, authenticating with
+// an access key. Buckets are addressed as virtual hosts of the regional AWS endpoint,
+// or by path under endpoint when set, as S3 compatible services such as MinIO expect.
+func NewS3(bucket, prefix, region, endpoint, accessKeyID, secretAccessKey string) (Store, error) {
+	if bucket == "" {
+		return nil, fmt.Errorf("filestore: S3 backend requires a bucket")
+	}
+	if accessKeyID == "" || secretAccessKey == "" {
+		return nil, fmt.Errorf("filestore: S3 backend requires an access key ID and secret access key")
+	}
+	if region == "" {
+		region = defaultS3Region
+	}
+
+	objectURL := func(key string) *url.URL {
+		return &url.URL{Scheme: "https", Host: bucket + ".s3." + region + ".amazonaws.com", Path: "/" + key}
+	}
+	if endpoint != "" {
+		base, err := parseEndpoint(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		objectURL = func(key string) *url.URL {
+			return &url.URL{Scheme: base.Scheme, Host: base.Host, Path: base.Path + "/" + bucket + "/" + key}
+		}
+	}
+
+	return &bucketStore{
+		client:    &http.Client{Timeout: 30 * time.Second},
+		objectURL: objectURL,
+		prefix:    prefix,
+		signer:    s3Signer(region, accessKeyID, secretAccessKey),
+		now:       time.Now,
+	}, nil
+}
+
+// s3Signer signs URLs with an AWS access key
+func s3Signer(region, accessKeyID, secretAccessKey string) signer {
+	return signer{
+		algorithm:  "AWS4-HMAC-SHA256",
+		param:      "X-Amz-",
+		credential: accessKeyID,
+		region:     region,
+		service:    "s3",
+		request:    "aws4_request",
+		sign: func(date, stringToSign string) (string, error) {
+			key := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+			for _, part := range []string{region, "s3", "aws4_request"} {
+				key = hmacSHA256(key, part)
+			}
+			return hex.EncodeToString(hmacSHA256(key, stringToSign)), nil
+		},
+	}
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
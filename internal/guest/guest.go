@@ -0,0 +1,85 @@
+// Package guest signs the tokens that give guest participants, people outside the
+// organization identified only by their email, access to the one meeting they were
+// invited to. Unlike RSVP links, tokens can be used until they expire, since guests
+// come back to change their availability.
+package guest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"meetsync/pkg/errors"
+)
+
+// purpose separates the signatures of guest tokens from other tokens signed with the
+// same secret, such as RSVP links
+const purpose = "guest:"
+
+// Access is what a guest token grants: a guest user may act on a meeting until it expires
+type Access struct {
+	MeetingID string    `json:"m"`
+	UserID    string    `json:"u"`
+	ExpiresAt time.Time `json:"e"`
+}
+
+// Signer signs and verifies guest tokens with an HMAC secret
+type Signer struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewSigner creates a Signer issuing tokens valid for ttl
+func NewSigner(secret []byte, ttl time.Duration) *Signer {
+	return &Signer{
+		secret: secret,
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+// Sign returns a token giving a guest user access to a meeting
+func (s *Signer) Sign(meetingID, userID string) string {
+	payload, _ := json.Marshal(Access{
+		MeetingID: meetingID,
+		UserID:    userID,
+		ExpiresAt: s.now().Add(s.ttl).UTC().Truncate(time.Second),
+	})
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.mac(encoded))
+}
+
+// Verify checks the signature and expiry of token and returns the access it grants
+func (s *Signer) Verify(token string) (Access, error) {
+	invalid := errors.NewUnauthorizedError("Invalid guest link")
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found {
+		return Access{}, invalid
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, s.mac(encoded)) {
+		return Access{}, invalid
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Access{}, invalid
+	}
+	var access Access
+	if err := json.Unmarshal(payload, &access); err != nil {
+		return Access{}, invalid
+	}
+	if !s.now().Before(access.ExpiresAt) {
+		return Access{}, errors.NewUnauthorizedError("Guest link has expired")
+	}
+	return access, nil
+}
+
+func (s *Signer) mac(encoded string) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(purpose + encoded))
+	return h.Sum(nil)
+}
@@ -0,0 +1,45 @@
+package guest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"meetsync/internal/rsvp"
+	"meetsync/pkg/errors"
+)
+
+func TestSigner(t *testing.T) {
+	now := time.Date(2024, 3, 14, 9, 0, 0, 0, time.UTC)
+	signer := NewSigner([]byte("secret"), 24*time.Hour)
+	signer.now = func() time.Time { return now }
+
+	token := signer.Sign("meeting-1", "guest-1")
+
+	// Tokens can be used more than once
+	for i := 0; i < 2; i++ {
+		access, err := signer.Verify(token)
+		require.NoError(t, err)
+		assert.Equal(t, "meeting-1", access.MeetingID)
+		assert.Equal(t, "guest-1", access.UserID)
+	}
+
+	// Tampered tokens and tokens signed with another secret are rejected
+	_, err := signer.Verify(token + "x")
+	assert.ErrorIs(t, err, errors.ErrUnauthorized)
+	_, err = NewSigner([]byte("other"), time.Hour).Verify(token)
+	assert.ErrorIs(t, err, errors.ErrUnauthorized)
+	_, err = signer.Verify("not-a-token")
+	assert.ErrorIs(t, err, errors.ErrUnauthorized)
+
+	// RSVP links signed with the same secret do not grant guest access
+	_, err = signer.Verify(rsvp.NewSigner([]byte("secret"), time.Hour).Sign("meeting-1", "guest-1", "slot-1"))
+	assert.ErrorIs(t, err, errors.ErrUnauthorized)
+
+	// Tokens expire
+	now = now.Add(24 * time.Hour)
+	_, err = signer.Verify(token)
+	assert.ErrorIs(t, err, errors.ErrUnauthorized)
+}
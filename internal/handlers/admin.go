@@ -0,0 +1,305 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"meetsync/internal/api"
+	"meetsync/internal/interfaces"
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+)
+
+const (
+	// maxUserImportSize caps the size of a user import CSV in bytes
+	maxUserImportSize = 5 << 20
+	// maxUserImportRows caps the number of users imported at once
+	maxUserImportRows = 10000
+)
+
+// AdminHandler handles administrative requests
+type AdminHandler struct {
+	service interfaces.AdminService
+	files   *FileLinks
+}
+
+// NewAdminHandler creates a new AdminHandler serving requests through service and
+// storing backups with files
+func NewAdminHandler(service interfaces.AdminService, files *FileLinks) *AdminHandler {
+	return &AdminHandler{service: service, files: files}
+}
+
+// MergeUsers handles merging a duplicate user into another user
+func (h *AdminHandler) MergeUsers(w http.ResponseWriter, r *http.Request) error {
+	var req api.MergeUsersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+
+	result, err := h.service.MergeUsers(req.SourceUserID, req.TargetUserID)
+	if err != nil {
+		return err
+	}
+
+	resp := api.MergeUsersResponse{
+		Result: result,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// ImportUsers handles creating users in bulk from a CSV body with a header row naming
+// its name and email columns
+func (h *AdminHandler) ImportUsers(w http.ResponseWriter, r *http.Request) error {
+	options := models.UserImportOptions{
+		OnDuplicate: models.DuplicateStrategy(r.URL.Query().Get("onDuplicate")),
+	}
+	if raw := r.URL.Query().Get("welcome"); raw != "" {
+		welcome, err := strconv.ParseBool(raw)
+		if err != nil {
+			return errors.NewValidationError("Invalid welcome flag", "use true or false")
+		}
+		options.Welcome = welcome
+	}
+
+	rows, err := parseUserImport(http.MaxBytesReader(w, r.Body, maxUserImportSize))
+	if err != nil {
+		return err
+	}
+
+	result, err := h.service.ImportUsers(rows, options)
+	if err != nil {
+		return err
+	}
+
+	resp := api.ImportUsersResponse{
+		Result: result,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// parseUserImport reads the rows of a user import CSV
+func parseUserImport(body io.Reader) ([]models.UserImportRow, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.NewValidationError("CSV is empty", "the first row must name the name and email columns")
+	}
+	if err != nil {
+		return nil, errors.NewValidationError("Invalid CSV", err.Error())
+	}
+	nameColumn, emailColumn := -1, -1
+	for i, column := range header {
+		switch strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))) {
+		case "name":
+			nameColumn = i
+		case "email":
+			emailColumn = i
+		}
+	}
+	if nameColumn < 0 || emailColumn < 0 {
+		return nil, errors.NewValidationError("CSV header must have name and email columns", "")
+	}
+
+	var rows []models.UserImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.NewValidationError("Invalid CSV", err.Error())
+		}
+		if len(rows) == maxUserImportRows {
+			return nil, errors.NewValidationError("Too many rows", fmt.Sprintf("import at most %d users at once", maxUserImportRows))
+		}
+		line, _ := reader.FieldPos(0)
+		row := models.UserImportRow{Line: line}
+		if nameColumn < len(record) {
+			row.Name = record[nameColumn]
+		}
+		if emailColumn < len(record) {
+			row.Email = record[emailColumn]
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// Bootstrap handles declaring the administrator of the environment and their access
+// tokens. Applying the same body again changes nothing, so provisioning tools can apply
+// it on every deploy.
+func (h *AdminHandler) Bootstrap(w http.ResponseWriter, r *http.Request) error {
+	var bootstrap models.Bootstrap
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&bootstrap); err != nil {
+		return errors.NewValidationError("Invalid bootstrap", err.Error())
+	}
+
+	result, err := h.service.Bootstrap(bootstrap)
+	if err != nil {
+		return err
+	}
+
+	resp := api.BootstrapResponse{
+		Result: result,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// SyncDirectory handles synchronizing users from the directory; with dryRun=true it
+// reports the changes without applying them
+func (h *AdminHandler) SyncDirectory(w http.ResponseWriter, r *http.Request) error {
+	dryRun := false
+	if raw := r.URL.Query().Get("dryRun"); raw != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(raw); err != nil {
+			return errors.NewValidationError("Invalid dryRun flag", "use true or false")
+		}
+	}
+
+	result, err := h.service.SyncDirectory(dryRun)
+	if err != nil {
+		return err
+	}
+
+	resp := api.SyncDirectoryResponse{
+		Result: result,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// SendDigests handles sending the digests that are due right away, without waiting for
+// the scheduled digest job
+func (h *AdminHandler) SendDigests(w http.ResponseWriter, r *http.Request) error {
+	result, err := h.service.SendDigests()
+	if err != nil {
+		return err
+	}
+
+	resp := api.SendDigestsResponse{
+		Result: result,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// ListAuditLog handles listing recorded administrative actions
+func (h *AdminHandler) ListAuditLog(w http.ResponseWriter, r *http.Request) error {
+	cursor, limit, err := pageParams(r)
+	if err != nil {
+		return err
+	}
+
+	entries, nextCursor, err := h.service.ListAuditLog(cursor, limit)
+	if err != nil {
+		return err
+	}
+
+	resp := api.ListAuditLogResponse{
+		Entries:    entries,
+		NextCursor: nextCursor,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// Backup handles downloading a backup of every store
+func (h *AdminHandler) Backup(w http.ResponseWriter, r *http.Request) error {
+	backup, err := h.service.Backup()
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="meetsync-backup-%s.json"`, backup.CreatedAt.UTC().Format("20060102T150405Z")))
+	if err := json.NewEncoder(w).Encode(backup); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// StoreBackup handles storing a backup of every store in file storage, responding with
+// a link to download it
+func (h *AdminHandler) StoreBackup(w http.ResponseWriter, r *http.Request) error {
+	backup, err := h.service.Backup()
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(backup)
+	if err != nil {
+		return errors.NewInternalError("Failed to encode backup", err)
+	}
+
+	key := fmt.Sprintf("backups/meetsync-backup-%s.json", backup.CreatedAt.UTC().Format("20060102T150405Z"))
+	return h.files.respond(w, r, http.StatusCreated, key, "application/json", body)
+}
+
+// VerifyBackup handles checking the integrity of a backup without restoring it
+func (h *AdminHandler) VerifyBackup(w http.ResponseWriter, r *http.Request) error {
+	return h.handleBackup(w, r, h.service.VerifyBackup)
+}
+
+// RestoreBackup handles replacing every store with the contents of a backup
+func (h *AdminHandler) RestoreBackup(w http.ResponseWriter, r *http.Request) error {
+	return h.handleBackup(w, r, h.service.RestoreBackup)
+}
+
+// handleBackup decodes a backup from the request body and responds with the summary returned by apply
+func (h *AdminHandler) handleBackup(w http.ResponseWriter, r *http.Request, apply func(models.Backup) (models.BackupSummary, error)) error {
+	var backup models.Backup
+	if err := json.NewDecoder(r.Body).Decode(&backup); err != nil {
+		return errors.NewValidationError("Invalid backup", err.Error())
+	}
+
+	summary, err := apply(backup)
+	if err != nil {
+		return err
+	}
+
+	resp := api.BackupSummaryResponse{
+		Summary: summary,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
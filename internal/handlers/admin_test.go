@@ -0,0 +1,313 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"meetsync/internal/api"
+	"meetsync/internal/interfaces"
+	"meetsync/internal/middleware"
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+)
+
+// MockAdminService is a mock implementation of AdminService
+type MockAdminService struct {
+	mock.Mock
+}
+
+var _ interfaces.AdminService = (*MockAdminService)(nil) // Verify MockAdminService implements AdminService interface
+
+func (m *MockAdminService) MergeUsers(sourceUserID string, targetUserID string) (models.UserReassignment, error) {
+	args := m.Called(sourceUserID, targetUserID)
+	return args.Get(0).(models.UserReassignment), args.Error(1)
+}
+
+func (m *MockAdminService) ImportUsers(rows []models.UserImportRow, options models.UserImportOptions) (models.UserImportResult, error) {
+	args := m.Called(rows, options)
+	return args.Get(0).(models.UserImportResult), args.Error(1)
+}
+
+func (m *MockAdminService) Bootstrap(bootstrap models.Bootstrap) (models.BootstrapResult, error) {
+	args := m.Called(bootstrap)
+	return args.Get(0).(models.BootstrapResult), args.Error(1)
+}
+
+func (m *MockAdminService) SyncDirectory(dryRun bool) (models.DirectorySyncResult, error) {
+	args := m.Called(dryRun)
+	return args.Get(0).(models.DirectorySyncResult), args.Error(1)
+}
+
+func (m *MockAdminService) SendDigests() (models.DigestResult, error) {
+	args := m.Called()
+	return args.Get(0).(models.DigestResult), args.Error(1)
+}
+
+func (m *MockAdminService) ListAuditLog(cursor string, limit int) ([]models.AuditEntry, string, error) {
+	args := m.Called(cursor, limit)
+	return args.Get(0).([]models.AuditEntry), args.String(1), args.Error(2)
+}
+
+func (m *MockAdminService) Backup() (models.Backup, error) {
+	args := m.Called()
+	return args.Get(0).(models.Backup), args.Error(1)
+}
+
+func (m *MockAdminService) VerifyBackup(backup models.Backup) (models.BackupSummary, error) {
+	args := m.Called(backup)
+	return args.Get(0).(models.BackupSummary), args.Error(1)
+}
+
+func (m *MockAdminService) RestoreBackup(backup models.Backup) (models.BackupSummary, error) {
+	args := m.Called(backup)
+	return args.Get(0).(models.BackupSummary), args.Error(1)
+}
+
+func TestMergeUsers(t *testing.T) {
+	tests := []struct {
+		name           string
+		adminKey       string
+		requestKey     string
+		request        api.MergeUsersRequest
+		setupMock      func(*MockAdminService)
+		expectedStatus int
+		expectedError  bool
+	}{
+		{
+			name:       "successful merge",
+			adminKey:   "secret",
+			requestKey: "secret",
+			request:    api.MergeUsersRequest{SourceUserID: "dup", TargetUserID: "jane"},
+			setupMock: func(m *MockAdminService) {
+				m.On("MergeUsers", "dup", "jane").Return(models.UserReassignment{SourceUserID: "dup", TargetUserID: "jane", Participations: 2}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:       "target not found",
+			adminKey:   "secret",
+			requestKey: "secret",
+			request:    api.MergeUsersRequest{SourceUserID: "dup", TargetUserID: "missing"},
+			setupMock: func(m *MockAdminService) {
+				m.On("MergeUsers", "dup", "missing").Return(models.UserReassignment{}, errors.NewNotFoundError("Target user not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedError:  true,
+		},
+		{
+			name:           "wrong admin key",
+			adminKey:       "secret",
+			requestKey:     "guess",
+			request:        api.MergeUsersRequest{SourceUserID: "dup", TargetUserID: "jane"},
+			setupMock:      func(m *MockAdminService) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedError:  true,
+		},
+		{
+			name:           "admin API disabled",
+			adminKey:       "",
+			requestKey:     "",
+			request:        api.MergeUsersRequest{SourceUserID: "dup", TargetUserID: "jane"},
+			setupMock:      func(m *MockAdminService) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAdminService)
+			tt.setupMock(mockService)
+			handler := &AdminHandler{service: mockService}
+
+			body, _ := json.Marshal(tt.request)
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/users/merge", bytes.NewBuffer(body))
+			req.Header.Set(middleware.AdminKeyHeader, tt.requestKey)
+			w := httptest.NewRecorder()
+
+			err := middleware.RequireAdminKey(tt.adminKey, handler.MergeUsers)(w, req)
+
+			if tt.expectedError {
+				assert.Error(t, err)
+				if appErr, ok := err.(*errors.AppError); ok {
+					assert.Equal(t, tt.expectedStatus, appErr.HTTPStatusCode())
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, w.Code)
+
+				var resp api.MergeUsersResponse
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, 2, resp.Result.Participations)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestImportUsers(t *testing.T) {
+	mockService := new(MockAdminService)
+	rows := []models.UserImportRow{
+		{Line: 2, Name: "Jane Doe", Email: "jane@example.com"},
+		{Line: 4, Name: "John Roe", Email: "john@example.com"},
+		{Line: 5, Name: "No Email"},
+	}
+	options := models.UserImportOptions{OnDuplicate: models.DuplicateUpdate, Welcome: true}
+	mockService.On("ImportUsers", rows, options).Return(models.UserImportResult{Created: 2, Failed: 1}, nil)
+	handler := &AdminHandler{service: mockService}
+
+	body := "\ufeffEmail,Team,Name\njane@example.com,Sales,Jane Doe\n\njohn@example.com, Support, \"John Roe\"\n,,No Email\n"
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/users/import?onDuplicate=update&welcome=true", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	assert.NoError(t, handler.ImportUsers(w, req))
+
+	var resp api.ImportUsersResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, 2, resp.Result.Created)
+	assert.Equal(t, 1, resp.Result.Failed)
+
+	// Malformed files are rejected before reaching the service
+	for name, tt := range map[string]struct{ query, body string }{
+		"empty":          {"", ""},
+		"missing column": {"", "name\nJane\n"},
+		"bad quoting":    {"", "name,email\n\"Jane,jane@example.com\n"},
+		"bad welcome":    {"?welcome=maybe", "name,email\n"},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/api/admin/users/import"+tt.query, strings.NewReader(tt.body))
+		err := handler.ImportUsers(httptest.NewRecorder(), req)
+		if assert.Error(t, err, name) {
+			assert.Equal(t, http.StatusBadRequest, err.(*errors.AppError).HTTPStatusCode(), name)
+		}
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestBootstrap(t *testing.T) {
+	mockService := new(MockAdminService)
+	bootstrap := models.Bootstrap{
+		Admin:  models.BootstrapAdmin{Name: "Ops", Email: "ops@example.com"},
+		Tokens: []models.BootstrapToken{{Name: "deploy", Scopes: []models.Scope{models.ScopeReadMeetings}}},
+	}
+	mockService.On("Bootstrap", bootstrap).Return(models.BootstrapResult{
+		Admin:       models.User{ID: "admin-id"},
+		AdminStatus: models.BootstrapCreated,
+		Tokens:      []models.BootstrapTokenResult{{Status: models.BootstrapCreated, Secret: "msp_generated"}},
+	}, nil)
+	handler := &AdminHandler{service: mockService}
+
+	body, _ := json.Marshal(bootstrap)
+	req := httptest.NewRequest(http.MethodPut, "/api/admin/bootstrap", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	assert.NoError(t, handler.Bootstrap(w, req))
+
+	var resp api.BootstrapResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, models.BootstrapCreated, resp.Result.AdminStatus)
+	assert.Equal(t, "msp_generated", resp.Result.Tokens[0].Secret)
+
+	// Unknown fields are rejected rather than silently declaring nothing
+	req = httptest.NewRequest(http.MethodPut, "/api/admin/bootstrap", strings.NewReader(`{"admin":{"email":"ops@example.com"},"org":"Acme"}`))
+	err := handler.Bootstrap(httptest.NewRecorder(), req)
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusBadRequest, err.(*errors.AppError).HTTPStatusCode())
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestStoreBackup(t *testing.T) {
+	mockService := new(MockAdminService)
+	mockService.On("Backup").Return(models.Backup{Version: 1, CreatedAt: time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)}, nil)
+	handler := &AdminHandler{service: mockService}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/backups", nil)
+	err := handler.StoreBackup(httptest.NewRecorder(), req)
+	if assert.Error(t, err, "backups cannot be stored without file storage") {
+		assert.Equal(t, http.StatusNotFound, err.(*errors.AppError).HTTPStatusCode())
+	}
+
+	files := memoryFileStore{}
+	handler.files = NewFileLinks(files, time.Hour)
+	w := httptest.NewRecorder()
+	assert.NoError(t, handler.StoreBackup(w, req))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp api.DownloadLinkResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, "https://files.example.com/backups/meetsync-backup-20260302T093000Z.json", resp.URL)
+	assert.Contains(t, files["backups/meetsync-backup-20260302T093000Z.json"], `"version":1`)
+}
+
+func TestSyncDirectory(t *testing.T) {
+	mockService := new(MockAdminService)
+	mockService.On("SyncDirectory", true).Return(models.DirectorySyncResult{DryRun: true, Created: 2}, nil)
+	handler := &AdminHandler{service: mockService}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/directory/sync?dryRun=true", nil)
+	w := httptest.NewRecorder()
+	assert.NoError(t, handler.SyncDirectory(w, req))
+
+	var resp api.SyncDirectoryResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.True(t, resp.Result.DryRun)
+	assert.Equal(t, 2, resp.Result.Created)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/admin/directory/sync?dryRun=maybe", nil)
+	err := handler.SyncDirectory(httptest.NewRecorder(), req)
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusBadRequest, err.(*errors.AppError).HTTPStatusCode())
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestSendDigests(t *testing.T) {
+	mockService := new(MockAdminService)
+	mockService.On("SendDigests").Return(models.DigestResult{Sent: 3, Empty: 1}, nil)
+	handler := &AdminHandler{service: mockService}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/digests", nil)
+	w := httptest.NewRecorder()
+	assert.NoError(t, handler.SendDigests(w, req))
+
+	var resp api.SendDigestsResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, models.DigestResult{Sent: 3, Empty: 1}, resp.Result)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestListAuditLog(t *testing.T) {
+	mockService := new(MockAdminService)
+	mockService.On("ListAuditLog", "abc", 10).Return([]models.AuditEntry{
+		{ID: "entry-1", Action: models.AuditActionUsersMerged, SubjectID: "jane"},
+	}, "next", nil)
+	handler := &AdminHandler{service: mockService}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/audit?cursor=abc&limit=10", nil)
+	w := httptest.NewRecorder()
+
+	err := handler.ListAuditLog(w, req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp api.ListAuditLogResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Len(t, resp.Entries, 1)
+	assert.Equal(t, "next", resp.NextCursor)
+
+	// Invalid limits are rejected before reaching the service
+	req = httptest.NewRequest(http.MethodGet, "/api/admin/audit?limit=-1", nil)
+	assert.Error(t, handler.ListAuditLog(httptest.NewRecorder(), req))
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"meetsync/internal/api"
+	"meetsync/internal/interfaces"
+	"meetsync/internal/jobs"
+	"meetsync/pkg/errors"
+	"meetsync/pkg/logs"
+)
+
+const (
+	// maxBatchOperations caps the number of operations in a batch request
+	maxBatchOperations = 1000
+	// asyncBatchThreshold is the largest batch applied within the request; larger
+	// batches are processed as a background job
+	asyncBatchThreshold = 25
+	// jobKindMeetingBatch identifies jobs applying a batch of meeting operations
+	jobKindMeetingBatch = "meeting_batch"
+)
+
+// Batch operations on meetings
+const (
+	batchOpDelete    = "delete"
+	batchOpPublish   = "publish"
+	batchOpAddTags   = "addTags"
+	batchOpRemoveTag = "removeTag"
+)
+
+// BatchHandler handles requests applying many meeting operations at once, such as mass
+// cancellations, and polling the jobs processing large batches
+type BatchHandler struct {
+	service interfaces.MeetingService
+	jobs    *jobs.Queue
+}
+
+// NewBatchHandler creates a new BatchHandler applying operations through the meeting
+// service and processing large batches on queue
+func NewBatchHandler(service interfaces.MeetingService, queue *jobs.Queue) *BatchHandler {
+	return &BatchHandler{
+		service: service,
+		jobs:    queue,
+	}
+}
+
+// DeleteMeetings handles deleting the meetings listed in the ids query parameter, as a
+// comma-separated list or repeated parameters
+func (h *BatchHandler) DeleteMeetings(w http.ResponseWriter, r *http.Request) error {
+	var operations []api.BatchOperation
+	for _, value := range r.URL.Query()["ids"] {
+		for _, id := range strings.Split(value, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				operations = append(operations, api.BatchOperation{Op: batchOpDelete, MeetingID: id})
+			}
+		}
+	}
+	return h.process(w, callerID(r), operations)
+}
+
+// ApplyBatch handles applying a list of operations on meetings. Operations are applied
+// in order and each one succeeds or fails on its own.
+func (h *BatchHandler) ApplyBatch(w http.ResponseWriter, r *http.Request) error {
+	var req api.BatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+	return h.process(w, callerID(r), req.Operations)
+}
+
+// GetJob handles getting the status of a background job, with its result once it finished
+func (h *BatchHandler) GetJob(w http.ResponseWriter, r *http.Request) error {
+	job, err := h.jobs.Get(r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(api.JobResponse{Job: job}); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// process applies small batches right away and answers with their results. Larger
+// batches are queued as a job and answered with 202 Accepted and the job to poll.
+// Operations of requests authenticated with a personal access token only apply to
+// meetings its owner, callerID, organizes.
+func (h *BatchHandler) process(w http.ResponseWriter, callerID string, operations []api.BatchOperation) error {
+	if len(operations) == 0 {
+		return errors.NewValidationError("At least one operation is required", "")
+	}
+	if len(operations) > maxBatchOperations {
+		return errors.NewValidationError("Too many operations", fmt.Sprintf("at most %d operations can be applied at once", maxBatchOperations))
+	}
+
+	if len(operations) <= asyncBatchThreshold {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(h.apply(callerID, operations)); err != nil {
+			return errors.NewInternalError("Failed to encode response", err)
+		}
+		return nil
+	}
+
+	job, err := h.jobs.Submit(jobKindMeetingBatch, func() (any, error) {
+		return h.apply(callerID, operations), nil
+	})
+	if err != nil {
+		return err
+	}
+	logs.Info("Queued batch of %d meeting operations as job %s", len(operations), job.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/api/jobs/"+job.ID)
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(api.JobResponse{Job: job}); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// apply applies operations in order, reporting the outcome of each one
+func (h *BatchHandler) apply(callerID string, operations []api.BatchOperation) api.BatchResponse {
+	resp := api.BatchResponse{
+		Results: make([]api.BatchOperationResult, 0, len(operations)),
+	}
+	for i, operation := range operations {
+		result := api.BatchOperationResult{Index: i, Op: operation.Op, MeetingID: operation.MeetingID}
+
+		status, err := h.applyOne(callerID, operation)
+		if err != nil {
+			appErr, ok := errors.AsAppError(err)
+			if !ok {
+				appErr = errors.NewInternalError("Internal server error", err)
+			}
+			result.Status = appErr.HTTPStatusCode()
+			result.ErrorType = string(appErr.Type)
+			result.Error = appErr.Message
+			resp.Failed++
+		} else {
+			result.Status = status
+			resp.Succeeded++
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	logs.Info("Applied batch of %d meeting operations: %d succeeded, %d failed", len(operations), resp.Succeeded, resp.Failed)
+	return resp
+}
+
+// applyOne applies a single operation and returns the status it answers with on its own
+func (h *BatchHandler) applyOne(callerID string, operation api.BatchOperation) (int, error) {
+	if operation.MeetingID == "" {
+		return 0, errors.NewValidationError("Meeting ID is required", "")
+	}
+	if callerID != "" {
+		if err := h.service.AuthorizeMeetingChange(callerID, operation.MeetingID); err != nil {
+			return 0, err
+		}
+	}
+
+	switch operation.Op {
+	case batchOpDelete:
+		return http.StatusNoContent, h.service.DeleteMeeting(operation.MeetingID)
+	case batchOpPublish:
+		_, err := h.service.PublishMeeting(operation.MeetingID)
+		return http.StatusOK, err
+	case batchOpAddTags:
+		_, err := h.service.AddMeetingTags(operation.MeetingID, operation.Tags)
+		return http.StatusOK, err
+	case batchOpRemoveTag:
+		if len(operation.Tags) != 1 {
+			return 0, errors.NewValidationError("Exactly one tag is required", "")
+		}
+		_, err := h.service.RemoveMeetingTag(operation.MeetingID, operation.Tags[0])
+		return http.StatusOK, err
+	default:
+		return 0, errors.NewValidationError("Unknown operation", "use delete, publish, addTags or removeTag")
+	}
+}
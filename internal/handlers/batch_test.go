@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"meetsync/internal/api"
+	"meetsync/internal/jobs"
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+)
+
+func TestDeleteMeetings(t *testing.T) {
+	mockService := new(MockMeetingService)
+	mockService.On("DeleteMeeting", "m1").Return(nil)
+	mockService.On("DeleteMeeting", "m2").Return(errors.NewNotFoundError("Meeting not found"))
+	mockService.On("DeleteMeeting", "m3").Return(nil)
+	queue := jobs.NewQueue(nil)
+	defer queue.Close()
+	handler := &BatchHandler{service: mockService, jobs: queue}
+
+	// Missing meetings are reported per result instead of failing the batch
+	req := httptest.NewRequest(http.MethodDelete, "/api/meetings?ids=m1,m2&ids=m3", nil)
+	w := httptest.NewRecorder()
+	require.NoError(t, handler.DeleteMeetings(w, req))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp api.BatchResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, 2, resp.Succeeded)
+	assert.Equal(t, 1, resp.Failed)
+	require.Len(t, resp.Results, 3)
+	assert.Equal(t, http.StatusNoContent, resp.Results[0].Status)
+	assert.Equal(t, http.StatusNotFound, resp.Results[1].Status)
+	assert.Equal(t, string(errors.ErrorTypeNotFound), resp.Results[1].ErrorType)
+	assert.Equal(t, "m3", resp.Results[2].MeetingID)
+
+	// At least one ID is required
+	err := handler.DeleteMeetings(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/api/meetings?ids=", nil))
+	assert.Error(t, err)
+}
+
+func TestApplyBatch(t *testing.T) {
+	mockService := new(MockMeetingService)
+	mockService.On("PublishMeeting", "m1").Return(models.Meeting{ID: "m1"}, nil)
+	mockService.On("AddMeetingTags", "m2", []string{"reorg"}).Return(models.Meeting{ID: "m2"}, nil)
+	queue := jobs.NewQueue(nil)
+	defer queue.Close()
+	handler := &BatchHandler{service: mockService, jobs: queue}
+
+	body := `{"operations":[
+		{"op":"publish","meetingId":"m1"},
+		{"op":"addTags","meetingId":"m2","tags":["reorg"]},
+		{"op":"removeTag","meetingId":"m2"},
+		{"op":"archive","meetingId":"m3"},
+		{"op":"delete"}
+	]}`
+	w := httptest.NewRecorder()
+	require.NoError(t, handler.ApplyBatch(w, httptest.NewRequest(http.MethodPost, "/api/meetings/batch", strings.NewReader(body))))
+
+	var resp api.BatchResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, 2, resp.Succeeded)
+	assert.Equal(t, 3, resp.Failed)
+	for _, result := range resp.Results[2:] {
+		assert.Equal(t, http.StatusBadRequest, result.Status)
+	}
+
+	// Invalid bodies fail the whole request
+	err := handler.ApplyBatch(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/meetings/batch", strings.NewReader("not json")))
+	assert.Error(t, err)
+}
+
+func TestApplyBatchAsync(t *testing.T) {
+	mockService := new(MockMeetingService)
+	operations := make([]api.BatchOperation, asyncBatchThreshold+1)
+	for i := range operations {
+		id := fmt.Sprintf("m%d", i)
+		operations[i] = api.BatchOperation{Op: batchOpDelete, MeetingID: id}
+		mockService.On("DeleteMeeting", id).Return(nil)
+	}
+	queue := jobs.NewQueue(nil)
+	defer queue.Close()
+	handler := &BatchHandler{service: mockService, jobs: queue}
+
+	// Large batches are accepted as a job
+	body, _ := json.Marshal(api.BatchRequest{Operations: operations})
+	w := httptest.NewRecorder()
+	require.NoError(t, handler.ApplyBatch(w, httptest.NewRequest(http.MethodPost, "/api/meetings/batch", bytes.NewReader(body))))
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	var accepted api.JobResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&accepted))
+	assert.Equal(t, "/api/jobs/"+accepted.Job.ID, w.Header().Get("Location"))
+
+	// The job reports the results once it finished
+	var polled struct {
+		Job struct {
+			Status models.JobStatus  `json:"status"`
+			Result api.BatchResponse `json:"result"`
+		} `json:"job"`
+	}
+	require.Eventually(t, func() bool {
+		req := httptest.NewRequest(http.MethodGet, "/api/jobs/"+accepted.Job.ID, nil)
+		req.SetPathValue("id", accepted.Job.ID)
+		w := httptest.NewRecorder()
+		if err := handler.GetJob(w, req); err != nil {
+			return false
+		}
+		return json.NewDecoder(w.Body).Decode(&polled) == nil && polled.Job.Status == models.JobStatusSucceeded
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, len(operations), polled.Job.Result.Succeeded)
+
+	// Unknown jobs are not found
+	req := httptest.NewRequest(http.MethodGet, "/api/jobs/unknown", nil)
+	req.SetPathValue("id", "unknown")
+	err := handler.GetJob(httptest.NewRecorder(), req)
+	appErr, ok := errors.AsAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, appErr.HTTPStatusCode())
+}
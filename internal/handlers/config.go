@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"meetsync/internal/api"
+	"meetsync/internal/config"
+	"meetsync/pkg/errors"
+)
+
+// ConfigHandler handles inspecting the effective configuration
+type ConfigHandler struct {
+	settings []config.Setting
+}
+
+// NewConfigHandler creates a new ConfigHandler for resolved settings with secrets masked
+func NewConfigHandler(settings []config.Setting) *ConfigHandler {
+	return &ConfigHandler{
+		settings: settings,
+	}
+}
+
+// GetConfig handles listing the effective configuration
+func (h *ConfigHandler) GetConfig(w http.ResponseWriter, r *http.Request) error {
+	settings := h.settings
+	if settings == nil {
+		settings = []config.Setting{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(api.GetConfigResponse{Settings: settings}); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
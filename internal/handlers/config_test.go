@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"meetsync/internal/api"
+	"meetsync/internal/config"
+	"meetsync/internal/middleware"
+)
+
+func TestGetConfig(t *testing.T) {
+	settings := []config.Setting{
+		{Name: "SERVER_PORT", Value: "8080", Source: config.SourceDefault},
+		{Name: "ADMIN_API_KEY", Value: "********", Source: config.SourceEnv},
+	}
+	handler := NewConfigHandler(settings)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/config", nil)
+	req.Header.Set(middleware.AdminKeyHeader, "secret")
+	w := httptest.NewRecorder()
+
+	err := middleware.RequireAdminKey("secret", handler.GetConfig)(w, req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp api.GetConfigResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, settings, resp.Settings)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/admin/config", nil)
+	err = middleware.RequireAdminKey("secret", handler.GetConfig)(httptest.NewRecorder(), req)
+	assert.Error(t, err)
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+
+	"meetsync/internal/api"
+	"meetsync/internal/ical"
+	"meetsync/internal/models"
+	"meetsync/internal/spreadsheet"
+	"meetsync/pkg/errors"
+)
+
+// exportTimeLayout formats slot times in exported spreadsheets
+const exportTimeLayout = "2006-01-02 15:04"
+
+// availabilityMarkLabels are the labels of availability marks in exported spreadsheets
+var availabilityMarkLabels = map[models.AvailabilityMark]string{
+	models.AvailabilityMarkAvailable:   "yes",
+	models.AvailabilityMarkTentative:   "tentative",
+	models.AvailabilityMarkUnavailable: "no",
+	models.AvailabilityMarkNoResponse:  "",
+}
+
+// exportRecommendations writes the recommended slots of a meeting against its participants
+// as a spreadsheet in format, to share with people who do not use the service
+func (h *MeetingHandler) exportRecommendations(w http.ResponseWriter, meetingID, format string) error {
+	filename, file, err := h.renderRecommendations(meetingID, format)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", spreadsheet.ContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	_, _ = w.Write(file)
+	return nil
+}
+
+// ExportRecommendations handles storing a spreadsheet of the recommended slots of a
+// meeting in file storage, responding with a link to download it, for clients that
+// hand the download over to a browser or another service
+func (h *MeetingHandler) ExportRecommendations(w http.ResponseWriter, r *http.Request) error {
+	var req api.ExportRecommendationsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+	if req.MeetingID == "" {
+		return errors.NewValidationError("Meeting ID is required", "")
+	}
+	if req.Format != spreadsheet.FormatCSV && req.Format != spreadsheet.FormatXLSX {
+		return errors.NewValidationError("Invalid format", "format must be csv or xlsx")
+	}
+
+	filename, file, err := h.renderRecommendations(req.MeetingID, req.Format)
+	if err != nil {
+		return err
+	}
+	// A random element keeps links to earlier exports from being guessed or overwritten
+	key := path.Join("exports", req.MeetingID, uuid.New().String(), filename)
+	return h.files.respond(w, r, http.StatusCreated, key, spreadsheet.ContentType(req.Format), file)
+}
+
+// renderRecommendations renders the spreadsheet of the recommended slots of a meeting in
+// format and returns it with its file name
+func (h *MeetingHandler) renderRecommendations(meetingID, format string) (string, []byte, error) {
+	grid, err := h.service.GetAvailabilityGrid(meetingID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	file, err := spreadsheet.Render(availabilityTable(grid), format)
+	if err != nil {
+		return "", nil, errors.NewInternalError("Failed to render spreadsheet", err)
+	}
+	return fmt.Sprintf("recommendations-%s.%s", grid.MeetingID, format), file, nil
+}
+
+// ExportCalendarEvent handles downloading a meeting as an iCalendar file to import into
+// calendar applications
+func (h *MeetingHandler) ExportCalendarEvent(w http.ResponseWriter, r *http.Request) error {
+	meetingID := r.PathValue("id")
+	if meetingID == "" {
+		return errors.NewValidationError("Meeting ID is required", "")
+	}
+
+	event, err := h.service.GetCalendarEvent(meetingID)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", ical.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="meeting-%s.ics"`, event.MeetingID))
+	_, _ = w.Write(ical.Render(event, time.Now()))
+	return nil
+}
+
+// availabilityTable lays out an availability grid with a row per recommended slot, in
+// the meeting's time zone, and a column per participant
+func availabilityTable(grid models.AvailabilityGrid) spreadsheet.Table {
+	location, err := time.LoadLocation(grid.TimeZone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	header := []any{
+		"Rank",
+		fmt.Sprintf("Start (%s)", location),
+		fmt.Sprintf("End (%s)", location),
+		"Available",
+		"Tentative",
+		"Participants",
+	}
+	for _, participant := range grid.Participants {
+		header = append(header, displayName(participant))
+	}
+
+	rows := [][]any{header}
+	for i, row := range grid.Rows {
+		cells := []any{
+			i + 1,
+			row.Slot.TimeSlot.StartTime.In(location).Format(exportTimeLayout),
+			row.Slot.TimeSlot.EndTime.In(location).Format(exportTimeLayout),
+			row.Slot.AvailableCount,
+			row.Slot.TentativeCount,
+			row.Slot.TotalParticipants,
+		}
+		for _, mark := range row.Marks {
+			cells = append(cells, availabilityMarkLabels[mark])
+		}
+		rows = append(rows, cells)
+	}
+	return spreadsheet.Table{Name: grid.Title, Rows: rows}
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"meetsync/internal/api"
+	"meetsync/internal/middleware"
+	"meetsync/pkg/errors"
+)
+
+// FaultHandler handles inspecting and changing the faults injected into requests
+type FaultHandler struct {
+	injector *middleware.FaultInjector
+}
+
+// NewFaultHandler creates a new FaultHandler controlling injector
+func NewFaultHandler(injector *middleware.FaultInjector) *FaultHandler {
+	return &FaultHandler{injector: injector}
+}
+
+// GetFaults handles listing the fault injection rules in effect
+func (h *FaultHandler) GetFaults(w http.ResponseWriter, r *http.Request) error {
+	return h.writeFaults(w)
+}
+
+// SetFaults handles replacing the fault injection rules
+func (h *FaultHandler) SetFaults(w http.ResponseWriter, r *http.Request) error {
+	var req api.SetFaultsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+	if err := h.injector.SetRules(req.Rules); err != nil {
+		return err
+	}
+	return h.writeFaults(w)
+}
+
+// ClearFaults handles removing every fault injection rule
+func (h *FaultHandler) ClearFaults(w http.ResponseWriter, r *http.Request) error {
+	if err := h.injector.SetRules(nil); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (h *FaultHandler) writeFaults(w http.ResponseWriter) error {
+	resp := api.FaultsResponse{
+		Enabled: h.injector.Enabled(),
+		Rules:   h.injector.Rules(),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
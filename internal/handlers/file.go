@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"time"
+
+	"meetsync/internal/api"
+	"meetsync/internal/filestore"
+	"meetsync/pkg/errors"
+)
+
+// FileLinks stores files such as exports and backups in a file store and responds with
+// signed URLs clients download them from directly. A nil FileLinks, or one without a
+// store, reports file storage as not configured.
+type FileLinks struct {
+	store filestore.Store
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+// NewFileLinks creates FileLinks storing files in store, whose URLs stay valid for ttl
+func NewFileLinks(store filestore.Store, ttl time.Duration) *FileLinks {
+	return &FileLinks{store: store, ttl: ttl, now: time.Now}
+}
+
+// respond stores body under key and responds with status and a link downloading it
+func (l *FileLinks) respond(w http.ResponseWriter, r *http.Request, status int, key, contentType string, body []byte) error {
+	if l == nil || l.store == nil {
+		return errors.NewNotFoundError("File storage is not configured")
+	}
+
+	expiresAt := l.now().Add(l.ttl).UTC().Truncate(time.Second)
+	if err := l.store.Put(r.Context(), key, contentType, body); err != nil {
+		return errors.NewDependencyError("Failed to store file", err)
+	}
+	url, err := l.store.SignedURL(key, l.ttl)
+	if err != nil {
+		return errors.NewInternalError("Failed to sign download link", err)
+	}
+
+	resp := api.DownloadLinkResponse{
+		URL:       url,
+		ExpiresAt: expiresAt,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// FileHandler serves the files of a local file store from the download URLs it signs
+type FileHandler struct {
+	store *filestore.Local
+}
+
+// NewFileHandler creates a new FileHandler serving the files of store
+func NewFileHandler(store *filestore.Local) *FileHandler {
+	return &FileHandler{store: store}
+}
+
+// DownloadFile handles downloading a stored file from a signed URL
+func (h *FileHandler) DownloadFile(w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+	key := query.Get("key")
+	file, err := h.store.Open(key, query.Get("expires"), query.Get("signature"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return errors.NewInternalError("Failed to read file", err)
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, path.Base(key)))
+	w.Header().Set("Cache-Control", "private, no-store")
+	http.ServeContent(w, r, "", info.ModTime(), file)
+	return nil
+}
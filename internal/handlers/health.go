@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"time"
+
+	"meetsync/internal/api"
+	"meetsync/internal/health"
+	"meetsync/internal/version"
+	"meetsync/pkg/errors"
+)
+
+// HealthHandler handles liveness and readiness probes
+type HealthHandler struct {
+	stateCheck health.StateCheck
+	storage    *health.StorageMonitor
+	started    time.Time
+	now        func() time.Time
+}
+
+// NewHealthHandler creates a new HealthHandler reporting the startup storage check and
+// whether storage is currently reachable
+func NewHealthHandler(stateCheck health.StateCheck, storage *health.StorageMonitor) *HealthHandler {
+	return &HealthHandler{
+		stateCheck: stateCheck,
+		storage:    storage,
+		started:    time.Now(),
+		now:        time.Now,
+	}
+}
+
+// Healthz handles the liveness probe. While storage is unreachable the process reports
+// itself "degraded" rather than failing the probe, since restarting it would not
+// bring storage back.
+func (h *HealthHandler) Healthz(w http.ResponseWriter, r *http.Request) error {
+	resp := api.HealthResponse{
+		Status:  "ok",
+		Storage: h.storage.Status(),
+	}
+	if !resp.Storage.Available {
+		resp.Status = "degraded"
+	}
+	return writeHealth(w, resp)
+}
+
+// Readyz handles the readiness probe. An instance whose storage does not fit the
+// deployment or is unreachable still reports itself ready, as "degraded", since taking
+// every replica out of the load balancer would not help; the details say what is wrong.
+func (h *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) error {
+	resp := api.ReadinessResponse{
+		Status:  "ready",
+		State:   h.stateCheck,
+		Storage: h.storage.Status(),
+	}
+	if !h.stateCheck.OK || !resp.Storage.Available {
+		resp.Status = "degraded"
+	}
+	return writeHealth(w, resp)
+}
+
+// Status handles the public status page: the version running, how long it has been up
+// and whether its dependencies are healthy, without the details of what is wrong
+func (h *HealthHandler) Status(w http.ResponseWriter, r *http.Request) error {
+	now := h.now()
+	build := version.Get()
+	storageUp := h.storage.Available()
+	resp := api.StatusResponse{
+		Status:        "operational",
+		Version:       build.Version,
+		Commit:        build.Commit,
+		StartedAt:     h.started.UTC(),
+		UptimeSeconds: int64(now.Sub(h.started).Seconds()),
+		Storage:       h.stateCheck.Backend,
+		Dependencies: []api.DependencyStatus{
+			{
+				Name:         "storage",
+				Status:       "up",
+				Availability: math.Round(h.storage.Availability(now)*10000) / 100,
+			},
+		},
+	}
+	if !storageUp {
+		resp.Dependencies[0].Status = "down"
+	}
+	if !h.stateCheck.OK || !storageUp {
+		resp.Status = "degraded"
+	}
+	return writeHealth(w, resp)
+}
+
+// Version handles reporting the version, commit and build date of the service
+func (h *HealthHandler) Version(w http.ResponseWriter, r *http.Request) error {
+	build := version.Get()
+	return writeHealth(w, api.VersionResponse{
+		Version:   build.Version,
+		Commit:    build.Commit,
+		BuildDate: build.BuildDate,
+	})
+}
+
+func writeHealth(w http.ResponseWriter, resp interface{}) error {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
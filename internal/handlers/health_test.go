@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"meetsync/internal/api"
+	"meetsync/internal/health"
+	"meetsync/internal/version"
+)
+
+func TestReadyz(t *testing.T) {
+	tests := []struct {
+		name       string
+		replicas   int
+		wantStatus string
+	}{
+		{name: "single replica", replicas: 1, wantStatus: "ready"},
+		{name: "several replicas on in-memory storage", replicas: 3, wantStatus: "degraded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHealthHandler(health.CheckState(health.StorageMemory, false, tt.replicas, health.ProcessLocal), health.NewStorageMonitor())
+
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			w := httptest.NewRecorder()
+
+			err := handler.Readyz(w, req)
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var resp api.ReadinessResponse
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			assert.Equal(t, tt.wantStatus, resp.Status)
+			assert.Equal(t, tt.replicas, resp.State.Replicas)
+			assert.Equal(t, health.StorageMemory, resp.State.Backend)
+		})
+	}
+}
+
+func TestHealthz(t *testing.T) {
+	storage := health.NewStorageMonitor()
+	handler := NewHealthHandler(health.CheckState(health.StorageMemory, false, 1, health.ProcessLocal), storage)
+
+	get := func() api.HealthResponse {
+		req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+		w := httptest.NewRecorder()
+		assert.NoError(t, handler.Healthz(w, req))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp api.HealthResponse
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		return resp
+	}
+
+	resp := get()
+	assert.Equal(t, "ok", resp.Status)
+	assert.True(t, resp.Storage.Available)
+
+	storage.MarkUnavailable(fmt.Errorf("connection refused"), time.Now())
+	resp = get()
+	assert.Equal(t, "degraded", resp.Status)
+	assert.False(t, resp.Storage.Available)
+	assert.Equal(t, "connection refused", resp.Storage.LastError)
+}
+
+func TestStatus(t *testing.T) {
+	storage := health.NewStorageMonitor()
+	handler := NewHealthHandler(health.CheckState("postgres", true, 3, nil), storage)
+	now := handler.started.Add(time.Hour)
+	handler.now = func() time.Time { return now }
+
+	get := func() (api.StatusResponse, string) {
+		req := httptest.NewRequest(http.MethodGet, "/status", nil)
+		w := httptest.NewRecorder()
+		assert.NoError(t, handler.Status(w, req))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		body := w.Body.String()
+		var resp api.StatusResponse
+		assert.NoError(t, json.Unmarshal([]byte(body), &resp))
+		return resp, body
+	}
+
+	resp, _ := get()
+	assert.Equal(t, "operational", resp.Status)
+	assert.Equal(t, version.Get().Version, resp.Version)
+	assert.Equal(t, int64(3600), resp.UptimeSeconds)
+	assert.Equal(t, "postgres", resp.Storage)
+	assert.Equal(t, []api.DependencyStatus{{Name: "storage", Status: "up", Availability: 100}}, resp.Dependencies)
+
+	// Outages are reported without their cause
+	storage.MarkUnavailable(fmt.Errorf("dial tcp 10.0.0.5:5432: connection refused"), time.Now())
+	resp, body := get()
+	assert.Equal(t, "degraded", resp.Status)
+	assert.Equal(t, "down", resp.Dependencies[0].Status)
+	assert.NotContains(t, body, "10.0.0.5")
+	assert.NotContains(t, body, "replicas")
+}
+
+func TestVersion(t *testing.T) {
+	handler := NewHealthHandler(health.CheckState(health.StorageMemory, false, 1, health.ProcessLocal), health.NewStorageMonitor())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/version", nil)
+	w := httptest.NewRecorder()
+	assert.NoError(t, handler.Version(w, req))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp api.VersionResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, version.Get().Version, resp.Version)
+	assert.Equal(t, version.Get().Commit, resp.Commit)
+}
@@ -2,55 +2,638 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"meetsync/internal/api"
+	"meetsync/internal/busy"
 	"meetsync/internal/interfaces"
-	"meetsync/internal/services"
+	"meetsync/internal/models"
+	"meetsync/internal/qrcode"
+	"meetsync/internal/spreadsheet"
 	"meetsync/pkg/errors"
 	"meetsync/pkg/logs"
 )
 
+const (
+	// maxRecommendationWait caps how long a recommendations request may wait for a change
+	maxRecommendationWait = time.Minute
+	// maxBatchRecommendations caps the number of meetings in a batch recommendations request
+	maxBatchRecommendations = 100
+	// recommendationModeSplit also suggests two-session splits when no slot fits everyone
+	recommendationModeSplit = "split"
+	// defaultQRCodeSize, minQRCodeSize and maxQRCodeSize bound the width of PNG QR codes in pixels
+	defaultQRCodeSize = 512
+	minQRCodeSize     = 64
+	maxQRCodeSize     = 2048
+	// maxBusyImportSize caps the size of an imported ICS or CSV of busy times in bytes
+	maxBusyImportSize = 5 << 20
+	// maxMeetingBodySize caps the size of a meeting created or updated, in bytes
+	maxMeetingBodySize = 1 << 20
+)
+
 // MeetingHandler handles meeting-related requests
 type MeetingHandler struct {
 	service interfaces.MeetingService
+	files   *FileLinks
+}
+
+// NewMeetingHandler creates a new MeetingHandler serving requests through service and
+// storing exported files with files
+func NewMeetingHandler(service interfaces.MeetingService, files *FileLinks) *MeetingHandler {
+	return &MeetingHandler{service: service, files: files}
+}
+
+// CreateMeeting handles the creation of a new meeting
+func (h *MeetingHandler) CreateMeeting(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return errors.NewValidationError("Method not allowed", "Only POST method is allowed")
+	}
+
+	var req api.CreateMeetingRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxMeetingBodySize)).Decode(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+	if err := authorizeUser(r, req.OrganizerID); err != nil {
+		return err
+	}
+
+	// Create meeting using service
+	createdMeeting, warnings, err := h.service.CreateMeeting(models.MeetingInput{
+		Title:                 req.Title,
+		OrganizerID:           req.OrganizerID,
+		EstimatedDuration:     req.EstimatedDuration,
+		MaxDuration:           req.MaxDuration,
+		ProposedSlots:         req.ProposedSlots,
+		ParticipantIDs:        req.ParticipantIDs,
+		Draft:                 req.Draft,
+		CoalesceAdjacentSlots: req.CoalesceAdjacentSlots,
+		TimeZone:              req.TimeZone,
+		OverrideFocusTime:     &req.OverrideFocusTime,
+		AvailabilityTTL:       req.AvailabilityTTL,
+		Tags:                  req.Tags,
+		Priority:              req.Priority,
+		ExternalID:            req.ExternalID,
+		Metadata:              req.Metadata,
+	})
+	if err != nil {
+		return err
+	}
+
+	logs.Info("Created meeting: %s by organizer %s", createdMeeting.ID, logs.PII(createdMeeting.Organizer.Name))
+
+	// Return response
+	resp := api.CreateMeetingResponse{
+		Meeting:  createdMeeting,
+		Warnings: warnings,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// UpsertExternalMeeting handles creating or updating the meeting a calendar sync knows
+// under an external ID, answering 201 Created when the meeting is new
+func (h *MeetingHandler) UpsertExternalMeeting(w http.ResponseWriter, r *http.Request) error {
+	externalID := r.PathValue("externalId")
+	if externalID == "" {
+		return errors.NewValidationError("External ID is required", "")
+	}
+
+	var req api.UpsertExternalMeetingRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxMeetingBodySize)).Decode(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+	if req.ExternalID != "" && req.ExternalID != externalID {
+		return errors.NewValidationError("External ID does not match the path", "")
+	}
+	if err := authorizeUser(r, req.OrganizerID); err != nil {
+		return err
+	}
+
+	meeting, created, warnings, err := h.service.UpsertExternalMeeting(externalID, models.MeetingInput{
+		Title:                 req.Title,
+		OrganizerID:           req.OrganizerID,
+		EstimatedDuration:     req.EstimatedDuration,
+		MaxDuration:           req.MaxDuration,
+		ProposedSlots:         req.ProposedSlots,
+		ParticipantIDs:        req.ParticipantIDs,
+		Draft:                 req.Draft,
+		CoalesceAdjacentSlots: req.CoalesceAdjacentSlots,
+		TimeZone:              req.TimeZone,
+		OverrideFocusTime:     &req.OverrideFocusTime,
+		AvailabilityTTL:       req.AvailabilityTTL,
+		Tags:                  req.Tags,
+		Priority:              req.Priority,
+		Metadata:              req.Metadata,
+		ExternalUpdatedAt:     req.ExternalUpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+		logs.Info("Created meeting: %s synced as %s", meeting.ID, externalID)
+	}
+
+	resp := api.CreateMeetingResponse{
+		Meeting:  meeting,
+		Warnings: warnings,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
 }
 
-// NewMeetingHandler creates a new MeetingHandler
-func NewMeetingHandler(userHandler *UserHandler) *MeetingHandler {
-	return &MeetingHandler{
-		service: services.NewMeetingService(userHandler.service),
+// ListMeetings handles listing meetings one page at a time
+func (h *MeetingHandler) ListMeetings(w http.ResponseWriter, r *http.Request) error {
+	cursor, limit, err := pageParams(r)
+	if err != nil {
+		return err
+	}
+
+	query := r.URL.Query()
+	filter := models.MeetingFilter{
+		Tags:          query["tag"],
+		ExternalID:    query.Get("externalId"),
+		OrganizerID:   query.Get("organizerId"),
+		ParticipantID: query.Get("participantId"),
+		Status:        models.MeetingStatus(query.Get("status")),
+		Metadata:      metadataFilter(query),
+	}
+	if value := query.Get("from"); value != "" {
+		if filter.From, err = time.Parse(time.RFC3339, value); err != nil {
+			return errors.NewValidationError("Invalid from", "from must be an RFC 3339 time")
+		}
+	}
+	if value := query.Get("to"); value != "" {
+		if filter.To, err = time.Parse(time.RFC3339, value); err != nil {
+			return errors.NewValidationError("Invalid to", "to must be an RFC 3339 time")
+		}
+	}
+
+	meetings, nextCursor, err := h.service.ListMeetings(cursor, limit, filter)
+	if err != nil {
+		return err
+	}
+
+	resp := api.ListMeetingsResponse{
+		Meetings:   meetings,
+		NextCursor: nextCursor,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// SearchMeetings handles searching meetings by title, tags and participant names
+func (h *MeetingHandler) SearchMeetings(w http.ResponseWriter, r *http.Request) error {
+	_, limit, err := pageParams(r)
+	if err != nil {
+		return err
+	}
+
+	results, err := h.service.SearchMeetings(r.URL.Query().Get("q"), limit)
+	if err != nil {
+		return err
+	}
+
+	resp := api.SearchMeetingsResponse{
+		Results: results,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// AddAvailability handles adding a participant's availability
+func (h *MeetingHandler) AddAvailability(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return errors.NewValidationError("Method not allowed", "Only POST method is allowed")
+	}
+
+	var req api.AddAvailabilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+	if err := authorizeUser(r, req.UserID); err != nil {
+		return err
+	}
+
+	// Add availability using service
+	_, err := h.service.AddAvailability(req.UserID, req.MeetingID, req.AvailableSlots, req.Tentative)
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	return nil
+}
+
+// ImportAvailability handles submitting availability for a meeting from an ICS or CSV of
+// the participant's busy times, for calendars that cannot be linked
+func (h *MeetingHandler) ImportAvailability(w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+	userID, meetingID := query.Get("userId"), query.Get("meetingId")
+	if userID == "" || meetingID == "" {
+		return errors.NewValidationError("User ID and meeting ID are required", "")
+	}
+	if err := authorizeUser(r, userID); err != nil {
+		return err
+	}
+	tentative := false
+	if raw := query.Get("tentative"); raw != "" {
+		var err error
+		if tentative, err = strconv.ParseBool(raw); err != nil {
+			return errors.NewValidationError("Invalid tentative flag", "use true or false")
+		}
+	}
+	location := time.UTC
+	if name := query.Get("timeZone"); name != "" {
+		var err error
+		if location, err = time.LoadLocation(name); err != nil {
+			return errors.NewValidationError("Invalid time zone", "use an IANA time zone such as Europe/Paris")
+		}
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBusyImportSize))
+	if err != nil {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+	format, err := busy.DetectFormat(query.Get("format"), r.Header.Get("Content-Type"), data)
+	if err != nil {
+		return err
+	}
+	busyTimes, err := busy.Parse(data, format, location)
+	if err != nil {
+		return err
+	}
+
+	availability, err := h.service.ImportAvailability(userID, meetingID, busyTimes, tentative)
+	if err != nil {
+		return err
+	}
+
+	resp := api.ImportAvailabilityResponse{
+		Availability: availability,
+		BusyTimes:    len(busyTimes),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// ImportCalendarAvailability handles submitting availability for a meeting from the busy
+// times of the participant's calendar at a provider, such as Google Calendar
+func (h *MeetingHandler) ImportCalendarAvailability(w http.ResponseWriter, r *http.Request) error {
+	provider := r.URL.Query().Get("provider")
+	if provider == "" {
+		return errors.NewValidationError("Provider is required", "")
+	}
+	var req api.ImportCalendarAvailabilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+	if req.UserID == "" {
+		return errors.NewValidationError("User ID is required", "")
+	}
+	if err := authorizeUser(r, req.UserID); err != nil {
+		return err
+	}
+
+	availability, busyTimes, err := h.service.ImportCalendarAvailability(r.Context(), req.UserID, r.PathValue("id"), provider, req.AccessToken, req.Tentative)
+	if err != nil {
+		return err
+	}
+
+	resp := api.ImportAvailabilityResponse{
+		Availability: availability,
+		BusyTimes:    busyTimes,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// GetRecommendations handles getting recommendations for a meeting
+func (h *MeetingHandler) GetRecommendations(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodGet {
+		return errors.NewValidationError("Method not allowed", "Only GET method is allowed")
+	}
+
+	meetingID := r.URL.Query().Get("meetingId")
+	if meetingID == "" {
+		return errors.NewValidationError("Meeting ID is required", "")
+	}
+
+	mode := r.URL.Query().Get("mode")
+	if mode != "" && mode != recommendationModeSplit {
+		return errors.NewValidationError("Invalid mode", "mode must be "+recommendationModeSplit)
+	}
+
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "json":
+	case spreadsheet.FormatCSV, spreadsheet.FormatXLSX:
+		if mode != "" || r.URL.Query().Has("waitForChange") {
+			return errors.NewValidationError("Exports cannot be combined with mode or waitForChange", "")
+		}
+		return h.exportRecommendations(w, meetingID, format)
+	default:
+		return errors.NewValidationError("Invalid format", "format must be json, csv or xlsx")
+	}
+
+	var wait time.Duration
+	if value := r.URL.Query().Get("waitForChange"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 || parsed > maxRecommendationWait {
+			return errors.NewValidationError("Invalid waitForChange", "waitForChange must be a positive duration of at most "+maxRecommendationWait.String())
+		}
+		wait = parsed
+	}
+
+	// Get recommendations using service
+	recommendations, err := h.service.GetRecommendations(r.Context(), meetingID, callerID(r))
+	if err != nil {
+		return err
+	}
+
+	if wait > 0 {
+		// Keep the server write timeout from cutting off the held request
+		_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(wait + 10*time.Second))
+
+		recommendations, err = h.service.WaitForRecommendationChange(r.Context(), meetingID, recommendations, wait)
+		if err != nil {
+			return err
+		}
+	}
+
+	resp := api.GetRecommendationsResponse{
+		RecommendedSlots: recommendations.Slots,
+		ComputedAt:       recommendations.ComputedAt,
+	}
+	if mode == recommendationModeSplit {
+		if resp.SplitSuggestions, err = h.service.GetSplitRecommendations(meetingID); err != nil {
+			return err
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// GetBatchRecommendations handles getting recommendations for several meetings at once.
+// Meetings that do not exist are reported per result instead of failing the whole batch.
+func (h *MeetingHandler) GetBatchRecommendations(w http.ResponseWriter, r *http.Request) error {
+	var req api.BatchRecommendationsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+	if len(req.MeetingIDs) == 0 {
+		return errors.NewValidationError("At least one meeting ID is required", "")
+	}
+	if len(req.MeetingIDs) > maxBatchRecommendations {
+		return errors.NewValidationError("Too many meeting IDs", fmt.Sprintf("at most %d meetings can be requested at once", maxBatchRecommendations))
+	}
+
+	resp := api.BatchRecommendationsResponse{
+		Results: make([]api.MeetingRecommendations, 0, len(req.MeetingIDs)),
+	}
+	for _, meetingID := range req.MeetingIDs {
+		result := api.MeetingRecommendations{MeetingID: meetingID}
+
+		recommendations, err := h.service.GetRecommendations(r.Context(), meetingID, callerID(r))
+		if appErr, ok := errors.AsAppError(err); ok && appErr.Type == errors.ErrorTypeNotFound {
+			result.Error = appErr.Message
+		} else if err != nil {
+			return err
+		} else {
+			result.RecommendedSlots = recommendations.Slots
+			result.ComputedAt = &recommendations.ComputedAt
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// SimulateRecommendations handles previewing how hypothetical changes to a meeting
+// would affect its recommendations, without changing the meeting
+func (h *MeetingHandler) SimulateRecommendations(w http.ResponseWriter, r *http.Request) error {
+	var req api.SimulateRecommendationsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+	if req.MeetingID == "" {
+		return errors.NewValidationError("Meeting ID is required", "")
+	}
+
+	simulation, err := h.service.SimulateRecommendations(req.MeetingID, models.RecommendationScenario{
+		RemoveParticipantIDs: req.RemoveParticipantIDs,
+		ExtendWindow:         req.ExtendWindow,
+		Duration:             req.Duration,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp := api.SimulateRecommendationsResponse{
+		Simulation: simulation,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// UpdateMeeting handles updating an existing meeting
+func (h *MeetingHandler) UpdateMeeting(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPut {
+		return errors.NewValidationError("Method not allowed", "Only PUT method is allowed")
+	}
+
+	meetingID := r.PathValue("id")
+	if meetingID == "" {
+		return errors.NewValidationError("Meeting ID is required", "")
+	}
+
+	var req api.UpdateMeetingRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxMeetingBodySize)).Decode(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+
+	// Update meeting using service
+	updatedMeeting, warnings, err := h.service.UpdateMeeting(meetingID, models.MeetingInput{
+		Title:                 req.Title,
+		EstimatedDuration:     req.EstimatedDuration,
+		MaxDuration:           req.MaxDuration,
+		ProposedSlots:         req.ProposedSlots,
+		ParticipantIDs:        req.ParticipantIDs,
+		CoalesceAdjacentSlots: req.CoalesceAdjacentSlots,
+		TimeZone:              req.TimeZone,
+		OverrideFocusTime:     req.OverrideFocusTime,
+		AvailabilityTTL:       req.AvailabilityTTL,
+		Tags:                  req.Tags,
+		Priority:              req.Priority,
+		Metadata:              req.Metadata,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp := api.UpdateMeetingResponse{
+		Meeting:  updatedMeeting,
+		Warnings: warnings,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// PublishMeeting handles publishing a draft meeting so participants can submit availability
+func (h *MeetingHandler) PublishMeeting(w http.ResponseWriter, r *http.Request) error {
+	meetingID := r.PathValue("id")
+	if meetingID == "" {
+		return errors.NewValidationError("Meeting ID is required", "")
+	}
+
+	publishedMeeting, err := h.service.PublishMeeting(meetingID)
+	if err != nil {
+		return err
+	}
+
+	logs.Info("Published meeting: %s", publishedMeeting.ID)
+
+	resp := api.PublishMeetingResponse{
+		Meeting: publishedMeeting,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// ConfirmMeeting handles confirming a meeting on one of its recommended slots
+func (h *MeetingHandler) ConfirmMeeting(w http.ResponseWriter, r *http.Request) error {
+	meetingID := r.PathValue("id")
+	if meetingID == "" {
+		return errors.NewValidationError("Meeting ID is required", "")
+	}
+
+	var req api.ConfirmMeetingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+	if req.Slot.StartTime.IsZero() || req.Slot.EndTime.IsZero() {
+		return errors.NewValidationError("Slot is required", "Set slot.startTime and slot.endTime to a recommended slot")
+	}
+
+	confirmedMeeting, err := h.service.ConfirmMeeting(meetingID, req.Slot)
+	if err != nil {
+		return err
+	}
+
+	logs.Info("Confirmed meeting: %s", confirmedMeeting.ID)
+
+	resp := api.ConfirmMeetingResponse{
+		Meeting: confirmedMeeting,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// CancelMeeting handles cancelling a meeting
+func (h *MeetingHandler) CancelMeeting(w http.ResponseWriter, r *http.Request) error {
+	meetingID := r.PathValue("id")
+	if meetingID == "" {
+		return errors.NewValidationError("Meeting ID is required", "")
+	}
+
+	cancelledMeeting, err := h.service.CancelMeeting(meetingID)
+	if err != nil {
+		return err
+	}
+
+	logs.Info("Cancelled meeting: %s", cancelledMeeting.ID)
+
+	resp := api.CancelMeetingResponse{
+		Meeting: cancelledMeeting,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
 	}
+	return nil
 }
 
-// CreateMeeting handles the creation of a new meeting
-func (h *MeetingHandler) CreateMeeting(w http.ResponseWriter, r *http.Request) error {
-	if r.Method != http.MethodPost {
-		return errors.NewValidationError("Method not allowed", "Only POST method is allowed")
+// ProposeAlternative handles a participant proposing another slot for a confirmed meeting
+func (h *MeetingHandler) ProposeAlternative(w http.ResponseWriter, r *http.Request) error {
+	meetingID := r.PathValue("id")
+	if meetingID == "" {
+		return errors.NewValidationError("Meeting ID is required", "")
 	}
 
-	var req api.CreateMeetingRequest
+	var req api.ProposeAlternativeRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		return errors.NewValidationError("Invalid request body", err.Error())
 	}
+	if req.ParticipantID == "" {
+		return errors.NewValidationError("Participant ID is required", "")
+	}
+	if err := authorizeUser(r, req.ParticipantID); err != nil {
+		return err
+	}
 
-	// Create meeting using service
-	createdMeeting, err := h.service.CreateMeeting(
-		req.Title,
-		req.OrganizerID,
-		req.EstimatedDuration,
-		req.ProposedSlots,
-		req.ParticipantIDs,
-	)
+	proposal, err := h.service.ProposeAlternative(meetingID, req.ParticipantID, req.Slot, req.Reason)
 	if err != nil {
 		return err
 	}
 
-	logs.Info("Created meeting: %s by organizer %s", createdMeeting.ID, createdMeeting.Organizer.Name)
-
-	// Return response
-	resp := api.CreateMeetingResponse{
-		Meeting: createdMeeting,
+	resp := api.ProposeAlternativeResponse{
+		Proposal: proposal,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -61,46 +644,59 @@ func (h *MeetingHandler) CreateMeeting(w http.ResponseWriter, r *http.Request) e
 	return nil
 }
 
-// AddAvailability handles adding a participant's availability
-func (h *MeetingHandler) AddAvailability(w http.ResponseWriter, r *http.Request) error {
-	if r.Method != http.MethodPost {
-		return errors.NewValidationError("Method not allowed", "Only POST method is allowed")
+// DecideProposal handles the organizer accepting or declining a proposed alternative
+func (h *MeetingHandler) DecideProposal(w http.ResponseWriter, r *http.Request) error {
+	meetingID, proposalID := r.PathValue("id"), r.PathValue("proposalId")
+	if meetingID == "" || proposalID == "" {
+		return errors.NewValidationError("Meeting ID and proposal ID are required", "")
 	}
 
-	var req api.AddAvailabilityRequest
+	var req api.DecideProposalRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		return errors.NewValidationError("Invalid request body", err.Error())
 	}
+	if req.Decision != "accept" && req.Decision != "decline" {
+		return errors.NewValidationError("Invalid decision", "decision must be accept or decline")
+	}
 
-	// Add availability using service
-	_, err := h.service.AddAvailability(req.UserID, req.MeetingID, req.AvailableSlots)
+	meeting, proposal, err := h.service.DecideProposal(meetingID, proposalID, req.Decision == "accept")
 	if err != nil {
 		return err
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	return nil
-}
+	logs.Info("Proposal %s of meeting %s was %s", proposal.ID, meeting.ID, proposal.Status)
 
-// GetRecommendations handles getting recommendations for a meeting
-func (h *MeetingHandler) GetRecommendations(w http.ResponseWriter, r *http.Request) error {
-	if r.Method != http.MethodGet {
-		return errors.NewValidationError("Method not allowed", "Only GET method is allowed")
+	resp := api.DecideProposalResponse{
+		Meeting:  meeting,
+		Proposal: proposal,
 	}
 
-	meetingID := r.URL.Query().Get("meetingId")
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// AddMeetingTags handles adding tags to a meeting
+func (h *MeetingHandler) AddMeetingTags(w http.ResponseWriter, r *http.Request) error {
+	meetingID := r.PathValue("id")
 	if meetingID == "" {
 		return errors.NewValidationError("Meeting ID is required", "")
 	}
 
-	// Get recommendations using service
-	recommendations, err := h.service.GetRecommendations(meetingID)
+	var req api.AddMeetingTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+
+	updatedMeeting, err := h.service.AddMeetingTags(meetingID, req.Tags)
 	if err != nil {
 		return err
 	}
 
-	resp := api.GetRecommendationsResponse{
-		RecommendedSlots: recommendations,
+	resp := api.UpdateMeetingTagsResponse{
+		Meeting: updatedMeeting,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -110,36 +706,22 @@ func (h *MeetingHandler) GetRecommendations(w http.ResponseWriter, r *http.Reque
 	return nil
 }
 
-// UpdateMeeting handles updating an existing meeting
-func (h *MeetingHandler) UpdateMeeting(w http.ResponseWriter, r *http.Request) error {
-	if r.Method != http.MethodPut {
-		return errors.NewValidationError("Method not allowed", "Only PUT method is allowed")
-	}
-
-	// Extract meeting ID from URL path
-	meetingID := r.URL.Path[len("/api/meetings/"):]
+// RemoveMeetingTag handles removing a tag from a meeting
+func (h *MeetingHandler) RemoveMeetingTag(w http.ResponseWriter, r *http.Request) error {
+	meetingID, tag := r.PathValue("id"), r.PathValue("tag")
 	if meetingID == "" {
 		return errors.NewValidationError("Meeting ID is required", "")
 	}
-
-	var req api.UpdateMeetingRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		return errors.NewValidationError("Invalid request body", err.Error())
+	if tag == "" {
+		return errors.NewValidationError("Tag is required", "")
 	}
 
-	// Update meeting using service
-	updatedMeeting, err := h.service.UpdateMeeting(
-		meetingID,
-		req.Title,
-		req.EstimatedDuration,
-		req.ProposedSlots,
-		req.ParticipantIDs,
-	)
+	updatedMeeting, err := h.service.RemoveMeetingTag(meetingID, tag)
 	if err != nil {
 		return err
 	}
 
-	resp := api.UpdateMeetingResponse{
+	resp := api.UpdateMeetingTagsResponse{
 		Meeting: updatedMeeting,
 	}
 
@@ -150,14 +732,31 @@ func (h *MeetingHandler) UpdateMeeting(w http.ResponseWriter, r *http.Request) e
 	return nil
 }
 
+// GetTagStats handles getting meeting statistics by tag
+func (h *MeetingHandler) GetTagStats(w http.ResponseWriter, r *http.Request) error {
+	stats, err := h.service.GetTagStats()
+	if err != nil {
+		return err
+	}
+
+	resp := api.GetTagStatsResponse{
+		Tags: stats,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
 // DeleteMeeting handles deleting an existing meeting
 func (h *MeetingHandler) DeleteMeeting(w http.ResponseWriter, r *http.Request) error {
 	if r.Method != http.MethodDelete {
 		return errors.NewValidationError("Method not allowed", "Only DELETE method is allowed")
 	}
 
-	// Extract meeting ID from URL path
-	meetingID := r.URL.Path[len("/api/meetings/"):]
+	meetingID := r.PathValue("id")
 	if meetingID == "" {
 		return errors.NewValidationError("Meeting ID is required", "")
 	}
@@ -178,8 +777,7 @@ func (h *MeetingHandler) UpdateAvailability(w http.ResponseWriter, r *http.Reque
 		return errors.NewValidationError("Method not allowed", "Only PUT method is allowed")
 	}
 
-	// Extract availability ID from URL path
-	availabilityID := r.URL.Path[len("/api/availabilities/"):]
+	availabilityID := r.PathValue("id")
 	if availabilityID == "" {
 		return errors.NewValidationError("Availability ID is required", "")
 	}
@@ -190,7 +788,7 @@ func (h *MeetingHandler) UpdateAvailability(w http.ResponseWriter, r *http.Reque
 	}
 
 	// Update availability using service
-	updatedAvailability, err := h.service.UpdateAvailability(availabilityID, req.AvailableSlots)
+	updatedAvailability, err := h.service.UpdateAvailability(availabilityID, req.AvailableSlots, req.Tentative)
 	if err != nil {
 		return err
 	}
@@ -212,8 +810,7 @@ func (h *MeetingHandler) DeleteAvailability(w http.ResponseWriter, r *http.Reque
 		return errors.NewValidationError("Method not allowed", "Only DELETE method is allowed")
 	}
 
-	// Extract availability ID from URL path
-	availabilityID := r.URL.Path[len("/api/availabilities/"):]
+	availabilityID := r.PathValue("id")
 	if availabilityID == "" {
 		return errors.NewValidationError("Availability ID is required", "")
 	}
@@ -228,6 +825,254 @@ func (h *MeetingHandler) DeleteAvailability(w http.ResponseWriter, r *http.Reque
 	return nil
 }
 
+// ConfirmAvailability handles a participant reconfirming their availability unchanged
+func (h *MeetingHandler) ConfirmAvailability(w http.ResponseWriter, r *http.Request) error {
+	availabilityID := r.PathValue("id")
+	if availabilityID == "" {
+		return errors.NewValidationError("Availability ID is required", "")
+	}
+
+	confirmedAvailability, err := h.service.ConfirmAvailability(availabilityID)
+	if err != nil {
+		return err
+	}
+
+	resp := api.ConfirmAvailabilityResponse{
+		Availability: confirmedAvailability,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// SetAvailabilityMetadata handles replacing the external ID and metadata of an availability
+func (h *MeetingHandler) SetAvailabilityMetadata(w http.ResponseWriter, r *http.Request) error {
+	availabilityID := r.PathValue("id")
+	if availabilityID == "" {
+		return errors.NewValidationError("Availability ID is required", "")
+	}
+
+	var req api.SetAvailabilityMetadataRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+
+	availability, err := h.service.SetAvailabilityMetadata(availabilityID, req.ExternalID, req.Metadata)
+	if err != nil {
+		return err
+	}
+
+	resp := api.GetAvailabilityResponse{
+		Availability: availability,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// ListAvailabilities handles listing the availabilities submitted for a meeting, filtered
+// by external ID and metadata
+func (h *MeetingHandler) ListAvailabilities(w http.ResponseWriter, r *http.Request) error {
+	meetingID := r.PathValue("id")
+	if meetingID == "" {
+		return errors.NewValidationError("Meeting ID is required", "")
+	}
+
+	query := r.URL.Query()
+	availabilities, err := h.service.ListAvailabilities(meetingID, models.AvailabilityFilter{
+		ExternalID: query.Get("externalId"),
+		Metadata:   metadataFilter(query),
+	})
+	if err != nil {
+		return err
+	}
+
+	resp := api.ListAvailabilitiesResponse{
+		Availabilities: availabilities,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// RespondToInvitation handles a one-click RSVP link from an invitation email; the
+// signed token in the path authenticates the participant
+func (h *MeetingHandler) RespondToInvitation(w http.ResponseWriter, r *http.Request) error {
+	token := r.PathValue("token")
+	if token == "" {
+		return errors.NewValidationError("Token is required", "")
+	}
+
+	availability, err := h.service.RespondToInvitation(token)
+	if err != nil {
+		return err
+	}
+
+	resp := api.RespondToInvitationResponse{
+		Availability: availability,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// AddGuest handles inviting a guest identified by email to a meeting
+func (h *MeetingHandler) AddGuest(w http.ResponseWriter, r *http.Request) error {
+	meetingID := r.PathValue("id")
+	if meetingID == "" {
+		return errors.NewValidationError("Meeting ID is required", "")
+	}
+
+	var req api.AddGuestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+
+	invitation, err := h.service.AddGuest(meetingID, req.Email, req.Name)
+	if err != nil {
+		return err
+	}
+
+	resp := api.AddGuestResponse{
+		Invitation: invitation,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// GetGuestMeeting handles a guest viewing the meeting they were invited to; the guest
+// token stands in for authentication
+func (h *MeetingHandler) GetGuestMeeting(w http.ResponseWriter, r *http.Request) error {
+	token := r.PathValue("token")
+	if token == "" {
+		return errors.NewValidationError("Token is required", "")
+	}
+
+	meeting, err := h.service.GetGuestMeeting(token)
+	if err != nil {
+		return err
+	}
+
+	resp := api.GetGuestMeetingResponse{
+		Meeting: meeting,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// SubmitGuestAvailability handles a guest submitting their availability for the meeting
+// they were invited to
+func (h *MeetingHandler) SubmitGuestAvailability(w http.ResponseWriter, r *http.Request) error {
+	token := r.PathValue("token")
+	if token == "" {
+		return errors.NewValidationError("Token is required", "")
+	}
+
+	var req api.SubmitGuestAvailabilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+
+	availability, err := h.service.SubmitGuestAvailability(token, req.AvailableSlots, req.Tentative)
+	if err != nil {
+		return err
+	}
+
+	resp := api.SubmitGuestAvailabilityResponse{
+		Availability: availability,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// GetPollQRCode handles rendering the poll link of a meeting as a QR code, a PNG image
+// unless format=svg is given, so organizers can project it in a room
+func (h *MeetingHandler) GetPollQRCode(w http.ResponseWriter, r *http.Request) error {
+	meetingID := r.PathValue("id")
+	if meetingID == "" {
+		return errors.NewValidationError("Meeting ID is required", "")
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = qrcode.FormatPNG
+	}
+	if format != qrcode.FormatPNG && format != qrcode.FormatSVG {
+		return errors.NewValidationError("Invalid format", "use png or svg")
+	}
+	size := defaultQRCodeSize
+	if raw := r.URL.Query().Get("size"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < minQRCodeSize || parsed > maxQRCodeSize {
+			return errors.NewValidationError("Invalid size", fmt.Sprintf("size must be between %d and %d pixels", minQRCodeSize, maxQRCodeSize))
+		}
+		size = parsed
+	}
+
+	link, err := h.service.GetPollLink(meetingID)
+	if err != nil {
+		return err
+	}
+	image, err := qrcode.Render(link, format, size)
+	if err != nil {
+		return errors.NewInternalError("Failed to render QR code", err)
+	}
+
+	w.Header().Set("Content-Type", qrcode.ContentType(format))
+	w.Header().Set("X-Poll-URL", link)
+	w.Write(image)
+	return nil
+}
+
+// RequestReconfirmation handles asking participants to reconfirm stale availability for a meeting
+func (h *MeetingHandler) RequestReconfirmation(w http.ResponseWriter, r *http.Request) error {
+	meetingID := r.PathValue("id")
+	if meetingID == "" {
+		return errors.NewValidationError("Meeting ID is required", "")
+	}
+
+	stale, err := h.service.RequestReconfirmation(meetingID)
+	if err != nil {
+		return err
+	}
+
+	logs.Info("Requested reconfirmation of %d stale availabilities for meeting %s", len(stale), meetingID)
+
+	resp := api.RequestReconfirmationResponse{
+		Stale: stale,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
 // GetAvailability handles getting a participant's availability
 func (h *MeetingHandler) GetAvailability(w http.ResponseWriter, r *http.Request) error {
 	if r.Method != http.MethodGet {
@@ -261,3 +1106,31 @@ func (h *MeetingHandler) GetAvailability(w http.ResponseWriter, r *http.Request)
 	}
 	return nil
 }
+
+// GetMeetingTimeline handles getting the activity timeline of a meeting
+func (h *MeetingHandler) GetMeetingTimeline(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodGet {
+		return errors.NewValidationError("Method not allowed", "Only GET method is allowed")
+	}
+
+	meetingID := r.PathValue("id")
+	if meetingID == "" {
+		return errors.NewValidationError("Meeting ID is required", "")
+	}
+
+	// Get timeline using service
+	timeline, err := h.service.GetMeetingTimeline(meetingID)
+	if err != nil {
+		return err
+	}
+
+	resp := api.GetMeetingTimelineResponse{
+		Events: timeline,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
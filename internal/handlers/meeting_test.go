@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -57,35 +59,115 @@ type MockMeetingService struct {
 
 var _ interfaces.MeetingService = (*MockMeetingService)(nil) // Verify MockMeetingService implements MeetingService interface
 
-func (m *MockMeetingService) CreateMeeting(title string, organizerID string, estimatedDuration int, proposedSlots []models.TimeSlot, participantIDs []string) (models.Meeting, error) {
-	args := m.Called(title, organizerID, estimatedDuration, mock.MatchedBy(func(slots []models.TimeSlot) bool {
-		return timeSlotMatcher{proposedSlots}.Matches(slots)
-	}), participantIDs)
-	return args.Get(0).(models.Meeting), args.Error(1)
+func (m *MockMeetingService) CreateMeeting(input models.MeetingInput) (models.Meeting, []models.Warning, error) {
+	args := m.Called(input.Title, input.OrganizerID, input.EstimatedDuration, mock.MatchedBy(func(slots []models.TimeSlot) bool {
+		return timeSlotMatcher{input.ProposedSlots}.Matches(slots)
+	}), input.ParticipantIDs)
+	return args.Get(0).(models.Meeting), nil, args.Error(1)
+}
+
+func (m *MockMeetingService) GetRecommendations(ctx context.Context, meetingID, viewerID string) (models.RecommendationSet, error) {
+	args := m.Called(meetingID, viewerID)
+	return args.Get(0).(models.RecommendationSet), args.Error(1)
+}
+
+func (m *MockMeetingService) GetSplitRecommendations(meetingID string) ([]models.SplitRecommendation, error) {
+	args := m.Called(meetingID)
+	return args.Get(0).([]models.SplitRecommendation), args.Error(1)
+}
+
+func (m *MockMeetingService) GetAvailabilityGrid(meetingID string) (models.AvailabilityGrid, error) {
+	args := m.Called(meetingID)
+	return args.Get(0).(models.AvailabilityGrid), args.Error(1)
+}
+
+func (m *MockMeetingService) GetMeetingSummary(meetingID string) (models.MeetingSummary, error) {
+	args := m.Called(meetingID)
+	return args.Get(0).(models.MeetingSummary), args.Error(1)
+}
+
+func (m *MockMeetingService) SimulateRecommendations(meetingID string, scenario models.RecommendationScenario) (models.RecommendationSimulation, error) {
+	args := m.Called(meetingID, scenario)
+	return args.Get(0).(models.RecommendationSimulation), args.Error(1)
+}
+
+func (m *MockMeetingService) WaitForRecommendationChange(ctx context.Context, meetingID string, previous models.RecommendationSet, timeout time.Duration) (models.RecommendationSet, error) {
+	args := m.Called(meetingID, previous, timeout)
+	return args.Get(0).(models.RecommendationSet), args.Error(1)
+}
+
+func (m *MockMeetingService) PrecomputeRecommendations(meetingID string, requestedAt time.Time) error {
+	args := m.Called(meetingID, requestedAt)
+	return args.Error(0)
+}
+
+func (m *MockMeetingService) UpdateMeeting(meetingID string, input models.MeetingInput) (models.Meeting, []models.Warning, error) {
+	args := m.Called(meetingID, input.Title, input.EstimatedDuration, input.ProposedSlots, input.ParticipantIDs)
+	return args.Get(0).(models.Meeting), nil, args.Error(1)
+}
+
+func (m *MockMeetingService) UpsertExternalMeeting(externalID string, input models.MeetingInput) (models.Meeting, bool, []models.Warning, error) {
+	args := m.Called(externalID, input.OrganizerID, input.ExternalUpdatedAt)
+	return args.Get(0).(models.Meeting), args.Bool(1), nil, args.Error(2)
+}
+
+func (m *MockMeetingService) ListAvailabilities(meetingID string, filter models.AvailabilityFilter) ([]models.Availability, error) {
+	args := m.Called(meetingID, filter)
+	return args.Get(0).([]models.Availability), args.Error(1)
+}
+
+func (m *MockMeetingService) SetAvailabilityMetadata(availabilityID, externalID string, metadata models.Metadata) (models.Availability, error) {
+	args := m.Called(availabilityID, externalID, metadata)
+	return args.Get(0).(models.Availability), args.Error(1)
+}
+
+func (m *MockMeetingService) ListMeetings(cursor string, limit int, filter models.MeetingFilter) ([]models.Meeting, string, error) {
+	args := m.Called(cursor, limit, filter)
+	return args.Get(0).([]models.Meeting), args.String(1), args.Error(2)
+}
+
+func (m *MockMeetingService) SearchMeetings(query string, limit int) ([]models.SearchResult, error) {
+	args := m.Called(query, limit)
+	return args.Get(0).([]models.SearchResult), args.Error(1)
 }
 
-func (m *MockMeetingService) GetRecommendations(meetingID string) ([]models.RecommendedSlot, error) {
+func (m *MockMeetingService) PublishMeeting(meetingID string) (models.Meeting, error) {
 	args := m.Called(meetingID)
-	return args.Get(0).([]models.RecommendedSlot), args.Error(1)
+	return args.Get(0).(models.Meeting), args.Error(1)
+}
+
+func (m *MockMeetingService) ConfirmMeeting(meetingID string, slot models.TimeSlot) (models.Meeting, error) {
+	args := m.Called(meetingID, slot)
+	return args.Get(0).(models.Meeting), args.Error(1)
 }
 
-func (m *MockMeetingService) UpdateMeeting(meetingID string, title string, estimatedDuration int, proposedSlots []models.TimeSlot, participantIDs []string) (models.Meeting, error) {
-	args := m.Called(meetingID, title, estimatedDuration, proposedSlots, participantIDs)
+func (m *MockMeetingService) CancelMeeting(meetingID string) (models.Meeting, error) {
+	args := m.Called(meetingID)
 	return args.Get(0).(models.Meeting), args.Error(1)
 }
 
+func (m *MockMeetingService) ProposeAlternative(meetingID, participantID string, slot models.TimeSlot, reason string) (models.Proposal, error) {
+	args := m.Called(meetingID, participantID, slot, reason)
+	return args.Get(0).(models.Proposal), args.Error(1)
+}
+
+func (m *MockMeetingService) DecideProposal(meetingID, proposalID string, accept bool) (models.Meeting, models.Proposal, error) {
+	args := m.Called(meetingID, proposalID, accept)
+	return args.Get(0).(models.Meeting), args.Get(1).(models.Proposal), args.Error(2)
+}
+
 func (m *MockMeetingService) DeleteMeeting(meetingID string) error {
 	args := m.Called(meetingID)
 	return args.Error(0)
 }
 
-func (m *MockMeetingService) AddAvailability(userID string, meetingID string, availableSlots []models.TimeSlot) (models.Availability, error) {
-	args := m.Called(userID, meetingID, availableSlots)
+func (m *MockMeetingService) AddAvailability(userID string, meetingID string, availableSlots []models.TimeSlot, tentative bool) (models.Availability, error) {
+	args := m.Called(userID, meetingID, availableSlots, tentative)
 	return args.Get(0).(models.Availability), args.Error(1)
 }
 
-func (m *MockMeetingService) UpdateAvailability(availabilityID string, availableSlots []models.TimeSlot) (models.Availability, error) {
-	args := m.Called(availabilityID, availableSlots)
+func (m *MockMeetingService) UpdateAvailability(availabilityID string, availableSlots []models.TimeSlot, tentative bool) (models.Availability, error) {
+	args := m.Called(availabilityID, availableSlots, tentative)
 	return args.Get(0).(models.Availability), args.Error(1)
 }
 
@@ -94,11 +176,114 @@ func (m *MockMeetingService) DeleteAvailability(availabilityID string) error {
 	return args.Error(0)
 }
 
+func (m *MockMeetingService) AddMeetingTags(meetingID string, tags []string) (models.Meeting, error) {
+	args := m.Called(meetingID, tags)
+	return args.Get(0).(models.Meeting), args.Error(1)
+}
+
+func (m *MockMeetingService) RemoveMeetingTag(meetingID string, tag string) (models.Meeting, error) {
+	args := m.Called(meetingID, tag)
+	return args.Get(0).(models.Meeting), args.Error(1)
+}
+
+func (m *MockMeetingService) GetTagStats() ([]models.TagStats, error) {
+	args := m.Called()
+	return args.Get(0).([]models.TagStats), args.Error(1)
+}
+
+func (m *MockMeetingService) Snapshot() (models.MeetingStoreSnapshot, error) {
+	args := m.Called()
+	return args.Get(0).(models.MeetingStoreSnapshot), args.Error(1)
+}
+
+func (m *MockMeetingService) Restore(snapshot models.MeetingStoreSnapshot) error {
+	args := m.Called(snapshot)
+	return args.Error(0)
+}
+
+func (m *MockMeetingService) ConfirmAvailability(availabilityID string) (models.Availability, error) {
+	args := m.Called(availabilityID)
+	return args.Get(0).(models.Availability), args.Error(1)
+}
+
+func (m *MockMeetingService) RespondToInvitation(token string) (models.Availability, error) {
+	args := m.Called(token)
+	return args.Get(0).(models.Availability), args.Error(1)
+}
+
+func (m *MockMeetingService) GetPollLink(meetingID string) (string, error) {
+	args := m.Called(meetingID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockMeetingService) SendDigests(now time.Time) (models.DigestResult, error) {
+	args := m.Called(now)
+	return args.Get(0).(models.DigestResult), args.Error(1)
+}
+
+func (m *MockMeetingService) AddGuest(meetingID, email, name string) (models.GuestInvitation, error) {
+	args := m.Called(meetingID, email, name)
+	return args.Get(0).(models.GuestInvitation), args.Error(1)
+}
+
+func (m *MockMeetingService) GetGuestMeeting(token string) (models.GuestMeeting, error) {
+	args := m.Called(token)
+	return args.Get(0).(models.GuestMeeting), args.Error(1)
+}
+
+func (m *MockMeetingService) SubmitGuestAvailability(token string, availableSlots []models.TimeSlot, tentative bool) (models.Availability, error) {
+	args := m.Called(token, availableSlots, tentative)
+	return args.Get(0).(models.Availability), args.Error(1)
+}
+
+func (m *MockMeetingService) ImportAvailability(userID, meetingID string, busy []models.TimeSlot, tentative bool) (models.Availability, error) {
+	args := m.Called(userID, meetingID, busy, tentative)
+	return args.Get(0).(models.Availability), args.Error(1)
+}
+
+func (m *MockMeetingService) ImportCalendarAvailability(ctx context.Context, userID, meetingID, provider, accessToken string, tentative bool) (models.Availability, int, error) {
+	args := m.Called(userID, meetingID, provider, accessToken, tentative)
+	return args.Get(0).(models.Availability), args.Int(1), args.Error(2)
+}
+
+func (m *MockMeetingService) RequestReconfirmation(meetingID string) ([]models.Availability, error) {
+	args := m.Called(meetingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Availability), args.Error(1)
+}
+
 func (m *MockMeetingService) GetAvailability(userID string, meetingID string) (models.Availability, error) {
 	args := m.Called(userID, meetingID)
 	return args.Get(0).(models.Availability), args.Error(1)
 }
 
+func (m *MockMeetingService) GetMeetingTimeline(meetingID string) ([]models.TimelineEvent, error) {
+	args := m.Called(meetingID)
+	return args.Get(0).([]models.TimelineEvent), args.Error(1)
+}
+
+func (m *MockMeetingService) ReassignUser(fromUserID string, toUserID string) (models.UserReassignment, error) {
+	args := m.Called(fromUserID, toUserID)
+	return args.Get(0).(models.UserReassignment), args.Error(1)
+}
+
+func (m *MockMeetingService) GetCalendarEvent(meetingID string) (models.CalendarEvent, error) {
+	args := m.Called(meetingID)
+	return args.Get(0).(models.CalendarEvent), args.Error(1)
+}
+
+func (m *MockMeetingService) AuthorizeMeetingChange(userID, meetingID string) error {
+	args := m.Called(userID, meetingID)
+	return args.Error(0)
+}
+
+func (m *MockMeetingService) AuthorizeAvailabilityChange(userID, availabilityID string) error {
+	args := m.Called(userID, availabilityID)
+	return args.Error(0)
+}
+
 func TestCreateMeeting(t *testing.T) {
 	// Create test data
 	now := time.Now().Truncate(time.Second) // Truncate to remove sub-second precision
@@ -214,7 +399,7 @@ func TestCreateMeeting(t *testing.T) {
 			tt.setupMock(mockService)
 
 			// Create handler with mock service
-			handler := &MeetingHandler{service: mockService}
+			handler := NewMeetingHandler(mockService, nil)
 
 			// Create request
 			body, _ := json.Marshal(tt.request)
@@ -261,6 +446,29 @@ func TestCreateMeeting(t *testing.T) {
 	}
 }
 
+func TestMeetingBodyTooLarge(t *testing.T) {
+	// Oversized bodies are rejected before they reach the service
+	mockService := new(MockMeetingService)
+	handler := NewMeetingHandler(mockService, nil)
+	body := `{"title":"` + strings.Repeat("a", maxMeetingBodySize) + `"}`
+
+	req := httptest.NewRequest(http.MethodPost, "/api/meetings", strings.NewReader(body))
+	err := handler.CreateMeeting(httptest.NewRecorder(), req)
+	assert.True(t, errors.Is(err, errors.ErrValidation))
+
+	req = httptest.NewRequest(http.MethodPut, "/api/meetings/m1", strings.NewReader(body))
+	req.SetPathValue("id", "m1")
+	err = handler.UpdateMeeting(httptest.NewRecorder(), req)
+	assert.True(t, errors.Is(err, errors.ErrValidation))
+
+	req = httptest.NewRequest(http.MethodPut, "/api/meetings/external/cal-1", strings.NewReader(body))
+	req.SetPathValue("externalId", "cal-1")
+	err = handler.UpsertExternalMeeting(httptest.NewRecorder(), req)
+	assert.True(t, errors.Is(err, errors.ErrValidation))
+
+	mockService.AssertExpectations(t)
+}
+
 func TestGetRecommendations(t *testing.T) {
 	// Create test data
 	now := time.Now()
@@ -290,15 +498,19 @@ func TestGetRecommendations(t *testing.T) {
 			name:      "successful recommendations",
 			meetingID: meetingID,
 			setupMock: func(m *MockMeetingService) {
-				recommendations := []models.RecommendedSlot{
-					{
-						TimeSlot:                testSlot,
-						AvailableCount:          1,
-						TotalParticipants:       2,
-						UnavailableParticipants: []models.User{organizer},
+				recommendations := models.RecommendationSet{
+					MeetingID: meetingID,
+					Slots: []models.RecommendedSlot{
+						{
+							TimeSlot:                testSlot,
+							AvailableCount:          1,
+							TotalParticipants:       2,
+							UnavailableParticipants: []models.User{organizer},
+						},
 					},
+					ComputedAt: now,
 				}
-				m.On("GetRecommendations", meetingID).Return(recommendations, nil)
+				m.On("GetRecommendations", meetingID, "").Return(recommendations, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedError:  false,
@@ -307,7 +519,7 @@ func TestGetRecommendations(t *testing.T) {
 			name:      "meeting not found",
 			meetingID: "non-existent",
 			setupMock: func(m *MockMeetingService) {
-				m.On("GetRecommendations", "non-existent").Return([]models.RecommendedSlot{}, errors.NewNotFoundError("Meeting not found"))
+				m.On("GetRecommendations", "non-existent", "").Return(models.RecommendationSet{}, errors.NewNotFoundError("Meeting not found"))
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedError:  true,
@@ -328,7 +540,7 @@ func TestGetRecommendations(t *testing.T) {
 			tt.setupMock(mockService)
 
 			// Create handler with mock service
-			handler := &MeetingHandler{service: mockService}
+			handler := NewMeetingHandler(mockService, nil)
 
 			// Create request
 			req := httptest.NewRequest(http.MethodGet, "/api/recommendations?meetingId="+tt.meetingID, nil)
@@ -354,6 +566,7 @@ func TestGetRecommendations(t *testing.T) {
 				assert.Len(t, resp.RecommendedSlots, 1)
 				assert.Equal(t, 1, resp.RecommendedSlots[0].AvailableCount)
 				assert.Equal(t, 2, resp.RecommendedSlots[0].TotalParticipants)
+				assert.True(t, now.Equal(resp.ComputedAt))
 			}
 
 			// Verify mock expectations
@@ -361,3 +574,1138 @@ func TestGetRecommendations(t *testing.T) {
 		})
 	}
 }
+
+func TestGetRecommendations_QueryOptions(t *testing.T) {
+	meetingID := uuid.New().String()
+	initial := models.RecommendationSet{MeetingID: meetingID, ComputedAt: time.Now()}
+	changed := models.RecommendationSet{
+		MeetingID:  meetingID,
+		Slots:      []models.RecommendedSlot{{AvailableCount: 1, TotalParticipants: 1}},
+		ComputedAt: time.Now(),
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*MockMeetingService)
+		expectedStatus int
+	}{
+		{
+			name:  "waits for a change",
+			query: "meetingId=" + meetingID + "&waitForChange=30s",
+			setupMock: func(m *MockMeetingService) {
+				m.On("GetRecommendations", meetingID, "").Return(initial, nil)
+				m.On("WaitForRecommendationChange", meetingID, initial, 30*time.Second).Return(changed, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "split mode",
+			query: "meetingId=" + meetingID + "&mode=split",
+			setupMock: func(m *MockMeetingService) {
+				m.On("GetRecommendations", meetingID, "").Return(changed, nil)
+				m.On("GetSplitRecommendations", meetingID).Return([]models.SplitRecommendation{{CoveredCount: 1, TotalParticipants: 1}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unknown mode",
+			query:          "meetingId=" + meetingID + "&mode=other",
+			setupMock:      func(m *MockMeetingService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid duration",
+			query:          "meetingId=" + meetingID + "&waitForChange=soon",
+			setupMock:      func(m *MockMeetingService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "duration above the maximum",
+			query:          "meetingId=" + meetingID + "&waitForChange=5m",
+			setupMock:      func(m *MockMeetingService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockMeetingService)
+			tt.setupMock(mockService)
+			handler := NewMeetingHandler(mockService, nil)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/recommendations?"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			err := handler.GetRecommendations(w, req)
+			if tt.expectedStatus != http.StatusOK {
+				assert.Error(t, err)
+				if appErr, ok := err.(*errors.AppError); ok {
+					assert.Equal(t, tt.expectedStatus, appErr.HTTPStatusCode())
+				}
+			} else {
+				assert.NoError(t, err)
+				var resp api.GetRecommendationsResponse
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Len(t, resp.RecommendedSlots, 1)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetRecommendations_Export(t *testing.T) {
+	grid := models.AvailabilityGrid{
+		MeetingID:    "meeting-1",
+		Title:        "Planning",
+		TimeZone:     "Europe/Paris",
+		Participants: []models.User{{ID: "user-1", Name: "Ada"}, {ID: "user-2", Email: "bob@example.com"}},
+		Rows: []models.AvailabilityRow{{
+			Slot: models.RecommendedSlot{
+				TimeSlot:          models.TimeSlot{StartTime: time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC), EndTime: time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)},
+				AvailableCount:    1,
+				TotalParticipants: 2,
+			},
+			Marks: []models.AvailabilityMark{models.AvailabilityMarkAvailable, models.AvailabilityMarkNoResponse},
+		}},
+	}
+	mockService := new(MockMeetingService)
+	mockService.On("GetAvailabilityGrid", "meeting-1").Return(grid, nil)
+	handler := NewMeetingHandler(mockService, nil)
+
+	get := func(query string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodGet, "/api/recommendations?meetingId=meeting-1"+query, nil)
+		w := httptest.NewRecorder()
+		return w, handler.GetRecommendations(w, req)
+	}
+
+	w, err := get("&format=csv")
+	assert.NoError(t, err)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="recommendations-meeting-1.csv"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "Rank,Start (Europe/Paris),End (Europe/Paris),Available,Tentative,Participants,Ada,bob@example.com\n"+
+		"1,2030-01-07 10:00,2030-01-07 11:00,1,0,2,yes,\n", w.Body.String())
+
+	w, err = get("&format=xlsx")
+	assert.NoError(t, err)
+	assert.Equal(t, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", w.Header().Get("Content-Type"))
+	assert.True(t, bytes.HasPrefix(w.Body.Bytes(), []byte("PK")))
+
+	for _, query := range []string{"&format=pdf", "&format=csv&mode=split", "&format=xlsx&waitForChange=5s"} {
+		_, err = get(query)
+		if assert.Error(t, err, query) {
+			assert.Equal(t, http.StatusBadRequest, err.(*errors.AppError).HTTPStatusCode(), query)
+		}
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestExportRecommendations(t *testing.T) {
+	mockService := new(MockMeetingService)
+	mockService.On("GetAvailabilityGrid", "meeting-1").Return(models.AvailabilityGrid{MeetingID: "meeting-1", TimeZone: "UTC"}, nil)
+	handler := NewMeetingHandler(mockService, nil)
+
+	export := func(req api.ExportRecommendationsRequest) (*httptest.ResponseRecorder, error) {
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		return w, handler.ExportRecommendations(w, httptest.NewRequest(http.MethodPost, "/api/recommendations/export", bytes.NewReader(body)))
+	}
+
+	// Exports need file storage
+	_, err := export(api.ExportRecommendationsRequest{MeetingID: "meeting-1", Format: "csv"})
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusNotFound, err.(*errors.AppError).HTTPStatusCode())
+	}
+
+	files := memoryFileStore{}
+	handler.files = NewFileLinks(files, time.Hour)
+	w, err := export(api.ExportRecommendationsRequest{MeetingID: "meeting-1", Format: "csv"})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp api.DownloadLinkResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Regexp(t, `^https://files\.example\.com/exports/meeting-1/[0-9a-f-]{36}/recommendations-meeting-1\.csv$`, resp.URL)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), resp.ExpiresAt, time.Minute)
+	assert.Contains(t, files[strings.TrimPrefix(resp.URL, "https://files.example.com/")], "Rank,")
+
+	for _, req := range []api.ExportRecommendationsRequest{{Format: "csv"}, {MeetingID: "meeting-1", Format: "json"}} {
+		_, err := export(req)
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusBadRequest, err.(*errors.AppError).HTTPStatusCode())
+		}
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+// memoryFileStore keeps files in memory and links to them on an example host
+type memoryFileStore map[string]string
+
+func (s memoryFileStore) Put(ctx context.Context, key, contentType string, body []byte) error {
+	s[key] = string(body)
+	return nil
+}
+
+func (s memoryFileStore) SignedURL(key string, ttl time.Duration) (string, error) {
+	return "https://files.example.com/" + key, nil
+}
+
+func TestGetBatchRecommendations(t *testing.T) {
+	firstID := uuid.New().String()
+	secondID := uuid.New().String()
+	now := time.Now()
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*MockMeetingService)
+		expectedStatus int
+	}{
+		{
+			name: "reports missing meetings per result",
+			body: `{"meetingIds":["` + firstID + `","` + secondID + `"]}`,
+			setupMock: func(m *MockMeetingService) {
+				m.On("GetRecommendations", firstID, "").Return(models.RecommendationSet{
+					MeetingID:  firstID,
+					Slots:      []models.RecommendedSlot{{AvailableCount: 2, TotalParticipants: 3}},
+					ComputedAt: now,
+				}, nil)
+				m.On("GetRecommendations", secondID, "").Return(models.RecommendationSet{}, errors.NewNotFoundError("Meeting not found"))
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "no meeting IDs",
+			body:           `{"meetingIds":[]}`,
+			setupMock:      func(m *MockMeetingService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid body",
+			body:           `not json`,
+			setupMock:      func(m *MockMeetingService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "internal errors fail the batch",
+			body: `{"meetingIds":["` + firstID + `"]}`,
+			setupMock: func(m *MockMeetingService) {
+				m.On("GetRecommendations", firstID, "").Return(models.RecommendationSet{}, errors.NewInternalError("Storage unavailable", nil))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockMeetingService)
+			tt.setupMock(mockService)
+			handler := NewMeetingHandler(mockService, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/recommendations/batch", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			err := handler.GetBatchRecommendations(w, req)
+			if tt.expectedStatus != http.StatusOK {
+				assert.Error(t, err)
+				if appErr, ok := err.(*errors.AppError); ok {
+					assert.Equal(t, tt.expectedStatus, appErr.HTTPStatusCode())
+				}
+			} else {
+				assert.NoError(t, err)
+				var resp api.BatchRecommendationsResponse
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Len(t, resp.Results, 2)
+				assert.Equal(t, firstID, resp.Results[0].MeetingID)
+				assert.Len(t, resp.Results[0].RecommendedSlots, 1)
+				assert.NotNil(t, resp.Results[0].ComputedAt)
+				assert.Empty(t, resp.Results[0].Error)
+				assert.Equal(t, secondID, resp.Results[1].MeetingID)
+				assert.Equal(t, "Meeting not found", resp.Results[1].Error)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSimulateRecommendations(t *testing.T) {
+	meetingID := uuid.New().String()
+	scenario := models.RecommendationScenario{RemoveParticipantIDs: []string{"bob"}, Duration: 30}
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*MockMeetingService)
+		expectedStatus int
+	}{
+		{
+			name: "successful simulation",
+			body: `{"meetingId":"` + meetingID + `","removeParticipantIds":["bob"],"duration":30}`,
+			setupMock: func(m *MockMeetingService) {
+				m.On("SimulateRecommendations", meetingID, scenario).Return(models.RecommendationSimulation{
+					MeetingID: meetingID,
+					Scenario:  scenario,
+					Changes:   []models.RecommendationChange{{Kind: models.RecommendationChanged, CurrentAvailableCount: 1, SimulatedAvailableCount: 1}},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "meeting not found",
+			body: `{"meetingId":"` + meetingID + `","removeParticipantIds":["bob"],"duration":30}`,
+			setupMock: func(m *MockMeetingService) {
+				m.On("SimulateRecommendations", meetingID, scenario).Return(models.RecommendationSimulation{}, errors.NewNotFoundError("Meeting not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "missing meeting ID",
+			body:           `{"duration":30}`,
+			setupMock:      func(m *MockMeetingService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid body",
+			body:           `not json`,
+			setupMock:      func(m *MockMeetingService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockMeetingService)
+			tt.setupMock(mockService)
+			handler := NewMeetingHandler(mockService, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/recommendations/simulate", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			err := handler.SimulateRecommendations(w, req)
+			if tt.expectedStatus != http.StatusOK {
+				assert.Error(t, err)
+				if appErr, ok := err.(*errors.AppError); ok {
+					assert.Equal(t, tt.expectedStatus, appErr.HTTPStatusCode())
+				}
+			} else {
+				assert.NoError(t, err)
+				var resp api.SimulateRecommendationsResponse
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, meetingID, resp.Simulation.MeetingID)
+				assert.Len(t, resp.Simulation.Changes, 1)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetMeetingTimeline(t *testing.T) {
+	meetingID := uuid.New().String()
+	now := time.Now()
+
+	tests := []struct {
+		name           string
+		meetingID      string
+		setupMock      func(*MockMeetingService)
+		expectedStatus int
+		expectedError  bool
+	}{
+		{
+			name:      "successful timeline",
+			meetingID: meetingID,
+			setupMock: func(m *MockMeetingService) {
+				timeline := []models.TimelineEvent{
+					{ID: uuid.New().String(), MeetingID: meetingID, Type: models.TimelineMeetingCreated, OccurredAt: now},
+					{ID: uuid.New().String(), MeetingID: meetingID, Type: models.TimelineAvailabilitySubmitted, OccurredAt: now.Add(time.Minute)},
+				}
+				m.On("GetMeetingTimeline", meetingID).Return(timeline, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "meeting not found",
+			meetingID: "non-existent",
+			setupMock: func(m *MockMeetingService) {
+				m.On("GetMeetingTimeline", "non-existent").Return([]models.TimelineEvent{}, errors.NewNotFoundError("Meeting not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedError:  true,
+		},
+		{
+			name:           "missing meeting ID",
+			meetingID:      "",
+			setupMock:      func(m *MockMeetingService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockMeetingService)
+			tt.setupMock(mockService)
+			handler := NewMeetingHandler(mockService, nil)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/meetings/"+tt.meetingID+"/timeline", nil)
+			req.SetPathValue("id", tt.meetingID)
+			w := httptest.NewRecorder()
+
+			err := handler.GetMeetingTimeline(w, req)
+
+			if tt.expectedError {
+				assert.Error(t, err)
+				if appErr, ok := err.(*errors.AppError); ok {
+					assert.Equal(t, tt.expectedStatus, appErr.HTTPStatusCode())
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, w.Code)
+
+				var resp api.GetMeetingTimelineResponse
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Len(t, resp.Events, 2)
+				assert.Equal(t, models.TimelineMeetingCreated, resp.Events[0].Type)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRequestReconfirmation(t *testing.T) {
+	meetingID := uuid.New().String()
+
+	tests := []struct {
+		name           string
+		meetingID      string
+		setupMock      func(*MockMeetingService)
+		expectedStatus int
+		expectedError  bool
+	}{
+		{
+			name:      "stale availabilities",
+			meetingID: meetingID,
+			setupMock: func(m *MockMeetingService) {
+				stale := []models.Availability{{ID: uuid.New().String(), MeetingID: meetingID, Stale: true}}
+				m.On("RequestReconfirmation", meetingID).Return(stale, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "meeting without TTL",
+			meetingID: meetingID,
+			setupMock: func(m *MockMeetingService) {
+				m.On("RequestReconfirmation", meetingID).Return(nil, errors.NewValidationError("Meeting has no availability TTL", ""))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+		{
+			name:           "missing meeting ID",
+			meetingID:      "",
+			setupMock:      func(m *MockMeetingService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockMeetingService)
+			tt.setupMock(mockService)
+			handler := NewMeetingHandler(mockService, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/meetings/"+tt.meetingID+"/reconfirm", nil)
+			req.SetPathValue("id", tt.meetingID)
+			w := httptest.NewRecorder()
+
+			err := handler.RequestReconfirmation(w, req)
+
+			if tt.expectedError {
+				assert.Error(t, err)
+				if appErr, ok := err.(*errors.AppError); ok {
+					assert.Equal(t, tt.expectedStatus, appErr.HTTPStatusCode())
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, w.Code)
+
+				var resp api.RequestReconfirmationResponse
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Len(t, resp.Stale, 1)
+				assert.True(t, resp.Stale[0].Stale)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRespondToInvitation(t *testing.T) {
+	mockService := new(MockMeetingService)
+	mockService.On("RespondToInvitation", "valid").Return(models.Availability{ID: "availability-1"}, nil)
+	mockService.On("RespondToInvitation", "used").Return(models.Availability{}, errors.NewConflictError("RSVP link has already been used"))
+	handler := NewMeetingHandler(mockService, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/rsvp/valid", nil)
+	req.SetPathValue("token", "valid")
+	w := httptest.NewRecorder()
+	assert.NoError(t, handler.RespondToInvitation(w, req))
+	var resp api.RespondToInvitationResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, "availability-1", resp.Availability.ID)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/rsvp/used", nil)
+	req.SetPathValue("token", "used")
+	err := handler.RespondToInvitation(httptest.NewRecorder(), req)
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusConflict, err.(*errors.AppError).HTTPStatusCode())
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGuestParticipants(t *testing.T) {
+	mockService := new(MockMeetingService)
+	mockService.On("AddGuest", "meeting-1", "vendor@partner.com", "Vendor").Return(models.GuestInvitation{MeetingID: "meeting-1", Token: "token-1"}, nil)
+	mockService.On("GetGuestMeeting", "token-1").Return(models.GuestMeeting{MeetingID: "meeting-1", Title: "Vendor sync"}, nil)
+	mockService.On("GetGuestMeeting", "expired").Return(models.GuestMeeting{}, errors.NewUnauthorizedError("Guest link has expired"))
+	mockService.On("SubmitGuestAvailability", "token-1", []models.TimeSlot(nil), true).Return(models.Availability{ID: "availability-1"}, nil)
+	handler := NewMeetingHandler(mockService, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/meetings/meeting-1/guests", bytes.NewBufferString(`{"email":"vendor@partner.com","name":"Vendor"}`))
+	req.SetPathValue("id", "meeting-1")
+	w := httptest.NewRecorder()
+	assert.NoError(t, handler.AddGuest(w, req))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var added api.AddGuestResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&added))
+	assert.Equal(t, "token-1", added.Invitation.Token)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/guest/token-1", nil)
+	req.SetPathValue("token", "token-1")
+	w = httptest.NewRecorder()
+	assert.NoError(t, handler.GetGuestMeeting(w, req))
+	var view api.GetGuestMeetingResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&view))
+	assert.Equal(t, "Vendor sync", view.Meeting.Title)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/guest/expired", nil)
+	req.SetPathValue("token", "expired")
+	err := handler.GetGuestMeeting(httptest.NewRecorder(), req)
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusUnauthorized, err.(*errors.AppError).HTTPStatusCode())
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/api/guest/token-1/availability", bytes.NewBufferString(`{"tentative":true}`))
+	req.SetPathValue("token", "token-1")
+	w = httptest.NewRecorder()
+	assert.NoError(t, handler.SubmitGuestAvailability(w, req))
+	var submitted api.SubmitGuestAvailabilityResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&submitted))
+	assert.Equal(t, "availability-1", submitted.Availability.ID)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestImportAvailability(t *testing.T) {
+	busyTime := models.TimeSlot{
+		StartTime: time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC),
+	}
+	mockService := new(MockMeetingService)
+	sameBusyTime := mock.MatchedBy(func(busy []models.TimeSlot) bool {
+		return len(busy) == 1 && busy[0].StartTime.Equal(busyTime.StartTime) && busy[0].EndTime.Equal(busyTime.EndTime)
+	})
+	mockService.On("ImportAvailability", "user-1", "meeting-1", sameBusyTime, true).Return(models.Availability{ID: "availability-1"}, nil)
+	handler := NewMeetingHandler(mockService, nil)
+
+	post := func(query, contentType, body string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodPost, "/api/availabilities/import"+query, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		return w, handler.ImportAvailability(w, req)
+	}
+
+	ics := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART;TZID=Europe/Paris:20300107T100000\r\nDTEND;TZID=Europe/Paris:20300107T110000\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	w, err := post("?userId=user-1&meetingId=meeting-1&tentative=true", "text/calendar", ics)
+	assert.NoError(t, err)
+	var resp api.ImportAvailabilityResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, "availability-1", resp.Availability.ID)
+	assert.Equal(t, 1, resp.BusyTimes)
+
+	// Times without a time zone are read in timeZone
+	w, err = post("?userId=user-1&meetingId=meeting-1&tentative=true&timeZone=Europe/Paris", "text/plain", "start,end\n2030-01-07 10:00,2030-01-07 11:00\n")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	for _, query := range []string{"?userId=user-1", "?userId=user-1&meetingId=meeting-1&timeZone=Mars/Olympus", "?userId=user-1&meetingId=meeting-1&format=xlsx"} {
+		_, err = post(query, "text/csv", "start,end\n")
+		if assert.Error(t, err, query) {
+			assert.Equal(t, http.StatusBadRequest, err.(*errors.AppError).HTTPStatusCode(), query)
+		}
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestImportCalendarAvailability(t *testing.T) {
+	mockService := new(MockMeetingService)
+	mockService.On("ImportCalendarAvailability", "user-1", "meeting-1", "google", "token", false).Return(models.Availability{ID: "availability-1"}, 2, nil)
+	mockService.On("ImportCalendarAvailability", "user-1", "meeting-1", "outlook", "token", false).
+		Return(models.Availability{}, 0, errors.NewValidationError("Unknown calendar provider: outlook", ""))
+	handler := NewMeetingHandler(mockService, nil)
+
+	post := func(query, body string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodPost, "/api/meetings/meeting-1/availabilities/import"+query, bytes.NewBufferString(body))
+		req.SetPathValue("id", "meeting-1")
+		w := httptest.NewRecorder()
+		return w, handler.ImportCalendarAvailability(w, req)
+	}
+
+	w, err := post("?provider=google", `{"userId":"user-1","accessToken":"token"}`)
+	assert.NoError(t, err)
+	var resp api.ImportAvailabilityResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, "availability-1", resp.Availability.ID)
+	assert.Equal(t, 2, resp.BusyTimes)
+
+	for _, tt := range []struct{ query, body string }{
+		{"", `{"userId":"user-1","accessToken":"token"}`},
+		{"?provider=outlook", `{"userId":"user-1","accessToken":"token"}`},
+		{"?provider=google", `{"accessToken":"token"}`},
+		{"?provider=google", `not json`},
+	} {
+		_, err = post(tt.query, tt.body)
+		if assert.Error(t, err, tt.query+" "+tt.body) {
+			assert.Equal(t, http.StatusBadRequest, err.(*errors.AppError).HTTPStatusCode())
+		}
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetPollQRCode(t *testing.T) {
+	mockService := new(MockMeetingService)
+	mockService.On("GetPollLink", "meeting-1").Return("https://meetsync.example.com/poll/meeting-1", nil)
+	mockService.On("GetPollLink", "missing").Return("", errors.NewNotFoundError("Meeting not found"))
+	handler := NewMeetingHandler(mockService, nil)
+
+	get := func(meetingID, query string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodGet, "/api/meetings/"+meetingID+"/poll/qr"+query, nil)
+		req.SetPathValue("id", meetingID)
+		w := httptest.NewRecorder()
+		return w, handler.GetPollQRCode(w, req)
+	}
+
+	w, err := get("meeting-1", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	assert.Equal(t, "https://meetsync.example.com/poll/meeting-1", w.Header().Get("X-Poll-URL"))
+	assert.True(t, bytes.HasPrefix(w.Body.Bytes(), []byte("\x89PNG")))
+
+	w, err = get("meeting-1", "?format=svg")
+	assert.NoError(t, err)
+	assert.Equal(t, "image/svg+xml", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "<svg")
+
+	for _, query := range []string{"?format=gif", "?size=10", "?size=big"} {
+		_, err = get("meeting-1", query)
+		if assert.Error(t, err, query) {
+			assert.Equal(t, http.StatusBadRequest, err.(*errors.AppError).HTTPStatusCode(), query)
+		}
+	}
+
+	_, err = get("missing", "")
+	assert.Error(t, err)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestSearchMeetings(t *testing.T) {
+	mockService := new(MockMeetingService)
+	mockService.On("SearchMeetings", "planning", 5).Return([]models.SearchResult{{Meeting: models.Meeting{ID: "meeting-1"}, Score: 3, MatchedFields: []string{"title"}}}, nil)
+	mockService.On("SearchMeetings", "", 0).Return([]models.SearchResult{}, errors.NewValidationError("Search query is required", ""))
+	handler := NewMeetingHandler(mockService, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/search?q=planning&limit=5", nil)
+	w := httptest.NewRecorder()
+	assert.NoError(t, handler.SearchMeetings(w, req))
+	var resp api.SearchMeetingsResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	if assert.Len(t, resp.Results, 1) {
+		assert.Equal(t, "meeting-1", resp.Results[0].Meeting.ID)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/search", nil)
+	err := handler.SearchMeetings(httptest.NewRecorder(), req)
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusBadRequest, err.(*errors.AppError).HTTPStatusCode())
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestMeetingTags(t *testing.T) {
+	mockService := new(MockMeetingService)
+	mockService.On("AddMeetingTags", "meeting-1", []string{"hiring"}).Return(models.Meeting{ID: "meeting-1", Tags: []string{"hiring"}}, nil)
+	mockService.On("RemoveMeetingTag", "meeting-1", "hiring").Return(models.Meeting{ID: "meeting-1"}, nil)
+	mockService.On("GetTagStats").Return([]models.TagStats{{Tag: "hiring", Meetings: 1}}, nil)
+	handler := NewMeetingHandler(mockService, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/meetings/meeting-1/tags", bytes.NewBufferString(`{"tags":["hiring"]}`))
+	req.SetPathValue("id", "meeting-1")
+	w := httptest.NewRecorder()
+	assert.NoError(t, handler.AddMeetingTags(w, req))
+	var resp api.UpdateMeetingTagsResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, []string{"hiring"}, resp.Meeting.Tags)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/meetings/meeting-1/tags/hiring", nil)
+	req.SetPathValue("id", "meeting-1")
+	req.SetPathValue("tag", "hiring")
+	w = httptest.NewRecorder()
+	assert.NoError(t, handler.RemoveMeetingTag(w, req))
+	assert.NotContains(t, w.Body.String(), "tags")
+
+	req = httptest.NewRequest(http.MethodGet, "/api/stats/tags", nil)
+	w = httptest.NewRecorder()
+	assert.NoError(t, handler.GetTagStats(w, req))
+	var stats api.GetTagStatsResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&stats))
+	assert.Len(t, stats.Tags, 1)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/meetings/meeting-1/tags", bytes.NewBufferString(`not json`))
+	req.SetPathValue("id", "meeting-1")
+	err := handler.AddMeetingTags(httptest.NewRecorder(), req)
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusBadRequest, err.(*errors.AppError).HTTPStatusCode())
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestPublishMeeting(t *testing.T) {
+	meetingID := uuid.New().String()
+
+	tests := []struct {
+		name           string
+		meetingID      string
+		setupMock      func(*MockMeetingService)
+		expectedStatus int
+		expectedError  bool
+	}{
+		{
+			name:      "successful publish",
+			meetingID: meetingID,
+			setupMock: func(m *MockMeetingService) {
+				m.On("PublishMeeting", meetingID).Return(models.Meeting{ID: meetingID, Status: models.MeetingStatusPending}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "already published",
+			meetingID: meetingID,
+			setupMock: func(m *MockMeetingService) {
+				m.On("PublishMeeting", meetingID).Return(models.Meeting{}, errors.NewConflictError("Meeting is already published"))
+			},
+			expectedStatus: http.StatusConflict,
+			expectedError:  true,
+		},
+		{
+			name:           "missing meeting ID",
+			meetingID:      "",
+			setupMock:      func(m *MockMeetingService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockMeetingService)
+			tt.setupMock(mockService)
+			handler := NewMeetingHandler(mockService, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/meetings/"+tt.meetingID+"/publish", nil)
+			req.SetPathValue("id", tt.meetingID)
+			w := httptest.NewRecorder()
+
+			err := handler.PublishMeeting(w, req)
+
+			if tt.expectedError {
+				assert.Error(t, err)
+				if appErr, ok := err.(*errors.AppError); ok {
+					assert.Equal(t, tt.expectedStatus, appErr.HTTPStatusCode())
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, w.Code)
+
+				var resp api.PublishMeetingResponse
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, models.MeetingStatusPending, resp.Meeting.Status)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestConfirmMeeting(t *testing.T) {
+	meetingID := uuid.New().String()
+	slot := models.TimeSlot{
+		StartTime: time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2030, 1, 7, 11, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*MockMeetingService)
+		expectedStatus int
+		expectedError  bool
+	}{
+		{
+			name: "successful confirmation",
+			body: `{"slot":{"startTime":"2030-01-07T10:00:00Z","endTime":"2030-01-07T11:00:00Z"}}`,
+			setupMock: func(m *MockMeetingService) {
+				m.On("ConfirmMeeting", meetingID, slot).Return(models.Meeting{ID: meetingID, Status: models.MeetingStatusConfirmed, ConfirmedSlot: &slot}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "already confirmed",
+			body: `{"slot":{"startTime":"2030-01-07T10:00:00Z","endTime":"2030-01-07T11:00:00Z"}}`,
+			setupMock: func(m *MockMeetingService) {
+				m.On("ConfirmMeeting", meetingID, slot).Return(models.Meeting{}, errors.NewConflictError("Meeting is already confirmed"))
+			},
+			expectedStatus: http.StatusConflict,
+			expectedError:  true,
+		},
+		{
+			name:           "missing slot",
+			body:           `{}`,
+			setupMock:      func(m *MockMeetingService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+		{
+			name:           "invalid body",
+			body:           `{`,
+			setupMock:      func(m *MockMeetingService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockMeetingService)
+			tt.setupMock(mockService)
+			handler := NewMeetingHandler(mockService, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/meetings/"+meetingID+"/confirm", bytes.NewBufferString(tt.body))
+			req.SetPathValue("id", meetingID)
+			w := httptest.NewRecorder()
+
+			err := handler.ConfirmMeeting(w, req)
+
+			if tt.expectedError {
+				if assert.Error(t, err) {
+					assert.Equal(t, tt.expectedStatus, err.(*errors.AppError).HTTPStatusCode())
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, w.Code)
+
+				var resp api.ConfirmMeetingResponse
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, models.MeetingStatusConfirmed, resp.Meeting.Status)
+				assert.NotNil(t, resp.Meeting.ConfirmedSlot)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCancelMeeting(t *testing.T) {
+	mockService := new(MockMeetingService)
+	mockService.On("CancelMeeting", "meeting-1").Return(models.Meeting{ID: "meeting-1", Status: models.MeetingStatusCancelled}, nil)
+	handler := NewMeetingHandler(mockService, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/meetings/meeting-1/cancel", nil)
+	req.SetPathValue("id", "meeting-1")
+	w := httptest.NewRecorder()
+	assert.NoError(t, handler.CancelMeeting(w, req))
+	var resp api.CancelMeetingResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, models.MeetingStatusCancelled, resp.Meeting.Status)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestExportCalendarEvent(t *testing.T) {
+	start := time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)
+	mockService := new(MockMeetingService)
+	mockService.On("GetCalendarEvent", "meeting-1").Return(models.CalendarEvent{
+		MeetingID: "meeting-1",
+		Title:     "Planning",
+		Slot:      models.TimeSlot{StartTime: start, EndTime: start.Add(time.Hour)},
+		Status:    models.MeetingStatusConfirmed,
+		Organizer: models.User{Name: "Organizer", Email: "organizer@example.com"},
+	}, nil)
+	mockService.On("GetCalendarEvent", "missing").Return(models.CalendarEvent{}, errors.NewNotFoundError("Meeting not found"))
+	handler := NewMeetingHandler(mockService, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/meetings/meeting-1/ical", nil)
+	req.SetPathValue("id", "meeting-1")
+	w := httptest.NewRecorder()
+	assert.NoError(t, handler.ExportCalendarEvent(w, req))
+	assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="meeting-meeting-1.ics"`, w.Header().Get("Content-Disposition"))
+	assert.Contains(t, w.Body.String(), "\r\nDTSTART:20300107T100000Z\r\n")
+
+	req = httptest.NewRequest(http.MethodGet, "/api/meetings/missing/ical", nil)
+	req.SetPathValue("id", "missing")
+	err := handler.ExportCalendarEvent(httptest.NewRecorder(), req)
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusNotFound, err.(*errors.AppError).HTTPStatusCode())
+	}
+}
+
+func TestProposals(t *testing.T) {
+	slot := models.TimeSlot{
+		StartTime: time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2030, 1, 7, 11, 0, 0, 0, time.UTC),
+	}
+	proposal := models.Proposal{ID: "proposal-1", ParticipantID: "user-1", Slot: slot, Status: models.ProposalPending}
+	mockService := new(MockMeetingService)
+	mockService.On("ProposeAlternative", "meeting-1", "user-1", slot, "Conflict").Return(proposal, nil)
+	accepted := proposal
+	accepted.Status = models.ProposalAccepted
+	mockService.On("DecideProposal", "meeting-1", "proposal-1", true).Return(models.Meeting{ID: "meeting-1", ConfirmedSlot: &slot}, accepted, nil)
+	handler := NewMeetingHandler(mockService, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/meetings/meeting-1/proposals",
+		bytes.NewBufferString(`{"participantId":"user-1","slot":{"startTime":"2030-01-07T10:00:00Z","endTime":"2030-01-07T11:00:00Z"},"reason":"Conflict"}`))
+	req.SetPathValue("id", "meeting-1")
+	w := httptest.NewRecorder()
+	assert.NoError(t, handler.ProposeAlternative(w, req))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var proposed api.ProposeAlternativeResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&proposed))
+	assert.Equal(t, "proposal-1", proposed.Proposal.ID)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/meetings/meeting-1/proposals/proposal-1/decision", bytes.NewBufferString(`{"decision":"accept"}`))
+	req.SetPathValue("id", "meeting-1")
+	req.SetPathValue("proposalId", "proposal-1")
+	w = httptest.NewRecorder()
+	assert.NoError(t, handler.DecideProposal(w, req))
+	var decided api.DecideProposalResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&decided))
+	assert.Equal(t, models.ProposalAccepted, decided.Proposal.Status)
+
+	// Decisions must be accept or decline, and proposals name the participant
+	req = httptest.NewRequest(http.MethodPost, "/api/meetings/meeting-1/proposals/proposal-1/decision", bytes.NewBufferString(`{"decision":"maybe"}`))
+	req.SetPathValue("id", "meeting-1")
+	req.SetPathValue("proposalId", "proposal-1")
+	err := handler.DecideProposal(httptest.NewRecorder(), req)
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusBadRequest, err.(*errors.AppError).HTTPStatusCode())
+	}
+	req = httptest.NewRequest(http.MethodPost, "/api/meetings/meeting-1/proposals", bytes.NewBufferString(`{"slot":{}}`))
+	req.SetPathValue("id", "meeting-1")
+	err = handler.ProposeAlternative(httptest.NewRecorder(), req)
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusBadRequest, err.(*errors.AppError).HTTPStatusCode())
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestListMeetings(t *testing.T) {
+	mockService := new(MockMeetingService)
+	mockService.On("ListMeetings", "", 0, models.MeetingFilter{}).Return([]models.Meeting{{ID: "meeting-1"}, {ID: "meeting-2"}}, "next-cursor", nil)
+	mockService.On("ListMeetings", "next-cursor", 2, models.MeetingFilter{}).Return([]models.Meeting{{ID: "meeting-3"}}, "", nil)
+	mockService.On("ListMeetings", "", 0, models.MeetingFilter{Tags: []string{"1on1", "hiring"}}).Return([]models.Meeting{{ID: "meeting-2"}}, "", nil)
+	mockService.On("ListMeetings", "", 0, models.MeetingFilter{
+		OrganizerID:   "organizer-id",
+		ParticipantID: "user-id",
+		Status:        models.MeetingStatusPending,
+		From:          time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC),
+		To:            time.Date(2030, 1, 14, 9, 0, 0, 0, time.UTC),
+	}).Return([]models.Meeting{{ID: "meeting-1"}}, "", nil)
+	handler := NewMeetingHandler(mockService, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/meetings", nil)
+	w := httptest.NewRecorder()
+	assert.NoError(t, handler.ListMeetings(w, req))
+	var resp api.ListMeetingsResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Len(t, resp.Meetings, 2)
+	assert.Equal(t, "next-cursor", resp.NextCursor)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/meetings?cursor=next-cursor&limit=2", nil)
+	w = httptest.NewRecorder()
+	assert.NoError(t, handler.ListMeetings(w, req))
+	assert.NotContains(t, w.Body.String(), "nextCursor")
+
+	req = httptest.NewRequest(http.MethodGet, "/api/meetings?tag=1on1&tag=hiring", nil)
+	w = httptest.NewRecorder()
+	assert.NoError(t, handler.ListMeetings(w, req))
+	assert.Contains(t, w.Body.String(), "meeting-2")
+
+	req = httptest.NewRequest(http.MethodGet, "/api/meetings?organizerId=organizer-id&participantId=user-id&status=pending&from=2030-01-07T09:00:00Z&to=2030-01-14T09:00:00Z", nil)
+	w = httptest.NewRecorder()
+	assert.NoError(t, handler.ListMeetings(w, req))
+	assert.Contains(t, w.Body.String(), "meeting-1")
+
+	for _, target := range []string{"/api/meetings?limit=abc", "/api/meetings?from=tomorrow", "/api/meetings?to=2030-01-14"} {
+		err := handler.ListMeetings(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+		if assert.Error(t, err, target) {
+			assert.Equal(t, http.StatusBadRequest, err.(*errors.AppError).HTTPStatusCode())
+		}
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUpsertExternalMeeting(t *testing.T) {
+	changedAt := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	body := api.UpsertExternalMeetingRequest{
+		CreateMeetingRequest: api.CreateMeetingRequest{Title: "Synced Meeting", OrganizerID: "organizer-id", EstimatedDuration: 60},
+		ExternalUpdatedAt:    changedAt,
+	}
+
+	tests := []struct {
+		name           string
+		body           interface{}
+		setupMock      func(*MockMeetingService)
+		expectedStatus int
+		expectedError  bool
+	}{
+		{
+			name: "created",
+			body: body,
+			setupMock: func(m *MockMeetingService) {
+				m.On("UpsertExternalMeeting", "event-1", "organizer-id", changedAt).Return(models.Meeting{ID: "meeting-id", ExternalID: "event-1"}, true, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "updated",
+			body: body,
+			setupMock: func(m *MockMeetingService) {
+				m.On("UpsertExternalMeeting", "event-1", "organizer-id", changedAt).Return(models.Meeting{ID: "meeting-id", ExternalID: "event-1"}, false, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "stale change",
+			body: body,
+			setupMock: func(m *MockMeetingService) {
+				m.On("UpsertExternalMeeting", "event-1", "organizer-id", changedAt).Return(models.Meeting{}, false, errors.NewConflictError("A newer change of the meeting was already synced"))
+			},
+			expectedStatus: http.StatusConflict,
+			expectedError:  true,
+		},
+		{
+			name:           "invalid body",
+			body:           "not a meeting",
+			setupMock:      func(m *MockMeetingService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockMeetingService)
+			tt.setupMock(mockService)
+			handler := NewMeetingHandler(mockService, nil)
+
+			reqBody, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(http.MethodPut, "/api/meetings/external/event-1", bytes.NewBuffer(reqBody))
+			req.SetPathValue("externalId", "event-1")
+			w := httptest.NewRecorder()
+
+			err := handler.UpsertExternalMeeting(w, req)
+
+			if tt.expectedError {
+				assert.Error(t, err)
+				if appErr, ok := err.(*errors.AppError); ok {
+					assert.Equal(t, tt.expectedStatus, appErr.HTTPStatusCode())
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, w.Code)
+
+				var resp api.CreateMeetingResponse
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, "event-1", resp.Meeting.ExternalID)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListAvailabilities(t *testing.T) {
+	mockService := new(MockMeetingService)
+	filter := models.AvailabilityFilter{ExternalID: "ats-feedback-3", Metadata: models.Metadata{"interviewer": "yes"}}
+	mockService.On("ListAvailabilities", "meeting-id", filter).Return([]models.Availability{{ID: "availability-id", ExternalID: "ats-feedback-3"}}, nil)
+	handler := NewMeetingHandler(mockService, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/meetings/meeting-id/availabilities?externalId=ats-feedback-3&metadata.interviewer=yes", nil)
+	req.SetPathValue("id", "meeting-id")
+	w := httptest.NewRecorder()
+
+	assert.NoError(t, handler.ListAvailabilities(w, req))
+	var resp api.ListAvailabilitiesResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Len(t, resp.Availabilities, 1)
+	mockService.AssertExpectations(t)
+}
+
+// TestPathParameters checks that IDs are taken from the route pattern and unescaped,
+// so IDs containing reserved characters reach the service intact
+func TestPathParameters(t *testing.T) {
+	mockService := new(MockMeetingService)
+	handler := NewMeetingHandler(mockService, nil)
+	mockService.On("DeleteMeeting", "team sync/2024").Return(nil)
+	mockService.On("DeleteAvailability", "a+b?c#d").Return(nil)
+	mockService.On("UpdateAvailability", "ünïcode", mock.Anything, false).Return(models.Availability{ID: "ünïcode"}, nil)
+
+	var handlerErr error
+	mux := http.NewServeMux()
+	route := func(pattern string, h func(http.ResponseWriter, *http.Request) error) {
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) { handlerErr = h(w, r) })
+	}
+	route("DELETE /api/meetings/{id}", handler.DeleteMeeting)
+	route("DELETE /api/availabilities/{id}", handler.DeleteAvailability)
+	route("PUT /api/availabilities/{id}", handler.UpdateAvailability)
+
+	tests := []struct {
+		method string
+		target string
+		body   string
+	}{
+		{http.MethodDelete, "/api/meetings/team%20sync%2F2024", ""},
+		{http.MethodDelete, "/api/availabilities/a+b%3Fc%23d", ""},
+		{http.MethodPut, "/api/availabilities/%C3%BCn%C3%AFcode", `{"availableSlots":[]}`},
+	}
+	for _, tt := range tests {
+		handlerErr = nil
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, bytes.NewBufferString(tt.body)))
+		assert.NoError(t, handlerErr, tt.target)
+	}
+	mockService.AssertExpectations(t)
+}
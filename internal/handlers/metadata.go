@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/url"
+	"strings"
+
+	"meetsync/internal/models"
+)
+
+// metadataParamPrefix prefixes the query parameters filtering listings by metadata, as in
+// ?metadata.crmId=42
+const metadataParamPrefix = "metadata."
+
+// metadataFilter reads the metadata.<key>=<value> query parameters of a listing
+func metadataFilter(query url.Values) models.Metadata {
+	var filter models.Metadata
+	for param, values := range query {
+		key, found := strings.CutPrefix(param, metadataParamPrefix)
+		if !found || key == "" || len(values) == 0 {
+			continue
+		}
+		if filter == nil {
+			filter = make(models.Metadata)
+		}
+		filter[key] = values[0]
+	}
+	return filter
+}
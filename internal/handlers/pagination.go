@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"meetsync/pkg/errors"
+)
+
+// pageParams reads the cursor and limit query parameters of a paginated listing
+func pageParams(r *http.Request) (string, int, error) {
+	cursor := r.URL.Query().Get("cursor")
+
+	limit := 0
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			return "", 0, errors.NewValidationError("Invalid limit", "limit must be a positive integer")
+		}
+		limit = parsed
+	}
+	return cursor, limit, nil
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"meetsync/internal/api"
+	"meetsync/internal/interfaces"
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+)
+
+// SchedulingHandler handles requests for finding times outside of a meeting
+type SchedulingHandler struct {
+	service interfaces.SchedulingService
+}
+
+// NewSchedulingHandler creates a new SchedulingHandler serving requests through service
+func NewSchedulingHandler(service interfaces.SchedulingService) *SchedulingHandler {
+	return &SchedulingHandler{service: service}
+}
+
+// FindTimes handles ranking candidate slots for a group of users without creating a meeting
+func (h *SchedulingHandler) FindTimes(w http.ResponseWriter, r *http.Request) error {
+	var req api.SchedulingQueryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+
+	candidates, err := h.service.FindTimes(models.SchedulingQuery{
+		ParticipantIDs: req.ParticipantIDs,
+		Duration:       req.Duration,
+		Window:         req.Window,
+		TimeZone:       req.TimeZone,
+		WorkingHours:   req.WorkingHours,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp := api.SchedulingQueryResponse{
+		Candidates: candidates,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// icsTimeLayout formats times in UTC in iCalendar files
+const icsTimeLayout = "20060102T150405Z"
+
+// GetFreeBusy handles listing when a user is busy between the from and to query
+// parameters, as JSON or, with format=ics, as an iCalendar VFREEBUSY component
+func (h *SchedulingHandler) GetFreeBusy(w http.ResponseWriter, r *http.Request) error {
+	userID := r.PathValue("id")
+	if userID == "" {
+		return errors.NewValidationError("User ID is required", "")
+	}
+
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format != "" && format != "json" && format != "ics" {
+		return errors.NewValidationError("Invalid format", "format must be json or ics")
+	}
+	from, err := time.Parse(time.RFC3339, query.Get("from"))
+	if err != nil {
+		return errors.NewValidationError("Invalid from", "from must be an RFC 3339 time")
+	}
+	to, err := time.Parse(time.RFC3339, query.Get("to"))
+	if err != nil {
+		return errors.NewValidationError("Invalid to", "to must be an RFC 3339 time")
+	}
+
+	freeBusy, err := h.service.FreeBusy(userID, from, to)
+	if err != nil {
+		return err
+	}
+
+	if format == "ics" {
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		_, err := w.Write([]byte(freeBusyICS(freeBusy, time.Now())))
+		return err
+	}
+
+	resp := api.FreeBusyResponse{
+		UserID: freeBusy.UserID,
+		From:   freeBusy.From,
+		To:     freeBusy.To,
+		Busy:   make([]api.FreeBusyPeriod, 0, len(freeBusy.Busy)),
+	}
+	for _, slot := range freeBusy.Busy {
+		resp.Busy = append(resp.Busy, api.FreeBusyPeriod{Start: slot.StartTime, End: slot.EndTime})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// freeBusyICS renders free/busy times as an iCalendar VFREEBUSY component (RFC 5545)
+func freeBusyICS(freeBusy models.FreeBusy, now time.Time) string {
+	var b strings.Builder
+	line := func(format string, args ...any) {
+		fmt.Fprintf(&b, format+"\r\n", args...)
+	}
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//MeetSync//Free Busy//EN")
+	line("METHOD:PUBLISH")
+	line("BEGIN:VFREEBUSY")
+	line("UID:%s-%s@meetsync", freeBusy.UserID, freeBusy.From.UTC().Format(icsTimeLayout))
+	line("DTSTAMP:%s", now.UTC().Format(icsTimeLayout))
+	line("DTSTART:%s", freeBusy.From.UTC().Format(icsTimeLayout))
+	line("DTEND:%s", freeBusy.To.UTC().Format(icsTimeLayout))
+	for _, slot := range freeBusy.Busy {
+		line("FREEBUSY;FBTYPE=BUSY:%s/%s", slot.StartTime.UTC().Format(icsTimeLayout), slot.EndTime.UTC().Format(icsTimeLayout))
+	}
+	line("END:VFREEBUSY")
+	line("END:VCALENDAR")
+	return b.String()
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"meetsync/internal/api"
+	"meetsync/internal/interfaces"
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+)
+
+// MockSchedulingService is a mock implementation of SchedulingService
+type MockSchedulingService struct {
+	mock.Mock
+}
+
+var _ interfaces.SchedulingService = (*MockSchedulingService)(nil) // Verify MockSchedulingService implements SchedulingService interface
+
+func (m *MockSchedulingService) FindTimes(query models.SchedulingQuery) ([]models.RecommendedSlot, error) {
+	args := m.Called(query)
+	return args.Get(0).([]models.RecommendedSlot), args.Error(1)
+}
+
+func (m *MockSchedulingService) FreeBusy(userID string, from, to time.Time) (models.FreeBusy, error) {
+	args := m.Called(userID, from, to)
+	return args.Get(0).(models.FreeBusy), args.Error(1)
+}
+
+func TestFindTimes(t *testing.T) {
+	start := time.Date(2025, 1, 14, 9, 0, 0, 0, time.UTC)
+	window := models.TimeSlot{StartTime: start, EndTime: start.Add(8 * time.Hour)}
+	query := models.SchedulingQuery{
+		ParticipantIDs: []string{"alice", "bob"},
+		Duration:       30,
+		Window:         window,
+		TimeZone:       "Europe/Paris",
+		WorkingHours:   &models.WorkingHours{Start: "09:00", End: "17:00"},
+	}
+
+	tests := []struct {
+		name           string
+		body           interface{}
+		setupMock      func(*MockSchedulingService)
+		expectedStatus int
+		expectedError  bool
+	}{
+		{
+			name: "successful query",
+			body: api.SchedulingQueryRequest{
+				ParticipantIDs: query.ParticipantIDs,
+				Duration:       query.Duration,
+				Window:         query.Window,
+				TimeZone:       query.TimeZone,
+				WorkingHours:   query.WorkingHours,
+			},
+			setupMock: func(m *MockSchedulingService) {
+				m.On("FindTimes", query).Return([]models.RecommendedSlot{
+					{TimeSlot: models.TimeSlot{StartTime: start, EndTime: start.Add(30 * time.Minute)}, AvailableCount: 2, TotalParticipants: 2},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "unknown participant",
+			body: api.SchedulingQueryRequest{
+				ParticipantIDs: query.ParticipantIDs,
+				Duration:       query.Duration,
+				Window:         query.Window,
+				TimeZone:       query.TimeZone,
+				WorkingHours:   query.WorkingHours,
+			},
+			setupMock: func(m *MockSchedulingService) {
+				m.On("FindTimes", query).Return([]models.RecommendedSlot(nil), errors.NewNotFoundError("Participant not found: bob"))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedError:  true,
+		},
+		{
+			name:           "invalid body",
+			body:           "not a query",
+			setupMock:      func(m *MockSchedulingService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockSchedulingService)
+			tt.setupMock(mockService)
+			handler := &SchedulingHandler{service: mockService}
+
+			body, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(http.MethodPost, "/api/scheduling/query", bytes.NewBuffer(body))
+			w := httptest.NewRecorder()
+
+			err := handler.FindTimes(w, req)
+
+			if tt.expectedError {
+				assert.Error(t, err)
+				if appErr, ok := err.(*errors.AppError); ok {
+					assert.Equal(t, tt.expectedStatus, appErr.HTTPStatusCode())
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, w.Code)
+
+				var resp api.SchedulingQueryResponse
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Len(t, resp.Candidates, 1)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetFreeBusy(t *testing.T) {
+	from := time.Date(2025, 1, 14, 8, 0, 0, 0, time.UTC)
+	to := from.Add(8 * time.Hour)
+	freeBusy := models.FreeBusy{
+		UserID: "alice",
+		From:   from,
+		To:     to,
+		Busy:   []models.TimeSlot{{StartTime: from.Add(time.Hour), EndTime: from.Add(2 * time.Hour)}},
+	}
+	window := "from=2025-01-14T08:00:00Z&to=2025-01-14T16:00:00Z"
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*MockSchedulingService)
+		expectedStatus int
+		expectedError  bool
+		expectedBody   string
+	}{
+		{
+			name:  "json",
+			query: window,
+			setupMock: func(m *MockSchedulingService) {
+				m.On("FreeBusy", "alice", from, to).Return(freeBusy, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"busy":[{"start":"2025-01-14T09:00:00Z","end":"2025-01-14T10:00:00Z"}]`,
+		},
+		{
+			name:  "ics",
+			query: window + "&format=ics",
+			setupMock: func(m *MockSchedulingService) {
+				m.On("FreeBusy", "alice", from, to).Return(freeBusy, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "BEGIN:VFREEBUSY\r\n",
+		},
+		{
+			name:  "unknown user",
+			query: window,
+			setupMock: func(m *MockSchedulingService) {
+				m.On("FreeBusy", "alice", from, to).Return(models.FreeBusy{}, errors.NewNotFoundError("User not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedError:  true,
+		},
+		{
+			name:           "missing window",
+			query:          "from=2025-01-14T08:00:00Z",
+			setupMock:      func(m *MockSchedulingService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+		{
+			name:           "invalid format",
+			query:          window + "&format=xml",
+			setupMock:      func(m *MockSchedulingService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockSchedulingService)
+			tt.setupMock(mockService)
+			handler := &SchedulingHandler{service: mockService}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/users/alice/freebusy?"+tt.query, nil)
+			req.SetPathValue("id", "alice")
+			w := httptest.NewRecorder()
+
+			err := handler.GetFreeBusy(w, req)
+
+			if tt.expectedError {
+				assert.Error(t, err)
+				if appErr, ok := err.(*errors.AppError); ok {
+					assert.Equal(t, tt.expectedStatus, appErr.HTTPStatusCode())
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, w.Code)
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFreeBusyICS(t *testing.T) {
+	from := time.Date(2025, 1, 14, 9, 0, 0, 0, time.FixedZone("CET", 3600))
+	ics := freeBusyICS(models.FreeBusy{
+		UserID: "alice",
+		From:   from,
+		To:     from.Add(8 * time.Hour),
+		Busy:   []models.TimeSlot{{StartTime: from.Add(time.Hour), EndTime: from.Add(2 * time.Hour)}},
+	}, from)
+
+	assert.Contains(t, ics, "DTSTART:20250114T080000Z\r\nDTEND:20250114T160000Z\r\n")
+	assert.Contains(t, ics, "FREEBUSY;FBTYPE=BUSY:20250114T090000Z/20250114T100000Z\r\n")
+	assert.True(t, strings.HasSuffix(ics, "END:VFREEBUSY\r\nEND:VCALENDAR\r\n"))
+}
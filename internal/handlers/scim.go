@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"meetsync/internal/interfaces"
+	"meetsync/internal/middleware"
+	"meetsync/internal/models"
+	"meetsync/internal/scim"
+	"meetsync/pkg/errors"
+	"meetsync/pkg/logs"
+)
+
+const (
+	// defaultSCIMPageSize is the number of users listed when count is not given
+	defaultSCIMPageSize = 100
+	// maxSCIMPageSize caps the number of users listed at once
+	maxSCIMPageSize = 1000
+)
+
+// SCIMHandler handles SCIM 2.0 provisioning requests from identity providers
+type SCIMHandler struct {
+	service   interfaces.UserService
+	publicURL string
+}
+
+// NewSCIMHandler creates a new SCIMHandler serving resource locations under publicURL
+func NewSCIMHandler(service interfaces.UserService, publicURL string) *SCIMHandler {
+	return &SCIMHandler{
+		service:   service,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+	}
+}
+
+// SCIMErrors wraps a SCIM handler so client errors are reported as SCIM error
+// responses. Internal errors are left to WithErrorHandling.
+func SCIMErrors(handler middleware.ErrorHandler) middleware.ErrorHandler {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		err := handler(w, r)
+		appErr, ok := errors.AsAppError(err)
+		if err == nil || !ok || appErr.Type == errors.ErrorTypeInternal {
+			return err
+		}
+
+		var scimType string
+		var protocolErr *scim.ProtocolError
+		if errors.As(err, &protocolErr) {
+			scimType = protocolErr.ScimType
+		}
+		logs.Warn("[%s] SCIM request error: %v", middleware.RequestID(r.Context()), appErr)
+		return writeSCIM(w, appErr.HTTPStatusCode(), scim.NewErrorResponse(appErr, scimType))
+	}
+}
+
+// ListUsers handles listing users, optionally filtered by `attribute eq "value"`
+func (h *SCIMHandler) ListUsers(w http.ResponseWriter, r *http.Request) error {
+	filter, err := scim.ParseFilter(r.URL.Query().Get("filter"))
+	if err != nil {
+		return err
+	}
+	// Out of range paging parameters are clamped rather than rejected, per RFC 7644
+	startIndex := max(queryInt(r, "startIndex", 1), 1)
+	count := min(max(queryInt(r, "count", defaultSCIMPageSize), 0), maxSCIMPageSize)
+
+	users, err := h.service.ListUsers()
+	if err != nil {
+		return err
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if !users[i].CreatedAt.Equal(users[j].CreatedAt) {
+			return users[i].CreatedAt.Before(users[j].CreatedAt)
+		}
+		return users[i].ID < users[j].ID
+	})
+
+	resp := scim.ListResponse{
+		Schemas:    []string{scim.SchemaListResponse},
+		StartIndex: startIndex,
+		Resources:  []scim.User{},
+	}
+	for _, user := range users {
+		if !filter.Matches(user) {
+			continue
+		}
+		resp.TotalResults++
+		if resp.TotalResults >= startIndex && len(resp.Resources) < count {
+			resp.Resources = append(resp.Resources, h.resource(user))
+		}
+	}
+	resp.ItemsPerPage = len(resp.Resources)
+
+	return writeSCIM(w, http.StatusOK, resp)
+}
+
+// GetUser handles fetching a user
+func (h *SCIMHandler) GetUser(w http.ResponseWriter, r *http.Request) error {
+	user, err := h.service.GetUserByID(r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+	return writeSCIM(w, http.StatusOK, h.resource(user))
+}
+
+// CreateUser handles provisioning a new user
+func (h *SCIMHandler) CreateUser(w http.ResponseWriter, r *http.Request) error {
+	req, err := decodeSCIMUser(r)
+	if err != nil {
+		return err
+	}
+
+	user, err := h.service.ProvisionUser(req.Provisioning())
+	if err != nil {
+		return err
+	}
+	logs.Info("Provisioned user: %s (%s)", logs.PII(user.Name), user.ID)
+
+	resource := h.resource(user)
+	w.Header().Set("Location", resource.Meta.Location)
+	return writeSCIM(w, http.StatusCreated, resource)
+}
+
+// ReplaceUser handles replacing the attributes of a user. Setting active to false
+// deactivates the user.
+func (h *SCIMHandler) ReplaceUser(w http.ResponseWriter, r *http.Request) error {
+	req, err := decodeSCIMUser(r)
+	if err != nil {
+		return err
+	}
+	return h.update(w, r.PathValue("id"), req)
+}
+
+// PatchUser handles updating some attributes of a user
+func (h *SCIMHandler) PatchUser(w http.ResponseWriter, r *http.Request) error {
+	var req scim.PatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return scim.NewError(errors.NewValidationError("Invalid request body", err.Error()), "invalidSyntax")
+	}
+
+	userID := r.PathValue("id")
+	user, err := h.service.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+	resource := h.resource(user)
+	if err := req.Apply(&resource); err != nil {
+		return err
+	}
+	return h.update(w, userID, resource)
+}
+
+// update applies the state of a SCIM user to the user with the ID and writes the result
+func (h *SCIMHandler) update(w http.ResponseWriter, userID string, resource scim.User) error {
+	input := resource.Provisioning()
+	user, err := h.service.UpdateProvisionedUser(userID, input)
+	if err != nil {
+		return err
+	}
+	if !input.Active {
+		logs.Info("Deactivated user: %s", user.ID)
+	}
+	return writeSCIM(w, http.StatusOK, h.resource(user))
+}
+
+// DeleteUser handles deprovisioning a user by deleting them
+func (h *SCIMHandler) DeleteUser(w http.ResponseWriter, r *http.Request) error {
+	userID := r.PathValue("id")
+	if err := h.service.DeleteUser(userID); err != nil {
+		return err
+	}
+	logs.Info("Deprovisioned user: %s", userID)
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// resource returns the SCIM representation of a user
+func (h *SCIMHandler) resource(user models.User) scim.User {
+	return scim.FromUser(user, h.publicURL+"/scim/v2/Users/"+user.ID)
+}
+
+// decodeSCIMUser decodes a SCIM User from a request body
+func decodeSCIMUser(r *http.Request) (scim.User, error) {
+	var user scim.User
+	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+		return scim.User{}, scim.NewError(errors.NewValidationError("Invalid request body", err.Error()), "invalidSyntax")
+	}
+	return user, nil
+}
+
+// queryInt returns an integer query parameter, or fallback when it is missing or invalid
+func queryInt(r *http.Request, name string, fallback int) int {
+	value, err := strconv.Atoi(r.URL.Query().Get(name))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// writeSCIM writes a SCIM response with the status code
+func writeSCIM(w http.ResponseWriter, status int, body any) error {
+	w.Header().Set("Content-Type", scim.ContentType)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
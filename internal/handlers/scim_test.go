@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"meetsync/internal/models"
+	"meetsync/internal/scim"
+	"meetsync/pkg/errors"
+)
+
+// serveSCIM routes a request to a SCIM handler method the way the router does
+func serveSCIM(pattern string, handler func(http.ResponseWriter, *http.Request) error, req *http.Request) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		if err := SCIMErrors(handler)(w, r); err != nil {
+			errors.WriteError(w, err)
+		}
+	})
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	return w
+}
+
+func TestSCIMHandler_ListUsers(t *testing.T) {
+	mockService := new(MockUserService)
+	now := time.Now()
+	users := []models.User{
+		{ID: "u2", Name: "John Roe", Email: "john@example.com", CreatedAt: now},
+		{ID: "u1", Name: "Jane Doe", Email: "jane@example.com", CreatedAt: now.Add(-time.Hour)},
+	}
+	mockService.On("ListUsers").Return(users, nil)
+	handler := &SCIMHandler{service: mockService, publicURL: "https://meetsync.example.com"}
+
+	req := httptest.NewRequest(http.MethodGet, "/scim/v2/Users?startIndex=2&count=5", nil)
+	w := serveSCIM("GET /scim/v2/Users", handler.ListUsers, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, scim.ContentType, w.Header().Get("Content-Type"))
+
+	var resp scim.ListResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, 2, resp.TotalResults)
+	assert.Equal(t, 1, resp.ItemsPerPage)
+	if assert.Len(t, resp.Resources, 1) {
+		assert.Equal(t, "u2", resp.Resources[0].ID)
+		assert.Equal(t, "https://meetsync.example.com/scim/v2/Users/u2", resp.Resources[0].Meta.Location)
+	}
+
+	// Identity providers look users up by userName before provisioning them
+	req = httptest.NewRequest(http.MethodGet, `/scim/v2/Users?filter=userName+eq+%22JANE%40example.com%22`, nil)
+	w = serveSCIM("GET /scim/v2/Users", handler.ListUsers, req)
+	resp = scim.ListResponse{}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, 1, resp.TotalResults)
+
+	req = httptest.NewRequest(http.MethodGet, `/scim/v2/Users?filter=title+pr`, nil)
+	w = serveSCIM("GET /scim/v2/Users", handler.ListUsers, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var scimErr scim.Error
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&scimErr))
+	assert.Equal(t, "invalidFilter", scimErr.ScimType)
+	assert.Equal(t, "400", scimErr.Status)
+}
+
+func TestSCIMHandler_CreateUser(t *testing.T) {
+	mockService := new(MockUserService)
+	input := models.UserProvisioning{ExternalID: "00u1", Name: "Jane Doe", Email: "jane@example.com", Active: true}
+	mockService.On("ProvisionUser", input).Return(models.User{ID: "u1", Name: "Jane Doe", Email: "jane@example.com", ExternalID: "00u1"}, nil).Once()
+	mockService.On("ProvisionUser", input).Return(models.User{}, errors.NewConflictError("Email is already in use")).Once()
+	handler := &SCIMHandler{service: mockService, publicURL: "https://meetsync.example.com"}
+
+	body := `{"schemas":["urn:ietf:params:scim:schemas:core:2.0:User"],"externalId":"00u1","userName":"jane@example.com","name":{"givenName":"Jane","familyName":"Doe"},"active":true}`
+	req := httptest.NewRequest(http.MethodPost, "/scim/v2/Users", strings.NewReader(body))
+	w := serveSCIM("POST /scim/v2/Users", handler.CreateUser, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "https://meetsync.example.com/scim/v2/Users/u1", w.Header().Get("Location"))
+
+	var resource scim.User
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resource))
+	assert.Equal(t, "u1", resource.ID)
+	assert.True(t, *resource.Active)
+
+	// Existing users are reported as uniqueness errors
+	req = httptest.NewRequest(http.MethodPost, "/scim/v2/Users", strings.NewReader(body))
+	w = serveSCIM("POST /scim/v2/Users", handler.CreateUser, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var scimErr scim.Error
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&scimErr))
+	assert.Equal(t, "uniqueness", scimErr.ScimType)
+
+	req = httptest.NewRequest(http.MethodPost, "/scim/v2/Users", strings.NewReader("{"))
+	w = serveSCIM("POST /scim/v2/Users", handler.CreateUser, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestSCIMHandler_PatchUser(t *testing.T) {
+	mockService := new(MockUserService)
+	user := models.User{ID: "u1", Name: "Jane Doe", Email: "jane@example.com", ExternalID: "00u1"}
+	deactivatedAt := time.Now()
+	deactivated := user
+	deactivated.DeactivatedAt = &deactivatedAt
+	mockService.On("GetUserByID", "u1").Return(user, nil)
+	mockService.On("GetUserByID", "missing").Return(models.User{}, errors.NewNotFoundError("User not found"))
+	mockService.On("UpdateProvisionedUser", "u1", models.UserProvisioning{ExternalID: "00u1", Name: "Jane Doe", Email: "jane@example.com"}).Return(deactivated, nil)
+	handler := &SCIMHandler{service: mockService}
+
+	body := `{"schemas":["urn:ietf:params:scim:api:messages:2.0:PatchOp"],"Operations":[{"op":"replace","value":{"active":false}}]}`
+	req := httptest.NewRequest(http.MethodPatch, "/scim/v2/Users/u1", strings.NewReader(body))
+	w := serveSCIM("PATCH /scim/v2/Users/{id}", handler.PatchUser, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resource scim.User
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resource))
+	assert.False(t, *resource.Active)
+
+	req = httptest.NewRequest(http.MethodPatch, "/scim/v2/Users/missing", strings.NewReader(body))
+	w = serveSCIM("PATCH /scim/v2/Users/{id}", handler.PatchUser, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	var scimErr scim.Error
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&scimErr))
+	assert.Equal(t, "404", scimErr.Status)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestSCIMHandler_DeleteUser(t *testing.T) {
+	mockService := new(MockUserService)
+	mockService.On("DeleteUser", "u1").Return(nil)
+	handler := &SCIMHandler{service: mockService}
+
+	req := httptest.NewRequest(http.MethodDelete, "/scim/v2/Users/u1", nil)
+	w := serveSCIM("DELETE /scim/v2/Users/{id}", handler.DeleteUser, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	mockService.AssertExpectations(t)
+}
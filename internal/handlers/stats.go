@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"meetsync/internal/api"
+	"meetsync/internal/metrics"
+	"meetsync/pkg/errors"
+)
+
+// StatsHandler handles scheduling statistics and metrics requests
+type StatsHandler struct {
+	sloTracker *metrics.SLOTracker
+	pool       metrics.PoolStatser
+}
+
+// NewStatsHandler creates a new StatsHandler. The statistics of pool, the connection
+// pool of a SQL storage backend, are exposed with the metrics when it is not nil.
+func NewStatsHandler(sloTracker *metrics.SLOTracker, pool metrics.PoolStatser) *StatsHandler {
+	return &StatsHandler{
+		sloTracker: sloTracker,
+		pool:       pool,
+	}
+}
+
+// GetSLOStats handles getting the scheduling SLO report
+func (h *StatsHandler) GetSLOStats(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodGet {
+		return errors.NewValidationError("Method not allowed", "Only GET method is allowed")
+	}
+
+	resp := api.GetSLOStatsResponse{
+		SLO: h.sloTracker.Report(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// GetMetrics handles exposing metrics in the Prometheus text format
+func (h *StatsHandler) GetMetrics(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodGet {
+		return errors.NewValidationError("Method not allowed", "Only GET method is allowed")
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if err := h.sloTracker.WritePrometheus(w); err != nil {
+		return errors.NewInternalError("Failed to write metrics", err)
+	}
+	if h.pool != nil {
+		if err := metrics.WritePoolPrometheus(w, h.pool); err != nil {
+			return errors.NewInternalError("Failed to write metrics", err)
+		}
+	}
+	return nil
+}
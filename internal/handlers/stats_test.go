@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"meetsync/internal/api"
+	"meetsync/internal/metrics"
+)
+
+func TestGetSLOStats(t *testing.T) {
+	tracker := metrics.NewSLOTracker()
+	tracker.RecordMeetingCreated()
+	tracker.RecordMeetingCreated()
+	tracker.RecordReschedule()
+	tracker.RecordAvailabilityResponse(time.Minute)
+
+	handler := NewStatsHandler(tracker, nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/stats/slo", nil)
+	w := httptest.NewRecorder()
+
+	err := handler.GetSLOStats(w, req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp api.GetSLOStatsResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, int64(2), resp.SLO.MeetingsCreated)
+	assert.Equal(t, int64(1), resp.SLO.MeetingsRescheduled)
+	assert.InDelta(t, 0.5, resp.SLO.RescheduleRate, 0.0001)
+	assert.InDelta(t, 60.0, resp.SLO.AverageAvailabilityLatencySeconds, 0.0001)
+}
+
+func TestGetMetrics(t *testing.T) {
+	tracker := metrics.NewSLOTracker()
+	tracker.RecordMeetingCreated()
+
+	handler := NewStatsHandler(tracker, nil)
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	w := httptest.NewRecorder()
+
+	err := handler.GetMetrics(w, req)
+	assert.NoError(t, err)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/plain")
+	assert.Contains(t, w.Body.String(), "meetsync_meetings_created_total 1")
+	assert.NotContains(t, w.Body.String(), "meetsync_db_")
+
+	// SQL backends also expose their connection pool
+	handler = NewStatsHandler(tracker, staticPool{MaxOpenConnections: 25, OpenConnections: 4, InUse: 3, Idle: 1, WaitCount: 7, WaitDuration: 1500 * time.Millisecond})
+	w = httptest.NewRecorder()
+	assert.NoError(t, handler.GetMetrics(w, req))
+	assert.Contains(t, w.Body.String(), "meetsync_db_in_use_connections 3\n")
+	assert.Contains(t, w.Body.String(), "meetsync_db_wait_count_total 7\n")
+	assert.Contains(t, w.Body.String(), "meetsync_db_wait_duration_seconds_total 1.5\n")
+}
+
+// staticPool reports fixed connection pool statistics
+type staticPool sql.DBStats
+
+func (p staticPool) Stats() sql.DBStats {
+	return sql.DBStats(p)
+}
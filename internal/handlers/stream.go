@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"meetsync/internal/api"
+	"meetsync/pkg/errors"
+	"meetsync/pkg/logs"
+)
+
+// recommendationStreamHeartbeat is how often a recommendation stream sends a comment
+// while the recommendations do not change, so proxies keep the connection open and
+// disconnected clients are noticed
+const recommendationStreamHeartbeat = 30 * time.Second
+
+// StreamRecommendations handles streaming the recommendations of a meeting as Server-Sent
+// Events: a recommendations event with the current ones, then another one whenever they
+// change, until the client disconnects. An error event ends the stream when they can no
+// longer be read, for instance because the meeting was deleted.
+func (h *MeetingHandler) StreamRecommendations(w http.ResponseWriter, r *http.Request) error {
+	meetingID := r.PathValue("id")
+	set, err := h.service.GetRecommendations(r.Context(), meetingID, callerID(r))
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // keep nginx from buffering events
+	w.WriteHeader(http.StatusOK)
+	controller := http.NewResponseController(w)
+
+	var sent []byte
+	for {
+		// Keep the server write timeout from cutting off the stream
+		_ = controller.SetWriteDeadline(time.Now().Add(recommendationStreamHeartbeat + 10*time.Second))
+
+		slots, err := json.Marshal(set.Slots)
+		if err != nil {
+			logs.Error("Failed to encode recommendations of meeting %s for its stream: %v", meetingID, err)
+			return nil
+		}
+		if bytes.Equal(slots, sent) {
+			_, err = fmt.Fprint(w, ": heartbeat\n\n")
+		} else {
+			err = writeEvent(w, "recommendations", api.GetRecommendationsResponse{RecommendedSlots: set.Slots, ComputedAt: set.ComputedAt})
+			sent = slots
+		}
+		if err != nil || controller.Flush() != nil {
+			return nil // the client is gone
+		}
+
+		set, err = h.service.WaitForRecommendationChange(r.Context(), meetingID, set, recommendationStreamHeartbeat)
+		if r.Context().Err() != nil {
+			return nil
+		}
+		if err != nil {
+			appErr, ok := errors.AsAppError(err)
+			if !ok {
+				appErr = errors.NewInternalError("An unexpected error occurred", err)
+			}
+			if writeEvent(w, "error", api.StreamError{Type: appErr.Type, Message: appErr.Message}) == nil {
+				_ = controller.Flush()
+			}
+			return nil
+		}
+	}
+}
+
+// writeEvent writes a Server-Sent Event of the given type with data encoded as JSON
+func writeEvent(w http.ResponseWriter, event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	return err
+}
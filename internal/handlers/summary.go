@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"time"
+
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+)
+
+// maxSummarySlots caps the number of recommended slots shown on a meeting summary page
+const maxSummarySlots = 5
+
+// summaryTemplate renders the read-only summary page of a meeting. It is self-contained,
+// with inline styles, so it displays the same when opened from an email.
+var summaryTemplate = template.Must(template.New("summary").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{.Title}} · MeetSync</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #1f2328; max-width: 48rem; margin: 2rem auto; padding: 0 1rem; line-height: 1.5; }
+h1 { margin-bottom: 0.25rem; }
+.meta { color: #59636e; margin-top: 0; }
+table { border-collapse: collapse; width: 100%; margin: 0.5rem 0 1.5rem; }
+th, td { text-align: left; padding: 0.4rem 0.6rem; border-bottom: 1px solid #d1d9e0; }
+th { background: #f6f8fa; }
+.yes { color: #1a7f37; } .tentative { color: #9a6700; } .no { color: #cf222e; } .none { color: #59636e; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">{{.Duration}} · organized by {{.Organizer}} · {{.Status}} · times in {{.TimeZone}}</p>
+
+<h2>Recommended times</h2>
+{{if .Slots}}
+<table>
+<tr><th>#</th><th>When</th><th>Available</th></tr>
+{{range .Slots}}<tr><td>{{.Rank}}</td><td>{{.When}}</td><td>{{.Available}} of {{.Total}}{{if .Tentative}} ({{.Tentative}} tentative){{end}}</td></tr>
+{{end}}</table>
+{{else}}
+<p class="none">No times have been proposed yet.</p>
+{{end}}
+
+<h2>Responses ({{.Responded}} of {{len .Responses}})</h2>
+<table>
+<tr><th>Participant</th><th>Response</th></tr>
+{{range .Responses}}<tr><td>{{.Name}}</td><td class="{{.Class}}">{{.Status}}</td></tr>
+{{end}}</table>
+<p class="meta">Recommendations computed {{.ComputedAt}}.</p>
+</body>
+</html>
+`))
+
+// summaryPage is what the summary template renders
+type summaryPage struct {
+	Title      string
+	Duration   string
+	Organizer  string
+	Status     string
+	TimeZone   string
+	Slots      []summarySlot
+	Responses  []summaryResponse
+	Responded  int
+	ComputedAt string
+}
+
+type summarySlot struct {
+	Rank      int
+	When      string
+	Available int
+	Tentative int
+	Total     int
+}
+
+type summaryResponse struct {
+	Name   string
+	Status string
+	Class  string
+}
+
+// GetMeetingSummary handles serving a read-only HTML page summarizing a meeting, its
+// recommended times and who responded, that can be linked from emails
+func (h *MeetingHandler) GetMeetingSummary(w http.ResponseWriter, r *http.Request) error {
+	meetingID := r.PathValue("id")
+	if meetingID == "" {
+		return errors.NewValidationError("Meeting ID is required", "")
+	}
+
+	summary, err := h.service.GetMeetingSummary(meetingID)
+	if err != nil {
+		return err
+	}
+
+	var page bytes.Buffer
+	if err := summaryTemplate.Execute(&page, newSummaryPage(summary)); err != nil {
+		return errors.NewInternalError("Failed to render summary", err)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(page.Bytes())
+	return nil
+}
+
+// newSummaryPage formats a meeting summary for display, with times in the meeting's
+// time zone
+func newSummaryPage(summary models.MeetingSummary) summaryPage {
+	meeting := summary.Meeting
+	location, err := time.LoadLocation(meeting.TimeZone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	page := summaryPage{
+		Title:      meeting.Title,
+		Duration:   (time.Duration(meeting.EstimatedDuration) * time.Minute).String(),
+		Organizer:  meeting.OrganizerID,
+		Status:     string(meeting.Status),
+		TimeZone:   location.String(),
+		ComputedAt: summary.Grid.ComputedAt.In(location).Format("Mon Jan 2, 15:04"),
+	}
+	if meeting.Organizer != nil {
+		page.Organizer = displayName(*meeting.Organizer)
+	}
+	for i, row := range summary.Grid.Rows {
+		if i == maxSummarySlots {
+			break
+		}
+		slot := row.Slot
+		page.Slots = append(page.Slots, summarySlot{
+			Rank:      i + 1,
+			When:      slot.TimeSlot.StartTime.In(location).Format("Mon Jan 2, 15:04") + " – " + slot.TimeSlot.EndTime.In(location).Format("15:04"),
+			Available: slot.AvailableCount,
+			Tentative: slot.TentativeCount,
+			Total:     slot.TotalParticipants,
+		})
+	}
+	for _, response := range summary.Responses {
+		item := summaryResponse{Name: displayName(response.Participant), Status: "Awaiting response", Class: "none"}
+		switch {
+		case response.Responded && response.Stale:
+			item.Status, item.Class = "Needs to reconfirm", "no"
+		case response.Responded && response.Tentative:
+			item.Status, item.Class = "Responded (tentative)", "tentative"
+		case response.Responded:
+			item.Status, item.Class = "Responded", "yes"
+		}
+		if response.Responded {
+			page.Responded++
+		}
+		page.Responses = append(page.Responses, item)
+	}
+	return page
+}
+
+// displayName returns the name of a user, or their email when they have none
+func displayName(user models.User) string {
+	if user.Name != "" {
+		return user.Name
+	}
+	return user.Email
+}
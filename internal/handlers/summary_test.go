@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+)
+
+func TestGetMeetingSummary(t *testing.T) {
+	organizer := models.User{ID: "user-1", Name: "Ada"}
+	summary := models.MeetingSummary{
+		Meeting: models.Meeting{
+			ID:                "meeting-1",
+			Title:             "Planning <Q3>",
+			Organizer:         &organizer,
+			EstimatedDuration: 60,
+			Status:            models.MeetingStatusPending,
+			TimeZone:          "Europe/Paris",
+		},
+		Grid: models.AvailabilityGrid{
+			Rows: []models.AvailabilityRow{{Slot: models.RecommendedSlot{
+				TimeSlot:          models.TimeSlot{StartTime: time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC), EndTime: time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)},
+				AvailableCount:    1,
+				TentativeCount:    1,
+				TotalParticipants: 2,
+			}}},
+		},
+		Responses: []models.ParticipantResponse{
+			{Participant: organizer},
+			{Participant: models.User{ID: "user-2", Email: "bob@example.com"}, Responded: true, Tentative: true},
+		},
+	}
+	mockService := new(MockMeetingService)
+	mockService.On("GetMeetingSummary", "meeting-1").Return(summary, nil)
+	mockService.On("GetMeetingSummary", "missing").Return(models.MeetingSummary{}, errors.NewNotFoundError("Meeting not found"))
+	handler := &MeetingHandler{service: mockService}
+
+	req := httptest.NewRequest(http.MethodGet, "/meetings/meeting-1/summary", nil)
+	req.SetPathValue("id", "meeting-1")
+	w := httptest.NewRecorder()
+	assert.NoError(t, handler.GetMeetingSummary(w, req))
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	body := w.Body.String()
+	assert.Contains(t, body, "<h1>Planning &lt;Q3&gt;</h1>")
+	assert.Contains(t, body, "organized by Ada")
+	assert.Contains(t, body, "Mon Jan 7, 10:00 – 11:00")
+	assert.Contains(t, body, "1 of 2 (1 tentative)")
+	assert.Contains(t, body, "Responses (1 of 2)")
+	assert.Contains(t, body, "<td>bob@example.com</td><td class=\"tentative\">Responded (tentative)</td>")
+	assert.Contains(t, body, "Awaiting response")
+
+	req = httptest.NewRequest(http.MethodGet, "/meetings/missing/summary", nil)
+	req.SetPathValue("id", "missing")
+	err := handler.GetMeetingSummary(httptest.NewRecorder(), req)
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusNotFound, err.(*errors.AppError).HTTPStatusCode())
+	}
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"meetsync/internal/api"
+	"meetsync/internal/metering"
+	"meetsync/internal/quota"
+	"meetsync/pkg/errors"
+)
+
+// UsageHandler handles reporting the organization's usage against its quotas and the
+// metered usage billed for
+type UsageHandler struct {
+	quotas *quota.Tracker
+	meter  *metering.Meter
+}
+
+// NewUsageHandler creates a new UsageHandler
+func NewUsageHandler(quotas *quota.Tracker, meter *metering.Meter) *UsageHandler {
+	return &UsageHandler{
+		quotas: quotas,
+		meter:  meter,
+	}
+}
+
+// GetUsage handles getting the usage of the current month against the quotas
+func (h *UsageHandler) GetUsage(w http.ResponseWriter, r *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(api.GetUsageResponse{Usage: h.quotas.Usage()}); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// GetUsageSummary handles getting the metered usage of a month, the current one unless
+// the month query parameter is given
+func (h *UsageHandler) GetUsageSummary(w http.ResponseWriter, r *http.Request) error {
+	period := r.URL.Query().Get("month")
+	if period == "" {
+		period = h.meter.CurrentPeriod()
+	} else if err := metering.ParsePeriod(period); err != nil {
+		return errors.NewValidationError("Invalid month", "use YYYY-MM")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(api.GetUsageSummaryResponse{Summary: h.meter.Summary(period)}); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"meetsync/internal/api"
+	"meetsync/internal/metering"
+	"meetsync/internal/quota"
+)
+
+func TestGetUsage(t *testing.T) {
+	tracker := quota.NewTracker(quota.Limits{MeetingsPerMonth: 10, ParticipantsPerMeeting: 5})
+	_, err := tracker.ReserveMeeting(3)
+	assert.NoError(t, err)
+	handler := NewUsageHandler(tracker, metering.NewMeter(metering.NoopSink{}))
+
+	w := httptest.NewRecorder()
+	err = handler.GetUsage(w, httptest.NewRequest(http.MethodGet, "/api/usage", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp api.GetUsageResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, quota.Counter{Used: 1, Limit: 10}, resp.Usage.Meetings)
+	assert.Equal(t, quota.Counter{Used: 3, Limit: 5}, resp.Usage.ParticipantsPerMeeting)
+	assert.NotEmpty(t, resp.Usage.Period)
+}
+
+func TestGetUsageSummary(t *testing.T) {
+	meter := metering.NewMeter(metering.NoopSink{})
+	meter.Record(metering.KindMeetingCreated, "meeting-1")
+	handler := NewUsageHandler(quota.NewTracker(quota.Limits{}), meter)
+
+	w := httptest.NewRecorder()
+	err := handler.GetUsageSummary(w, httptest.NewRequest(http.MethodGet, "/api/usage/summary", nil))
+	assert.NoError(t, err)
+
+	var resp api.GetUsageSummaryResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, meter.CurrentPeriod(), resp.Summary.Period)
+	assert.Equal(t, int64(1), resp.Summary.MeetingsCreated)
+
+	w = httptest.NewRecorder()
+	err = handler.GetUsageSummary(w, httptest.NewRequest(http.MethodGet, "/api/usage/summary?month=2001-01", nil))
+	assert.NoError(t, err)
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, "2001-01", resp.Summary.Period)
+	assert.Zero(t, resp.Summary.MeetingsCreated)
+
+	err = handler.GetUsageSummary(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/usage/summary?month=last", nil))
+	assert.Error(t, err)
+}
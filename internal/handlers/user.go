@@ -3,11 +3,11 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
-	"strings"
 
 	"meetsync/internal/api"
 	"meetsync/internal/interfaces"
-	"meetsync/internal/services"
+	"meetsync/internal/middleware"
+	"meetsync/internal/models"
 	"meetsync/pkg/errors"
 	"meetsync/pkg/logs"
 )
@@ -17,11 +17,9 @@ type UserHandler struct {
 	service interfaces.UserService
 }
 
-// NewUserHandler creates a new UserHandler
-func NewUserHandler() *UserHandler {
-	return &UserHandler{
-		service: services.NewUserService(),
-	}
+// NewUserHandler creates a new UserHandler serving requests through service
+func NewUserHandler(service interfaces.UserService) *UserHandler {
+	return &UserHandler{service: service}
 }
 
 // CreateUser handles the creation of a new user
@@ -41,7 +39,7 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) error {
 		return err
 	}
 
-	logs.Info("Created user: %s (%s)", createdUser.Name, createdUser.ID)
+	logs.Info("Created user: %s (%s)", logs.PII(createdUser.Name), createdUser.ID)
 
 	// Return response
 	resp := api.CreateUserResponse{
@@ -62,12 +60,10 @@ func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) error {
 		return errors.NewValidationError("Method not allowed", "Only GET method is allowed")
 	}
 
-	// Extract user ID from path
-	parts := strings.Split(r.URL.Path, "/")
-	if len(parts) < 4 {
+	userID := r.PathValue("id")
+	if userID == "" {
 		return errors.NewValidationError("Invalid path", "User ID not provided")
 	}
-	userID := parts[3]
 
 	// Get user using service
 	user, err := h.service.GetUserByID(userID)
@@ -99,9 +95,219 @@ func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) error {
 		return err
 	}
 
+	// Keep the users matching the external ID and metadata filters
+	query := r.URL.Query()
+	filter := models.UserFilter{ExternalID: query.Get("externalId"), Metadata: metadataFilter(query)}
+	matching := make([]models.User, 0, len(users))
+	for _, user := range users {
+		if filter.Matches(user) {
+			matching = append(matching, user)
+		}
+	}
+
 	// Return response
 	resp := api.ListUsersResponse{
-		Users: users,
+		Users: matching,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// RequestEmailChange handles requesting a change of a user's email address
+func (h *UserHandler) RequestEmailChange(w http.ResponseWriter, r *http.Request) error {
+	userID := r.PathValue("id")
+	if userID == "" {
+		return errors.NewValidationError("User ID is required", "")
+	}
+
+	var req api.ChangeEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+
+	token, err := h.service.RequestEmailChange(userID, req.Email)
+	if err != nil {
+		return err
+	}
+
+	logs.Info("Requested email change for user %s", userID)
+
+	// The token itself is only delivered to the new address
+	resp := api.ChangeEmailResponse{
+		PendingEmail: token.Email,
+		ExpiresAt:    token.ExpiresAt,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// ConfirmEmailChange handles confirming a pending email change
+func (h *UserHandler) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) error {
+	var req api.ConfirmEmailChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+
+	user, err := h.service.ConfirmEmailChange(req.Token)
+	if err != nil {
+		return err
+	}
+
+	logs.Info("Changed email for user %s", user.ID)
+
+	resp := api.GetUserResponse{
+		User: user,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// VerifyEmail handles verifying a user's email address with a signup token
+func (h *UserHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) error {
+	var req api.VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+
+	user, err := h.service.VerifyEmail(req.Token)
+	if err != nil {
+		return err
+	}
+
+	logs.Info("Verified email for user %s", user.ID)
+
+	resp := api.GetUserResponse{
+		User: user,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// SetFocusBlocks handles replacing the recurring focus blocks of a user
+func (h *UserHandler) SetFocusBlocks(w http.ResponseWriter, r *http.Request) error {
+	userID := r.PathValue("id")
+	if userID == "" {
+		return errors.NewValidationError("User ID is required", "")
+	}
+
+	var req api.SetFocusBlocksRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+
+	user, err := h.service.SetFocusBlocks(userID, req.FocusBlocks)
+	if err != nil {
+		return err
+	}
+
+	resp := api.GetUserResponse{
+		User: user,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// SetMetadata handles replacing the metadata of a user
+func (h *UserHandler) SetMetadata(w http.ResponseWriter, r *http.Request) error {
+	userID := r.PathValue("id")
+	if userID == "" {
+		return errors.NewValidationError("User ID is required", "")
+	}
+
+	var req api.SetUserMetadataRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+
+	user, err := h.service.SetUserMetadata(userID, req.Metadata)
+	if err != nil {
+		return err
+	}
+
+	resp := api.GetUserResponse{
+		User: user,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// SetDigest handles changing how often a user receives a digest instead of an email per
+// invitation or reconfirmation request
+func (h *UserHandler) SetDigest(w http.ResponseWriter, r *http.Request) error {
+	userID := r.PathValue("id")
+	if userID == "" {
+		return errors.NewValidationError("User ID is required", "")
+	}
+
+	var req api.SetDigestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+	frequency := models.DigestFrequency(req.Frequency)
+	if req.Frequency == "off" {
+		frequency = models.DigestOff
+	}
+
+	user, err := h.service.SetDigest(userID, frequency)
+	if err != nil {
+		return err
+	}
+
+	resp := api.GetUserResponse{
+		User: user,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// SetDoNotDisturb handles setting the quiet hours of a user and snoozing their notifications
+func (h *UserHandler) SetDoNotDisturb(w http.ResponseWriter, r *http.Request) error {
+	userID := r.PathValue("id")
+	if userID == "" {
+		return errors.NewValidationError("User ID is required", "")
+	}
+
+	var req api.SetDoNotDisturbRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+
+	user, err := h.service.SetDoNotDisturb(userID, req.QuietHours, req.SnoozedUntil)
+	if err != nil {
+		return err
+	}
+
+	resp := api.GetUserResponse{
+		User: user,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -110,3 +316,179 @@ func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) error {
 	}
 	return nil
 }
+
+// ResendVerification handles sending a new verification token to a user
+func (h *UserHandler) ResendVerification(w http.ResponseWriter, r *http.Request) error {
+	userID := r.PathValue("id")
+	if userID == "" {
+		return errors.NewValidationError("User ID is required", "")
+	}
+
+	if err := h.service.ResendVerification(userID); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	return nil
+}
+
+// CreateAccessToken handles minting a personal access token for a user
+func (h *UserHandler) CreateAccessToken(w http.ResponseWriter, r *http.Request) error {
+	userID := r.PathValue("id")
+	if userID == "" {
+		return errors.NewValidationError("User ID is required", "")
+	}
+
+	if err := authorizeUser(r, userID); err != nil {
+		return err
+	}
+
+	var req api.CreateAccessTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+	// A token cannot mint a token more powerful than itself
+	if caller, ok := middleware.AccessTokenFromContext(r.Context()); ok {
+		for _, scope := range req.Scopes {
+			if !caller.HasScope(scope) {
+				return errors.NewForbiddenError("Access token cannot grant the " + string(scope) + " scope")
+			}
+		}
+	}
+
+	token, secret, err := h.service.CreateAccessToken(userID, req.Name, req.Scopes)
+	if err != nil {
+		return err
+	}
+
+	logs.Info("Created access token %s for user %s", token.ID, userID)
+
+	resp := api.CreateAccessTokenResponse{
+		Token:  token,
+		Secret: secret,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// ListAccessTokens handles listing a user's personal access tokens
+func (h *UserHandler) ListAccessTokens(w http.ResponseWriter, r *http.Request) error {
+	userID := r.PathValue("id")
+	if userID == "" {
+		return errors.NewValidationError("User ID is required", "")
+	}
+	if err := authorizeUser(r, userID); err != nil {
+		return err
+	}
+
+	tokens, err := h.service.ListAccessTokens(userID)
+	if err != nil {
+		return err
+	}
+
+	resp := api.ListAccessTokensResponse{
+		Tokens: tokens,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// ListSessions handles listing the clients a user is signed in on with personal access tokens
+func (h *UserHandler) ListSessions(w http.ResponseWriter, r *http.Request) error {
+	userID := r.PathValue("id")
+	if userID == "" {
+		return errors.NewValidationError("User ID is required", "")
+	}
+	if err := authorizeUser(r, userID); err != nil {
+		return err
+	}
+
+	sessions, err := h.service.ListSessions(userID)
+	if err != nil {
+		return err
+	}
+
+	resp := api.ListSessionsResponse{
+		Sessions: sessions,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// RevokeAccessToken handles revoking a user's personal access token
+func (h *UserHandler) RevokeAccessToken(w http.ResponseWriter, r *http.Request) error {
+	userID := r.PathValue("id")
+	tokenID := r.PathValue("tokenId")
+	if userID == "" || tokenID == "" {
+		return errors.NewValidationError("User ID and token ID are required", "")
+	}
+	if err := authorizeUser(r, userID); err != nil {
+		return err
+	}
+
+	if err := h.service.RevokeAccessToken(userID, tokenID); err != nil {
+		return err
+	}
+
+	logs.Info("Revoked access token %s of user %s", tokenID, userID)
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// RevokeAccessTokens handles revoking every personal access token of a user
+func (h *UserHandler) RevokeAccessTokens(w http.ResponseWriter, r *http.Request) error {
+	userID := r.PathValue("id")
+	if userID == "" {
+		return errors.NewValidationError("User ID is required", "")
+	}
+	if err := authorizeUser(r, userID); err != nil {
+		return err
+	}
+
+	revoked, err := h.service.RevokeAccessTokens(userID)
+	if err != nil {
+		return err
+	}
+
+	logs.Info("Revoked %d access tokens of user %s", revoked, userID)
+
+	resp := api.RevokeAccessTokensResponse{
+		Revoked: revoked,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// authorizeUser ensures a request authenticated with a personal access token only acts for the token's owner
+func authorizeUser(r *http.Request, userID string) error {
+	token, ok := middleware.AccessTokenFromContext(r.Context())
+	if ok && token.UserID != userID {
+		return errors.NewForbiddenError("Access token cannot act on behalf of another user")
+	}
+	return nil
+}
+
+// callerID returns the owner of the personal access token that authenticated the
+// request, or nothing for requests without a token
+func callerID(r *http.Request) string {
+	token, _ := middleware.AccessTokenFromContext(r.Context())
+	return token.UserID
+}
@@ -35,11 +35,131 @@ func (m *MockUserService) GetUserByID(id string) (models.User, error) {
 	return args.Get(0).(models.User), args.Error(1)
 }
 
+func (m *MockUserService) GetUsersByIDs(ids []string) ([]models.User, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
 func (m *MockUserService) ListUsers() ([]models.User, error) {
 	args := m.Called()
 	return args.Get(0).([]models.User), args.Error(1)
 }
 
+func (m *MockUserService) ImportUsers(rows []models.UserImportRow, options models.UserImportOptions) (models.UserImportResult, error) {
+	args := m.Called(rows, options)
+	return args.Get(0).(models.UserImportResult), args.Error(1)
+}
+
+func (m *MockUserService) Bootstrap(bootstrap models.Bootstrap) (models.BootstrapResult, error) {
+	args := m.Called(bootstrap)
+	return args.Get(0).(models.BootstrapResult), args.Error(1)
+}
+
+func (m *MockUserService) ProvisionUser(input models.UserProvisioning) (models.User, error) {
+	args := m.Called(input)
+	return args.Get(0).(models.User), args.Error(1)
+}
+
+func (m *MockUserService) EnsureGuest(email, name string) (models.User, error) {
+	args := m.Called(email, name)
+	return args.Get(0).(models.User), args.Error(1)
+}
+
+func (m *MockUserService) UpdateProvisionedUser(userID string, input models.UserProvisioning) (models.User, error) {
+	args := m.Called(userID, input)
+	return args.Get(0).(models.User), args.Error(1)
+}
+
+func (m *MockUserService) DeleteUser(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockUserService) RequestEmailChange(userID, newEmail string) (models.UserToken, error) {
+	args := m.Called(userID, newEmail)
+	return args.Get(0).(models.UserToken), args.Error(1)
+}
+
+func (m *MockUserService) ConfirmEmailChange(token string) (models.User, error) {
+	args := m.Called(token)
+	return args.Get(0).(models.User), args.Error(1)
+}
+
+func (m *MockUserService) VerifyEmail(token string) (models.User, error) {
+	args := m.Called(token)
+	return args.Get(0).(models.User), args.Error(1)
+}
+
+func (m *MockUserService) ResendVerification(userID string) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockUserService) SetFocusBlocks(userID string, blocks []models.FocusBlock) (models.User, error) {
+	args := m.Called(userID, blocks)
+	return args.Get(0).(models.User), args.Error(1)
+}
+
+func (m *MockUserService) SetDigest(userID string, frequency models.DigestFrequency) (models.User, error) {
+	args := m.Called(userID, frequency)
+	return args.Get(0).(models.User), args.Error(1)
+}
+
+func (m *MockUserService) RecordDigest(userID string, sentAt time.Time) error {
+	args := m.Called(userID, sentAt)
+	return args.Error(0)
+}
+
+func (m *MockUserService) SetDoNotDisturb(userID string, quietHours *models.QuietHours, snoozedUntil *time.Time) (models.User, error) {
+	args := m.Called(userID, quietHours, snoozedUntil)
+	return args.Get(0).(models.User), args.Error(1)
+}
+
+func (m *MockUserService) SetUserMetadata(userID string, metadata models.Metadata) (models.User, error) {
+	args := m.Called(userID, metadata)
+	return args.Get(0).(models.User), args.Error(1)
+}
+
+func (m *MockUserService) CreateAccessToken(userID, name string, scopes []models.Scope) (models.PersonalAccessToken, string, error) {
+	args := m.Called(userID, name, scopes)
+	return args.Get(0).(models.PersonalAccessToken), args.String(1), args.Error(2)
+}
+
+func (m *MockUserService) ListAccessTokens(userID string) ([]models.PersonalAccessToken, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]models.PersonalAccessToken), args.Error(1)
+}
+
+func (m *MockUserService) RevokeAccessToken(userID, tokenID string) error {
+	args := m.Called(userID, tokenID)
+	return args.Error(0)
+}
+
+func (m *MockUserService) RevokeAccessTokens(userID string) (int, error) {
+	args := m.Called(userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockUserService) ListSessions(userID string) ([]models.Session, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]models.Session), args.Error(1)
+}
+
+func (m *MockUserService) AuthenticateAccessToken(secret string, client models.TokenClient) (models.PersonalAccessToken, error) {
+	args := m.Called(secret, client)
+	return args.Get(0).(models.PersonalAccessToken), args.Error(1)
+}
+
+func (m *MockUserService) Snapshot() (models.UserStoreSnapshot, error) {
+	args := m.Called()
+	return args.Get(0).(models.UserStoreSnapshot), args.Error(1)
+}
+
+func (m *MockUserService) Restore(snapshot models.UserStoreSnapshot) error {
+	args := m.Called(snapshot)
+	return args.Error(0)
+}
+
 func TestCreateUser(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -110,7 +230,7 @@ func TestCreateUser(t *testing.T) {
 			tt.setupMock(mockService)
 
 			// Create handler with mock service
-			handler := &UserHandler{service: mockService}
+			handler := NewUserHandler(mockService)
 
 			// Create request
 			body, _ := json.Marshal(tt.request)
@@ -185,10 +305,11 @@ func TestGetUser(t *testing.T) {
 			tt.setupMock(mockService)
 
 			// Create handler with mock service
-			handler := &UserHandler{service: mockService}
+			handler := NewUserHandler(mockService)
 
 			// Create request
 			req := httptest.NewRequest(http.MethodGet, "/api/users/"+tt.userID, nil)
+			req.SetPathValue("id", tt.userID)
 			w := httptest.NewRecorder()
 
 			// Handle request
@@ -219,6 +340,7 @@ func TestGetUser(t *testing.T) {
 func TestListUsers(t *testing.T) {
 	tests := []struct {
 		name           string
+		query          string
 		setupMock      func(*MockUserService)
 		expectedStatus int
 		expectedError  bool
@@ -258,6 +380,20 @@ func TestListUsers(t *testing.T) {
 			expectedError:  false,
 			expectedCount:  0,
 		},
+		{
+			name:  "filtered by external ID and metadata",
+			query: "?externalId=okta-1&metadata.team=sales",
+			setupMock: func(m *MockUserService) {
+				m.On("ListUsers").Return([]models.User{
+					{ID: "user-1", ExternalID: "okta-1", Metadata: models.Metadata{"team": "sales"}},
+					{ID: "user-2", ExternalID: "okta-1", Metadata: models.Metadata{"team": "support"}},
+					{ID: "user-3", ExternalID: "okta-3", Metadata: models.Metadata{"team": "sales"}},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedError:  false,
+			expectedCount:  1,
+		},
 	}
 
 	for _, tt := range tests {
@@ -267,10 +403,10 @@ func TestListUsers(t *testing.T) {
 			tt.setupMock(mockService)
 
 			// Create handler with mock service
-			handler := &UserHandler{service: mockService}
+			handler := NewUserHandler(mockService)
 
 			// Create request
-			req := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+			req := httptest.NewRequest(http.MethodGet, "/api/users"+tt.query, nil)
 			w := httptest.NewRecorder()
 
 			// Handle request
@@ -297,3 +433,331 @@ func TestListUsers(t *testing.T) {
 		})
 	}
 }
+
+func TestRequestEmailChange(t *testing.T) {
+	expiresAt := time.Now().Add(24 * time.Hour)
+
+	tests := []struct {
+		name           string
+		userID         string
+		request        api.ChangeEmailRequest
+		setupMock      func(*MockUserService)
+		expectedStatus int
+		expectedError  bool
+	}{
+		{
+			name:    "successful request",
+			userID:  "test-id",
+			request: api.ChangeEmailRequest{Email: "new@example.com"},
+			setupMock: func(m *MockUserService) {
+				m.On("RequestEmailChange", "test-id", "new@example.com").Return(models.UserToken{
+					Token:     "secret-token",
+					UserID:    "test-id",
+					Email:     "new@example.com",
+					ExpiresAt: expiresAt,
+				}, nil)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:    "email already in use",
+			userID:  "test-id",
+			request: api.ChangeEmailRequest{Email: "taken@example.com"},
+			setupMock: func(m *MockUserService) {
+				m.On("RequestEmailChange", "test-id", "taken@example.com").Return(models.UserToken{}, errors.NewConflictError("Email is already in use"))
+			},
+			expectedStatus: http.StatusConflict,
+			expectedError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockUserService)
+			tt.setupMock(mockService)
+			handler := NewUserHandler(mockService)
+
+			body, _ := json.Marshal(tt.request)
+			req := httptest.NewRequest(http.MethodPost, "/api/users/"+tt.userID+"/email", bytes.NewBuffer(body))
+			req.SetPathValue("id", tt.userID)
+			w := httptest.NewRecorder()
+
+			err := handler.RequestEmailChange(w, req)
+
+			if tt.expectedError {
+				assert.Error(t, err)
+				if appErr, ok := err.(*errors.AppError); ok {
+					assert.Equal(t, tt.expectedStatus, appErr.HTTPStatusCode())
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, w.Code)
+				assert.NotContains(t, w.Body.String(), "secret-token")
+
+				var resp api.ChangeEmailResponse
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, tt.request.Email, resp.PendingEmail)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestConfirmEmailChange(t *testing.T) {
+	mockService := new(MockUserService)
+	mockService.On("ConfirmEmailChange", "secret-token").Return(models.User{ID: "test-id", Email: "new@example.com"}, nil)
+	mockService.On("ConfirmEmailChange", "unknown").Return(models.User{}, errors.NewNotFoundError("Token not found"))
+	handler := NewUserHandler(mockService)
+
+	body, _ := json.Marshal(api.ConfirmEmailChangeRequest{Token: "secret-token"})
+	req := httptest.NewRequest(http.MethodPost, "/api/users/email/confirm", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+
+	err := handler.ConfirmEmailChange(w, req)
+	assert.NoError(t, err)
+	var resp api.GetUserResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, "new@example.com", resp.User.Email)
+
+	body, _ = json.Marshal(api.ConfirmEmailChangeRequest{Token: "unknown"})
+	req = httptest.NewRequest(http.MethodPost, "/api/users/email/confirm", bytes.NewBuffer(body))
+	err = handler.ConfirmEmailChange(w, req)
+	assert.Error(t, err)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestVerifyEmail(t *testing.T) {
+	mockService := new(MockUserService)
+	mockService.On("VerifyEmail", "secret-token").Return(models.User{ID: "test-id", EmailVerified: true}, nil)
+	mockService.On("VerifyEmail", "expired").Return(models.User{}, errors.NewValidationError("Token has expired", ""))
+	handler := NewUserHandler(mockService)
+
+	body, _ := json.Marshal(api.VerifyEmailRequest{Token: "secret-token"})
+	req := httptest.NewRequest(http.MethodPost, "/api/users/verify", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+
+	err := handler.VerifyEmail(w, req)
+	assert.NoError(t, err)
+	var resp api.GetUserResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.True(t, resp.User.EmailVerified)
+
+	body, _ = json.Marshal(api.VerifyEmailRequest{Token: "expired"})
+	req = httptest.NewRequest(http.MethodPost, "/api/users/verify", bytes.NewBuffer(body))
+	err = handler.VerifyEmail(httptest.NewRecorder(), req)
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusBadRequest, err.(*errors.AppError).HTTPStatusCode())
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestResendVerification(t *testing.T) {
+	mockService := new(MockUserService)
+	mockService.On("ResendVerification", "test-id").Return(nil)
+	mockService.On("ResendVerification", "verified-id").Return(errors.NewConflictError("Email is already verified"))
+	handler := NewUserHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/users/test-id/verify/resend", nil)
+	req.SetPathValue("id", "test-id")
+	w := httptest.NewRecorder()
+	assert.NoError(t, handler.ResendVerification(w, req))
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/users/verified-id/verify/resend", nil)
+	req.SetPathValue("id", "verified-id")
+	err := handler.ResendVerification(httptest.NewRecorder(), req)
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusConflict, err.(*errors.AppError).HTTPStatusCode())
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestSetFocusBlocks(t *testing.T) {
+	blocks := []models.FocusBlock{{Days: []string{"monday"}, Start: "09:00", End: "11:00", TimeZone: "Europe/Paris"}}
+	invalid := []models.FocusBlock{{Start: "11:00", End: "09:00"}}
+
+	mockService := new(MockUserService)
+	mockService.On("SetFocusBlocks", "test-id", blocks).Return(models.User{ID: "test-id", FocusBlocks: blocks}, nil)
+	mockService.On("SetFocusBlocks", "test-id", invalid).Return(models.User{}, errors.NewValidationError("Invalid focus block", ""))
+	handler := NewUserHandler(mockService)
+
+	body, _ := json.Marshal(api.SetFocusBlocksRequest{FocusBlocks: blocks})
+	req := httptest.NewRequest(http.MethodPut, "/api/users/test-id/focus-blocks", bytes.NewBuffer(body))
+	req.SetPathValue("id", "test-id")
+	w := httptest.NewRecorder()
+
+	assert.NoError(t, handler.SetFocusBlocks(w, req))
+	var resp api.GetUserResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, blocks, resp.User.FocusBlocks)
+
+	body, _ = json.Marshal(api.SetFocusBlocksRequest{FocusBlocks: invalid})
+	req = httptest.NewRequest(http.MethodPut, "/api/users/test-id/focus-blocks", bytes.NewBuffer(body))
+	req.SetPathValue("id", "test-id")
+	err := handler.SetFocusBlocks(httptest.NewRecorder(), req)
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusBadRequest, err.(*errors.AppError).HTTPStatusCode())
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestSetDigest(t *testing.T) {
+	mockService := new(MockUserService)
+	mockService.On("SetDigest", "test-id", models.DigestWeekly).Return(models.User{ID: "test-id", Digest: models.DigestWeekly}, nil)
+	mockService.On("SetDigest", "test-id", models.DigestOff).Return(models.User{ID: "test-id"}, nil)
+	mockService.On("SetDigest", "test-id", models.DigestFrequency("hourly")).Return(models.User{}, errors.NewValidationError("Invalid digest frequency", ""))
+	handler := NewUserHandler(mockService)
+
+	body, _ := json.Marshal(api.SetDigestRequest{Frequency: "weekly"})
+	req := httptest.NewRequest(http.MethodPut, "/api/users/test-id/digest", bytes.NewBuffer(body))
+	req.SetPathValue("id", "test-id")
+	w := httptest.NewRecorder()
+
+	assert.NoError(t, handler.SetDigest(w, req))
+	var resp api.GetUserResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, models.DigestWeekly, resp.User.Digest)
+
+	// "off" turns digests off
+	body, _ = json.Marshal(api.SetDigestRequest{Frequency: "off"})
+	req = httptest.NewRequest(http.MethodPut, "/api/users/test-id/digest", bytes.NewBuffer(body))
+	req.SetPathValue("id", "test-id")
+	assert.NoError(t, handler.SetDigest(httptest.NewRecorder(), req))
+
+	body, _ = json.Marshal(api.SetDigestRequest{Frequency: "hourly"})
+	req = httptest.NewRequest(http.MethodPut, "/api/users/test-id/digest", bytes.NewBuffer(body))
+	req.SetPathValue("id", "test-id")
+	err := handler.SetDigest(httptest.NewRecorder(), req)
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusBadRequest, err.(*errors.AppError).HTTPStatusCode())
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestSetDoNotDisturb(t *testing.T) {
+	hours := &models.QuietHours{Start: "22:00", End: "07:00", TimeZone: "Europe/Paris"}
+	snoozedUntil := time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)
+
+	mockService := new(MockUserService)
+	mockService.On("SetDoNotDisturb", "test-id", hours, mock.MatchedBy(func(until *time.Time) bool {
+		return until != nil && until.Equal(snoozedUntil)
+	})).Return(models.User{ID: "test-id", QuietHours: hours, SnoozedUntil: &snoozedUntil}, nil)
+	mockService.On("SetDoNotDisturb", "test-id", (*models.QuietHours)(nil), (*time.Time)(nil)).Return(models.User{ID: "test-id"}, nil)
+	handler := NewUserHandler(mockService)
+
+	body, _ := json.Marshal(api.SetDoNotDisturbRequest{QuietHours: hours, SnoozedUntil: &snoozedUntil})
+	req := httptest.NewRequest(http.MethodPut, "/api/users/test-id/do-not-disturb", bytes.NewBuffer(body))
+	req.SetPathValue("id", "test-id")
+	w := httptest.NewRecorder()
+
+	assert.NoError(t, handler.SetDoNotDisturb(w, req))
+	var resp api.GetUserResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, hours, resp.User.QuietHours)
+
+	// An empty body clears both
+	req = httptest.NewRequest(http.MethodPut, "/api/users/test-id/do-not-disturb", bytes.NewBufferString("{}"))
+	req.SetPathValue("id", "test-id")
+	assert.NoError(t, handler.SetDoNotDisturb(httptest.NewRecorder(), req))
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateAccessToken(t *testing.T) {
+	scopes := []models.Scope{models.ScopeReadMeetings}
+	mockService := new(MockUserService)
+	mockService.On("CreateAccessToken", "test-id", "sync", scopes).Return(models.PersonalAccessToken{
+		ID:         "token-id",
+		UserID:     "test-id",
+		Name:       "sync",
+		Scopes:     scopes,
+		SecretHash: "hash",
+	}, "msp_secret", nil)
+	handler := NewUserHandler(mockService)
+
+	body, _ := json.Marshal(api.CreateAccessTokenRequest{Name: "sync", Scopes: scopes})
+	req := httptest.NewRequest(http.MethodPost, "/api/users/test-id/tokens", bytes.NewBuffer(body))
+	req.SetPathValue("id", "test-id")
+	w := httptest.NewRecorder()
+
+	err := handler.CreateAccessToken(w, req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.NotContains(t, w.Body.String(), "hash")
+
+	var resp api.CreateAccessTokenResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, "msp_secret", resp.Secret)
+	assert.Equal(t, "token-id", resp.Token.ID)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestListAndRevokeAccessTokens(t *testing.T) {
+	mockService := new(MockUserService)
+	mockService.On("ListAccessTokens", "test-id").Return([]models.PersonalAccessToken{{ID: "token-id", UserID: "test-id"}}, nil)
+	mockService.On("RevokeAccessToken", "test-id", "token-id").Return(nil)
+	mockService.On("RevokeAccessToken", "test-id", "unknown").Return(errors.NewNotFoundError("Access token not found"))
+	mockService.On("RevokeAccessTokens", "test-id").Return(2, nil)
+	mockService.On("ListSessions", "test-id").Return([]models.Session{{TokenID: "token-id", Client: models.TokenClient{IP: "192.0.2.1"}}}, nil)
+	mockService.On("RevokeAccessTokens", "unknown").Return(0, errors.NewNotFoundError("User not found"))
+	handler := NewUserHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/users/test-id/tokens", nil)
+	req.SetPathValue("id", "test-id")
+	w := httptest.NewRecorder()
+	assert.NoError(t, handler.ListAccessTokens(w, req))
+	var resp api.ListAccessTokensResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Len(t, resp.Tokens, 1)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/users/test-id/sessions", nil)
+	req.SetPathValue("id", "test-id")
+	w = httptest.NewRecorder()
+	assert.NoError(t, handler.ListSessions(w, req))
+	var sessions api.ListSessionsResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&sessions))
+	if assert.Len(t, sessions.Sessions, 1) {
+		assert.Equal(t, "192.0.2.1", sessions.Sessions[0].Client.IP)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/users/test-id/tokens/token-id", nil)
+	req.SetPathValue("id", "test-id")
+	req.SetPathValue("tokenId", "token-id")
+	w = httptest.NewRecorder()
+	assert.NoError(t, handler.RevokeAccessToken(w, req))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/users/test-id/tokens/unknown", nil)
+	req.SetPathValue("id", "test-id")
+	req.SetPathValue("tokenId", "unknown")
+	err := handler.RevokeAccessToken(httptest.NewRecorder(), req)
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusNotFound, err.(*errors.AppError).HTTPStatusCode())
+	}
+
+	// Revoking every token reports how many were revoked
+	req = httptest.NewRequest(http.MethodDelete, "/api/users/test-id/tokens", nil)
+	req.SetPathValue("id", "test-id")
+	w = httptest.NewRecorder()
+	assert.NoError(t, handler.RevokeAccessTokens(w, req))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var revoked api.RevokeAccessTokensResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&revoked))
+	assert.Equal(t, 2, revoked.Revoked)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/users/unknown/tokens", nil)
+	req.SetPathValue("id", "unknown")
+	err = handler.RevokeAccessTokens(httptest.NewRecorder(), req)
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusNotFound, err.(*errors.AppError).HTTPStatusCode())
+	}
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"meetsync/internal/api"
+	"meetsync/internal/models"
+	"meetsync/internal/webhooks"
+	"meetsync/pkg/errors"
+)
+
+// WebhookHandler handles registering the webhooks of integrators, inspecting their
+// deliveries and replaying failed ones
+type WebhookHandler struct {
+	dispatcher *webhooks.Dispatcher
+}
+
+// NewWebhookHandler creates a new WebhookHandler managing the webhooks of dispatcher
+func NewWebhookHandler(dispatcher *webhooks.Dispatcher) *WebhookHandler {
+	return &WebhookHandler{dispatcher: dispatcher}
+}
+
+// CreateWebhook handles registering a webhook
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) error {
+	var req api.CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+
+	webhook, err := h.dispatcher.Register(req.URL, req.Events)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(api.CreateWebhookResponse{Webhook: webhook}); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// ListWebhooks handles listing the registered webhooks
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(api.ListWebhooksResponse{Webhooks: h.dispatcher.List()}); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// DeleteWebhook handles removing a webhook
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) error {
+	if err := h.dispatcher.Delete(r.PathValue("id")); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// ListDeliveries handles listing the deliveries of a webhook with their attempts,
+// optionally only those with the status given in the status query parameter
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) error {
+	status := models.WebhookDeliveryStatus(r.URL.Query().Get("status"))
+	switch status {
+	case "", models.WebhookDeliveryPending, models.WebhookDeliverySucceeded, models.WebhookDeliveryFailed:
+	default:
+		return errors.NewValidationError("Invalid status", "status must be pending, succeeded or failed")
+	}
+
+	deliveries, err := h.dispatcher.Deliveries(r.PathValue("id"), status)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(api.ListWebhookDeliveriesResponse{Deliveries: deliveries}); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
+
+// ReplayWebhook handles delivering events of a webhook again, the given deliveries or
+// every failed one when the body is empty
+func (h *WebhookHandler) ReplayWebhook(w http.ResponseWriter, r *http.Request) error {
+	var req api.ReplayWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		return errors.NewValidationError("Invalid request body", err.Error())
+	}
+
+	deliveries, err := h.dispatcher.Replay(r.PathValue("id"), req.DeliveryIDs)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(api.ReplayWebhookResponse{Deliveries: deliveries}); err != nil {
+		return errors.NewInternalError("Failed to encode response", err)
+	}
+	return nil
+}
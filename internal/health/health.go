@@ -0,0 +1,56 @@
+// Package health reports whether the service can serve requests correctly, including
+// whether its storage backend is safe for the number of replicas it is deployed with.
+package health
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StorageMemory identifies the storage backend that keeps all data in process memory
+const StorageMemory = "memory"
+
+// ProcessLocal lists the state API instances keep in their own memory whatever the storage
+// backend, so it is never shared between replicas
+var ProcessLocal = []string{"webhooks", "background jobs"}
+
+// StateCheck is the result of checking a storage backend against the deployment
+type StateCheck struct {
+	Backend  string `json:"backend"`
+	Shared   bool   `json:"shared"`   // whether replicas see each other's writes
+	Replicas int    `json:"replicas"` // declared number of replicas
+	// ProcessLocal lists the state kept in process memory even with a shared backend
+	ProcessLocal []string `json:"processLocal,omitempty"`
+	OK           bool     `json:"ok"`
+	Message      string   `json:"message,omitempty"`
+}
+
+// CheckState checks that a storage backend fits the declared number of replicas. A
+// backend that is not shared only works with a single replica: with more, every replica
+// serves its own copy of the data, so meetings and availability appear and disappear
+// depending on which replica answers, unless the load balancer pins each client to one
+// replica. State kept in process memory, listed in processLocal, is never shared, so it
+// needs a single replica whatever the backend: webhooks registered on one replica would
+// not receive the events of the others, and jobs could only be polled on the replica
+// running them.
+func CheckState(backend string, shared bool, replicas int, processLocal []string) StateCheck {
+	if replicas < 1 {
+		replicas = 1
+	}
+
+	check := StateCheck{
+		Backend:      backend,
+		Shared:       shared,
+		Replicas:     replicas,
+		ProcessLocal: processLocal,
+		OK:           replicas == 1 || (shared && len(processLocal) == 0),
+	}
+	switch {
+	case check.OK:
+	case !shared:
+		check.Message = fmt.Sprintf("the %s storage backend is not shared between replicas but %d replicas are declared; run a single replica", backend, replicas)
+	default:
+		check.Message = fmt.Sprintf("%s are kept in process memory and not shared between replicas but %d replicas are declared; run a single replica", strings.Join(processLocal, " and "), replicas)
+	}
+	return check
+}
@@ -0,0 +1,41 @@
+package health
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckState(t *testing.T) {
+	tests := []struct {
+		name         string
+		shared       bool
+		replicas     int
+		processLocal []string
+		wantOK       bool
+	}{
+		{name: "single replica", replicas: 1, processLocal: ProcessLocal, wantOK: true},
+		{name: "replicas not declared", replicas: 0, processLocal: ProcessLocal, wantOK: true},
+		{name: "several replicas without shared state", replicas: 3, wantOK: false},
+		{name: "several replicas with shared state", shared: true, replicas: 3, wantOK: true},
+		{name: "several replicas with shared storage but process-local state", shared: true, replicas: 3, processLocal: ProcessLocal, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := CheckState(StorageMemory, tt.shared, tt.replicas, tt.processLocal)
+			assert.Equal(t, tt.wantOK, check.OK)
+			assert.Equal(t, StorageMemory, check.Backend)
+			assert.Equal(t, tt.processLocal, check.ProcessLocal)
+			if tt.wantOK {
+				assert.Empty(t, check.Message)
+			} else {
+				assert.Contains(t, check.Message, "3 replicas")
+			}
+			if tt.shared && !tt.wantOK {
+				// Storage is shared, so only the process-local state is in the way
+				assert.Contains(t, check.Message, "webhooks and background jobs are kept in process memory")
+			}
+		})
+	}
+}
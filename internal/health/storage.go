@@ -0,0 +1,138 @@
+package health
+
+import (
+	"sync"
+	"time"
+)
+
+// AvailabilityWindow is how far back StorageMonitor.Availability looks
+const AvailabilityWindow = 24 * time.Hour
+
+// StorageStatus describes whether the storage backend is currently reachable
+type StorageStatus struct {
+	Available        bool       `json:"available"`
+	UnavailableSince *time.Time `json:"unavailableSince,omitempty"`
+	LastError        string     `json:"lastError,omitempty"`
+}
+
+// outage is a past period during which the storage backend could not be reached
+type outage struct {
+	start, end time.Time
+}
+
+// StorageMonitor tracks whether the storage backend is reachable, based on the outcome
+// of the operations run against it. It is safe for concurrent use.
+type StorageMonitor struct {
+	now     func() time.Time
+	started time.Time
+
+	mu               sync.RWMutex
+	unavailableSince time.Time
+	lastError        string
+	outages          []outage // ended within AvailabilityWindow, oldest first
+}
+
+// NewStorageMonitor creates a StorageMonitor that considers storage available
+func NewStorageMonitor() *StorageMonitor {
+	return &StorageMonitor{
+		now:     time.Now,
+		started: time.Now(),
+	}
+}
+
+// MarkAvailable records that an operation reached the storage backend
+func (m *StorageMonitor) MarkAvailable() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.unavailableSince.IsZero() {
+		now := m.now()
+		m.outages = append(m.outages, outage{start: m.unavailableSince, end: now})
+		m.pruneOutages(now)
+	}
+	m.unavailableSince = time.Time{}
+	m.lastError = ""
+}
+
+// MarkUnavailable records that the storage backend could not be reached
+func (m *StorageMonitor) MarkUnavailable(err error, now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.unavailableSince.IsZero() {
+		m.unavailableSince = now
+	}
+	if err != nil {
+		m.lastError = err.Error()
+	}
+}
+
+// Available reports whether the last operation reached the storage backend
+func (m *StorageMonitor) Available() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.unavailableSince.IsZero()
+}
+
+// Status returns the current storage status
+func (m *StorageMonitor) Status() StorageStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	status := StorageStatus{
+		Available: m.unavailableSince.IsZero(),
+		LastError: m.lastError,
+	}
+	if !status.Available {
+		since := m.unavailableSince
+		status.UnavailableSince = &since
+	}
+	return status
+}
+
+// Availability returns the fraction, between 0 and 1, of the last AvailabilityWindow
+// during which the storage backend was reachable. Only the time since the monitor was
+// created counts, so a freshly started process reports on its own uptime.
+func (m *StorageMonitor) Availability(now time.Time) float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	from := now.Add(-AvailabilityWindow)
+	if m.started.After(from) {
+		from = m.started
+	}
+	total := now.Sub(from)
+	if total <= 0 {
+		return 1
+	}
+
+	var down time.Duration
+	overlap := func(start, end time.Time) {
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(now) {
+			end = now
+		}
+		if end.After(start) {
+			down += end.Sub(start)
+		}
+	}
+	for _, o := range m.outages {
+		overlap(o.start, o.end)
+	}
+	if !m.unavailableSince.IsZero() {
+		overlap(m.unavailableSince, now)
+	}
+	return 1 - float64(down)/float64(total)
+}
+
+// pruneOutages forgets outages that ended before AvailabilityWindow; callers hold the
+// write lock
+func (m *StorageMonitor) pruneOutages(now time.Time) {
+	from := now.Add(-AvailabilityWindow)
+	kept := m.outages[:0]
+	for _, o := range m.outages {
+		if o.end.After(from) {
+			kept = append(kept, o)
+		}
+	}
+	m.outages = kept
+}
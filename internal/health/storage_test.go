@@ -0,0 +1,34 @@
+package health
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorageMonitorAvailability(t *testing.T) {
+	start := time.Now()
+	monitor := NewStorageMonitor()
+	monitor.started = start
+	now := start
+	monitor.now = func() time.Time { return now }
+
+	assert.Equal(t, 1.0, monitor.Availability(start))
+
+	// Down for 15 minutes of the first hour
+	monitor.MarkUnavailable(fmt.Errorf("connection refused"), start.Add(30*time.Minute))
+	now = start.Add(45 * time.Minute)
+	monitor.MarkAvailable()
+	assert.InDelta(t, 0.75, monitor.Availability(start.Add(time.Hour)), 1e-9)
+
+	// An ongoing outage counts until now
+	monitor.MarkUnavailable(fmt.Errorf("connection refused"), start.Add(time.Hour))
+	assert.InDelta(t, 0.5, monitor.Availability(start.Add(90*time.Minute)), 1e-9)
+	now = start.Add(90 * time.Minute)
+	monitor.MarkAvailable()
+
+	// Outages older than the window no longer count
+	assert.Equal(t, 1.0, monitor.Availability(start.Add(90*time.Minute+AvailabilityWindow)))
+}
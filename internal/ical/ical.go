@@ -0,0 +1,188 @@
+// Package ical renders meetings as iCalendar (RFC 5545) files, which Outlook, Apple
+// Calendar and Google Calendar import.
+package ical
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"meetsync/internal/models"
+)
+
+const (
+	// ContentType is the media type of rendered files
+	ContentType = "text/calendar; charset=utf-8"
+	// InvitationContentType is the media type of invitations rendered by RenderInvitation
+	InvitationContentType = ContentType + "; method=REQUEST"
+	// productID identifies the application that created a file
+	productID = "-//MeetSync//MeetSync//EN"
+	// uidDomain makes event UIDs globally unique, as RFC 5545 recommends
+	uidDomain = "meetsync"
+	// timeLayout formats times in UTC
+	timeLayout = "20060102T150405Z"
+	// maxLineLength is the longest a line may be, in octets, before it is folded
+	maxLineLength = 75
+)
+
+// sequenceEpoch is when invitation sequence numbers start counting
+var sequenceEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// method is the METHOD property of a file, telling clients what to do with it
+type method string
+
+const (
+	methodPublish method = "PUBLISH"
+	methodRequest method = "REQUEST"
+)
+
+// Render writes event as an iCalendar file holding a single event, stamped at now. The
+// file is published rather than sent as an invitation, so importing it adds the event
+// without replying to the organizer. Importing it again updates the event, since its
+// UID is derived from the meeting.
+func Render(event models.CalendarEvent, now time.Time) []byte {
+	return render(event, now, methodPublish)
+}
+
+// RenderInvitation writes event as an invitation sent by the organizer to the attendees,
+// which mail clients offer to add to the calendar and answer. Invitations for the same
+// meeting update each other; the sequence number is taken from when the meeting was
+// last updated, so a rescheduled meeting supersedes the earlier invitation.
+func RenderInvitation(event models.CalendarEvent, now time.Time) []byte {
+	return render(event, now, methodRequest)
+}
+
+// render writes event as an iCalendar file with the given method
+func render(event models.CalendarEvent, now time.Time, method method) []byte {
+	var b bytes.Buffer
+	line := func(name, value string) {
+		writeFolded(&b, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", productID)
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", string(method))
+	line("BEGIN", "VEVENT")
+	line("UID", event.MeetingID+"@"+uidDomain)
+	line("DTSTAMP", formatTime(now))
+	line("DTSTART", formatTime(event.Slot.StartTime))
+	line("DTEND", formatTime(event.Slot.EndTime))
+	if !event.UpdatedAt.IsZero() {
+		line("LAST-MODIFIED", formatTime(event.UpdatedAt))
+	}
+	if method == methodRequest {
+		line("SEQUENCE", strconv.FormatInt(sequence(event.UpdatedAt), 10))
+	}
+	line("SUMMARY", escapeText(event.Title))
+	if event.Description != "" {
+		line("DESCRIPTION", escapeText(event.Description))
+	}
+	line("STATUS", string(status(event.Status)))
+	if event.Organizer.Email != "" {
+		line("ORGANIZER;CN="+escapeParam(event.Organizer.Name), "mailto:"+event.Organizer.Email)
+	}
+	for _, attendee := range event.Attendees {
+		if attendee.Email == "" {
+			continue
+		}
+		params := ";CN=" + escapeParam(attendee.Name) + ";ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION"
+		if method == methodRequest {
+			params += ";RSVP=TRUE"
+		}
+		line("ATTENDEE"+params, "mailto:"+attendee.Email)
+	}
+	line("END", "VEVENT")
+	line("END", "VCALENDAR")
+	return b.Bytes()
+}
+
+// eventStatus is the STATUS property of an event
+type eventStatus string
+
+const (
+	statusTentative eventStatus = "TENTATIVE"
+	statusConfirmed eventStatus = "CONFIRMED"
+	statusCancelled eventStatus = "CANCELLED"
+)
+
+// status returns the event status of a meeting in status; meetings that are not
+// confirmed yet are tentative
+func status(meetingStatus models.MeetingStatus) eventStatus {
+	switch meetingStatus {
+	case models.MeetingStatusConfirmed:
+		return statusConfirmed
+	case models.MeetingStatusCancelled:
+		return statusCancelled
+	default:
+		return statusTentative
+	}
+}
+
+// sequence returns the SEQUENCE of an invitation for a meeting last updated at updatedAt:
+// the seconds since 2020, which grow with every update and fit the 32-bit integer
+// clients expect well past 2080
+func sequence(updatedAt time.Time) int64 {
+	seconds := updatedAt.Unix() - sequenceEpoch.Unix()
+	if seconds < 0 {
+		return 0
+	}
+	return seconds
+}
+
+// formatTime formats t as a UTC date-time
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// escapeText escapes a TEXT value: backslashes, semicolons, commas and line breaks
+func escapeText(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(value)
+}
+
+// escapeParam makes value safe as a parameter value. Parameter values cannot contain
+// double quotes or line breaks, and must be quoted when they contain a colon, a
+// semicolon or a comma.
+func escapeParam(value string) string {
+	value = strings.Map(func(r rune) rune {
+		switch r {
+		case '"':
+			return -1
+		case '\r', '\n':
+			return ' '
+		}
+		return r
+	}, value)
+	if strings.ContainsAny(value, ":;,") {
+		return `"` + value + `"`
+	}
+	return value
+}
+
+// writeFolded writes a content line terminated by CRLF, folding it into lines of at
+// most 75 octets without splitting UTF-8 characters; continuation lines start with a space
+func writeFolded(b *bytes.Buffer, content string) {
+	limit := maxLineLength
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		b.WriteString(content[:cut])
+		b.WriteString("\r\n ")
+		content = content[cut:]
+		limit = maxLineLength - 1 // the leading space counts toward the line
+	}
+	b.WriteString(content)
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,86 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"meetsync/internal/models"
+)
+
+func TestRender(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip("time zone database unavailable")
+	}
+	start := time.Date(2030, 1, 7, 10, 0, 0, 0, paris)
+	event := models.CalendarEvent{
+		MeetingID: "meeting-1",
+		Title:     "Budget review, Q1; final",
+		Slot:      models.TimeSlot{StartTime: start, EndTime: start.Add(time.Hour)},
+		Status:    models.MeetingStatusConfirmed,
+		Organizer: models.User{Name: "Doe, Jane", Email: "jane@example.com"},
+		Attendees: []models.User{
+			{Name: "John \"JD\" Smith", Email: "john@example.com"},
+			{Name: "No email"},
+		},
+		UpdatedAt: time.Date(2029, 12, 1, 8, 0, 0, 0, time.UTC),
+	}
+
+	file := string(Render(event, time.Date(2029, 12, 2, 9, 30, 0, 0, time.UTC)))
+	assert.True(t, strings.HasPrefix(file, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//MeetSync//MeetSync//EN\r\n"))
+	assert.True(t, strings.HasSuffix(file, "END:VEVENT\r\nEND:VCALENDAR\r\n"))
+	assert.Contains(t, file, "\r\nUID:meeting-1@meetsync\r\n")
+	assert.Contains(t, file, "\r\nDTSTAMP:20291202T093000Z\r\n")
+	assert.Contains(t, file, "\r\nDTSTART:20300107T090000Z\r\nDTEND:20300107T100000Z\r\n")
+	assert.Contains(t, file, "\r\nSUMMARY:Budget review\\, Q1\\; final\r\n")
+	assert.Contains(t, file, "\r\nSTATUS:CONFIRMED\r\n")
+	assert.Contains(t, file, "\r\nORGANIZER;CN=\"Doe, Jane\":mailto:jane@example.com\r\n")
+	assert.Contains(t, file, "\r\nATTENDEE;CN=John JD Smith;ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION:mailto\r\n :john@example.com\r\n")
+	assert.Equal(t, 1, strings.Count(file, "ATTENDEE"))
+	assert.NotContains(t, file, "DESCRIPTION")
+
+	event.Status = models.MeetingStatusPending
+	assert.Contains(t, string(Render(event, time.Now())), "\r\nSTATUS:TENTATIVE\r\n")
+}
+
+func TestRenderInvitation(t *testing.T) {
+	start := time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC)
+	event := models.CalendarEvent{
+		MeetingID: "meeting-1",
+		Title:     "Planning",
+		Slot:      models.TimeSlot{StartTime: start, EndTime: start.Add(time.Hour)},
+		Status:    models.MeetingStatusConfirmed,
+		Organizer: models.User{Name: "Jane", Email: "jane@example.com"},
+		Attendees: []models.User{{Name: "John", Email: "john@example.com"}},
+		UpdatedAt: time.Date(2020, 1, 1, 0, 1, 0, 0, time.UTC),
+	}
+
+	file := string(RenderInvitation(event, time.Now()))
+	assert.Contains(t, file, "\r\nMETHOD:REQUEST\r\n")
+	assert.Contains(t, file, "\r\nSEQUENCE:60\r\n")
+	assert.Contains(t, file, "\r\nATTENDEE;CN=John;ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailt\r\n o:john@example.com\r\n")
+
+	// Rescheduling supersedes the earlier invitation
+	event.UpdatedAt = event.UpdatedAt.Add(time.Hour)
+	assert.Contains(t, string(RenderInvitation(event, time.Now())), "\r\nSEQUENCE:3660\r\n")
+
+	// Published files are not invitations
+	published := string(Render(event, time.Now()))
+	assert.Contains(t, published, "\r\nMETHOD:PUBLISH\r\n")
+	assert.NotContains(t, published, "SEQUENCE")
+	assert.NotContains(t, published, "RSVP")
+}
+
+func TestWriteFolded(t *testing.T) {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimSuffix(string(Render(models.CalendarEvent{
+		Title: strings.Repeat("é", 100),
+	}, time.Now())), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), maxLineLength)
+		b.WriteString(strings.TrimPrefix(line, " "))
+	}
+	assert.Contains(t, b.String(), "SUMMARY:"+strings.Repeat("é", 100))
+}
@@ -0,0 +1,147 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+)
+
+const (
+	// googleBaseURL is the Google Calendar API the free/busy query is sent to
+	googleBaseURL = "https://www.googleapis.com/calendar/v3"
+	// googleCalendarID is the calendar queried, the primary calendar of the token owner
+	googleCalendarID = "primary"
+	// googleTimeout caps how long Google Calendar can take to answer
+	googleTimeout = 10 * time.Second
+	// maxGoogleResponseSize caps the size of the answers read from Google Calendar
+	maxGoogleResponseSize = 1 << 20
+)
+
+// Google reads busy times from the primary calendar of a Google account with the free/busy
+// API. Access tokens need the calendar.freebusy scope, or any broader calendar scope.
+type Google struct {
+	baseURL string
+	client  *http.Client
+}
+
+var _ Provider = (*Google)(nil) // Verify Google implements Provider
+
+// GoogleOption configures a Google provider
+type GoogleOption func(*Google)
+
+// WithGoogleBaseURL sets the URL of the Google Calendar API, such as a test server's
+func WithGoogleBaseURL(baseURL string) GoogleOption {
+	return func(g *Google) {
+		g.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// NewGoogle creates a provider reading busy times from Google Calendar
+func NewGoogle(opts ...GoogleOption) *Google {
+	g := &Google{
+		baseURL: googleBaseURL,
+		client:  &http.Client{Timeout: googleTimeout},
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// googleFreeBusyRequest is the body of a free/busy query
+type googleFreeBusyRequest struct {
+	TimeMin string               `json:"timeMin"`
+	TimeMax string               `json:"timeMax"`
+	Items   []googleCalendarItem `json:"items"`
+}
+
+type googleCalendarItem struct {
+	ID string `json:"id"`
+}
+
+// googleFreeBusyResponse is the answer to a free/busy query
+type googleFreeBusyResponse struct {
+	Calendars map[string]struct {
+		Busy []struct {
+			Start time.Time `json:"start"`
+			End   time.Time `json:"end"`
+		} `json:"busy"`
+		Errors []struct {
+			Reason string `json:"reason"`
+		} `json:"errors"`
+	} `json:"calendars"`
+}
+
+// BusyTimes implements Provider. Rejected tokens are validation errors, since the client
+// has to get a new one; throttling and server errors are unavailable errors.
+func (g *Google) BusyTimes(ctx context.Context, accessToken string, from, to time.Time) ([]models.TimeSlot, error) {
+	body, err := json.Marshal(googleFreeBusyRequest{
+		TimeMin: from.UTC().Format(time.RFC3339),
+		TimeMax: to.UTC().Format(time.RFC3339),
+		Items:   []googleCalendarItem{{ID: googleCalendarID}},
+	})
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to encode free/busy query", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.baseURL+"/freeBusy", bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to create free/busy query", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, errors.NewDependencyError("Failed to reach Google Calendar", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, errors.NewValidationError("Google rejected the access token",
+			"the token may have expired or lack the https://www.googleapis.com/auth/calendar.freebusy scope")
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError:
+		return nil, errors.NewUnavailableError("Google Calendar is unavailable", retryAfter(resp.Header.Get("Retry-After")),
+			fmt.Errorf("google calendar answered %d", resp.StatusCode))
+	case resp.StatusCode != http.StatusOK:
+		return nil, errors.NewInternalError("Google Calendar refused the free/busy query",
+			fmt.Errorf("google calendar answered %d", resp.StatusCode))
+	}
+
+	var answer googleFreeBusyResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxGoogleResponseSize)).Decode(&answer); err != nil {
+		return nil, errors.NewInternalError("Failed to decode free/busy answer", err)
+	}
+	calendar, ok := answer.Calendars[googleCalendarID]
+	if !ok {
+		return nil, errors.NewInternalError("Free/busy answer misses the primary calendar", nil)
+	}
+	if len(calendar.Errors) > 0 {
+		return nil, errors.NewInternalError("Google Calendar could not read the primary calendar",
+			fmt.Errorf("google calendar reported %s", calendar.Errors[0].Reason))
+	}
+
+	busy := make([]models.TimeSlot, 0, len(calendar.Busy))
+	for _, period := range calendar.Busy {
+		busy = append(busy, models.TimeSlot{StartTime: period.Start.UTC(), EndTime: period.End.UTC()})
+	}
+	return busy, nil
+}
+
+// retryAfter parses a Retry-After header given in seconds, returning zero otherwise
+func retryAfter(header string) time.Duration {
+	seconds, err := strconv.Atoi(header)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,65 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"meetsync/pkg/errors"
+)
+
+func TestGoogle_BusyTimes(t *testing.T) {
+	from := time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC)
+	to := from.Add(8 * time.Hour)
+
+	var query googleFreeBusyRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/freeBusy", r.URL.Path)
+		switch r.Header.Get("Authorization") {
+		case "Bearer valid":
+		case "Bearer throttled":
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		case "Bearer unreadable":
+			_, _ = w.Write([]byte(`{"calendars":{"primary":{"busy":[],"errors":[{"domain":"global","reason":"notFound"}]}}}`))
+			return
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&query))
+		_, _ = w.Write([]byte(`{"kind":"calendar#freeBusy","calendars":{"primary":{"busy":[
+			{"start":"2030-01-07T10:00:00+01:00","end":"2030-01-07T11:30:00+01:00"},
+			{"start":"2030-01-07T14:00:00Z","end":"2030-01-07T15:00:00Z"}]}}}`))
+	}))
+	defer server.Close()
+	google := NewGoogle(WithGoogleBaseURL(server.URL + "/"))
+
+	busy, err := google.BusyTimes(context.Background(), "valid", from, to)
+	require.NoError(t, err)
+	assert.Equal(t, "2030-01-07T09:00:00Z", query.TimeMin)
+	assert.Equal(t, "2030-01-07T17:00:00Z", query.TimeMax)
+	assert.Equal(t, []googleCalendarItem{{ID: "primary"}}, query.Items)
+	require.Len(t, busy, 2)
+	assert.Equal(t, time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC), busy[0].StartTime)
+	assert.Equal(t, time.Date(2030, 1, 7, 10, 30, 0, 0, time.UTC), busy[0].EndTime)
+
+	_, err = google.BusyTimes(context.Background(), "expired", from, to)
+	assert.True(t, errors.Is(err, errors.ErrValidation))
+
+	_, err = google.BusyTimes(context.Background(), "throttled", from, to)
+	assert.True(t, errors.Is(err, errors.ErrUnavailable))
+	appErr, _ := errors.AsAppError(err)
+	assert.Equal(t, 30*time.Second, appErr.RetryAfter)
+
+	_, err = google.BusyTimes(context.Background(), "unreadable", from, to)
+	assert.Equal(t, errors.ErrorTypeInternal, errors.TypeOf(err))
+}
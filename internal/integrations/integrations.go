@@ -0,0 +1,26 @@
+// Package integrations reads the busy times of users from the calendar providers they
+// sign in to, with OAuth access tokens they grant MeetSync. Tokens are used for a single
+// lookup and never stored.
+package integrations
+
+import (
+	"context"
+	"time"
+
+	"meetsync/internal/models"
+)
+
+// ProviderGoogle identifies Google Calendar
+const ProviderGoogle = "google"
+
+// Provider reads the busy times of the user owning an access token between from and to
+type Provider interface {
+	BusyTimes(ctx context.Context, accessToken string, from, to time.Time) ([]models.TimeSlot, error)
+}
+
+// DefaultProviders returns the providers supported out of the box, by name
+func DefaultProviders() map[string]Provider {
+	return map[string]Provider{
+		ProviderGoogle: NewGoogle(),
+	}
+}
@@ -1,22 +1,106 @@
 package interfaces
 
-import "meetsync/internal/models"
+import (
+	"context"
+	"time"
+
+	"meetsync/internal/models"
+)
 
 // UserService defines the interface for user-related business logic
 type UserService interface {
 	CreateUser(name, email string) (models.User, error)
 	GetUserByID(userID string) (models.User, error)
+	GetUsersByIDs(userIDs []string) ([]models.User, error)
 	ListUsers() ([]models.User, error)
+	ImportUsers(rows []models.UserImportRow, options models.UserImportOptions) (models.UserImportResult, error)
+	ProvisionUser(input models.UserProvisioning) (models.User, error)
+	EnsureGuest(email, name string) (models.User, error)
+	UpdateProvisionedUser(userID string, input models.UserProvisioning) (models.User, error)
+	DeleteUser(userID string) error
+	RequestEmailChange(userID, newEmail string) (models.UserToken, error)
+	ConfirmEmailChange(token string) (models.User, error)
+	VerifyEmail(token string) (models.User, error)
+	ResendVerification(userID string) error
+	SetFocusBlocks(userID string, blocks []models.FocusBlock) (models.User, error)
+	SetDigest(userID string, frequency models.DigestFrequency) (models.User, error)
+	RecordDigest(userID string, sentAt time.Time) error
+	SetDoNotDisturb(userID string, quietHours *models.QuietHours, snoozedUntil *time.Time) (models.User, error)
+	SetUserMetadata(userID string, metadata models.Metadata) (models.User, error)
+	CreateAccessToken(userID, name string, scopes []models.Scope) (models.PersonalAccessToken, string, error)
+	ListAccessTokens(userID string) ([]models.PersonalAccessToken, error)
+	RevokeAccessToken(userID, tokenID string) error
+	RevokeAccessTokens(userID string) (int, error)
+	ListSessions(userID string) ([]models.Session, error)
+	AuthenticateAccessToken(secret string, client models.TokenClient) (models.PersonalAccessToken, error)
+	Bootstrap(bootstrap models.Bootstrap) (models.BootstrapResult, error)
+	Snapshot() (models.UserStoreSnapshot, error)
+	Restore(snapshot models.UserStoreSnapshot) error
 }
 
 // MeetingService defines the interface for meeting-related business logic
 type MeetingService interface {
-	CreateMeeting(title string, organizerID string, estimatedDuration int, proposedSlots []models.TimeSlot, participantIDs []string) (models.Meeting, error)
-	GetRecommendations(meetingID string) ([]models.RecommendedSlot, error)
-	UpdateMeeting(meetingID string, title string, estimatedDuration int, proposedSlots []models.TimeSlot, participantIDs []string) (models.Meeting, error)
+	CreateMeeting(input models.MeetingInput) (models.Meeting, []models.Warning, error)
+	PublishMeeting(meetingID string) (models.Meeting, error)
+	ConfirmMeeting(meetingID string, slot models.TimeSlot) (models.Meeting, error)
+	CancelMeeting(meetingID string) (models.Meeting, error)
+	ProposeAlternative(meetingID, participantID string, slot models.TimeSlot, reason string) (models.Proposal, error)
+	DecideProposal(meetingID, proposalID string, accept bool) (models.Meeting, models.Proposal, error)
+	ListMeetings(cursor string, limit int, filter models.MeetingFilter) ([]models.Meeting, string, error)
+	SearchMeetings(query string, limit int) ([]models.SearchResult, error)
+	GetRecommendations(ctx context.Context, meetingID, viewerID string) (models.RecommendationSet, error)
+	GetSplitRecommendations(meetingID string) ([]models.SplitRecommendation, error)
+	GetAvailabilityGrid(meetingID string) (models.AvailabilityGrid, error)
+	GetCalendarEvent(meetingID string) (models.CalendarEvent, error)
+	GetMeetingSummary(meetingID string) (models.MeetingSummary, error)
+	SimulateRecommendations(meetingID string, scenario models.RecommendationScenario) (models.RecommendationSimulation, error)
+	WaitForRecommendationChange(ctx context.Context, meetingID string, previous models.RecommendationSet, timeout time.Duration) (models.RecommendationSet, error)
+	PrecomputeRecommendations(meetingID string, requestedAt time.Time) error
+	UpdateMeeting(meetingID string, input models.MeetingInput) (models.Meeting, []models.Warning, error)
+	UpsertExternalMeeting(externalID string, input models.MeetingInput) (models.Meeting, bool, []models.Warning, error)
 	DeleteMeeting(meetingID string) error
-	AddAvailability(userID string, meetingID string, availableSlots []models.TimeSlot) (models.Availability, error)
-	UpdateAvailability(availabilityID string, availableSlots []models.TimeSlot) (models.Availability, error)
+	AddMeetingTags(meetingID string, tags []string) (models.Meeting, error)
+	RemoveMeetingTag(meetingID string, tag string) (models.Meeting, error)
+	GetTagStats() ([]models.TagStats, error)
+	AddAvailability(userID string, meetingID string, availableSlots []models.TimeSlot, tentative bool) (models.Availability, error)
+	UpdateAvailability(availabilityID string, availableSlots []models.TimeSlot, tentative bool) (models.Availability, error)
 	DeleteAvailability(availabilityID string) error
+	ConfirmAvailability(availabilityID string) (models.Availability, error)
+	RequestReconfirmation(meetingID string) ([]models.Availability, error)
 	GetAvailability(userID string, meetingID string) (models.Availability, error)
+	ListAvailabilities(meetingID string, filter models.AvailabilityFilter) ([]models.Availability, error)
+	SetAvailabilityMetadata(availabilityID, externalID string, metadata models.Metadata) (models.Availability, error)
+	RespondToInvitation(token string) (models.Availability, error)
+	GetPollLink(meetingID string) (string, error)
+	SendDigests(now time.Time) (models.DigestResult, error)
+	AddGuest(meetingID, email, name string) (models.GuestInvitation, error)
+	GetGuestMeeting(token string) (models.GuestMeeting, error)
+	SubmitGuestAvailability(token string, availableSlots []models.TimeSlot, tentative bool) (models.Availability, error)
+	ImportAvailability(userID, meetingID string, busy []models.TimeSlot, tentative bool) (models.Availability, error)
+	ImportCalendarAvailability(ctx context.Context, userID, meetingID, provider, accessToken string, tentative bool) (models.Availability, int, error)
+	GetMeetingTimeline(meetingID string) ([]models.TimelineEvent, error)
+	ReassignUser(fromUserID string, toUserID string) (models.UserReassignment, error)
+	AuthorizeMeetingChange(userID, meetingID string) error
+	AuthorizeAvailabilityChange(userID, availabilityID string) error
+	Snapshot() (models.MeetingStoreSnapshot, error)
+	Restore(snapshot models.MeetingStoreSnapshot) error
+}
+
+// AdminService defines the interface for administrative operations
+type AdminService interface {
+	MergeUsers(sourceUserID string, targetUserID string) (models.UserReassignment, error)
+	ImportUsers(rows []models.UserImportRow, options models.UserImportOptions) (models.UserImportResult, error)
+	Bootstrap(bootstrap models.Bootstrap) (models.BootstrapResult, error)
+	SyncDirectory(dryRun bool) (models.DirectorySyncResult, error)
+	SendDigests() (models.DigestResult, error)
+	ListAuditLog(cursor string, limit int) ([]models.AuditEntry, string, error)
+	Backup() (models.Backup, error)
+	VerifyBackup(backup models.Backup) (models.BackupSummary, error)
+	RestoreBackup(backup models.Backup) (models.BackupSummary, error)
+}
+
+// SchedulingService defines the interface for finding times outside of a meeting
+type SchedulingService interface {
+	FindTimes(query models.SchedulingQuery) ([]models.RecommendedSlot, error)
+	FreeBusy(userID string, from, to time.Time) (models.FreeBusy, error)
 }
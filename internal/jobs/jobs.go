@@ -0,0 +1,172 @@
+// Package jobs processes work accepted by requests in the background, such as large
+// batches, and keeps the outcome for clients to poll.
+package jobs
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+	"meetsync/pkg/logs"
+)
+
+const (
+	// defaultQueueSize caps the number of jobs waiting for a worker
+	defaultQueueSize = 64
+	// defaultRetention is how long finished jobs can still be polled
+	defaultRetention = time.Hour
+	// retryAfter is how long clients are asked to wait when the queue is full
+	retryAfter = 10 * time.Second
+)
+
+// Func is the work of a job; the result is returned to clients polling the job
+type Func func() (any, error)
+
+// Queue runs submitted jobs one at a time in the order they were submitted. Jobs are
+// kept in memory, so jobs still queued when the process exits are lost.
+type Queue struct {
+	mu        sync.RWMutex
+	jobs      map[string]*models.Job
+	queue     chan queuedJob
+	done      chan struct{}
+	once      sync.Once
+	closed    bool
+	run       func(func())
+	retention time.Duration
+	now       func() time.Time
+}
+
+type queuedJob struct {
+	id string
+	fn Func
+}
+
+// NewQueue creates a Queue and starts its worker. Jobs are run through run, such as
+// middleware.WriteGate.Run, so they can be gated like the requests that submitted them.
+func NewQueue(run func(func())) *Queue {
+	if run == nil {
+		run = func(job func()) { job() }
+	}
+	q := &Queue{
+		jobs:      make(map[string]*models.Job),
+		queue:     make(chan queuedJob, defaultQueueSize),
+		done:      make(chan struct{}),
+		run:       run,
+		retention: defaultRetention,
+		now:       time.Now,
+	}
+	go q.work()
+	return q
+}
+
+// Submit queues fn as a job of the given kind and returns the queued job. It fails with
+// an unavailable error when the queue is full or closed.
+func (q *Queue) Submit(kind string, fn Func) (models.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return models.Job{}, errors.NewUnavailableError("Job queue is shutting down", retryAfter, nil)
+	}
+	q.prune()
+
+	job := &models.Job{
+		ID:        uuid.New().String(),
+		Kind:      kind,
+		Status:    models.JobStatusQueued,
+		CreatedAt: q.now(),
+	}
+	select {
+	case q.queue <- queuedJob{id: job.ID, fn: fn}:
+	default:
+		return models.Job{}, errors.NewUnavailableError("Job queue is full", retryAfter, nil)
+	}
+	q.jobs[job.ID] = job
+	return *job, nil
+}
+
+// Get returns the current state of a job
+func (q *Queue) Get(id string) (models.Job, error) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	job, ok := q.jobs[id]
+	if !ok {
+		return models.Job{}, errors.NewNotFoundError("Job not found")
+	}
+	return *job, nil
+}
+
+// Close stops accepting jobs and waits for queued jobs to finish
+func (q *Queue) Close() error {
+	q.once.Do(func() {
+		q.mu.Lock()
+		q.closed = true
+		close(q.queue)
+		q.mu.Unlock()
+	})
+	<-q.done
+	return nil
+}
+
+// prune forgets jobs that finished longer ago than the retention; callers hold mu
+func (q *Queue) prune() {
+	cutoff := q.now().Add(-q.retention)
+	for id, job := range q.jobs {
+		if job.FinishedAt != nil && job.FinishedAt.Before(cutoff) {
+			delete(q.jobs, id)
+		}
+	}
+}
+
+func (q *Queue) work() {
+	defer close(q.done)
+	for queued := range q.queue {
+		q.update(queued.id, func(job *models.Job) {
+			startedAt := q.now()
+			job.Status = models.JobStatusRunning
+			job.StartedAt = &startedAt
+		})
+
+		var result any
+		var err error
+		q.run(func() {
+			result, err = runSafely(queued.fn)
+		})
+
+		q.update(queued.id, func(job *models.Job) {
+			finishedAt := q.now()
+			job.FinishedAt = &finishedAt
+			if err != nil {
+				job.Status = models.JobStatusFailed
+				job.Error = err.Error()
+				logs.Error("Job %s (%s) failed: %v", job.ID, job.Kind, err)
+				return
+			}
+			job.Status = models.JobStatusSucceeded
+			job.Result = result
+		})
+	}
+}
+
+// update changes a job under the lock
+func (q *Queue) update(id string, change func(*models.Job)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if job, ok := q.jobs[id]; ok {
+		change(job)
+	}
+}
+
+// runSafely runs fn, turning a panic into an error so one job cannot stop the worker
+func runSafely(fn Func) (result any, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("job panicked: %v", recovered)
+		}
+	}()
+	return fn()
+}
@@ -0,0 +1,90 @@
+package jobs
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+)
+
+func TestQueue(t *testing.T) {
+	var gated int
+	queue := NewQueue(func(job func()) {
+		gated++
+		job()
+	})
+
+	succeeded, err := queue.Submit("test", func() (any, error) { return "done", nil })
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusQueued, succeeded.Status)
+	failed, err := queue.Submit("test", func() (any, error) { return nil, fmt.Errorf("boom") })
+	require.NoError(t, err)
+	panicked, err := queue.Submit("test", func() (any, error) { panic("oops") })
+	require.NoError(t, err)
+
+	// Close waits for queued jobs, which run through the gate
+	require.NoError(t, queue.Close())
+	assert.Equal(t, 3, gated)
+
+	job, err := queue.Get(succeeded.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusSucceeded, job.Status)
+	assert.Equal(t, "done", job.Result)
+	assert.NotNil(t, job.StartedAt)
+	assert.NotNil(t, job.FinishedAt)
+
+	job, err = queue.Get(failed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusFailed, job.Status)
+	assert.Equal(t, "boom", job.Error)
+
+	job, err = queue.Get(panicked.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.JobStatusFailed, job.Status)
+	assert.Contains(t, job.Error, "oops")
+
+	// Closed queues refuse new jobs
+	_, err = queue.Submit("test", func() (any, error) { return nil, nil })
+	assert.True(t, errors.IsUnavailable(err))
+
+	_, err = queue.Get("unknown")
+	assert.Equal(t, errors.ErrorTypeNotFound, errors.TypeOf(err))
+}
+
+func TestQueueFullAndRetention(t *testing.T) {
+	release := make(chan struct{})
+	queue := NewQueue(func(job func()) {
+		<-release
+		job()
+	})
+
+	// The worker holds one job while defaultQueueSize more wait for it
+	first, err := queue.Submit("test", func() (any, error) { return nil, nil })
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		job, _ := queue.Get(first.ID)
+		return job.Status == models.JobStatusRunning
+	}, time.Second, time.Millisecond)
+	for i := 0; i < defaultQueueSize; i++ {
+		_, err := queue.Submit("test", func() (any, error) { return nil, nil })
+		require.NoError(t, err)
+	}
+	_, err = queue.Submit("test", func() (any, error) { return nil, nil })
+	assert.True(t, errors.IsUnavailable(err))
+
+	close(release)
+	require.NoError(t, queue.Close())
+
+	// Finished jobs are forgotten once the retention passed
+	queue.now = func() time.Time { return time.Now().Add(2 * defaultRetention) }
+	queue.mu.Lock()
+	queue.prune()
+	queue.mu.Unlock()
+	_, err = queue.Get(first.ID)
+	assert.Equal(t, errors.ErrorTypeNotFound, errors.TypeOf(err))
+}
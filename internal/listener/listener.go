@@ -0,0 +1,98 @@
+// Package listener creates the listener the HTTP server accepts connections on: a TCP
+// port, a Unix domain socket for local reverse proxies, or a socket passed by systemd
+package listener
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Systemd is the address selecting the socket passed by systemd socket activation
+	Systemd = "systemd"
+	// unixPrefix starts addresses selecting a Unix domain socket
+	unixPrefix = "unix:"
+	// listenFDsStart is the first file descriptor systemd passes sockets from
+	listenFDsStart = 3
+)
+
+// Config configures where the server listens
+type Config struct {
+	Address    string      // empty for TCP on Port, "unix:" and a socket path, or "systemd"
+	Port       string      // TCP port, used when Address is empty
+	ReusePort  bool        // let other processes listen on Port too, see Listen
+	SocketMode os.FileMode // permissions of a Unix domain socket
+}
+
+// Listen returns a listener for the address in config. With ReusePort, the TCP port is
+// opened with SO_REUSEPORT, so a new process can start listening on the port before the
+// previous one stops accepting connections and drains its in-flight requests.
+func Listen(config Config) (net.Listener, error) {
+	switch {
+	case config.Address == "":
+		var lc net.ListenConfig
+		if config.ReusePort {
+			lc.Control = reusePort
+		}
+		return lc.Listen(context.Background(), "tcp", ":"+config.Port)
+	case config.Address == Systemd:
+		return systemdListener()
+	case strings.HasPrefix(config.Address, unixPrefix):
+		return unixListener(strings.TrimPrefix(config.Address, unixPrefix), config.SocketMode)
+	default:
+		return nil, fmt.Errorf("invalid listen address %q: use unix:<path> or systemd", config.Address)
+	}
+}
+
+// unixListener listens on a Unix domain socket at path, replacing a socket left behind
+// by a previous process. The socket is removed when the listener is closed.
+func unixListener(path string, mode os.FileMode) (net.Listener, error) {
+	if path == "" {
+		return nil, fmt.Errorf("Unix socket path is required")
+	}
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("cannot listen on %s: file exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket %s: %w", path, err)
+		}
+	}
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if mode != 0 {
+		if err := os.Chmod(path, mode); err != nil {
+			l.Close()
+			return nil, fmt.Errorf("failed to set permissions of socket %s: %w", path, err)
+		}
+	}
+	return l, nil
+}
+
+// systemdListener returns the first socket passed by systemd socket activation. The
+// activation variables are unset so that child processes do not inherit them.
+func systemdListener() (net.Listener, error) {
+	pid, _ := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	fds, _ := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if pid != os.Getpid() || fds < 1 {
+		return nil, fmt.Errorf("no socket passed by systemd: LISTEN_PID and LISTEN_FDS are not set for this process")
+	}
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	file := os.NewFile(listenFDsStart, "systemd-socket")
+	defer file.Close()
+	l, err := net.FileListener(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to use socket passed by systemd: %w", err)
+	}
+	return l, nil
+}
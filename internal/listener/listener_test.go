@@ -0,0 +1,82 @@
+package listener
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListen_Unix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "meetsync.sock")
+
+	l, err := Listen(Config{Address: "unix:" + path, SocketMode: 0o660})
+	require.NoError(t, err)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o660), info.Mode().Perm())
+
+	conn, err := net.Dial("unix", path)
+	require.NoError(t, err)
+	conn.Close()
+	l.Close()
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "socket is removed on close")
+}
+
+func TestListen_UnixReplacesStaleSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "meetsync.sock")
+	stale, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	l, err := Listen(Config{Address: "unix:" + path})
+	require.NoError(t, err)
+	l.Close()
+}
+
+func TestListen_UnixRefusesOtherFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "meetsync.sock")
+	require.NoError(t, os.WriteFile(path, []byte("data"), 0o600))
+
+	_, err := Listen(Config{Address: "unix:" + path})
+	assert.ErrorContains(t, err, "not a socket")
+	_, err = os.Stat(path)
+	assert.NoError(t, err, "file is kept")
+}
+
+func TestListen_Systemd(t *testing.T) {
+	t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()+1))
+	t.Setenv("LISTEN_FDS", "1")
+
+	_, err := Listen(Config{Address: Systemd})
+	assert.ErrorContains(t, err, "no socket passed by systemd")
+}
+
+func TestListen_InvalidAddress(t *testing.T) {
+	_, err := Listen(Config{Address: "tcp:8080"})
+	assert.ErrorContains(t, err, "invalid listen address")
+
+	_, err = Listen(Config{Address: "unix:"})
+	assert.ErrorContains(t, err, "path is required")
+}
+
+func TestListen_ReusePort(t *testing.T) {
+	first, err := Listen(Config{Port: "0", ReusePort: true})
+	require.NoError(t, err)
+	defer first.Close()
+	_, port, err := net.SplitHostPort(first.Addr().String())
+	require.NoError(t, err)
+
+	second, err := Listen(Config{Port: port, ReusePort: true})
+	require.NoError(t, err, "a second process can listen on the port")
+	second.Close()
+
+	_, err = Listen(Config{Port: port})
+	assert.Error(t, err, "the port is taken without SO_REUSEPORT")
+}
@@ -0,0 +1,13 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package listener
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// reusePort reports that SO_REUSEPORT is not supported on this platform
+func reusePort(network, address string, conn syscall.RawConn) error {
+	return fmt.Errorf("SO_REUSEPORT is not supported on this platform")
+}
@@ -0,0 +1,241 @@
+// Package metering records billable usage, such as meetings created or notifications
+// sent, to a pluggable sink for usage-based billing and keeps monthly totals.
+package metering
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"meetsync/pkg/logs"
+)
+
+// Kind identifies what a metering record counts
+type Kind string
+
+const (
+	// KindMeetingCreated counts created meetings
+	KindMeetingCreated Kind = "meeting_created"
+	// KindNotificationSent counts delivered notifications
+	KindNotificationSent Kind = "notification_sent"
+	// KindCalendarSync counts calls to a linked calendar for busy times
+	KindCalendarSync Kind = "calendar_sync"
+)
+
+const (
+	// BackendNone keeps monthly totals without sending records anywhere
+	BackendNone = "none"
+	// BackendLog writes records to the application log
+	BackendLog = "log"
+	// BackendHTTP posts records as JSON to a URL, such as a billing service
+	BackendHTTP = "http"
+)
+
+// monthLayout formats the calendar months records are summarized by
+const monthLayout = "2006-01"
+
+// Record is a single metered usage. IDs are unique so sinks can drop records
+// delivered twice.
+type Record struct {
+	ID       string    `json:"id"`
+	Kind     Kind      `json:"kind"`
+	Quantity int64     `json:"quantity"`
+	Subject  string    `json:"subject,omitempty"` // what was used, such as a meeting or user ID
+	Time     time.Time `json:"time"`
+}
+
+// Sink receives metering records
+type Sink interface {
+	Record(record Record) error
+	Close() error
+}
+
+// NewSink creates a Sink for the given backend. Records are delivered asynchronously
+// so a slow or unavailable billing service never delays API requests.
+func NewSink(backend, url string) (Sink, error) {
+	switch strings.ToLower(backend) {
+	case "", BackendNone:
+		return NoopSink{}, nil
+	case BackendLog:
+		return LogSink{}, nil
+	case BackendHTTP:
+		if url == "" {
+			return nil, fmt.Errorf("metering: HTTP backend requires a URL")
+		}
+		return NewAsyncSink(NewHTTPSink(url), defaultBufferSize), nil
+	default:
+		return nil, fmt.Errorf("metering: unknown backend %q", backend)
+	}
+}
+
+// NoopSink discards all records
+type NoopSink struct{}
+
+// Record implements Sink
+func (NoopSink) Record(record Record) error { return nil }
+
+// Close implements Sink
+func (NoopSink) Close() error { return nil }
+
+// LogSink writes records to the application log
+type LogSink struct{}
+
+// Record implements Sink
+func (LogSink) Record(record Record) error {
+	logs.Info("Metering: %s x%d subject=%s id=%s", record.Kind, record.Quantity, record.Subject, record.ID)
+	return nil
+}
+
+// Close implements Sink
+func (LogSink) Close() error { return nil }
+
+const defaultBufferSize = 1024
+
+// AsyncSink buffers records and sends them from a background goroutine
+type AsyncSink struct {
+	next   Sink
+	queue  chan Record
+	done   chan struct{}
+	once   sync.Once
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewAsyncSink wraps a Sink so that Record never blocks the caller
+func NewAsyncSink(next Sink, bufferSize int) *AsyncSink {
+	s := &AsyncSink{
+		next:  next,
+		queue: make(chan Record, bufferSize),
+		done:  make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Record enqueues the record, dropping it if the buffer is full
+func (s *AsyncSink) Record(record Record) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return fmt.Errorf("metering: sink is closed")
+	}
+
+	select {
+	case s.queue <- record:
+		return nil
+	default:
+		return fmt.Errorf("metering: buffer full, dropping record %s", record.ID)
+	}
+}
+
+// Close flushes buffered records and closes the underlying sink
+func (s *AsyncSink) Close() error {
+	s.once.Do(func() {
+		s.mu.Lock()
+		s.closed = true
+		close(s.queue)
+		s.mu.Unlock()
+	})
+	<-s.done
+	return s.next.Close()
+}
+
+func (s *AsyncSink) run() {
+	defer close(s.done)
+	for record := range s.queue {
+		if err := s.next.Record(record); err != nil {
+			logs.Error("Failed to send metering record %s: %v", record.ID, err)
+		}
+	}
+}
+
+// Summary is the metered usage of a calendar month
+type Summary struct {
+	Period            string `json:"period"` // "2006-01", in UTC
+	MeetingsCreated   int64  `json:"meetingsCreated"`
+	NotificationsSent int64  `json:"notificationsSent"`
+	CalendarSyncCalls int64  `json:"calendarSyncCalls"`
+}
+
+// Meter sends usage to a Sink and keeps monthly totals since the service started.
+// A nil Meter records nothing.
+type Meter struct {
+	sink Sink
+	now  func() time.Time
+
+	mu     sync.Mutex
+	totals map[string]map[Kind]int64 // quantities by month and kind
+}
+
+// NewMeter creates a Meter sending records to sink
+func NewMeter(sink Sink) *Meter {
+	return &Meter{
+		sink:   sink,
+		now:    time.Now,
+		totals: make(map[string]map[Kind]int64),
+	}
+}
+
+// Record meters a single use of kind by subject
+func (m *Meter) Record(kind Kind, subject string) {
+	if m == nil {
+		return
+	}
+
+	record := Record{
+		ID:       uuid.New().String(),
+		Kind:     kind,
+		Quantity: 1,
+		Subject:  subject,
+		Time:     m.now().UTC(),
+	}
+
+	m.mu.Lock()
+	month := record.Time.Format(monthLayout)
+	if m.totals[month] == nil {
+		m.totals[month] = make(map[Kind]int64)
+	}
+	m.totals[month][kind] += record.Quantity
+	m.mu.Unlock()
+
+	if err := m.sink.Record(record); err != nil {
+		logs.Error("Failed to record %s usage: %v", kind, err)
+	}
+}
+
+// CurrentPeriod returns the month usage is currently recorded in
+func (m *Meter) CurrentPeriod() string {
+	if m == nil {
+		return time.Now().UTC().Format(monthLayout)
+	}
+	return m.now().UTC().Format(monthLayout)
+}
+
+// Summary returns the usage of period, a month formatted as "2006-01"
+func (m *Meter) Summary(period string) Summary {
+	summary := Summary{Period: period}
+	if m == nil {
+		return summary
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	totals := m.totals[period]
+	summary.MeetingsCreated = totals[KindMeetingCreated]
+	summary.NotificationsSent = totals[KindNotificationSent]
+	summary.CalendarSyncCalls = totals[KindCalendarSync]
+	return summary
+}
+
+// ParsePeriod validates a month formatted as "2006-01"
+func ParsePeriod(period string) error {
+	if _, err := time.Parse(monthLayout, period); err != nil {
+		return fmt.Errorf("metering: invalid period %q, use YYYY-MM", period)
+	}
+	return nil
+}
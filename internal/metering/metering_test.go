@@ -0,0 +1,122 @@
+package metering
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"meetsync/internal/notifications"
+)
+
+// recordingSink keeps every record it receives
+type recordingSink struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+func (s *recordingSink) Record(record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+	return nil
+}
+
+func (s *recordingSink) Close() error { return nil }
+
+// failingNotifier fails every delivery
+type failingNotifier struct{}
+
+func (failingNotifier) Send(notification notifications.Notification) error {
+	return fmt.Errorf("smtp: connection refused")
+}
+
+func TestMeter(t *testing.T) {
+	sink := &recordingSink{}
+	meter := NewMeter(sink)
+	now := time.Date(2024, 3, 31, 23, 30, 0, 0, time.UTC)
+	meter.now = func() time.Time { return now }
+
+	meter.Record(KindMeetingCreated, "meeting-1")
+	meter.Record(KindMeetingCreated, "meeting-2")
+	meter.Record(KindCalendarSync, "user-1")
+	now = now.Add(time.Hour)
+	meter.Record(KindNotificationSent, "user-1")
+
+	assert.Equal(t, Summary{Period: "2024-03", MeetingsCreated: 2, CalendarSyncCalls: 1}, meter.Summary("2024-03"))
+	assert.Equal(t, Summary{Period: "2024-04", NotificationsSent: 1}, meter.Summary("2024-04"))
+	assert.Equal(t, "2024-04", meter.CurrentPeriod())
+
+	assert.Len(t, sink.records, 4)
+	assert.Equal(t, KindMeetingCreated, sink.records[0].Kind)
+	assert.Equal(t, "meeting-1", sink.records[0].Subject)
+	assert.Equal(t, int64(1), sink.records[0].Quantity)
+	assert.NotEqual(t, sink.records[0].ID, sink.records[1].ID)
+
+	var nilMeter *Meter
+	nilMeter.Record(KindMeetingCreated, "meeting-1")
+	assert.Equal(t, Summary{Period: "2024-03"}, nilMeter.Summary("2024-03"))
+}
+
+func TestNotifier(t *testing.T) {
+	meter := NewMeter(NoopSink{})
+
+	assert.NoError(t, Notifier(notifications.NoopNotifier{}, meter).Send(notifications.Notification{UserID: "user-1"}))
+	assert.Error(t, Notifier(failingNotifier{}, meter).Send(notifications.Notification{UserID: "user-1"}))
+
+	assert.Equal(t, int64(1), meter.Summary(meter.CurrentPeriod()).NotificationsSent)
+}
+
+func TestNewSink(t *testing.T) {
+	sink, err := NewSink("none", "")
+	assert.NoError(t, err)
+	assert.IsType(t, NoopSink{}, sink)
+
+	sink, err = NewSink("log", "")
+	assert.NoError(t, err)
+	assert.IsType(t, LogSink{}, sink)
+
+	_, err = NewSink("http", "")
+	assert.Error(t, err)
+
+	_, err = NewSink("stripe", "")
+	assert.Error(t, err)
+}
+
+func TestHTTPSink(t *testing.T) {
+	received := make(chan Record, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var record Record
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&record))
+		received <- record
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sink, err := NewSink("http", server.URL)
+	assert.NoError(t, err)
+	meter := NewMeter(sink)
+	meter.Record(KindMeetingCreated, "meeting-1")
+	assert.NoError(t, sink.Close())
+
+	record := <-received
+	assert.Equal(t, KindMeetingCreated, record.Kind)
+	assert.Equal(t, "meeting-1", record.Subject)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	assert.Error(t, NewHTTPSink(failing.URL).Record(record))
+}
+
+func TestParsePeriod(t *testing.T) {
+	assert.NoError(t, ParsePeriod("2024-03"))
+	assert.Error(t, ParsePeriod("2024-13"))
+	assert.Error(t, ParsePeriod("March"))
+}
@@ -0,0 +1,23 @@
+package metering
+
+import "meetsync/internal/notifications"
+
+// meteredNotifier meters every notification delivered by the wrapped Notifier
+type meteredNotifier struct {
+	next  notifications.Notifier
+	meter *Meter
+}
+
+// Notifier wraps next so that every delivered notification is metered
+func Notifier(next notifications.Notifier, meter *Meter) notifications.Notifier {
+	return meteredNotifier{next: next, meter: meter}
+}
+
+// Send implements notifications.Notifier
+func (n meteredNotifier) Send(notification notifications.Notification) error {
+	if err := n.next.Send(notification); err != nil {
+		return err
+	}
+	n.meter.Record(KindNotificationSent, notification.UserID)
+	return nil
+}
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+)
+
+// PoolStatser reports the statistics of a connection pool, such as a *sql.DB
+type PoolStatser interface {
+	Stats() sql.DBStats
+}
+
+// WritePoolPrometheus writes connection pool statistics in the Prometheus text format
+func WritePoolPrometheus(w io.Writer, pool PoolStatser) error {
+	stats := pool.Stats()
+	metrics := []struct {
+		name       string
+		help       string
+		metricType string
+		value      string
+	}{
+		{"meetsync_db_max_open_connections", "Maximum number of open database connections, 0 for unlimited.", "gauge", fmt.Sprint(stats.MaxOpenConnections)},
+		{"meetsync_db_open_connections", "Number of open database connections.", "gauge", fmt.Sprint(stats.OpenConnections)},
+		{"meetsync_db_in_use_connections", "Number of database connections in use.", "gauge", fmt.Sprint(stats.InUse)},
+		{"meetsync_db_idle_connections", "Number of idle database connections.", "gauge", fmt.Sprint(stats.Idle)},
+		{"meetsync_db_wait_count_total", "Total number of times a query waited for a free database connection.", "counter", fmt.Sprint(stats.WaitCount)},
+		{"meetsync_db_wait_duration_seconds_total", "Total time spent waiting for a free database connection.", "counter", fmt.Sprintf("%g", stats.WaitDuration.Seconds())},
+		{"meetsync_db_max_idle_closed_total", "Total number of connections closed because the pool had too many idle connections.", "counter", fmt.Sprint(stats.MaxIdleClosed)},
+		{"meetsync_db_max_idle_time_closed_total", "Total number of connections closed because they were idle too long.", "counter", fmt.Sprint(stats.MaxIdleTimeClosed)},
+		{"meetsync_db_max_lifetime_closed_total", "Total number of connections closed because they reached their maximum lifetime.", "counter", fmt.Sprint(stats.MaxLifetimeClosed)},
+	}
+	for _, m := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %s\n", m.name, m.help, m.name, m.metricType, m.name, m.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
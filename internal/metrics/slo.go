@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// SLOTracker tracks scheduling effectiveness metrics
+type SLOTracker struct {
+	mu                     sync.RWMutex
+	meetingsCreated        int64
+	meetingsRescheduled    int64
+	meetingsFinalized      int64
+	finalizedOnTopSlot     int64
+	availabilityResponses  int64
+	availabilityLatencySum time.Duration
+}
+
+// SLOReport is a point-in-time summary of scheduling effectiveness
+type SLOReport struct {
+	MeetingsCreated                   int64   `json:"meetingsCreated"`
+	MeetingsFinalized                 int64   `json:"meetingsFinalized"`
+	MeetingsFinalizedOnTopSlot        int64   `json:"meetingsFinalizedOnTopSlot"`
+	TopSlotFinalizationRate           float64 `json:"topSlotFinalizationRate"`
+	AvailabilityResponses             int64   `json:"availabilityResponses"`
+	AverageAvailabilityLatencySeconds float64 `json:"averageAvailabilityLatencySeconds"`
+	MeetingsRescheduled               int64   `json:"meetingsRescheduled"`
+	RescheduleRate                    float64 `json:"rescheduleRate"`
+}
+
+// NewSLOTracker creates a new SLOTracker
+func NewSLOTracker() *SLOTracker {
+	return &SLOTracker{}
+}
+
+// RecordMeetingCreated records the creation of a meeting
+func (t *SLOTracker) RecordMeetingCreated() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.meetingsCreated++
+}
+
+// RecordAvailabilityResponse records how long a participant took to respond to a meeting
+func (t *SLOTracker) RecordAvailabilityResponse(latency time.Duration) {
+	if latency < 0 {
+		latency = 0
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.availabilityResponses++
+	t.availabilityLatencySum += latency
+}
+
+// RecordReschedule records a change to a meeting's proposed or finalized time
+func (t *SLOTracker) RecordReschedule() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.meetingsRescheduled++
+}
+
+// RecordFinalization records a finalized meeting and whether the top recommended slot was chosen
+func (t *SLOTracker) RecordFinalization(topSlot bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.meetingsFinalized++
+	if topSlot {
+		t.finalizedOnTopSlot++
+	}
+}
+
+// Report returns the current SLO report
+func (t *SLOTracker) Report() SLOReport {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	report := SLOReport{
+		MeetingsCreated:            t.meetingsCreated,
+		MeetingsFinalized:          t.meetingsFinalized,
+		MeetingsFinalizedOnTopSlot: t.finalizedOnTopSlot,
+		AvailabilityResponses:      t.availabilityResponses,
+		MeetingsRescheduled:        t.meetingsRescheduled,
+	}
+	if t.meetingsFinalized > 0 {
+		report.TopSlotFinalizationRate = float64(t.finalizedOnTopSlot) / float64(t.meetingsFinalized)
+	}
+	if t.availabilityResponses > 0 {
+		report.AverageAvailabilityLatencySeconds = t.availabilityLatencySum.Seconds() / float64(t.availabilityResponses)
+	}
+	if t.meetingsCreated > 0 {
+		report.RescheduleRate = float64(t.meetingsRescheduled) / float64(t.meetingsCreated)
+	}
+	return report
+}
+
+// WritePrometheus writes the tracked metrics in the Prometheus text exposition format
+func (t *SLOTracker) WritePrometheus(w io.Writer) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	metrics := []struct {
+		name       string
+		help       string
+		metricType string
+		value      string
+	}{
+		{"meetsync_meetings_created_total", "Total number of meetings created.", "counter", fmt.Sprint(t.meetingsCreated)},
+		{"meetsync_meetings_finalized_total", "Total number of meetings finalized.", "counter", fmt.Sprint(t.meetingsFinalized)},
+		{"meetsync_meetings_finalized_top_slot_total", "Total number of meetings finalized on the top recommended slot.", "counter", fmt.Sprint(t.finalizedOnTopSlot)},
+		{"meetsync_meetings_rescheduled_total", "Total number of meeting reschedules.", "counter", fmt.Sprint(t.meetingsRescheduled)},
+	}
+	for _, m := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %s\n", m.name, m.help, m.name, m.metricType, m.name, m.value); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "# HELP %[1]s Time between meeting creation and availability submission.\n# TYPE %[1]s summary\n%[1]s_sum %[2]g\n%[1]s_count %[3]d\n",
+		"meetsync_availability_response_latency_seconds", t.availabilityLatencySum.Seconds(), t.availabilityResponses)
+	return err
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSLOTracker_Report(t *testing.T) {
+	tracker := NewSLOTracker()
+
+	// Empty tracker reports zero rates rather than dividing by zero
+	assert.Equal(t, SLOReport{}, tracker.Report())
+
+	for i := 0; i < 4; i++ {
+		tracker.RecordMeetingCreated()
+	}
+	tracker.RecordAvailabilityResponse(2 * time.Hour)
+	tracker.RecordAvailabilityResponse(4 * time.Hour)
+	tracker.RecordReschedule()
+	tracker.RecordFinalization(true)
+	tracker.RecordFinalization(true)
+	tracker.RecordFinalization(false)
+
+	report := tracker.Report()
+	assert.Equal(t, int64(4), report.MeetingsCreated)
+	assert.Equal(t, int64(3), report.MeetingsFinalized)
+	assert.Equal(t, int64(2), report.MeetingsFinalizedOnTopSlot)
+	assert.InDelta(t, 2.0/3.0, report.TopSlotFinalizationRate, 0.0001)
+	assert.Equal(t, int64(2), report.AvailabilityResponses)
+	assert.InDelta(t, (3 * time.Hour).Seconds(), report.AverageAvailabilityLatencySeconds, 0.0001)
+	assert.Equal(t, int64(1), report.MeetingsRescheduled)
+	assert.InDelta(t, 0.25, report.RescheduleRate, 0.0001)
+}
+
+func TestSLOTracker_WritePrometheus(t *testing.T) {
+	tracker := NewSLOTracker()
+	tracker.RecordMeetingCreated()
+	tracker.RecordAvailabilityResponse(90 * time.Second)
+	tracker.RecordFinalization(true)
+
+	var buf bytes.Buffer
+	require.NoError(t, tracker.WritePrometheus(&buf))
+
+	output := buf.String()
+	assert.Contains(t, output, "# TYPE meetsync_meetings_created_total counter\nmeetsync_meetings_created_total 1\n")
+	assert.Contains(t, output, "meetsync_meetings_finalized_top_slot_total 1\n")
+	assert.Contains(t, output, "# TYPE meetsync_availability_response_latency_seconds summary\n")
+	assert.Contains(t, output, "meetsync_availability_response_latency_seconds_sum 90\n")
+	assert.Contains(t, output, "meetsync_availability_response_latency_seconds_count 1\n")
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// AccessLogCommon writes access logs in the Common Log Format
+	AccessLogCommon = "common"
+	// AccessLogCombined writes access logs in the Combined Log Format, which adds the
+	// referer and user agent to the Common Log Format
+	AccessLogCombined = "combined"
+)
+
+// clfTimeFormat is the timestamp layout of the Common Log Format
+const clfTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// AccessLog returns a middleware writing one line per completed request to out in the
+// Common or Combined Log Format, for tools such as GoAccess or AWStats
+func AccessLog(out io.Writer, format string) (func(http.Handler) http.Handler, error) {
+	if format != AccessLogCommon && format != AccessLogCombined {
+		return nil, fmt.Errorf("unknown access log format %q", format)
+	}
+
+	var mu sync.Mutex
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := &responseRecorder{ResponseWriter: w}
+			next.ServeHTTP(recorder, r)
+
+			line := accessLogLine(r, recorder, start, format)
+			mu.Lock()
+			defer mu.Unlock()
+			io.WriteString(out, line)
+		})
+	}, nil
+}
+
+// accessLogLine formats a completed request as a Common or Combined Log Format line.
+// The authenticated user is always logged as "-" so that user IDs stay out of access logs.
+func accessLogLine(r *http.Request, recorder *responseRecorder, start time.Time, format string) string {
+	host := remoteHost(r)
+	size := "-"
+	if recorder.bytes > 0 {
+		size = strconv.Itoa(recorder.bytes)
+	}
+
+	line := fmt.Sprintf("%s - - [%s] %s %d %s",
+		orDash(host),
+		start.Format(clfTimeFormat),
+		quote(fmt.Sprintf("%s %s %s", r.Method, r.URL.RequestURI(), r.Proto)),
+		recorder.Status(),
+		size,
+	)
+	if format == AccessLogCombined {
+		line += " " + quote(orDash(r.Referer())) + " " + quote(orDash(r.UserAgent()))
+	}
+	return line + "\n"
+}
+
+// quote wraps s in double quotes, escaping quotes, backslashes and control characters
+// so every request stays on one line
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, c := range s {
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(c)
+		case c < 0x20 || c == 0x7f:
+			fmt.Fprintf(&b, "\\x%02x", c)
+		default:
+			b.WriteRune(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
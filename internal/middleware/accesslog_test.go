@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessLog(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"ok":true}`))
+	})
+
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{
+			name:   "common",
+			format: AccessLogCommon,
+			want:   `^192\.0\.2\.1 - - \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] "POST /api/meetings\?limit=5 HTTP/1\.1" 201 11\n$`,
+		},
+		{
+			name:   "combined",
+			format: AccessLogCombined,
+			want:   `^192\.0\.2\.1 - - \[[^\]]+\] "POST /api/meetings\?limit=5 HTTP/1\.1" 201 11 "-" "curl/8\.0 \\"quoted\\""\n$`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			accessLog, err := AccessLog(&out, tt.format)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/meetings?limit=5", nil)
+			req.Header.Set("User-Agent", `curl/8.0 "quoted"`)
+			accessLog(handler).ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Regexp(t, regexp.MustCompile(tt.want), out.String())
+		})
+	}
+
+	_, err := AccessLog(&bytes.Buffer{}, "json")
+	assert.Error(t, err)
+}
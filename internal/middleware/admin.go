@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"meetsync/pkg/errors"
+)
+
+// AdminKeyHeader is the request header carrying the admin API key
+const AdminKeyHeader = "X-Admin-Key"
+
+// RequireAdminKey wraps a handler so it only runs for requests carrying the
+// configured admin API key. Admin endpoints are disabled when no key is set.
+func RequireAdminKey(apiKey string, handler ErrorHandler) ErrorHandler {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		if apiKey == "" {
+			return errors.NewUnauthorizedError("Admin API is disabled")
+		}
+		if !hasAdminKey(r, apiKey) {
+			return errors.NewUnauthorizedError("Invalid admin API key")
+		}
+		return handler(w, r)
+	}
+}
+
+// RequireBearerToken wraps a handler so it only runs for requests authenticated with
+// the configured bearer token, as sent by identity providers. The handler is
+// disabled when no token is set.
+func RequireBearerToken(token string, handler ErrorHandler) ErrorHandler {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		if token == "" {
+			return errors.NewUnauthorizedError("Endpoint is disabled")
+		}
+		provided, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			return errors.NewUnauthorizedError("Invalid bearer token")
+		}
+		return handler(w, r)
+	}
+}
+
+// hasAdminKey reports whether r carries apiKey, which must not be empty
+func hasAdminKey(r *http.Request, apiKey string) bool {
+	provided := r.Header.Get(AdminKeyHeader)
+	return apiKey != "" && subtle.ConstantTimeCompare([]byte(provided), []byte(apiKey)) == 1
+}
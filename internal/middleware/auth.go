@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+)
+
+// AccessTokenKey is the context key for the personal access token that authenticated a request
+const AccessTokenKey contextKey = "accessToken"
+
+// AccessTokenAuthenticator resolves personal access token secrets
+type AccessTokenAuthenticator interface {
+	AuthenticateAccessToken(secret string, client models.TokenClient) (models.PersonalAccessToken, error)
+}
+
+// RequireScope wraps a handler so requests authenticated with a personal access
+// token must have been granted the scope. Requests without a bearer token are
+// passed through unchanged.
+func RequireScope(authenticator AccessTokenAuthenticator, scope models.Scope, handler ErrorHandler) ErrorHandler {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			return handler(w, r)
+		}
+
+		secret, found := strings.CutPrefix(header, "Bearer ")
+		if !found || secret == "" {
+			return errors.NewUnauthorizedError("Invalid authorization header")
+		}
+
+		token, err := authenticator.AuthenticateAccessToken(secret, tokenClient(r))
+		if err != nil {
+			return err
+		}
+		if !token.HasScope(scope) {
+			return errors.NewUnauthorizedError("Access token is missing the " + string(scope) + " scope")
+		}
+
+		return handler(w, r.WithContext(context.WithValue(r.Context(), AccessTokenKey, token)))
+	}
+}
+
+// RequireAccessToken wraps a handler so it only runs for requests carrying the admin
+// API key or a valid personal access token. Like RequireScope, it stores the token in
+// the request context, so handlers can check that its owner may act on the target.
+// Tokens of any scope are accepted.
+func RequireAccessToken(authenticator AccessTokenAuthenticator, apiKey string, handler ErrorHandler) ErrorHandler {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		if hasAdminKey(r, apiKey) {
+			return handler(w, r)
+		}
+
+		secret, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || secret == "" {
+			return errors.NewUnauthorizedError("An access token or the admin API key is required")
+		}
+
+		token, err := authenticator.AuthenticateAccessToken(secret, tokenClient(r))
+		if err != nil {
+			return err
+		}
+		return handler(w, r.WithContext(context.WithValue(r.Context(), AccessTokenKey, token)))
+	}
+}
+
+// Authorization decides whether a user may act on the resource a request targets,
+// returning a forbidden error when they may not
+type Authorization func(userID string, r *http.Request) error
+
+// RequireAuthorization wraps a handler so requests authenticated with a personal access
+// token only run when authorize allows the token's owner to act on the resource, for
+// instance because they organize the meeting. It must run after RequireScope; like it,
+// requests without a bearer token are passed through unchanged.
+func RequireAuthorization(authorize Authorization, handler ErrorHandler) ErrorHandler {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		token, ok := AccessTokenFromContext(r.Context())
+		if !ok {
+			return handler(w, r)
+		}
+		if err := authorize(token.UserID, r); err != nil {
+			return err
+		}
+		return handler(w, r)
+	}
+}
+
+// AccessTokenFromContext returns the personal access token that authenticated the request, if any
+func AccessTokenFromContext(ctx context.Context) (models.PersonalAccessToken, bool) {
+	token, ok := ctx.Value(AccessTokenKey).(models.PersonalAccessToken)
+	return token, ok
+}
+
+// maxUserAgentLength bounds the user agent recorded for a token, which clients choose freely
+const maxUserAgentLength = 256
+
+// tokenClient identifies the client that sent r
+func tokenClient(r *http.Request) models.TokenClient {
+	userAgent := r.UserAgent()
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = strings.ToValidUTF8(userAgent[:maxUserAgentLength], "")
+	}
+	return models.TokenClient{IP: remoteHost(r), UserAgent: userAgent}
+}
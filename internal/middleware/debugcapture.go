@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"meetsync/pkg/logs"
+)
+
+// DebugCaptureHeader asks for the bodies of a single request to be logged; it is only
+// honored together with a valid admin API key
+const DebugCaptureHeader = "X-Debug-Capture"
+
+// maxCapturedBody caps how much of each body is logged
+const maxCapturedBody = 16 << 10
+
+// redacted replaces the values of sensitive fields in captured bodies
+const redacted = "[redacted]"
+
+var (
+	// emailPattern matches email addresses in captured bodies
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	// secretPattern matches personal access token secrets and bearer credentials
+	secretPattern = regexp.MustCompile(`(msp_[A-Za-z0-9_\-]+|Bearer\s+[A-Za-z0-9._~+/\-]+=*)`)
+)
+
+// sensitiveFieldWords mark JSON fields whose names contain them as secrets
+var sensitiveFieldWords = []string{"token", "secret", "password", "key"}
+
+// DebugCapture returns a middleware logging the request and response bodies of requests
+// to the given route prefixes, and of requests carrying the X-Debug-Capture header and
+// the admin API key, to troubleshoot client integrations. Emails are masked and tokens
+// and other secrets are redacted before bodies are logged.
+func DebugCapture(routes []string, adminKey string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !captureRequested(r, routes, adminKey) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			requestID := RequestID(r.Context())
+			var requestBody []byte
+			if r.Body != nil {
+				requestBody, _ = io.ReadAll(r.Body)
+				r.Body = io.NopCloser(bytes.NewReader(requestBody))
+			}
+			logs.Info("[%s] Debug capture request %s %s: %s", requestID, r.Method, r.URL.Path, redactBody(requestBody))
+
+			capture := &captureWriter{responseRecorder: responseRecorder{ResponseWriter: w}}
+			next.ServeHTTP(capture, r)
+			logs.Info("[%s] Debug capture response %d: %s", requestID, capture.Status(), redactBody(capture.body.Bytes()))
+		})
+	}
+}
+
+// captureRequested reports whether the bodies of r should be logged
+func captureRequested(r *http.Request, routes []string, adminKey string) bool {
+	if r.Header.Get(DebugCaptureHeader) != "" && hasAdminKey(r, adminKey) {
+		return true
+	}
+	for _, route := range routes {
+		if route != "" && strings.HasPrefix(r.URL.Path, route) {
+			return true
+		}
+	}
+	return false
+}
+
+// captureWriter records the status and the beginning of the body of a response
+type captureWriter struct {
+	responseRecorder
+	body bytes.Buffer
+}
+
+// Write copies up to maxCapturedBody bytes of the response
+func (cw *captureWriter) Write(b []byte) (int, error) {
+	if remaining := maxCapturedBody - cw.body.Len(); remaining > 0 {
+		cw.body.Write(b[:min(len(b), remaining)])
+	}
+	return cw.responseRecorder.Write(b)
+}
+
+// redactBody returns a body safe to log: JSON bodies have sensitive fields redacted
+// and emails masked, other bodies have emails and token-like strings replaced
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return "(empty)"
+	}
+	truncated := len(body) > maxCapturedBody
+	if truncated {
+		body = body[:maxCapturedBody]
+	}
+
+	var value interface{}
+	var out string
+	if !truncated && json.Unmarshal(body, &value) == nil {
+		encoded, _ := json.Marshal(redactValue(value))
+		out = string(encoded)
+	} else {
+		out = redactText(string(body))
+	}
+	if truncated {
+		out += " (truncated)"
+	}
+	return out
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isSensitiveField(key) {
+				v[key] = redacted
+			} else {
+				v[key] = redactValue(field)
+			}
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+		return v
+	case string:
+		return redactText(v)
+	default:
+		return v
+	}
+}
+
+func redactText(s string) string {
+	s = secretPattern.ReplaceAllString(s, redacted)
+	return emailPattern.ReplaceAllStringFunc(s, logs.MaskEmail)
+}
+
+func isSensitiveField(name string) bool {
+	name = strings.ToLower(name)
+	for _, word := range sensitiveFieldWords {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"meetsync/pkg/logs"
+)
+
+func TestDebugCapture(t *testing.T) {
+	var buf bytes.Buffer
+	logs.SetDefaultLogger(logs.New("info", &buf))
+	defer logs.SetDefaultLogger(logs.New("info", os.Stdout))
+
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+	})
+
+	tests := []struct {
+		name        string
+		path        string
+		adminKey    string
+		wantCapture bool
+	}{
+		{name: "configured route", path: "/api/users", wantCapture: true},
+		{name: "header with admin key", path: "/api/meetings", adminKey: "admin-secret", wantCapture: true},
+		{name: "header with wrong admin key", path: "/api/meetings", adminKey: "guess"},
+		{name: "other route", path: "/api/meetings"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			body := `{"name":"Jane","email":"jane.doe@example.com","token":"abc123","note":"call with Bearer xyz.789"}`
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(body))
+			if tt.adminKey != "" {
+				req.Header.Set(DebugCaptureHeader, "1")
+				req.Header.Set(AdminKeyHeader, tt.adminKey)
+			}
+			w := httptest.NewRecorder()
+
+			DebugCapture([]string{"/api/users"}, "admin-secret")(echo).ServeHTTP(w, req)
+
+			// The handler still sees the full request body
+			assert.Equal(t, body, w.Body.String())
+
+			captured := buf.String()
+			if tt.wantCapture {
+				assert.Contains(t, captured, "Debug capture request POST "+tt.path)
+				assert.Contains(t, captured, "Debug capture response 201")
+				assert.Contains(t, captured, `j***@example.com`)
+				assert.Contains(t, captured, `"token":"[redacted]"`)
+				assert.NotContains(t, captured, "jane.doe")
+				assert.NotContains(t, captured, "abc123")
+				assert.NotContains(t, captured, "xyz.789")
+			} else {
+				assert.Empty(t, captured)
+			}
+		})
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	assert.Equal(t, "(empty)", redactBody(nil))
+	assert.Equal(t, "token [redacted] for j***@example.com", redactBody([]byte("token msp_Ab1_x for jo@example.com")))
+	assert.Equal(t, `[{"accessToken":"[redacted]","id":"1"}]`, redactBody([]byte(`[{"id":"1","accessToken":"msp_x"}]`)))
+}
@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"meetsync/pkg/errors"
+)
+
+// DeduplicatedHeader marks responses replayed for a duplicate request
+const DeduplicatedHeader = "X-Deduplicated"
+
+// maxDeduplicatedBody caps the request bodies considered for deduplication
+const maxDeduplicatedBody = 1 << 20
+
+// Deduplicator collapses identical requests from the same caller arriving within a
+// window, such as a double-clicked submit button: the first request is handled and
+// its duplicates receive the same response instead of being applied again. Requests
+// are identical when their method, path, query, credentials and body match.
+type Deduplicator struct {
+	window  time.Duration
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[string]*dedupEntry
+}
+
+// dedupEntry is a handled or in-flight request and, once done is closed, its response
+type dedupEntry struct {
+	done        chan struct{}
+	completedAt time.Time
+	status      int
+	header      http.Header
+	body        []byte
+}
+
+// NewDeduplicator creates a Deduplicator collapsing identical requests within window;
+// a window of zero disables deduplication
+func NewDeduplicator(window time.Duration) *Deduplicator {
+	return &Deduplicator{
+		window:  window,
+		now:     time.Now,
+		entries: make(map[string]*dedupEntry),
+	}
+}
+
+// Wrap deduplicates requests to next
+func (d *Deduplicator) Wrap(next http.Handler) http.Handler {
+	if d.window <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxDeduplicatedBody+1))
+		if err != nil {
+			errors.WriteErrorWithRequestID(w, errors.NewValidationError("Failed to read request body", ""), RequestID(r.Context()))
+			return
+		}
+		// Larger bodies are passed on whole, the part read followed by the rest
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+		if len(body) > maxDeduplicatedBody {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := dedupKey(r, body)
+		entry, duplicate := d.claim(key)
+		if duplicate {
+			<-entry.done
+			replay(w, entry)
+			return
+		}
+
+		recorder := &bodyRecorder{responseRecorder: responseRecorder{ResponseWriter: w}}
+		defer func() {
+			d.complete(key, entry, recorder)
+		}()
+		next.ServeHTTP(recorder, r)
+	})
+}
+
+// claim returns the entry of an identical request handled within the window, or
+// registers a new in-flight entry for the caller to complete
+func (d *Deduplicator) claim(key string) (*dedupEntry, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	for k, entry := range d.entries {
+		if !entry.completedAt.IsZero() && now.Sub(entry.completedAt) >= d.window {
+			delete(d.entries, k)
+		}
+	}
+	if entry, exists := d.entries[key]; exists {
+		return entry, true
+	}
+	entry := &dedupEntry{done: make(chan struct{})}
+	d.entries[key] = entry
+	return entry, false
+}
+
+// complete stores the response of a handled request for its duplicates. Server errors
+// are handed to duplicates already waiting but not kept, so a retry runs again.
+func (d *Deduplicator) complete(key string, entry *dedupEntry, recorder *bodyRecorder) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	entry.status = recorder.Status()
+	entry.header = recorder.Header().Clone()
+	entry.body = recorder.body.Bytes()
+	entry.completedAt = d.now()
+	if entry.status >= http.StatusInternalServerError {
+		delete(d.entries, key)
+	}
+	close(entry.done)
+}
+
+// replay writes the stored response of the original request
+func replay(w http.ResponseWriter, entry *dedupEntry) {
+	for name, values := range entry.header {
+		if name == RequestIDHeader {
+			continue
+		}
+		w.Header()[name] = values
+	}
+	w.Header().Set(DeduplicatedHeader, "true")
+	w.WriteHeader(entry.status)
+	w.Write(entry.body)
+}
+
+// dedupKey identifies identical requests from the same caller
+func dedupKey(r *http.Request, body []byte) string {
+	h := sha256.New()
+	for _, part := range []string{r.Method, r.URL.RequestURI(), r.Header.Get("Authorization"), string(body)} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// bodyRecorder captures the status, headers and whole body of a response
+type bodyRecorder struct {
+	responseRecorder
+	body bytes.Buffer
+}
+
+// Write copies the response body
+func (br *bodyRecorder) Write(b []byte) (int, error) {
+	br.body.Write(b)
+	return br.responseRecorder.Write(b)
+}
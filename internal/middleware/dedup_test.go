@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeduplicator(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		if r.URL.Query().Get("slow") != "" {
+			<-release
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"call":` + string('0'+rune(n)) + `}`))
+	})
+
+	now := time.Now()
+	dedup := NewDeduplicator(2 * time.Second)
+	dedup.now = func() time.Time { return now }
+	wrapped := dedup.Wrap(handler)
+
+	send := func(target, auth, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set("Authorization", auth)
+		w := httptest.NewRecorder()
+		wrapped.ServeHTTP(w, req)
+		return w
+	}
+
+	// A duplicate within the window gets the original response
+	first := send("/api/availabilities", "Bearer a", `{"slots":1}`)
+	second := send("/api/availabilities", "Bearer a", `{"slots":1}`)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "application/json", second.Header().Get("Content-Type"))
+	assert.Equal(t, "true", second.Header().Get(DeduplicatedHeader))
+	assert.Empty(t, first.Header().Get(DeduplicatedHeader))
+
+	// Different bodies or callers are handled separately
+	send("/api/availabilities", "Bearer a", `{"slots":2}`)
+	send("/api/availabilities", "Bearer b", `{"slots":1}`)
+	assert.Equal(t, int32(3), calls.Load())
+
+	// After the window the request is handled again
+	now = now.Add(2 * time.Second)
+	send("/api/availabilities", "Bearer a", `{"slots":1}`)
+	assert.Equal(t, int32(4), calls.Load())
+
+	// Duplicates of an in-flight request wait for its response
+	var wg sync.WaitGroup
+	responses := make([]*httptest.ResponseRecorder, 3)
+	for i := range responses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			responses[i] = send("/api/availabilities?slow=1", "Bearer a", `{"slots":1}`)
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(5), calls.Load())
+	for _, w := range responses {
+		assert.Equal(t, responses[0].Body.String(), w.Body.String())
+	}
+}
+
+func TestDeduplicatorDisabled(t *testing.T) {
+	calls := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { calls++ })
+	wrapped := NewDeduplicator(0).Wrap(handler)
+
+	for i := 0; i < 2; i++ {
+		wrapped.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/availabilities", strings.NewReader(`{}`)))
+	}
+	assert.Equal(t, 2, calls)
+}
+
+func TestDeduplicatorDoesNotKeepServerErrors(t *testing.T) {
+	calls := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	wrapped := NewDeduplicator(time.Minute).Wrap(handler)
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		wrapped.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/availabilities", strings.NewReader(`{}`)))
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	}
+	assert.Equal(t, 2, calls)
+}
+
+func TestDeduplicatorPassesLargeBodiesWhole(t *testing.T) {
+	calls := 0
+	var received []byte
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		received, _ = io.ReadAll(r.Body)
+	})
+	wrapped := NewDeduplicator(time.Minute).Wrap(handler)
+
+	// Bodies over the limit are neither truncated nor deduplicated
+	body := strings.Repeat("x", 3*maxDeduplicatedBody)
+	for i := 0; i < 2; i++ {
+		wrapped.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/availabilities", strings.NewReader(body)))
+		assert.Equal(t, len(body), len(received))
+	}
+	assert.Equal(t, 2, calls)
+}
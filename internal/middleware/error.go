@@ -1,12 +1,15 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"runtime/debug"
+	"time"
 
 	"github.com/google/uuid"
 
+	"meetsync/internal/reporting"
 	"meetsync/pkg/errors"
 	"meetsync/pkg/logs"
 )
@@ -20,20 +23,59 @@ type contextKey string
 const (
 	// RequestIDKey is the context key for request ID
 	RequestIDKey contextKey = "requestID"
+	// RequestIDHeader is the response header carrying the request ID
+	RequestIDHeader = "X-Request-ID"
 )
 
+// RequestID returns the ID of the request handled with ctx, or an empty string
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(RequestIDKey).(string)
+	return requestID
+}
+
+// withRequestID makes sure r has a request ID, reusing the one assigned by an
+// outer middleware, and returns the request carrying it
+func withRequestID(w http.ResponseWriter, r *http.Request) (*http.Request, string) {
+	if requestID := RequestID(r.Context()); requestID != "" {
+		return r, requestID
+	}
+	requestID := uuid.New().String()
+	w.Header().Set(RequestIDHeader, requestID)
+	return r.WithContext(context.WithValue(r.Context(), RequestIDKey, requestID)), requestID
+}
+
+// errorReporter receives internal errors and panics handled by WithErrorHandling
+var errorReporter reporting.Reporter = reporting.NoopReporter{}
+
+// SetErrorReporter sets the reporter receiving internal errors and panics
+func SetErrorReporter(reporter reporting.Reporter) {
+	errorReporter = reporter
+}
+
+// reportError sends an internal error or panic to the error reporter
+func reportError(r *http.Request, requestID string, message string, err error, stack []byte) {
+	report := reporting.NewRequestReport(r, requestID)
+	report.Message = message
+	report.Error = fmt.Sprint(err)
+	report.Panic = stack != nil
+	report.Stack = string(stack)
+	if reportErr := errorReporter.Report(report); reportErr != nil {
+		logs.Warn("[%s] Failed to report error: %v", requestID, reportErr)
+	}
+}
+
 // WithErrorHandling wraps a handler function with error handling
 func WithErrorHandling(handler ErrorHandler) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// Generate request ID
-		requestID := uuid.New().String()
-		r = r.WithContext(r.Context())
+		r, requestID := withRequestID(w, r)
 
 		// Defer panic recovery
 		defer func() {
 			if err := recover(); err != nil {
-				// Log the stack trace
-				logs.Error("PANIC [%s] %v\n%s", requestID, err, debug.Stack())
+				// Log and report the stack trace
+				stack := debug.Stack()
+				logs.Error("PANIC [%s] %v\n%s", requestID, err, stack)
+				reportError(r, requestID, "Panic", fmt.Errorf("panic: %v", err), stack)
 
 				// Create an internal server error
 				appErr := errors.NewInternalError(
@@ -42,7 +84,7 @@ func WithErrorHandling(handler ErrorHandler) http.HandlerFunc {
 				)
 
 				// Write error response
-				errors.WriteError(w, appErr)
+				errors.WriteErrorWithRequestID(w, appErr, requestID)
 			}
 		}()
 
@@ -50,38 +92,82 @@ func WithErrorHandling(handler ErrorHandler) http.HandlerFunc {
 		err := handler(w, r)
 		if err != nil {
 			// Log the error with request ID
-			if appErr, ok := err.(*errors.AppError); ok {
+			if appErr, ok := errors.AsAppError(err); ok {
 				if appErr.Type == errors.ErrorTypeInternal {
 					logs.Error("[%s] Internal server error: %v", requestID, appErr.Err)
+					reportError(r, requestID, appErr.Message, appErr.Err, nil)
+				} else if errors.IsRetryable(appErr) {
+					logs.Warn("[%s] Dependency unavailable: %v (%v)", requestID, appErr, appErr.Err)
 				} else {
 					logs.Warn("[%s] Request error: %v", requestID, appErr)
 				}
 			} else {
 				logs.Error("[%s] Unexpected error: %v", requestID, err)
+				reportError(r, requestID, "Unexpected error", err, nil)
 			}
 
 			// Write error response
-			errors.WriteError(w, err)
+			errors.WriteErrorWithRequestID(w, err, requestID)
 		}
 	}
 }
 
-// RequestLogger logs incoming requests
+// RequestLogger logs every request once it completes, with its response status,
+// size and latency
 func RequestLogger(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		requestID := uuid.New().String()
+		r, requestID := withRequestID(w, r)
+		start := time.Now()
+		recorder := &responseRecorder{ResponseWriter: w}
 
-		// Log the incoming request
-		logs.Info("[%s] %s %s %s", requestID, r.Method, r.URL.Path, r.RemoteAddr)
-
-		// Add request ID to context
-		ctx := r.Context()
-		r = r.WithContext(ctx)
+		defer func() {
+			logs.Info("[%s] %s %s %d %dB %s %s", requestID, r.Method, r.URL.Path,
+				recorder.Status(), recorder.bytes, time.Since(start).Round(time.Microsecond), r.RemoteAddr)
+		}()
 
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(recorder, r)
 	})
 }
 
+// responseRecorder captures the status and size of a response
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+// WriteHeader records the status before writing it
+func (rr *responseRecorder) WriteHeader(status int) {
+	if rr.status == 0 {
+		rr.status = status
+	}
+	rr.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the bytes written; the status defaults to 200 like in net/http
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	if rr.status == 0 {
+		rr.status = http.StatusOK
+	}
+	n, err := rr.ResponseWriter.Write(b)
+	rr.bytes += n
+	return n, err
+}
+
+// Unwrap exposes the underlying ResponseWriter to http.ResponseController, so handlers
+// can still flush or extend deadlines
+func (rr *responseRecorder) Unwrap() http.ResponseWriter {
+	return rr.ResponseWriter
+}
+
+// Status returns the response status, 200 when nothing was written
+func (rr *responseRecorder) Status() int {
+	if rr.status == 0 {
+		return http.StatusOK
+	}
+	return rr.status
+}
+
 // Chain combines multiple middleware into a single middleware
 func Chain(middlewares ...func(http.Handler) http.Handler) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
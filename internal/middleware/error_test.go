@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"meetsync/pkg/errors"
+	"meetsync/pkg/logs"
+)
+
+func TestRequestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logs.SetDefaultLogger(logs.New("info", &buf))
+	defer logs.SetDefaultLogger(logs.New("info", os.Stdout))
+
+	handler := RequestLogger(WithErrorHandling(func(w http.ResponseWriter, r *http.Request) error {
+		// Deadlines can still be extended through the wrapped writer
+		assert.NoError(t, http.NewResponseController(w).SetWriteDeadline(time.Now().Add(time.Second)))
+		return errors.NewNotFoundError("Meeting not found")
+	}))
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/meetings/missing")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	requestID := resp.Header.Get(RequestIDHeader)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.NotEmpty(t, requestID)
+	assert.Contains(t, buf.String(), "["+requestID+"] GET /api/meetings/missing 404 ")
+}
+
+func TestResponseRecorder(t *testing.T) {
+	recorder := &responseRecorder{ResponseWriter: httptest.NewRecorder()}
+	assert.Equal(t, http.StatusOK, recorder.Status())
+
+	n, err := recorder.Write([]byte("hello"))
+	assert.NoError(t, err)
+	recorder.WriteHeader(http.StatusTeapot)
+
+	assert.Equal(t, 5, n)
+	assert.Equal(t, 5, recorder.bytes)
+	assert.Equal(t, http.StatusOK, recorder.Status())
+}
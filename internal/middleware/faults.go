@@ -0,0 +1,188 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"meetsync/pkg/errors"
+	"meetsync/pkg/logs"
+)
+
+// FaultInjectedHeader marks responses whose error was injected rather than real
+const FaultInjectedHeader = "X-Fault-Injected"
+
+// maxInjectedLatency caps the latency a fault rule can add to a request
+const maxInjectedLatency = time.Minute
+
+// faultExemptPrefix is never subject to fault injection, so operators can always reach
+// the admin API to turn injection off
+const faultExemptPrefix = "/api/admin/"
+
+// FaultRule injects latency and errors into the requests to a route
+type FaultRule struct {
+	Route        string  `json:"route"`                  // path prefix, optionally preceded by a method, e.g. "POST /api/availabilities"
+	MinLatencyMS int     `json:"minLatencyMs,omitempty"` // requests are delayed by a random duration between min and max
+	MaxLatencyMS int     `json:"maxLatencyMs,omitempty"`
+	ErrorRate    float64 `json:"errorRate,omitempty"`   // fraction of requests failed, between 0 and 1
+	ErrorStatus  int     `json:"errorStatus,omitempty"` // 429, 500, 503 (the default) or 504
+}
+
+// matches reports whether the rule applies to r
+func (rule FaultRule) matches(r *http.Request) bool {
+	method, path, found := strings.Cut(rule.Route, " ")
+	if !found {
+		method, path = "", rule.Route
+	}
+	return (method == "" || method == r.Method) && strings.HasPrefix(r.URL.Path, path)
+}
+
+// injectedError returns the error the rule fails requests with
+func (rule FaultRule) injectedError() *errors.AppError {
+	const message = "Injected fault"
+	switch rule.ErrorStatus {
+	case http.StatusTooManyRequests:
+		return errors.NewRateLimitedError(message, time.Second)
+	case http.StatusInternalServerError:
+		return errors.NewInternalError(message, nil)
+	case http.StatusGatewayTimeout:
+		return errors.NewTimeoutError(message, nil)
+	default:
+		return errors.NewUnavailableError(message, time.Second, nil)
+	}
+}
+
+// validate checks that the rule can be applied
+func (rule FaultRule) validate() error {
+	switch {
+	case strings.TrimSpace(rule.Route) == "":
+		return errors.NewValidationError("Invalid fault rule", "route is required")
+	case rule.MinLatencyMS < 0 || rule.MaxLatencyMS < rule.MinLatencyMS:
+		return errors.NewValidationError("Invalid fault rule", fmt.Sprintf("route %q: latencies must satisfy 0 <= minLatencyMs <= maxLatencyMs", rule.Route))
+	case time.Duration(rule.MaxLatencyMS)*time.Millisecond > maxInjectedLatency:
+		return errors.NewValidationError("Invalid fault rule", fmt.Sprintf("route %q: maxLatencyMs cannot exceed %d", rule.Route, maxInjectedLatency.Milliseconds()))
+	case rule.ErrorRate < 0 || rule.ErrorRate > 1:
+		return errors.NewValidationError("Invalid fault rule", fmt.Sprintf("route %q: errorRate must be between 0 and 1", rule.Route))
+	}
+	switch rule.ErrorStatus {
+	case 0, http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return nil
+	default:
+		return errors.NewValidationError("Invalid fault rule", fmt.Sprintf("route %q: errorStatus must be 429, 500, 503 or 504", rule.Route))
+	}
+}
+
+// FaultInjector delays and fails requests according to rules set at runtime, to test
+// how clients and the resilience layer cope with a slow or failing service. It is meant
+// for staging and only injects anything when enabled in the configuration. Requests are
+// delayed and failed before they are handled, so a failed request never takes effect.
+// It is safe for concurrent use.
+type FaultInjector struct {
+	enabled bool
+	random  func() float64
+	sleep   func(ctx context.Context, d time.Duration)
+
+	mu    sync.RWMutex
+	rules []FaultRule
+}
+
+// NewFaultInjector creates a FaultInjector without rules; a disabled injector never
+// injects faults and refuses rules
+func NewFaultInjector(enabled bool) *FaultInjector {
+	return &FaultInjector{
+		enabled: enabled,
+		random:  rand.Float64,
+		sleep:   sleepContext,
+	}
+}
+
+// Enabled reports whether faults can be injected
+func (f *FaultInjector) Enabled() bool {
+	return f.enabled
+}
+
+// Rules returns the rules in effect
+func (f *FaultInjector) Rules() []FaultRule {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return append([]FaultRule{}, f.rules...)
+}
+
+// SetRules replaces the rules in effect; the first rule matching a request applies.
+// An empty list stops injecting faults.
+func (f *FaultInjector) SetRules(rules []FaultRule) error {
+	if !f.enabled {
+		return errors.NewNotFoundError("Fault injection is disabled")
+	}
+	for _, rule := range rules {
+		if err := rule.validate(); err != nil {
+			return err
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rules = append([]FaultRule{}, rules...)
+	if len(rules) > 0 {
+		logs.Warn("Fault injection rules set for %d routes", len(rules))
+	} else {
+		logs.Info("Fault injection rules cleared")
+	}
+	return nil
+}
+
+// rule returns the first rule matching r
+func (f *FaultInjector) rule(r *http.Request) (FaultRule, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for _, rule := range f.rules {
+		if rule.matches(r) {
+			return rule, true
+		}
+	}
+	return FaultRule{}, false
+}
+
+// Wrap injects faults into requests to next
+func (f *FaultInjector) Wrap(next http.Handler) http.Handler {
+	if !f.enabled {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, faultExemptPrefix) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		rule, ok := f.rule(r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if rule.MaxLatencyMS > 0 {
+			spread := float64(rule.MaxLatencyMS - rule.MinLatencyMS)
+			latency := time.Duration(float64(rule.MinLatencyMS)+f.random()*spread) * time.Millisecond
+			f.sleep(r.Context(), latency)
+		}
+		if rule.ErrorRate > 0 && f.random() < rule.ErrorRate {
+			w.Header().Set(FaultInjectedHeader, "true")
+			errors.WriteErrorWithRequestID(w, rule.injectedError(), RequestID(r.Context()))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"meetsync/pkg/errors"
+)
+
+func TestFaultInjector(t *testing.T) {
+	injector := NewFaultInjector(true)
+	var slept time.Duration
+	injector.sleep = func(ctx context.Context, d time.Duration) { slept += d }
+	random := 0.0
+	injector.random = func() float64 { return random }
+
+	calls := 0
+	handler := injector.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+	}))
+	send := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	assert.NoError(t, injector.SetRules([]FaultRule{
+		{Route: "POST /api/availabilities", ErrorRate: 0.5, ErrorStatus: http.StatusGatewayTimeout},
+		{Route: "/api/", MinLatencyMS: 100, MaxLatencyMS: 300, ErrorRate: 1},
+	}))
+
+	// A request below the error rate fails before it is handled
+	random = 0.25
+	w := send(http.MethodPost, "/api/availabilities")
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Equal(t, "true", w.Header().Get(FaultInjectedHeader))
+	assert.Equal(t, 0, calls)
+
+	// Above it, the request goes through; only the first matching rule applies
+	random = 0.75
+	w = send(http.MethodPost, "/api/availabilities")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, calls)
+	assert.Zero(t, slept)
+
+	// Latency is spread between the minimum and the maximum
+	random = 0.5
+	w = send(http.MethodGet, "/api/meetings")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.Equal(t, 200*time.Millisecond, slept)
+
+	// Admin routes and unmatched routes are left alone
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/api/admin/faults").Code)
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/healthz").Code)
+
+	assert.NoError(t, injector.SetRules(nil))
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/api/meetings").Code)
+}
+
+func TestFaultInjectorValidation(t *testing.T) {
+	injector := NewFaultInjector(true)
+	invalid := []FaultRule{
+		{Route: ""},
+		{Route: "/api/", MinLatencyMS: 200, MaxLatencyMS: 100},
+		{Route: "/api/", MaxLatencyMS: 120000},
+		{Route: "/api/", ErrorRate: 1.5},
+		{Route: "/api/", ErrorStatus: http.StatusTeapot},
+	}
+	for _, rule := range invalid {
+		err := injector.SetRules([]FaultRule{rule})
+		assert.True(t, errors.Is(err, errors.ErrValidation), "rule %+v", rule)
+	}
+	assert.Empty(t, injector.Rules())
+}
+
+func TestFaultInjectorDisabled(t *testing.T) {
+	injector := NewFaultInjector(false)
+	err := injector.SetRules([]FaultRule{{Route: "/", ErrorRate: 1}})
+	assert.True(t, errors.Is(err, errors.ErrNotFound))
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	w := httptest.NewRecorder()
+	injector.Wrap(next).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/meetings", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
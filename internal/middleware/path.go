@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// CanonicalPath returns the canonical form of an escaped URL path: repeated slashes are
+// collapsed, "." and ".." segments resolved and a trailing slash removed, so that
+// /api/meetings//42/ and /api/meetings/42 name the same resource
+func CanonicalPath(escaped string) string {
+	if escaped == "" || escaped == "/" {
+		return "/"
+	}
+
+	segments := make([]string, 0, strings.Count(escaped, "/"))
+	for _, segment := range strings.Split(escaped, "/") {
+		switch segment {
+		case "", ".":
+		case "..":
+			if len(segments) > 0 {
+				segments = segments[:len(segments)-1]
+			}
+		default:
+			segments = append(segments, segment)
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// NormalizePath serves requests for non-canonical paths as if they were made to the
+// canonical path. GET and HEAD requests are redirected there permanently instead, so
+// clients and caches learn the canonical URL; other methods are rewritten in place,
+// since clients may not repeat their body after a redirect. It must wrap the ServeMux,
+// whose own cleaning of paths redirects every method and keeps trailing slashes.
+func NormalizePath(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		escaped := r.URL.EscapedPath()
+		canonical := CanonicalPath(escaped)
+		if canonical == escaped {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			target := canonical
+			if r.URL.RawQuery != "" {
+				target += "?" + r.URL.RawQuery
+			}
+			http.Redirect(w, r, target, http.StatusMovedPermanently)
+			return
+		}
+
+		path, err := url.PathUnescape(canonical)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		rewritten := r.Clone(r.Context())
+		rewritten.URL.Path = path
+		rewritten.URL.RawPath = ""
+		if rewritten.URL.EscapedPath() != canonical {
+			rewritten.URL.RawPath = canonical
+		}
+		rewritten.RequestURI = rewritten.URL.RequestURI()
+		next.ServeHTTP(w, rewritten)
+	})
+}
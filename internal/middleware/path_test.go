@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalPath(t *testing.T) {
+	tests := map[string]string{
+		"":                            "/",
+		"/":                           "/",
+		"//":                          "/",
+		"/api/meetings":               "/api/meetings",
+		"/api/meetings/":              "/api/meetings",
+		"/api//meetings///42/":        "/api/meetings/42",
+		"/api/meetings/./42":          "/api/meetings/42",
+		"/api/users/../meetings/42":   "/api/meetings/42",
+		"/../api/meetings":            "/api/meetings",
+		"/api/meetings/42/tags/a%2Fb": "/api/meetings/42/tags/a%2Fb",
+	}
+	for path, want := range tests {
+		assert.Equal(t, want, CanonicalPath(path), path)
+	}
+}
+
+func TestNormalizePath(t *testing.T) {
+	var served *http.Request
+	handler := NormalizePath(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served = r
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(method, target string) *httptest.ResponseRecorder {
+		served = nil
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+		return w
+	}
+
+	// Canonical paths are served as they are
+	w := serve(http.MethodGet, "/api/meetings/42?limit=5")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "/api/meetings/42", served.URL.Path)
+
+	// GET requests are redirected to the canonical path, keeping the query
+	w = serve(http.MethodGet, "/api//meetings/42/?limit=5")
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/api/meetings/42?limit=5", w.Header().Get("Location"))
+	assert.Nil(t, served)
+
+	// Other methods are served at the canonical path without a redirect
+	w = serve(http.MethodPut, "/api/meetings/42/")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "/api/meetings/42", served.URL.Path)
+	assert.Equal(t, "/api/meetings/42", served.RequestURI)
+
+	// Escaped slashes stay escaped
+	w = serve(http.MethodDelete, "/api/meetings/42/tags/a%2Fb/")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "/api/meetings/42/tags/a/b", served.URL.Path)
+	assert.Equal(t, "/api/meetings/42/tags/a%2Fb", served.URL.EscapedPath())
+}
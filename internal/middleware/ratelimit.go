@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"meetsync/pkg/errors"
+)
+
+// rateLimitWindow is the period over which RateLimiter counts requests
+const rateLimitWindow = time.Minute
+
+// RateLimiter caps how many requests each client can make per minute, telling clients
+// over the limit when to retry. Clients are told apart by their remote address, so
+// behind a reverse proxy every client shares the proxy's limit. Requests are counted in
+// fixed one-minute windows, which keeps the memory used bounded by the clients of the
+// current minute.
+type RateLimiter struct {
+	perMinute int
+	now       func() time.Time
+
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[string]int
+}
+
+// NewRateLimiter creates a RateLimiter allowing perMinute requests per client; zero
+// disables the limit
+func NewRateLimiter(perMinute int) *RateLimiter {
+	return &RateLimiter{
+		perMinute: perMinute,
+		now:       time.Now,
+		counts:    make(map[string]int),
+	}
+}
+
+// Allow counts a request from client and reports whether it is within the limit, or
+// else how long until the client may retry
+func (l *RateLimiter) Allow(client string) (bool, time.Duration) {
+	if l == nil || l.perMinute <= 0 {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.windowStart) >= rateLimitWindow {
+		l.windowStart = now
+		clear(l.counts)
+	}
+	if l.counts[client] >= l.perMinute {
+		return false, l.windowStart.Add(rateLimitWindow).Sub(now)
+	}
+	l.counts[client]++
+	return true, 0
+}
+
+// Limit wraps a handler so that clients over the limit get 429 Too Many Requests
+func (l *RateLimiter) Limit(handler ErrorHandler) ErrorHandler {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		if ok, retryAfter := l.Allow(remoteHost(r)); !ok {
+			return errors.NewRateLimitedError("Too many requests", retryAfter)
+		}
+		return handler(w, r)
+	}
+}
+
+// remoteHost returns the address of the client that sent r, without its port
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
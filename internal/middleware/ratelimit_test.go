@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"meetsync/pkg/errors"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Now()
+	limiter := NewRateLimiter(2)
+	limiter.now = func() time.Time { return now }
+	handler := limiter.Limit(func(w http.ResponseWriter, r *http.Request) error {
+		w.WriteHeader(http.StatusOK)
+		return nil
+	})
+
+	send := func(remoteAddr string) error {
+		req := httptest.NewRequest(http.MethodGet, "/status", nil)
+		req.RemoteAddr = remoteAddr
+		return handler(httptest.NewRecorder(), req)
+	}
+
+	assert.NoError(t, send("192.0.2.1:1000"))
+	assert.NoError(t, send("192.0.2.1:1001"))
+
+	// The third request of the minute is rejected, whatever the client's port
+	err := send("192.0.2.1:1002")
+	assert.True(t, errors.Is(err, errors.ErrRateLimited))
+	appErr, _ := errors.AsAppError(err)
+	assert.Equal(t, http.StatusTooManyRequests, appErr.HTTPStatusCode())
+	assert.Equal(t, time.Minute, appErr.RetryAfter)
+
+	// Other clients have their own limit
+	assert.NoError(t, send("192.0.2.2:1000"))
+
+	// The limit resets with the next window
+	now = now.Add(time.Minute)
+	assert.NoError(t, send("192.0.2.1:1003"))
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+	limiter := NewRateLimiter(0)
+	for i := 0; i < 100; i++ {
+		ok, _ := limiter.Allow("192.0.2.1")
+		assert.True(t, ok)
+	}
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"meetsync/pkg/errors"
+)
+
+const (
+	// RequestTimeoutHeader carries how long the client is willing to wait for the response
+	RequestTimeoutHeader = "X-Request-Timeout"
+	// standardRequestTimeoutHeader is the standard equivalent of RequestTimeoutHeader,
+	// honored when RequestTimeoutHeader is absent
+	standardRequestTimeoutHeader = "Request-Timeout"
+)
+
+// RequestTimeout returns a middleware giving requests the deadline their client asked
+// for with the X-Request-Timeout or Request-Timeout header, capped at max, so services
+// give up on work whose result the client will no longer wait for. The timeout is a
+// duration such as 2.5s or a number of seconds. Requests without the header keep no
+// deadline, and a max of zero ignores the headers.
+func RequestTimeout(max time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			value := r.Header.Get(RequestTimeoutHeader)
+			if value == "" {
+				value = r.Header.Get(standardRequestTimeoutHeader)
+			}
+			if value == "" || max <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			timeout, err := parseRequestTimeout(value)
+			if err != nil {
+				errors.WriteErrorWithRequestID(w, err, RequestID(r.Context()))
+				return
+			}
+			if timeout > max {
+				timeout = max
+			}
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// parseRequestTimeout parses a request timeout given as a duration or a number of seconds
+func parseRequestTimeout(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		seconds, parseErr := strconv.ParseFloat(value, 64)
+		if parseErr != nil {
+			return 0, errors.NewValidationError("Invalid request timeout", "use a duration such as 2.5s or a number of seconds")
+		}
+		timeout = time.Duration(seconds * float64(time.Second))
+	}
+	if timeout <= 0 {
+		return 0, errors.NewValidationError("Invalid request timeout", "the request timeout must be positive")
+	}
+	return timeout, nil
+}
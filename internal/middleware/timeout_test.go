@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestTimeout(t *testing.T) {
+	var remaining time.Duration
+	var hasDeadline bool
+	handler := RequestTimeout(10 * time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var deadline time.Time
+		deadline, hasDeadline = r.Context().Deadline()
+		remaining = time.Until(deadline)
+	}))
+	send := func(header, value string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/recommendations?meetingId=42", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, send("", ""))
+	assert.False(t, hasDeadline, "requests without the header keep no deadline")
+
+	assert.Equal(t, http.StatusOK, send(RequestTimeoutHeader, "2.5s"))
+	assert.True(t, hasDeadline)
+	assert.InDelta(t, 2500*time.Millisecond, remaining, float64(time.Second))
+
+	assert.Equal(t, http.StatusOK, send("Request-Timeout", "3"))
+	assert.InDelta(t, 3*time.Second, remaining, float64(time.Second))
+
+	// Timeouts are capped at the server maximum
+	assert.Equal(t, http.StatusOK, send(RequestTimeoutHeader, "1h"))
+	assert.InDelta(t, 10*time.Second, remaining, float64(time.Second))
+
+	assert.Equal(t, http.StatusBadRequest, send(RequestTimeoutHeader, "soon"))
+	assert.Equal(t, http.StatusBadRequest, send("Request-Timeout", "-1"))
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import "net/http"
+
+// VersionHeader is the response header carrying the version of the service, so client
+// bug reports can tell which build answered
+const VersionHeader = "X-MeetSync-Version"
+
+// Version adds the version header to every response
+func Version(version string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(VersionHeader, version)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
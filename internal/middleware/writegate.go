@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+
+	"meetsync/pkg/errors"
+)
+
+// WriteGate keeps operations that need a consistent view of every store, such as backups
+// and restores, from overlapping with requests that change state. A read-only gate
+// rejects those requests instead, for instances serving reads from a replica.
+type WriteGate struct {
+	mu       sync.RWMutex
+	readOnly bool
+	reads    map[string]bool // "METHOD /path" of routes that only read despite an unsafe method
+}
+
+// NewWriteGate creates a new WriteGate
+func NewWriteGate() *WriteGate {
+	return &WriteGate{}
+}
+
+// SetReadOnly makes the gate reject requests that change state with 503 Service
+// Unavailable and skip background jobs. reads lists the routes, such as
+// "POST /api/scheduling/query", that only read although their method is unsafe; they are
+// still served. Call it before serving requests.
+func (g *WriteGate) SetReadOnly(reads ...string) {
+	g.readOnly = true
+	g.reads = make(map[string]bool, len(reads))
+	for _, route := range reads {
+		g.reads[route] = true
+	}
+}
+
+// ReadOnly reports whether the gate rejects requests that change state
+func (g *WriteGate) ReadOnly() bool {
+	return g.readOnly
+}
+
+// Writes wraps a handler so requests with unsafe methods run concurrently with each
+// other but never during an exclusive operation. Safe methods pass straight through so
+// long-polling reads cannot hold up a backup.
+func (g *WriteGate) Writes(next http.Handler) http.Handler {
+	rejected := WithErrorHandling(func(w http.ResponseWriter, r *http.Request) error {
+		return errReadOnly()
+	})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !safeMethod(r.Method) {
+			if g.readOnly && !g.reads[r.Method+" "+r.URL.Path] {
+				rejected(w, r)
+				return
+			}
+			g.mu.RLock()
+			defer g.mu.RUnlock()
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Write wraps a handler that changes state although it is served for a safe method, such
+// as a one-click link followed from an email, so it is gated like unsafe methods
+func (g *WriteGate) Write(handler ErrorHandler) ErrorHandler {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		if g.readOnly {
+			return errReadOnly()
+		}
+		g.mu.RLock()
+		defer g.mu.RUnlock()
+		return handler(w, r)
+	}
+}
+
+// Run runs a background job that changes state, such as a scheduled sync, gated like
+// write requests. Read-only gates skip jobs.
+func (g *WriteGate) Run(job func()) {
+	if g.readOnly {
+		return
+	}
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	job()
+}
+
+// Exclusive wraps a handler so it runs once no write request is in flight, holding new
+// ones until it returns. Exclusive handlers must not be served through Writes. Read-only
+// gates reject those served for unsafe methods, such as restores.
+func (g *WriteGate) Exclusive(handler ErrorHandler) ErrorHandler {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		if g.readOnly && !safeMethod(r.Method) {
+			return errReadOnly()
+		}
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		return handler(w, r)
+	}
+}
+
+// safeMethod reports whether requests with method only read state
+func safeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// errReadOnly is returned for requests that would change state on a read-only instance
+func errReadOnly() error {
+	return errors.NewUnavailableError("This instance is read-only; send changes to the primary instance", 0, nil)
+}
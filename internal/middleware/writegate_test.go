@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"meetsync/pkg/errors"
+)
+
+func TestWriteGateReadOnly(t *testing.T) {
+	gate := NewWriteGate()
+	gate.SetReadOnly("POST /api/scheduling/query")
+	assert.True(t, gate.ReadOnly())
+
+	handler := gate.Writes(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(method, path string) int {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w.Code
+	}
+
+	// Reads are served, writes are rejected
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/api/meetings"))
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/api/scheduling/query"))
+	assert.Equal(t, http.StatusServiceUnavailable, serve(http.MethodPost, "/api/meetings"))
+	assert.Equal(t, http.StatusServiceUnavailable, serve(http.MethodDelete, "/api/meetings/42"))
+
+	ok := func(w http.ResponseWriter, r *http.Request) error { return nil }
+	err := gate.Write(ok)(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/rsvp/token", nil))
+	assert.True(t, errors.Is(err, errors.ErrUnavailable))
+	assert.NoError(t, gate.Exclusive(ok)(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/admin/backup", nil)))
+	err = gate.Exclusive(ok)(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/admin/restore", nil))
+	assert.True(t, errors.Is(err, errors.ErrUnavailable))
+
+	ran := false
+	gate.Run(func() { ran = true })
+	assert.False(t, ran, "background jobs are skipped")
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+)
+
+// Scope grants a personal access token permission to a group of endpoints
+type Scope string
+
+const (
+	// ScopeReadMeetings allows reading meetings, recommendations and availabilities
+	ScopeReadMeetings Scope = "read:meetings"
+	// ScopeWriteMeetings allows creating, updating, publishing and deleting meetings
+	ScopeWriteMeetings Scope = "write:meetings"
+	// ScopeWriteAvailability allows submitting, updating and deleting availability
+	ScopeWriteAvailability Scope = "write:availability"
+)
+
+// ValidScopes lists every scope a personal access token can be granted
+var ValidScopes = []Scope{ScopeReadMeetings, ScopeWriteMeetings, ScopeWriteAvailability}
+
+// PersonalAccessToken is a long-lived credential a user mints for scripts and calendar tools.
+// Only a hash of the secret is stored; the secret is shown once when the token is created.
+type PersonalAccessToken struct {
+	ID           string       `json:"id"`
+	UserID       string       `json:"userId"`
+	Name         string       `json:"name"`
+	Scopes       []Scope      `json:"scopes"`
+	SecretHash   string       `json:"-"`
+	LastUsedAt   *time.Time   `json:"lastUsedAt,omitempty"`
+	LastUsedFrom *TokenClient `json:"lastUsedFrom,omitempty"`
+	CreatedAt    time.Time    `json:"createdAt"`
+}
+
+// TokenClient identifies the client that sent a request authenticated with a personal
+// access token
+type TokenClient struct {
+	IP        string `json:"ip,omitempty"`
+	UserAgent string `json:"userAgent,omitempty"`
+}
+
+// Session is a client a user is signed in on, that is a personal access token that
+// authenticated requests, and where it was last used from. Revoking the token ends it.
+type Session struct {
+	TokenID    string      `json:"tokenId"`
+	TokenName  string      `json:"tokenName"`
+	Scopes     []Scope     `json:"scopes"`
+	LastUsedAt time.Time   `json:"lastUsedAt"`
+	Client     TokenClient `json:"client"`
+	CreatedAt  time.Time   `json:"createdAt"`
+}
+
+// HasScope reports whether the token was granted the scope
+func (t PersonalAccessToken) HasScope(scope Scope) bool {
+	for _, granted := range t.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+)
+
+// AuditAction identifies an administrative action recorded in the audit log
+type AuditAction string
+
+const (
+	// AuditActionUsersMerged records that a duplicate user was merged into another user
+	AuditActionUsersMerged AuditAction = "users.merged"
+	// AuditActionUsersImported records that users were imported in bulk
+	AuditActionUsersImported AuditAction = "users.imported"
+	// AuditActionDirectorySynced records that users were synchronized from the directory
+	AuditActionDirectorySynced AuditAction = "directory.synced"
+	// AuditActionBackupRestored records that every store was replaced with the contents of a backup
+	AuditActionBackupRestored AuditAction = "backup.restored"
+	// AuditActionBootstrapped records that a bootstrap created or replaced the administrator or their tokens
+	AuditActionBootstrapped AuditAction = "environment.bootstrapped"
+)
+
+// AuditEntry represents a single administrative action recorded in the audit log
+type AuditEntry struct {
+	ID         string      `json:"id"`
+	Action     AuditAction `json:"action"`
+	SubjectID  string      `json:"subjectId"`
+	Details    string      `json:"details,omitempty"`
+	OccurredAt time.Time   `json:"occurredAt"`
+}
+
+// UserReassignment summarizes the records moved from one user to another during a merge
+type UserReassignment struct {
+	SourceUserID      string `json:"sourceUserId"`
+	TargetUserID      string `json:"targetUserId"`
+	MeetingsOrganized int    `json:"meetingsOrganized"`
+	Participations    int    `json:"participations"`
+	Availabilities    int    `json:"availabilities"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+)
+
+// BackupVersion is the format version of backups written by this build
+const BackupVersion = 1
+
+// Backup is a point-in-time copy of every store, used to restore a deployment
+type Backup struct {
+	Version   int        `json:"version"`
+	CreatedAt time.Time  `json:"createdAt"`
+	Checksum  string     `json:"checksum"` // hex SHA-256 of the JSON-encoded data
+	Data      BackupData `json:"data"`
+}
+
+// BackupData holds the contents of every store
+type BackupData struct {
+	Users    UserStoreSnapshot    `json:"users"`
+	Meetings MeetingStoreSnapshot `json:"meetings"`
+	AuditLog []AuditEntry         `json:"auditLog"`
+}
+
+// UserStoreSnapshot holds the contents of the user store
+type UserStoreSnapshot struct {
+	Users        []User              `json:"users"`
+	Tokens       []UserToken         `json:"tokens"`
+	AccessTokens []AccessTokenBackup `json:"accessTokens"`
+}
+
+// AccessTokenBackup is a personal access token including the secret hash, which is
+// otherwise never serialized
+type AccessTokenBackup struct {
+	PersonalAccessToken
+	SecretHash string `json:"secretHash"`
+}
+
+// MeetingStoreSnapshot holds the contents of the meeting store
+type MeetingStoreSnapshot struct {
+	Meetings        []Meeting           `json:"meetings"`
+	Availabilities  []Availability      `json:"availabilities"`
+	Timelines       []TimelineEvent     `json:"timelines"`
+	Recommendations []RecommendationSet `json:"recommendations"`
+}
+
+// BackupSummary counts the records of a backup
+type BackupSummary struct {
+	Users          int `json:"users"`
+	Meetings       int `json:"meetings"`
+	Availabilities int `json:"availabilities"`
+	AuditEntries   int `json:"auditEntries"`
+}
@@ -0,0 +1,63 @@
+package models
+
+// Bootstrap declares the administrator of a new environment and the personal access
+// tokens its automation authenticates with. Applying the same Bootstrap again leaves the
+// environment unchanged.
+type Bootstrap struct {
+	Admin  BootstrapAdmin   `json:"admin"`
+	Tokens []BootstrapToken `json:"tokens,omitempty"`
+}
+
+// BootstrapAdmin is the administrator declared by a Bootstrap, identified by email
+type BootstrapAdmin struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// BootstrapToken is a personal access token of the administrator, identified by name.
+// With a secret, the token authenticates with it, so provisioning tools can generate
+// the secret themselves; otherwise one is generated when the token is created.
+type BootstrapToken struct {
+	Name   string  `json:"name"`
+	Scopes []Scope `json:"scopes"`
+	Secret string  `json:"secret,omitempty"`
+}
+
+// BootstrapStatus tells what applying a Bootstrap did to a declared resource
+type BootstrapStatus string
+
+const (
+	// BootstrapCreated is a resource that did not exist yet
+	BootstrapCreated BootstrapStatus = "created"
+	// BootstrapReplaced is a token recreated because its scopes or secret changed
+	BootstrapReplaced BootstrapStatus = "replaced"
+	// BootstrapUnchanged is a resource that already matched its declaration
+	BootstrapUnchanged BootstrapStatus = "unchanged"
+)
+
+// BootstrapResult is the outcome of applying a Bootstrap
+type BootstrapResult struct {
+	Admin       User                   `json:"admin"`
+	AdminStatus BootstrapStatus        `json:"adminStatus"`
+	Tokens      []BootstrapTokenResult `json:"tokens"`
+}
+
+// BootstrapTokenResult is a token declared by a Bootstrap once applied
+type BootstrapTokenResult struct {
+	Token  PersonalAccessToken `json:"token"`
+	Status BootstrapStatus     `json:"status"`
+	Secret string              `json:"secret,omitempty"` // only when generated, and only once
+}
+
+// Changed reports whether applying the Bootstrap created or replaced anything
+func (r BootstrapResult) Changed() bool {
+	if r.AdminStatus != BootstrapUnchanged {
+		return true
+	}
+	for _, token := range r.Tokens {
+		if token.Status != BootstrapUnchanged {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// CalendarEvent is a meeting as exported to calendar applications: on its confirmed
+// slot, or on the best recommended one while it is not confirmed
+type CalendarEvent struct {
+	MeetingID   string        `json:"meetingId"`
+	Title       string        `json:"title"`
+	Description string        `json:"description,omitempty"`
+	Slot        TimeSlot      `json:"slot"`
+	Status      MeetingStatus `json:"status"`
+	Organizer   User          `json:"organizer"`
+	Attendees   []User        `json:"attendees"`
+	UpdatedAt   time.Time     `json:"updatedAt"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// DigestFrequency is how often a user receives a digest email instead of an email per
+// invitation or reconfirmation request
+type DigestFrequency string
+
+const (
+	// DigestOff sends every notification as it happens
+	DigestOff DigestFrequency = ""
+	// DigestDaily aggregates notifications into one email a day
+	DigestDaily DigestFrequency = "daily"
+	// DigestWeekly aggregates notifications into one email a week
+	DigestWeekly DigestFrequency = "weekly"
+)
+
+// Valid reports whether f is a known frequency
+func (f DigestFrequency) Valid() bool {
+	switch f {
+	case DigestOff, DigestDaily, DigestWeekly:
+		return true
+	}
+	return false
+}
+
+// Period returns the time between two digests, or zero when digests are off
+func (f DigestFrequency) Period() time.Duration {
+	switch f {
+	case DigestDaily:
+		return 24 * time.Hour
+	case DigestWeekly:
+		return 7 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// DigestResult summarizes a run of the digest job
+type DigestResult struct {
+	Sent   int `json:"sent"`
+	Empty  int `json:"empty"` // users due a digest with nothing to report, who were not emailed
+	Failed int `json:"failed"`
+}
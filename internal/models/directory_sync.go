@@ -0,0 +1,83 @@
+package models
+
+// DirectoryConflictPolicy decides what a directory sync does with directory users whose
+// email belongs to a user not yet linked to the directory
+type DirectoryConflictPolicy string
+
+const (
+	// DirectoryConflictLink links the existing user to the directory entry, which then
+	// manages their name, email and activation
+	DirectoryConflictLink DirectoryConflictPolicy = "link"
+	// DirectoryConflictSkip leaves the existing user unchanged and reports a conflict
+	DirectoryConflictSkip DirectoryConflictPolicy = "skip"
+)
+
+// Valid reports whether p is a known policy
+func (p DirectoryConflictPolicy) Valid() bool {
+	return p == DirectoryConflictLink || p == DirectoryConflictSkip
+}
+
+// DirectoryMissingPolicy decides what a directory sync does with linked users no longer
+// found in the directory
+type DirectoryMissingPolicy string
+
+const (
+	// DirectoryMissingKeep leaves them unchanged
+	DirectoryMissingKeep DirectoryMissingPolicy = "keep"
+	// DirectoryMissingDeactivate deactivates them
+	DirectoryMissingDeactivate DirectoryMissingPolicy = "deactivate"
+)
+
+// Valid reports whether p is a known policy
+func (p DirectoryMissingPolicy) Valid() bool {
+	return p == DirectoryMissingKeep || p == DirectoryMissingDeactivate
+}
+
+// DirectorySyncOptions configures how directory users are reconciled with users
+type DirectorySyncOptions struct {
+	OnConflict DirectoryConflictPolicy
+	OnMissing  DirectoryMissingPolicy
+}
+
+// DirectorySyncAction is a change a directory sync makes, or would make, to a user
+type DirectorySyncAction string
+
+const (
+	// DirectorySyncCreate means a user is created for a new directory user
+	DirectorySyncCreate DirectorySyncAction = "create"
+	// DirectorySyncUpdate means a linked user is updated to match the directory
+	DirectorySyncUpdate DirectorySyncAction = "update"
+	// DirectorySyncLink means an existing user with the same email is linked to the directory
+	DirectorySyncLink DirectorySyncAction = "link"
+	// DirectorySyncDeactivate means a linked user missing from the directory is deactivated
+	DirectorySyncDeactivate DirectorySyncAction = "deactivate"
+	// DirectorySyncConflict means a directory user is skipped, as explained by the reason
+	DirectorySyncConflict DirectorySyncAction = "conflict"
+)
+
+// DirectorySyncChange is a change to a single user. Error is set when applying the
+// change failed.
+type DirectorySyncChange struct {
+	Action     DirectorySyncAction `json:"action"`
+	UserID     string              `json:"userId,omitempty"`
+	ExternalID string              `json:"externalId,omitempty"`
+	DN         string              `json:"dn,omitempty"`
+	Email      string              `json:"email"`
+	Name       string              `json:"name,omitempty"`
+	Reason     string              `json:"reason,omitempty"`
+	Error      string              `json:"error,omitempty"`
+}
+
+// DirectorySyncResult summarizes a directory sync. Dry runs report the changes a sync
+// would make without applying them.
+type DirectorySyncResult struct {
+	DryRun      bool                  `json:"dryRun"`
+	Created     int                   `json:"created"`
+	Updated     int                   `json:"updated"`
+	Linked      int                   `json:"linked"`
+	Deactivated int                   `json:"deactivated"`
+	Unchanged   int                   `json:"unchanged"`
+	Conflicts   int                   `json:"conflicts"`
+	Failed      int                   `json:"failed"`
+	Changes     []DirectorySyncChange `json:"changes"`
+}
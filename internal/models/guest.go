@@ -0,0 +1,23 @@
+package models
+
+// GuestInvitation is a guest participant added to a meeting, with the token and link that
+// give them access to it
+type GuestInvitation struct {
+	MeetingID string `json:"meetingId"`
+	Guest     User   `json:"guest"`
+	Token     string `json:"token"`
+	URL       string `json:"url"`
+}
+
+// GuestMeeting is what a guest participant sees of the meeting they were invited to: its
+// proposed times and their own availability, but not the organizer, the other
+// participants or anything else about the organization
+type GuestMeeting struct {
+	MeetingID         string        `json:"meetingId"`
+	Title             string        `json:"title"`
+	EstimatedDuration int           `json:"estimatedDuration"` // in minutes
+	ProposedSlots     []TimeSlot    `json:"proposedSlots"`
+	TimeZone          string        `json:"timeZone,omitempty"`
+	Status            MeetingStatus `json:"status"`
+	Availability      *Availability `json:"availability,omitempty"` // the guest's own, once submitted
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// JobStatus represents the lifecycle state of a background job
+type JobStatus string
+
+const (
+	// JobStatusQueued is a job waiting for a worker
+	JobStatusQueued JobStatus = "queued"
+	// JobStatusRunning is a job being processed
+	JobStatusRunning JobStatus = "running"
+	// JobStatusSucceeded is a job that completed; its result may still report failed items
+	JobStatusSucceeded JobStatus = "succeeded"
+	// JobStatusFailed is a job that could not complete
+	JobStatusFailed JobStatus = "failed"
+)
+
+// Job is work accepted by a request and processed in the background, such as a large batch
+type Job struct {
+	ID         string     `json:"id"`
+	Kind       string     `json:"kind"`
+	Status     JobStatus  `json:"status"`
+	Result     any        `json:"result,omitempty"` // set once the job succeeded
+	Error      string     `json:"error,omitempty"`  // set once the job failed
+	CreatedAt  time.Time  `json:"createdAt"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// Finished reports whether the job succeeded or failed
+func (j Job) Finished() bool {
+	return j.Status == JobStatusSucceeded || j.Status == JobStatusFailed
+}
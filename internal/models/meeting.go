@@ -11,17 +11,197 @@ type TimeSlot struct {
 	EndTime   time.Time `json:"endTime"`
 }
 
+// Fingerprint identifies a slot by its times, so the same slot is recognized when a
+// meeting is rescheduled whatever its ID or the time zone it was given in
+func (t TimeSlot) Fingerprint() string {
+	return t.StartTime.UTC().Format(time.RFC3339Nano) + "/" + t.EndTime.UTC().Format(time.RFC3339Nano)
+}
+
+// MeetingStatus represents the lifecycle state of a meeting
+type MeetingStatus string
+
+const (
+	// MeetingStatusDraft is a meeting that is still being prepared and cannot collect availability
+	MeetingStatusDraft MeetingStatus = "draft"
+	// MeetingStatusPending is a published meeting collecting participant availability
+	MeetingStatusPending MeetingStatus = "pending"
+	// MeetingStatusConfirmed is a meeting the organizer confirmed on one slot; it no longer collects availability
+	MeetingStatusConfirmed MeetingStatus = "confirmed"
+	// MeetingStatusCancelled is a meeting that will not take place
+	MeetingStatusCancelled MeetingStatus = "cancelled"
+)
+
+// Valid reports whether s is a known status
+func (s MeetingStatus) Valid() bool {
+	switch s {
+	case MeetingStatusDraft, MeetingStatusPending, MeetingStatusConfirmed, MeetingStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// MeetingPriority ranks meetings competing for the same time
+type MeetingPriority string
+
+const (
+	// PriorityLow is a meeting that yields its slots to any other meeting
+	PriorityLow MeetingPriority = "low"
+	// PriorityNormal is the default priority
+	PriorityNormal MeetingPriority = "normal"
+	// PriorityHigh is a meeting whose invitations are marked as important
+	PriorityHigh MeetingPriority = "high"
+	// PriorityUrgent is a meeting whose invitations are marked as urgent
+	PriorityUrgent MeetingPriority = "urgent"
+)
+
+// Rank orders priorities from low to urgent; unknown priorities rank as normal
+func (p MeetingPriority) Rank() int {
+	switch p {
+	case PriorityLow:
+		return 0
+	case PriorityHigh:
+		return 2
+	case PriorityUrgent:
+		return 3
+	default:
+		return 1
+	}
+}
+
+// Valid reports whether p is a known priority
+func (p MeetingPriority) Valid() bool {
+	switch p {
+	case PriorityLow, PriorityNormal, PriorityHigh, PriorityUrgent:
+		return true
+	}
+	return false
+}
+
 // Meeting represents a meeting with multiple time slots
 type Meeting struct {
-	ID                string     `json:"id"`
-	Title             string     `json:"title"`
-	OrganizerID       string     `json:"organizerId"`
-	Organizer         *User      `json:"organizer,omitempty"`
-	EstimatedDuration int        `json:"estimatedDuration"` // in minutes
-	ProposedSlots     []TimeSlot `json:"proposedSlots"`
-	Participants      []User     `json:"participants,omitempty"`
-	CreatedAt         time.Time  `json:"createdAt"`
-	UpdatedAt         time.Time  `json:"updatedAt"`
+	ID                string          `json:"id"`
+	Title             string          `json:"title"`
+	OrganizerID       string          `json:"organizerId"`
+	Organizer         *User           `json:"organizer,omitempty"`
+	EstimatedDuration int             `json:"estimatedDuration"`     // in minutes
+	MaxDuration       int             `json:"maxDuration,omitempty"` // in minutes; makes EstimatedDuration the minimum of a range
+	ProposedSlots     []TimeSlot      `json:"proposedSlots"`
+	Participants      []User          `json:"participants,omitempty"`
+	Status            MeetingStatus   `json:"status"`
+	ConfirmedSlot     *TimeSlot       `json:"confirmedSlot,omitempty"` // the slot the organizer confirmed the meeting on
+	Proposals         []Proposal      `json:"proposals,omitempty"`     // alternatives participants suggested once confirmed
+	Priority          MeetingPriority `json:"priority"`
+	TimeZone          string          `json:"timeZone,omitempty"`          // IANA zone of the organizer, e.g. Europe/Paris
+	OverrideFocusTime bool            `json:"overrideFocusTime,omitempty"` // lets slots during participants' focus time count as available
+	AvailabilityTTL   int             `json:"availabilityTtl,omitempty"`   // in hours; older responses are stale until reconfirmed
+	Tags              []string        `json:"tags,omitempty"`              // free-form labels such as 1on1 or hiring
+	ExternalID        string          `json:"externalId,omitempty"`        // identifier of the meeting in the calendar it is synced from
+	ExternalUpdatedAt *time.Time      `json:"externalUpdatedAt,omitempty"` // when the synced calendar last changed the meeting
+	Metadata          Metadata        `json:"metadata,omitempty"`
+	CreatedAt         time.Time       `json:"createdAt"`
+	UpdatedAt         time.Time       `json:"updatedAt"`
+}
+
+// HasParticipant reports whether the user with userID is a participant of the meeting
+func (m Meeting) HasParticipant(userID string) bool {
+	for _, participant := range m.Participants {
+		if participant.ID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// MeetingInput holds the caller-supplied fields used to create or update a meeting
+type MeetingInput struct {
+	Title                 string
+	OrganizerID           string
+	EstimatedDuration     int // in minutes
+	MaxDuration           int // in minutes, zero for a fixed duration
+	ProposedSlots         []TimeSlot
+	ParticipantIDs        []string
+	Draft                 bool
+	CoalesceAdjacentSlots bool     // merges proposed slots where one ends as the next starts
+	TimeZone              string   // IANA zone of the organizer
+	OverrideFocusTime     *bool    // nil leaves the flag unchanged on update
+	AvailabilityTTL       int      // in hours, zero for responses that never go stale
+	Tags                  []string // nil leaves the tags unchanged on update, an empty list clears them
+	Priority              MeetingPriority
+	ExternalID            string    // set on creation only
+	ExternalUpdatedAt     time.Time // when the synced calendar last changed the meeting; zero for other changes
+	Metadata              Metadata  // nil leaves the metadata unchanged on update, an empty map clears it
+}
+
+// MeetingFilter narrows down listed meetings; the zero value matches every meeting
+type MeetingFilter struct {
+	Tags          []string // meetings must carry every tag
+	ExternalID    string
+	OrganizerID   string
+	ParticipantID string
+	Status        MeetingStatus
+	From          time.Time // meetings must propose a slot ending after From; zero for no bound
+	To            time.Time // meetings must propose a slot starting before To; zero for no bound
+	Metadata      Metadata  // meetings must carry every key with the same value
+}
+
+// Matches reports whether a meeting passes the filter
+func (f MeetingFilter) Matches(meeting Meeting) bool {
+	if f.ExternalID != "" && meeting.ExternalID != f.ExternalID {
+		return false
+	}
+	if f.OrganizerID != "" && meeting.OrganizerID != f.OrganizerID {
+		return false
+	}
+	if f.Status != "" && meeting.Status != f.Status {
+		return false
+	}
+	if f.ParticipantID != "" && !meeting.HasParticipant(f.ParticipantID) {
+		return false
+	}
+	if !f.From.IsZero() || !f.To.IsZero() {
+		inRange := false
+		for _, slot := range meeting.ProposedSlots {
+			if (f.From.IsZero() || slot.EndTime.After(f.From)) && (f.To.IsZero() || slot.StartTime.Before(f.To)) {
+				inRange = true
+				break
+			}
+		}
+		if !inRange {
+			return false
+		}
+	}
+	if !meeting.Metadata.Matches(f.Metadata) {
+		return false
+	}
+	for _, tag := range f.Tags {
+		found := false
+		for _, meetingTag := range meeting.Tags {
+			if meetingTag == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// SearchResult is a meeting matching a search query
+type SearchResult struct {
+	Meeting       Meeting  `json:"meeting"`
+	Score         float64  `json:"score"`         // relevance, higher is better
+	MatchedFields []string `json:"matchedFields"` // title, tags or participants
+}
+
+// TagStats summarizes the meetings carrying a tag
+type TagStats struct {
+	Tag                   string  `json:"tag"`
+	Meetings              int     `json:"meetings"`
+	Participants          int     `json:"participants"`          // summed over the tagged meetings
+	AvailabilityResponses int     `json:"availabilityResponses"` // participants who submitted availability
+	ResponseRate          float64 `json:"responseRate"`          // responses per participant, zero without participants
 }
 
 // Participant represents a participant in a meeting
@@ -40,14 +220,163 @@ type Availability struct {
 	Participant    *User      `json:"participant,omitempty"`
 	MeetingID      string     `json:"meetingId"`
 	AvailableSlots []TimeSlot `json:"availableSlots"`
+	Tentative      bool       `json:"tentative"`            // the participant may still change their mind about the whole response
+	ConfirmedAt    time.Time  `json:"confirmedAt"`          // when the participant last submitted or reconfirmed the response
+	Stale          bool       `json:"stale,omitempty"`      // older than the meeting's availability TTL; set when read
+	ExternalID     string     `json:"externalId,omitempty"` // identifier of the response in an integrator's system
+	Metadata       Metadata   `json:"metadata,omitempty"`
 	CreatedAt      time.Time  `json:"createdAt"`
 	UpdatedAt      time.Time  `json:"updatedAt"`
 }
 
+// IsStale reports whether the response was last confirmed more than ttl hours before now
+func (a Availability) IsStale(ttl int, now time.Time) bool {
+	return ttl > 0 && now.Sub(a.ConfirmedAt) > time.Duration(ttl)*time.Hour
+}
+
 // RecommendedSlot represents a recommended time slot for a meeting
 type RecommendedSlot struct {
 	TimeSlot                TimeSlot `json:"timeSlot"`
 	AvailableCount          int      `json:"availableCount"`
+	ConfirmedCount          int      `json:"confirmedCount"` // available participants whose response is not tentative
+	TentativeCount          int      `json:"tentativeCount"` // available participants whose response is tentative
 	TotalParticipants       int      `json:"totalParticipants"`
 	UnavailableParticipants []User   `json:"unavailableParticipants,omitempty"`
+	// LongestFeasibleDuration is how long, in minutes, the meeting can run in this slot
+	// while everyone available for it stays available; only set for meetings with a MaxDuration
+	LongestFeasibleDuration int `json:"longestFeasibleDuration,omitempty"`
+	// FocusTimeConflicts lists the available participants whose focus time the slot falls in;
+	// they only count as available when the meeting overrides focus time
+	FocusTimeConflicts []User `json:"focusTimeConflicts,omitempty"`
+	// Conflicts lists meetings of equal or higher priority that available participants also
+	// offered this time to; conflicts with lower-priority meetings are ignored
+	Conflicts []MeetingConflict `json:"conflicts,omitempty"`
+	// PolicyViolations lists the scheduling policies a meeting in this slot would break
+	PolicyViolations []PolicyViolation `json:"policyViolations,omitempty"`
+}
+
+// PolicyViolation reports a scheduling policy broken by a slot
+type PolicyViolation struct {
+	Policy  string   `json:"policy"`
+	Message string   `json:"message"`
+	UserIDs []string `json:"userIds,omitempty"` // the people affected, for per-person policies
+}
+
+// MeetingConflict reports another open meeting competing with a slot for a participant's time
+type MeetingConflict struct {
+	MeetingID string          `json:"meetingId"`
+	Priority  MeetingPriority `json:"priority"`
+	UserID    string          `json:"userId"`
+}
+
+// SplitRecommendation pairs two sessions that together reach every participant of a
+// meeting when no single slot does
+type SplitRecommendation struct {
+	Sessions          []TimeSlot `json:"sessions"`
+	CoveredCount      int        `json:"coveredCount"`  // participants who can attend at least one session
+	AttendingBoth     int        `json:"attendingBoth"` // participants who can attend both sessions
+	TotalParticipants int        `json:"totalParticipants"`
+}
+
+// RecommendationSet holds the recommended slots of a meeting and when they were computed
+type RecommendationSet struct {
+	MeetingID  string            `json:"meetingId"`
+	Slots      []RecommendedSlot `json:"slots"`
+	ComputedAt time.Time         `json:"computedAt"`
+}
+
+// RecommendationView records the recommendations of a meeting being fetched, so the time
+// organizers take to finalize after seeing them can be measured
+type RecommendationView struct {
+	ViewerID          string    `json:"viewerId,omitempty"` // absent for requests without an access token
+	ByOrganizer       bool      `json:"byOrganizer"`
+	TopSlot           *TimeSlot `json:"topSlot,omitempty"` // absent when no slot was recommended
+	AvailableCount    int       `json:"availableCount"`    // how many can attend the top slot
+	TotalParticipants int       `json:"totalParticipants"`
+	ComputedAt        time.Time `json:"computedAt"` // when the viewed recommendations were computed
+}
+
+// AvailabilityMark is how a participant answered for a recommended slot
+type AvailabilityMark string
+
+const (
+	AvailabilityMarkAvailable   AvailabilityMark = "available"
+	AvailabilityMarkTentative   AvailabilityMark = "tentative"
+	AvailabilityMarkUnavailable AvailabilityMark = "unavailable"
+	AvailabilityMarkNoResponse  AvailabilityMark = "no_response" // the participant has not submitted availability
+)
+
+// AvailabilityGrid lays out the recommended slots of a meeting against its organizer
+// and participants, for sharing outside the service
+type AvailabilityGrid struct {
+	MeetingID    string            `json:"meetingId"`
+	Title        string            `json:"title"`
+	TimeZone     string            `json:"timeZone,omitempty"`
+	Participants []User            `json:"participants"` // the organizer first
+	Rows         []AvailabilityRow `json:"rows"`         // in recommendation order
+	ComputedAt   time.Time         `json:"computedAt"`
+}
+
+// AvailabilityRow holds the marks of every participant of an AvailabilityGrid for a slot,
+// in the order of the grid's participants
+type AvailabilityRow struct {
+	Slot  RecommendedSlot    `json:"slot"`
+	Marks []AvailabilityMark `json:"marks"`
+}
+
+// MeetingSummary gathers what the read-only summary page of a meeting shows
+type MeetingSummary struct {
+	Meeting   Meeting
+	Grid      AvailabilityGrid
+	Responses []ParticipantResponse // in the order of the grid's participants
+}
+
+// ParticipantResponse tells whether and when a participant answered a meeting
+type ParticipantResponse struct {
+	Participant User
+	Responded   bool
+	Tentative   bool
+	Stale       bool      // older than the meeting's availability TTL
+	ConfirmedAt time.Time // zero until the participant responds
+}
+
+// RecommendationScenario describes hypothetical changes to a meeting whose effect on the
+// recommendations is simulated without changing the meeting
+type RecommendationScenario struct {
+	RemoveParticipantIDs []string `json:"removeParticipantIds,omitempty"`
+	ExtendWindow         int      `json:"extendWindow,omitempty"` // minutes added to the end of the latest proposed slot
+	Duration             int      `json:"duration,omitempty"`     // in minutes, zero keeps the meeting's duration
+}
+
+// RecommendationChangeKind tells how a simulated change affects a recommended slot
+type RecommendationChangeKind string
+
+const (
+	// RecommendationAdded is a slot only recommended in the simulation
+	RecommendationAdded RecommendationChangeKind = "added"
+	// RecommendationRemoved is a slot no longer recommended in the simulation
+	RecommendationRemoved RecommendationChangeKind = "removed"
+	// RecommendationChanged is a slot whose availability differs in the simulation
+	RecommendationChanged RecommendationChangeKind = "changed"
+)
+
+// RecommendationChange describes the difference between the current and simulated
+// recommendation of a slot; counts are zero on the side where the slot is missing
+type RecommendationChange struct {
+	TimeSlot                   TimeSlot                 `json:"timeSlot"`
+	Kind                       RecommendationChangeKind `json:"kind"`
+	CurrentAvailableCount      int                      `json:"currentAvailableCount"`
+	SimulatedAvailableCount    int                      `json:"simulatedAvailableCount"`
+	CurrentTotalParticipants   int                      `json:"currentTotalParticipants"`
+	SimulatedTotalParticipants int                      `json:"simulatedTotalParticipants"`
+}
+
+// RecommendationSimulation holds the recommendations of a meeting under a scenario and
+// how they differ from the current ones
+type RecommendationSimulation struct {
+	MeetingID string                 `json:"meetingId"`
+	Scenario  RecommendationScenario `json:"scenario"`
+	Current   []RecommendedSlot      `json:"current"`
+	Simulated []RecommendedSlot      `json:"simulated"`
+	Changes   []RecommendationChange `json:"changes"`
 }
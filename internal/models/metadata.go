@@ -0,0 +1,37 @@
+package models
+
+// Metadata holds free-form key/value pairs integrators attach to meetings, users and
+// availabilities to correlate them with records of their own systems, such as a CRM
+type Metadata map[string]string
+
+// Matches reports whether m holds every key of filter with the same value
+func (m Metadata) Matches(filter Metadata) bool {
+	for key, value := range filter {
+		if actual, found := m[key]; !found || actual != value {
+			return false
+		}
+	}
+	return true
+}
+
+// UserFilter narrows down listed users; the zero value matches every user
+type UserFilter struct {
+	ExternalID string
+	Metadata   Metadata // users must carry every key with the same value
+}
+
+// Matches reports whether a user passes the filter
+func (f UserFilter) Matches(user User) bool {
+	return (f.ExternalID == "" || user.ExternalID == f.ExternalID) && user.Metadata.Matches(f.Metadata)
+}
+
+// AvailabilityFilter narrows down listed availabilities; the zero value matches every availability
+type AvailabilityFilter struct {
+	ExternalID string
+	Metadata   Metadata // availabilities must carry every key with the same value
+}
+
+// Matches reports whether an availability passes the filter
+func (f AvailabilityFilter) Matches(availability Availability) bool {
+	return (f.ExternalID == "" || availability.ExternalID == f.ExternalID) && availability.Metadata.Matches(f.Metadata)
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+)
+
+// ProposalStatus is the organizer's decision on an alternative proposal
+type ProposalStatus string
+
+const (
+	// ProposalPending is a proposal the organizer has not decided on yet
+	ProposalPending ProposalStatus = "pending"
+	// ProposalAccepted is a proposal the meeting was rescheduled to
+	ProposalAccepted ProposalStatus = "accepted"
+	// ProposalDeclined is a proposal the organizer turned down
+	ProposalDeclined ProposalStatus = "declined"
+)
+
+// Proposal is an alternative slot a participant suggested for a confirmed meeting they
+// cannot attend at the confirmed time
+type Proposal struct {
+	ID            string         `json:"id"`
+	ParticipantID string         `json:"participantId"`
+	Slot          TimeSlot       `json:"slot"`
+	Reason        string         `json:"reason,omitempty"`
+	Status        ProposalStatus `json:"status"`
+	CreatedAt     time.Time      `json:"createdAt"`
+	DecidedAt     *time.Time     `json:"decidedAt,omitempty"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// WorkingHours restricts scheduling to a daily time range, in "15:04" format
+type WorkingHours struct {
+	Start           string `json:"start"`
+	End             string `json:"end"`
+	ExcludeWeekends bool   `json:"excludeWeekends,omitempty"`
+}
+
+// SchedulingQuery describes a search for times that suit a group of users
+type SchedulingQuery struct {
+	ParticipantIDs []string
+	Duration       int // in minutes
+	Window         TimeSlot
+	TimeZone       string        // IANA zone the working hours and slot alignment refer to
+	WorkingHours   *WorkingHours // nil to consider the whole window
+}
+
+// FreeBusy lists when a user is busy within a window, for other scheduling systems
+type FreeBusy struct {
+	UserID string
+	From   time.Time
+	To     time.Time
+	Busy   []TimeSlot // sorted and non-overlapping, clipped to the window
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+)
+
+// TimelineEventType identifies the kind of activity recorded on a meeting timeline
+type TimelineEventType string
+
+const (
+	// TimelineMeetingCreated records the creation of a meeting
+	TimelineMeetingCreated TimelineEventType = "created"
+	// TimelineParticipantAdded records a participant being added to a meeting
+	TimelineParticipantAdded TimelineEventType = "participant_added"
+	// TimelineAvailabilitySubmitted records a participant submitting or changing availability
+	TimelineAvailabilitySubmitted TimelineEventType = "availability_submitted"
+	// TimelineRecommendationViewed records recommendations being fetched for a meeting
+	TimelineRecommendationViewed TimelineEventType = "recommendation_viewed"
+	// TimelineMeetingFinalized records a meeting being finalized on a slot
+	TimelineMeetingFinalized TimelineEventType = "finalized"
+	// TimelineMeetingRescheduled records a change to a meeting's time slots
+	TimelineMeetingRescheduled TimelineEventType = "rescheduled"
+	// TimelineMeetingCancelled records a meeting being cancelled
+	TimelineMeetingCancelled TimelineEventType = "cancelled"
+	// TimelineAlternativeProposed records a participant proposing another slot for a confirmed meeting
+	TimelineAlternativeProposed TimelineEventType = "alternative_proposed"
+	// TimelineAlternativeDeclined records the organizer declining a proposed alternative
+	TimelineAlternativeDeclined TimelineEventType = "alternative_declined"
+)
+
+// TimelineEvent represents an entry in a meeting's activity timeline
+type TimelineEvent struct {
+	ID          string            `json:"id"`
+	MeetingID   string            `json:"meetingId"`
+	Type        TimelineEventType `json:"type"`
+	UserID      string            `json:"userId,omitempty"`
+	Description string            `json:"description"`
+	OccurredAt  time.Time         `json:"occurredAt"`
+}
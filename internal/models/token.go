@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+// TokenPurpose identifies what a user token can be redeemed for
+type TokenPurpose string
+
+const (
+	// TokenPurposeEmailChange confirms a pending change of a user's email address
+	TokenPurposeEmailChange TokenPurpose = "email_change"
+	// TokenPurposeEmailVerification verifies the email address a user signed up with
+	TokenPurposeEmailVerification TokenPurpose = "email_verification"
+)
+
+// UserToken is a single-use secret sent to a user's email address
+type UserToken struct {
+	Token     string
+	UserID    string
+	Purpose   TokenPurpose
+	Email     string // address the token was sent to
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
@@ -6,9 +6,48 @@ import (
 
 // User represents a user in the system who can organize or participate in meetings
 type User struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID            string          `json:"id"`
+	Name          string          `json:"name"`
+	Email         string          `json:"email"`
+	EmailVerified bool            `json:"emailVerified"`
+	FocusBlocks   []FocusBlock    `json:"focusBlocks,omitempty"`
+	ExternalID    string          `json:"externalId,omitempty"`    // ID of the user in the identity provider that provisioned them
+	Guest         bool            `json:"guest,omitempty"`         // invited by email from outside the organization, with access to their meetings only
+	DeactivatedAt *time.Time      `json:"deactivatedAt,omitempty"` // set while the user is deactivated
+	Digest        DigestFrequency `json:"digest,omitempty"`        // aggregates invitations and reconfirmation requests into one email
+	DigestSentAt  *time.Time      `json:"digestSentAt,omitempty"`  // when the last digest covered the user
+	QuietHours    *QuietHours     `json:"quietHours,omitempty"`    // daily period with only urgent notifications
+	SnoozedUntil  *time.Time      `json:"snoozedUntil,omitempty"`  // only urgent notifications until then
+	Metadata      Metadata        `json:"metadata,omitempty"`
+	CreatedAt     time.Time       `json:"createdAt"`
+	UpdatedAt     time.Time       `json:"updatedAt"`
+}
+
+// Active reports whether the user has not been deactivated
+func (u User) Active() bool {
+	return u.DeactivatedAt == nil
+}
+
+// UserProvisioning is the state of a user as asserted by an identity provider
+type UserProvisioning struct {
+	ExternalID string
+	Name       string
+	Email      string
+	Active     bool
+}
+
+// QuietHours is a daily period during which a user only receives urgent notifications;
+// others are delivered when it ends. It spans midnight when End is before Start.
+type QuietHours struct {
+	Start    string `json:"start"` // "15:04"
+	End      string `json:"end"`   // "15:04"
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// FocusBlock is a weekly recurring period a user keeps free of meetings
+type FocusBlock struct {
+	Days     []string `json:"days,omitempty"` // weekdays the block recurs on, every day when empty
+	Start    string   `json:"start"`          // "15:04"
+	End      string   `json:"end"`            // "15:04"
+	TimeZone string   `json:"timeZone,omitempty"`
 }
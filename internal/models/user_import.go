@@ -0,0 +1,69 @@
+package models
+
+// DuplicateStrategy decides what a user import does with rows whose email already
+// belongs to a user
+type DuplicateStrategy string
+
+const (
+	// DuplicateSkip leaves the existing user unchanged
+	DuplicateSkip DuplicateStrategy = "skip"
+	// DuplicateUpdate updates the name of the existing user
+	DuplicateUpdate DuplicateStrategy = "update"
+	// DuplicateFail reports the row as failed
+	DuplicateFail DuplicateStrategy = "fail"
+)
+
+// Valid reports whether s is a known strategy
+func (s DuplicateStrategy) Valid() bool {
+	switch s {
+	case DuplicateSkip, DuplicateUpdate, DuplicateFail:
+		return true
+	default:
+		return false
+	}
+}
+
+// UserImportRow is a user to import, with the line it was read from
+type UserImportRow struct {
+	Line  int
+	Name  string
+	Email string
+}
+
+// UserImportOptions configures a user import
+type UserImportOptions struct {
+	OnDuplicate DuplicateStrategy
+	Welcome     bool // send created users the welcome email asking them to verify their address
+}
+
+// UserImportStatus is the outcome of importing a single row
+type UserImportStatus string
+
+const (
+	// UserImportCreated means a new user was created
+	UserImportCreated UserImportStatus = "created"
+	// UserImportUpdated means an existing user with the same email was updated
+	UserImportUpdated UserImportStatus = "updated"
+	// UserImportSkipped means an existing user with the same email was left unchanged
+	UserImportSkipped UserImportStatus = "skipped"
+	// UserImportFailed means the row was invalid or could not be stored
+	UserImportFailed UserImportStatus = "failed"
+)
+
+// UserImportRowResult is the outcome of importing a single row
+type UserImportRowResult struct {
+	Line   int              `json:"line"`
+	Email  string           `json:"email"`
+	Status UserImportStatus `json:"status"`
+	UserID string           `json:"userId,omitempty"`
+	Error  string           `json:"error,omitempty"`
+}
+
+// UserImportResult summarizes a user import
+type UserImportResult struct {
+	Created int                   `json:"created"`
+	Updated int                   `json:"updated"`
+	Skipped int                   `json:"skipped"`
+	Failed  int                   `json:"failed"`
+	Rows    []UserImportRowResult `json:"rows"`
+}
@@ -0,0 +1,31 @@
+package models
+
+// WarningCode identifies a non-fatal issue found while processing a request
+type WarningCode string
+
+const (
+	// WarningDuplicateSlot reports a proposed slot that was dropped because it repeated another one
+	WarningDuplicateSlot WarningCode = "duplicate_slot"
+	// WarningContainedSlot reports a proposed slot that was dropped because another slot contains it
+	WarningContainedSlot WarningCode = "contained_slot"
+	// WarningOverlappingSlots reports proposed slots that partially overlap
+	WarningOverlappingSlots WarningCode = "overlapping_slots"
+	// WarningCoalescedSlots reports adjacent proposed slots that were merged into one
+	WarningCoalescedSlots WarningCode = "coalesced_slots"
+	// WarningDSTTransition reports a proposed slot that spans a daylight saving time change
+	WarningDSTTransition WarningCode = "dst_transition"
+	// WarningShortSlot reports a proposed slot shorter than the estimated duration of the meeting
+	WarningShortSlot WarningCode = "short_slot"
+	// WarningParticipantConflict reports a participant who offered a proposed slot to another open meeting
+	WarningParticipantConflict WarningCode = "participant_conflict"
+	// WarningOutsideWorkingHours reports a proposed slot outside the working hours of the organization
+	WarningOutsideWorkingHours WarningCode = "outside_working_hours"
+	// WarningPossibleDuplicate reports an existing meeting the new one seems to duplicate
+	WarningPossibleDuplicate WarningCode = "possible_duplicate"
+)
+
+// Warning describes a non-fatal issue that changed or may affect the stored result
+type Warning struct {
+	Code    WarningCode `json:"code"`
+	Message string      `json:"message"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Webhook is an endpoint of an integrator receiving scheduling events over HTTP
+type Webhook struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events,omitempty"` // event types delivered; empty for every type
+	Secret    string    `json:"secret,omitempty"` // signs deliveries; only returned when the webhook is created
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// WebhookDeliveryStatus represents the state of the delivery of an event to a webhook
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending is a delivery waiting to be attempted or retried
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliverySucceeded is a delivery the receiver acknowledged with a 2xx status
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	// WebhookDeliveryFailed is a delivery whose attempts all failed; it can be replayed
+	WebhookDeliveryFailed WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is the delivery of an event to a webhook, with every attempt made
+type WebhookDelivery struct {
+	ID        string                `json:"id"`
+	WebhookID string                `json:"webhookId"`
+	EventID   string                `json:"eventId"`
+	EventType string                `json:"eventType"`
+	Status    WebhookDeliveryStatus `json:"status"`
+	Attempts  []WebhookAttempt      `json:"attempts"`
+	CreatedAt time.Time             `json:"createdAt"`
+	UpdatedAt time.Time             `json:"updatedAt"`
+}
+
+// WebhookAttempt is one attempt at delivering an event to a webhook
+type WebhookAttempt struct {
+	At         time.Time `json:"at"`
+	StatusCode int       `json:"statusCode,omitempty"` // absent when no response was received
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"durationMs"`
+	Replay     bool      `json:"replay,omitempty"` // made because the delivery was replayed
+}
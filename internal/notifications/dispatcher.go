@@ -0,0 +1,124 @@
+package notifications
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"meetsync/pkg/logs"
+)
+
+// QuietHoursFunc reports whether a user does not want to be disturbed at t, and if so
+// when they can be notified again
+type QuietHoursFunc func(userID string, t time.Time) (until time.Time, quiet bool)
+
+// heldNotification is a notification waiting for the quiet hours of its recipient to end
+type heldNotification struct {
+	notification Notification
+	releaseAt    time.Time
+}
+
+// Dispatcher delivers notifications through another Notifier, holding back those sent to
+// users during their quiet hours until the hours end. Urgent notifications are delivered
+// at once. Held notifications are kept in memory and are lost when the process stops.
+type Dispatcher struct {
+	next       Notifier
+	quietHours QuietHoursFunc
+	now        func() time.Time
+
+	mu     sync.Mutex
+	held   []heldNotification // ordered by release time
+	timer  *time.Timer
+	closed bool
+}
+
+// NewDispatcher creates a Dispatcher delivering notifications through next, asking
+// quietHours when their recipients may be disturbed
+func NewDispatcher(next Notifier, quietHours QuietHoursFunc) *Dispatcher {
+	return &Dispatcher{next: next, quietHours: quietHours, now: time.Now}
+}
+
+// Send implements Notifier. Notifications held for quiet hours are reported as sent;
+// failures to deliver them later are logged.
+func (d *Dispatcher) Send(notification Notification) error {
+	if !notification.Urgent && notification.UserID != "" {
+		if until, quiet := d.quietHours(notification.UserID, d.now()); quiet {
+			d.hold(notification, until)
+			return nil
+		}
+	}
+	return d.next.Send(notification)
+}
+
+// Held returns the number of notifications waiting for quiet hours to end
+func (d *Dispatcher) Held() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.held)
+}
+
+// Close stops releasing held notifications and reports how many are dropped
+func (d *Dispatcher) Close() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.closed = true
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	dropped := len(d.held)
+	d.held = nil
+	return dropped
+}
+
+// hold queues a notification until releaseAt
+func (d *Dispatcher) hold(notification Notification, releaseAt time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		logs.Warn("Dropped %s notification to user %s during quiet hours: dispatcher is closed", notification.Kind, notification.UserID)
+		return
+	}
+
+	i := sort.Search(len(d.held), func(i int) bool { return d.held[i].releaseAt.After(releaseAt) })
+	d.held = append(d.held, heldNotification{})
+	copy(d.held[i+1:], d.held[i:])
+	d.held[i] = heldNotification{notification: notification, releaseAt: releaseAt}
+	if i == 0 {
+		d.scheduleLocked()
+	}
+}
+
+// scheduleLocked arms the timer for the earliest held notification
+func (d *Dispatcher) scheduleLocked() {
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	if len(d.held) == 0 {
+		return
+	}
+	d.timer = time.AfterFunc(d.held[0].releaseAt.Sub(d.now()), d.release)
+}
+
+// release delivers the held notifications whose quiet hours have ended
+func (d *Dispatcher) release() {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return
+	}
+	now := d.now()
+	due := 0
+	for due < len(d.held) && !d.held[due].releaseAt.After(now) {
+		due++
+	}
+	released := append([]heldNotification(nil), d.held[:due]...)
+	d.held = d.held[due:]
+	d.scheduleLocked()
+	d.mu.Unlock()
+
+	for _, held := range released {
+		if err := d.next.Send(held.notification); err != nil {
+			logs.Warn("Failed to deliver %s notification to user %s after quiet hours: %v", held.notification.Kind, held.notification.UserID, err)
+		}
+	}
+}
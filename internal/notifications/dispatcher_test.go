@@ -0,0 +1,67 @@
+package notifications
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// channelNotifier passes delivered notifications to a channel
+type channelNotifier chan Notification
+
+func (n channelNotifier) Send(notification Notification) error {
+	n <- notification
+	return nil
+}
+
+func TestDispatcher_HoldsNotificationsDuringQuietHours(t *testing.T) {
+	delivered := make(channelNotifier, 10)
+	quietUntil := map[string]time.Time{"quiet": time.Now().Add(50 * time.Millisecond)}
+	dispatcher := NewDispatcher(delivered, func(userID string, t time.Time) (time.Time, bool) {
+		until, quiet := quietUntil[userID]
+		return until, quiet && t.Before(until)
+	})
+	defer dispatcher.Close()
+
+	assert.NoError(t, dispatcher.Send(Notification{Kind: KindInvitation, UserID: "awake"}))
+	assert.Equal(t, "awake", (<-delivered).UserID)
+
+	// Urgent notifications go through quiet hours
+	assert.NoError(t, dispatcher.Send(Notification{Kind: KindInvitation, UserID: "quiet", Urgent: true}))
+	assert.True(t, (<-delivered).Urgent)
+
+	assert.NoError(t, dispatcher.Send(Notification{Kind: KindInvitation, UserID: "quiet", Subject: "first"}))
+	assert.NoError(t, dispatcher.Send(Notification{Kind: KindReconfirmation, UserID: "quiet", Subject: "second"}))
+	assert.Equal(t, 2, dispatcher.Held())
+	select {
+	case notification := <-delivered:
+		t.Fatalf("Expected the notification to be held, got %+v", notification)
+	default:
+	}
+
+	for _, subject := range []string{"first", "second"} {
+		select {
+		case notification := <-delivered:
+			assert.Equal(t, subject, notification.Subject)
+		case <-time.After(2 * time.Second):
+			t.Fatal("Timed out waiting for the quiet hours to end")
+		}
+	}
+	assert.Equal(t, 0, dispatcher.Held())
+}
+
+func TestDispatcher_CloseDropsHeldNotifications(t *testing.T) {
+	delivered := make(channelNotifier, 10)
+	dispatcher := NewDispatcher(delivered, func(userID string, t time.Time) (time.Time, bool) {
+		return t.Add(time.Hour), true
+	})
+
+	assert.NoError(t, dispatcher.Send(Notification{Kind: KindInvitation, UserID: "quiet"}))
+	assert.Equal(t, 1, dispatcher.Held())
+	assert.Equal(t, 1, dispatcher.Close())
+
+	assert.NoError(t, dispatcher.Send(Notification{Kind: KindInvitation, UserID: "quiet"}))
+	assert.Equal(t, 0, dispatcher.Held())
+	assert.Empty(t, delivered)
+}
@@ -0,0 +1,278 @@
+package notifications
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+
+	"meetsync/pkg/logs"
+)
+
+// Kind identifies the purpose of a notification
+type Kind string
+
+const (
+	// KindInvitation invites a participant to submit availability for a meeting
+	KindInvitation Kind = "invitation"
+	// KindEmailChange asks a user to confirm a new email address
+	KindEmailChange Kind = "email_change"
+	// KindEmailVerification asks a new user to verify their email address
+	KindEmailVerification Kind = "email_verification"
+	// KindReconfirmation asks a participant to reconfirm availability that went stale
+	KindReconfirmation Kind = "reconfirmation"
+	// KindDigest aggregates pending availability requests and upcoming meetings of a user
+	KindDigest Kind = "digest"
+	// KindFinalization tells a participant when a meeting was confirmed or moved to take place
+	KindFinalization Kind = "finalization"
+	// KindProposal tells an organizer a participant proposed another slot for a confirmed meeting
+	KindProposal Kind = "proposal"
+	// KindProposalDecision tells a participant whether the organizer accepted their proposed slot
+	KindProposalDecision Kind = "proposal_decision"
+)
+
+const (
+	// BackendLog writes notifications to the application log
+	BackendLog = "log"
+	// BackendSMTP delivers notifications as email through an SMTP server
+	BackendSMTP = "smtp"
+	// BackendNone discards all notifications
+	BackendNone = "none"
+)
+
+// Importance tells mail clients how to flag a notification
+type Importance string
+
+const (
+	// ImportanceLow marks notifications that can wait
+	ImportanceLow Importance = "low"
+	// ImportanceNormal is the default importance
+	ImportanceNormal Importance = "normal"
+	// ImportanceHigh marks notifications that need prompt attention
+	ImportanceHigh Importance = "high"
+)
+
+// Notification represents a message delivered to a user
+type Notification struct {
+	Kind       Kind
+	UserID     string
+	To         string
+	Subject    string
+	Body       string
+	HTML       string     // optional HTML alternative to Body
+	Importance Importance // empty means normal
+	Urgent     bool       // delivered even during the recipient's quiet hours
+	// Attachments are files sent along with the message, such as calendar invitations
+	Attachments []Attachment
+}
+
+// Attachment is a file attached to a notification
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+// Notifier defines the interface for delivering notifications to users
+type Notifier interface {
+	Send(notification Notification) error
+}
+
+// SMTPConfig holds the settings used to deliver notifications by email
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// NewNotifier creates a Notifier for the given backend
+func NewNotifier(backend string, smtpConfig SMTPConfig) (Notifier, error) {
+	switch strings.ToLower(backend) {
+	case "", BackendLog:
+		return LogNotifier{}, nil
+	case BackendNone:
+		return NoopNotifier{}, nil
+	case BackendSMTP:
+		if smtpConfig.Host == "" || smtpConfig.From == "" {
+			return nil, fmt.Errorf("notifications: SMTP backend requires a host and a from address")
+		}
+		return NewSMTPNotifier(smtpConfig), nil
+	default:
+		return nil, fmt.Errorf("notifications: unknown backend %q", backend)
+	}
+}
+
+// NoopNotifier discards all notifications
+type NoopNotifier struct{}
+
+// Send implements Notifier
+func (NoopNotifier) Send(notification Notification) error { return nil }
+
+// LogNotifier writes notifications to the application log, useful for local development
+type LogNotifier struct{}
+
+// Send implements Notifier
+func (LogNotifier) Send(notification Notification) error {
+	logs.Info("Notification (%s, %s importance) to %s: %s\n%s", notification.Kind, importanceOf(notification), logs.PII(notification.To), notification.Subject, logs.PII(notification.Body))
+	return nil
+}
+
+// SMTPNotifier delivers notifications as email, with optional HTML and attachments
+type SMTPNotifier struct {
+	config   SMTPConfig
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTPNotifier creates a new SMTPNotifier
+func NewSMTPNotifier(config SMTPConfig) *SMTPNotifier {
+	if config.Port == "" {
+		config.Port = "587"
+	}
+	return &SMTPNotifier{
+		config:   config,
+		sendMail: smtp.SendMail,
+	}
+}
+
+// Send implements Notifier
+func (n *SMTPNotifier) Send(notification Notification) error {
+	var auth smtp.Auth
+	if n.config.Username != "" {
+		auth = smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.Host)
+	}
+
+	msg := "From: " + n.config.From + "\r\n" +
+		"To: " + notification.To + "\r\n" +
+		"Subject: " + notification.Subject + "\r\n" +
+		priorityHeaders(importanceOf(notification)) +
+		"MIME-Version: 1.0\r\n"
+	body, contentType := notification.Body+"\r\n", "text/plain; charset=UTF-8"
+	var err error
+	if notification.HTML != "" {
+		body, contentType, err = alternativeBody(notification.Body, notification.HTML)
+		if err != nil {
+			return fmt.Errorf("notifications: failed to write %s email: %w", notification.Kind, err)
+		}
+	}
+	if len(notification.Attachments) > 0 {
+		body, contentType, err = mixedBody(body, contentType, notification.Attachments)
+		if err != nil {
+			return fmt.Errorf("notifications: failed to write %s email: %w", notification.Kind, err)
+		}
+	}
+	msg += "Content-Type: " + contentType + "\r\n" +
+		"\r\n" + body
+
+	addr := n.config.Host + ":" + n.config.Port
+	if err := n.sendMail(addr, auth, n.config.From, []string{notification.To}, []byte(msg)); err != nil {
+		return fmt.Errorf("notifications: failed to send %s email: %w", notification.Kind, err)
+	}
+	return nil
+}
+
+// alternativeBody renders a multipart/alternative body offering text and html, returning
+// the body and its content type
+func alternativeBody(text, html string) (string, string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for _, part := range []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=UTF-8", text},
+		{"text/html; charset=UTF-8", html},
+	} {
+		w, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return "", "", err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return "", "", err
+		}
+		if err := qp.Close(); err != nil {
+			return "", "", err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return "", "", err
+	}
+	return buf.String(), "multipart/alternative; boundary=" + writer.Boundary(), nil
+}
+
+// mixedBody renders a multipart/mixed body holding a message body of the given content
+// type followed by attachments, returning the body and its content type
+func mixedBody(body, contentType string, attachments []Attachment) (string, string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	w, err := writer.CreatePart(textproto.MIMEHeader{"Content-Type": {contentType}})
+	if err != nil {
+		return "", "", err
+	}
+	if _, err := io.WriteString(w, body); err != nil {
+		return "", "", err
+	}
+	for _, attachment := range attachments {
+		w, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {attachment.ContentType},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return "", "", err
+		}
+		if _, err := io.WriteString(w, wrapBase64(attachment.Content)); err != nil {
+			return "", "", err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return "", "", err
+	}
+	return buf.String(), "multipart/mixed; boundary=" + writer.Boundary(), nil
+}
+
+// wrapBase64 encodes content as base64 in lines of 76 characters, as MIME requires
+func wrapBase64(content []byte) string {
+	const lineLength = 76
+	encoded := base64.StdEncoding.EncodeToString(content)
+	var b strings.Builder
+	for len(encoded) > lineLength {
+		b.WriteString(encoded[:lineLength])
+		b.WriteString("\r\n")
+		encoded = encoded[lineLength:]
+	}
+	b.WriteString(encoded)
+	b.WriteString("\r\n")
+	return b.String()
+}
+
+// importanceOf returns the importance of a notification, defaulting to normal
+func importanceOf(notification Notification) Importance {
+	if notification.Importance == "" {
+		return ImportanceNormal
+	}
+	return notification.Importance
+}
+
+// priorityHeaders returns the email headers flagging a message with importance
+func priorityHeaders(importance Importance) string {
+	switch importance {
+	case ImportanceLow:
+		return "Importance: low\r\nX-Priority: 5\r\n"
+	case ImportanceHigh:
+		return "Importance: high\r\nX-Priority: 1\r\n"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,133 @@
+package notifications
+
+import (
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNotifier(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend string
+		smtp    SMTPConfig
+		wantErr bool
+	}{
+		{name: "default", backend: ""},
+		{name: "log", backend: "log"},
+		{name: "none", backend: "none"},
+		{name: "smtp", backend: "smtp", smtp: SMTPConfig{Host: "mail.example.com", From: "meetsync@example.com"}},
+		{name: "smtp without host", backend: "smtp", smtp: SMTPConfig{From: "meetsync@example.com"}, wantErr: true},
+		{name: "unknown", backend: "pigeon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier, err := NewNotifier(tt.backend, tt.smtp)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, notifier)
+		})
+	}
+}
+
+func TestSMTPNotifier_Send(t *testing.T) {
+	notifier := NewSMTPNotifier(SMTPConfig{
+		Host:     "mail.example.com",
+		Username: "meetsync",
+		Password: "secret",
+		From:     "meetsync@example.com",
+	})
+
+	var (
+		gotAddr string
+		gotFrom string
+		gotTo   []string
+		gotMsg  string
+	)
+	notifier.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotFrom, gotTo, gotMsg = addr, from, to, string(msg)
+		return nil
+	}
+
+	err := notifier.Send(Notification{
+		Kind:    KindInvitation,
+		To:      "participant@example.com",
+		Subject: "Invitation: Planning",
+		Body:    "Please submit your availability.",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "mail.example.com:587", gotAddr)
+	assert.Equal(t, "meetsync@example.com", gotFrom)
+	assert.Equal(t, []string{"participant@example.com"}, gotTo)
+	assert.Contains(t, gotMsg, "Subject: Invitation: Planning\r\n")
+	assert.Contains(t, gotMsg, "Please submit your availability.")
+	assert.NotContains(t, gotMsg, "X-Priority")
+
+	// Important notifications are flagged for mail clients
+	err = notifier.Send(Notification{
+		Kind:       KindInvitation,
+		To:         "participant@example.com",
+		Subject:    "Urgent invitation: Incident review",
+		Body:       "Please submit your availability.",
+		Importance: ImportanceHigh,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, gotMsg, "Importance: high\r\nX-Priority: 1\r\n")
+
+	// HTML bodies are sent alongside the plain text
+	err = notifier.Send(Notification{
+		Kind:    KindInvitation,
+		To:      "participant@example.com",
+		Subject: "Invitation: Planning",
+		Body:    "Please submit your availability.",
+		HTML:    "<p>Please submit your availability.</p>",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, gotMsg, "Content-Type: multipart/alternative; boundary=")
+	assert.Contains(t, gotMsg, "Content-Type: text/plain; charset=UTF-8")
+	assert.Contains(t, gotMsg, "Content-Type: text/html; charset=UTF-8")
+	assert.Contains(t, gotMsg, "<p>Please submit your availability.</p>")
+
+	// Attachments follow the message body
+	invite := []byte("BEGIN:VCALENDAR\r\nMETHOD:REQUEST\r\nEND:VCALENDAR\r\n")
+	err = notifier.Send(Notification{
+		Kind:        KindFinalization,
+		To:          "participant@example.com",
+		Subject:     "Meeting confirmed: Planning",
+		Body:        "The meeting is confirmed.",
+		HTML:        "<p>The meeting is confirmed.</p>",
+		Attachments: []Attachment{{Filename: "invite.ics", ContentType: "text/calendar; charset=utf-8; method=REQUEST", Content: invite}},
+	})
+	require.NoError(t, err)
+	message, err := mail.ReadMessage(strings.NewReader(gotMsg))
+	require.NoError(t, err)
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/mixed", mediaType)
+	parts := multipart.NewReader(message.Body, params["boundary"])
+	body, err := parts.NextPart()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(body.Header.Get("Content-Type"), "multipart/alternative; boundary="))
+	attachment, err := parts.NextPart()
+	require.NoError(t, err)
+	assert.Equal(t, "text/calendar; charset=utf-8; method=REQUEST", attachment.Header.Get("Content-Type"))
+	assert.Equal(t, "invite.ics", attachment.FileName())
+	content, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, attachment))
+	require.NoError(t, err)
+	assert.Equal(t, invite, content)
+	_, err = parts.NextPart()
+	assert.Equal(t, io.EOF, err)
+}
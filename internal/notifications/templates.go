@@ -0,0 +1,362 @@
+package notifications
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+//go:embed templates/*.tmpl
+var defaultTemplateFiles embed.FS
+
+// Parts of a notification rendered by templates, used as file name suffixes
+const (
+	partSubject = ".subject.tmpl"
+	partText    = ".txt.tmpl"
+	partHTML    = ".html.tmpl"
+)
+
+// templateFuncs are available to every template
+var templateFuncs = map[string]any{
+	// datetime formats a time for people in any time zone
+	"datetime": func(t time.Time) string { return t.UTC().Format(time.RFC1123) },
+	"add":      func(a, b int) int { return a + b },
+}
+
+// InvitationData is rendered by the invitation templates
+type InvitationData struct {
+	Recipient string // name of the invited participant
+	Organizer string // name of the organizer
+	Title     string
+	Duration  int // in minutes
+	MeetingID string
+	Urgent    bool
+	Slots     []InvitationSlot
+	GuestURL  string // link to the meeting for guests from outside the organization
+	// Rescheduled is set when the slots were added to a meeting the participant was
+	// already invited to; availability submitted for its other slots is kept
+	Rescheduled bool
+}
+
+// InvitationSlot is a proposed time of an invitation
+type InvitationSlot struct {
+	Start   time.Time
+	End     time.Time
+	RSVPURL string // one-click link marking the participant free for the slot, when enabled
+}
+
+// RSVPLinks reports whether the slots carry one-click RSVP links
+func (d InvitationData) RSVPLinks() bool {
+	return len(d.Slots) > 0 && d.Slots[0].RSVPURL != ""
+}
+
+// ReconfirmationData is rendered by the reconfirmation templates
+type ReconfirmationData struct {
+	Recipient      string // name of the participant
+	Title          string
+	MeetingID      string
+	AvailabilityID string
+	ConfirmedAt    time.Time // when the participant last confirmed their availability
+}
+
+// FinalizationData is rendered by the finalization templates
+type FinalizationData struct {
+	Recipient string // name of the participant
+	Organizer string // name of the organizer
+	Title     string
+	MeetingID string
+	Start     time.Time // when the meeting takes place
+	End       time.Time
+	// Rescheduled is set when a confirmed meeting moved to another slot, proposed by
+	// ProposedBy
+	Rescheduled bool
+	ProposedBy  string
+}
+
+// EmailVerificationData is rendered by the email verification templates
+type EmailVerificationData struct {
+	Recipient string // name of the new user
+	Token     string
+	ExpiresAt time.Time
+}
+
+// EmailChangeData is rendered by the email change templates
+type EmailChangeData struct {
+	Recipient string // name of the user
+	Email     string // new address to confirm
+	Token     string
+	ExpiresAt time.Time
+}
+
+// DigestData is rendered by the digest templates
+type DigestData struct {
+	Recipient string // name of the user
+	Period    string // daily or weekly
+	Pending   []DigestMeeting
+	Upcoming  []DigestUpcoming
+}
+
+// DigestMeeting is a meeting waiting for the availability of the recipient of a digest
+type DigestMeeting struct {
+	Title     string
+	Duration  int // in minutes
+	MeetingID string
+	Slots     []InvitationSlot
+}
+
+// RSVPLinks reports whether the slots carry one-click RSVP links
+func (m DigestMeeting) RSVPLinks() bool {
+	return len(m.Slots) > 0 && m.Slots[0].RSVPURL != ""
+}
+
+// DigestUpcoming is a meeting whose best time starts before the next digest
+type DigestUpcoming struct {
+	Title     string
+	MeetingID string
+	Start     time.Time
+	End       time.Time
+}
+
+// ProposalData is rendered by the proposal templates
+type ProposalData struct {
+	Recipient      string // name of the organizer
+	Participant    string // name of the participant proposing another time
+	Title          string
+	MeetingID      string
+	ProposalID     string
+	Reason         string    // empty when none was given
+	ConfirmedStart time.Time // when the meeting is confirmed to take place
+	ConfirmedEnd   time.Time
+	ProposedStart  time.Time // when the participant proposed it takes place instead
+	ProposedEnd    time.Time
+}
+
+// ProposalDecisionData is rendered by the proposal decision templates
+type ProposalDecisionData struct {
+	Recipient      string // name of the participant who made the proposal
+	Title          string
+	MeetingID      string
+	Accepted       bool
+	ConfirmedStart time.Time // when the meeting takes place after the decision
+	ConfirmedEnd   time.Time
+	ProposedStart  time.Time
+	ProposedEnd    time.Time
+}
+
+// sampleData is rendered by templates when they are loaded, so that mistakes such as
+// misspelled fields are found at startup rather than when notifying participants
+var sampleData = map[Kind]any{
+	KindInvitation: InvitationData{
+		Recipient: "Jane Doe",
+		Organizer: "John Doe",
+		Title:     "Planning",
+		Duration:  60,
+		MeetingID: "meeting-id",
+		Slots:     []InvitationSlot{{Start: time.Unix(0, 0), End: time.Unix(3600, 0), RSVPURL: "https://meetsync.example.com/api/rsvp/token"}},
+		GuestURL:  "https://meetsync.example.com/api/guest/token",
+	},
+	KindReconfirmation: ReconfirmationData{
+		Recipient:      "Jane Doe",
+		Title:          "Planning",
+		MeetingID:      "meeting-id",
+		AvailabilityID: "availability-id",
+		ConfirmedAt:    time.Unix(0, 0),
+	},
+	KindFinalization: FinalizationData{
+		Recipient:   "Jane Doe",
+		Organizer:   "John Doe",
+		Title:       "Planning",
+		MeetingID:   "meeting-id",
+		Start:       time.Unix(0, 0),
+		End:         time.Unix(3600, 0),
+		Rescheduled: true,
+		ProposedBy:  "Jane Doe",
+	},
+	KindProposal: ProposalData{
+		Recipient:      "John Doe",
+		Participant:    "Jane Doe",
+		Title:          "Planning",
+		MeetingID:      "meeting-id",
+		ProposalID:     "proposal-id",
+		Reason:         "Dentist appointment",
+		ConfirmedStart: time.Unix(0, 0),
+		ConfirmedEnd:   time.Unix(3600, 0),
+		ProposedStart:  time.Unix(7200, 0),
+		ProposedEnd:    time.Unix(10800, 0),
+	},
+	KindProposalDecision: ProposalDecisionData{
+		Recipient:      "Jane Doe",
+		Title:          "Planning",
+		MeetingID:      "meeting-id",
+		Accepted:       true,
+		ConfirmedStart: time.Unix(7200, 0),
+		ConfirmedEnd:   time.Unix(10800, 0),
+		ProposedStart:  time.Unix(7200, 0),
+		ProposedEnd:    time.Unix(10800, 0),
+	},
+	KindEmailVerification: EmailVerificationData{
+		Recipient: "Jane Doe",
+		Token:     "token",
+		ExpiresAt: time.Unix(0, 0),
+	},
+	KindEmailChange: EmailChangeData{
+		Recipient: "Jane Doe",
+		Email:     "jane@example.com",
+		Token:     "token",
+		ExpiresAt: time.Unix(0, 0),
+	},
+	KindDigest: DigestData{
+		Recipient: "Jane Doe",
+		Period:    "daily",
+		Pending: []DigestMeeting{{
+			Title:     "Planning",
+			Duration:  60,
+			MeetingID: "meeting-id",
+			Slots:     []InvitationSlot{{Start: time.Unix(0, 0), End: time.Unix(3600, 0), RSVPURL: "https://meetsync.example.com/api/rsvp/token"}},
+		}},
+		Upcoming: []DigestUpcoming{{Title: "Review", MeetingID: "meeting-id", Start: time.Unix(0, 0), End: time.Unix(3600, 0)}},
+	},
+}
+
+// Message is a notification rendered from templates
+type Message struct {
+	Subject string
+	Text    string
+	HTML    string // empty without an HTML template
+}
+
+// templateSet holds the templates of one kind of notification
+type templateSet struct {
+	subject *template.Template
+	text    *template.Template
+	html    *htmltemplate.Template
+}
+
+// Templates render the subject and body of every notification sent by email. Bodies are
+// plain text, with an HTML alternative when an HTML template is given.
+type Templates struct {
+	sets map[Kind]templateSet
+}
+
+// DefaultTemplates returns the built-in templates
+func DefaultTemplates() *Templates {
+	templates, err := LoadTemplates("")
+	if err != nil {
+		panic(err)
+	}
+	return templates
+}
+
+// LoadTemplates returns the built-in templates overridden by the files in dir, if any.
+// Files are named after the kind of notification and the part they render, such as
+// invitation.subject.tmpl, invitation.txt.tmpl and invitation.html.tmpl. Subjects and
+// text bodies are Go text templates; HTML bodies are html/template templates, so values
+// are escaped. Unknown files are rejected so that misnamed overrides do not go unnoticed.
+func LoadTemplates(dir string) (*Templates, error) {
+	sources := make(map[string]string)
+	defaults, err := defaultTemplateFiles.ReadDir("templates")
+	if err != nil {
+		return nil, fmt.Errorf("notifications: failed to read built-in templates: %w", err)
+	}
+	for _, entry := range defaults {
+		content, err := defaultTemplateFiles.ReadFile("templates/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("notifications: failed to read built-in templates: %w", err)
+		}
+		sources[entry.Name()] = string(content)
+	}
+	if dir != "" {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("notifications: failed to read templates: %w", err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("notifications: failed to read templates: %w", err)
+			}
+			sources[entry.Name()] = string(content)
+		}
+	}
+
+	templates := &Templates{sets: make(map[Kind]templateSet)}
+	for name, source := range sources {
+		kind, part, ok := templatePart(name)
+		if !ok {
+			return nil, fmt.Errorf("notifications: unknown template %s", name)
+		}
+		set := templates.sets[kind]
+		switch part {
+		case partSubject:
+			set.subject, err = template.New(name).Funcs(templateFuncs).Parse(source)
+		case partText:
+			set.text, err = template.New(name).Funcs(templateFuncs).Parse(source)
+		case partHTML:
+			set.html, err = htmltemplate.New(name).Funcs(templateFuncs).Parse(source)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("notifications: invalid template: %w", err)
+		}
+		templates.sets[kind] = set
+	}
+
+	for kind, data := range sampleData {
+		if _, err := templates.Render(kind, data); err != nil {
+			return nil, err
+		}
+	}
+	return templates, nil
+}
+
+// templatePart splits a template file name into the kind of notification and the part
+// it renders
+func templatePart(name string) (Kind, string, bool) {
+	for _, part := range []string{partSubject, partText, partHTML} {
+		if kind, found := strings.CutSuffix(name, part); found {
+			if _, known := sampleData[Kind(kind)]; known {
+				return Kind(kind), part, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// Render renders the notification of kind for data
+func (t *Templates) Render(kind Kind, data any) (Message, error) {
+	set, found := t.sets[kind]
+	if !found || set.subject == nil || set.text == nil {
+		return Message{}, fmt.Errorf("notifications: no templates for %s notifications", kind)
+	}
+
+	var message Message
+	var buf bytes.Buffer
+	if err := set.subject.Execute(&buf, data); err != nil {
+		return Message{}, fmt.Errorf("notifications: failed to render %s subject: %w", kind, err)
+	}
+	// Subjects are email headers, so they must stay on one line
+	message.Subject = strings.Join(strings.Fields(buf.String()), " ")
+
+	buf.Reset()
+	if err := set.text.Execute(&buf, data); err != nil {
+		return Message{}, fmt.Errorf("notifications: failed to render %s body: %w", kind, err)
+	}
+	message.Text = strings.TrimRight(buf.String(), "\n")
+
+	if set.html != nil {
+		buf.Reset()
+		if err := set.html.Execute(&buf, data); err != nil {
+			return Message{}, fmt.Errorf("notifications: failed to render %s HTML body: %w", kind, err)
+		}
+		message.HTML = buf.String()
+	}
+	return message, nil
+}
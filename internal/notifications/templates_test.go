@@ -0,0 +1,117 @@
+package notifications
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultTemplates_Render(t *testing.T) {
+	start := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	message, err := DefaultTemplates().Render(KindInvitation, InvitationData{
+		Organizer: "John Doe",
+		Title:     "Planning",
+		Duration:  60,
+		MeetingID: "meeting-1",
+		Urgent:    true,
+		Slots: []InvitationSlot{
+			{Start: start, End: start.Add(time.Hour), RSVPURL: "https://meetsync.example.com/api/rsvp/one"},
+			{Start: start.Add(24 * time.Hour), End: start.Add(25 * time.Hour), RSVPURL: "https://meetsync.example.com/api/rsvp/two"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Urgent invitation: Planning", message.Subject)
+	assert.Contains(t, message.Text, `John Doe invited you to "Planning" (60 minutes).`)
+	assert.Contains(t, message.Text, "- Tue, 10 Mar 2026 09:00:00 UTC to Tue, 10 Mar 2026 10:00:00 UTC\n")
+	assert.Contains(t, message.Text, "- I'm free for slot 2: https://meetsync.example.com/api/rsvp/two")
+	assert.NotContains(t, message.Text, "View the proposed times")
+	assert.Empty(t, message.HTML)
+
+	message, err = DefaultTemplates().Render(KindInvitation, InvitationData{
+		Organizer:   "John Doe",
+		Title:       "Planning",
+		Duration:    60,
+		MeetingID:   "meeting-1",
+		Rescheduled: true,
+		Slots:       []InvitationSlot{{Start: start, End: start.Add(time.Hour)}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "New times proposed: Planning", message.Subject)
+	assert.Contains(t, message.Text, "John Doe proposed new times for \"Planning\" (60 minutes). Your availability for the other times was kept.\n\nNew times:\n- Tue, 10 Mar 2026")
+
+	message, err = DefaultTemplates().Render(KindFinalization, FinalizationData{
+		Organizer: "John Doe",
+		Title:     "Planning",
+		MeetingID: "meeting-1",
+		Start:     start,
+		End:       start.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Meeting confirmed: Planning", message.Subject)
+	assert.Contains(t, message.Text, `John Doe confirmed "Planning". It takes place from Tue, 10 Mar 2026 09:00:00 UTC to Tue, 10 Mar 2026 10:00:00 UTC.`)
+
+	message, err = DefaultTemplates().Render(KindDigest, DigestData{
+		Period:   "weekly",
+		Upcoming: []DigestUpcoming{{Title: "Review", Start: start, End: start.Add(time.Hour)}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Your weekly MeetSync digest", message.Subject)
+	assert.Equal(t, "Here is your weekly summary of MeetSync.\n\n"+
+		"Coming up before your next digest (1):\n"+
+		"- \"Review\": best time Tue, 10 Mar 2026 09:00:00 UTC to Tue, 10 Mar 2026 10:00:00 UTC\n\n"+
+		"You receive this digest instead of an email per invitation. Set your digest to off to receive them as they happen.", message.Text)
+
+	_, err = DefaultTemplates().Render("unknown", nil)
+	assert.Error(t, err)
+}
+
+func TestLoadTemplates(t *testing.T) {
+	t.Run("overrides", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTemplate(t, dir, "invitation.subject.tmpl", "[Acme]\n{{.Title}}\n")
+		writeTemplate(t, dir, "invitation.html.tmpl", `<p>{{.Organizer}} invited you to <b>{{.Title}}</b></p>`)
+
+		templates, err := LoadTemplates(dir)
+		require.NoError(t, err)
+		message, err := templates.Render(KindInvitation, InvitationData{Organizer: "John Doe", Title: "Q&A <draft>"})
+		require.NoError(t, err)
+
+		// Subjects stay on one line and HTML is escaped
+		assert.Equal(t, "[Acme] Q&A <draft>", message.Subject)
+		assert.Equal(t, "<p>John Doe invited you to <b>Q&amp;A &lt;draft&gt;</b></p>", message.HTML)
+		assert.Contains(t, message.Text, `invited you to "Q&A <draft>"`)
+
+		// Other kinds keep the built-in templates
+		message, err = templates.Render(KindReconfirmation, ReconfirmationData{Title: "Planning"})
+		require.NoError(t, err)
+		assert.Equal(t, "Please reconfirm your availability: Planning", message.Subject)
+	})
+
+	for name, files := range map[string]map[string]string{
+		"unknown file":  {"welcome.txt.tmpl": "Welcome"},
+		"syntax error":  {"invitation.txt.tmpl": "{{.Title"},
+		"unknown field": {"reconfirmation.subject.tmpl": "{{.Organizer}}"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			for file, content := range files {
+				writeTemplate(t, dir, file, content)
+			}
+			_, err := LoadTemplates(dir)
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := LoadTemplates(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
+
+func writeTemplate(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+}
@@ -0,0 +1,83 @@
+// Package pagination implements keyset pagination with opaque cursors.
+//
+// Listings are ordered by (timestamp, ID) and a cursor records the position of
+// the last item of a page, so fetching a deep page costs the same as fetching
+// the first one.
+package pagination
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"meetsync/pkg/errors"
+)
+
+const (
+	// DefaultLimit is the page size used when none is requested
+	DefaultLimit = 50
+	// MaxLimit is the largest page size a client can request
+	MaxLimit = 200
+)
+
+// Cursor identifies the position after which the next page starts
+type Cursor struct {
+	Timestamp time.Time
+	ID        string
+}
+
+// After reports whether an item with the given timestamp and ID sorts after the cursor
+func (c Cursor) After(timestamp time.Time, id string) bool {
+	if !timestamp.Equal(c.Timestamp) {
+		return timestamp.After(c.Timestamp)
+	}
+	return id > c.ID
+}
+
+// Encode returns the opaque string representation of the cursor
+func (c Cursor) Encode() string {
+	raw := strconv.FormatInt(c.Timestamp.UnixNano(), 10) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// Decode parses an opaque cursor. An empty string yields a nil cursor, meaning the first page.
+func Decode(cursor string) (*Cursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.NewValidationError("Invalid cursor", "")
+	}
+	nanos, id, found := strings.Cut(string(raw), "|")
+	if !found || id == "" {
+		return nil, errors.NewValidationError("Invalid cursor", "")
+	}
+	unixNano, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, errors.NewValidationError("Invalid cursor", "")
+	}
+
+	return &Cursor{Timestamp: time.Unix(0, unixNano), ID: id}, nil
+}
+
+// Less orders items by timestamp, then ID
+func Less(timestampA time.Time, idA string, timestampB time.Time, idB string) bool {
+	if !timestampA.Equal(timestampB) {
+		return timestampA.Before(timestampB)
+	}
+	return idA < idB
+}
+
+// NormalizeLimit applies the default and maximum page sizes
+func NormalizeLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultLimit
+	}
+	if limit > MaxLimit {
+		return MaxLimit
+	}
+	return limit
+}
@@ -0,0 +1,48 @@
+package pagination
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	original := Cursor{Timestamp: time.Date(2025, 1, 12, 14, 0, 0, 123, time.UTC), ID: "meeting-1"}
+
+	decoded, err := Decode(original.Encode())
+	require.NoError(t, err)
+	require.NotNil(t, decoded)
+	assert.True(t, original.Timestamp.Equal(decoded.Timestamp))
+	assert.Equal(t, original.ID, decoded.ID)
+
+	// An empty cursor means the first page
+	decoded, err = Decode("")
+	assert.NoError(t, err)
+	assert.Nil(t, decoded)
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	for _, cursor := range []string{"not base64!", "bm8tc2VwYXJhdG9y", "YWJjfGlk"} {
+		_, err := Decode(cursor)
+		assert.Error(t, err, cursor)
+	}
+}
+
+func TestCursorAfter(t *testing.T) {
+	now := time.Now()
+	cursor := Cursor{Timestamp: now, ID: "b"}
+
+	assert.True(t, cursor.After(now.Add(time.Second), "a"))
+	assert.True(t, cursor.After(now, "c"))
+	assert.False(t, cursor.After(now, "b"))
+	assert.False(t, cursor.After(now, "a"))
+	assert.False(t, cursor.After(now.Add(-time.Second), "z"))
+}
+
+func TestNormalizeLimit(t *testing.T) {
+	assert.Equal(t, DefaultLimit, NormalizeLimit(0))
+	assert.Equal(t, 10, NormalizeLimit(10))
+	assert.Equal(t, MaxLimit, NormalizeLimit(MaxLimit+1))
+}
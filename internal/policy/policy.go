@@ -0,0 +1,222 @@
+// Package policy evaluates the scheduling rules of an organization, such as lunch
+// blocks or a daily meeting limit, against candidate meeting slots.
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"meetsync/internal/models"
+)
+
+// Kind identifies what a rule restricts
+type Kind string
+
+const (
+	// KindBlockedHours forbids meetings overlapping a daily time range, e.g. a lunch block
+	// or Friday afternoons
+	KindBlockedHours Kind = "blocked_hours"
+	// KindDailyLimit caps the minutes of meetings a person has on a single day
+	KindDailyLimit Kind = "daily_limit"
+)
+
+// Rule is a single scheduling policy
+type Rule struct {
+	Name       string   `json:"name"`
+	Kind       Kind     `json:"kind"`
+	Days       []string `json:"days,omitempty"`       // weekdays the rule applies to, every day when empty
+	Start      string   `json:"start,omitempty"`      // start of blocked hours, "15:04"; midnight when empty
+	End        string   `json:"end,omitempty"`        // end of blocked hours, "15:04"; end of day when empty
+	MaxMinutes int      `json:"maxMinutes,omitempty"` // daily limit in minutes
+}
+
+// BookedTime returns how much of day a user already spends in meetings
+type BookedTime func(userID string, day time.Time) time.Duration
+
+// rule is a validated Rule
+type rule struct {
+	Rule
+	days       map[time.Weekday]bool
+	start, end time.Duration // offsets from midnight
+}
+
+// Engine evaluates a set of rules. A nil Engine has no rules.
+type Engine struct {
+	rules []rule
+}
+
+// New validates rules and creates an Engine for them
+func New(rules []Rule) (*Engine, error) {
+	engine := &Engine{rules: make([]rule, 0, len(rules))}
+	for _, r := range rules {
+		validated, err := validate(r)
+		if err != nil {
+			return nil, err
+		}
+		engine.rules = append(engine.rules, validated)
+	}
+	return engine, nil
+}
+
+// Load reads rules from a JSON file holding {"rules": [...]}. Without a path the
+// Engine has no rules.
+func Load(path string) (*Engine, error) {
+	if path == "" {
+		return New(nil)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("policy: failed to read %s: %w", path, err)
+	}
+	var file struct {
+		Rules []Rule `json:"rules"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("policy: failed to parse %s: %w", path, err)
+	}
+	return New(file.Rules)
+}
+
+// validate checks a rule and resolves its days and hours
+func validate(r Rule) (rule, error) {
+	if r.Name == "" {
+		return rule{}, fmt.Errorf("policy: rule without a name")
+	}
+
+	validated := rule{Rule: r, end: 24 * time.Hour}
+	if len(r.Days) > 0 {
+		validated.days = make(map[time.Weekday]bool, len(r.Days))
+		for _, day := range r.Days {
+			weekday, ok := parseWeekday(day)
+			if !ok {
+				return rule{}, fmt.Errorf("policy: rule %q has unknown day %q", r.Name, day)
+			}
+			validated.days[weekday] = true
+		}
+	}
+
+	switch r.Kind {
+	case KindBlockedHours:
+		var err error
+		if r.Start != "" {
+			if validated.start, err = parseClock(r.Start); err != nil {
+				return rule{}, fmt.Errorf("policy: rule %q has invalid start %q", r.Name, r.Start)
+			}
+		}
+		if r.End != "" {
+			if validated.end, err = parseClock(r.End); err != nil {
+				return rule{}, fmt.Errorf("policy: rule %q has invalid end %q", r.Name, r.End)
+			}
+		}
+		if validated.end <= validated.start {
+			return rule{}, fmt.Errorf("policy: rule %q must end after it starts", r.Name)
+		}
+	case KindDailyLimit:
+		if r.MaxMinutes <= 0 {
+			return rule{}, fmt.Errorf("policy: rule %q needs a positive maxMinutes", r.Name)
+		}
+	default:
+		return rule{}, fmt.Errorf("policy: rule %q has unknown kind %q", r.Name, r.Kind)
+	}
+	return validated, nil
+}
+
+// parseWeekday parses a full or three-letter English weekday name
+func parseWeekday(name string) (time.Weekday, bool) {
+	name = strings.ToLower(name)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if name == full || name == full[:3] {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// parseClock parses a "15:04" time of day into an offset from midnight
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Evaluate returns the rules a meeting held in slot would break for the given attendees.
+// Times of day are read in location. booked may be nil when nothing is booked yet.
+func (e *Engine) Evaluate(slot models.TimeSlot, location *time.Location, attendeeIDs []string, booked BookedTime) []models.PolicyViolation {
+	if e == nil || len(e.rules) == 0 {
+		return nil
+	}
+
+	var violations []models.PolicyViolation
+	for _, r := range e.rules {
+		switch r.Kind {
+		case KindBlockedHours:
+			if r.blocks(slot, location) {
+				violations = append(violations, models.PolicyViolation{
+					Policy:  r.Name,
+					Message: "Overlaps blocked hours",
+				})
+			}
+		case KindDailyLimit:
+			day := slot.StartTime.In(location)
+			if !r.appliesOn(day.Weekday()) {
+				continue
+			}
+
+			length := slot.EndTime.Sub(slot.StartTime)
+			limit := time.Duration(r.MaxMinutes) * time.Minute
+			var over []string
+			for _, id := range attendeeIDs {
+				total := length
+				if booked != nil {
+					total += booked(id, day)
+				}
+				if total > limit {
+					over = append(over, id)
+				}
+			}
+			if len(over) > 0 {
+				violations = append(violations, models.PolicyViolation{
+					Policy:  r.Name,
+					Message: fmt.Sprintf("Exceeds %d minutes of meetings per day", r.MaxMinutes),
+					UserIDs: over,
+				})
+			}
+		}
+	}
+	return violations
+}
+
+// appliesOn reports whether the rule is in effect on day
+func (r rule) appliesOn(day time.Weekday) bool {
+	return r.days == nil || r.days[day]
+}
+
+// blocks reports whether slot overlaps the blocked hours of any day it spans
+func (r rule) blocks(slot models.TimeSlot, location *time.Location) bool {
+	start, end := slot.StartTime.In(location), slot.EndTime.In(location)
+	year, month, day := start.Date()
+	for date := time.Date(year, month, day, 0, 0, 0, 0, location); date.Before(end); date = date.AddDate(0, 0, 1) {
+		if !r.appliesOn(date.Weekday()) {
+			continue
+		}
+		blockStart, blockEnd := atClock(date, r.start), atClock(date, r.end)
+		if blockStart.Before(end) && start.Before(blockEnd) {
+			return true
+		}
+	}
+	return false
+}
+
+// atClock returns the instant at a time of day on date's wall clock, so rules keep their
+// local hours across daylight saving time changes
+func atClock(date time.Time, offset time.Duration) time.Time {
+	year, month, day := date.Date()
+	return time.Date(year, month, day, int(offset/time.Hour), int(offset%time.Hour/time.Minute), 0, 0, date.Location())
+}
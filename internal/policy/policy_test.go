@@ -0,0 +1,136 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"meetsync/internal/models"
+)
+
+func slotAt(start time.Time, minutes int) models.TimeSlot {
+	return models.TimeSlot{StartTime: start, EndTime: start.Add(time.Duration(minutes) * time.Minute)}
+}
+
+func TestEvaluate(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	engine, err := New([]Rule{
+		{Name: "lunch", Kind: KindBlockedHours, Start: "12:00", End: "13:00"},
+		{Name: "friday afternoons", Kind: KindBlockedHours, Days: []string{"friday"}, Start: "14:00"},
+		{Name: "four hours a day", Kind: KindDailyLimit, MaxMinutes: 240},
+	})
+	require.NoError(t, err)
+
+	thursday := time.Date(2025, 1, 16, 0, 0, 0, 0, paris)
+	friday := time.Date(2025, 1, 17, 0, 0, 0, 0, paris)
+
+	tests := []struct {
+		name     string
+		slot     models.TimeSlot
+		booked   BookedTime
+		policies []string
+	}{
+		{
+			name: "morning slot is fine",
+			slot: slotAt(thursday.Add(9*time.Hour), 60),
+		},
+		{
+			name:     "slot overlapping lunch",
+			slot:     slotAt(thursday.Add(11*time.Hour+30*time.Minute), 60),
+			policies: []string{"lunch"},
+		},
+		{
+			name: "slot ending when lunch starts",
+			slot: slotAt(thursday.Add(11*time.Hour), 60),
+		},
+		{
+			name: "thursday afternoon",
+			slot: slotAt(thursday.Add(15*time.Hour), 60),
+		},
+		{
+			name:     "friday afternoon",
+			slot:     slotAt(friday.Add(15*time.Hour), 60),
+			policies: []string{"friday afternoons"},
+		},
+		{
+			name:     "meeting longer than the daily limit",
+			slot:     slotAt(thursday.Add(8*time.Hour), 300),
+			policies: []string{"lunch", "four hours a day"},
+		},
+		{
+			name: "booked time counts towards the daily limit",
+			slot: slotAt(thursday.Add(9*time.Hour), 60),
+			booked: func(userID string, day time.Time) time.Duration {
+				if userID == "busy" {
+					return 4 * time.Hour
+				}
+				return 0
+			},
+			policies: []string{"four hours a day"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := engine.Evaluate(tt.slot, paris, []string{"busy", "free"}, tt.booked)
+
+			var policies []string
+			for _, violation := range violations {
+				policies = append(policies, violation.Policy)
+			}
+			assert.Equal(t, tt.policies, policies)
+		})
+	}
+
+	t.Run("daily limit names the people over it", func(t *testing.T) {
+		violations := engine.Evaluate(slotAt(thursday.Add(9*time.Hour), 60), paris, []string{"busy", "free"}, func(userID string, day time.Time) time.Duration {
+			if userID == "busy" {
+				return 4 * time.Hour
+			}
+			return 0
+		})
+		require.Len(t, violations, 1)
+		assert.Equal(t, []string{"busy"}, violations[0].UserIDs)
+	})
+
+	t.Run("nil engine has no rules", func(t *testing.T) {
+		var none *Engine
+		assert.Empty(t, none.Evaluate(slotAt(thursday.Add(12*time.Hour), 60), paris, nil, nil))
+	})
+}
+
+func TestNew_InvalidRules(t *testing.T) {
+	rules := map[string]Rule{
+		"missing name":      {Kind: KindDailyLimit, MaxMinutes: 60},
+		"unknown kind":      {Name: "x", Kind: "holidays"},
+		"unknown day":       {Name: "x", Kind: KindBlockedHours, Days: []string{"someday"}},
+		"invalid start":     {Name: "x", Kind: KindBlockedHours, Start: "noon"},
+		"ends before start": {Name: "x", Kind: KindBlockedHours, Start: "13:00", End: "12:00"},
+		"no daily limit":    {Name: "x", Kind: KindDailyLimit},
+	}
+	for name, rule := range rules {
+		_, err := New([]Rule{rule})
+		assert.Error(t, err, name)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	engine, err := Load("")
+	require.NoError(t, err)
+	assert.Empty(t, engine.rules)
+
+	path := filepath.Join(t.TempDir(), "policies.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"rules":[{"name":"lunch","kind":"blocked_hours","start":"12:00","end":"13:00"}]}`), 0o600))
+	engine, err = Load(path)
+	require.NoError(t, err)
+	assert.Len(t, engine.rules, 1)
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
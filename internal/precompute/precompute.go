@@ -0,0 +1,186 @@
+// Package precompute hands off recomputing the stored recommendations of meetings from
+// API instances to scheduler workers through a NATS queue group, so heavy meetings are
+// computed away from latency-sensitive requests.
+package precompute
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"meetsync/internal/events"
+	"meetsync/pkg/logs"
+)
+
+const (
+	// JobType is the type of the events carrying jobs, published to the subject derived
+	// from it like other events
+	JobType events.Type = "recommendations.precompute"
+	// workerGroup is the queue group workers subscribe with, so NATS delivers each job to
+	// a single worker
+	workerGroup = "meetsync-workers"
+	// dialTimeout caps how long connecting to the NATS server can take
+	dialTimeout = 5 * time.Second
+	// reconnectDelay is how long workers wait before reconnecting to the NATS server
+	reconnectDelay = 2 * time.Second
+	// pendingJobs caps the jobs received but not handled yet; the connection keeps
+	// answering pings while jobs are handled
+	pendingJobs = 64
+)
+
+// Job asks for the recommendations of a meeting to be recomputed after a change made at
+// RequestedAt
+type Job struct {
+	MeetingID   string
+	RequestedAt time.Time
+}
+
+// Queue hands jobs to workers
+type Queue interface {
+	Enqueue(meetingID string) error
+}
+
+// Consumer delivers jobs to a worker until ctx is done
+type Consumer interface {
+	Consume(ctx context.Context, handle func(Job)) error
+}
+
+// NATSQueue is a Queue and Consumer on a NATS subject. NATS delivers jobs at most once:
+// jobs published while no worker is connected are lost, and so are the jobs a worker
+// received but had not handled when it stopped.
+type NATSQueue struct {
+	address   string
+	subject   string
+	publisher *events.NATSPublisher
+}
+
+var (
+	_ Queue    = (*NATSQueue)(nil) // Verify NATSQueue implements Queue
+	_ Consumer = (*NATSQueue)(nil) // Verify NATSQueue implements Consumer
+)
+
+// NewNATSQueue creates a NATSQueue for a URL such as nats://localhost:4222, publishing
+// jobs under subjectPrefix
+func NewNATSQueue(rawURL, subjectPrefix string) *NATSQueue {
+	address := rawURL
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		address = u.Host
+	}
+	return &NATSQueue{
+		address:   address,
+		subject:   events.Subject(subjectPrefix, JobType),
+		publisher: events.NewNATSPublisher(rawURL, subjectPrefix),
+	}
+}
+
+// Enqueue publishes a job for the recommendations of a meeting
+func (q *NATSQueue) Enqueue(meetingID string) error {
+	return q.publisher.Publish(events.New(JobType, meetingID, nil))
+}
+
+// Close closes the connection jobs are published on
+func (q *NATSQueue) Close() error {
+	return q.publisher.Close()
+}
+
+// Consume subscribes to jobs in the worker queue group and calls handle for each one, one
+// at a time, until ctx is done. It reconnects whenever the connection to the server is lost.
+func (q *NATSQueue) Consume(ctx context.Context, handle func(Job)) error {
+	jobs := make(chan Job, pendingJobs)
+	handled := make(chan struct{})
+	go func() {
+		defer close(handled)
+		for job := range jobs {
+			handle(job)
+		}
+	}()
+	defer func() {
+		close(jobs)
+		<-handled
+	}()
+
+	for {
+		err := q.subscribe(ctx, jobs)
+		if ctx.Err() != nil {
+			return nil
+		}
+		logs.Warn("Lost the precompute queue at %s, reconnecting in %s: %v", q.address, reconnectDelay, err)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+// subscribe connects to the server and forwards the jobs it delivers to jobs until the
+// connection fails or ctx is done
+func (q *NATSQueue) subscribe(ctx context.Context, jobs chan<- Job) error {
+	conn, err := net.DialTimeout("tcp", q.address, dialTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	reader := bufio.NewReader(conn)
+	conn.SetReadDeadline(time.Now().Add(dialTimeout))
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, "INFO") {
+		return fmt.Errorf("unexpected NATS greeting: %q", strings.TrimSpace(line))
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	if _, err := fmt.Fprintf(conn, "CONNECT {\"verbose\":false,\"pedantic\":false,\"name\":\"meetsync-worker\"}\r\nSUB %s %s 1\r\n", q.subject, workerGroup); err != nil {
+		return err
+	}
+	logs.Info("Consuming precompute jobs from %s at %s", q.subject, q.address)
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		switch {
+		case strings.HasPrefix(line, "PING"):
+			if _, err := fmt.Fprint(conn, "PONG\r\n"); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("NATS error: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		case strings.HasPrefix(line, "MSG "):
+			// MSG <subject> <sid> [reply-to] <size>, followed by the payload
+			fields := strings.Fields(line)
+			size, err := strconv.Atoi(fields[len(fields)-1])
+			if err != nil || size < 0 {
+				return fmt.Errorf("invalid NATS message header: %q", strings.TrimSpace(line))
+			}
+			payload := make([]byte, size+2) // the payload is followed by CRLF
+			if _, err := io.ReadFull(reader, payload); err != nil {
+				return err
+			}
+
+			var event events.Event
+			if err := json.Unmarshal(payload[:size], &event); err != nil || event.MeetingID == "" {
+				logs.Warn("Skipping invalid precompute job on %s", q.subject)
+				continue
+			}
+			select {
+			case jobs <- Job{MeetingID: event.MeetingID, RequestedAt: event.OccurredAt}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
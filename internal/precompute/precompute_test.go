@@ -0,0 +1,82 @@
+package precompute
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"meetsync/internal/events"
+)
+
+func TestNATSQueue_Consume(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	requestedAt := time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC)
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		io.WriteString(conn, "INFO {\"server_id\":\"test\"}\r\n")
+		reader := bufio.NewReader(conn)
+		var lines []string
+		for len(lines) < 2 {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			lines = append(lines, strings.TrimRight(line, "\r\n"))
+		}
+
+		// A ping, an invalid job and a job
+		payload, _ := json.Marshal(events.Event{Type: JobType, MeetingID: "meeting-1", OccurredAt: requestedAt})
+		fmt.Fprintf(conn, "PING\r\nMSG meetsync.recommendations.precompute 1 2\r\n{}\r\nMSG meetsync.recommendations.precompute 1 %d\r\n%s\r\n", len(payload), payload)
+		line, _ := reader.ReadString('\n')
+		received <- append(lines, strings.TrimRight(line, "\r\n"))
+		io.Copy(io.Discard, conn)
+	}()
+
+	queue := NewNATSQueue("nats://"+listener.Addr().String(), "meetsync")
+	ctx, cancel := context.WithCancel(context.Background())
+	jobs := make(chan Job, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- queue.Consume(ctx, func(job Job) { jobs <- job })
+	}()
+
+	select {
+	case job := <-jobs:
+		assert.Equal(t, "meeting-1", job.MeetingID)
+		assert.True(t, requestedAt.Equal(job.RequestedAt))
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a job")
+	}
+	lines := <-received
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "CONNECT "))
+	assert.Equal(t, "SUB meetsync.recommendations.precompute meetsync-workers 1", lines[1])
+	assert.Equal(t, "PONG", lines[2])
+
+	// Consuming stops with the context
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the consumer to stop")
+	}
+}
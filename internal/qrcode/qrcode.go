@@ -0,0 +1,58 @@
+// Package qrcode renders QR codes as PNG or SVG images, such as for links projected in
+// a meeting room.
+package qrcode
+
+import (
+	"fmt"
+	"strings"
+
+	qr "github.com/skip2/go-qrcode"
+)
+
+const (
+	// FormatPNG renders a PNG image
+	FormatPNG = "png"
+	// FormatSVG renders an SVG image that scales without blurring
+	FormatSVG = "svg"
+)
+
+// ContentType returns the media type of images rendered in format
+func ContentType(format string) string {
+	if format == FormatSVG {
+		return "image/svg+xml"
+	}
+	return "image/png"
+}
+
+// Render encodes content as a QR code image in format, size pixels wide for PNG
+func Render(content, format string, size int) ([]byte, error) {
+	code, err := qr.New(content, qr.Medium)
+	if err != nil {
+		return nil, fmt.Errorf("qrcode: failed to encode: %w", err)
+	}
+
+	switch format {
+	case FormatPNG:
+		return code.PNG(size)
+	case FormatSVG:
+		return svg(code.Bitmap()), nil
+	default:
+		return nil, fmt.Errorf("qrcode: unknown format %q", format)
+	}
+}
+
+// svg draws every dark module of bitmap, quiet zone included, as a unit square
+func svg(bitmap [][]bool) []byte {
+	var path strings.Builder
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&path, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+
+	n := len(bitmap)
+	return []byte(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+
+		`<rect width="%d" height="%d" fill="#fff"/><path d="%s" fill="#000"/></svg>`, n, n, n, n, path.String()))
+}
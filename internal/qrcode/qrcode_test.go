@@ -0,0 +1,31 @@
+package qrcode
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	const link = "https://meetsync.example.com/poll/3f2b8c1e"
+
+	image, err := Render(link, FormatPNG, 256)
+	require.NoError(t, err)
+	decoded, err := png.Decode(bytes.NewReader(image))
+	require.NoError(t, err)
+	assert.Equal(t, 256, decoded.Bounds().Dx())
+	assert.Equal(t, "image/png", ContentType(FormatPNG))
+
+	image, err = Render(link, FormatSVG, 256)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(image), "<svg "))
+	assert.Contains(t, string(image), "<path d=\"M")
+	assert.Equal(t, "image/svg+xml", ContentType(FormatSVG))
+
+	_, err = Render(link, "gif", 256)
+	assert.Error(t, err)
+}
@@ -0,0 +1,116 @@
+// Package quota enforces the usage limits of the organization running the service,
+// such as how many meetings can be created per month, and reports usage against them.
+package quota
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"meetsync/pkg/errors"
+)
+
+// monthLayout formats the calendar months meetings are counted in
+const monthLayout = "2006-01"
+
+// Limits are the quotas of the organization; zero means unlimited
+type Limits struct {
+	MeetingsPerMonth       int `json:"meetingsPerMonth"`
+	ParticipantsPerMeeting int `json:"participantsPerMeeting"`
+}
+
+// Counter is the consumption of a single quota; a Limit of zero means unlimited
+type Counter struct {
+	Used  int `json:"used"`
+	Limit int `json:"limit"`
+}
+
+// Usage is the consumption of every quota in the current month
+type Usage struct {
+	Period                 string  `json:"period"` // "2006-01", in UTC
+	Meetings               Counter `json:"meetings"`
+	ParticipantsPerMeeting Counter `json:"participantsPerMeeting"` // Used is the largest meeting created this month
+}
+
+// Tracker counts the meetings created per month and enforces Limits. A nil Tracker
+// enforces nothing.
+type Tracker struct {
+	limits Limits
+	now    func() time.Time
+
+	mu              sync.Mutex
+	meetings        map[string]int // created meetings by month
+	maxParticipants map[string]int // largest meeting by month
+}
+
+// NewTracker creates a Tracker enforcing limits
+func NewTracker(limits Limits) *Tracker {
+	return &Tracker{
+		limits:          limits,
+		now:             time.Now,
+		meetings:        make(map[string]int),
+		maxParticipants: make(map[string]int),
+	}
+}
+
+// CheckParticipants returns a quota error when a meeting with count participants
+// exceeds the participants quota
+func (t *Tracker) CheckParticipants(count int) error {
+	if t == nil || t.limits.ParticipantsPerMeeting == 0 || count <= t.limits.ParticipantsPerMeeting {
+		return nil
+	}
+	return errors.NewQuotaExceededError("Participant quota exceeded",
+		fmt.Sprintf("meetings can have at most %d participants", t.limits.ParticipantsPerMeeting))
+}
+
+// ReserveMeeting counts a meeting with participants toward the current month, or
+// returns a quota error when the month's quota is used up. Callers release the
+// reservation with the returned function when the meeting is not created after all.
+func (t *Tracker) ReserveMeeting(participants int) (release func(), err error) {
+	if t == nil {
+		return func() {}, nil
+	}
+	if err := t.CheckParticipants(participants); err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	month := t.now().UTC().Format(monthLayout)
+	if t.limits.MeetingsPerMonth > 0 && t.meetings[month] >= t.limits.MeetingsPerMonth {
+		return nil, errors.NewQuotaExceededError("Meeting quota exceeded",
+			fmt.Sprintf("at most %d meetings can be created per month", t.limits.MeetingsPerMonth))
+	}
+	t.meetings[month]++
+	previousMax := t.maxParticipants[month]
+	if participants > previousMax {
+		t.maxParticipants[month] = participants
+	}
+
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.meetings[month]--
+		if t.maxParticipants[month] == participants {
+			t.maxParticipants[month] = previousMax
+		}
+	}, nil
+}
+
+// Usage returns the consumption of every quota in the current month
+func (t *Tracker) Usage() Usage {
+	if t == nil {
+		return NewTracker(Limits{}).Usage()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	month := t.now().UTC().Format(monthLayout)
+	return Usage{
+		Period:                 month,
+		Meetings:               Counter{Used: t.meetings[month], Limit: t.limits.MeetingsPerMonth},
+		ParticipantsPerMeeting: Counter{Used: t.maxParticipants[month], Limit: t.limits.ParticipantsPerMeeting},
+	}
+}
@@ -0,0 +1,55 @@
+package quota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"meetsync/pkg/errors"
+)
+
+func TestTracker(t *testing.T) {
+	now := time.Date(2024, 3, 31, 23, 0, 0, 0, time.UTC)
+	tracker := NewTracker(Limits{MeetingsPerMonth: 2, ParticipantsPerMeeting: 3})
+	tracker.now = func() time.Time { return now }
+
+	_, err := tracker.ReserveMeeting(4)
+	assert.ErrorIs(t, err, errors.ErrQuotaExceeded)
+	assert.Error(t, tracker.CheckParticipants(4))
+	assert.NoError(t, tracker.CheckParticipants(3))
+
+	_, err = tracker.ReserveMeeting(2)
+	assert.NoError(t, err)
+	release, err := tracker.ReserveMeeting(3)
+	assert.NoError(t, err)
+	_, err = tracker.ReserveMeeting(1)
+	assert.ErrorIs(t, err, errors.ErrQuotaExceeded)
+	assert.Equal(t, Usage{
+		Period:                 "2024-03",
+		Meetings:               Counter{Used: 2, Limit: 2},
+		ParticipantsPerMeeting: Counter{Used: 3, Limit: 3},
+	}, tracker.Usage())
+
+	// Released reservations no longer count
+	release()
+	assert.Equal(t, Counter{Used: 1, Limit: 2}, tracker.Usage().Meetings)
+	assert.Equal(t, Counter{Used: 2, Limit: 3}, tracker.Usage().ParticipantsPerMeeting)
+
+	// The meeting quota starts over every month
+	now = now.Add(2 * time.Hour)
+	_, err = tracker.ReserveMeeting(1)
+	assert.NoError(t, err)
+	_, err = tracker.ReserveMeeting(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-04", tracker.Usage().Period)
+}
+
+func TestNilTracker(t *testing.T) {
+	var tracker *Tracker
+	release, err := tracker.ReserveMeeting(1000)
+	assert.NoError(t, err)
+	release()
+	assert.NoError(t, tracker.CheckParticipants(1000))
+	assert.Equal(t, Counter{}, tracker.Usage().Meetings)
+}
@@ -0,0 +1,58 @@
+package reporting
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// HTTPReporter posts reports as JSON to a URL, such as an internal error collector
+type HTTPReporter struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPReporter creates an HTTPReporter posting to url
+func NewHTTPReporter(url string) *HTTPReporter {
+	return &HTTPReporter{
+		url:    url,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Report posts the report
+func (r *HTTPReporter) Report(report Report) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("reporting: failed to encode report: %w", err)
+	}
+	return post(r.client, r.url, body, nil)
+}
+
+// Close implements Reporter
+func (r *HTTPReporter) Close() error { return nil }
+
+// post sends a JSON body with extra headers and checks for a successful status
+func post(client *http.Client, url string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("reporting: failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("reporting: failed to send report: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("reporting: %s returned status %d", url, resp.StatusCode)
+	}
+	return nil
+}
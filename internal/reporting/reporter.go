@@ -0,0 +1,135 @@
+// Package reporting sends internal errors and panics to an error tracker, such as
+// Sentry or a generic HTTP endpoint, together with the request they happened in.
+package reporting
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"meetsync/pkg/logs"
+)
+
+const (
+	// BackendNone disables error reporting
+	BackendNone = "none"
+	// BackendHTTP posts reports as JSON to a URL
+	BackendHTTP = "http"
+	// BackendSentry sends reports to the Sentry project identified by a DSN
+	BackendSentry = "sentry"
+)
+
+// Report describes an internal error or panic and the request it happened in.
+// Request data is scrubbed of credentials before it is reported.
+type Report struct {
+	Time      time.Time         `json:"time"`
+	RequestID string            `json:"requestId"`
+	Method    string            `json:"method"`
+	Route     string            `json:"route"` // path with IDs replaced by {id}
+	Path      string            `json:"path"`
+	Query     map[string]string `json:"query,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Message   string            `json:"message"`
+	Error     string            `json:"error"`
+	Panic     bool              `json:"panic"`
+	Stack     string            `json:"stack,omitempty"`
+}
+
+// Reporter defines the interface for sending error reports
+type Reporter interface {
+	Report(report Report) error
+	Close() error
+}
+
+// NewReporter creates a Reporter for the given backend. Reports are delivered
+// asynchronously so a slow or unavailable tracker never delays API responses.
+func NewReporter(backend, url string) (Reporter, error) {
+	switch strings.ToLower(backend) {
+	case "", BackendNone:
+		return NoopReporter{}, nil
+	case BackendHTTP:
+		if url == "" {
+			return nil, fmt.Errorf("reporting: HTTP backend requires a URL")
+		}
+		return NewAsyncReporter(NewHTTPReporter(url), defaultBufferSize), nil
+	case BackendSentry:
+		reporter, err := NewSentryReporter(url)
+		if err != nil {
+			return nil, err
+		}
+		return NewAsyncReporter(reporter, defaultBufferSize), nil
+	default:
+		return nil, fmt.Errorf("reporting: unknown backend %q", backend)
+	}
+}
+
+// NoopReporter discards all reports
+type NoopReporter struct{}
+
+// Report implements Reporter
+func (NoopReporter) Report(report Report) error { return nil }
+
+// Close implements Reporter
+func (NoopReporter) Close() error { return nil }
+
+const defaultBufferSize = 256
+
+// AsyncReporter buffers reports and sends them from a background goroutine
+type AsyncReporter struct {
+	next   Reporter
+	queue  chan Report
+	done   chan struct{}
+	once   sync.Once
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewAsyncReporter wraps a Reporter so that Report never blocks the caller
+func NewAsyncReporter(next Reporter, bufferSize int) *AsyncReporter {
+	r := &AsyncReporter{
+		next:  next,
+		queue: make(chan Report, bufferSize),
+		done:  make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// Report enqueues the report, dropping it if the buffer is full
+func (r *AsyncReporter) Report(report Report) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.closed {
+		return fmt.Errorf("reporting: reporter is closed")
+	}
+
+	select {
+	case r.queue <- report:
+		return nil
+	default:
+		return fmt.Errorf("reporting: buffer full, dropping report for request %s", report.RequestID)
+	}
+}
+
+// Close flushes buffered reports and closes the underlying reporter
+func (r *AsyncReporter) Close() error {
+	r.once.Do(func() {
+		r.mu.Lock()
+		r.closed = true
+		close(r.queue)
+		r.mu.Unlock()
+	})
+	<-r.done
+	return r.next.Close()
+}
+
+func (r *AsyncReporter) run() {
+	defer close(r.done)
+	for report := range r.queue {
+		if err := r.next.Report(report); err != nil {
+			logs.Error("Failed to report error for request %s: %v", report.RequestID, err)
+		}
+	}
+}
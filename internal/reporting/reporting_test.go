@@ -0,0 +1,115 @@
+package reporting
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReporter(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend string
+		url     string
+		wantErr bool
+	}{
+		{name: "none", backend: "none"},
+		{name: "empty backend", backend: ""},
+		{name: "http", backend: "http", url: "http://localhost:9000/errors"},
+		{name: "sentry", backend: "sentry", url: "https://public@o1.ingest.sentry.io/42"},
+		{name: "http without url", backend: "http", wantErr: true},
+		{name: "sentry without project", backend: "sentry", url: "https://public@o1.ingest.sentry.io/", wantErr: true},
+		{name: "sentry without key", backend: "sentry", url: "https://o1.ingest.sentry.io/42", wantErr: true},
+		{name: "unknown backend", backend: "rollbar", url: "https://api.rollbar.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reporter, err := NewReporter(tt.backend, tt.url)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, reporter.Close())
+		})
+	}
+}
+
+func TestNewRequestReport(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/meetings/0b6e5a4c-1f4e-4c55-9f43-2d7f5b1a9c3e/tags?access_token=abc&limit=10", strings.NewReader(`{"password":"hunter2"}`))
+	req.Header.Set("Authorization", "Bearer mst_secret")
+	req.Header.Set("X-Admin-Key", "admin-secret")
+	req.Header.Set("User-Agent", "test")
+
+	report := NewRequestReport(req, "req-1")
+
+	assert.Equal(t, "req-1", report.RequestID)
+	assert.Equal(t, "/api/meetings/{id}/tags", report.Route)
+	assert.Equal(t, scrubbed, report.Query["access_token"])
+	assert.Equal(t, "10", report.Query["limit"])
+	assert.Equal(t, scrubbed, report.Headers["Authorization"])
+	assert.Equal(t, scrubbed, report.Headers["X-Admin-Key"])
+	assert.Equal(t, "test", report.Headers["User-Agent"])
+
+	encoded, err := json.Marshal(report)
+	require.NoError(t, err)
+	for _, secret := range []string{"mst_secret", "admin-secret", "abc", "hunter2"} {
+		assert.NotContains(t, string(encoded), secret)
+	}
+}
+
+func TestHTTPReporter_Report(t *testing.T) {
+	var got Report
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	reporter := NewHTTPReporter(server.URL)
+	require.NoError(t, reporter.Report(Report{RequestID: "req-1", Message: "Failed to save", Error: "disk full"}))
+	assert.Equal(t, "req-1", got.RequestID)
+	assert.Equal(t, "disk full", got.Error)
+}
+
+func TestSentryReporter_Report(t *testing.T) {
+	var (
+		gotPath  string
+		gotAuth  string
+		gotEvent sentryEvent
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("X-Sentry-Auth")
+		json.NewDecoder(r.Body).Decode(&gotEvent)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	dsn := strings.Replace(server.URL, "http://", "http://public@", 1) + "/42"
+	reporter, err := NewSentryReporter(dsn)
+	require.NoError(t, err)
+	require.NoError(t, reporter.Report(Report{
+		RequestID: "req-1",
+		Method:    http.MethodGet,
+		Route:     "/api/meetings/{id}",
+		Message:   "Panic",
+		Error:     "panic: nil map",
+		Panic:     true,
+		Stack:     "goroutine 1",
+	}))
+
+	assert.Equal(t, "/api/42/store/", gotPath)
+	assert.Contains(t, gotAuth, "sentry_key=public")
+	assert.Equal(t, "fatal", gotEvent.Level)
+	assert.Equal(t, "req-1", gotEvent.Tags["request_id"])
+	assert.Equal(t, "GET /api/meetings/{id}", gotEvent.Culprit)
+	assert.Equal(t, "goroutine 1", gotEvent.Extra["stack"])
+	assert.Len(t, gotEvent.EventID, 32)
+}
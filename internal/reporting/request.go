@@ -0,0 +1,83 @@
+package reporting
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// scrubbed replaces the values of sensitive headers and query parameters
+const scrubbed = "[scrubbed]"
+
+// sensitiveHeaders are headers carrying credentials
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"X-Admin-Key":         true,
+}
+
+// sensitiveQueryWords mark query parameters whose names contain them as credentials
+var sensitiveQueryWords = []string{"token", "secret", "password", "key", "auth"}
+
+// idSegment matches path segments holding generated IDs
+var idSegment = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// NewRequestReport creates a report for a failure while handling r, with credentials
+// scrubbed from its headers and query parameters. Request bodies are never reported.
+func NewRequestReport(r *http.Request, requestID string) Report {
+	report := Report{
+		Time:      time.Now(),
+		RequestID: requestID,
+		Method:    r.Method,
+		Route:     Route(r.URL.Path),
+		Path:      r.URL.Path,
+	}
+
+	if query := r.URL.Query(); len(query) > 0 {
+		report.Query = make(map[string]string, len(query))
+		for name, values := range query {
+			value := strings.Join(values, ",")
+			if isSensitiveQueryParam(name) {
+				value = scrubbed
+			}
+			report.Query[name] = value
+		}
+	}
+
+	if len(r.Header) > 0 {
+		report.Headers = make(map[string]string, len(r.Header))
+		for name, values := range r.Header {
+			value := strings.Join(values, ",")
+			if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+				value = scrubbed
+			}
+			report.Headers[name] = value
+		}
+	}
+	return report
+}
+
+// Route returns path with generated IDs replaced by {id}, so reports of the same
+// endpoint group together
+func Route(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if idSegment.MatchString(segment) {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func isSensitiveQueryParam(name string) bool {
+	name = strings.ToLower(name)
+	for _, word := range sensitiveQueryWords {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package reporting
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// sentryClient identifies MeetSync to Sentry
+const sentryClient = "meetsync/1.0"
+
+// SentryReporter sends reports to Sentry's store endpoint
+type SentryReporter struct {
+	storeURL string
+	auth     string
+	client   *http.Client
+}
+
+// NewSentryReporter creates a SentryReporter for a DSN such as
+// https://<key>@o0.ingest.sentry.io/<project>
+func NewSentryReporter(dsn string) (*SentryReporter, error) {
+	u, err := url.Parse(dsn)
+	if err != nil || u.User == nil || u.User.Username() == "" || u.Host == "" {
+		return nil, fmt.Errorf("reporting: invalid Sentry DSN")
+	}
+	path := strings.Trim(u.Path, "/")
+	slash := strings.LastIndex(path, "/")
+	prefix, project := "", path
+	if slash >= 0 {
+		prefix, project = "/"+path[:slash], path[slash+1:]
+	}
+	if project == "" {
+		return nil, fmt.Errorf("reporting: Sentry DSN has no project ID")
+	}
+
+	return &SentryReporter{
+		storeURL: fmt.Sprintf("%s://%s%s/api/%s/store/", u.Scheme, u.Host, prefix, project),
+		auth:     fmt.Sprintf("Sentry sentry_version=7, sentry_client=%s, sentry_key=%s", sentryClient, u.User.Username()),
+		client:   &http.Client{Timeout: 5 * time.Second},
+	}, nil
+}
+
+type sentryEvent struct {
+	EventID   string            `json:"event_id"`
+	Timestamp string            `json:"timestamp"`
+	Level     string            `json:"level"`
+	Platform  string            `json:"platform"`
+	Logger    string            `json:"logger"`
+	Message   string            `json:"message"`
+	Culprit   string            `json:"culprit"`
+	Tags      map[string]string `json:"tags"`
+	Request   sentryRequest     `json:"request"`
+	Extra     map[string]string `json:"extra"`
+}
+
+type sentryRequest struct {
+	Method      string            `json:"method"`
+	URL         string            `json:"url"`
+	QueryString map[string]string `json:"query_string,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+}
+
+// Report sends the report as a Sentry event
+func (r *SentryReporter) Report(report Report) error {
+	level := "error"
+	if report.Panic {
+		level = "fatal"
+	}
+	event := sentryEvent{
+		EventID:   strings.ReplaceAll(uuid.New().String(), "-", ""),
+		Timestamp: report.Time.UTC().Format(time.RFC3339),
+		Level:     level,
+		Platform:  "go",
+		Logger:    "meetsync",
+		Message:   fmt.Sprintf("%s: %s", report.Message, report.Error),
+		Culprit:   report.Method + " " + report.Route,
+		Tags: map[string]string{
+			"request_id": report.RequestID,
+			"route":      report.Route,
+		},
+		Request: sentryRequest{
+			Method:      report.Method,
+			URL:         report.Path,
+			QueryString: report.Query,
+			Headers:     report.Headers,
+		},
+		Extra: map[string]string{},
+	}
+	if report.Stack != "" {
+		event.Extra["stack"] = report.Stack
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("reporting: failed to encode Sentry event: %w", err)
+	}
+	return post(r.client, r.storeURL, body, map[string]string{"X-Sentry-Auth": r.auth})
+}
+
+// Close implements Reporter
+func (r *SentryReporter) Close() error { return nil }
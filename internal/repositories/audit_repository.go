@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"meetsync/internal/models"
+	"meetsync/internal/pagination"
+)
+
+// AuditRepository defines the interface for audit log data access
+type AuditRepository interface {
+	Append(entry models.AuditEntry) (models.AuditEntry, error)
+	List(after *pagination.Cursor, limit int) ([]models.AuditEntry, error)
+	Snapshot() ([]models.AuditEntry, error)
+	Restore(entries []models.AuditEntry) error
+}
+
+// InMemoryAuditRepository implements AuditRepository using in-memory storage
+type InMemoryAuditRepository struct {
+	entries []models.AuditEntry
+	mu      sync.RWMutex
+}
+
+// NewInMemoryAuditRepository creates a new InMemoryAuditRepository
+func NewInMemoryAuditRepository() *InMemoryAuditRepository {
+	return &InMemoryAuditRepository{}
+}
+
+func (r *InMemoryAuditRepository) Append(entry models.AuditEntry) (models.AuditEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if entry.ID == "" {
+		entry.ID = uuid.New().String()
+	}
+	if entry.OccurredAt.IsZero() {
+		entry.OccurredAt = time.Now()
+	}
+
+	r.entries = append(r.entries, entry)
+	return entry, nil
+}
+
+// List returns up to limit entries ordered by occurrence time, starting after the cursor
+func (r *InMemoryAuditRepository) List(after *pagination.Cursor, limit int) ([]models.AuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := make([]models.AuditEntry, 0, len(r.entries))
+	for _, entry := range r.entries {
+		if after == nil || after.After(entry.OccurredAt, entry.ID) {
+			entries = append(entries, entry)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return pagination.Less(entries[i].OccurredAt, entries[i].ID, entries[j].OccurredAt, entries[j].ID)
+	})
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// Snapshot returns a copy of every entry in the order they were appended
+func (r *InMemoryAuditRepository) Snapshot() ([]models.AuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := make([]models.AuditEntry, len(r.entries))
+	copy(entries, r.entries)
+	return entries, nil
+}
+
+// Restore replaces every entry with the given ones
+func (r *InMemoryAuditRepository) Restore(entries []models.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = make([]models.AuditEntry, len(entries))
+	copy(r.entries, entries)
+	return nil
+}
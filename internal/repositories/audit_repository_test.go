@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"meetsync/internal/models"
+	"meetsync/internal/pagination"
+)
+
+func TestInMemoryAuditRepository(t *testing.T) {
+	repo := NewInMemoryAuditRepository()
+
+	entries, err := repo.List(nil, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	first, err := repo.Append(models.AuditEntry{Action: models.AuditActionUsersMerged, SubjectID: "user-1"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, first.ID)
+	assert.False(t, first.OccurredAt.IsZero())
+
+	_, err = repo.Append(models.AuditEntry{Action: models.AuditActionUsersMerged, SubjectID: "user-2"})
+	require.NoError(t, err)
+
+	// Entries are listed in the order they were recorded
+	entries, err = repo.List(nil, 10)
+	assert.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "user-1", entries[0].SubjectID)
+	assert.Equal(t, "user-2", entries[1].SubjectID)
+}
+
+func TestInMemoryAuditRepository_KeysetPagination(t *testing.T) {
+	repo := NewInMemoryAuditRepository()
+	for i := 0; i < 5; i++ {
+		_, err := repo.Append(models.AuditEntry{Action: models.AuditActionUsersMerged})
+		require.NoError(t, err)
+	}
+
+	firstPage, err := repo.List(nil, 2)
+	require.NoError(t, err)
+	require.Len(t, firstPage, 2)
+
+	last := firstPage[1]
+	secondPage, err := repo.List(&pagination.Cursor{Timestamp: last.OccurredAt, ID: last.ID}, 10)
+	require.NoError(t, err)
+	assert.Len(t, secondPage, 3)
+	for _, entry := range secondPage {
+		assert.NotEqual(t, firstPage[0].ID, entry.ID)
+		assert.NotEqual(t, firstPage[1].ID, entry.ID)
+	}
+}
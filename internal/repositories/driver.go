@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DriverMemory is the name of the storage driver keeping all data in process memory
+const DriverMemory = "memory"
+
+// Storage is the set of repositories opened by a storage driver
+type Storage struct {
+	Meetings MeetingRepository
+	Users    UserRepository
+	Audit    AuditRepository // audit log; nil keeps it in process memory
+	Shared   bool            // whether replicas opening the same storage see each other's writes
+	DB       *sql.DB         // SQL database the repositories run on, migrated at startup; nil for other backends
+	Close    func() error    // releases connections held by the repositories; may be nil
+}
+
+// Driver opens the repositories of a storage backend, such as DynamoDB or Firestore.
+// Drivers register themselves under a name with Register, typically from the init
+// function of their package, so that a blank import of the package in the main
+// package is all it takes to make the backend selectable by configuration.
+type Driver interface {
+	// Open opens the repositories of the storage identified by dsn, whose format is
+	// up to the driver
+	Open(dsn string) (Storage, error)
+}
+
+// DriverFunc adapts a function to a Driver
+type DriverFunc func(dsn string) (Storage, error)
+
+// Open implements Driver
+func (f DriverFunc) Open(dsn string) (Storage, error) { return f(dsn) }
+
+var (
+	driversMu sync.RWMutex
+	drivers   = map[string]Driver{}
+)
+
+func init() {
+	Register(DriverMemory, DriverFunc(func(string) (Storage, error) {
+		return Storage{
+			Meetings: NewInMemoryMeetingRepository(),
+			Users:    NewInMemoryUserRepository(),
+		}, nil
+	}))
+}
+
+// Register makes a storage driver available under name. Like database/sql drivers, it
+// panics if driver is nil or a driver is already registered under name.
+func Register(name string, driver Driver) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	name = strings.ToLower(name)
+	if driver == nil {
+		panic("repositories: Register driver is nil")
+	}
+	if _, dup := drivers[name]; dup {
+		panic("repositories: Register called twice for driver " + name)
+	}
+	drivers[name] = driver
+}
+
+// Drivers returns the sorted names of the registered storage drivers
+func Drivers() []string {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	names := make([]string, 0, len(drivers))
+	for name := range drivers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Open opens the repositories of the storage driver registered under name. An empty
+// name selects the in-memory driver.
+func Open(name, dsn string) (Storage, error) {
+	if name == "" {
+		name = DriverMemory
+	}
+	driversMu.RLock()
+	driver, ok := drivers[strings.ToLower(name)]
+	driversMu.RUnlock()
+	if !ok {
+		return Storage{}, fmt.Errorf("repositories: unknown storage driver %q (registered: %s)", name, strings.Join(Drivers(), ", "))
+	}
+
+	storage, err := driver.Open(dsn)
+	if err != nil {
+		return Storage{}, fmt.Errorf("repositories: failed to open %s storage: %w", name, err)
+	}
+	if storage.Meetings == nil || storage.Users == nil {
+		return Storage{}, fmt.Errorf("repositories: %s storage driver did not open every repository", name)
+	}
+	return storage, nil
+}
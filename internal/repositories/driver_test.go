@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageDrivers(t *testing.T) {
+	// The in-memory driver is registered by default and selected by an empty name
+	storage, err := Open("", "")
+	require.NoError(t, err)
+	assert.IsType(t, &InMemoryMeetingRepository{}, storage.Meetings)
+	assert.IsType(t, &InMemoryUserRepository{}, storage.Users)
+	assert.False(t, storage.Shared)
+
+	// Third-party drivers are selected by the name they registered under
+	var opened string
+	Register("test-shared", DriverFunc(func(dsn string) (Storage, error) {
+		opened = dsn
+		return Storage{Meetings: NewInMemoryMeetingRepository(), Users: NewInMemoryUserRepository(), Shared: true}, nil
+	}))
+	storage, err = Open("Test-Shared", "shared://table")
+	require.NoError(t, err)
+	assert.True(t, storage.Shared)
+	assert.Equal(t, "shared://table", opened)
+	assert.Contains(t, Drivers(), "test-shared")
+	assert.Contains(t, Drivers(), DriverPostgres)
+
+	// Registering a name twice or a nil driver panics
+	assert.Panics(t, func() { Register("test-shared", DriverFunc(nil)) })
+	assert.Panics(t, func() { Register("test-nil", nil) })
+
+	// Unknown drivers, failures and incomplete storage are reported
+	_, err = Open("dynamodb", "")
+	assert.ErrorContains(t, err, `unknown storage driver "dynamodb"`)
+	Register("test-failing", DriverFunc(func(string) (Storage, error) {
+		return Storage{}, fmt.Errorf("table not found")
+	}))
+	_, err = Open("test-failing", "")
+	assert.ErrorContains(t, err, "table not found")
+	Register("test-incomplete", DriverFunc(func(string) (Storage, error) {
+		return Storage{Meetings: NewInMemoryMeetingRepository()}, nil
+	}))
+	_, err = Open("test-incomplete", "")
+	assert.Error(t, err)
+}
@@ -1,12 +1,14 @@
 package repositories
 
 import (
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 
 	"meetsync/internal/models"
+	"meetsync/internal/pagination"
 	"meetsync/pkg/errors"
 )
 
@@ -14,28 +16,108 @@ import (
 type MeetingRepository interface {
 	CreateMeeting(meeting models.Meeting) (models.Meeting, error)
 	GetMeetingByID(id string) (models.Meeting, error)
+	GetMeetingByExternalID(externalID string) (models.Meeting, error)
+	ListMeetings(after *pagination.Cursor, limit int, filter models.MeetingFilter) ([]models.Meeting, error)
 	UpdateMeeting(meeting models.Meeting) (models.Meeting, error)
 	DeleteMeeting(id string) error
+	SearchMeetings(query string, limit int) ([]models.SearchResult, error)
 	CreateAvailability(availability models.Availability) (models.Availability, error)
 	GetAvailability(userID, meetingID string) (models.Availability, error)
+	GetAvailabilityByID(id string) (models.Availability, error)
 	UpdateAvailability(availability models.Availability) (models.Availability, error)
 	DeleteAvailability(id string) error
 	GetMeetingAvailabilities(meetingID string) ([]models.Availability, error)
+	GetParticipantAvailabilities(participantID string) ([]models.Availability, error)
 	GetAllAvailabilities() []models.Availability
+	AddTimelineEvent(event models.TimelineEvent) (models.TimelineEvent, error)
+	GetTimeline(meetingID string) ([]models.TimelineEvent, error)
+	ReassignUser(fromUserID string, to models.User) (models.UserReassignment, error)
+	SaveRecommendations(set models.RecommendationSet) error
+	GetRecommendations(meetingID string) (models.RecommendationSet, error)
+	Snapshot() (models.MeetingStoreSnapshot, error)
+	Restore(snapshot models.MeetingStoreSnapshot) error
 }
 
 // InMemoryMeetingRepository implements MeetingRepository using in-memory storage
 type InMemoryMeetingRepository struct {
-	meetings       map[string]models.Meeting
-	availabilities map[string]models.Availability
-	mu             sync.RWMutex
+	meetings        map[string]models.Meeting
+	availabilities  map[string]models.Availability
+	timelines       map[string][]models.TimelineEvent
+	recommendations map[string]models.RecommendationSet
+	mu              sync.RWMutex
+
+	// meetingsByExternalID maps the external IDs of synced meetings to their IDs, kept in sync under mu
+	meetingsByExternalID map[string]string
+
+	// Secondary indexes over availabilities, kept in sync under mu
+	availabilitiesByMeeting     map[string]map[string]struct{}
+	availabilitiesByParticipant map[string]map[string]struct{}
+	availabilityByParticipation map[participationKey]string
+}
+
+// participationKey identifies the availability of one participant for one meeting
+type participationKey struct {
+	meetingID     string
+	participantID string
 }
 
 // NewInMemoryMeetingRepository creates a new InMemoryMeetingRepository
 func NewInMemoryMeetingRepository() *InMemoryMeetingRepository {
 	return &InMemoryMeetingRepository{
-		meetings:       make(map[string]models.Meeting),
-		availabilities: make(map[string]models.Availability),
+		meetings:        make(map[string]models.Meeting),
+		availabilities:  make(map[string]models.Availability),
+		timelines:       make(map[string][]models.TimelineEvent),
+		recommendations: make(map[string]models.RecommendationSet),
+
+		meetingsByExternalID:        make(map[string]string),
+		availabilitiesByMeeting:     make(map[string]map[string]struct{}),
+		availabilitiesByParticipant: make(map[string]map[string]struct{}),
+		availabilityByParticipation: make(map[participationKey]string),
+	}
+}
+
+// indexAvailability stores an availability and adds it to the secondary indexes.
+// The caller must hold the write lock.
+func (r *InMemoryMeetingRepository) indexAvailability(availability models.Availability) {
+	if previous, exists := r.availabilities[availability.ID]; exists {
+		r.unindexAvailability(previous)
+	}
+
+	r.availabilities[availability.ID] = availability
+	ids, exists := r.availabilitiesByMeeting[availability.MeetingID]
+	if !exists {
+		ids = make(map[string]struct{})
+		r.availabilitiesByMeeting[availability.MeetingID] = ids
+	}
+	ids[availability.ID] = struct{}{}
+	ids, exists = r.availabilitiesByParticipant[availability.ParticipantID]
+	if !exists {
+		ids = make(map[string]struct{})
+		r.availabilitiesByParticipant[availability.ParticipantID] = ids
+	}
+	ids[availability.ID] = struct{}{}
+	r.availabilityByParticipation[participationKey{availability.MeetingID, availability.ParticipantID}] = availability.ID
+}
+
+// unindexAvailability removes an availability from storage and the secondary indexes.
+// The caller must hold the write lock.
+func (r *InMemoryMeetingRepository) unindexAvailability(availability models.Availability) {
+	delete(r.availabilities, availability.ID)
+	if ids, exists := r.availabilitiesByMeeting[availability.MeetingID]; exists {
+		delete(ids, availability.ID)
+		if len(ids) == 0 {
+			delete(r.availabilitiesByMeeting, availability.MeetingID)
+		}
+	}
+	if ids, exists := r.availabilitiesByParticipant[availability.ParticipantID]; exists {
+		delete(ids, availability.ID)
+		if len(ids) == 0 {
+			delete(r.availabilitiesByParticipant, availability.ParticipantID)
+		}
+	}
+	key := participationKey{availability.MeetingID, availability.ParticipantID}
+	if r.availabilityByParticipation[key] == availability.ID {
+		delete(r.availabilityByParticipation, key)
 	}
 }
 
@@ -50,6 +132,9 @@ func (r *InMemoryMeetingRepository) CreateMeeting(meeting models.Meeting) (model
 	if meeting.ID == "" {
 		meeting.ID = uuid.New().String()
 	}
+	if meeting.Status == "" {
+		meeting.Status = models.MeetingStatusPending
+	}
 
 	// Assign IDs to time slots if not already assigned
 	for i := range meeting.ProposedSlots {
@@ -58,6 +143,14 @@ func (r *InMemoryMeetingRepository) CreateMeeting(meeting models.Meeting) (model
 		}
 	}
 
+	// External IDs are unique so that concurrent syncs cannot create a meeting twice
+	if meeting.ExternalID != "" {
+		if _, exists := r.meetingsByExternalID[meeting.ExternalID]; exists {
+			return models.Meeting{}, errors.NewConflictError("A meeting with this external ID already exists")
+		}
+		r.meetingsByExternalID[meeting.ExternalID] = meeting.ID
+	}
+
 	r.meetings[meeting.ID] = meeting
 	return meeting, nil
 }
@@ -73,13 +166,73 @@ func (r *InMemoryMeetingRepository) GetMeetingByID(id string) (models.Meeting, e
 	return meeting, nil
 }
 
+// GetMeetingByExternalID returns the meeting synced from an external calendar under externalID
+func (r *InMemoryMeetingRepository) GetMeetingByExternalID(externalID string) (models.Meeting, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, exists := r.meetingsByExternalID[externalID]
+	if !exists {
+		return models.Meeting{}, errors.NewNotFoundError("Meeting not found")
+	}
+	return r.meetings[id], nil
+}
+
+// ListMeetings returns up to limit meetings matching the filter ordered by creation time, starting after the cursor
+func (r *InMemoryMeetingRepository) ListMeetings(after *pagination.Cursor, limit int, filter models.MeetingFilter) ([]models.Meeting, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	meetings := make([]models.Meeting, 0, len(r.meetings))
+	for _, meeting := range r.meetings {
+		if (after == nil || after.After(meeting.CreatedAt, meeting.ID)) && filter.Matches(meeting) {
+			meetings = append(meetings, meeting)
+		}
+	}
+	sort.Slice(meetings, func(i, j int) bool {
+		return pagination.Less(meetings[i].CreatedAt, meetings[i].ID, meetings[j].CreatedAt, meetings[j].ID)
+	})
+	if len(meetings) > limit {
+		meetings = meetings[:limit]
+	}
+	return meetings, nil
+}
+
+// SearchMeetings returns up to limit meetings matching every word of the query, most relevant first.
+// Meetings are scanned on every search, which is fine for the data sizes kept in memory.
+func (r *InMemoryMeetingRepository) SearchMeetings(query string, limit int) ([]models.SearchResult, error) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return []models.SearchResult{}, nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	results := []models.SearchResult{}
+	for _, meeting := range r.meetings {
+		if score, matchedFields := scoreMeeting(meeting, terms); score > 0 {
+			results = append(results, models.SearchResult{Meeting: meeting, Score: score, MatchedFields: matchedFields})
+		}
+	}
+	rankSearchResults(results)
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
 func (r *InMemoryMeetingRepository) UpdateMeeting(meeting models.Meeting) (models.Meeting, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.meetings[meeting.ID]; !exists {
+	previous, exists := r.meetings[meeting.ID]
+	if !exists {
 		return models.Meeting{}, errors.NewNotFoundError("Meeting not found")
 	}
+	if meeting.ExternalID != previous.ExternalID {
+		return models.Meeting{}, errors.NewValidationError("The external ID of a meeting cannot be changed", "")
+	}
 
 	meeting.UpdatedAt = time.Now()
 	r.meetings[meeting.ID] = meeting
@@ -90,19 +243,25 @@ func (r *InMemoryMeetingRepository) DeleteMeeting(id string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.meetings[id]; !exists {
+	meeting, exists := r.meetings[id]
+	if !exists {
 		return errors.NewNotFoundError("Meeting not found")
 	}
 
 	delete(r.meetings, id)
+	if meeting.ExternalID != "" {
+		delete(r.meetingsByExternalID, meeting.ExternalID)
+	}
 
 	// Delete associated availabilities
-	for availID, avail := range r.availabilities {
-		if avail.MeetingID == id {
-			delete(r.availabilities, availID)
-		}
+	for availID := range r.availabilitiesByMeeting[id] {
+		r.unindexAvailability(r.availabilities[availID])
 	}
 
+	// Delete associated timeline and recommendations
+	delete(r.timelines, id)
+	delete(r.recommendations, id)
+
 	return nil
 }
 
@@ -118,7 +277,7 @@ func (r *InMemoryMeetingRepository) CreateAvailability(availability models.Avail
 		availability.ID = uuid.New().String()
 	}
 
-	r.availabilities[availability.ID] = availability
+	r.indexAvailability(availability)
 	return availability, nil
 }
 
@@ -126,12 +285,22 @@ func (r *InMemoryMeetingRepository) GetAvailability(userID, meetingID string) (m
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	for _, availability := range r.availabilities {
-		if availability.ParticipantID == userID && availability.MeetingID == meetingID {
-			return availability, nil
-		}
+	id, exists := r.availabilityByParticipation[participationKey{meetingID, userID}]
+	if !exists {
+		return models.Availability{}, errors.NewNotFoundError("Availability not found")
+	}
+	return r.availabilities[id], nil
+}
+
+func (r *InMemoryMeetingRepository) GetAvailabilityByID(id string) (models.Availability, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	availability, exists := r.availabilities[id]
+	if !exists {
+		return models.Availability{}, errors.NewNotFoundError("Availability not found")
 	}
-	return models.Availability{}, errors.NewNotFoundError("Availability not found")
+	return availability, nil
 }
 
 func (r *InMemoryMeetingRepository) UpdateAvailability(availability models.Availability) (models.Availability, error) {
@@ -143,7 +312,7 @@ func (r *InMemoryMeetingRepository) UpdateAvailability(availability models.Avail
 	}
 
 	availability.UpdatedAt = time.Now()
-	r.availabilities[availability.ID] = availability
+	r.indexAvailability(availability)
 	return availability, nil
 }
 
@@ -151,11 +320,12 @@ func (r *InMemoryMeetingRepository) DeleteAvailability(id string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.availabilities[id]; !exists {
+	availability, exists := r.availabilities[id]
+	if !exists {
 		return errors.NewNotFoundError("Availability not found")
 	}
 
-	delete(r.availabilities, id)
+	r.unindexAvailability(availability)
 	return nil
 }
 
@@ -164,10 +334,19 @@ func (r *InMemoryMeetingRepository) GetMeetingAvailabilities(meetingID string) (
 	defer r.mu.RUnlock()
 
 	var availabilities []models.Availability
-	for _, availability := range r.availabilities {
-		if availability.MeetingID == meetingID {
-			availabilities = append(availabilities, availability)
-		}
+	for id := range r.availabilitiesByMeeting[meetingID] {
+		availabilities = append(availabilities, r.availabilities[id])
+	}
+	return availabilities, nil
+}
+
+func (r *InMemoryMeetingRepository) GetParticipantAvailabilities(participantID string) ([]models.Availability, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var availabilities []models.Availability
+	for id := range r.availabilitiesByParticipant[participantID] {
+		availabilities = append(availabilities, r.availabilities[id])
 	}
 	return availabilities, nil
 }
@@ -182,3 +361,252 @@ func (r *InMemoryMeetingRepository) GetAllAvailabilities() []models.Availability
 	}
 	return availabilities
 }
+
+func (r *InMemoryMeetingRepository) AddTimelineEvent(event models.TimelineEvent) (models.TimelineEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.meetings[event.MeetingID]; !exists {
+		return models.TimelineEvent{}, errors.NewNotFoundError("Meeting not found")
+	}
+
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	r.timelines[event.MeetingID] = append(r.timelines[event.MeetingID], event)
+	return event, nil
+}
+
+func (r *InMemoryMeetingRepository) GetTimeline(meetingID string) ([]models.TimelineEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, exists := r.meetings[meetingID]; !exists {
+		return nil, errors.NewNotFoundError("Meeting not found")
+	}
+
+	timeline := make([]models.TimelineEvent, len(r.timelines[meetingID]))
+	copy(timeline, r.timelines[meetingID])
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].OccurredAt.Before(timeline[j].OccurredAt)
+	})
+	return timeline, nil
+}
+
+// reassignProposals returns a copy of proposals with those of one participant moved to
+// another, and whether there were any. The stored proposals are left untouched.
+func reassignProposals(proposals []models.Proposal, fromUserID, toUserID string) ([]models.Proposal, bool) {
+	var reassigned []models.Proposal
+	for i, proposal := range proposals {
+		if proposal.ParticipantID != fromUserID {
+			continue
+		}
+		if reassigned == nil {
+			reassigned = append([]models.Proposal(nil), proposals...)
+		}
+		reassigned[i].ParticipantID = toUserID
+	}
+	if reassigned == nil {
+		return proposals, false
+	}
+	return reassigned, true
+}
+
+// ReassignUser moves every organizer role, participation and availability of
+// one user to another in a single step. When both users responded to the same
+// meeting their available slots are combined.
+func (r *InMemoryMeetingRepository) ReassignUser(fromUserID string, to models.User) (models.UserReassignment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := models.UserReassignment{SourceUserID: fromUserID, TargetUserID: to.ID}
+	now := time.Now()
+
+	for id, meeting := range r.meetings {
+		changed := false
+		if meeting.OrganizerID == fromUserID {
+			meeting.OrganizerID = to.ID
+			organizer := to
+			meeting.Organizer = &organizer
+			result.MeetingsOrganized++
+			changed = true
+		}
+
+		participants := make([]models.User, 0, len(meeting.Participants))
+		hasTarget := false
+		for _, participant := range meeting.Participants {
+			if participant.ID == to.ID {
+				hasTarget = true
+			}
+		}
+		for _, participant := range meeting.Participants {
+			if participant.ID != fromUserID {
+				participants = append(participants, participant)
+				continue
+			}
+			result.Participations++
+			changed = true
+			if !hasTarget {
+				participants = append(participants, to)
+				hasTarget = true
+			}
+		}
+
+		if proposals, reassigned := reassignProposals(meeting.Proposals, fromUserID, to.ID); reassigned {
+			meeting.Proposals = proposals
+			changed = true
+		}
+
+		if changed {
+			meeting.Participants = participants
+			meeting.UpdatedAt = now
+			r.meetings[id] = meeting
+			delete(r.recommendations, id)
+		}
+
+		for i, event := range r.timelines[id] {
+			if event.UserID == fromUserID {
+				r.timelines[id][i].UserID = to.ID
+			}
+		}
+	}
+
+	var moved []models.Availability
+	for _, availability := range r.availabilities {
+		if availability.ParticipantID == fromUserID {
+			moved = append(moved, availability)
+		}
+	}
+
+	for _, availability := range moved {
+		result.Availabilities++
+		delete(r.recommendations, availability.MeetingID)
+
+		existingID, exists := r.availabilityByParticipation[participationKey{availability.MeetingID, to.ID}]
+		if !exists {
+			availability.ParticipantID = to.ID
+			availability.Participant = nil
+			availability.UpdatedAt = now
+			r.indexAvailability(availability)
+			continue
+		}
+		existing := r.availabilities[existingID]
+
+		// Combine slots, skipping those the target already marked as available
+		for _, slot := range availability.AvailableSlots {
+			duplicate := false
+			for _, existingSlot := range existing.AvailableSlots {
+				if slot.StartTime.Equal(existingSlot.StartTime) && slot.EndTime.Equal(existingSlot.EndTime) {
+					duplicate = true
+					break
+				}
+			}
+			if !duplicate {
+				existing.AvailableSlots = append(existing.AvailableSlots, slot)
+			}
+		}
+		existing.UpdatedAt = now
+		r.indexAvailability(existing)
+		r.unindexAvailability(availability)
+	}
+
+	return result, nil
+}
+
+// SaveRecommendations stores the latest recommendations of a meeting, replacing older ones
+func (r *InMemoryMeetingRepository) SaveRecommendations(set models.RecommendationSet) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.meetings[set.MeetingID]; !exists {
+		return errors.NewNotFoundError("Meeting not found")
+	}
+
+	// Never let a slower, older computation overwrite a newer one
+	if current, exists := r.recommendations[set.MeetingID]; exists && current.ComputedAt.After(set.ComputedAt) {
+		return nil
+	}
+	r.recommendations[set.MeetingID] = set
+	return nil
+}
+
+// GetRecommendations returns the stored recommendations of a meeting
+func (r *InMemoryMeetingRepository) GetRecommendations(meetingID string) (models.RecommendationSet, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	set, exists := r.recommendations[meetingID]
+	if !exists {
+		return models.RecommendationSet{}, errors.NewNotFoundError("Recommendations not found")
+	}
+	return set, nil
+}
+
+// Snapshot returns a copy of every record in a stable order
+func (r *InMemoryMeetingRepository) Snapshot() (models.MeetingStoreSnapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshot := models.MeetingStoreSnapshot{
+		Meetings:        make([]models.Meeting, 0, len(r.meetings)),
+		Availabilities:  make([]models.Availability, 0, len(r.availabilities)),
+		Timelines:       []models.TimelineEvent{},
+		Recommendations: make([]models.RecommendationSet, 0, len(r.recommendations)),
+	}
+	for _, meeting := range r.meetings {
+		snapshot.Meetings = append(snapshot.Meetings, meeting)
+	}
+	sort.Slice(snapshot.Meetings, func(i, j int) bool {
+		return pagination.Less(snapshot.Meetings[i].CreatedAt, snapshot.Meetings[i].ID, snapshot.Meetings[j].CreatedAt, snapshot.Meetings[j].ID)
+	})
+	for _, availability := range r.availabilities {
+		snapshot.Availabilities = append(snapshot.Availabilities, availability)
+	}
+	sort.Slice(snapshot.Availabilities, func(i, j int) bool {
+		a, b := snapshot.Availabilities[i], snapshot.Availabilities[j]
+		return pagination.Less(a.CreatedAt, a.ID, b.CreatedAt, b.ID)
+	})
+	for _, meeting := range snapshot.Meetings {
+		snapshot.Timelines = append(snapshot.Timelines, r.timelines[meeting.ID]...)
+		if set, exists := r.recommendations[meeting.ID]; exists {
+			snapshot.Recommendations = append(snapshot.Recommendations, set)
+		}
+	}
+	return snapshot, nil
+}
+
+// Restore replaces every record with the contents of a snapshot and rebuilds the indexes
+func (r *InMemoryMeetingRepository) Restore(snapshot models.MeetingStoreSnapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.meetings = make(map[string]models.Meeting, len(snapshot.Meetings))
+	r.availabilities = make(map[string]models.Availability, len(snapshot.Availabilities))
+	r.timelines = make(map[string][]models.TimelineEvent)
+	r.recommendations = make(map[string]models.RecommendationSet, len(snapshot.Recommendations))
+	r.meetingsByExternalID = make(map[string]string)
+	r.availabilitiesByMeeting = make(map[string]map[string]struct{})
+	r.availabilitiesByParticipant = make(map[string]map[string]struct{})
+	r.availabilityByParticipation = make(map[participationKey]string)
+
+	for _, meeting := range snapshot.Meetings {
+		r.meetings[meeting.ID] = meeting
+		if meeting.ExternalID != "" {
+			r.meetingsByExternalID[meeting.ExternalID] = meeting.ID
+		}
+	}
+	for _, availability := range snapshot.Availabilities {
+		r.indexAvailability(availability)
+	}
+	for _, event := range snapshot.Timelines {
+		r.timelines[event.MeetingID] = append(r.timelines[event.MeetingID], event)
+	}
+	for _, set := range snapshot.Recommendations {
+		r.recommendations[set.MeetingID] = set
+	}
+	return nil
+}
@@ -152,6 +152,93 @@ func TestInMemoryMeetingRepository_DeleteMeeting(t *testing.T) {
 	assert.Contains(t, err.Error(), "Meeting not found")
 }
 
+func TestInMemoryMeetingRepository_ExternalID(t *testing.T) {
+	repo := NewInMemoryMeetingRepository()
+	meeting := createTestMeeting()
+	meeting.ExternalID = "calendar-event-1"
+
+	created, err := repo.CreateMeeting(meeting)
+	require.NoError(t, err)
+	found, err := repo.GetMeetingByExternalID("calendar-event-1")
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, found.ID)
+
+	// External IDs are unique and cannot be changed
+	duplicate := createTestMeeting()
+	duplicate.ExternalID = "calendar-event-1"
+	_, err = repo.CreateMeeting(duplicate)
+	assert.Error(t, err)
+	created.ExternalID = "calendar-event-2"
+	_, err = repo.UpdateMeeting(created)
+	assert.Error(t, err)
+
+	// Restoring a snapshot rebuilds the index
+	snapshot, err := repo.Snapshot()
+	require.NoError(t, err)
+	restored := NewInMemoryMeetingRepository()
+	require.NoError(t, restored.Restore(snapshot))
+	found, err = restored.GetMeetingByExternalID("calendar-event-1")
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, found.ID)
+
+	require.NoError(t, repo.DeleteMeeting(created.ID))
+	_, err = repo.GetMeetingByExternalID("calendar-event-1")
+	assert.Error(t, err)
+	_, err = repo.CreateMeeting(duplicate)
+	assert.NoError(t, err)
+}
+
+func TestInMemoryMeetingRepository_SearchMeetings(t *testing.T) {
+	repo := NewInMemoryMeetingRepository()
+
+	create := func(title string, tags []string, participant string) models.Meeting {
+		meeting := createTestMeeting()
+		meeting.Title = title
+		meeting.Tags = tags
+		meeting.Participants[0].Name = participant
+		created, err := repo.CreateMeeting(meeting)
+		require.NoError(t, err)
+		return created
+	}
+
+	planning := create("Quarterly planning", nil, "Alice Martin")
+	review := create("Design review", []string{"planning"}, "Bob Stone")
+	create("Hiring sync", []string{"hiring"}, "Alice Martin")
+
+	t.Run("title matches rank above tags", func(t *testing.T) {
+		results, err := repo.SearchMeetings("Planning", 10)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, planning.ID, results[0].Meeting.ID)
+		assert.Equal(t, []string{"title"}, results[0].MatchedFields)
+		assert.Equal(t, review.ID, results[1].Meeting.ID)
+		assert.Equal(t, []string{"tags"}, results[1].MatchedFields)
+		assert.Greater(t, results[0].Score, results[1].Score)
+	})
+
+	t.Run("every word must match", func(t *testing.T) {
+		results, err := repo.SearchMeetings("alice plan", 10)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, planning.ID, results[0].Meeting.ID)
+		assert.Equal(t, []string{"title", "participants"}, results[0].MatchedFields)
+	})
+
+	t.Run("limit and no matches", func(t *testing.T) {
+		results, err := repo.SearchMeetings("alice", 1)
+		require.NoError(t, err)
+		assert.Len(t, results, 1)
+
+		results, err = repo.SearchMeetings("retrospective", 10)
+		require.NoError(t, err)
+		assert.Empty(t, results)
+
+		results, err = repo.SearchMeetings("!?", 10)
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+}
+
 func TestInMemoryMeetingRepository_Availability(t *testing.T) {
 	repo := NewInMemoryMeetingRepository()
 	meeting := createTestMeeting()
@@ -198,6 +285,100 @@ func TestInMemoryMeetingRepository_Availability(t *testing.T) {
 	assert.Empty(t, meetingAvails)
 }
 
+func TestInMemoryMeetingRepository_AvailabilityIndexes(t *testing.T) {
+	repo := NewInMemoryMeetingRepository()
+	first, err := repo.CreateMeeting(createTestMeeting())
+	require.NoError(t, err)
+	second, err := repo.CreateMeeting(createTestMeeting())
+	require.NoError(t, err)
+
+	participantID := first.Participants[0].ID
+	otherID := uuid.New().String()
+	availability, err := repo.CreateAvailability(models.Availability{ParticipantID: participantID, MeetingID: first.ID})
+	require.NoError(t, err)
+	_, err = repo.CreateAvailability(models.Availability{ParticipantID: participantID, MeetingID: second.ID})
+	require.NoError(t, err)
+
+	// Lookups are scoped to the meeting
+	found, err := repo.GetAvailability(participantID, first.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, availability.ID, found.ID)
+	_, err = repo.GetAvailability(otherID, first.ID)
+	assert.Error(t, err)
+
+	byParticipant, err := repo.GetParticipantAvailabilities(participantID)
+	assert.NoError(t, err)
+	assert.Len(t, byParticipant, 2)
+
+	// Changing the participant moves the index entry
+	availability.ParticipantID = otherID
+	_, err = repo.UpdateAvailability(availability)
+	require.NoError(t, err)
+	byParticipant, err = repo.GetParticipantAvailabilities(participantID)
+	assert.NoError(t, err)
+	assert.Len(t, byParticipant, 1)
+	byParticipant, err = repo.GetParticipantAvailabilities(otherID)
+	assert.NoError(t, err)
+	assert.Len(t, byParticipant, 1)
+	_, err = repo.GetAvailability(participantID, first.ID)
+	assert.Error(t, err)
+	found, err = repo.GetAvailability(otherID, first.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, availability.ID, found.ID)
+
+	// Deleting a meeting drops only its availabilities
+	require.NoError(t, repo.DeleteMeeting(first.ID))
+	availabilities, err := repo.GetMeetingAvailabilities(first.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, availabilities)
+	_, err = repo.GetAvailability(otherID, first.ID)
+	assert.Error(t, err)
+	availabilities, err = repo.GetMeetingAvailabilities(second.ID)
+	assert.NoError(t, err)
+	assert.Len(t, availabilities, 1)
+	byParticipant, err = repo.GetParticipantAvailabilities(otherID)
+	assert.NoError(t, err)
+	assert.Empty(t, byParticipant)
+}
+
+func TestInMemoryMeetingRepository_Timeline(t *testing.T) {
+	repo := NewInMemoryMeetingRepository()
+	created, err := repo.CreateMeeting(createTestMeeting())
+	require.NoError(t, err)
+
+	now := time.Now()
+	_, err = repo.AddTimelineEvent(models.TimelineEvent{
+		MeetingID:  created.ID,
+		Type:       models.TimelineAvailabilitySubmitted,
+		OccurredAt: now.Add(time.Minute),
+	})
+	require.NoError(t, err)
+	first, err := repo.AddTimelineEvent(models.TimelineEvent{
+		MeetingID:  created.ID,
+		Type:       models.TimelineMeetingCreated,
+		OccurredAt: now,
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, first.ID)
+
+	// Timeline is ordered by occurrence time
+	timeline, err := repo.GetTimeline(created.ID)
+	assert.NoError(t, err)
+	require.Len(t, timeline, 2)
+	assert.Equal(t, models.TimelineMeetingCreated, timeline[0].Type)
+	assert.Equal(t, models.TimelineAvailabilitySubmitted, timeline[1].Type)
+
+	// Events cannot be added to unknown meetings
+	_, err = repo.AddTimelineEvent(models.TimelineEvent{MeetingID: "non-existent-id"})
+	assert.Error(t, err)
+
+	// Timeline is removed with the meeting
+	require.NoError(t, repo.DeleteMeeting(created.ID))
+	_, err = repo.GetTimeline(created.ID)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Meeting not found")
+}
+
 func TestInMemoryMeetingRepository_ConcurrentOperations(t *testing.T) {
 	repo := NewInMemoryMeetingRepository()
 	var wg sync.WaitGroup
@@ -237,3 +418,63 @@ func TestInMemoryMeetingRepository_ConcurrentOperations(t *testing.T) {
 	allAvails := repo.GetAllAvailabilities()
 	assert.Len(t, allAvails, numGoroutines)
 }
+
+// seedAvailabilities fills a repository with meetings that each have a few responses
+func seedAvailabilities(b *testing.B, meetings, participantsPerMeeting int) (*InMemoryMeetingRepository, models.Availability) {
+	b.Helper()
+	repo := NewInMemoryMeetingRepository()
+	var last models.Availability
+	for i := 0; i < meetings; i++ {
+		meeting, err := repo.CreateMeeting(models.Meeting{Title: "Benchmark Meeting"})
+		require.NoError(b, err)
+		for j := 0; j < participantsPerMeeting; j++ {
+			last, err = repo.CreateAvailability(models.Availability{
+				ParticipantID: uuid.New().String(),
+				MeetingID:     meeting.ID,
+			})
+			require.NoError(b, err)
+		}
+	}
+	return repo, last
+}
+
+func BenchmarkInMemoryMeetingRepository_GetAvailability(b *testing.B) {
+	repo, target := seedAvailabilities(b, 1000, 10)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.GetAvailability(target.ParticipantID, target.MeetingID); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkInMemoryMeetingRepository_GetMeetingAvailabilities(b *testing.B) {
+	repo, target := seedAvailabilities(b, 1000, 10)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.GetMeetingAvailabilities(target.MeetingID); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkLinearAvailabilityScan is the full-scan lookup the indexes replaced, kept as a baseline
+func BenchmarkLinearAvailabilityScan(b *testing.B) {
+	repo, target := seedAvailabilities(b, 1000, 10)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		found := false
+		for _, availability := range repo.GetAllAvailabilities() {
+			if availability.ParticipantID == target.ParticipantID && availability.MeetingID == target.MeetingID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			b.Fatal("availability not found")
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"meetsync/internal/database"
+	"meetsync/pkg/errors"
+)
+
+// DriverPostgres is the name of the storage driver keeping all data in a Postgres database
+const DriverPostgres = "postgres"
+
+// postgresTimeout bounds reaching the database when the driver opens it, and every call
+// of the repositories it opens
+const postgresTimeout = 10 * time.Second
+
+// databaseRetryAfter is how long clients are asked to wait when the database is unavailable
+const databaseRetryAfter = 5 * time.Second
+
+func init() {
+	Register(DriverPostgres, DriverFunc(openPostgres))
+}
+
+// openPostgres opens the Postgres repositories on the database dsn points to, through the
+// database/sql driver registered as "postgres", which the main package imports. The
+// schema is left to database.Migrate, which runs on Storage.DB.
+func openPostgres(dsn string) (Storage, error) {
+	if dsn == "" {
+		return Storage{}, fmt.Errorf("postgres storage requires a connection string")
+	}
+	db, err := database.Open("postgres", dsn, database.PoolConfig{}, postgresTimeout)
+	if err != nil {
+		return Storage{}, err
+	}
+	return Storage{
+		Meetings: NewPostgresMeetingRepository(db, postgresTimeout),
+		Users:    NewPostgresUserRepository(db, postgresTimeout),
+		Audit:    NewPostgresAuditRepository(db, postgresTimeout),
+		Shared:   true,
+		DB:       db,
+		Close:    db.Close,
+	}, nil
+}
+
+// The Postgres repositories run on a *sql.DB opened with a Postgres driver such as
+// lib/pq, whose schema database.Migrate maintains. Records are stored as JSON documents
+// next to the columns they are looked up by.
+
+// postgresStore holds what every Postgres repository needs to run statements
+type postgresStore struct {
+	db      *sql.DB
+	timeout time.Duration // bounds every call, since the repository interfaces take no context
+}
+
+// context returns the context the statements of one call run in
+func (s postgresStore) context() (context.Context, context.CancelFunc) {
+	if s.timeout <= 0 {
+		return context.Background(), func() {}
+	}
+	return context.WithTimeout(context.Background(), s.timeout)
+}
+
+// Postgres SQLSTATEs the repositories tell apart
+const (
+	// uniqueViolation is a unique constraint violation
+	uniqueViolation = "23505"
+	// queryCanceled is a statement cancelled by the server, such as on statement_timeout
+	queryCanceled = "57014"
+	// connectionExceptionClass prefixes the codes of failed or lost connections
+	connectionExceptionClass = "08"
+	// adminShutdown, crashShutdown and cannotConnectNow are a server shutting down or
+	// still starting up
+	adminShutdown    = "57P01"
+	crashShutdown    = "57P02"
+	cannotConnectNow = "57P03"
+)
+
+// inTx runs fn in a transaction, committing when it succeeds
+func (s postgresStore) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return databaseError(err)
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return databaseError(err)
+	}
+	return databaseError(tx.Commit())
+}
+
+// databaseError passes application errors through and wraps database failures. A
+// database that cannot be reached or does not answer in time is unavailable, so clients
+// retry after databaseRetryAfter; timeouts still wrap a timeout error, since the timed
+// out operation may have applied and must not be retried blindly.
+func databaseError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := errors.AsAppError(err); ok {
+		return err
+	}
+	if isDatabaseTimeout(err) {
+		return errors.NewUnavailableError("The database did not answer in time", databaseRetryAfter,
+			errors.NewTimeoutError("The database did not answer in time", err))
+	}
+	if isDatabaseUnreachable(err) {
+		return errors.NewUnavailableError("The database cannot be reached", databaseRetryAfter, err)
+	}
+	return errors.NewInternalError("Database operation failed", err)
+}
+
+// isDatabaseTimeout reports whether err is a call that ran out of time, or a statement
+// the server cancelled
+func isDatabaseTimeout(err error) bool {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return true
+	}
+	var state interface{ SQLState() string }
+	return errors.As(err, &state) && state.SQLState() == queryCanceled
+}
+
+// isDatabaseUnreachable reports whether err is a failure to reach the database: a
+// refused or dropped connection, or a server shutting down or not accepting connections yet
+func isDatabaseUnreachable(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	if errors.As(err, &opErr) || errors.As(err, &dnsErr) {
+		return true
+	}
+	var state interface{ SQLState() string }
+	if !errors.As(err, &state) {
+		return false
+	}
+	code := state.SQLState()
+	return strings.HasPrefix(code, connectionExceptionClass) || code == adminShutdown || code == crashShutdown || code == cannotConnectNow
+}
+
+// isUniqueViolation reports whether err is a unique constraint violation. Drivers expose
+// the SQLSTATE differently, so it is looked up through the SQLState method lib/pq and
+// pgx both provide.
+func isUniqueViolation(err error) bool {
+	var state interface{ SQLState() string }
+	return errors.As(err, &state) && state.SQLState() == uniqueViolation
+}
+
+// storageTime truncates a timestamp to the microsecond precision of Postgres, so that
+// documents and their columns order records the same way
+func storageTime(t time.Time) time.Time {
+	return t.Truncate(time.Microsecond)
+}
+
+// nullString stores empty strings as NULL, so unique columns only constrain set values
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// scanDocuments decodes the JSON document in the single column of each row
+func scanDocuments[T any](rows *sql.Rows) ([]T, error) {
+	defer rows.Close()
+	var documents []T
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var document T
+		if err := json.Unmarshal(data, &document); err != nil {
+			return nil, err
+		}
+		documents = append(documents, document)
+	}
+	return documents, rows.Err()
+}
+
+// queryDocument decodes the JSON document of the single row a query returns into v,
+// failing with a not found error naming what when there is none
+func queryDocument(ctx context.Context, q queryer, v any, what, query string, args ...any) error {
+	var data []byte
+	err := q.QueryRowContext(ctx, query, args...).Scan(&data)
+	if err == sql.ErrNoRows {
+		return errors.NewNotFoundError(what + " not found")
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// queryer is implemented by *sql.DB and *sql.Tx
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"meetsync/internal/models"
+	"meetsync/internal/pagination"
+)
+
+// PostgresAuditRepository implements AuditRepository on a Postgres database, so the audit
+// log survives restarts and every replica appends to and lists the same log. Entries are
+// stored as JSON documents. It is safe for concurrent use.
+type PostgresAuditRepository struct {
+	postgresStore
+}
+
+// NewPostgresAuditRepository creates a PostgresAuditRepository on db, whose schema must
+// have been migrated with database.Migrate. Every call is bounded by timeout.
+func NewPostgresAuditRepository(db *sql.DB, timeout time.Duration) *PostgresAuditRepository {
+	return &PostgresAuditRepository{postgresStore{db: db, timeout: timeout}}
+}
+
+// putAuditEntry appends an entry to the audit log
+func putAuditEntry(ctx context.Context, q queryer, entry models.AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = q.ExecContext(ctx, `INSERT INTO audit_entries (id, occurred_at, data) VALUES ($1, $2, $3)`,
+		entry.ID, entry.OccurredAt, data)
+	return err
+}
+
+func (r *PostgresAuditRepository) Append(entry models.AuditEntry) (models.AuditEntry, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	if entry.ID == "" {
+		entry.ID = uuid.New().String()
+	}
+	if entry.OccurredAt.IsZero() {
+		entry.OccurredAt = time.Now()
+	}
+	entry.OccurredAt = storageTime(entry.OccurredAt)
+
+	if err := putAuditEntry(ctx, r.db, entry); err != nil {
+		return models.AuditEntry{}, databaseError(err)
+	}
+	return entry, nil
+}
+
+// List returns up to limit entries ordered by occurrence time, starting after the cursor
+func (r *PostgresAuditRepository) List(after *pagination.Cursor, limit int) ([]models.AuditEntry, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	var rows *sql.Rows
+	var err error
+	if after == nil {
+		rows, err = r.db.QueryContext(ctx, `SELECT data FROM audit_entries ORDER BY occurred_at, id LIMIT $1`, limit)
+	} else {
+		rows, err = r.db.QueryContext(ctx, `SELECT data FROM audit_entries WHERE (occurred_at, id) > ($1, $2) ORDER BY occurred_at, id LIMIT $3`,
+			after.Timestamp, after.ID, limit)
+	}
+	if err != nil {
+		return nil, databaseError(err)
+	}
+	entries, err := scanDocuments[models.AuditEntry](rows)
+	if err != nil {
+		return nil, databaseError(err)
+	}
+	if entries == nil {
+		entries = []models.AuditEntry{}
+	}
+	return entries, nil
+}
+
+// Snapshot returns a copy of every entry in the order they were appended
+func (r *PostgresAuditRepository) Snapshot() ([]models.AuditEntry, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `SELECT data FROM audit_entries ORDER BY seq`)
+	if err != nil {
+		return nil, databaseError(err)
+	}
+	entries, err := scanDocuments[models.AuditEntry](rows)
+	if err != nil {
+		return nil, databaseError(err)
+	}
+	if entries == nil {
+		entries = []models.AuditEntry{}
+	}
+	return entries, nil
+}
+
+// Restore replaces every entry with the given ones
+func (r *PostgresAuditRepository) Restore(entries []models.AuditEntry) error {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `TRUNCATE audit_entries`); err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := putAuditEntry(ctx, tx, entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
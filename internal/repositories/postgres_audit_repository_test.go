@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"meetsync/internal/models"
+	"meetsync/internal/pagination"
+)
+
+func TestPostgresAuditRepository(t *testing.T) {
+	repo := NewPostgresAuditRepository(openTestDatabase(t), 5*time.Second)
+
+	entries, err := repo.List(nil, 10)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// Entries sharing a timestamp are ordered by ID, so pages neither skip nor repeat them
+	at := time.Now()
+	for i := 0; i < 5; i++ {
+		entry, err := repo.Append(models.AuditEntry{Action: models.AuditActionUsersMerged, OccurredAt: at})
+		require.NoError(t, err)
+		assert.NotEmpty(t, entry.ID)
+	}
+	firstPage, err := repo.List(nil, 2)
+	require.NoError(t, err)
+	require.Len(t, firstPage, 2)
+	last := firstPage[1]
+	secondPage, err := repo.List(&pagination.Cursor{Timestamp: last.OccurredAt, ID: last.ID}, 10)
+	require.NoError(t, err)
+	require.Len(t, secondPage, 3)
+	for _, entry := range secondPage {
+		assert.NotEqual(t, firstPage[0].ID, entry.ID)
+		assert.NotEqual(t, firstPage[1].ID, entry.ID)
+	}
+
+	// Snapshots restore every entry in append order
+	snapshot, err := repo.Snapshot()
+	require.NoError(t, err)
+	require.Len(t, snapshot, 5)
+	require.NoError(t, repo.Restore(snapshot[:1]))
+	restored, err := repo.Snapshot()
+	require.NoError(t, err)
+	assert.Equal(t, snapshot[:1], restored)
+}
@@ -0,0 +1,720 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"meetsync/internal/models"
+	"meetsync/internal/pagination"
+	"meetsync/pkg/errors"
+)
+
+// PostgresMeetingRepository implements MeetingRepository on a Postgres database, so
+// that every replica of the service shares the same meetings. Meetings, availabilities,
+// timeline events and recommendations are stored as JSON documents, with the fields
+// they are queried by copied to indexed columns. It is safe for concurrent use.
+type PostgresMeetingRepository struct {
+	postgresStore
+}
+
+// NewPostgresMeetingRepository creates a PostgresMeetingRepository on db, whose schema
+// must have been migrated with database.Migrate. Every call is bounded by timeout.
+func NewPostgresMeetingRepository(db *sql.DB, timeout time.Duration) *PostgresMeetingRepository {
+	return &PostgresMeetingRepository{postgresStore{db: db, timeout: timeout}}
+}
+
+// meetingExists fails with a not found error unless the meeting exists
+func meetingExists(ctx context.Context, q queryer, id string) error {
+	var exists bool
+	if err := q.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM meetings WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return errors.NewNotFoundError("Meeting not found")
+	}
+	return nil
+}
+
+// putMeeting inserts or replaces a meeting
+func putMeeting(ctx context.Context, q queryer, meeting models.Meeting) error {
+	data, err := json.Marshal(meeting)
+	if err != nil {
+		return err
+	}
+	_, err = q.ExecContext(ctx, `INSERT INTO meetings (id, external_id, created_at, data) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO UPDATE SET external_id = EXCLUDED.external_id, data = EXCLUDED.data`,
+		meeting.ID, nullString(meeting.ExternalID), meeting.CreatedAt, data)
+	return err
+}
+
+// putAvailability inserts or replaces an availability
+func putAvailability(ctx context.Context, q queryer, availability models.Availability) error {
+	data, err := json.Marshal(availability)
+	if err != nil {
+		return err
+	}
+	_, err = q.ExecContext(ctx, `INSERT INTO availabilities (id, meeting_id, participant_id, created_at, data) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO UPDATE SET meeting_id = EXCLUDED.meeting_id, participant_id = EXCLUDED.participant_id, data = EXCLUDED.data`,
+		availability.ID, availability.MeetingID, availability.ParticipantID, availability.CreatedAt, data)
+	return err
+}
+
+// putTimelineEvent appends an event to the timeline of its meeting
+func putTimelineEvent(ctx context.Context, q queryer, event models.TimelineEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = q.ExecContext(ctx, `INSERT INTO timeline_events (id, meeting_id, user_id, occurred_at, data) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO UPDATE SET user_id = EXCLUDED.user_id, data = EXCLUDED.data`,
+		event.ID, event.MeetingID, event.UserID, event.OccurredAt, data)
+	return err
+}
+
+// putRecommendations stores the recommendations of a meeting unless newer ones are stored
+func putRecommendations(ctx context.Context, q queryer, set models.RecommendationSet) error {
+	data, err := json.Marshal(set)
+	if err != nil {
+		return err
+	}
+	_, err = q.ExecContext(ctx, `INSERT INTO recommendations (meeting_id, computed_at, data) VALUES ($1, $2, $3)
+		ON CONFLICT (meeting_id) DO UPDATE SET computed_at = EXCLUDED.computed_at, data = EXCLUDED.data
+		WHERE recommendations.computed_at <= EXCLUDED.computed_at`,
+		set.MeetingID, set.ComputedAt, data)
+	return err
+}
+
+func (r *PostgresMeetingRepository) CreateMeeting(meeting models.Meeting) (models.Meeting, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	now := storageTime(time.Now())
+	meeting.CreatedAt = now
+	meeting.UpdatedAt = now
+	if meeting.ID == "" {
+		meeting.ID = uuid.New().String()
+	}
+	if meeting.Status == "" {
+		meeting.Status = models.MeetingStatusPending
+	}
+	for i := range meeting.ProposedSlots {
+		if meeting.ProposedSlots[i].ID == "" {
+			meeting.ProposedSlots[i].ID = uuid.New().String()
+		}
+	}
+
+	data, err := json.Marshal(meeting)
+	if err != nil {
+		return models.Meeting{}, databaseError(err)
+	}
+	_, err = r.db.ExecContext(ctx, `INSERT INTO meetings (id, external_id, created_at, data) VALUES ($1, $2, $3, $4)`,
+		meeting.ID, nullString(meeting.ExternalID), meeting.CreatedAt, data)
+	if isUniqueViolation(err) {
+		// External IDs are unique so that concurrent syncs cannot create a meeting twice
+		return models.Meeting{}, errors.NewConflictError("A meeting with this external ID already exists")
+	}
+	if err != nil {
+		return models.Meeting{}, databaseError(err)
+	}
+	return meeting, nil
+}
+
+func (r *PostgresMeetingRepository) GetMeetingByID(id string) (models.Meeting, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	var meeting models.Meeting
+	if err := queryDocument(ctx, r.db, &meeting, "Meeting", `SELECT data FROM meetings WHERE id = $1`, id); err != nil {
+		return models.Meeting{}, databaseError(err)
+	}
+	return meeting, nil
+}
+
+// GetMeetingByExternalID returns the meeting synced from an external calendar under externalID
+func (r *PostgresMeetingRepository) GetMeetingByExternalID(externalID string) (models.Meeting, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	var meeting models.Meeting
+	if err := queryDocument(ctx, r.db, &meeting, "Meeting", `SELECT data FROM meetings WHERE external_id = $1`, externalID); err != nil {
+		return models.Meeting{}, databaseError(err)
+	}
+	return meeting, nil
+}
+
+// ListMeetings returns up to limit meetings matching the filter ordered by creation time, starting after the cursor
+func (r *PostgresMeetingRepository) ListMeetings(after *pagination.Cursor, limit int, filter models.MeetingFilter) ([]models.Meeting, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	query, args := listMeetingsQuery(after, limit, filter)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, databaseError(err)
+	}
+	meetings, err := scanDocuments[models.Meeting](rows)
+	if err != nil {
+		return nil, databaseError(err)
+	}
+	if meetings == nil {
+		meetings = []models.Meeting{}
+	}
+	return meetings, nil
+}
+
+// listMeetingsQuery builds the query of ListMeetings. The organizer, participant, status,
+// tags and metadata are matched by JSON containment, so a meeting must carry every tag and
+// metadata entry of the filter; the date range is matched against each proposed slot.
+func listMeetingsQuery(after *pagination.Cursor, limit int, filter models.MeetingFilter) (string, []any) {
+	var conditions []string
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if after != nil {
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) > (%s, %s)", arg(after.Timestamp), arg(after.ID)))
+	}
+	if filter.ExternalID != "" {
+		conditions = append(conditions, "external_id = "+arg(filter.ExternalID))
+	}
+	if !filter.From.IsZero() || !filter.To.IsZero() {
+		slot := "jsonb_array_elements(data->'proposedSlots') AS slot"
+		var bounds []string
+		if !filter.From.IsZero() {
+			bounds = append(bounds, "(slot->>'endTime')::timestamptz > "+arg(filter.From))
+		}
+		if !filter.To.IsZero() {
+			bounds = append(bounds, "(slot->>'startTime')::timestamptz < "+arg(filter.To))
+		}
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM "+slot+" WHERE "+strings.Join(bounds, " AND ")+")")
+	}
+	contains := map[string]any{}
+	if filter.OrganizerID != "" {
+		contains["organizerId"] = filter.OrganizerID
+	}
+	if filter.ParticipantID != "" {
+		contains["participants"] = []map[string]string{{"id": filter.ParticipantID}}
+	}
+	if filter.Status != "" {
+		contains["status"] = filter.Status
+	}
+	if len(filter.Tags) > 0 {
+		contains["tags"] = filter.Tags
+	}
+	if len(filter.Metadata) > 0 {
+		contains["metadata"] = filter.Metadata
+	}
+	if len(contains) > 0 {
+		document, _ := json.Marshal(contains)
+		conditions = append(conditions, "data @> "+arg(string(document))+"::jsonb")
+	}
+
+	query := "SELECT data FROM meetings"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at, id LIMIT " + arg(limit)
+	return query, args
+}
+
+// SearchMeetings returns up to limit meetings matching every word of the query, most relevant first.
+// The full-text index of the search column narrows meetings down to those with a word
+// starting with every term, which are then scored like the in-memory repository does.
+func (r *PostgresMeetingRepository) SearchMeetings(query string, limit int) ([]models.SearchResult, error) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return []models.SearchResult{}, nil
+	}
+
+	ctx, cancel := r.context()
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `SELECT data FROM meetings WHERE search @@ to_tsquery('simple', $1)`, prefixQuery(terms))
+	if err != nil {
+		return nil, databaseError(err)
+	}
+	meetings, err := scanDocuments[models.Meeting](rows)
+	if err != nil {
+		return nil, databaseError(err)
+	}
+
+	results := []models.SearchResult{}
+	for _, meeting := range meetings {
+		if score, matchedFields := scoreMeeting(meeting, terms); score > 0 {
+			results = append(results, models.SearchResult{Meeting: meeting, Score: score, MatchedFields: matchedFields})
+		}
+	}
+	rankSearchResults(results)
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// prefixQuery returns the tsquery matching documents with a word starting with every
+// term. Terms are quoted, so they are taken as words rather than query operators.
+func prefixQuery(terms []string) string {
+	lexemes := make([]string, len(terms))
+	for i, term := range terms {
+		lexemes[i] = "'" + strings.ReplaceAll(term, "'", "''") + "':*"
+	}
+	return strings.Join(lexemes, " & ")
+}
+
+func (r *PostgresMeetingRepository) UpdateMeeting(meeting models.Meeting) (models.Meeting, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	err := r.inTx(ctx, func(tx *sql.Tx) error {
+		var previous models.Meeting
+		if err := queryDocument(ctx, tx, &previous, "Meeting", `SELECT data FROM meetings WHERE id = $1 FOR UPDATE`, meeting.ID); err != nil {
+			return err
+		}
+		if meeting.ExternalID != previous.ExternalID {
+			return errors.NewValidationError("The external ID of a meeting cannot be changed", "")
+		}
+
+		meeting.CreatedAt = previous.CreatedAt
+		meeting.UpdatedAt = storageTime(time.Now())
+		return putMeeting(ctx, tx, meeting)
+	})
+	if err != nil {
+		return models.Meeting{}, err
+	}
+	return meeting, nil
+}
+
+func (r *PostgresMeetingRepository) DeleteMeeting(id string) error {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `DELETE FROM meetings WHERE id = $1`, id)
+		if err != nil {
+			return err
+		}
+		if deleted, err := result.RowsAffected(); err != nil {
+			return err
+		} else if deleted == 0 {
+			return errors.NewNotFoundError("Meeting not found")
+		}
+
+		// The timeline and recommendations go with the meeting through their foreign keys
+		_, err = tx.ExecContext(ctx, `DELETE FROM availabilities WHERE meeting_id = $1`, id)
+		return err
+	})
+}
+
+func (r *PostgresMeetingRepository) CreateAvailability(availability models.Availability) (models.Availability, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	now := storageTime(time.Now())
+	availability.CreatedAt = now
+	availability.UpdatedAt = now
+	if availability.ID == "" {
+		availability.ID = uuid.New().String()
+	}
+
+	if err := putAvailability(ctx, r.db, availability); err != nil {
+		return models.Availability{}, databaseError(err)
+	}
+	return availability, nil
+}
+
+func (r *PostgresMeetingRepository) GetAvailability(userID, meetingID string) (models.Availability, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	// Like the in-memory repository, the latest response of a participant wins
+	var availability models.Availability
+	err := queryDocument(ctx, r.db, &availability, "Availability",
+		`SELECT data FROM availabilities WHERE meeting_id = $1 AND participant_id = $2 ORDER BY created_at DESC, id DESC LIMIT 1`,
+		meetingID, userID)
+	if err != nil {
+		return models.Availability{}, databaseError(err)
+	}
+	return availability, nil
+}
+
+func (r *PostgresMeetingRepository) GetAvailabilityByID(id string) (models.Availability, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	var availability models.Availability
+	if err := queryDocument(ctx, r.db, &availability, "Availability", `SELECT data FROM availabilities WHERE id = $1`, id); err != nil {
+		return models.Availability{}, databaseError(err)
+	}
+	return availability, nil
+}
+
+func (r *PostgresMeetingRepository) UpdateAvailability(availability models.Availability) (models.Availability, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	err := r.inTx(ctx, func(tx *sql.Tx) error {
+		var previous models.Availability
+		if err := queryDocument(ctx, tx, &previous, "Availability", `SELECT data FROM availabilities WHERE id = $1 FOR UPDATE`, availability.ID); err != nil {
+			return err
+		}
+		availability.CreatedAt = previous.CreatedAt
+		availability.UpdatedAt = storageTime(time.Now())
+		return putAvailability(ctx, tx, availability)
+	})
+	if err != nil {
+		return models.Availability{}, err
+	}
+	return availability, nil
+}
+
+func (r *PostgresMeetingRepository) DeleteAvailability(id string) error {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM availabilities WHERE id = $1`, id)
+	if err != nil {
+		return databaseError(err)
+	}
+	if deleted, err := result.RowsAffected(); err != nil {
+		return databaseError(err)
+	} else if deleted == 0 {
+		return errors.NewNotFoundError("Availability not found")
+	}
+	return nil
+}
+
+func (r *PostgresMeetingRepository) GetMeetingAvailabilities(meetingID string) ([]models.Availability, error) {
+	return r.queryAvailabilities(`SELECT data FROM availabilities WHERE meeting_id = $1 ORDER BY created_at, id`, meetingID)
+}
+
+func (r *PostgresMeetingRepository) GetParticipantAvailabilities(participantID string) ([]models.Availability, error) {
+	return r.queryAvailabilities(`SELECT data FROM availabilities WHERE participant_id = $1 ORDER BY created_at, id`, participantID)
+}
+
+// GetAllAvailabilities returns every availability. The interface has no way to report
+// failures, so an unreachable database yields no availabilities.
+func (r *PostgresMeetingRepository) GetAllAvailabilities() []models.Availability {
+	availabilities, err := r.queryAvailabilities(`SELECT data FROM availabilities ORDER BY created_at, id`)
+	if err != nil || availabilities == nil {
+		return []models.Availability{}
+	}
+	return availabilities
+}
+
+// queryAvailabilities returns the availabilities a query selects the documents of
+func (r *PostgresMeetingRepository) queryAvailabilities(query string, args ...any) ([]models.Availability, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, databaseError(err)
+	}
+	availabilities, err := scanDocuments[models.Availability](rows)
+	if err != nil {
+		return nil, databaseError(err)
+	}
+	return availabilities, nil
+}
+
+func (r *PostgresMeetingRepository) AddTimelineEvent(event models.TimelineEvent) (models.TimelineEvent, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = storageTime(time.Now())
+	}
+
+	err := r.inTx(ctx, func(tx *sql.Tx) error {
+		if err := meetingExists(ctx, tx, event.MeetingID); err != nil {
+			return err
+		}
+		return putTimelineEvent(ctx, tx, event)
+	})
+	if err != nil {
+		return models.TimelineEvent{}, err
+	}
+	return event, nil
+}
+
+func (r *PostgresMeetingRepository) GetTimeline(meetingID string) ([]models.TimelineEvent, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	var timeline []models.TimelineEvent
+	err := r.inTx(ctx, func(tx *sql.Tx) error {
+		if err := meetingExists(ctx, tx, meetingID); err != nil {
+			return err
+		}
+		rows, err := tx.QueryContext(ctx, `SELECT data FROM timeline_events WHERE meeting_id = $1 ORDER BY occurred_at, seq`, meetingID)
+		if err != nil {
+			return err
+		}
+		timeline, err = scanDocuments[models.TimelineEvent](rows)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if timeline == nil {
+		timeline = []models.TimelineEvent{}
+	}
+	return timeline, nil
+}
+
+// ReassignUser moves every organizer role, participation and availability of
+// one user to another in a single transaction. When both users responded to the
+// same meeting their available slots are combined.
+func (r *PostgresMeetingRepository) ReassignUser(fromUserID string, to models.User) (models.UserReassignment, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	result := models.UserReassignment{SourceUserID: fromUserID, TargetUserID: to.ID}
+	now := storageTime(time.Now())
+
+	err := r.inTx(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, `SELECT data FROM meetings
+			WHERE data->>'organizerId' = $1 OR data->'participants' @> jsonb_build_array(jsonb_build_object('id', $1::text))
+			FOR UPDATE`, fromUserID)
+		if err != nil {
+			return err
+		}
+		meetings, err := scanDocuments[models.Meeting](rows)
+		if err != nil {
+			return err
+		}
+		for _, meeting := range meetings {
+			if meeting.OrganizerID == fromUserID {
+				meeting.OrganizerID = to.ID
+				organizer := to
+				meeting.Organizer = &organizer
+				result.MeetingsOrganized++
+			}
+
+			participants := make([]models.User, 0, len(meeting.Participants))
+			hasTarget := false
+			for _, participant := range meeting.Participants {
+				if participant.ID == to.ID {
+					hasTarget = true
+				}
+			}
+			for _, participant := range meeting.Participants {
+				if participant.ID != fromUserID {
+					participants = append(participants, participant)
+					continue
+				}
+				result.Participations++
+				if !hasTarget {
+					participants = append(participants, to)
+					hasTarget = true
+				}
+			}
+
+			meeting.Proposals, _ = reassignProposals(meeting.Proposals, fromUserID, to.ID)
+			meeting.Participants = participants
+			meeting.UpdatedAt = now
+			if err := putMeeting(ctx, tx, meeting); err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, `DELETE FROM recommendations WHERE meeting_id = $1`, meeting.ID); err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, `UPDATE timeline_events
+			SET user_id = $2, data = jsonb_set(data, '{userId}', to_jsonb($2::text))
+			WHERE user_id = $1`, fromUserID, to.ID); err != nil {
+			return err
+		}
+
+		rows, err = tx.QueryContext(ctx, `SELECT data FROM availabilities WHERE participant_id = $1 ORDER BY created_at, id FOR UPDATE`, fromUserID)
+		if err != nil {
+			return err
+		}
+		moved, err := scanDocuments[models.Availability](rows)
+		if err != nil {
+			return err
+		}
+		for _, availability := range moved {
+			result.Availabilities++
+			if _, err := tx.ExecContext(ctx, `DELETE FROM recommendations WHERE meeting_id = $1`, availability.MeetingID); err != nil {
+				return err
+			}
+
+			var existing models.Availability
+			err := queryDocument(ctx, tx, &existing, "Availability",
+				`SELECT data FROM availabilities WHERE meeting_id = $1 AND participant_id = $2 ORDER BY created_at DESC, id DESC LIMIT 1 FOR UPDATE`,
+				availability.MeetingID, to.ID)
+			if errors.Is(err, errors.ErrNotFound) {
+				availability.ParticipantID = to.ID
+				availability.Participant = nil
+				availability.UpdatedAt = now
+				if err := putAvailability(ctx, tx, availability); err != nil {
+					return err
+				}
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			// Combine slots, skipping those the target already marked as available
+			for _, slot := range availability.AvailableSlots {
+				duplicate := false
+				for _, existingSlot := range existing.AvailableSlots {
+					if slot.StartTime.Equal(existingSlot.StartTime) && slot.EndTime.Equal(existingSlot.EndTime) {
+						duplicate = true
+						break
+					}
+				}
+				if !duplicate {
+					existing.AvailableSlots = append(existing.AvailableSlots, slot)
+				}
+			}
+			existing.UpdatedAt = now
+			if err := putAvailability(ctx, tx, existing); err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, `DELETE FROM availabilities WHERE id = $1`, availability.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return models.UserReassignment{}, err
+	}
+	return result, nil
+}
+
+// SaveRecommendations stores the latest recommendations of a meeting, replacing older ones
+func (r *PostgresMeetingRepository) SaveRecommendations(set models.RecommendationSet) error {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		if err := meetingExists(ctx, tx, set.MeetingID); err != nil {
+			return err
+		}
+		// Never let a slower, older computation overwrite a newer one
+		return putRecommendations(ctx, tx, set)
+	})
+}
+
+// GetRecommendations returns the stored recommendations of a meeting
+func (r *PostgresMeetingRepository) GetRecommendations(meetingID string) (models.RecommendationSet, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	var set models.RecommendationSet
+	if err := queryDocument(ctx, r.db, &set, "Recommendations", `SELECT data FROM recommendations WHERE meeting_id = $1`, meetingID); err != nil {
+		return models.RecommendationSet{}, databaseError(err)
+	}
+	return set, nil
+}
+
+// Snapshot returns a copy of every record in a stable order, read in one transaction
+// so the copy is consistent
+func (r *PostgresMeetingRepository) Snapshot() (models.MeetingStoreSnapshot, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	var snapshot models.MeetingStoreSnapshot
+	err := r.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `SET TRANSACTION ISOLATION LEVEL REPEATABLE READ READ ONLY`); err != nil {
+			return err
+		}
+		queries := []struct {
+			query string
+			scan  func(rows *sql.Rows) error
+		}{
+			{`SELECT data FROM meetings ORDER BY created_at, id`, func(rows *sql.Rows) (err error) {
+				snapshot.Meetings, err = scanDocuments[models.Meeting](rows)
+				return err
+			}},
+			{`SELECT data FROM availabilities ORDER BY created_at, id`, func(rows *sql.Rows) (err error) {
+				snapshot.Availabilities, err = scanDocuments[models.Availability](rows)
+				return err
+			}},
+			{`SELECT e.data FROM timeline_events e JOIN meetings m ON m.id = e.meeting_id ORDER BY m.created_at, m.id, e.seq`, func(rows *sql.Rows) (err error) {
+				snapshot.Timelines, err = scanDocuments[models.TimelineEvent](rows)
+				return err
+			}},
+			{`SELECT r.data FROM recommendations r JOIN meetings m ON m.id = r.meeting_id ORDER BY m.created_at, m.id`, func(rows *sql.Rows) (err error) {
+				snapshot.Recommendations, err = scanDocuments[models.RecommendationSet](rows)
+				return err
+			}},
+		}
+		for _, q := range queries {
+			rows, err := tx.QueryContext(ctx, q.query)
+			if err != nil {
+				return err
+			}
+			if err := q.scan(rows); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return models.MeetingStoreSnapshot{}, err
+	}
+	if snapshot.Meetings == nil {
+		snapshot.Meetings = []models.Meeting{}
+	}
+	if snapshot.Availabilities == nil {
+		snapshot.Availabilities = []models.Availability{}
+	}
+	if snapshot.Timelines == nil {
+		snapshot.Timelines = []models.TimelineEvent{}
+	}
+	if snapshot.Recommendations == nil {
+		snapshot.Recommendations = []models.RecommendationSet{}
+	}
+	return snapshot, nil
+}
+
+// Restore replaces every record with the contents of a snapshot in one transaction
+func (r *PostgresMeetingRepository) Restore(snapshot models.MeetingStoreSnapshot) error {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `TRUNCATE meetings, availabilities, timeline_events, recommendations`); err != nil {
+			return err
+		}
+		for _, meeting := range snapshot.Meetings {
+			if err := putMeeting(ctx, tx, meeting); err != nil {
+				return err
+			}
+		}
+		for _, availability := range snapshot.Availabilities {
+			if err := putAvailability(ctx, tx, availability); err != nil {
+				return err
+			}
+		}
+		// Events are inserted in snapshot order, which keeps the order of simultaneous ones
+		for _, event := range snapshot.Timelines {
+			if err := putTimelineEvent(ctx, tx, event); err != nil {
+				return err
+			}
+		}
+		for _, set := range snapshot.Recommendations {
+			if err := putRecommendations(ctx, tx, set); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
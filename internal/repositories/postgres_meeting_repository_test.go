@@ -0,0 +1,205 @@
+package repositories
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"meetsync/internal/database"
+	"meetsync/internal/models"
+	"meetsync/internal/pagination"
+	"meetsync/pkg/errors"
+)
+
+func TestListMeetingsQuery(t *testing.T) {
+	query, args := listMeetingsQuery(nil, 20, models.MeetingFilter{})
+	assert.Equal(t, "SELECT data FROM meetings ORDER BY created_at, id LIMIT $1", query)
+	assert.Equal(t, []any{20}, args)
+
+	cursor := &pagination.Cursor{Timestamp: time.Unix(1700000000, 0), ID: "m1"}
+	query, args = listMeetingsQuery(cursor, 5, models.MeetingFilter{
+		Tags:       []string{"hiring"},
+		ExternalID: "cal-1",
+		Metadata:   models.Metadata{"crm": "acme"},
+	})
+	assert.Equal(t, "SELECT data FROM meetings WHERE (created_at, id) > ($1, $2) AND external_id = $3 AND data @> $4::jsonb ORDER BY created_at, id LIMIT $5", query)
+	assert.Equal(t, []any{cursor.Timestamp, "m1", "cal-1", `{"metadata":{"crm":"acme"},"tags":["hiring"]}`, 5}, args)
+
+	from := time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC)
+	query, args = listMeetingsQuery(nil, 5, models.MeetingFilter{OrganizerID: "u1", ParticipantID: "u2", Status: models.MeetingStatusPending, From: from})
+	assert.Equal(t, "SELECT data FROM meetings WHERE EXISTS (SELECT 1 FROM jsonb_array_elements(data->'proposedSlots') AS slot WHERE (slot->>'endTime')::timestamptz > $1) AND data @> $2::jsonb ORDER BY created_at, id LIMIT $3", query)
+	assert.Equal(t, []any{from, `{"organizerId":"u1","participants":[{"id":"u2"}],"status":"pending"}`, 5}, args)
+}
+
+func TestPrefixQuery(t *testing.T) {
+	assert.Equal(t, "'plan':*", prefixQuery([]string{"plan"}))
+	assert.Equal(t, "'plan':* & 'o''brien':*", prefixQuery([]string{"plan", "o'brien"}))
+}
+
+// openTestDatabase returns the database TEST_DATABASE_DSN points to, migrated and
+// emptied, skipping the test when it is not set
+func openTestDatabase(t *testing.T) *sql.DB {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+	db, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	_, err = database.Migrate(db, 10*time.Second)
+	require.NoError(t, err)
+	applied, err := database.Migrate(db, 10*time.Second)
+	require.NoError(t, err)
+	assert.Zero(t, applied, "migrating twice applies nothing the second time")
+	_, err = db.Exec(`TRUNCATE meetings, availabilities, timeline_events, recommendations, users, user_tokens, access_tokens, audit_entries`)
+	require.NoError(t, err)
+	return db
+}
+
+func TestPostgresMeetingRepository(t *testing.T) {
+	repo := NewPostgresMeetingRepository(openTestDatabase(t), 5*time.Second)
+
+	meeting := createTestMeeting()
+	meeting.Tags = []string{"hiring"}
+	meeting.ExternalID = "cal-1"
+	created, err := repo.CreateMeeting(meeting)
+	require.NoError(t, err)
+	assert.Equal(t, models.MeetingStatusPending, created.Status)
+
+	_, err = repo.CreateMeeting(meeting)
+	assert.True(t, errors.Is(err, errors.ErrConflict))
+
+	got, err := repo.GetMeetingByExternalID("cal-1")
+	require.NoError(t, err)
+	assert.Equal(t, created.Title, got.Title)
+	assert.True(t, created.CreatedAt.Equal(got.CreatedAt))
+
+	listed, err := repo.ListMeetings(nil, 10, models.MeetingFilter{Tags: []string{"hiring"}})
+	require.NoError(t, err)
+	assert.Len(t, listed, 1)
+	listed, err = repo.ListMeetings(nil, 10, models.MeetingFilter{
+		ParticipantID: created.Participants[0].ID,
+		Status:        models.MeetingStatusPending,
+		From:          created.ProposedSlots[0].StartTime,
+		To:            created.ProposedSlots[0].EndTime,
+	})
+	require.NoError(t, err)
+	assert.Len(t, listed, 1)
+	listed, err = repo.ListMeetings(nil, 10, models.MeetingFilter{From: created.ProposedSlots[len(created.ProposedSlots)-1].EndTime})
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+	listed, err = repo.ListMeetings(&pagination.Cursor{Timestamp: created.CreatedAt, ID: created.ID}, 10, models.MeetingFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+
+	results, err := repo.SearchMeetings("test", 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, []string{"title"}, results[0].MatchedFields)
+	// The full-text index matches words by prefix
+	results, err = repo.SearchMeetings("hir", 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, []string{"tags"}, results[0].MatchedFields)
+	results, err = repo.SearchMeetings("test unrelated", 10)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	got.Title = "Renamed"
+	_, err = repo.UpdateMeeting(got)
+	require.NoError(t, err)
+	got.ExternalID = "cal-2"
+	_, err = repo.UpdateMeeting(got)
+	assert.True(t, errors.Is(err, errors.ErrValidation))
+
+	// Availabilities and their lookups
+	participantID := created.Participants[0].ID
+	availability, err := repo.CreateAvailability(models.Availability{
+		ParticipantID:  participantID,
+		MeetingID:      created.ID,
+		AvailableSlots: created.ProposedSlots,
+	})
+	require.NoError(t, err)
+	byParticipation, err := repo.GetAvailability(participantID, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, availability.ID, byParticipation.ID)
+	forMeeting, err := repo.GetMeetingAvailabilities(created.ID)
+	require.NoError(t, err)
+	assert.Len(t, forMeeting, 1)
+	assert.Len(t, repo.GetAllAvailabilities(), 1)
+
+	// Timeline and recommendations
+	_, err = repo.AddTimelineEvent(models.TimelineEvent{MeetingID: created.ID, Type: models.TimelineMeetingFinalized, UserID: participantID})
+	require.NoError(t, err)
+	computedAt := time.Now().UTC().Truncate(time.Microsecond)
+	require.NoError(t, repo.SaveRecommendations(models.RecommendationSet{MeetingID: created.ID, ComputedAt: computedAt}))
+	require.NoError(t, repo.SaveRecommendations(models.RecommendationSet{MeetingID: created.ID, ComputedAt: computedAt.Add(-time.Minute)}))
+	set, err := repo.GetRecommendations(created.ID)
+	require.NoError(t, err)
+	assert.True(t, computedAt.Equal(set.ComputedAt))
+
+	// Reassigning the participant moves their availability and timeline entries
+	target := models.User{ID: "target", Name: "Target"}
+	reassignment, err := repo.ReassignUser(participantID, target)
+	require.NoError(t, err)
+	assert.Equal(t, 1, reassignment.Participations)
+	assert.Equal(t, 1, reassignment.Availabilities)
+	_, err = repo.GetAvailability(target.ID, created.ID)
+	require.NoError(t, err)
+	timeline, err := repo.GetTimeline(created.ID)
+	require.NoError(t, err)
+	require.Len(t, timeline, 1)
+	assert.Equal(t, target.ID, timeline[0].UserID)
+
+	// Snapshots restore every record
+	snapshot, err := repo.Snapshot()
+	require.NoError(t, err)
+	require.NoError(t, repo.DeleteMeeting(created.ID))
+	_, err = repo.GetAvailabilityByID(availability.ID)
+	assert.True(t, errors.Is(err, errors.ErrNotFound))
+	require.NoError(t, repo.Restore(snapshot))
+	restored, err := repo.Snapshot()
+	require.NoError(t, err)
+	assert.Equal(t, snapshot, restored)
+}
+
+func TestPostgresStorageDriver(t *testing.T) {
+	_, err := Open(DriverPostgres, "")
+	assert.ErrorContains(t, err, "requires a connection string")
+
+	openTestDatabase(t)
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	storage, err := Open(DriverPostgres, dsn)
+	require.NoError(t, err)
+	assert.True(t, storage.Shared)
+	require.NotNil(t, storage.DB)
+	assert.IsType(t, &PostgresMeetingRepository{}, storage.Meetings)
+	assert.IsType(t, &PostgresUserRepository{}, storage.Users)
+	assert.IsType(t, &PostgresAuditRepository{}, storage.Audit)
+
+	user, err := storage.Users.Create(models.User{Name: "Alice", Email: "alice@example.com"})
+	require.NoError(t, err)
+	meeting, err := storage.Meetings.CreateMeeting(createTestMeeting())
+	require.NoError(t, err)
+	_, err = storage.Audit.Append(models.AuditEntry{Action: models.AuditActionUsersMerged, SubjectID: user.ID})
+	require.NoError(t, err)
+	require.NoError(t, storage.Close())
+
+	// Data outlives the process that stored it
+	storage, err = Open(DriverPostgres, dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { storage.Close() })
+	_, err = storage.Users.GetByID(user.ID)
+	assert.NoError(t, err)
+	_, err = storage.Meetings.GetMeetingByID(meeting.ID)
+	assert.NoError(t, err)
+	entries, err := storage.Audit.List(nil, 10)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
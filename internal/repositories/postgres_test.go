@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"meetsync/pkg/errors"
+)
+
+// sqlStateError is a driver error carrying a SQLSTATE, as lib/pq and pgx errors do
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "SQLSTATE " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+func TestDatabaseError(t *testing.T) {
+	assert.NoError(t, databaseError(nil))
+
+	// Application errors pass through, even wrapped
+	notFound := fmt.Errorf("lookup: %w", errors.NewNotFoundError("Meeting not found"))
+	assert.Equal(t, notFound, databaseError(notFound))
+
+	tests := []struct {
+		name        string
+		err         error
+		wantType    errors.ErrorType
+		wantTimeout bool
+	}{
+		{name: "deadline exceeded", err: fmt.Errorf("query: %w", context.DeadlineExceeded), wantType: errors.ErrorTypeUnavailable, wantTimeout: true},
+		{name: "statement timeout", err: sqlStateError(queryCanceled), wantType: errors.ErrorTypeUnavailable, wantTimeout: true},
+		{name: "connection refused", err: &net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("connection refused")}, wantType: errors.ErrorTypeUnavailable},
+		{name: "bad connection", err: driver.ErrBadConn, wantType: errors.ErrorTypeUnavailable},
+		{name: "connection failure", err: sqlStateError("08006"), wantType: errors.ErrorTypeUnavailable},
+		{name: "server shutting down", err: sqlStateError(adminShutdown), wantType: errors.ErrorTypeUnavailable},
+		{name: "syntax error", err: sqlStateError("42601"), wantType: errors.ErrorTypeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := databaseError(tt.err)
+			appErr, ok := errors.AsAppError(err)
+			assert.True(t, ok)
+			assert.Equal(t, tt.wantType, appErr.Type)
+			assert.True(t, errors.Is(err, tt.err))
+			assert.Equal(t, tt.wantTimeout, errors.Is(err, errors.ErrTimeout))
+			if tt.wantType == errors.ErrorTypeUnavailable {
+				assert.Equal(t, databaseRetryAfter, appErr.RetryAfter)
+			}
+		})
+	}
+}
@@ -0,0 +1,472 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"meetsync/internal/models"
+	"meetsync/internal/sanitize"
+	"meetsync/pkg/errors"
+)
+
+// PostgresUserRepository implements UserRepository on a Postgres database. Users, email
+// tokens and personal access tokens are stored as JSON documents; email addresses are
+// indexed in their normalized form (see sanitize.Email) and unique whatever their case,
+// which a unique index on their lowercase form enforces even between replicas. It is safe for concurrent use.
+type PostgresUserRepository struct {
+	postgresStore
+}
+
+// NewPostgresUserRepository creates a PostgresUserRepository on db, whose schema must
+// have been migrated with database.Migrate. Every call is bounded by timeout.
+func NewPostgresUserRepository(db *sql.DB, timeout time.Duration) *PostgresUserRepository {
+	return &PostgresUserRepository{postgresStore{db: db, timeout: timeout}}
+}
+
+// userExists fails with a not found error unless the user exists
+func userExists(ctx context.Context, q queryer, id string) error {
+	var exists bool
+	if err := q.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return errors.NewNotFoundError("User not found")
+	}
+	return nil
+}
+
+// putUser inserts or replaces a user
+func putUser(ctx context.Context, q queryer, user models.User) error {
+	data, err := json.Marshal(user)
+	if err != nil {
+		return err
+	}
+	_, err = q.ExecContext(ctx, `INSERT INTO users (id, email, created_at, updated_at, data) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO UPDATE SET email = EXCLUDED.email, updated_at = EXCLUDED.updated_at, data = EXCLUDED.data`,
+		user.ID, sanitize.Email(user.Email), user.CreatedAt, user.UpdatedAt, data)
+	if isUniqueViolation(err) {
+		return errors.NewConflictError("Email is already in use")
+	}
+	return err
+}
+
+// putToken inserts an email token
+func putToken(ctx context.Context, q queryer, token models.UserToken) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	_, err = q.ExecContext(ctx, `INSERT INTO user_tokens (token, user_id, purpose, created_at, data) VALUES ($1, $2, $3, $4, $5)`,
+		token.Token, token.UserID, token.Purpose, token.CreatedAt, data)
+	return err
+}
+
+// putAccessToken inserts or replaces a personal access token. The secret hash is kept
+// out of the document, like it is kept out of every other serialization of the token.
+func putAccessToken(ctx context.Context, q queryer, token models.PersonalAccessToken) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	_, err = q.ExecContext(ctx, `INSERT INTO access_tokens (id, user_id, secret_hash, created_at, data) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO UPDATE SET data = EXCLUDED.data`,
+		token.ID, token.UserID, token.SecretHash, token.CreatedAt, data)
+	return err
+}
+
+// scanAccessTokens decodes rows of access token documents and secret hashes
+func scanAccessTokens(rows *sql.Rows) ([]models.PersonalAccessToken, error) {
+	defer rows.Close()
+	tokens := []models.PersonalAccessToken{}
+	for rows.Next() {
+		var data []byte
+		var secretHash string
+		if err := rows.Scan(&data, &secretHash); err != nil {
+			return nil, err
+		}
+		var token models.PersonalAccessToken
+		if err := json.Unmarshal(data, &token); err != nil {
+			return nil, err
+		}
+		token.SecretHash = secretHash
+		tokens = append(tokens, token)
+	}
+	return tokens, rows.Err()
+}
+
+func (r *PostgresUserRepository) Create(user models.User) (models.User, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	now := storageTime(time.Now())
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	if user.ID == "" {
+		user.ID = uuid.New().String()
+	}
+
+	data, err := json.Marshal(user)
+	if err != nil {
+		return models.User{}, databaseError(err)
+	}
+	_, err = r.db.ExecContext(ctx, `INSERT INTO users (id, email, created_at, updated_at, data) VALUES ($1, $2, $3, $4, $5)`,
+		user.ID, sanitize.Email(user.Email), user.CreatedAt, user.UpdatedAt, data)
+	if isUniqueViolation(err) {
+		return models.User{}, errors.NewConflictError("Email is already in use")
+	}
+	if err != nil {
+		return models.User{}, databaseError(err)
+	}
+	return user, nil
+}
+
+func (r *PostgresUserRepository) GetByID(id string) (models.User, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	var user models.User
+	if err := queryDocument(ctx, r.db, &user, "User", `SELECT data FROM users WHERE id = $1`, id); err != nil {
+		return models.User{}, databaseError(err)
+	}
+	return user, nil
+}
+
+// GetByIDs returns the users matching ids in a single query, in the order of ids;
+// unknown IDs are skipped
+func (r *PostgresUserRepository) GetByIDs(ids []string) ([]models.User, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	list, err := json.Marshal(ids)
+	if err != nil {
+		return nil, databaseError(err)
+	}
+	rows, err := r.db.QueryContext(ctx, `SELECT data FROM users WHERE id IN (SELECT jsonb_array_elements_text($1::jsonb))`, string(list))
+	if err != nil {
+		return nil, databaseError(err)
+	}
+	found, err := scanDocuments[models.User](rows)
+	if err != nil {
+		return nil, databaseError(err)
+	}
+
+	byID := make(map[string]models.User, len(found))
+	for _, user := range found {
+		byID[user.ID] = user
+	}
+	users := make([]models.User, 0, len(found))
+	for _, id := range ids {
+		if user, exists := byID[id]; exists {
+			users = append(users, user)
+			delete(byID, id)
+		}
+	}
+	return users, nil
+}
+
+func (r *PostgresUserRepository) GetAll() ([]models.User, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `SELECT data FROM users ORDER BY created_at, id`)
+	if err != nil {
+		return nil, databaseError(err)
+	}
+	users, err := scanDocuments[models.User](rows)
+	if err != nil {
+		return nil, databaseError(err)
+	}
+	if users == nil {
+		users = []models.User{}
+	}
+	return users, nil
+}
+
+// GetByEmail returns the user with an email address, whatever its case and form. The interface
+// has no way to report failures, so an unreachable database finds no user.
+func (r *PostgresUserRepository) GetByEmail(email string) (models.User, bool) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	var user models.User
+	if err := queryDocument(ctx, r.db, &user, "User", `SELECT data FROM users WHERE lower(email) = $1`, sanitize.Email(email)); err != nil {
+		return models.User{}, false
+	}
+	return user, true
+}
+
+func (r *PostgresUserRepository) Update(user models.User) (models.User, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	err := r.inTx(ctx, func(tx *sql.Tx) error {
+		var existing models.User
+		if err := queryDocument(ctx, tx, &existing, "User", `SELECT data FROM users WHERE id = $1 FOR UPDATE`, user.ID); err != nil {
+			return err
+		}
+		user.CreatedAt = existing.CreatedAt
+		user.UpdatedAt = storageTime(time.Now())
+		return putUser(ctx, tx, user)
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+	return user, nil
+}
+
+// Delete removes a user; their outstanding tokens go with them through foreign keys
+func (r *PostgresUserRepository) Delete(id string) error {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, id)
+	if err != nil {
+		return databaseError(err)
+	}
+	if deleted, err := result.RowsAffected(); err != nil {
+		return databaseError(err)
+	} else if deleted == 0 {
+		return errors.NewNotFoundError("User not found")
+	}
+	return nil
+}
+
+// CreateToken stores a token, replacing any outstanding token of the user with the same purpose
+func (r *PostgresUserRepository) CreateToken(token models.UserToken) (models.UserToken, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	token.CreatedAt = storageTime(time.Now())
+	err := r.inTx(ctx, func(tx *sql.Tx) error {
+		if err := userExists(ctx, tx, token.UserID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM user_tokens WHERE user_id = $1 AND purpose = $2`, token.UserID, token.Purpose); err != nil {
+			return err
+		}
+		return putToken(ctx, tx, token)
+	})
+	if err != nil {
+		return models.UserToken{}, err
+	}
+	return token, nil
+}
+
+// ConsumeToken removes and returns a token; each token can be redeemed only once
+func (r *PostgresUserRepository) ConsumeToken(token string, purpose models.TokenPurpose) (models.UserToken, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	var stored models.UserToken
+	err := queryDocument(ctx, r.db, &stored, "Token", `DELETE FROM user_tokens WHERE token = $1 AND purpose = $2 RETURNING data`, token, purpose)
+	if err != nil {
+		return models.UserToken{}, databaseError(err)
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return models.UserToken{}, errors.NewValidationError("Token has expired", "")
+	}
+	return stored, nil
+}
+
+func (r *PostgresUserRepository) CreateAccessToken(token models.PersonalAccessToken) (models.PersonalAccessToken, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	if token.ID == "" {
+		token.ID = uuid.New().String()
+	}
+	token.CreatedAt = storageTime(time.Now())
+	err := r.inTx(ctx, func(tx *sql.Tx) error {
+		if err := userExists(ctx, tx, token.UserID); err != nil {
+			return err
+		}
+		return putAccessToken(ctx, tx, token)
+	})
+	if err != nil {
+		return models.PersonalAccessToken{}, err
+	}
+	return token, nil
+}
+
+func (r *PostgresUserRepository) ListAccessTokens(userID string) ([]models.PersonalAccessToken, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	var tokens []models.PersonalAccessToken
+	err := r.inTx(ctx, func(tx *sql.Tx) error {
+		if err := userExists(ctx, tx, userID); err != nil {
+			return err
+		}
+		rows, err := tx.QueryContext(ctx, `SELECT data, secret_hash FROM access_tokens WHERE user_id = $1 ORDER BY created_at, id`, userID)
+		if err != nil {
+			return err
+		}
+		tokens, err = scanAccessTokens(rows)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (r *PostgresUserRepository) GetAccessTokenByHash(secretHash string) (models.PersonalAccessToken, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `SELECT data, secret_hash FROM access_tokens WHERE secret_hash = $1`, secretHash)
+	if err != nil {
+		return models.PersonalAccessToken{}, databaseError(err)
+	}
+	tokens, err := scanAccessTokens(rows)
+	if err != nil {
+		return models.PersonalAccessToken{}, databaseError(err)
+	}
+	if len(tokens) == 0 {
+		return models.PersonalAccessToken{}, errors.NewNotFoundError("Access token not found")
+	}
+	return tokens[0], nil
+}
+
+func (r *PostgresUserRepository) TouchAccessToken(id string, usedAt time.Time, client models.TokenClient) error {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		var token models.PersonalAccessToken
+		if err := queryDocument(ctx, tx, &token, "Access token", `SELECT data FROM access_tokens WHERE id = $1 FOR UPDATE`, id); err != nil {
+			return err
+		}
+		token.LastUsedAt = &usedAt
+		token.LastUsedFrom = &client
+		data, err := json.Marshal(token)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE access_tokens SET data = $2 WHERE id = $1`, id, data)
+		return err
+	})
+}
+
+func (r *PostgresUserRepository) DeleteAccessToken(userID, id string) error {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM access_tokens WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return databaseError(err)
+	}
+	if deleted, err := result.RowsAffected(); err != nil {
+		return databaseError(err)
+	} else if deleted == 0 {
+		return errors.NewNotFoundError("Access token not found")
+	}
+	return nil
+}
+
+func (r *PostgresUserRepository) DeleteAccessTokens(userID string) (int, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	var deleted int64
+	err := r.inTx(ctx, func(tx *sql.Tx) error {
+		if err := userExists(ctx, tx, userID); err != nil {
+			return err
+		}
+		result, err := tx.ExecContext(ctx, `DELETE FROM access_tokens WHERE user_id = $1`, userID)
+		if err != nil {
+			return err
+		}
+		deleted, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(deleted), nil
+}
+
+// Snapshot returns a copy of every record in a stable order, including access token
+// secret hashes, read in one transaction so the copy is consistent
+func (r *PostgresUserRepository) Snapshot() (models.UserStoreSnapshot, error) {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	var snapshot models.UserStoreSnapshot
+	err := r.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `SET TRANSACTION ISOLATION LEVEL REPEATABLE READ READ ONLY`); err != nil {
+			return err
+		}
+		rows, err := tx.QueryContext(ctx, `SELECT data FROM users ORDER BY created_at, id`)
+		if err != nil {
+			return err
+		}
+		if snapshot.Users, err = scanDocuments[models.User](rows); err != nil {
+			return err
+		}
+		rows, err = tx.QueryContext(ctx, `SELECT data FROM user_tokens ORDER BY created_at, token`)
+		if err != nil {
+			return err
+		}
+		if snapshot.Tokens, err = scanDocuments[models.UserToken](rows); err != nil {
+			return err
+		}
+		rows, err = tx.QueryContext(ctx, `SELECT data, secret_hash FROM access_tokens ORDER BY created_at, id`)
+		if err != nil {
+			return err
+		}
+		tokens, err := scanAccessTokens(rows)
+		if err != nil {
+			return err
+		}
+		snapshot.AccessTokens = make([]models.AccessTokenBackup, 0, len(tokens))
+		for _, token := range tokens {
+			snapshot.AccessTokens = append(snapshot.AccessTokens, models.AccessTokenBackup{PersonalAccessToken: token, SecretHash: token.SecretHash})
+		}
+		return nil
+	})
+	if err != nil {
+		return models.UserStoreSnapshot{}, err
+	}
+	if snapshot.Users == nil {
+		snapshot.Users = []models.User{}
+	}
+	if snapshot.Tokens == nil {
+		snapshot.Tokens = []models.UserToken{}
+	}
+	return snapshot, nil
+}
+
+// Restore replaces every record with the contents of a snapshot in one transaction
+func (r *PostgresUserRepository) Restore(snapshot models.UserStoreSnapshot) error {
+	ctx, cancel := r.context()
+	defer cancel()
+
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `TRUNCATE users, user_tokens, access_tokens`); err != nil {
+			return err
+		}
+		for _, user := range snapshot.Users {
+			if err := putUser(ctx, tx, user); err != nil {
+				return err
+			}
+		}
+		for _, token := range snapshot.Tokens {
+			if err := putToken(ctx, tx, token); err != nil {
+				return err
+			}
+		}
+		for _, backup := range snapshot.AccessTokens {
+			token := backup.PersonalAccessToken
+			token.SecretHash = backup.SecretHash
+			if err := putAccessToken(ctx, tx, token); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
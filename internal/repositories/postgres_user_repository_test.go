@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+)
+
+func TestPostgresUserRepository(t *testing.T) {
+	repo := NewPostgresUserRepository(openTestDatabase(t), 5*time.Second)
+
+	alice, err := repo.Create(models.User{Name: "Alice", Email: "Alice@Example.com"})
+	require.NoError(t, err)
+	assert.False(t, alice.CreatedAt.IsZero())
+
+	// Email addresses are unique whatever their case
+	_, err = repo.Create(models.User{Name: "Other Alice", Email: "alice@example.com"})
+	assert.True(t, errors.Is(err, errors.ErrConflict))
+	found, ok := repo.GetByEmail("ALICE@example.com")
+	require.True(t, ok)
+	assert.Equal(t, alice.ID, found.ID)
+
+	bob, err := repo.Create(models.User{Name: "Bob", Email: "bob@example.com"})
+	require.NoError(t, err)
+	bob.Email = "alice@example.com"
+	_, err = repo.Update(bob)
+	assert.True(t, errors.Is(err, errors.ErrConflict))
+	bob.Email = "robert@example.com"
+	updated, err := repo.Update(bob)
+	require.NoError(t, err)
+	assert.True(t, updated.CreatedAt.Equal(bob.CreatedAt))
+	assert.True(t, updated.UpdatedAt.After(bob.UpdatedAt))
+
+	users, err := repo.GetByIDs([]string{bob.ID, "missing", alice.ID, bob.ID})
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	assert.Equal(t, []string{bob.ID, alice.ID}, []string{users[0].ID, users[1].ID})
+
+	// Tokens can be consumed once, and a new token replaces the outstanding one
+	_, err = repo.CreateToken(models.UserToken{Token: "first", UserID: alice.ID, Purpose: models.TokenPurposeEmailVerification, ExpiresAt: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	_, err = repo.CreateToken(models.UserToken{Token: "second", UserID: alice.ID, Purpose: models.TokenPurposeEmailVerification, ExpiresAt: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	_, err = repo.ConsumeToken("first", models.TokenPurposeEmailVerification)
+	assert.True(t, errors.Is(err, errors.ErrNotFound))
+	_, err = repo.ConsumeToken("second", models.TokenPurposeEmailVerification)
+	require.NoError(t, err)
+	_, err = repo.ConsumeToken("second", models.TokenPurposeEmailVerification)
+	assert.True(t, errors.Is(err, errors.ErrNotFound))
+
+	// Access tokens keep their secret hash out of the document but not out of lookups
+	token, err := repo.CreateAccessToken(models.PersonalAccessToken{UserID: alice.ID, Name: "cli", SecretHash: "hash", Scopes: []models.Scope{models.ScopeReadMeetings}})
+	require.NoError(t, err)
+	byHash, err := repo.GetAccessTokenByHash("hash")
+	require.NoError(t, err)
+	assert.Equal(t, token.ID, byHash.ID)
+	require.NoError(t, repo.TouchAccessToken(token.ID, time.Now(), models.TokenClient{IP: "192.0.2.1", UserAgent: "cli/1.0"}))
+	tokens, err := repo.ListAccessTokens(alice.ID)
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.NotNil(t, tokens[0].LastUsedAt)
+	assert.Equal(t, &models.TokenClient{IP: "192.0.2.1", UserAgent: "cli/1.0"}, tokens[0].LastUsedFrom)
+	_, err = repo.DeleteAccessTokens("missing")
+	assert.True(t, errors.Is(err, errors.ErrNotFound))
+
+	// Snapshots restore every record, and deleting a user deletes their tokens
+	snapshot, err := repo.Snapshot()
+	require.NoError(t, err)
+	require.NoError(t, repo.Delete(alice.ID))
+	_, err = repo.GetAccessTokenByHash("hash")
+	assert.True(t, errors.Is(err, errors.ErrNotFound))
+	require.NoError(t, repo.Restore(snapshot))
+	restored, err := repo.Snapshot()
+	require.NoError(t, err)
+	assert.Equal(t, snapshot, restored)
+}
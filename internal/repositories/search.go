@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"meetsync/internal/models"
+)
+
+// Field weights of the in-memory meeting search; exact word matches score the full weight
+// and prefix matches half of it
+const (
+	titleWeight       = 3.0
+	tagWeight         = 2.0
+	participantWeight = 1.0
+)
+
+// searchTerms splits text into lowercase words
+func searchTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// searchField is a weighted, tokenized part of a meeting
+type searchField struct {
+	name   string
+	weight float64
+	words  []string
+}
+
+// searchFields lists the searchable parts of a meeting
+func searchFields(meeting models.Meeting) []searchField {
+	fields := []searchField{
+		{name: "title", weight: titleWeight, words: searchTerms(meeting.Title)},
+		{name: "tags", weight: tagWeight, words: searchTerms(strings.Join(meeting.Tags, " "))},
+	}
+
+	var names []string
+	if meeting.Organizer != nil {
+		names = append(names, meeting.Organizer.Name)
+	}
+	for _, participant := range meeting.Participants {
+		names = append(names, participant.Name)
+	}
+	return append(fields, searchField{name: "participants", weight: participantWeight, words: searchTerms(strings.Join(names, " "))})
+}
+
+// scoreMeeting scores a meeting against query terms. Every term must match a word of some
+// field; the fields that matched are returned in field order.
+func scoreMeeting(meeting models.Meeting, terms []string) (float64, []string) {
+	fields := searchFields(meeting)
+	matched := make([]bool, len(fields))
+
+	score := 0.0
+	for _, term := range terms {
+		termScore := 0.0
+		for i, field := range fields {
+			for _, word := range field.words {
+				weight := 0.0
+				if word == term {
+					weight = field.weight
+				} else if strings.HasPrefix(word, term) {
+					weight = field.weight / 2
+				}
+				if weight > 0 {
+					termScore += weight
+					matched[i] = true
+				}
+			}
+		}
+		if termScore == 0 {
+			return 0, nil
+		}
+		score += termScore
+	}
+
+	var matchedFields []string
+	for i, field := range fields {
+		if matched[i] {
+			matchedFields = append(matchedFields, field.name)
+		}
+	}
+	return score, matchedFields
+}
+
+// rankSearchResults orders results by score, most recently created first on ties
+func rankSearchResults(results []models.SearchResult) {
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if !results[i].Meeting.CreatedAt.Equal(results[j].Meeting.CreatedAt) {
+			return results[i].Meeting.CreatedAt.After(results[j].Meeting.CreatedAt)
+		}
+		return results[i].Meeting.ID < results[j].Meeting.ID
+	})
+}
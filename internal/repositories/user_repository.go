@@ -1,13 +1,15 @@
 package repositories
 
 import (
-	"strings"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 
 	"meetsync/internal/models"
+	"meetsync/internal/pagination"
+	"meetsync/internal/sanitize"
 	"meetsync/pkg/errors"
 )
 
@@ -15,20 +17,37 @@ import (
 type UserRepository interface {
 	Create(user models.User) (models.User, error)
 	GetByID(id string) (models.User, error)
+	GetByIDs(ids []string) ([]models.User, error)
 	GetAll() ([]models.User, error)
 	GetByEmail(email string) (models.User, bool)
+	Update(user models.User) (models.User, error)
+	Delete(id string) error
+	CreateToken(token models.UserToken) (models.UserToken, error)
+	ConsumeToken(token string, purpose models.TokenPurpose) (models.UserToken, error)
+	CreateAccessToken(token models.PersonalAccessToken) (models.PersonalAccessToken, error)
+	ListAccessTokens(userID string) ([]models.PersonalAccessToken, error)
+	GetAccessTokenByHash(secretHash string) (models.PersonalAccessToken, error)
+	TouchAccessToken(id string, usedAt time.Time, client models.TokenClient) error
+	DeleteAccessToken(userID, id string) error
+	DeleteAccessTokens(userID string) (int, error)
+	Snapshot() (models.UserStoreSnapshot, error)
+	Restore(snapshot models.UserStoreSnapshot) error
 }
 
 // InMemoryUserRepository implements UserRepository using in-memory storage
 type InMemoryUserRepository struct {
-	users map[string]models.User
-	mu    sync.RWMutex
+	users        map[string]models.User
+	tokens       map[string]models.UserToken
+	accessTokens map[string]models.PersonalAccessToken
+	mu           sync.RWMutex
 }
 
 // NewInMemoryUserRepository creates a new InMemoryUserRepository
 func NewInMemoryUserRepository() *InMemoryUserRepository {
 	return &InMemoryUserRepository{
-		users: make(map[string]models.User),
+		users:        make(map[string]models.User),
+		tokens:       make(map[string]models.UserToken),
+		accessTokens: make(map[string]models.PersonalAccessToken),
 	}
 }
 
@@ -38,7 +57,7 @@ func (r *InMemoryUserRepository) Create(user models.User) (models.User, error) {
 
 	// Check if email is already in use
 	for _, existingUser := range r.users {
-		if strings.EqualFold(existingUser.Email, user.Email) {
+		if sanitize.SameEmail(existingUser.Email, user.Email) {
 			return models.User{}, errors.NewConflictError("Email is already in use")
 		}
 	}
@@ -68,6 +87,25 @@ func (r *InMemoryUserRepository) GetByID(id string) (models.User, error) {
 	return user, nil
 }
 
+// GetByIDs returns the users matching ids in a single lookup; unknown IDs are skipped
+func (r *InMemoryUserRepository) GetByIDs(ids []string) ([]models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]models.User, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if user, exists := r.users[id]; exists {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
 func (r *InMemoryUserRepository) GetAll() ([]models.User, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -84,9 +122,241 @@ func (r *InMemoryUserRepository) GetByEmail(email string) (models.User, bool) {
 	defer r.mu.RUnlock()
 
 	for _, user := range r.users {
-		if strings.EqualFold(user.Email, email) {
+		if sanitize.SameEmail(user.Email, email) {
 			return user, true
 		}
 	}
 	return models.User{}, false
 }
+
+func (r *InMemoryUserRepository) Update(user models.User) (models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.users[user.ID]
+	if !exists {
+		return models.User{}, errors.NewNotFoundError("User not found")
+	}
+
+	// Check if email is already in use by another user
+	for _, other := range r.users {
+		if other.ID != user.ID && sanitize.SameEmail(other.Email, user.Email) {
+			return models.User{}, errors.NewConflictError("Email is already in use")
+		}
+	}
+
+	user.CreatedAt = existing.CreatedAt
+	user.UpdatedAt = time.Now()
+	r.users[user.ID] = user
+	return user, nil
+}
+
+func (r *InMemoryUserRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.users[id]; !exists {
+		return errors.NewNotFoundError("User not found")
+	}
+
+	delete(r.users, id)
+
+	// Delete outstanding tokens of the user
+	for value, token := range r.tokens {
+		if token.UserID == id {
+			delete(r.tokens, value)
+		}
+	}
+	for tokenID, token := range r.accessTokens {
+		if token.UserID == id {
+			delete(r.accessTokens, tokenID)
+		}
+	}
+	return nil
+}
+
+// CreateToken stores a token, replacing any outstanding token of the user with the same purpose
+func (r *InMemoryUserRepository) CreateToken(token models.UserToken) (models.UserToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.users[token.UserID]; !exists {
+		return models.UserToken{}, errors.NewNotFoundError("User not found")
+	}
+
+	for value, existing := range r.tokens {
+		if existing.UserID == token.UserID && existing.Purpose == token.Purpose {
+			delete(r.tokens, value)
+		}
+	}
+
+	token.CreatedAt = time.Now()
+	r.tokens[token.Token] = token
+	return token, nil
+}
+
+// ConsumeToken removes and returns a token; each token can be redeemed only once
+func (r *InMemoryUserRepository) ConsumeToken(token string, purpose models.TokenPurpose) (models.UserToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.tokens[token]
+	if !exists || stored.Purpose != purpose {
+		return models.UserToken{}, errors.NewNotFoundError("Token not found")
+	}
+
+	delete(r.tokens, token)
+	if time.Now().After(stored.ExpiresAt) {
+		return models.UserToken{}, errors.NewValidationError("Token has expired", "")
+	}
+	return stored, nil
+}
+
+func (r *InMemoryUserRepository) CreateAccessToken(token models.PersonalAccessToken) (models.PersonalAccessToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.users[token.UserID]; !exists {
+		return models.PersonalAccessToken{}, errors.NewNotFoundError("User not found")
+	}
+
+	if token.ID == "" {
+		token.ID = uuid.New().String()
+	}
+	token.CreatedAt = time.Now()
+
+	r.accessTokens[token.ID] = token
+	return token, nil
+}
+
+func (r *InMemoryUserRepository) ListAccessTokens(userID string) ([]models.PersonalAccessToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, exists := r.users[userID]; !exists {
+		return nil, errors.NewNotFoundError("User not found")
+	}
+
+	tokens := make([]models.PersonalAccessToken, 0)
+	for _, token := range r.accessTokens {
+		if token.UserID == userID {
+			tokens = append(tokens, token)
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].CreatedAt.Before(tokens[j].CreatedAt)
+	})
+	return tokens, nil
+}
+
+func (r *InMemoryUserRepository) GetAccessTokenByHash(secretHash string) (models.PersonalAccessToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, token := range r.accessTokens {
+		if token.SecretHash == secretHash {
+			return token, nil
+		}
+	}
+	return models.PersonalAccessToken{}, errors.NewNotFoundError("Access token not found")
+}
+
+func (r *InMemoryUserRepository) TouchAccessToken(id string, usedAt time.Time, client models.TokenClient) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, exists := r.accessTokens[id]
+	if !exists {
+		return errors.NewNotFoundError("Access token not found")
+	}
+
+	token.LastUsedAt = &usedAt
+	token.LastUsedFrom = &client
+	r.accessTokens[id] = token
+	return nil
+}
+
+func (r *InMemoryUserRepository) DeleteAccessToken(userID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, exists := r.accessTokens[id]
+	if !exists || token.UserID != userID {
+		return errors.NewNotFoundError("Access token not found")
+	}
+
+	delete(r.accessTokens, id)
+	return nil
+}
+
+func (r *InMemoryUserRepository) DeleteAccessTokens(userID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.users[userID]; !exists {
+		return 0, errors.NewNotFoundError("User not found")
+	}
+
+	deleted := 0
+	for id, token := range r.accessTokens {
+		if token.UserID == userID {
+			delete(r.accessTokens, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// Snapshot returns a copy of every record in a stable order, including access token secret hashes
+func (r *InMemoryUserRepository) Snapshot() (models.UserStoreSnapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshot := models.UserStoreSnapshot{
+		Users:        make([]models.User, 0, len(r.users)),
+		Tokens:       make([]models.UserToken, 0, len(r.tokens)),
+		AccessTokens: make([]models.AccessTokenBackup, 0, len(r.accessTokens)),
+	}
+	for _, user := range r.users {
+		snapshot.Users = append(snapshot.Users, user)
+	}
+	sort.Slice(snapshot.Users, func(i, j int) bool {
+		return pagination.Less(snapshot.Users[i].CreatedAt, snapshot.Users[i].ID, snapshot.Users[j].CreatedAt, snapshot.Users[j].ID)
+	})
+	for _, token := range r.tokens {
+		snapshot.Tokens = append(snapshot.Tokens, token)
+	}
+	sort.Slice(snapshot.Tokens, func(i, j int) bool {
+		return pagination.Less(snapshot.Tokens[i].CreatedAt, snapshot.Tokens[i].Token, snapshot.Tokens[j].CreatedAt, snapshot.Tokens[j].Token)
+	})
+	for _, token := range r.accessTokens {
+		snapshot.AccessTokens = append(snapshot.AccessTokens, models.AccessTokenBackup{PersonalAccessToken: token, SecretHash: token.SecretHash})
+	}
+	sort.Slice(snapshot.AccessTokens, func(i, j int) bool {
+		a, b := snapshot.AccessTokens[i], snapshot.AccessTokens[j]
+		return pagination.Less(a.CreatedAt, a.ID, b.CreatedAt, b.ID)
+	})
+	return snapshot, nil
+}
+
+// Restore replaces every record with the contents of a snapshot
+func (r *InMemoryUserRepository) Restore(snapshot models.UserStoreSnapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.users = make(map[string]models.User, len(snapshot.Users))
+	r.tokens = make(map[string]models.UserToken, len(snapshot.Tokens))
+	r.accessTokens = make(map[string]models.PersonalAccessToken, len(snapshot.AccessTokens))
+	for _, user := range snapshot.Users {
+		r.users[user.ID] = user
+	}
+	for _, token := range snapshot.Tokens {
+		r.tokens[token.Token] = token
+	}
+	for _, backup := range snapshot.AccessTokens {
+		token := backup.PersonalAccessToken
+		token.SecretHash = backup.SecretHash
+		r.accessTokens[token.ID] = token
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -123,6 +124,24 @@ func TestInMemoryUserRepository_GetByID(t *testing.T) {
 	}
 }
 
+func TestInMemoryUserRepository_GetByIDs(t *testing.T) {
+	repo := NewInMemoryUserRepository()
+	first, err := repo.Create(models.User{Name: "User 1", Email: "user1@example.com"})
+	require.NoError(t, err)
+	second, err := repo.Create(models.User{Name: "User 2", Email: "user2@example.com"})
+	require.NoError(t, err)
+
+	users, err := repo.GetByIDs([]string{second.ID, "non-existent-id", first.ID, second.ID})
+	assert.NoError(t, err)
+	require.Len(t, users, 2)
+	assert.Equal(t, second.ID, users[0].ID)
+	assert.Equal(t, first.ID, users[1].ID)
+
+	users, err = repo.GetByIDs(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, users)
+}
+
 func TestInMemoryUserRepository_GetByEmail(t *testing.T) {
 	repo := NewInMemoryUserRepository()
 	existingUser := models.User{
@@ -202,6 +221,95 @@ func TestInMemoryUserRepository_GetAll(t *testing.T) {
 	assert.Len(t, users, len(testUsers))
 }
 
+func TestInMemoryUserRepository_Delete(t *testing.T) {
+	repo := NewInMemoryUserRepository()
+	created, err := repo.Create(models.User{Name: "Test User", Email: "test@example.com"})
+	require.NoError(t, err)
+
+	assert.NoError(t, repo.Delete(created.ID))
+	_, err = repo.GetByID(created.ID)
+	assert.Error(t, err)
+
+	// The email can be registered again once the user is deleted
+	_, err = repo.Create(models.User{Name: "Test User", Email: "test@example.com"})
+	assert.NoError(t, err)
+
+	err = repo.Delete("non-existent-id")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "User not found")
+}
+
+func TestInMemoryUserRepository_Update(t *testing.T) {
+	repo := NewInMemoryUserRepository()
+	user, err := repo.Create(models.User{Name: "User 1", Email: "user1@example.com"})
+	require.NoError(t, err)
+	_, err = repo.Create(models.User{Name: "User 2", Email: "user2@example.com"})
+	require.NoError(t, err)
+
+	user.Email = "renamed@example.com"
+	updated, err := repo.Update(user)
+	assert.NoError(t, err)
+	assert.Equal(t, "renamed@example.com", updated.Email)
+	assert.Equal(t, user.CreatedAt, updated.CreatedAt)
+
+	// Email uniqueness is preserved
+	user.Email = "USER2@example.com"
+	_, err = repo.Update(user)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Email is already in use")
+
+	_, err = repo.Update(models.User{ID: "non-existent-id"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "User not found")
+}
+
+func TestInMemoryUserRepository_Tokens(t *testing.T) {
+	repo := NewInMemoryUserRepository()
+	user, err := repo.Create(models.User{Name: "User 1", Email: "user1@example.com"})
+	require.NoError(t, err)
+
+	_, err = repo.CreateToken(models.UserToken{
+		Token:     "first",
+		UserID:    user.ID,
+		Purpose:   models.TokenPurposeEmailChange,
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	// A new token replaces the outstanding one
+	_, err = repo.CreateToken(models.UserToken{
+		Token:     "second",
+		UserID:    user.ID,
+		Purpose:   models.TokenPurposeEmailChange,
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	_, err = repo.ConsumeToken("first", models.TokenPurposeEmailChange)
+	assert.Error(t, err)
+
+	consumed, err := repo.ConsumeToken("second", models.TokenPurposeEmailChange)
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, consumed.UserID)
+	_, err = repo.ConsumeToken("second", models.TokenPurposeEmailChange)
+	assert.Error(t, err)
+
+	// Expired tokens cannot be redeemed
+	_, err = repo.CreateToken(models.UserToken{
+		Token:     "expired",
+		UserID:    user.ID,
+		Purpose:   models.TokenPurposeEmailChange,
+		ExpiresAt: time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+	_, err = repo.ConsumeToken("expired", models.TokenPurposeEmailChange)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Token has expired")
+
+	// Tokens cannot be issued for unknown users
+	_, err = repo.CreateToken(models.UserToken{Token: "orphan", UserID: "non-existent-id"})
+	assert.Error(t, err)
+}
+
 func TestInMemoryUserRepository_ConcurrentOperations(t *testing.T) {
 	repo := NewInMemoryUserRepository()
 	var wg sync.WaitGroup
@@ -0,0 +1,188 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"meetsync/internal/api"
+	"meetsync/internal/contract"
+	"meetsync/internal/filestore"
+	"meetsync/internal/integrations"
+	"meetsync/internal/middleware"
+	"meetsync/internal/models"
+)
+
+// TestRouterContract replays a tour of the API through the contract checker, failing
+// on any request or response the OpenAPI document does not describe. Add requests here
+// when adding endpoints, so the document keeps up with the API.
+func TestRouterContract(t *testing.T) {
+	spec, err := contract.Load("../../docs/openapi.yaml")
+	if err != nil {
+		t.Fatalf("Failed to load the OpenAPI document: %v", err)
+	}
+
+	google := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"calendars":{"primary":{"busy":[]}}}`))
+	}))
+	defer google.Close()
+
+	files, err := filestore.NewLocal(t.TempDir(), "/api/files", []byte("secret"))
+	if err != nil {
+		t.Fatalf("Failed to create file store: %v", err)
+	}
+
+	r := New(WithAdminAPIKey("secret"), WithFaultInjection(true), WithFileStore(files, time.Hour), WithCalendarProviders(map[string]integrations.Provider{
+		integrations.ProviderGoogle: integrations.NewGoogle(integrations.WithGoogleBaseURL(google.URL)),
+	}))
+	r.Setup()
+	defer r.Close()
+	handler := spec.Handler(t, r)
+
+	serve := func(method, path string, body any) *httptest.ResponseRecorder {
+		t.Helper()
+		var payload []byte
+		if body != nil {
+			payload = mustMarshal(body)
+		}
+		req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set(middleware.AdminKeyHeader, "secret")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+	decode := func(w *httptest.ResponseRecorder, v any) {
+		t.Helper()
+		if err := json.NewDecoder(w.Body).Decode(v); err != nil {
+			t.Fatalf("Failed to decode response with status %d: %v", w.Code, err)
+		}
+	}
+
+	// Users
+	var organizer, participant api.CreateUserResponse
+	decode(serve(http.MethodPost, "/api/users", api.CreateUserRequest{Name: "Organizer", Email: "organizer@example.com"}), &organizer)
+	decode(serve(http.MethodPost, "/api/users", api.CreateUserRequest{Name: "Participant", Email: "participant@example.com"}), &participant)
+	serve(http.MethodPost, "/api/users", api.CreateUserRequest{Name: "Duplicate", Email: "organizer@example.com"})
+	serve(http.MethodPost, "/api/users", api.CreateUserRequest{})
+	serve(http.MethodGet, "/api/users", nil)
+	serve(http.MethodGet, "/api/users/"+organizer.User.ID, nil)
+	serve(http.MethodGet, "/api/users/missing", nil)
+	serve(http.MethodPut, "/api/users/"+organizer.User.ID+"/focus-blocks", api.SetFocusBlocksRequest{FocusBlocks: []models.FocusBlock{}})
+	serve(http.MethodPut, "/api/users/"+organizer.User.ID+"/digest", api.SetDigestRequest{Frequency: "daily"})
+	serve(http.MethodPut, "/api/users/"+organizer.User.ID+"/do-not-disturb", api.SetDoNotDisturbRequest{})
+	serve(http.MethodPut, "/api/users/"+organizer.User.ID+"/metadata", api.SetUserMetadataRequest{Metadata: models.Metadata{"crm": "acme"}})
+	serve(http.MethodPost, "/api/users/"+organizer.User.ID+"/tokens", api.CreateAccessTokenRequest{Name: "cli", Scopes: []models.Scope{models.ScopeReadMeetings}})
+	serve(http.MethodGet, "/api/users/"+organizer.User.ID+"/tokens", nil)
+	serve(http.MethodGet, "/api/users/"+organizer.User.ID+"/sessions", nil)
+	serve(http.MethodDelete, "/api/users/"+organizer.User.ID+"/tokens", nil)
+
+	// Meetings
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	slot := models.TimeSlot{StartTime: start, EndTime: start.Add(2 * time.Hour)}
+	var created api.CreateMeetingResponse
+	decode(serve(http.MethodPost, "/api/meetings", api.CreateMeetingRequest{
+		Title:             "Contract review",
+		OrganizerID:       organizer.User.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     []models.TimeSlot{slot},
+		ParticipantIDs:    []string{participant.User.ID},
+		Tags:              []string{"legal"},
+		Metadata:          models.Metadata{"crm": "acme"},
+	}), &created)
+	meetingID := created.Meeting.ID
+	serve(http.MethodPost, "/api/meetings", api.CreateMeetingRequest{OrganizerID: organizer.User.ID, ProposedSlots: []models.TimeSlot{}})
+	serve(http.MethodGet, "/api/meetings?tag=legal", nil)
+	serve(http.MethodGet, "/api/search?q=contract", nil)
+	serve(http.MethodPut, "/api/meetings/"+meetingID, api.UpdateMeetingRequest{Title: "Contract review, part 1"})
+	serve(http.MethodPost, "/api/meetings/"+meetingID+"/tags", api.AddMeetingTagsRequest{Tags: []string{"q3"}})
+	serve(http.MethodDelete, "/api/meetings/"+meetingID+"/tags/q3", nil)
+	serve(http.MethodGet, "/api/meetings/"+meetingID+"/timeline", nil)
+	serve(http.MethodGet, "/api/meetings/"+meetingID+"/ical", nil)
+	streamCtx, endStream := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer endStream()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/meetings/"+meetingID+"/stream", nil).WithContext(streamCtx))
+	serve(http.MethodGet, "/api/meetings/missing/stream", nil)
+	serve(http.MethodPut, "/api/meetings/external/cal-1", api.UpsertExternalMeetingRequest{
+		CreateMeetingRequest: api.CreateMeetingRequest{
+			Title:             "Synced",
+			OrganizerID:       organizer.User.ID,
+			EstimatedDuration: 30,
+			ProposedSlots:     []models.TimeSlot{slot},
+		},
+		ExternalUpdatedAt: start,
+	})
+
+	// Availability and recommendations
+	serve(http.MethodPost, "/api/availabilities", api.AddAvailabilityRequest{
+		UserID:         participant.User.ID,
+		MeetingID:      meetingID,
+		AvailableSlots: []models.TimeSlot{slot},
+	})
+	var availability api.GetAvailabilityResponse
+	decode(serve(http.MethodGet, "/api/availabilities?userId="+participant.User.ID+"&meetingId="+meetingID, nil), &availability)
+	availabilityID := availability.Availability.ID
+	serve(http.MethodPut, "/api/availabilities/"+availabilityID, api.UpdateAvailabilityRequest{AvailableSlots: []models.TimeSlot{slot}})
+	serve(http.MethodPost, "/api/availabilities/"+availabilityID+"/confirm", nil)
+	serve(http.MethodPut, "/api/availabilities/"+availabilityID+"/metadata", api.SetAvailabilityMetadataRequest{ExternalID: "slot-1"})
+	serve(http.MethodGet, "/api/meetings/"+meetingID+"/availabilities", nil)
+	serve(http.MethodPost, "/api/meetings/"+meetingID+"/availabilities/import?provider=google", api.ImportCalendarAvailabilityRequest{
+		UserID:      participant.User.ID,
+		AccessToken: "token",
+	})
+	serve(http.MethodPost, "/api/meetings/"+meetingID+"/availabilities/import?provider=outlook", api.ImportCalendarAvailabilityRequest{
+		UserID:      participant.User.ID,
+		AccessToken: "token",
+	})
+	serve(http.MethodGet, "/api/recommendations?meetingId="+meetingID, nil)
+	serve(http.MethodGet, "/api/recommendations?meetingId=missing", nil)
+	serve(http.MethodPost, "/api/recommendations/batch", api.BatchRecommendationsRequest{MeetingIDs: []string{meetingID}})
+	serve(http.MethodPost, "/api/recommendations/simulate", api.SimulateRecommendationsRequest{MeetingID: meetingID, Duration: 30})
+	var export api.DownloadLinkResponse
+	decode(serve(http.MethodPost, "/api/recommendations/export", api.ExportRecommendationsRequest{MeetingID: meetingID, Format: "csv"}), &export)
+	serve(http.MethodGet, export.URL, nil)
+	serve(http.MethodPost, "/api/scheduling/query", api.SchedulingQueryRequest{
+		ParticipantIDs: []string{organizer.User.ID, participant.User.ID},
+		Duration:       30,
+		Window:         slot,
+	})
+	serve(http.MethodGet, "/api/users/"+participant.User.ID+"/freebusy?from="+start.Format(time.RFC3339)+"&to="+start.Add(time.Hour).Format(time.RFC3339), nil)
+
+	// Statistics, health and administration
+	serve(http.MethodGet, "/api/stats/slo", nil)
+	serve(http.MethodGet, "/api/stats/tags", nil)
+	serve(http.MethodGet, "/api/usage", nil)
+	serve(http.MethodGet, "/api/usage/summary", nil)
+	serve(http.MethodGet, "/healthz", nil)
+	serve(http.MethodGet, "/readyz", nil)
+	serve(http.MethodGet, "/status", nil)
+	serve(http.MethodGet, "/api/version", nil)
+	serve(http.MethodPut, "/api/admin/bootstrap", models.Bootstrap{
+		Admin:  models.BootstrapAdmin{Name: "Ops", Email: "ops@example.com"},
+		Tokens: []models.BootstrapToken{{Name: "deploy", Scopes: []models.Scope{models.ScopeReadMeetings}}},
+	})
+	serve(http.MethodGet, "/api/admin/audit", nil)
+	serve(http.MethodGet, "/api/admin/config", nil)
+	var backup api.DownloadLinkResponse
+	decode(serve(http.MethodPost, "/api/admin/backups", nil), &backup)
+	serve(http.MethodGet, backup.URL, nil)
+	serve(http.MethodGet, backup.URL+"0", nil)
+	serve(http.MethodPut, "/api/admin/faults", api.SetFaultsRequest{Rules: []middleware.FaultRule{{Route: "/api/nowhere", ErrorRate: 1}}})
+	serve(http.MethodGet, "/api/admin/faults", nil)
+	serve(http.MethodDelete, "/api/admin/faults", nil)
+	var webhook api.CreateWebhookResponse
+	decode(serve(http.MethodPost, "/api/webhooks", api.CreateWebhookRequest{URL: "https://example.com/hooks"}), &webhook)
+	serve(http.MethodGet, "/api/webhooks", nil)
+	serve(http.MethodGet, "/api/webhooks/"+webhook.Webhook.ID+"/deliveries", nil)
+	serve(http.MethodDelete, "/api/webhooks/"+webhook.Webhook.ID, nil)
+
+	// Cleanup
+	serve(http.MethodDelete, "/api/availabilities/"+availabilityID, nil)
+	serve(http.MethodDelete, "/api/meetings/"+meetingID, nil)
+}
@@ -1,76 +1,796 @@
 package router
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"time"
 
+	"meetsync/internal/config"
+	"meetsync/internal/directory"
+	"meetsync/internal/events"
+	"meetsync/internal/filestore"
+	"meetsync/internal/guest"
 	"meetsync/internal/handlers"
+	"meetsync/internal/health"
+	"meetsync/internal/integrations"
+	"meetsync/internal/interfaces"
+	"meetsync/internal/jobs"
+	"meetsync/internal/metering"
+	"meetsync/internal/metrics"
 	"meetsync/internal/middleware"
+	"meetsync/internal/models"
+	"meetsync/internal/notifications"
+	"meetsync/internal/policy"
+	"meetsync/internal/precompute"
+	"meetsync/internal/quota"
+	"meetsync/internal/repositories"
+	"meetsync/internal/rsvp"
+	"meetsync/internal/sanitize"
+	"meetsync/internal/services"
+	"meetsync/internal/version"
+	"meetsync/internal/webhooks"
 	"meetsync/pkg/logs"
 )
 
 // Router handles HTTP routing
 type Router struct {
-	mux *http.ServeMux
+	mux        *http.ServeMux
+	handler    http.Handler // mux behind path normalization, set up by Setup
+	publisher  events.Publisher
+	sloTracker *metrics.SLOTracker
+	notifier   notifications.Notifier
+	dispatcher *notifications.Dispatcher
+	templates  *notifications.Templates
+	policies   *policy.Engine
+	adminKey   string
+	scimToken  string
+	writeGate  *middleware.WriteGate
+	stateCheck health.StateCheck
+	storage    *health.StorageMonitor
+	statusRate *middleware.RateLimiter
+	faults     *middleware.FaultInjector
+	accessLog  func(http.Handler) http.Handler
+	settings   []config.Setting
+	quotas     *quota.Tracker
+	meter      *metering.Meter
+	rsvpSigner *rsvp.Signer
+	guests     *guest.Signer
+	publicURL  string
+	pollURL    string
+	directory  directory.Directory
+	admin      interfaces.AdminService
+	meetings   interfaces.MeetingService
+	precompute precompute.Queue
+	worker     precompute.Consumer
+	jobs       *jobs.Queue
+	webhooks   *webhooks.Dispatcher
+	streams    context.Context // ends open recommendation streams once cancelled
+	endStreams context.CancelFunc
+	dbPool     metrics.PoolStatser
+	workHours  *models.WorkingHours
+	dupWindow  time.Duration
+	repos      repositories.Storage
+	calendars  map[string]integrations.Provider
+	files      filestore.Store
+	fileTTL    time.Duration
+
+	requireVerifiedEmail       bool
+	textLimits                 sanitize.Limits
+	materializeRecommendations bool
+	readOnly                   bool
+	slotGranularity            time.Duration
+	maxRequestTimeout          time.Duration
+	debugRoutes                []string
+	availabilityDedupWindow    time.Duration
+	directorySync              models.DirectorySyncOptions
+	directorySyncInterval      time.Duration
+	digestInterval             time.Duration
+	userCacheTTL               time.Duration
+}
+
+// Option configures optional Router dependencies
+type Option func(*Router)
+
+// readRoutes only read state although they are served for POST, so read-only instances
+// still serve them
+var readRoutes = []string{
+	"POST /api/recommendations/batch",
+	"POST /api/recommendations/simulate",
+	"POST /api/recommendations/export",
+	"POST /api/scheduling/query",
+}
+
+// WithEventPublisher sets the publisher used to emit scheduling events
+func WithEventPublisher(publisher events.Publisher) Option {
+	return func(r *Router) {
+		r.publisher = publisher
+	}
+}
+
+// WithNotifier sets the notifier used to invite meeting participants
+func WithNotifier(notifier notifications.Notifier) Option {
+	return func(r *Router) {
+		r.notifier = notifier
+	}
+}
+
+// WithNotificationTemplates sets the templates rendering every email notification, the
+// built-in ones by default
+func WithNotificationTemplates(templates *notifications.Templates) Option {
+	return func(r *Router) {
+		r.templates = templates
+	}
+}
+
+// WithPolicies sets the scheduling policies checked for recommended slots
+func WithPolicies(policies *policy.Engine) Option {
+	return func(r *Router) {
+		r.policies = policies
+	}
+}
+
+// WithAdminAPIKey sets the key required to call admin endpoints; admin endpoints are disabled without it
+func WithAdminAPIKey(key string) Option {
+	return func(r *Router) {
+		r.adminKey = key
+	}
+}
+
+// WithSCIM enables SCIM 2.0 user provisioning for identity providers authenticating
+// with token, serving resource locations under publicURL
+func WithSCIM(token string, publicURL string) Option {
+	return func(r *Router) {
+		r.scimToken = token
+		r.publicURL = publicURL
+	}
+}
+
+// WithDirectorySync synchronizes users from dir every interval, reconciling them with
+// existing users according to options; zero only syncs on demand
+func WithDirectorySync(dir directory.Directory, options models.DirectorySyncOptions, interval time.Duration) Option {
+	return func(r *Router) {
+		r.directory = dir
+		r.directorySync = options
+		r.directorySyncInterval = interval
+	}
+}
+
+// WithDigests checks every interval for users due a daily or weekly digest and sends it;
+// zero only sends digests on demand
+func WithDigests(interval time.Duration) Option {
+	return func(r *Router) {
+		r.digestInterval = interval
+	}
+}
+
+// WithUserCache caches users looked up by ID for ttl; zero disables the cache
+func WithUserCache(ttl time.Duration) Option {
+	return func(r *Router) {
+		r.userCacheTTL = ttl
+	}
+}
+
+// WithDatabasePool exposes the statistics of the connection pool of a SQL storage
+// backend, such as a *sql.DB, on the metrics endpoint
+func WithDatabasePool(pool metrics.PoolStatser) Option {
+	return func(r *Router) {
+		r.dbPool = pool
+	}
+}
+
+// WithStorage stores users, meetings and availability in repositories opened by a
+// registered storage driver instead of in process memory
+func WithStorage(storage repositories.Storage) Option {
+	return func(r *Router) {
+		r.repos = storage
+	}
+}
+
+// WithEmailVerificationRequired prevents users with unverified emails from organizing meetings
+func WithEmailVerificationRequired(required bool) Option {
+	return func(r *Router) {
+		r.requireVerifiedEmail = required
+	}
+}
+
+// WithTextLimits sets the maximum lengths of free-text fields
+func WithTextLimits(limits sanitize.Limits) Option {
+	return func(r *Router) {
+		r.textLimits = limits
+	}
+}
+
+// WithMaterializedRecommendations stores recommendations whenever availability changes
+// instead of computing them on every read
+func WithMaterializedRecommendations(enabled bool) Option {
+	return func(r *Router) {
+		r.materializeRecommendations = enabled
+	}
+}
+
+// WithSlotGranularity splits proposed windows longer than a meeting into sub-slots starting every granularity
+func WithSlotGranularity(granularity time.Duration) Option {
+	return func(r *Router) {
+		r.slotGranularity = granularity
+	}
+}
+
+// WithWorkingHours warns about proposed slots outside hours; nil disables the check
+func WithWorkingHours(hours *models.WorkingHours) Option {
+	return func(r *Router) {
+		r.workHours = hours
+	}
+}
+
+// WithDuplicateDetection warns when a new meeting looks like one its organizer already
+// created with slots within window; zero disables the check
+func WithDuplicateDetection(window time.Duration) Option {
+	return func(r *Router) {
+		r.dupWindow = window
+	}
+}
+
+// WithStateCheck sets the result of the startup storage check reported by /readyz
+func WithStateCheck(check health.StateCheck) Option {
+	return func(r *Router) {
+		r.stateCheck = check
+	}
+}
+
+// WithStatusRateLimit sets how many requests each client can make per minute to the
+// public status page; zero disables the limit
+func WithStatusRateLimit(perMinute int) Option {
+	return func(r *Router) {
+		r.statusRate = middleware.NewRateLimiter(perMinute)
+	}
+}
+
+// WithFaultInjection allows injecting latency and errors into requests through the
+// admin API, to test clients in staging
+func WithFaultInjection(enabled bool) Option {
+	return func(r *Router) {
+		r.faults = middleware.NewFaultInjector(enabled)
+	}
+}
+
+// WithAccessLog adds an access log middleware, such as one created by middleware.AccessLog
+func WithAccessLog(accessLog func(http.Handler) http.Handler) Option {
+	return func(r *Router) {
+		r.accessLog = accessLog
+	}
+}
+
+// WithDebugCapture logs the sanitized request and response bodies of requests to the
+// given route prefixes
+func WithDebugCapture(routes []string) Option {
+	return func(r *Router) {
+		r.debugRoutes = routes
+	}
+}
+
+// WithConfigSettings sets the resolved configuration shown by GET /api/admin/config
+func WithConfigSettings(settings []config.Setting) Option {
+	return func(r *Router) {
+		r.settings = settings
+	}
+}
+
+// WithAvailabilityDeduplication collapses identical availability submissions from the
+// same caller arriving within window into one; zero disables it
+func WithAvailabilityDeduplication(window time.Duration) Option {
+	return func(r *Router) {
+		r.availabilityDedupWindow = window
+	}
+}
+
+// WithQuotas sets the usage quotas of the organization
+func WithQuotas(limits quota.Limits) Option {
+	return func(r *Router) {
+		r.quotas = quota.NewTracker(limits)
+	}
+}
+
+// WithMeteringSink sets where usage is metered for billing
+func WithMeteringSink(sink metering.Sink) Option {
+	return func(r *Router) {
+		r.meter = metering.NewMeter(sink)
+	}
+}
+
+// WithRSVPLinks adds one-click RSVP links signed by signer to invitations, pointing at
+// the API served from publicURL
+func WithRSVPLinks(signer *rsvp.Signer, publicURL string) Option {
+	return func(r *Router) {
+		r.rsvpSigner = signer
+		r.publicURL = publicURL
+	}
+}
+
+// WithGuestLinks lets organizers invite guests from outside the organization, who access
+// their meeting through tokens signed by signer, pointing at the API served from publicURL
+func WithGuestLinks(signer *guest.Signer, publicURL string) Option {
+	return func(r *Router) {
+		r.guests = signer
+		r.publicURL = publicURL
+	}
+}
+
+// WithPollURL sets the URL of the page collecting availability for a meeting, with {id}
+// standing for the meeting ID, encoded by poll QR codes
+func WithPollURL(template string) Option {
+	return func(r *Router) {
+		r.pollURL = template
+	}
+}
+
+// WithCalendarProviders sets the calendar providers availability can be imported from,
+// replacing the default ones
+func WithCalendarProviders(providers map[string]integrations.Provider) Option {
+	return func(r *Router) {
+		r.calendars = providers
+	}
+}
+
+// WithPrecomputeQueue hands off refreshing stored recommendations to scheduler workers
+// through queue
+func WithPrecomputeQueue(queue precompute.Queue) Option {
+	return func(r *Router) {
+		r.precompute = queue
+	}
+}
+
+// WithPrecomputeWorker runs the instance as a scheduler worker computing the recommendations
+// of the precompute jobs consumer delivers
+func WithPrecomputeWorker(consumer precompute.Consumer) Option {
+	return func(r *Router) {
+		r.worker = consumer
+	}
+}
+
+// WithMaxRequestTimeout caps the deadline clients can ask for with the X-Request-Timeout
+// or Request-Timeout header; zero ignores the headers
+func WithMaxRequestTimeout(max time.Duration) Option {
+	return func(r *Router) {
+		r.maxRequestTimeout = max
+	}
+}
+
+// WithFileStore keeps stored exports and backups in store, linking to them with signed
+// URLs valid for ttl. Files of a local store are served from /api/files.
+func WithFileStore(store filestore.Store, ttl time.Duration) Option {
+	return func(r *Router) {
+		r.files = store
+		r.fileTTL = ttl
+	}
+}
+
+// WithReadOnly rejects requests that change state with 503 Service Unavailable and
+// skips background jobs, for instances serving reads from a replica of the primary's storage
+func WithReadOnly(enabled bool) Option {
+	return func(r *Router) {
+		r.readOnly = enabled
+	}
 }
 
 // New creates a new Router
-func New() *Router {
-	return &Router{
-		mux: http.NewServeMux(),
+func New(opts ...Option) *Router {
+	r := &Router{
+		mux:        http.NewServeMux(),
+		publisher:  events.NoopPublisher{},
+		sloTracker: metrics.NewSLOTracker(),
+		notifier:   notifications.NoopNotifier{},
+		textLimits: sanitize.DefaultLimits,
+		writeGate:  middleware.NewWriteGate(),
+		stateCheck: health.CheckState(health.StorageMemory, false, 1, health.ProcessLocal),
+		storage:    health.NewStorageMonitor(),
+		statusRate: middleware.NewRateLimiter(60),
+		faults:     middleware.NewFaultInjector(false),
+		quotas:     quota.NewTracker(quota.Limits{}),
+		meter:      metering.NewMeter(metering.NoopSink{}),
+		calendars:  integrations.DefaultProviders(),
+
+		maxRequestTimeout: 10 * time.Second,
+		fileTTL:           15 * time.Minute,
 	}
+	r.streams, r.endStreams = context.WithCancel(context.Background())
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.readOnly {
+		r.writeGate.SetReadOnly(readRoutes...)
+	}
+	r.jobs = jobs.NewQueue(r.writeGate.Run)
+	r.webhooks = webhooks.NewDispatcher()
+	return r
 }
 
 // Setup sets up all routes
 func (r *Router) Setup() {
+	// Meter delivered notifications; discarded ones are not billed
+	notifier := r.notifier
+	if _, discarded := notifier.(notifications.NoopNotifier); !discarded {
+		notifier = metering.Notifier(notifier, r.meter)
+	}
+
+	// Store data in the configured storage, in process memory by default
+	userOptions := []services.UserServiceOption{
+		services.WithUserNotifier(notifier),
+		services.WithUserTextLimits(r.textLimits),
+		services.WithUserStorageMonitor(r.storage),
+		services.WithUserCacheTTL(r.userCacheTTL),
+	}
+	var meetingOptions []services.MeetingServiceOption
+	if r.repos.Users != nil {
+		userOptions = append(userOptions, services.WithUserRepository(r.repos.Users))
+	}
+	if r.repos.Meetings != nil {
+		meetingOptions = append(meetingOptions, services.WithMeetingRepository(r.repos.Meetings))
+	}
+	if r.templates != nil {
+		userOptions = append(userOptions, services.WithUserNotificationTemplates(r.templates))
+		meetingOptions = append(meetingOptions, services.WithNotificationTemplates(r.templates))
+	}
+
+	// Create services. Notifications about meetings wait for the quiet hours of their
+	// recipients to end; account emails are always sent right away.
+	userService := services.NewUserService(userOptions...)
+	r.dispatcher = notifications.NewDispatcher(notifier, services.QuietHoursOf(userService))
+	meetingService := services.NewMeetingService(userService, append(meetingOptions,
+		services.WithEventPublisher(events.FanoutPublisher{r.publisher, r.webhooks}),
+		services.WithSLOTracker(r.sloTracker),
+		services.WithNotifier(r.dispatcher),
+		services.WithPolicies(r.policies),
+		services.WithEmailVerificationRequired(r.requireVerifiedEmail),
+		services.WithTextLimits(r.textLimits),
+		services.WithMaterializedRecommendations(r.materializeRecommendations),
+		services.WithReadOnlyStorage(r.readOnly),
+		services.WithSharedStorage(r.repos.Shared),
+		services.WithSlotGranularity(r.slotGranularity),
+		services.WithStorageMonitor(r.storage),
+		services.WithQuotas(r.quotas),
+		services.WithMeter(r.meter),
+		services.WithRSVPLinks(r.rsvpSigner, r.publicURL),
+		services.WithPollURL(r.pollURL),
+		services.WithWorkingHours(r.workHours),
+		services.WithDuplicateDetection(r.dupWindow),
+		services.WithGuestLinks(r.guests, r.publicURL),
+		services.WithCalendarProviders(r.calendars),
+		services.WithPrecomputeQueue(r.precompute),
+	)...)
+	r.meetings = meetingService
+	var adminOptions []services.AdminServiceOption
+	if r.repos.Audit != nil {
+		adminOptions = append(adminOptions, services.WithAuditRepository(r.repos.Audit))
+	}
+	if r.directory != nil {
+		adminOptions = append(adminOptions, services.WithDirectory(r.directory, r.directorySync))
+	}
+	r.admin = services.NewAdminService(userService, meetingService, adminOptions...)
+	schedulingService := services.NewSchedulingService(userService,
+		services.WithQueryGranularity(r.slotGranularity),
+		services.WithQueryPolicies(r.policies),
+		services.WithQueryMeter(r.meter),
+	)
+
 	// Create handlers
-	userHandler := handlers.NewUserHandler()
-	meetingHandler := handlers.NewMeetingHandler(userHandler)
+	userHandler := handlers.NewUserHandler(userService)
+	fileLinks := handlers.NewFileLinks(r.files, r.fileTTL)
+	meetingHandler := handlers.NewMeetingHandler(meetingService, fileLinks)
+	statsHandler := handlers.NewStatsHandler(r.sloTracker, r.dbPool)
+	healthHandler := handlers.NewHealthHandler(r.stateCheck, r.storage)
+	configHandler := handlers.NewConfigHandler(r.settings)
+	usageHandler := handlers.NewUsageHandler(r.quotas, r.meter)
+	adminHandler := handlers.NewAdminHandler(r.admin, fileLinks)
+	batchHandler := handlers.NewBatchHandler(meetingService, r.jobs)
+	webhookHandler := handlers.NewWebhookHandler(r.webhooks)
+	faultHandler := handlers.NewFaultHandler(r.faults)
+	scimHandler := handlers.NewSCIMHandler(userService, r.publicURL)
+	schedulingHandler := handlers.NewSchedulingHandler(schedulingService)
 
 	// Register user routes with error handling
 	r.mux.HandleFunc("POST /api/users", middleware.WithErrorHandling(userHandler.CreateUser))
 	r.mux.HandleFunc("GET /api/users", middleware.WithErrorHandling(userHandler.ListUsers))
 	r.mux.HandleFunc("GET /api/users/{id}", middleware.WithErrorHandling(userHandler.GetUser))
+	r.mux.HandleFunc("POST /api/users/{id}/email", middleware.WithErrorHandling(userHandler.RequestEmailChange))
+	r.mux.HandleFunc("POST /api/users/email/confirm", middleware.WithErrorHandling(userHandler.ConfirmEmailChange))
+	r.mux.HandleFunc("POST /api/users/verify", middleware.WithErrorHandling(userHandler.VerifyEmail))
+	r.mux.HandleFunc("POST /api/users/{id}/verify/resend", middleware.WithErrorHandling(userHandler.ResendVerification))
+	r.mux.HandleFunc("PUT /api/users/{id}/focus-blocks", middleware.WithErrorHandling(userHandler.SetFocusBlocks))
+	r.mux.HandleFunc("PUT /api/users/{id}/digest", middleware.WithErrorHandling(userHandler.SetDigest))
+	r.mux.HandleFunc("PUT /api/users/{id}/do-not-disturb", middleware.WithErrorHandling(userHandler.SetDoNotDisturb))
+	r.mux.HandleFunc("PUT /api/users/{id}/metadata", middleware.WithErrorHandling(userHandler.SetMetadata))
+
+	// Managing tokens takes one of the user's tokens, or the admin key to mint the first one
+	tokenOwner := func(handler middleware.ErrorHandler) http.HandlerFunc {
+		return middleware.WithErrorHandling(middleware.RequireAccessToken(userService, r.adminKey, handler))
+	}
+	r.mux.HandleFunc("POST /api/users/{id}/tokens", tokenOwner(userHandler.CreateAccessToken))
+	r.mux.HandleFunc("GET /api/users/{id}/tokens", tokenOwner(userHandler.ListAccessTokens))
+	r.mux.HandleFunc("DELETE /api/users/{id}/tokens", tokenOwner(userHandler.RevokeAccessTokens))
+	r.mux.HandleFunc("DELETE /api/users/{id}/tokens/{tokenId}", tokenOwner(userHandler.RevokeAccessToken))
+	r.mux.HandleFunc("GET /api/users/{id}/sessions", tokenOwner(userHandler.ListSessions))
 
-	// Register meeting routes with error handling
-	r.mux.HandleFunc("POST /api/meetings", middleware.WithErrorHandling(meetingHandler.CreateMeeting))
-	r.mux.HandleFunc("PUT /api/meetings/{id}", middleware.WithErrorHandling(meetingHandler.UpdateMeeting))
-	r.mux.HandleFunc("DELETE /api/meetings/{id}", middleware.WithErrorHandling(meetingHandler.DeleteMeeting))
+	// Register meeting routes with error handling; personal access tokens need the matching scope
+	scoped := func(scope models.Scope, handler middleware.ErrorHandler) http.HandlerFunc {
+		return middleware.WithErrorHandling(middleware.RequireScope(userService, scope, handler))
+	}
+	// Token owners can only change meetings they organize and availability they submitted
+	organizerOnly := func(handler middleware.ErrorHandler) middleware.ErrorHandler {
+		return middleware.RequireAuthorization(func(userID string, r *http.Request) error {
+			return meetingService.AuthorizeMeetingChange(userID, r.PathValue("id"))
+		}, handler)
+	}
+	participantOnly := func(handler middleware.ErrorHandler) middleware.ErrorHandler {
+		return middleware.RequireAuthorization(func(userID string, r *http.Request) error {
+			return meetingService.AuthorizeAvailabilityChange(userID, r.PathValue("id"))
+		}, handler)
+	}
+	r.mux.HandleFunc("POST /api/meetings", scoped(models.ScopeWriteMeetings, meetingHandler.CreateMeeting))
+	r.mux.HandleFunc("GET /api/meetings", scoped(models.ScopeReadMeetings, meetingHandler.ListMeetings))
+	r.mux.HandleFunc("GET /api/search", scoped(models.ScopeReadMeetings, meetingHandler.SearchMeetings))
+	r.mux.HandleFunc("PUT /api/meetings/{id}", scoped(models.ScopeWriteMeetings, organizerOnly(meetingHandler.UpdateMeeting)))
+	r.mux.HandleFunc("PUT /api/meetings/external/{externalId}", scoped(models.ScopeWriteMeetings, meetingHandler.UpsertExternalMeeting))
+	r.mux.HandleFunc("DELETE /api/meetings/{id}", scoped(models.ScopeWriteMeetings, organizerOnly(meetingHandler.DeleteMeeting)))
+	r.mux.HandleFunc("DELETE /api/meetings", scoped(models.ScopeWriteMeetings, batchHandler.DeleteMeetings))
+	r.mux.HandleFunc("POST /api/meetings/batch", scoped(models.ScopeWriteMeetings, batchHandler.ApplyBatch))
+	r.mux.HandleFunc("GET /api/jobs/{id}", scoped(models.ScopeReadMeetings, batchHandler.GetJob))
+	r.mux.HandleFunc("POST /api/meetings/{id}/publish", scoped(models.ScopeWriteMeetings, organizerOnly(meetingHandler.PublishMeeting)))
+	r.mux.HandleFunc("POST /api/meetings/{id}/confirm", scoped(models.ScopeWriteMeetings, organizerOnly(meetingHandler.ConfirmMeeting)))
+	r.mux.HandleFunc("POST /api/meetings/{id}/cancel", scoped(models.ScopeWriteMeetings, organizerOnly(meetingHandler.CancelMeeting)))
+	r.mux.HandleFunc("POST /api/meetings/{id}/proposals", scoped(models.ScopeWriteAvailability, meetingHandler.ProposeAlternative))
+	r.mux.HandleFunc("POST /api/meetings/{id}/proposals/{proposalId}/decision", scoped(models.ScopeWriteMeetings, organizerOnly(meetingHandler.DecideProposal)))
+	r.mux.HandleFunc("GET /api/meetings/{id}/timeline", scoped(models.ScopeReadMeetings, meetingHandler.GetMeetingTimeline))
+	r.mux.HandleFunc("GET /api/meetings/{id}/stream", scoped(models.ScopeReadMeetings, r.stream(meetingHandler.StreamRecommendations)))
+	r.mux.HandleFunc("GET /api/meetings/{id}/ical", scoped(models.ScopeReadMeetings, meetingHandler.ExportCalendarEvent))
+	r.mux.HandleFunc("GET /api/meetings/{id}/poll/qr", scoped(models.ScopeReadMeetings, meetingHandler.GetPollQRCode))
+	r.mux.HandleFunc("GET /meetings/{id}/summary", scoped(models.ScopeReadMeetings, meetingHandler.GetMeetingSummary))
+	r.mux.HandleFunc("POST /api/meetings/{id}/reconfirm", scoped(models.ScopeWriteMeetings, organizerOnly(meetingHandler.RequestReconfirmation)))
+	r.mux.HandleFunc("POST /api/meetings/{id}/guests", scoped(models.ScopeWriteMeetings, organizerOnly(meetingHandler.AddGuest)))
+	r.mux.HandleFunc("POST /api/meetings/{id}/tags", scoped(models.ScopeWriteMeetings, organizerOnly(meetingHandler.AddMeetingTags)))
+	r.mux.HandleFunc("DELETE /api/meetings/{id}/tags/{tag}", scoped(models.ScopeWriteMeetings, organizerOnly(meetingHandler.RemoveMeetingTag)))
 
-	// Register availability routes with error handling
-	r.mux.HandleFunc("POST /api/availabilities", middleware.WithErrorHandling(meetingHandler.AddAvailability))
-	r.mux.HandleFunc("GET /api/availabilities", middleware.WithErrorHandling(meetingHandler.GetAvailability))
-	r.mux.HandleFunc("PUT /api/availabilities/{id}", middleware.WithErrorHandling(meetingHandler.UpdateAvailability))
-	r.mux.HandleFunc("DELETE /api/availabilities/{id}", middleware.WithErrorHandling(meetingHandler.DeleteAvailability))
+	// Register availability routes with error handling; submissions are deduplicated
+	dedup := middleware.NewDeduplicator(r.availabilityDedupWindow)
+	r.mux.Handle("POST /api/availabilities", dedup.Wrap(scoped(models.ScopeWriteAvailability, meetingHandler.AddAvailability)))
+	r.mux.HandleFunc("POST /api/availabilities/import", scoped(models.ScopeWriteAvailability, meetingHandler.ImportAvailability))
+	r.mux.HandleFunc("GET /api/availabilities", scoped(models.ScopeReadMeetings, meetingHandler.GetAvailability))
+	r.mux.Handle("PUT /api/availabilities/{id}", dedup.Wrap(scoped(models.ScopeWriteAvailability, participantOnly(meetingHandler.UpdateAvailability))))
+	r.mux.HandleFunc("DELETE /api/availabilities/{id}", scoped(models.ScopeWriteAvailability, participantOnly(meetingHandler.DeleteAvailability)))
+	r.mux.HandleFunc("POST /api/availabilities/{id}/confirm", scoped(models.ScopeWriteAvailability, participantOnly(meetingHandler.ConfirmAvailability)))
+	r.mux.HandleFunc("PUT /api/availabilities/{id}/metadata", scoped(models.ScopeWriteAvailability, participantOnly(meetingHandler.SetAvailabilityMetadata)))
+	r.mux.HandleFunc("GET /api/meetings/{id}/availabilities", scoped(models.ScopeReadMeetings, meetingHandler.ListAvailabilities))
+	r.mux.HandleFunc("POST /api/meetings/{id}/availabilities/import", scoped(models.ScopeWriteAvailability, meetingHandler.ImportCalendarAvailability))
+
+	// Register the one-click RSVP route; the signed token stands in for authentication
+	r.mux.HandleFunc("GET /api/rsvp/{token}", middleware.WithErrorHandling(r.writeGate.Write(meetingHandler.RespondToInvitation)))
+
+	// Register guest routes; the guest token stands in for authentication and only gives
+	// access to the meeting it was issued for
+	r.mux.HandleFunc("GET /api/guest/{token}", middleware.WithErrorHandling(meetingHandler.GetGuestMeeting))
+	r.mux.HandleFunc("PUT /api/guest/{token}/availability", middleware.WithErrorHandling(meetingHandler.SubmitGuestAvailability))
 
 	// Register recommendations route with error handling
-	r.mux.HandleFunc("GET /api/recommendations", middleware.WithErrorHandling(meetingHandler.GetRecommendations))
+	r.mux.HandleFunc("GET /api/recommendations", scoped(models.ScopeReadMeetings, meetingHandler.GetRecommendations))
+	r.mux.HandleFunc("POST /api/recommendations/batch", scoped(models.ScopeReadMeetings, meetingHandler.GetBatchRecommendations))
+	r.mux.HandleFunc("POST /api/recommendations/simulate", scoped(models.ScopeReadMeetings, meetingHandler.SimulateRecommendations))
+	r.mux.HandleFunc("POST /api/recommendations/export", scoped(models.ScopeReadMeetings, meetingHandler.ExportRecommendations))
+
+	// Register scheduling routes with error handling
+	r.mux.HandleFunc("POST /api/scheduling/query", scoped(models.ScopeReadMeetings, schedulingHandler.FindTimes))
+	r.mux.HandleFunc("GET /api/users/{id}/freebusy", scoped(models.ScopeReadMeetings, schedulingHandler.GetFreeBusy))
+
+	// Register statistics and metrics routes with error handling
+	r.mux.HandleFunc("GET /api/stats/slo", middleware.WithErrorHandling(statsHandler.GetSLOStats))
+	r.mux.HandleFunc("GET /api/stats/tags", scoped(models.ScopeReadMeetings, meetingHandler.GetTagStats))
+	r.mux.HandleFunc("GET /metrics", middleware.WithErrorHandling(statsHandler.GetMetrics))
+	r.mux.HandleFunc("GET /api/usage", middleware.WithErrorHandling(usageHandler.GetUsage))
+	r.mux.HandleFunc("GET /api/usage/summary", middleware.WithErrorHandling(usageHandler.GetUsageSummary))
+
+	// Register admin routes with error handling, guarded by the admin API key
+	r.mux.HandleFunc("POST /api/admin/users/merge", middleware.WithErrorHandling(middleware.RequireAdminKey(r.adminKey, adminHandler.MergeUsers)))
+	r.mux.HandleFunc("POST /api/admin/users/import", middleware.WithErrorHandling(middleware.RequireAdminKey(r.adminKey, adminHandler.ImportUsers)))
+	r.mux.HandleFunc("PUT /api/admin/bootstrap", middleware.WithErrorHandling(middleware.RequireAdminKey(r.adminKey, adminHandler.Bootstrap)))
+	r.mux.HandleFunc("POST /api/admin/directory/sync", middleware.WithErrorHandling(middleware.RequireAdminKey(r.adminKey, adminHandler.SyncDirectory)))
+	r.mux.HandleFunc("POST /api/admin/digests", middleware.WithErrorHandling(middleware.RequireAdminKey(r.adminKey, adminHandler.SendDigests)))
+	r.mux.HandleFunc("GET /api/admin/audit", middleware.WithErrorHandling(middleware.RequireAdminKey(r.adminKey, adminHandler.ListAuditLog)))
+	r.mux.HandleFunc("POST /api/admin/backup/verify", middleware.WithErrorHandling(middleware.RequireAdminKey(r.adminKey, adminHandler.VerifyBackup)))
+	r.mux.HandleFunc("GET /api/admin/config", middleware.WithErrorHandling(middleware.RequireAdminKey(r.adminKey, configHandler.GetConfig)))
+
+	// Register webhook routes with error handling, guarded by the admin API key since
+	// webhooks receive the events of every meeting
+	admin := func(handler middleware.ErrorHandler) http.HandlerFunc {
+		return middleware.WithErrorHandling(middleware.RequireAdminKey(r.adminKey, handler))
+	}
+	r.mux.HandleFunc("POST /api/webhooks", admin(webhookHandler.CreateWebhook))
+	r.mux.HandleFunc("GET /api/webhooks", admin(webhookHandler.ListWebhooks))
+	r.mux.HandleFunc("DELETE /api/webhooks/{id}", admin(webhookHandler.DeleteWebhook))
+	r.mux.HandleFunc("GET /api/webhooks/{id}/deliveries", admin(webhookHandler.ListDeliveries))
+	r.mux.HandleFunc("POST /api/webhooks/{id}/replay", admin(webhookHandler.ReplayWebhook))
+
+	// Register fault injection routes, guarded by the admin API key; faults are never
+	// injected into admin routes, so they can always be cleared
+	r.mux.HandleFunc("GET /api/admin/faults", admin(faultHandler.GetFaults))
+	r.mux.HandleFunc("PUT /api/admin/faults", admin(faultHandler.SetFaults))
+	r.mux.HandleFunc("DELETE /api/admin/faults", admin(faultHandler.ClearFaults))
+
+	// Register SCIM provisioning routes with error handling, guarded by the SCIM token
+	provisioning := func(handler middleware.ErrorHandler) http.HandlerFunc {
+		return middleware.WithErrorHandling(handlers.SCIMErrors(middleware.RequireBearerToken(r.scimToken, handler)))
+	}
+	r.mux.HandleFunc("GET /scim/v2/Users", provisioning(scimHandler.ListUsers))
+	r.mux.HandleFunc("POST /scim/v2/Users", provisioning(scimHandler.CreateUser))
+	r.mux.HandleFunc("GET /scim/v2/Users/{id}", provisioning(scimHandler.GetUser))
+	r.mux.HandleFunc("PUT /scim/v2/Users/{id}", provisioning(scimHandler.ReplaceUser))
+	r.mux.HandleFunc("PATCH /scim/v2/Users/{id}", provisioning(scimHandler.PatchUser))
+	r.mux.HandleFunc("DELETE /scim/v2/Users/{id}", provisioning(scimHandler.DeleteUser))
+
+	// Serve the files of a local file store from the URLs it signs; bucket stores are
+	// downloaded from directly
+	if local, ok := r.files.(*filestore.Local); ok {
+		fileHandler := handlers.NewFileHandler(local)
+		r.mux.HandleFunc("GET /api/files", middleware.WithErrorHandling(fileHandler.DownloadFile))
+	}
+
+	// Register health routes with error handling
+	r.mux.HandleFunc("GET /healthz", middleware.WithErrorHandling(healthHandler.Healthz))
+	r.mux.HandleFunc("GET /readyz", middleware.WithErrorHandling(healthHandler.Readyz))
+	r.mux.HandleFunc("GET /status", middleware.WithErrorHandling(r.statusRate.Limit(healthHandler.Status)))
+	r.mux.HandleFunc("GET /api/version", middleware.WithErrorHandling(healthHandler.Version))
 
 	// Serve OpenAPI documentation
 	r.mux.HandleFunc("GET /docs", serveOpenAPIUI)
 	r.mux.HandleFunc("GET /docs/openapi.yaml", serveOpenAPISpec)
 
 	// Create a new handler with the middleware chain
-	handler := middleware.Chain(
+	middlewares := []func(http.Handler) http.Handler{
+		middleware.Version(version.Get().Version),
 		middleware.RequestLogger,
-		r.logMiddleware,
-	)(r.mux)
+		middleware.RequestTimeout(r.maxRequestTimeout),
+		middleware.DebugCapture(r.debugRoutes, r.adminKey),
+	}
+	if r.accessLog != nil {
+		middlewares = append(middlewares, r.accessLog)
+	}
+	middlewares = append(middlewares, r.faults.Wrap)
+	chain := middleware.Chain(middlewares...)
 
-	// Update the router's handler
+	// Update the router's handler. Backups and restores take the write gate exclusively,
+	// so they are served beside it rather than through it.
+	exclusive := func(handler middleware.ErrorHandler) http.Handler {
+		return chain(middleware.WithErrorHandling(middleware.RequireAdminKey(r.adminKey, r.writeGate.Exclusive(handler))))
+	}
+	routes := r.mux
 	r.mux = http.NewServeMux()
-	r.mux.Handle("/", handler)
+	r.mux.Handle("/", chain(r.writeGate.Writes(routes)))
+	r.mux.Handle("GET /api/admin/backup", exclusive(adminHandler.Backup))
+	r.mux.Handle("POST /api/admin/restore", exclusive(adminHandler.RestoreBackup))
+	r.mux.Handle("POST /api/admin/backups", exclusive(adminHandler.StoreBackup))
+
+	// Resolve trailing and repeated slashes before the mux sees the path
+	r.handler = middleware.NormalizePath(r.mux)
 }
 
-// ServeHTTP implements the http.Handler interface
-func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	r.mux.ServeHTTP(w, req)
+// Bootstrap ensures the administrator and access tokens declared by bootstrap exist,
+// gated like write requests so it never overlaps a backup or restore. Call it after
+// Setup; read-only instances cannot apply a bootstrap.
+func (r *Router) Bootstrap(bootstrap models.Bootstrap) (models.BootstrapResult, error) {
+	if r.readOnly {
+		return models.BootstrapResult{}, errors.New("cannot bootstrap a read-only instance")
+	}
+
+	var result models.BootstrapResult
+	var err error
+	r.writeGate.Run(func() {
+		result, err = r.admin.Bootstrap(bootstrap)
+	})
+	return result, err
+}
+
+// RunDirectorySync synchronizes users from the directory right away and then every
+// sync interval until ctx is done. Syncs are gated like write requests so they never
+// overlap a backup or restore. It returns at once when no scheduled sync is configured or
+// the instance is read-only.
+func (r *Router) RunDirectorySync(ctx context.Context) {
+	if r.directory == nil || r.directorySyncInterval <= 0 || r.admin == nil || r.readOnly {
+		return
+	}
+
+	ticker := time.NewTicker(r.directorySyncInterval)
+	defer ticker.Stop()
+	for {
+		r.writeGate.Run(func() {
+			if _, err := r.admin.SyncDirectory(false); err != nil {
+				logs.Error("Directory sync failed: %v", err)
+			}
+		})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
 }
 
-// logMiddleware logs all requests
-func (r *Router) logMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-		logs.Info("%s %s %s", req.Method, req.URL.Path, req.RemoteAddr)
-		next.ServeHTTP(w, req)
+// RunDigests sends the digests that are due every digest interval until ctx is done.
+// Runs are gated like write requests so they never overlap a backup or restore. It
+// returns at once when no digest interval is configured or the instance is read-only.
+func (r *Router) RunDigests(ctx context.Context) {
+	if r.digestInterval <= 0 || r.admin == nil || r.readOnly {
+		return
+	}
+
+	ticker := time.NewTicker(r.digestInterval)
+	defer ticker.Stop()
+	for {
+		r.writeGate.Run(func() {
+			if _, err := r.admin.SendDigests(); err != nil {
+				logs.Error("Sending digests failed: %v", err)
+			}
+		})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunPrecomputeWorker computes the recommendations of the precompute jobs delivered to the
+// worker until ctx is done. Jobs are gated like write requests so they never overlap a
+// backup or restore. It returns at once when the instance is not a worker.
+func (r *Router) RunPrecomputeWorker(ctx context.Context) {
+	if r.worker == nil || r.meetings == nil {
+		return
+	}
+
+	err := r.worker.Consume(ctx, func(job precompute.Job) {
+		r.writeGate.Run(func() {
+			if err := r.meetings.PrecomputeRecommendations(job.MeetingID, job.RequestedAt); err != nil {
+				logs.Error("Failed to precompute recommendations for meeting %s: %v", job.MeetingID, err)
+			}
+		})
 	})
+	if err != nil {
+		logs.Error("Precompute worker stopped: %v", err)
+	}
+}
+
+// stream wraps a handler streaming responses so it stops once CloseStreams is called
+func (r *Router) stream(handler middleware.ErrorHandler) middleware.ErrorHandler {
+	return func(w http.ResponseWriter, req *http.Request) error {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		stop := context.AfterFunc(r.streams, cancel)
+		defer stop()
+		return handler(w, req.WithContext(ctx))
+	}
+}
+
+// CloseStreams ends the open recommendation streams, which would otherwise keep a
+// graceful shutdown waiting until their clients disconnect. Register it with
+// http.Server.RegisterOnShutdown.
+func (r *Router) CloseStreams() {
+	r.endStreams()
+}
+
+// Close stops accepting background jobs and waits for queued jobs, such as large
+// batches, to finish, then for queued webhook deliveries. Notifications held for quiet
+// hours are dropped.
+func (r *Router) Close() error {
+	if r.dispatcher != nil {
+		if dropped := r.dispatcher.Close(); dropped > 0 {
+			logs.Warn("Dropped %d notifications held for quiet hours", dropped)
+		}
+	}
+	return errors.Join(r.jobs.Close(), r.webhooks.Close())
+}
+
+// ServeHTTP implements the http.Handler interface
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.handler.ServeHTTP(w, req)
 }
 
 // serveOpenAPISpec serves the OpenAPI specification file
@@ -1,15 +1,27 @@
 package router
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"meetsync/internal/api"
+	"meetsync/internal/directory"
+	"meetsync/internal/filestore"
+	"meetsync/internal/middleware"
 	"meetsync/internal/models"
+	"meetsync/internal/precompute"
+	"meetsync/internal/repositories"
 )
 
 func TestRouterSetup(t *testing.T) {
@@ -152,6 +164,34 @@ func TestRouterSetup(t *testing.T) {
 			body:           nil,
 			expectedStatus: http.StatusNotFound,
 		},
+		{
+			name:           "GET /healthz - Liveness probe",
+			method:         http.MethodGet,
+			path:           "/healthz",
+			body:           nil,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "GET /readyz - Readiness probe",
+			method:         http.MethodGet,
+			path:           "/readyz",
+			body:           nil,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "GET /status - Public status page",
+			method:         http.MethodGet,
+			path:           "/status",
+			body:           nil,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "GET /api/version - Build information",
+			method:         http.MethodGet,
+			path:           "/api/version",
+			body:           nil,
+			expectedStatus: http.StatusOK,
+		},
 		{
 			name:           "Method not allowed",
 			method:         http.MethodDelete,
@@ -183,8 +223,723 @@ func TestRouterSetup(t *testing.T) {
 			if w.Code != tc.expectedStatus {
 				t.Errorf("Expected status code %d, got %d", tc.expectedStatus, w.Code)
 			}
+			if w.Header().Get(middleware.VersionHeader) == "" {
+				t.Errorf("Expected the %s header", middleware.VersionHeader)
+			}
+		})
+	}
+}
+
+func TestRouterAccessTokenScopes(t *testing.T) {
+	r := New(WithAdminAPIKey("admin"))
+	r.Setup()
+
+	// The admin key stands in for a token to mint the first one
+	serve := func(method, path string, body []byte, secret string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		if secret == "admin" {
+			req.Header.Set(middleware.AdminKeyHeader, secret)
+		} else if secret != "" {
+			req.Header.Set("Authorization", "Bearer "+secret)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve(http.MethodPost, "/api/users", mustMarshal(api.CreateUserRequest{Name: "Script Owner", Email: "owner@example.com"}), "")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Failed to create test user: status %d", w.Code)
+	}
+	var createUserResp api.CreateUserResponse
+	if err := json.NewDecoder(w.Body).Decode(&createUserResp); err != nil {
+		t.Fatalf("Failed to decode create user response: %v", err)
+	}
+	userID := createUserResp.User.ID
+
+	w = serve(http.MethodPost, "/api/users/"+userID+"/tokens", mustMarshal(api.CreateAccessTokenRequest{
+		Name:   "calendar sync",
+		Scopes: []models.Scope{models.ScopeReadMeetings},
+	}), "admin")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Failed to create access token: status %d", w.Code)
+	}
+	var createTokenResp api.CreateAccessTokenResponse
+	if err := json.NewDecoder(w.Body).Decode(&createTokenResp); err != nil {
+		t.Fatalf("Failed to decode create access token response: %v", err)
+	}
+	secret := createTokenResp.Secret
+
+	meetingBody := mustMarshal(api.CreateMeetingRequest{
+		Title:             "Scripted Meeting",
+		OrganizerID:       userID,
+		EstimatedDuration: 30,
+		ProposedSlots:     []models.TimeSlot{{StartTime: time.Now(), EndTime: time.Now().Add(time.Hour)}},
+	})
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           []byte
+		secret         string
+		expectedStatus int
+	}{
+		{"read scope allows reads", http.MethodGet, "/api/recommendations?meetingId=non-existent-id", nil, secret, http.StatusNotFound},
+		{"missing scope is rejected", http.MethodPost, "/api/meetings", meetingBody, secret, http.StatusUnauthorized},
+		{"unknown token is rejected", http.MethodGet, "/api/recommendations?meetingId=non-existent-id", nil, "msp_unknown", http.StatusUnauthorized},
+		{"requests without a token are unaffected", http.MethodPost, "/api/meetings", meetingBody, "", http.StatusCreated},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := serve(tc.method, tc.path, tc.body, tc.secret)
+			if w.Code != tc.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", tc.expectedStatus, w.Code)
+			}
+		})
+	}
+
+	// Managing tokens takes one of the owner's tokens, which cannot grant more than it has
+	w = serve(http.MethodPost, "/api/users", mustMarshal(api.CreateUserRequest{Name: "Someone Else", Email: "else@example.com"}), "")
+	var otherUser api.CreateUserResponse
+	if err := json.NewDecoder(w.Body).Decode(&otherUser); err != nil {
+		t.Fatalf("Failed to decode create user response: %v", err)
+	}
+	readOnly := mustMarshal(api.CreateAccessTokenRequest{Name: "more", Scopes: []models.Scope{models.ScopeReadMeetings}})
+	writable := mustMarshal(api.CreateAccessTokenRequest{Name: "more", Scopes: []models.Scope{models.ScopeWriteMeetings}})
+	management := []struct {
+		name           string
+		method         string
+		path           string
+		body           []byte
+		secret         string
+		expectedStatus int
+	}{
+		{"minting without a token is rejected", http.MethodPost, "/api/users/" + userID + "/tokens", readOnly, "", http.StatusUnauthorized},
+		{"listing without a token is rejected", http.MethodGet, "/api/users/" + userID + "/tokens", nil, "", http.StatusUnauthorized},
+		{"revoking without a token is rejected", http.MethodDelete, "/api/users/" + userID + "/tokens", nil, "", http.StatusUnauthorized},
+		{"tokens cannot mint for another user", http.MethodPost, "/api/users/" + otherUser.User.ID + "/tokens", readOnly, secret, http.StatusForbidden},
+		{"tokens cannot list another user's", http.MethodGet, "/api/users/" + otherUser.User.ID + "/tokens", nil, secret, http.StatusForbidden},
+		{"tokens cannot revoke another user's", http.MethodDelete, "/api/users/" + otherUser.User.ID + "/tokens", nil, secret, http.StatusForbidden},
+		{"tokens cannot grant scopes they lack", http.MethodPost, "/api/users/" + userID + "/tokens", writable, secret, http.StatusForbidden},
+		{"tokens can mint for their owner", http.MethodPost, "/api/users/" + userID + "/tokens", readOnly, secret, http.StatusCreated},
+		{"tokens can list their owner's", http.MethodGet, "/api/users/" + userID + "/tokens", nil, secret, http.StatusOK},
+		{"sessions without a token are rejected", http.MethodGet, "/api/users/" + userID + "/sessions", nil, "", http.StatusUnauthorized},
+		{"tokens cannot list another user's sessions", http.MethodGet, "/api/users/" + otherUser.User.ID + "/sessions", nil, secret, http.StatusForbidden},
+		{"tokens can list their owner's sessions", http.MethodGet, "/api/users/" + userID + "/sessions", nil, secret, http.StatusOK},
+	}
+	for _, tc := range management {
+		t.Run(tc.name, func(t *testing.T) {
+			w := serve(tc.method, tc.path, tc.body, tc.secret)
+			if w.Code != tc.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", tc.expectedStatus, w.Code)
+			}
 		})
 	}
+
+	// Revoking every token of the user rejects them right away
+	w = serve(http.MethodDelete, "/api/users/"+userID+"/tokens", nil, secret)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Failed to revoke access tokens: status %d", w.Code)
+	}
+	w = serve(http.MethodGet, "/api/recommendations?meetingId=non-existent-id", nil, secret)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected revoked token to be rejected with status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestRouterRoleAuthorization(t *testing.T) {
+	r := New(WithAdminAPIKey("admin"))
+	r.Setup()
+
+	// The admin key stands in for a token to mint the first one
+	serve := func(method, path string, body []byte, secret string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		if secret == "admin" {
+			req.Header.Set(middleware.AdminKeyHeader, secret)
+		} else if secret != "" {
+			req.Header.Set("Authorization", "Bearer "+secret)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	createUser := func(name, email string) (string, string) {
+		w := serve(http.MethodPost, "/api/users", mustMarshal(api.CreateUserRequest{Name: name, Email: email}), "")
+		var created api.CreateUserResponse
+		if w.Code != http.StatusCreated || json.NewDecoder(w.Body).Decode(&created) != nil {
+			t.Fatalf("Failed to create test user: status %d", w.Code)
+		}
+		w = serve(http.MethodPost, "/api/users/"+created.User.ID+"/tokens", mustMarshal(api.CreateAccessTokenRequest{
+			Name:   "script",
+			Scopes: []models.Scope{models.ScopeWriteMeetings, models.ScopeWriteAvailability},
+		}), "admin")
+		var token api.CreateAccessTokenResponse
+		if w.Code != http.StatusCreated || json.NewDecoder(w.Body).Decode(&token) != nil {
+			t.Fatalf("Failed to create access token: status %d", w.Code)
+		}
+		return created.User.ID, token.Secret
+	}
+	organizerID, organizerSecret := createUser("Organizer", "organizer@example.com")
+	participantID, participantSecret := createUser("Participant", "participant@example.com")
+
+	slot := models.TimeSlot{StartTime: time.Now().Add(24 * time.Hour), EndTime: time.Now().Add(25 * time.Hour)}
+	w := serve(http.MethodPost, "/api/meetings", mustMarshal(api.CreateMeetingRequest{
+		Title:             "Planning",
+		OrganizerID:       organizerID,
+		EstimatedDuration: 60,
+		ProposedSlots:     []models.TimeSlot{slot},
+		ParticipantIDs:    []string{participantID},
+	}), organizerSecret)
+	var created api.CreateMeetingResponse
+	if w.Code != http.StatusCreated || json.NewDecoder(w.Body).Decode(&created) != nil {
+		t.Fatalf("Failed to create meeting: status %d", w.Code)
+	}
+	meetingID := created.Meeting.ID
+	w = serve(http.MethodPost, "/api/availabilities", mustMarshal(api.AddAvailabilityRequest{
+		UserID: participantID, MeetingID: meetingID, AvailableSlots: []models.TimeSlot{slot},
+	}), participantSecret)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Failed to add availability: status %d", w.Code)
+	}
+	w = serve(http.MethodGet, "/api/availabilities?userId="+participantID+"&meetingId="+meetingID, nil, "")
+	var availability api.GetAvailabilityResponse
+	if w.Code != http.StatusOK || json.NewDecoder(w.Body).Decode(&availability) != nil {
+		t.Fatalf("Failed to get availability: status %d", w.Code)
+	}
+	availabilityPath := "/api/availabilities/" + availability.Availability.ID
+	updateBody := mustMarshal(api.UpdateMeetingRequest{Title: "Renamed"})
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           []byte
+		secret         string
+		expectedStatus int
+	}{
+		{"participants cannot update the meeting", http.MethodPut, "/api/meetings/" + meetingID, updateBody, participantSecret, http.StatusForbidden},
+		{"participants cannot delete the meeting", http.MethodDelete, "/api/meetings/" + meetingID, nil, participantSecret, http.StatusForbidden},
+		{"participants cannot cancel the meeting", http.MethodPost, "/api/meetings/" + meetingID + "/cancel", nil, participantSecret, http.StatusForbidden},
+		{"organizers cannot confirm availability of participants", http.MethodPost, availabilityPath + "/confirm", nil, organizerSecret, http.StatusForbidden},
+		{"organizers cannot delete availability of participants", http.MethodDelete, availabilityPath, nil, organizerSecret, http.StatusForbidden},
+		{"organizers cannot submit availability for participants", http.MethodPost, "/api/availabilities", mustMarshal(api.AddAvailabilityRequest{
+			UserID: participantID, MeetingID: meetingID, AvailableSlots: []models.TimeSlot{slot}, Tentative: true,
+		}), organizerSecret, http.StatusForbidden},
+		{"unknown meetings are not found", http.MethodDelete, "/api/meetings/unknown", nil, participantSecret, http.StatusNotFound},
+		{"participants confirm their availability", http.MethodPost, availabilityPath + "/confirm", nil, participantSecret, http.StatusOK},
+		{"organizers update the meeting", http.MethodPut, "/api/meetings/" + meetingID, updateBody, organizerSecret, http.StatusOK},
+		{"requests without a token are unaffected", http.MethodPut, "/api/meetings/" + meetingID, updateBody, "", http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := serve(tc.method, tc.path, tc.body, tc.secret)
+			if w.Code != tc.expectedStatus {
+				t.Errorf("Expected status code %d, got %d: %s", tc.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	// Batch operations only apply to meetings the token owner organizes
+	w = serve(http.MethodDelete, "/api/meetings?ids="+meetingID, nil, participantSecret)
+	var batch api.BatchResponse
+	if w.Code != http.StatusOK || json.NewDecoder(w.Body).Decode(&batch) != nil {
+		t.Fatalf("Failed to apply batch: status %d", w.Code)
+	}
+	if batch.Failed != 1 || batch.Results[0].Status != http.StatusForbidden {
+		t.Errorf("Expected the deletion to be forbidden, got %+v", batch.Results)
+	}
+}
+
+// TestRouterConcurrentRequests serves requests writing and reading users, meetings and
+// availability in parallel; run with -race, it catches state shared between requests
+// without locking
+func TestRouterConcurrentRequests(t *testing.T) {
+	r := New()
+	r.Setup()
+
+	serve := func(method, path string, body []byte) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	const clients = 16
+	slot := models.TimeSlot{StartTime: time.Now().Add(24 * time.Hour), EndTime: time.Now().Add(25 * time.Hour)}
+	var wg sync.WaitGroup
+	failures := make(chan string, clients)
+	for i := 0; i < clients; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			w := serve(http.MethodPost, "/api/users", mustMarshal(api.CreateUserRequest{Name: "User", Email: fmt.Sprintf("user%d@example.com", i)}))
+			var user api.CreateUserResponse
+			if w.Code != http.StatusCreated || json.NewDecoder(w.Body).Decode(&user) != nil {
+				failures <- fmt.Sprintf("create user: status %d", w.Code)
+				return
+			}
+			w = serve(http.MethodPost, "/api/meetings", mustMarshal(api.CreateMeetingRequest{
+				Title:             fmt.Sprintf("Meeting %d", i),
+				OrganizerID:       user.User.ID,
+				EstimatedDuration: 60,
+				ProposedSlots:     []models.TimeSlot{slot},
+			}))
+			var meeting api.CreateMeetingResponse
+			if w.Code != http.StatusCreated || json.NewDecoder(w.Body).Decode(&meeting) != nil {
+				failures <- fmt.Sprintf("create meeting: status %d", w.Code)
+				return
+			}
+			w = serve(http.MethodPost, "/api/availabilities", mustMarshal(api.AddAvailabilityRequest{
+				UserID: user.User.ID, MeetingID: meeting.Meeting.ID, AvailableSlots: []models.TimeSlot{slot},
+			}))
+			if w.Code != http.StatusCreated {
+				failures <- fmt.Sprintf("add availability: status %d", w.Code)
+				return
+			}
+			if w = serve(http.MethodGet, "/api/recommendations?meetingId="+meeting.Meeting.ID, nil); w.Code != http.StatusOK {
+				failures <- fmt.Sprintf("get recommendations: status %d", w.Code)
+			}
+			if w = serve(http.MethodGet, "/api/meetings", nil); w.Code != http.StatusOK {
+				failures <- fmt.Sprintf("list meetings: status %d", w.Code)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(failures)
+	for failure := range failures {
+		t.Error(failure)
+	}
+
+	w := serve(http.MethodGet, "/api/meetings?limit=100", nil)
+	var list api.ListMeetingsResponse
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil {
+		t.Fatalf("Failed to decode meetings: %v", err)
+	}
+	if len(list.Meetings) != clients {
+		t.Errorf("Expected %d meetings, got %d", clients, len(list.Meetings))
+	}
+}
+
+func TestRouterBackupAndRestore(t *testing.T) {
+	r := New(WithAdminAPIKey("secret"))
+	r.Setup()
+
+	serve := func(method, path string, body []byte, adminKey string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		if adminKey != "" {
+			req.Header.Set(middleware.AdminKeyHeader, adminKey)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := serve(http.MethodPost, "/api/users", mustMarshal(api.CreateUserRequest{Name: "Kept", Email: "kept@example.com"}), ""); w.Code != http.StatusCreated {
+		t.Fatalf("Failed to create test user: status %d", w.Code)
+	}
+
+	if w := serve(http.MethodGet, "/api/admin/backup", nil, "wrong"); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status code %d without the admin key, got %d", http.StatusUnauthorized, w.Code)
+	}
+	w := serve(http.MethodGet, "/api/admin/backup", nil, "secret")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Failed to back up: status %d", w.Code)
+	}
+	backup := w.Body.Bytes()
+
+	if w := serve(http.MethodPost, "/api/users", mustMarshal(api.CreateUserRequest{Name: "Dropped", Email: "dropped@example.com"}), ""); w.Code != http.StatusCreated {
+		t.Fatalf("Failed to create test user: status %d", w.Code)
+	}
+
+	if w := serve(http.MethodPost, "/api/admin/backup/verify", backup, "secret"); w.Code != http.StatusOK {
+		t.Errorf("Expected the backup to verify, got status %d", w.Code)
+	}
+	if w := serve(http.MethodPost, "/api/admin/restore", backup, "secret"); w.Code != http.StatusOK {
+		t.Fatalf("Failed to restore: status %d", w.Code)
+	}
+
+	w = serve(http.MethodGet, "/api/users", nil, "")
+	var listResp api.ListUsersResponse
+	if err := json.NewDecoder(w.Body).Decode(&listResp); err != nil {
+		t.Fatalf("Failed to decode list users response: %v", err)
+	}
+	if len(listResp.Users) != 1 || listResp.Users[0].Name != "Kept" {
+		t.Errorf("Expected only the user from the backup, got %+v", listResp.Users)
+	}
+}
+
+func TestRouterSCIMProvisioning(t *testing.T) {
+	r := New(WithSCIM("scim-secret", "https://meetsync.example.com"))
+	r.Setup()
+
+	serve := func(method, path, body, token string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/scim+json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	body := `{"schemas":["urn:ietf:params:scim:schemas:core:2.0:User"],"userName":"jane@example.com","name":{"givenName":"Jane","familyName":"Doe"}}`
+	if w := serve(http.MethodPost, "/scim/v2/Users", body, "wrong"); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status code %d without the SCIM token, got %d", http.StatusUnauthorized, w.Code)
+	}
+	w := serve(http.MethodPost, "/scim/v2/Users", body, "scim-secret")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Failed to provision user: status %d", w.Code)
+	}
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatalf("Failed to decode SCIM user: %v", err)
+	}
+
+	// Deactivated users cannot organize meetings
+	deactivate := `{"schemas":["urn:ietf:params:scim:api:messages:2.0:PatchOp"],"Operations":[{"op":"replace","value":{"active":false}}]}`
+	if w := serve(http.MethodPatch, "/scim/v2/Users/"+created.ID, deactivate, "scim-secret"); w.Code != http.StatusOK {
+		t.Fatalf("Failed to deactivate user: status %d", w.Code)
+	}
+	meetingBody := mustMarshal(api.CreateMeetingRequest{
+		Title:             "Meeting",
+		OrganizerID:       created.ID,
+		EstimatedDuration: 30,
+		ProposedSlots:     []models.TimeSlot{{StartTime: time.Now(), EndTime: time.Now().Add(time.Hour)}},
+	})
+	if w := serve(http.MethodPost, "/api/meetings", string(meetingBody), ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for a deactivated organizer, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	if w := serve(http.MethodDelete, "/scim/v2/Users/"+created.ID, "", "scim-secret"); w.Code != http.StatusNoContent {
+		t.Errorf("Failed to deprovision user: status %d", w.Code)
+	}
+	if w := serve(http.MethodGet, "/scim/v2/Users/"+created.ID, "", "scim-secret"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d for a deprovisioned user, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+// staticDirectory is a directory listing fixed entries
+type staticDirectory []directory.Entry
+
+func (d staticDirectory) Users() ([]directory.Entry, error) {
+	return append([]directory.Entry(nil), d...), nil
+}
+
+func TestRouterDirectorySync(t *testing.T) {
+	dir := staticDirectory{{ID: "u-jane", DN: "uid=jane,ou=people", Name: "Jane Doe", Email: "jane@example.com"}}
+	r := New(WithAdminAPIKey("secret"), WithDirectorySync(dir, models.DirectorySyncOptions{
+		OnConflict: models.DirectoryConflictLink,
+		OnMissing:  models.DirectoryMissingKeep,
+	}, time.Hour))
+	r.Setup()
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/admin/directory/sync?dryRun=true", nil)
+	req.Header.Set(middleware.AdminKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var syncResp api.SyncDirectoryResponse
+	if err := json.NewDecoder(w.Body).Decode(&syncResp); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("Failed to plan directory sync: status %d, %v", w.Code, err)
+	}
+	if syncResp.Result.Created != 1 {
+		t.Errorf("Expected the dry run to plan 1 created user, got %+v", syncResp.Result)
+	}
+
+	// The scheduled sync runs once right away and stops with its context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r.RunDirectorySync(ctx)
+
+	req, _ = http.NewRequest(http.MethodGet, "/api/users", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var listResp api.ListUsersResponse
+	if err := json.NewDecoder(w.Body).Decode(&listResp); err != nil {
+		t.Fatalf("Failed to decode list users response: %v", err)
+	}
+	if len(listResp.Users) != 1 || listResp.Users[0].ExternalID != "u-jane" {
+		t.Errorf("Expected the directory user to be synchronized, got %+v", listResp.Users)
+	}
+}
+
+func TestRouterBootstrap(t *testing.T) {
+	r := New(WithAdminAPIKey("secret"))
+	r.Setup()
+
+	secret := "msp_" + strings.Repeat("0f", 32)
+	bootstrap := models.Bootstrap{
+		Admin:  models.BootstrapAdmin{Name: "Ops", Email: "ops@example.com"},
+		Tokens: []models.BootstrapToken{{Name: "deploy", Scopes: []models.Scope{models.ScopeReadMeetings}, Secret: secret}},
+	}
+	if _, err := r.Bootstrap(bootstrap); err != nil {
+		t.Fatalf("Failed to apply bootstrap at startup: %v", err)
+	}
+
+	// Applying it again over the API changes nothing
+	req, _ := http.NewRequest(http.MethodPut, "/api/admin/bootstrap", bytes.NewBuffer(mustMarshal(bootstrap)))
+	req.Header.Set(middleware.AdminKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var resp api.BootstrapResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("Failed to apply bootstrap: status %d, %v", w.Code, err)
+	}
+	if resp.Result.Changed() {
+		t.Errorf("Expected reapplying the bootstrap to change nothing, got %+v", resp.Result)
+	}
+
+	// The declared token authenticates as the administrator
+	req, _ = http.NewRequest(http.MethodGet, "/api/recommendations?meetingId=non-existent-id", nil)
+	req.Header.Set("Authorization", "Bearer "+secret)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected the bootstrapped token to authenticate, got status %d", w.Code)
+	}
+
+	// The bootstrap route needs the admin key
+	req, _ = http.NewRequest(http.MethodPut, "/api/admin/bootstrap", bytes.NewBuffer(mustMarshal(bootstrap)))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status code %d without the admin key, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestRouterFileStorage(t *testing.T) {
+	files, err := filestore.NewLocal(t.TempDir(), "https://meetsync.example.com/api/files", []byte("secret"))
+	if err != nil {
+		t.Fatalf("Failed to create file store: %v", err)
+	}
+	r := New(WithAdminAPIKey("secret"), WithFileStore(files, time.Hour))
+	r.Setup()
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/admin/backups", nil)
+	req.Header.Set(middleware.AdminKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var link api.DownloadLinkResponse
+	if err := json.NewDecoder(w.Body).Decode(&link); err != nil || w.Code != http.StatusCreated {
+		t.Fatalf("Failed to store backup: status %d, %v", w.Code, err)
+	}
+
+	// The link downloads the backup from the API, without the admin key
+	download, err := url.Parse(link.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse download link %q: %v", link.URL, err)
+	}
+	req, _ = http.NewRequest(http.MethodGet, download.RequestURI(), nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var backup models.Backup
+	if err := json.NewDecoder(w.Body).Decode(&backup); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("Failed to download backup: status %d, %v", w.Code, err)
+	}
+	if backup.Checksum == "" {
+		t.Errorf("Expected the downloaded backup to be complete, got %+v", backup)
+	}
+
+	// Links cannot be altered to download other files
+	altered := download.Query()
+	altered.Set("key", "exports/"+strings.TrimPrefix(altered.Get("key"), "backups/"))
+	req, _ = http.NewRequest(http.MethodGet, download.Path+"?"+altered.Encode(), nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status code %d for an altered link, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+// channelQueue is a precompute queue delivering jobs to the worker of the same process
+type channelQueue chan precompute.Job
+
+func (q channelQueue) Enqueue(meetingID string) error {
+	q <- precompute.Job{MeetingID: meetingID, RequestedAt: time.Now()}
+	return nil
+}
+
+func (q channelQueue) Consume(ctx context.Context, handle func(precompute.Job)) error {
+	for {
+		select {
+		case job := <-q:
+			handle(job)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func TestRouterPrecomputeWorker(t *testing.T) {
+	queue := make(channelQueue, 16)
+	r := New(WithMaterializedRecommendations(true), WithPrecomputeQueue(queue), WithPrecomputeWorker(queue))
+	r.Setup()
+
+	serve := func(method, path string, body []byte) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	var user api.CreateUserResponse
+	if w := serve(http.MethodPost, "/api/users", mustMarshal(api.CreateUserRequest{Name: "Organizer", Email: "organizer@example.com"})); w.Code != http.StatusCreated || json.NewDecoder(w.Body).Decode(&user) != nil {
+		t.Fatalf("Failed to create test user: status %d", w.Code)
+	}
+	slot := models.TimeSlot{StartTime: time.Now().Add(24 * time.Hour), EndTime: time.Now().Add(25 * time.Hour)}
+	var created api.CreateMeetingResponse
+	w := serve(http.MethodPost, "/api/meetings", mustMarshal(api.CreateMeetingRequest{
+		Title:             "Planning",
+		OrganizerID:       user.User.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     []models.TimeSlot{slot},
+	}))
+	if w.Code != http.StatusCreated || json.NewDecoder(w.Body).Decode(&created) != nil {
+		t.Fatalf("Failed to create test meeting: status %d", w.Code)
+	}
+	availableCount := func() int {
+		var resp api.GetRecommendationsResponse
+		w := serve(http.MethodGet, "/api/recommendations?meetingId="+created.Meeting.ID, nil)
+		if w.Code != http.StatusOK || json.NewDecoder(w.Body).Decode(&resp) != nil || len(resp.RecommendedSlots) == 0 {
+			t.Fatalf("Failed to get recommendations: status %d", w.Code)
+		}
+		return resp.RecommendedSlots[0].AvailableCount
+	}
+	availableCount()
+
+	// The change is queued; the worker computes the recommendations it leads to
+	if w := serve(http.MethodPost, "/api/availabilities", mustMarshal(api.AddAvailabilityRequest{
+		UserID:         user.User.ID,
+		MeetingID:      created.Meeting.ID,
+		AvailableSlots: []models.TimeSlot{slot},
+	})); w.Code != http.StatusCreated {
+		t.Fatalf("Failed to add availability: status %d", w.Code)
+	}
+	if count := availableCount(); count != 0 {
+		t.Errorf("Expected the previous recommendations until the worker runs, got %d available", count)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.RunPrecomputeWorker(ctx)
+	deadline := time.Now().Add(2 * time.Second)
+	for availableCount() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the worker to compute recommendations")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRouterRecommendationStream(t *testing.T) {
+	r := New()
+	r.Setup()
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	serve := func(method, path string, body []byte) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	var user api.CreateUserResponse
+	if w := serve(http.MethodPost, "/api/users", mustMarshal(api.CreateUserRequest{Name: "Organizer", Email: "organizer@example.com"})); w.Code != http.StatusCreated || json.NewDecoder(w.Body).Decode(&user) != nil {
+		t.Fatalf("Failed to create test user: status %d", w.Code)
+	}
+	slot := models.TimeSlot{StartTime: time.Now().Add(24 * time.Hour), EndTime: time.Now().Add(25 * time.Hour)}
+	var created api.CreateMeetingResponse
+	w := serve(http.MethodPost, "/api/meetings", mustMarshal(api.CreateMeetingRequest{
+		Title:             "Planning",
+		OrganizerID:       user.User.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     []models.TimeSlot{slot},
+	}))
+	if w.Code != http.StatusCreated || json.NewDecoder(w.Body).Decode(&created) != nil {
+		t.Fatalf("Failed to create test meeting: status %d", w.Code)
+	}
+
+	if resp, err := http.Get(server.URL + "/api/meetings/missing/stream"); err != nil || resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("Expected 404 for the stream of a missing meeting, got %v %v", resp, err)
+	}
+	resp, err := http.Get(server.URL + "/api/meetings/" + created.Meeting.ID + "/stream")
+	if err != nil {
+		t.Fatalf("Failed to open the stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got status %d and %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	// Events are read in the background so the test fails instead of hanging
+	type event struct {
+		name string
+		data api.GetRecommendationsResponse
+	}
+	received := make(chan event)
+	go func() {
+		defer close(received)
+		reader := bufio.NewReader(resp.Body)
+		var current event
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\n")
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				current.name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &current.data)
+			case line == "" && current.name != "":
+				received <- current
+				current = event{}
+			}
+		}
+	}()
+	next := func() (event, bool) {
+		select {
+		case e, ok := <-received:
+			return e, ok
+		case <-time.After(2 * time.Second):
+			t.Fatal("Timed out waiting for the stream")
+			return event{}, false
+		}
+	}
+
+	if e, _ := next(); e.name != "recommendations" || len(e.data.RecommendedSlots) != 1 || e.data.RecommendedSlots[0].AvailableCount != 0 {
+		t.Fatalf("Expected the current recommendations first, got %+v", e)
+	}
+	if w := serve(http.MethodPost, "/api/availabilities", mustMarshal(api.AddAvailabilityRequest{
+		UserID:         user.User.ID,
+		MeetingID:      created.Meeting.ID,
+		AvailableSlots: []models.TimeSlot{slot},
+	})); w.Code != http.StatusCreated {
+		t.Fatalf("Failed to add availability: status %d", w.Code)
+	}
+	if e, _ := next(); e.name != "recommendations" || e.data.RecommendedSlots[0].AvailableCount != 1 {
+		t.Fatalf("Expected the recommendations after the change, got %+v", e)
+	}
+
+	// Shutting down ends open streams
+	r.CloseStreams()
+	if _, ok := next(); ok {
+		t.Fatal("Expected the stream to end")
+	}
+	if _, err := io.ReadAll(resp.Body); err != nil {
+		t.Fatalf("Expected the stream to end cleanly, got %v", err)
+	}
 }
 
 // Helper function to marshal JSON
@@ -195,3 +950,215 @@ func mustMarshal(v interface{}) []byte {
 	}
 	return data
 }
+
+func TestRouterWebhooks(t *testing.T) {
+	received := make(chan string, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		received <- req.Header.Get("X-MeetSync-Event")
+	}))
+	defer receiver.Close()
+
+	r := New(WithAdminAPIKey("secret"))
+	r.Setup()
+	defer r.Close()
+
+	serve := func(method, path string, body []byte, adminKey string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		if adminKey != "" {
+			req.Header.Set(middleware.AdminKeyHeader, adminKey)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := serve(http.MethodPost, "/api/webhooks", mustMarshal(api.CreateWebhookRequest{URL: receiver.URL}), ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status code %d without the admin key, got %d", http.StatusUnauthorized, w.Code)
+	}
+	w := serve(http.MethodPost, "/api/webhooks", mustMarshal(api.CreateWebhookRequest{URL: receiver.URL, Events: []string{"meeting.created"}}), "secret")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Failed to register webhook: status %d", w.Code)
+	}
+	var createResp api.CreateWebhookResponse
+	if err := json.NewDecoder(w.Body).Decode(&createResp); err != nil {
+		t.Fatalf("Failed to decode create webhook response: %v", err)
+	}
+	webhookID := createResp.Webhook.ID
+
+	// Creating a meeting delivers a meeting.created event to the webhook
+	w = serve(http.MethodPost, "/api/users", mustMarshal(api.CreateUserRequest{Name: "Organizer", Email: "organizer@example.com"}), "")
+	var userResp api.CreateUserResponse
+	if err := json.NewDecoder(w.Body).Decode(&userResp); err != nil {
+		t.Fatalf("Failed to decode create user response: %v", err)
+	}
+	now := time.Now()
+	w = serve(http.MethodPost, "/api/meetings", mustMarshal(api.CreateMeetingRequest{
+		Title:             "Webhook Meeting",
+		OrganizerID:       userResp.User.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     []models.TimeSlot{{StartTime: now.Add(time.Hour), EndTime: now.Add(2 * time.Hour)}},
+	}), "")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Failed to create meeting: status %d", w.Code)
+	}
+	select {
+	case eventType := <-received:
+		if eventType != "meeting.created" {
+			t.Errorf("Expected a meeting.created delivery, got %q", eventType)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for the webhook delivery")
+	}
+
+	var deliveries api.ListWebhookDeliveriesResponse
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		w = serve(http.MethodGet, "/api/webhooks/"+webhookID+"/deliveries?status=succeeded", nil, "secret")
+		if err := json.NewDecoder(w.Body).Decode(&deliveries); err != nil {
+			t.Fatalf("Failed to decode deliveries response: %v", err)
+		}
+		if len(deliveries.Deliveries) == 1 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(deliveries.Deliveries) != 1 {
+		t.Fatalf("Expected one succeeded delivery, got %+v", deliveries.Deliveries)
+	}
+
+	// Deliveries can be replayed by ID
+	w = serve(http.MethodPost, "/api/webhooks/"+webhookID+"/replay", mustMarshal(api.ReplayWebhookRequest{DeliveryIDs: []string{deliveries.Deliveries[0].ID}}), "secret")
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Failed to replay delivery: status %d", w.Code)
+	}
+	select {
+	case <-received:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for the replayed delivery")
+	}
+
+	if w := serve(http.MethodDelete, "/api/webhooks/"+webhookID, nil, "secret"); w.Code != http.StatusNoContent {
+		t.Errorf("Expected status code %d deleting the webhook, got %d", http.StatusNoContent, w.Code)
+	}
+	if w := serve(http.MethodGet, "/api/webhooks/"+webhookID+"/deliveries", nil, "secret"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d for a deleted webhook, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestRouterReadOnly(t *testing.T) {
+	storage, err := repositories.Open(repositories.DriverMemory, "")
+	if err != nil {
+		t.Fatalf("Failed to open storage: %v", err)
+	}
+	primary := New(WithStorage(storage))
+	primary.Setup()
+	defer primary.Close()
+	replica := New(WithStorage(storage), WithReadOnly(true), WithAdminAPIKey("secret"))
+	replica.Setup()
+	defer replica.Close()
+
+	serve := func(r *Router, method, path string, body []byte) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(middleware.AdminKeyHeader, "secret")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	user := mustMarshal(api.CreateUserRequest{Name: "Primary", Email: "primary@example.com"})
+	if w := serve(primary, http.MethodPost, "/api/users", user); w.Code != http.StatusCreated {
+		t.Fatalf("Failed to create test user: status %d", w.Code)
+	}
+
+	// The replica serves reads of what the primary wrote
+	w := serve(replica, http.MethodGet, "/api/users", nil)
+	var listResp api.ListUsersResponse
+	if w.Code != http.StatusOK || json.NewDecoder(w.Body).Decode(&listResp) != nil || len(listResp.Users) != 1 {
+		t.Errorf("Expected the replica to list the user created on the primary, got status %d", w.Code)
+	}
+	if w := serve(replica, http.MethodPost, "/api/recommendations/batch", mustMarshal(api.BatchRecommendationsRequest{MeetingIDs: []string{"missing"}})); w.Code == http.StatusServiceUnavailable {
+		t.Errorf("Expected the replica to serve recommendation reads sent with POST, got status %d", w.Code)
+	}
+
+	// Writes are rejected, including those served for GET
+	for _, tt := range []struct{ method, path string }{
+		{http.MethodPost, "/api/users"},
+		{http.MethodDelete, "/api/meetings/42"},
+		{http.MethodGet, "/api/rsvp/token"},
+		{http.MethodPost, "/api/admin/restore"},
+	} {
+		if w := serve(replica, tt.method, tt.path, user); w.Code != http.StatusServiceUnavailable {
+			t.Errorf("Expected status code %d for %s %s on the replica, got %d", http.StatusServiceUnavailable, tt.method, tt.path, w.Code)
+		}
+	}
+}
+
+func TestRouterFaultInjection(t *testing.T) {
+	serve := func(r *Router, method, path string, body []byte) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(middleware.AdminKeyHeader, "secret")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	rules := mustMarshal(api.SetFaultsRequest{Rules: []middleware.FaultRule{{Route: "GET /api/users", ErrorRate: 1}}})
+
+	disabled := New(WithAdminAPIKey("secret"))
+	disabled.Setup()
+	if w := serve(disabled, http.MethodPut, "/api/admin/faults", rules); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d while fault injection is disabled, got %d", http.StatusNotFound, w.Code)
+	}
+
+	r := New(WithAdminAPIKey("secret"), WithFaultInjection(true))
+	r.Setup()
+	if w := serve(r, http.MethodPut, "/api/admin/faults", rules); w.Code != http.StatusOK {
+		t.Fatalf("Failed to set fault rules: status %d", w.Code)
+	}
+	w := serve(r, http.MethodGet, "/api/users", nil)
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get(middleware.FaultInjectedHeader) != "true" {
+		t.Errorf("Expected an injected %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+
+	if w := serve(r, http.MethodDelete, "/api/admin/faults", nil); w.Code != http.StatusNoContent {
+		t.Fatalf("Failed to clear fault rules: status %d", w.Code)
+	}
+	if w := serve(r, http.MethodGet, "/api/users", nil); w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d once faults are cleared, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestRouterPathNormalization(t *testing.T) {
+	r := New()
+	r.Setup()
+	serve := func(method, path string, body []byte) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Writes to a path with a trailing slash reach the route instead of 404ing
+	w := serve(http.MethodPost, "/api/users/", mustMarshal(api.CreateUserRequest{Name: "Slash", Email: "slash@example.com"}))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d", http.StatusCreated, w.Code)
+	}
+	var created api.CreateUserResponse
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	// Reads are redirected to the canonical path
+	w = serve(http.MethodGet, "/api//users/"+created.User.ID+"/?fields=name", nil)
+	if w.Code != http.StatusMovedPermanently {
+		t.Fatalf("Expected status code %d, got %d", http.StatusMovedPermanently, w.Code)
+	}
+	if location := w.Header().Get("Location"); location != "/api/users/"+created.User.ID+"?fields=name" {
+		t.Errorf("Unexpected redirect to %s", location)
+	}
+	if w := serve(http.MethodGet, w.Header().Get("Location"), nil); w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d at the canonical path, got %d", http.StatusOK, w.Code)
+	}
+}
@@ -0,0 +1,133 @@
+// Package rsvp signs one-click links that let invited participants answer a meeting
+// invitation from their email without logging in. Links carry the meeting, participant
+// and slot they answer for, expire, and can be redeemed only once.
+package rsvp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"meetsync/pkg/errors"
+)
+
+// Link is what an RSVP link answers: the participant is free for a slot of a meeting
+type Link struct {
+	ID        string    `json:"id"`
+	MeetingID string    `json:"m"`
+	UserID    string    `json:"u"`
+	SlotID    string    `json:"s"`
+	ExpiresAt time.Time `json:"e"`
+}
+
+// Signer signs links with an HMAC secret and remembers redeemed links until they expire
+type Signer struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu   sync.Mutex
+	used map[string]time.Time // expiry of redeemed link IDs
+}
+
+// NewSigner creates a Signer issuing links valid for ttl
+func NewSigner(secret []byte, ttl time.Duration) *Signer {
+	return &Signer{
+		secret: secret,
+		ttl:    ttl,
+		now:    time.Now,
+		used:   make(map[string]time.Time),
+	}
+}
+
+// Sign returns the token of a new link for a participant and a slot of a meeting
+func (s *Signer) Sign(meetingID, userID, slotID string) string {
+	payload, _ := json.Marshal(Link{
+		ID:        uuid.New().String(),
+		MeetingID: meetingID,
+		UserID:    userID,
+		SlotID:    slotID,
+		ExpiresAt: s.now().Add(s.ttl).UTC().Truncate(time.Second),
+	})
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.mac(encoded))
+}
+
+// Redeem verifies token and passes its link to record. The link is used up once record
+// succeeds; while record runs, concurrent redemptions of the same link fail.
+func (s *Signer) Redeem(token string, record func(Link) error) error {
+	link, err := s.verify(token)
+	if err != nil {
+		return err
+	}
+	if err := s.claim(link); err != nil {
+		return err
+	}
+	if err := record(link); err != nil {
+		s.release(link)
+		return err
+	}
+	return nil
+}
+
+// verify checks the signature and expiry of token
+func (s *Signer) verify(token string) (Link, error) {
+	invalid := errors.NewUnauthorizedError("Invalid RSVP link")
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found {
+		return Link{}, invalid
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, s.mac(encoded)) {
+		return Link{}, invalid
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Link{}, invalid
+	}
+	var link Link
+	if err := json.Unmarshal(payload, &link); err != nil {
+		return Link{}, invalid
+	}
+	if !s.now().Before(link.ExpiresAt) {
+		return Link{}, errors.NewUnauthorizedError("RSVP link has expired")
+	}
+	return link, nil
+}
+
+// claim marks link as redeemed, forgetting links that have expired since
+func (s *Signer) claim(link Link) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for id, expiresAt := range s.used {
+		if !now.Before(expiresAt) {
+			delete(s.used, id)
+		}
+	}
+	if _, used := s.used[link.ID]; used {
+		return errors.NewConflictError("RSVP link has already been used")
+	}
+	s.used[link.ID] = link.ExpiresAt
+	return nil
+}
+
+// release makes a link whose answer could not be recorded redeemable again
+func (s *Signer) release(link Link) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.used, link.ID)
+}
+
+func (s *Signer) mac(encoded string) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(encoded))
+	return h.Sum(nil)
+}
@@ -0,0 +1,69 @@
+package rsvp
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"meetsync/pkg/errors"
+)
+
+func TestSigner(t *testing.T) {
+	now := time.Date(2024, 3, 14, 9, 0, 0, 0, time.UTC)
+	signer := NewSigner([]byte("secret"), 24*time.Hour)
+	signer.now = func() time.Time { return now }
+
+	token := signer.Sign("meeting-1", "user-1", "slot-2")
+
+	var redeemed []Link
+	record := func(link Link) error {
+		redeemed = append(redeemed, link)
+		return nil
+	}
+
+	assert.NoError(t, signer.Redeem(token, record))
+	if assert.Len(t, redeemed, 1) {
+		assert.Equal(t, "meeting-1", redeemed[0].MeetingID)
+		assert.Equal(t, "user-1", redeemed[0].UserID)
+		assert.Equal(t, "slot-2", redeemed[0].SlotID)
+	}
+
+	// Links are single-use
+	err := signer.Redeem(token, record)
+	assert.ErrorIs(t, err, errors.ErrConflict)
+	assert.Len(t, redeemed, 1)
+
+	// Links whose answer could not be recorded can be followed again
+	retry := signer.Sign("meeting-1", "user-1", "slot-1")
+	assert.Error(t, signer.Redeem(retry, func(Link) error { return fmt.Errorf("storage down") }))
+	assert.NoError(t, signer.Redeem(retry, record))
+
+	// Links expire
+	expiring := signer.Sign("meeting-1", "user-1", "slot-3")
+	now = now.Add(24 * time.Hour)
+	err = signer.Redeem(expiring, record)
+	assert.ErrorIs(t, err, errors.ErrUnauthorized)
+	assert.Contains(t, err.Error(), "expired")
+}
+
+func TestSignerRejectsTamperedLinks(t *testing.T) {
+	signer := NewSigner([]byte("secret"), time.Hour)
+	token := signer.Sign("meeting-1", "user-1", "slot-1")
+	payload, signature, _ := strings.Cut(token, ".")
+	record := func(Link) error { return nil }
+
+	other := NewSigner([]byte("other"), time.Hour).Sign("meeting-1", "user-2", "slot-1")
+	otherPayload, _, _ := strings.Cut(other, ".")
+
+	for name, tampered := range map[string]string{
+		"other payload":     otherPayload + "." + signature,
+		"other secret":      other,
+		"missing signature": payload,
+		"garbage":           "not-a-token",
+	} {
+		assert.ErrorIs(t, signer.Redeem(tampered, record), errors.ErrUnauthorized, name)
+	}
+}
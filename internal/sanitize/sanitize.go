@@ -0,0 +1,69 @@
+// Package sanitize cleans user-supplied text so stored values are safe to
+// render in emails and UIs.
+package sanitize
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+
+	"meetsync/pkg/errors"
+)
+
+// Limits holds the maximum length, in characters, of free-text fields. A zero limit disables the check.
+type Limits struct {
+	Title int
+	Name  int
+}
+
+// DefaultLimits are the limits used when none are configured
+var DefaultLimits = Limits{
+	Title: 200,
+	Name:  100,
+}
+
+// htmlTagPattern matches HTML tags and comments
+var htmlTagPattern = regexp.MustCompile(`<!--.*?-->|</?[a-zA-Z][^<>]*>`)
+
+// SingleLine strips HTML tags and control characters from a single-line field,
+// collapses runs of whitespace and trims the result
+func SingleLine(s string) string {
+	s = htmlTagPattern.ReplaceAllString(s, "")
+	s = strings.Map(func(r rune) rune {
+		if r == utf8.RuneError {
+			return -1
+		}
+		if unicode.IsSpace(r) {
+			return ' '
+		}
+		if unicode.IsControl(r) || unicode.Is(unicode.Cf, r) {
+			return -1
+		}
+		return r
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// CheckLength returns a validation error when value is longer than max characters
+func CheckLength(field, value string, max int) error {
+	if max > 0 && utf8.RuneCountInString(value) > max {
+		return errors.NewValidationError(fmt.Sprintf("%s must be at most %d characters", field, max), "")
+	}
+	return nil
+}
+
+// Email returns the canonical form of an email address: surrounding whitespace trimmed,
+// lowercased and in Unicode normalization form C. Addresses are stored and compared in
+// this form everywhere, so "John@Example.com " and "john@example.com" are one address.
+func Email(address string) string {
+	return norm.NFC.String(strings.ToLower(strings.TrimSpace(address)))
+}
+
+// SameEmail reports whether two email addresses are the same once normalized
+func SameEmail(a, b string) bool {
+	return Email(a) == Email(b)
+}
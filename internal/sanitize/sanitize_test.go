@@ -0,0 +1,62 @@
+package sanitize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSingleLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain text", "Weekly sync", "Weekly sync"},
+		{"html tags", "<b>Weekly</b> <script>alert(1)</script>sync", "Weekly alert(1)sync"},
+		{"html comment", "Weekly<!-- hidden --> sync", "Weekly sync"},
+		{"comparison is kept", "Q1 < Q2 review", "Q1 < Q2 review"},
+		{"control characters", "Weekly\x00\x07 sync\u200b", "Weekly sync"},
+		{"newlines and tabs", "Weekly\r\n\tsync", "Weekly sync"},
+		{"surrounding whitespace", "   Weekly   sync  ", "Weekly sync"},
+		{"invalid utf-8", "Weekly \xff sync", "Weekly sync"},
+		{"non-latin text", "Réunion 週次", "Réunion 週次"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SingleLine(tt.input))
+		})
+	}
+}
+
+func TestCheckLength(t *testing.T) {
+	assert.NoError(t, CheckLength("Title", "週次", 2))
+	assert.NoError(t, CheckLength("Title", "anything", 0))
+
+	err := CheckLength("Title", "abc", 2)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Title must be at most 2 characters")
+}
+
+func TestEmail(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"canonical", "john@example.com", "john@example.com"},
+		{"mixed case and whitespace", " John@X.com \t", "john@x.com"},
+		{"decomposed accent", "Jose\u0301@example.com", "jos\u00e9@example.com"},
+		{"composed accent", "JOS\u00c9@example.com", "jos\u00e9@example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Email(tt.input))
+		})
+	}
+
+	assert.True(t, SameEmail("John@X.com ", "john@x.com"))
+	assert.True(t, SameEmail("jose\u0301@example.com", "JOS\u00c9@example.com"))
+	assert.False(t, SameEmail("john@x.com", "jon@x.com"))
+}
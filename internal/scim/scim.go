@@ -0,0 +1,392 @@
+// Package scim maps MeetSync users to SCIM 2.0 (RFC 7643, RFC 7644) User resources so
+// identity providers can provision and deprovision them
+package scim
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"meetsync/internal/models"
+	"meetsync/internal/sanitize"
+	"meetsync/pkg/errors"
+)
+
+// ContentType is the media type of SCIM requests and responses
+const ContentType = "application/scim+json"
+
+// Schema URNs of the resources and messages supported
+const (
+	SchemaUser         = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaListResponse = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp      = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError        = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// User is a SCIM User resource. userName and the primary email both carry the
+// MeetSync email, which identifies users.
+type User struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	ExternalID  string   `json:"externalId,omitempty"`
+	UserName    string   `json:"userName"`
+	Name        *Name    `json:"name,omitempty"`
+	DisplayName string   `json:"displayName,omitempty"`
+	Emails      []Email  `json:"emails,omitempty"`
+	Active      *bool    `json:"active,omitempty"`
+	Meta        *Meta    `json:"meta,omitempty"`
+}
+
+// Name is the name of a SCIM user
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// Email is an email address of a SCIM user
+type Email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// Meta is the metadata of a SCIM resource
+type Meta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location,omitempty"`
+}
+
+// ListResponse is a page of SCIM resources
+type ListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []User   `json:"Resources"`
+}
+
+// Error is a SCIM error response
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+// FromUser returns the SCIM representation of a user, served at location
+func FromUser(user models.User, location string) User {
+	active := user.Active()
+	givenName, familyName, _ := strings.Cut(user.Name, " ")
+	return User{
+		Schemas:     []string{SchemaUser},
+		ID:          user.ID,
+		ExternalID:  user.ExternalID,
+		UserName:    user.Email,
+		Name:        &Name{Formatted: user.Name, GivenName: givenName, FamilyName: familyName},
+		DisplayName: user.Name,
+		Emails:      []Email{{Value: user.Email, Type: "work", Primary: true}},
+		Active:      &active,
+		Meta: &Meta{
+			ResourceType: "User",
+			Created:      user.CreatedAt,
+			LastModified: user.UpdatedAt,
+			Location:     location,
+		},
+	}
+}
+
+// Provisioning returns the user state asserted by a SCIM User. The email is the
+// primary email, falling back to userName, and the name is the display name,
+// falling back to the formatted or given and family names. Users are active
+// unless active is false.
+func (u User) Provisioning() models.UserProvisioning {
+	email := u.UserName
+	for _, candidate := range u.Emails {
+		if candidate.Primary {
+			email = candidate.Value
+		}
+	}
+	if email == "" && len(u.Emails) > 0 {
+		email = u.Emails[0].Value
+	}
+
+	name := u.DisplayName
+	if name == "" && u.Name != nil {
+		name = u.Name.Formatted
+		if name == "" {
+			name = strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName)
+		}
+	}
+
+	return models.UserProvisioning{
+		ExternalID: u.ExternalID,
+		Name:       name,
+		Email:      email,
+		Active:     u.Active == nil || *u.Active,
+	}
+}
+
+// PatchRequest is a SCIM PATCH request body
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// PatchOperation is a single operation of a PATCH request
+type PatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
+
+// Apply applies the operations of a PATCH request to user. Operations without a
+// path take an object of attributes to set, as sent by most identity providers.
+func (p PatchRequest) Apply(user *User) error {
+	for _, operation := range p.Operations {
+		op := strings.ToLower(operation.Op)
+		switch op {
+		case "add", "replace":
+			if operation.Path != "" {
+				if err := user.set(operation.Path, operation.Value); err != nil {
+					return err
+				}
+				continue
+			}
+			attributes, ok := operation.Value.(map[string]any)
+			if !ok {
+				return invalidValue("Operations without a path need an object value")
+			}
+			for path, value := range attributes {
+				if err := user.set(path, value); err != nil {
+					return err
+				}
+			}
+		case "remove":
+			if operation.Path == "" {
+				return NewError(errors.NewValidationError("Remove operations need a path", ""), "noTarget")
+			}
+			if err := user.set(operation.Path, nil); err != nil {
+				return err
+			}
+		default:
+			return invalidValue(fmt.Sprintf("Unsupported operation %q", operation.Op))
+		}
+	}
+	return nil
+}
+
+// set sets the attribute at path, or clears it when value is nil
+func (u *User) set(path string, value any) error {
+	if u.Name == nil {
+		u.Name = &Name{}
+	}
+
+	path = strings.TrimPrefix(path, SchemaUser+":")
+	switch strings.ToLower(path) {
+	case "active":
+		active, err := boolValue(value)
+		if err != nil {
+			return err
+		}
+		u.Active = &active
+		return nil
+	case "emails", `emails[type eq "work"].value`, "emails[primary eq true].value":
+		if list, ok := value.([]any); ok {
+			value = primaryEmail(list)
+		}
+		email, err := stringValue(value)
+		if err != nil {
+			return err
+		}
+		u.Emails = []Email{{Value: email, Type: "work", Primary: true}}
+		return nil
+	case "name":
+		if value == nil {
+			*u.Name = Name{}
+			return nil
+		}
+		attributes, ok := value.(map[string]any)
+		if !ok {
+			return invalidValue("name must be an object")
+		}
+		for attribute, value := range attributes {
+			if err := u.set("name."+attribute, value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	text, err := stringValue(value)
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(path) {
+	case "username":
+		// userName and the primary email carry the same address
+		u.UserName = text
+		if strings.Contains(text, "@") {
+			u.Emails = []Email{{Value: text, Type: "work", Primary: true}}
+		}
+	case "externalid":
+		u.ExternalID = text
+	case "displayname":
+		u.DisplayName = text
+	case "name.formatted":
+		u.Name.Formatted = text
+	case "name.givenname":
+		u.Name.GivenName = text
+	case "name.familyname":
+		u.Name.FamilyName = text
+	default:
+		return NewError(errors.NewValidationError("Unsupported attribute", path), "invalidPath")
+	}
+
+	// The display and formatted names are derived from the name parts they replace
+	switch strings.ToLower(path) {
+	case "name.formatted":
+		u.DisplayName = ""
+	case "name.givenname", "name.familyname":
+		u.DisplayName, u.Name.Formatted = "", ""
+	}
+	return nil
+}
+
+// primaryEmail returns the value of the primary email of a list of emails, or of the
+// first one when none is primary
+func primaryEmail(emails []any) any {
+	var value any
+	for i, item := range emails {
+		email, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		if email["primary"] == true || i == 0 {
+			value = email["value"]
+		}
+	}
+	return value
+}
+
+// boolValue converts a PATCH value to a boolean. Some identity providers send
+// booleans as strings, such as "False".
+func boolValue(value any) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b, nil
+		}
+	}
+	return false, invalidValue("active must be a boolean")
+}
+
+// stringValue converts a PATCH value to a string; nil clears the attribute
+func stringValue(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	}
+	return "", invalidValue("Expected a string value")
+}
+
+// invalidValue returns a validation error with the invalidValue SCIM type
+func invalidValue(message string) error {
+	return NewError(errors.NewValidationError(message, ""), "invalidValue")
+}
+
+// Filter is a SCIM filter of the form `attribute eq "value"`, the only form
+// identity providers use to look up users before provisioning them
+type Filter struct {
+	Attribute string
+	Value     string
+}
+
+// ParseFilter parses a filter; an empty filter matches every user
+func ParseFilter(filter string) (Filter, error) {
+	filter = strings.TrimSpace(filter)
+	if filter == "" {
+		return Filter{}, nil
+	}
+
+	attribute, rest, found := strings.Cut(filter, " ")
+	operator, value, _ := strings.Cut(strings.TrimSpace(rest), " ")
+	value = strings.TrimSpace(value)
+	if !found || !strings.EqualFold(operator, "eq") {
+		return Filter{}, NewError(errors.NewValidationError("Unsupported filter", "use attribute eq \"value\""), "invalidFilter")
+	}
+	unquoted, err := strconv.Unquote(value)
+	if err != nil {
+		return Filter{}, NewError(errors.NewValidationError("Invalid filter value", value), "invalidFilter")
+	}
+
+	attribute = strings.ToLower(strings.TrimPrefix(attribute, SchemaUser+":"))
+	switch attribute {
+	case "id", "username", "externalid", "emails.value", "emails":
+	default:
+		return Filter{}, NewError(errors.NewValidationError("Unsupported filter attribute", attribute), "invalidFilter")
+	}
+	return Filter{Attribute: attribute, Value: unquoted}, nil
+}
+
+// Matches reports whether the filter matches a user. userName and emails are
+// compared case-insensitively, as emails are unique regardless of case.
+func (f Filter) Matches(user models.User) bool {
+	switch f.Attribute {
+	case "":
+		return true
+	case "id":
+		return user.ID == f.Value
+	case "externalid":
+		return user.ExternalID == f.Value
+	default:
+		return sanitize.SameEmail(user.Email, f.Value)
+	}
+}
+
+// ProtocolError is an error carrying the SCIM error type reported to clients
+type ProtocolError struct {
+	*errors.AppError
+	ScimType string
+}
+
+// Unwrap returns the underlying application error
+func (e *ProtocolError) Unwrap() error {
+	return e.AppError
+}
+
+// NewError attaches a SCIM error type, such as invalidFilter, to an application error
+func NewError(err *errors.AppError, scimType string) error {
+	return &ProtocolError{AppError: err, ScimType: scimType}
+}
+
+// NewErrorResponse returns the SCIM error response for an application error.
+// Without a SCIM error type, conflicts are reported as uniqueness errors and
+// validation errors as invalid values.
+func NewErrorResponse(err *errors.AppError, scimType string) Error {
+	if scimType == "" {
+		switch err.Type {
+		case errors.ErrorTypeConflict:
+			scimType = "uniqueness"
+		case errors.ErrorTypeValidation:
+			scimType = "invalidValue"
+		}
+	}
+	detail := err.Message
+	if err.Details != "" {
+		detail += ": " + err.Details
+	}
+	return Error{
+		Schemas:  []string{SchemaError},
+		Status:   strconv.Itoa(err.HTTPStatusCode()),
+		ScimType: scimType,
+		Detail:   detail,
+	}
+}
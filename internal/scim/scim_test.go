@@ -0,0 +1,142 @@
+package scim
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+)
+
+func TestFromUser(t *testing.T) {
+	deactivatedAt := time.Now()
+	user := models.User{ID: "u1", Name: "Jane Doe", Email: "jane@example.com", ExternalID: "00u1", DeactivatedAt: &deactivatedAt}
+
+	resource := FromUser(user, "https://meetsync.example.com/scim/v2/Users/u1")
+	assert.Equal(t, []string{SchemaUser}, resource.Schemas)
+	assert.Equal(t, "jane@example.com", resource.UserName)
+	assert.Equal(t, "Jane", resource.Name.GivenName)
+	assert.Equal(t, "Doe", resource.Name.FamilyName)
+	assert.False(t, *resource.Active)
+	assert.Equal(t, "User", resource.Meta.ResourceType)
+
+	// Round-tripping keeps the user unchanged
+	assert.Equal(t, models.UserProvisioning{ExternalID: "00u1", Name: "Jane Doe", Email: "jane@example.com"}, resource.Provisioning())
+}
+
+func TestUser_Provisioning(t *testing.T) {
+	tests := map[string]struct {
+		body     string
+		expected models.UserProvisioning
+	}{
+		"okta": {
+			`{"userName":"jane@example.com","name":{"givenName":"Jane","familyName":"Doe"},"emails":[{"value":"jane@example.com","primary":true}],"active":true}`,
+			models.UserProvisioning{Name: "Jane Doe", Email: "jane@example.com", Active: true},
+		},
+		"azure": {
+			`{"externalId":"a1","userName":"jane@corp.example.com","displayName":"Jane D.","emails":[{"value":"jane@example.com","type":"work","primary":true}]}`,
+			models.UserProvisioning{ExternalID: "a1", Name: "Jane D.", Email: "jane@example.com", Active: true},
+		},
+		"username only": {
+			`{"userName":"jane@example.com","name":{"formatted":"Jane Doe"},"active":false}`,
+			models.UserProvisioning{Name: "Jane Doe", Email: "jane@example.com"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var user User
+			assert.NoError(t, json.Unmarshal([]byte(tt.body), &user))
+			assert.Equal(t, tt.expected, user.Provisioning())
+		})
+	}
+}
+
+func TestPatchRequest_Apply(t *testing.T) {
+	tests := map[string]struct {
+		body     string
+		expected models.UserProvisioning
+		scimType string
+	}{
+		"okta deactivation": {
+			body:     `{"Operations":[{"op":"replace","value":{"active":false}}]}`,
+			expected: models.UserProvisioning{ExternalID: "00u1", Name: "Jane Doe", Email: "jane@example.com"},
+		},
+		"azure deactivation": {
+			body:     `{"Operations":[{"op":"Replace","path":"active","value":"False"}]}`,
+			expected: models.UserProvisioning{ExternalID: "00u1", Name: "Jane Doe", Email: "jane@example.com"},
+		},
+		"rename and change email": {
+			body: `{"Operations":[{"op":"replace","path":"name.familyName","value":"Smith"},
+				{"op":"replace","path":"userName","value":"jane.smith@example.com"},
+				{"op":"remove","path":"externalId"}]}`,
+			expected: models.UserProvisioning{Name: "Jane Smith", Email: "jane.smith@example.com", Active: true},
+		},
+		"replace emails": {
+			body:     `{"Operations":[{"op":"add","path":"emails","value":[{"value":"old@example.com"},{"value":"new@example.com","primary":true}]}]}`,
+			expected: models.UserProvisioning{ExternalID: "00u1", Name: "Jane Doe", Email: "new@example.com", Active: true},
+		},
+		"unsupported attribute": {
+			body:     `{"Operations":[{"op":"replace","path":"title","value":"CEO"}]}`,
+			scimType: "invalidPath",
+		},
+		"invalid active": {
+			body:     `{"Operations":[{"op":"replace","path":"active","value":"maybe"}]}`,
+			scimType: "invalidValue",
+		},
+		"remove without path": {
+			body:     `{"Operations":[{"op":"remove"}]}`,
+			scimType: "noTarget",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			user := FromUser(models.User{ID: "u1", Name: "Jane Doe", Email: "jane@example.com", ExternalID: "00u1"}, "")
+			var patch PatchRequest
+			assert.NoError(t, json.Unmarshal([]byte(tt.body), &patch))
+
+			err := patch.Apply(&user)
+			if tt.scimType != "" {
+				var protocolErr *ProtocolError
+				if assert.True(t, errors.As(err, &protocolErr)) {
+					assert.Equal(t, tt.scimType, protocolErr.ScimType)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, user.Provisioning())
+		})
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	user := models.User{ID: "u1", Email: "Jane@example.com", ExternalID: "00u1"}
+
+	for _, filter := range []string{"", `userName eq "jane@example.com"`, `externalId eq "00u1"`, `emails.value EQ "JANE@example.com"`, `id eq "u1"`} {
+		parsed, err := ParseFilter(filter)
+		assert.NoError(t, err, filter)
+		assert.True(t, parsed.Matches(user), filter)
+	}
+
+	parsed, err := ParseFilter(`userName eq "john@example.com"`)
+	assert.NoError(t, err)
+	assert.False(t, parsed.Matches(user))
+
+	for _, filter := range []string{`userName sw "jane"`, `userName eq jane`, `title eq "CEO"`, "userName"} {
+		_, err := ParseFilter(filter)
+		assert.True(t, errors.Is(err, errors.ErrValidation), filter)
+	}
+}
+
+func TestNewErrorResponse(t *testing.T) {
+	resp := NewErrorResponse(errors.NewConflictError("Email is already in use"), "")
+	assert.Equal(t, "409", resp.Status)
+	assert.Equal(t, "uniqueness", resp.ScimType)
+	assert.Equal(t, []string{SchemaError}, resp.Schemas)
+
+	resp = NewErrorResponse(errors.NewNotFoundError("User not found"), "")
+	assert.Equal(t, "404", resp.Status)
+	assert.Empty(t, resp.ScimType)
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+
+	"meetsync/internal/directory"
+	"meetsync/internal/interfaces"
+	"meetsync/internal/models"
+	"meetsync/internal/pagination"
+	"meetsync/internal/repositories"
+	"meetsync/pkg/errors"
+	"meetsync/pkg/logs"
+)
+
+// AdminServiceImpl implements the AdminService interface
+type AdminServiceImpl struct {
+	userService    interfaces.UserService
+	meetingService interfaces.MeetingService
+	auditLog       repositories.AuditRepository
+
+	directory   directory.Directory
+	syncOptions models.DirectorySyncOptions
+	syncMu      sync.Mutex // serializes directory syncs
+}
+
+var _ interfaces.AdminService = (*AdminServiceImpl)(nil) // Verify AdminServiceImpl implements AdminService interface
+
+// AdminServiceOption configures optional dependencies of an AdminServiceImpl
+type AdminServiceOption func(*AdminServiceImpl)
+
+// WithDirectory sets the directory users are synchronized from, and how directory users
+// are reconciled with existing users
+func WithDirectory(dir directory.Directory, options models.DirectorySyncOptions) AdminServiceOption {
+	return func(s *AdminServiceImpl) {
+		s.directory = dir
+		s.syncOptions = options
+	}
+}
+
+// WithAuditRepository sets the repository the audit log is kept in, such as one shared
+// by replicas; by default it is kept in process memory
+func WithAuditRepository(repo repositories.AuditRepository) AdminServiceOption {
+	return func(s *AdminServiceImpl) {
+		s.auditLog = repo
+	}
+}
+
+// NewAdminService creates a new AdminService
+func NewAdminService(userService interfaces.UserService, meetingService interfaces.MeetingService, opts ...AdminServiceOption) interfaces.AdminService {
+	s := &AdminServiceImpl{
+		userService:    userService,
+		meetingService: meetingService,
+		auditLog:       repositories.NewInMemoryAuditRepository(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// MergeUsers merges a duplicate user into another user. All organizer roles,
+// participations and availabilities of the source user are moved to the target
+// user before the source user is removed.
+//
+// The stores cannot share a transaction, so the steps run in an order that leaves a
+// retryable state when one fails: the data is moved in one transaction of the meeting
+// store, the merge is recorded, and only then is the source user deleted. Until the
+// delete the source user is left in place, and retrying the merge finishes it, since
+// moving data that was already moved is a no-op.
+func (s *AdminServiceImpl) MergeUsers(sourceUserID string, targetUserID string) (models.UserReassignment, error) {
+	// Validate input
+	if sourceUserID == "" || targetUserID == "" {
+		return models.UserReassignment{}, errors.NewValidationError("Source and target user IDs are required", "")
+	}
+	if sourceUserID == targetUserID {
+		return models.UserReassignment{}, errors.NewValidationError("Cannot merge a user into itself", "")
+	}
+
+	source, err := s.userService.GetUserByID(sourceUserID)
+	if err != nil {
+		return models.UserReassignment{}, errors.NewNotFoundError("Source user not found")
+	}
+	target, err := s.userService.GetUserByID(targetUserID)
+	if err != nil {
+		return models.UserReassignment{}, errors.NewNotFoundError("Target user not found")
+	}
+
+	result, err := s.meetingService.ReassignUser(source.ID, target.ID)
+	if err != nil {
+		return models.UserReassignment{}, err
+	}
+
+	details := fmt.Sprintf("Merged %s (%s) into %s (%s): %d meetings organized, %d participations, %d availabilities",
+		source.ID, source.Email, target.ID, target.Email, result.MeetingsOrganized, result.Participations, result.Availabilities)
+	if _, err := s.auditLog.Append(models.AuditEntry{
+		Action:    models.AuditActionUsersMerged,
+		SubjectID: target.ID,
+		Details:   details,
+	}); err != nil {
+		logs.Warn("Moved the data of %s to %s but failed to record the merge; retry the merge to finish it: %v", source.ID, target.ID, err)
+		return models.UserReassignment{}, err
+	}
+
+	if err := s.userService.DeleteUser(source.ID); err != nil {
+		logs.Warn("Merged %s into %s but failed to delete %s; retry the merge to finish it: %v", source.ID, target.ID, source.ID, err)
+		return models.UserReassignment{}, err
+	}
+
+	logs.Info("%s", details)
+	return result, nil
+}
+
+// ImportUsers creates users in bulk and records the import in the audit log
+func (s *AdminServiceImpl) ImportUsers(rows []models.UserImportRow, options models.UserImportOptions) (models.UserImportResult, error) {
+	result, err := s.userService.ImportUsers(rows, options)
+	if err != nil {
+		return models.UserImportResult{}, err
+	}
+
+	details := fmt.Sprintf("Imported %d rows: %d created, %d updated, %d skipped, %d failed",
+		len(rows), result.Created, result.Updated, result.Skipped, result.Failed)
+	if _, err := s.auditLog.Append(models.AuditEntry{
+		Action:  models.AuditActionUsersImported,
+		Details: details,
+	}); err != nil {
+		return models.UserImportResult{}, err
+	}
+
+	logs.Info("%s", details)
+	return result, nil
+}
+
+// Bootstrap ensures the declared administrator and tokens exist and records in the audit
+// log any bootstrap that changed something, so reapplying one leaves no trace
+func (s *AdminServiceImpl) Bootstrap(bootstrap models.Bootstrap) (models.BootstrapResult, error) {
+	result, err := s.userService.Bootstrap(bootstrap)
+	if err != nil {
+		return models.BootstrapResult{}, err
+	}
+	if !result.Changed() {
+		return result, nil
+	}
+
+	changed := 0
+	for _, token := range result.Tokens {
+		if token.Status != models.BootstrapUnchanged {
+			changed++
+		}
+	}
+	details := fmt.Sprintf("Bootstrapped admin user %s (%s) with %d of %d tokens created or replaced",
+		result.Admin.ID, result.AdminStatus, changed, len(result.Tokens))
+	if _, err := s.auditLog.Append(models.AuditEntry{
+		Action:    models.AuditActionBootstrapped,
+		SubjectID: result.Admin.ID,
+		Details:   details,
+	}); err != nil {
+		return models.BootstrapResult{}, err
+	}
+
+	logs.Info("%s", details)
+	return result, nil
+}
+
+// ListAuditLog returns a page of recorded administrative actions and the cursor of the next page
+func (s *AdminServiceImpl) ListAuditLog(cursor string, limit int) ([]models.AuditEntry, string, error) {
+	after, err := pagination.Decode(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	limit = pagination.NormalizeLimit(limit)
+
+	entries, err := s.auditLog.List(after, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(entries) > limit {
+		entries = entries[:limit]
+		last := entries[limit-1]
+		nextCursor = pagination.Cursor{Timestamp: last.OccurredAt, ID: last.ID}.Encode()
+	}
+	return entries, nextCursor, nil
+}
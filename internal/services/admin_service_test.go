@@ -0,0 +1,475 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"meetsync/internal/directory"
+	"meetsync/internal/models"
+	"meetsync/internal/repositories"
+	"meetsync/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminService_MergeUsers(t *testing.T) {
+	userService := NewUserService()
+	meetingService := NewMeetingService(userService)
+	adminService := NewAdminService(userService, meetingService)
+
+	duplicate, err := userService.CreateUser("Jane", "jane@work.example.com")
+	assert.NoError(t, err)
+	jane, err := userService.CreateUser("Jane", "jane@example.com")
+	assert.NoError(t, err)
+	other, err := userService.CreateUser("Other", "other@example.com")
+	assert.NoError(t, err)
+
+	timeSlots := createTestTimeSlots()
+
+	// The duplicate organizes one meeting and participates in another alongside the target user
+	organized, _, err := meetingService.CreateMeeting(models.MeetingInput{
+		Title:             "Organized by duplicate",
+		OrganizerID:       duplicate.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		ParticipantIDs:    []string{other.ID},
+	})
+	assert.NoError(t, err)
+	shared, _, err := meetingService.CreateMeeting(models.MeetingInput{
+		Title:             "Shared",
+		OrganizerID:       other.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		ParticipantIDs:    []string{duplicate.ID, jane.ID},
+	})
+	assert.NoError(t, err)
+
+	_, err = meetingService.AddAvailability(duplicate.ID, shared.ID, shared.ProposedSlots[:1], false)
+	assert.NoError(t, err)
+	_, err = meetingService.AddAvailability(jane.ID, shared.ID, shared.ProposedSlots[1:], false)
+	assert.NoError(t, err)
+
+	result, err := adminService.MergeUsers(duplicate.ID, jane.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.UserReassignment{
+		SourceUserID:      duplicate.ID,
+		TargetUserID:      jane.ID,
+		MeetingsOrganized: 1,
+		Participations:    1,
+		Availabilities:    1,
+	}, result)
+
+	// The duplicate user is removed
+	_, err = userService.GetUserByID(duplicate.ID)
+	assert.Error(t, err)
+
+	// Organizer role moved to the target user
+	ms := meetingService.(*MeetingServiceImpl)
+	meeting, err := ms.repository.GetMeetingByID(organized.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, jane.ID, meeting.OrganizerID)
+
+	// Participation is not duplicated and availabilities are combined
+	meeting, err = ms.repository.GetMeetingByID(shared.ID)
+	assert.NoError(t, err)
+	assert.Len(t, meeting.Participants, 1)
+	assert.Equal(t, jane.ID, meeting.Participants[0].ID)
+
+	availability, err := meetingService.GetAvailability(jane.ID, shared.ID)
+	assert.NoError(t, err)
+	assert.Len(t, availability.AvailableSlots, 2)
+	_, err = meetingService.GetAvailability(duplicate.ID, shared.ID)
+	assert.Error(t, err)
+
+	// The merge is recorded in the audit log
+	entries, _, err := adminService.ListAuditLog("", 0)
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, models.AuditActionUsersMerged, entries[0].Action)
+		assert.Equal(t, jane.ID, entries[0].SubjectID)
+		assert.Contains(t, entries[0].Details, duplicate.ID)
+	}
+}
+
+// failingAuditRepository fails to append entries while fail is set
+type failingAuditRepository struct {
+	*repositories.InMemoryAuditRepository
+	fail bool
+}
+
+func (r *failingAuditRepository) Append(entry models.AuditEntry) (models.AuditEntry, error) {
+	if r.fail {
+		return models.AuditEntry{}, errors.NewInternalError("Audit log unavailable", nil)
+	}
+	return r.InMemoryAuditRepository.Append(entry)
+}
+
+func TestAdminService_MergeUsersRetry(t *testing.T) {
+	auditLog := &failingAuditRepository{InMemoryAuditRepository: repositories.NewInMemoryAuditRepository(), fail: true}
+	userService := NewUserService()
+	meetingService := NewMeetingService(userService)
+	adminService := NewAdminService(userService, meetingService, WithAuditRepository(auditLog))
+
+	duplicate, err := userService.CreateUser("Jane", "jane@work.example.com")
+	assert.NoError(t, err)
+	jane, err := userService.CreateUser("Jane", "jane@example.com")
+	assert.NoError(t, err)
+	organized, _, err := meetingService.CreateMeeting(models.MeetingInput{
+		Title:             "Organized by duplicate",
+		OrganizerID:       duplicate.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     createTestTimeSlots(),
+	})
+	assert.NoError(t, err)
+
+	// A merge that cannot be recorded keeps the source user
+	_, err = adminService.MergeUsers(duplicate.ID, jane.ID)
+	assert.Error(t, err)
+	_, err = userService.GetUserByID(duplicate.ID)
+	assert.NoError(t, err)
+
+	// Retrying finishes the merge
+	auditLog.fail = false
+	_, err = adminService.MergeUsers(duplicate.ID, jane.ID)
+	assert.NoError(t, err)
+	_, err = userService.GetUserByID(duplicate.ID)
+	assert.Error(t, err)
+	meeting, err := meetingService.(*MeetingServiceImpl).repository.GetMeetingByID(organized.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, jane.ID, meeting.OrganizerID)
+	entries, _, err := adminService.ListAuditLog("", 0)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestAdminService_MergeUsersValidation(t *testing.T) {
+	userService := NewUserService()
+	adminService := NewAdminService(userService, NewMeetingService(userService))
+
+	user, err := userService.CreateUser("Jane", "jane@example.com")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		sourceUserID string
+		targetUserID string
+		errType      errors.ErrorType
+	}{
+		{"missing source", "", user.ID, errors.ErrorTypeValidation},
+		{"same user", user.ID, user.ID, errors.ErrorTypeValidation},
+		{"unknown source", "non-existent", user.ID, errors.ErrorTypeNotFound},
+		{"unknown target", user.ID, "non-existent", errors.ErrorTypeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := adminService.MergeUsers(tt.sourceUserID, tt.targetUserID)
+			assert.Error(t, err)
+			appErr, ok := err.(*errors.AppError)
+			assert.True(t, ok)
+			assert.Equal(t, tt.errType, appErr.Type)
+		})
+	}
+
+	entries, _, err := adminService.ListAuditLog("", 0)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestAdminService_ImportUsers(t *testing.T) {
+	userService := NewUserService()
+	adminService := NewAdminService(userService, NewMeetingService(userService))
+
+	result, err := adminService.ImportUsers([]models.UserImportRow{
+		{Line: 2, Name: "Jane", Email: "jane@example.com"},
+		{Line: 3, Name: "John", Email: ""},
+	}, models.UserImportOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 1, result.Failed)
+
+	entries, _, err := adminService.ListAuditLog("", 0)
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, models.AuditActionUsersImported, entries[0].Action)
+		assert.Contains(t, entries[0].Details, "1 created")
+	}
+}
+
+func TestAdminService_SharedAuditLog(t *testing.T) {
+	// Replicas sharing an audit repository list each other's entries
+	auditLog := repositories.NewInMemoryAuditRepository()
+	userService := NewUserService()
+	first := NewAdminService(userService, NewMeetingService(userService), WithAuditRepository(auditLog))
+	second := NewAdminService(userService, NewMeetingService(userService), WithAuditRepository(auditLog))
+
+	_, err := first.ImportUsers([]models.UserImportRow{{Line: 2, Name: "Jane", Email: "jane@example.com"}}, models.UserImportOptions{})
+	assert.NoError(t, err)
+	entries, _, err := second.ListAuditLog("", 0)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestAdminService_Bootstrap(t *testing.T) {
+	userService := NewUserService()
+	adminService := NewAdminService(userService, NewMeetingService(userService))
+	declaredSecret := "msp_" + strings.Repeat("ab", 32)
+	bootstrap := models.Bootstrap{
+		Admin: models.BootstrapAdmin{Name: "Ops", Email: "ops@example.com"},
+		Tokens: []models.BootstrapToken{
+			{Name: "deploy", Scopes: []models.Scope{models.ScopeReadMeetings}, Secret: declaredSecret},
+			{Name: "sync", Scopes: []models.Scope{models.ScopeWriteAvailability}},
+		},
+	}
+
+	result, err := adminService.Bootstrap(bootstrap)
+	assert.NoError(t, err)
+	assert.Equal(t, models.BootstrapCreated, result.AdminStatus)
+	assert.True(t, result.Admin.EmailVerified)
+	if assert.Len(t, result.Tokens, 2) {
+		assert.Equal(t, models.BootstrapCreated, result.Tokens[0].Status)
+		assert.Empty(t, result.Tokens[0].Secret, "declared secrets are not echoed")
+		assert.NotEmpty(t, result.Tokens[1].Secret)
+	}
+	token, err := userService.AuthenticateAccessToken(declaredSecret, models.TokenClient{})
+	assert.NoError(t, err)
+	assert.Equal(t, result.Admin.ID, token.UserID)
+
+	// Applying it again changes nothing and records nothing
+	again, err := adminService.Bootstrap(bootstrap)
+	assert.NoError(t, err)
+	assert.False(t, again.Changed())
+	assert.Equal(t, result.Admin.ID, again.Admin.ID)
+	assert.Equal(t, result.Tokens[1].Token.ID, again.Tokens[1].Token.ID)
+	assert.Empty(t, again.Tokens[1].Secret)
+
+	// Changing the scopes of a token replaces it
+	bootstrap.Tokens[0].Scopes = []models.Scope{models.ScopeReadMeetings, models.ScopeWriteMeetings}
+	replaced, err := adminService.Bootstrap(bootstrap)
+	assert.NoError(t, err)
+	assert.Equal(t, models.BootstrapReplaced, replaced.Tokens[0].Status)
+	tokens, err := userService.ListAccessTokens(result.Admin.ID)
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 2)
+
+	entries, _, err := adminService.ListAuditLog("", 0)
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, models.AuditActionBootstrapped, entries[0].Action)
+		assert.Equal(t, result.Admin.ID, entries[0].SubjectID)
+	}
+
+	// Invalid declarations change nothing
+	bootstrap.Tokens = append(bootstrap.Tokens, models.BootstrapToken{Name: "weak", Scopes: []models.Scope{models.ScopeReadMeetings}, Secret: "msp_short"})
+	_, err = adminService.Bootstrap(bootstrap)
+	appErr, ok := errors.AsAppError(err)
+	if assert.True(t, ok) {
+		assert.Equal(t, errors.ErrorTypeValidation, appErr.Type)
+	}
+	tokens, err = userService.ListAccessTokens(result.Admin.ID)
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 2)
+}
+
+func TestAdminService_BackupAndRestore(t *testing.T) {
+	userService := NewUserService()
+	meetingService := NewMeetingService(userService)
+	adminService := NewAdminService(userService, meetingService)
+
+	organizer, err := userService.CreateUser("Organizer", "organizer@example.com")
+	assert.NoError(t, err)
+	participant, err := userService.CreateUser("Participant", "participant@example.com")
+	assert.NoError(t, err)
+	_, secret, err := userService.CreateAccessToken(organizer.ID, "script", []models.Scope{models.ScopeReadMeetings})
+	assert.NoError(t, err)
+	meeting, _, err := meetingService.CreateMeeting(models.MeetingInput{
+		Title:             "Backed up",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     createTestTimeSlots(),
+		ParticipantIDs:    []string{participant.ID},
+	})
+	assert.NoError(t, err)
+	_, err = meetingService.AddAvailability(participant.ID, meeting.ID, meeting.ProposedSlots, false)
+	assert.NoError(t, err)
+
+	backup, err := adminService.Backup()
+	assert.NoError(t, err)
+	assert.Equal(t, models.BackupVersion, backup.Version)
+	assert.NotEmpty(t, backup.Checksum)
+
+	// Backups are downloaded and uploaded as JSON
+	encoded, err := json.Marshal(backup)
+	assert.NoError(t, err)
+	var decoded models.Backup
+	assert.NoError(t, json.Unmarshal(encoded, &decoded))
+
+	t.Run("restoring into an empty deployment", func(t *testing.T) {
+		restoredUsers := NewUserService()
+		restoredMeetings := NewMeetingService(restoredUsers)
+		restoredAdmin := NewAdminService(restoredUsers, restoredMeetings)
+
+		summary, err := restoredAdmin.RestoreBackup(decoded)
+		assert.NoError(t, err)
+		assert.Equal(t, models.BackupSummary{Users: 2, Meetings: 1, Availabilities: 1, AuditEntries: 0}, summary)
+
+		availability, err := restoredMeetings.GetAvailability(participant.ID, meeting.ID)
+		assert.NoError(t, err)
+		assert.Len(t, availability.AvailableSlots, 2)
+		token, err := restoredUsers.AuthenticateAccessToken(secret, models.TokenClient{})
+		assert.NoError(t, err)
+		assert.Equal(t, organizer.ID, token.UserID)
+
+		entries, _, err := restoredAdmin.ListAuditLog("", 0)
+		assert.NoError(t, err)
+		if assert.Len(t, entries, 1) {
+			assert.Equal(t, models.AuditActionBackupRestored, entries[0].Action)
+		}
+	})
+
+	t.Run("tampered backups are rejected", func(t *testing.T) {
+		tampered := decoded
+		tampered.Data.Meetings.Meetings = []models.Meeting{decoded.Data.Meetings.Meetings[0]}
+		tampered.Data.Meetings.Meetings[0].Title = "Changed"
+		_, err := adminService.VerifyBackup(tampered)
+		assert.Error(t, err)
+		appErr, ok := err.(*errors.AppError)
+		assert.True(t, ok)
+		assert.Equal(t, "Backup checksum mismatch", appErr.Message)
+
+		tampered = decoded
+		tampered.Version = models.BackupVersion + 1
+		_, err = adminService.VerifyBackup(tampered)
+		assert.Error(t, err)
+	})
+
+	t.Run("inconsistent backups are rejected", func(t *testing.T) {
+		inconsistent := decoded
+		inconsistent.Data.Users.Users = []models.User{decoded.Data.Users.Users[0]}
+		inconsistent.Checksum, err = backupChecksum(inconsistent.Data)
+		assert.NoError(t, err)
+
+		_, err := adminService.RestoreBackup(inconsistent)
+		assert.Error(t, err)
+		appErr, ok := err.(*errors.AppError)
+		assert.True(t, ok)
+		assert.Equal(t, "Backup is inconsistent", appErr.Message)
+
+		// Nothing was restored
+		users, err := userService.ListUsers()
+		assert.NoError(t, err)
+		assert.Len(t, users, 2)
+	})
+}
+
+// staticDirectory is a directory listing fixed entries
+type staticDirectory struct {
+	entries []directory.Entry
+	err     error
+}
+
+func (d *staticDirectory) Users() ([]directory.Entry, error) {
+	return append([]directory.Entry(nil), d.entries...), d.err
+}
+
+func TestAdminService_SyncDirectory(t *testing.T) {
+	userService := NewUserService()
+	dir := &staticDirectory{}
+	adminService := NewAdminService(userService, NewMeetingService(userService),
+		WithDirectory(dir, models.DirectorySyncOptions{OnConflict: models.DirectoryConflictLink, OnMissing: models.DirectoryMissingDeactivate}))
+
+	local, err := userService.CreateUser("Jane Local", "jane@example.com")
+	assert.NoError(t, err)
+	scim, err := userService.ProvisionUser(models.UserProvisioning{ExternalID: "idp-1", Name: "Kim", Email: "kim@example.com", Active: true})
+	assert.NoError(t, err)
+
+	dir.entries = []directory.Entry{
+		{ID: "u-jane", DN: "uid=jane,ou=people", Name: "Jane Doe", Email: "Jane@example.com"},
+		{ID: "u-john", DN: "uid=john,ou=people", Name: "John Roe", Email: "john@example.com"},
+		{ID: "u-kim", DN: "uid=kim,ou=people", Name: "Kim", Email: "kim@example.com"},
+		{ID: "u-noemail", DN: "uid=noemail,ou=people", Name: "No Email"},
+	}
+
+	// Dry runs report the plan without changing users
+	result, err := adminService.SyncDirectory(true)
+	assert.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 1, result.Linked)
+	assert.Equal(t, 2, result.Conflicts)
+	assert.Equal(t, 1, result.Deactivated)
+	users, err := userService.ListUsers()
+	assert.NoError(t, err)
+	assert.Len(t, users, 2)
+
+	result, err = adminService.SyncDirectory(false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 1, result.Linked)
+	assert.Equal(t, 0, result.Failed)
+
+	jane, err := userService.GetUserByID(local.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "u-jane", jane.ExternalID)
+	assert.Equal(t, "Jane Doe", jane.Name)
+	kim, err := userService.GetUserByID(scim.ID)
+	assert.NoError(t, err)
+	assert.False(t, kim.Active(), "users linked elsewhere and missing from the directory are deactivated")
+
+	// Later syncs only apply what changed in the directory
+	dir.entries = []directory.Entry{
+		{ID: "u-jane", DN: "uid=jane,ou=people", Name: "Jane Smith", Email: "jane.smith@example.com"},
+	}
+	result, err = adminService.SyncDirectory(false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, 1, result.Deactivated)
+	jane, err = userService.GetUserByID(local.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "jane.smith@example.com", jane.Email)
+
+	result, err = adminService.SyncDirectory(false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Unchanged)
+	assert.Empty(t, result.Changes)
+
+	entries, _, err := adminService.ListAuditLog("", 0)
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 3) {
+		assert.Equal(t, models.AuditActionDirectorySynced, entries[0].Action)
+	}
+
+	dir.err = errors.NewUnavailableError("LDAP server is down", 0, nil)
+	_, err = adminService.SyncDirectory(false)
+	assert.True(t, errors.Is(err, errors.ErrUnavailable))
+
+	_, err = NewAdminService(userService, NewMeetingService(userService)).SyncDirectory(true)
+	assert.True(t, errors.Is(err, errors.ErrValidation))
+}
+
+func TestAdminService_SyncDirectorySkipsConflicts(t *testing.T) {
+	userService := NewUserService()
+	dir := &staticDirectory{entries: []directory.Entry{
+		{ID: "u-jane", DN: "uid=jane,ou=people", Name: "Jane Doe", Email: "jane@example.com"},
+		{ID: "u-jane2", DN: "uid=jane2,ou=people", Name: "Jane Again", Email: "JANE@example.com"},
+	}}
+	adminService := NewAdminService(userService, NewMeetingService(userService),
+		WithDirectory(dir, models.DirectorySyncOptions{OnConflict: models.DirectoryConflictSkip, OnMissing: models.DirectoryMissingKeep}))
+
+	local, err := userService.CreateUser("Jane Local", "jane@example.com")
+	assert.NoError(t, err)
+
+	result, err := adminService.SyncDirectory(false)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Conflicts)
+	if assert.Len(t, result.Changes, 2) {
+		assert.Equal(t, local.ID, result.Changes[0].UserID)
+		assert.Equal(t, "Email belongs to an existing user", result.Changes[0].Reason)
+		assert.Equal(t, "Email is used by another directory entry", result.Changes[1].Reason)
+	}
+
+	jane, err := userService.GetUserByID(local.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, jane.ExternalID)
+}
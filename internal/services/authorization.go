@@ -0,0 +1,31 @@
+package services
+
+import (
+	"meetsync/pkg/errors"
+)
+
+// AuthorizeMeetingChange checks that a user may change a meeting: updating, publishing,
+// confirming, cancelling or deleting it is reserved to its organizer
+func (s *MeetingServiceImpl) AuthorizeMeetingChange(userID, meetingID string) error {
+	meeting, err := s.repository.GetMeetingByID(meetingID)
+	if err != nil {
+		return err
+	}
+	if meeting.OrganizerID != userID {
+		return errors.NewForbiddenError("Only the organizer can change the meeting")
+	}
+	return nil
+}
+
+// AuthorizeAvailabilityChange checks that a user may change an availability, which only
+// the participant who submitted it can
+func (s *MeetingServiceImpl) AuthorizeAvailabilityChange(userID, availabilityID string) error {
+	availability, err := s.repository.GetAvailabilityByID(availabilityID)
+	if err != nil {
+		return err
+	}
+	if availability.ParticipantID != userID {
+		return errors.NewForbiddenError("Only the participant can change their availability")
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+)
+
+func TestMeetingService_Authorization(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+	timeSlots := createTestTimeSlots()
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		ParticipantIDs:    []string{participants[0].ID},
+	})
+	require.NoError(t, err)
+	availability, err := service.AddAvailability(participants[0].ID, meeting.ID, timeSlots[:1], false)
+	require.NoError(t, err)
+
+	assert.NoError(t, service.AuthorizeMeetingChange(organizer.ID, meeting.ID))
+	assert.True(t, errors.Is(service.AuthorizeMeetingChange(participants[0].ID, meeting.ID), errors.ErrForbidden))
+	assert.True(t, errors.Is(service.AuthorizeMeetingChange(organizer.ID, "missing"), errors.ErrNotFound))
+
+	assert.NoError(t, service.AuthorizeAvailabilityChange(participants[0].ID, availability.ID))
+	assert.True(t, errors.Is(service.AuthorizeAvailabilityChange(organizer.ID, availability.ID), errors.ErrForbidden))
+	assert.True(t, errors.Is(service.AuthorizeAvailabilityChange(participants[0].ID, "missing"), errors.ErrNotFound))
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+	"meetsync/pkg/logs"
+)
+
+// Backup copies every store into a checksummed backup. The caller must keep writes out
+// while it runs for the stores to be consistent with each other.
+func (s *AdminServiceImpl) Backup() (models.Backup, error) {
+	users, err := s.userService.Snapshot()
+	if err != nil {
+		return models.Backup{}, err
+	}
+	meetings, err := s.meetingService.Snapshot()
+	if err != nil {
+		return models.Backup{}, err
+	}
+	auditLog, err := s.auditLog.Snapshot()
+	if err != nil {
+		return models.Backup{}, err
+	}
+
+	backup := models.Backup{
+		Version:   models.BackupVersion,
+		CreatedAt: time.Now(),
+		Data: models.BackupData{
+			Users:    users,
+			Meetings: meetings,
+			AuditLog: auditLog,
+		},
+	}
+	if backup.Checksum, err = backupChecksum(backup.Data); err != nil {
+		return models.Backup{}, err
+	}
+	return backup, nil
+}
+
+// VerifyBackup checks the version, checksum and internal references of a backup without restoring it
+func (s *AdminServiceImpl) VerifyBackup(backup models.Backup) (models.BackupSummary, error) {
+	if backup.Version != models.BackupVersion {
+		return models.BackupSummary{}, errors.NewValidationError("Unsupported backup version", fmt.Sprintf("expected version %d, got %d", models.BackupVersion, backup.Version))
+	}
+	checksum, err := backupChecksum(backup.Data)
+	if err != nil {
+		return models.BackupSummary{}, err
+	}
+	if checksum != backup.Checksum {
+		return models.BackupSummary{}, errors.NewValidationError("Backup checksum mismatch", "the backup is corrupted or was modified")
+	}
+	if err := checkBackupReferences(backup.Data); err != nil {
+		return models.BackupSummary{}, err
+	}
+
+	return models.BackupSummary{
+		Users:          len(backup.Data.Users.Users),
+		Meetings:       len(backup.Data.Meetings.Meetings),
+		Availabilities: len(backup.Data.Meetings.Availabilities),
+		AuditEntries:   len(backup.Data.AuditLog),
+	}, nil
+}
+
+// RestoreBackup verifies a backup and replaces every store with its contents. The caller
+// must keep other requests out while it runs.
+func (s *AdminServiceImpl) RestoreBackup(backup models.Backup) (models.BackupSummary, error) {
+	summary, err := s.VerifyBackup(backup)
+	if err != nil {
+		return models.BackupSummary{}, err
+	}
+
+	if err := s.userService.Restore(backup.Data.Users); err != nil {
+		return models.BackupSummary{}, err
+	}
+	if err := s.meetingService.Restore(backup.Data.Meetings); err != nil {
+		return models.BackupSummary{}, err
+	}
+	if err := s.auditLog.Restore(backup.Data.AuditLog); err != nil {
+		return models.BackupSummary{}, err
+	}
+
+	details := fmt.Sprintf("Restored backup created at %s: %d users, %d meetings, %d availabilities, %d audit entries",
+		backup.CreatedAt.UTC().Format(time.RFC3339), summary.Users, summary.Meetings, summary.Availabilities, summary.AuditEntries)
+	if _, err := s.auditLog.Append(models.AuditEntry{
+		Action:  models.AuditActionBackupRestored,
+		Details: details,
+	}); err != nil {
+		return models.BackupSummary{}, err
+	}
+
+	logs.Info("%s", details)
+	return summary, nil
+}
+
+// backupChecksum returns the hex SHA-256 of the JSON encoding of backup data
+func backupChecksum(data models.BackupData) (string, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return "", errors.NewInternalError("Failed to encode backup", err)
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// checkBackupReferences checks that record IDs are unique and that every record refers to
+// users and meetings present in the backup
+func checkBackupReferences(data models.BackupData) error {
+	invalid := func(format string, args ...interface{}) error {
+		return errors.NewValidationError("Backup is inconsistent", fmt.Sprintf(format, args...))
+	}
+
+	users := make(map[string]bool, len(data.Users.Users))
+	for _, user := range data.Users.Users {
+		if users[user.ID] {
+			return invalid("duplicate user %s", user.ID)
+		}
+		users[user.ID] = true
+	}
+	for _, token := range data.Users.AccessTokens {
+		if !users[token.UserID] {
+			return invalid("access token %s belongs to unknown user %s", token.ID, token.UserID)
+		}
+	}
+
+	meetings := make(map[string]bool, len(data.Meetings.Meetings))
+	for _, meeting := range data.Meetings.Meetings {
+		if meetings[meeting.ID] {
+			return invalid("duplicate meeting %s", meeting.ID)
+		}
+		meetings[meeting.ID] = true
+		if !users[meeting.OrganizerID] {
+			return invalid("meeting %s has unknown organizer %s", meeting.ID, meeting.OrganizerID)
+		}
+		for _, participant := range meeting.Participants {
+			if !users[participant.ID] {
+				return invalid("meeting %s has unknown participant %s", meeting.ID, participant.ID)
+			}
+		}
+	}
+
+	availabilities := make(map[string]bool, len(data.Meetings.Availabilities))
+	for _, availability := range data.Meetings.Availabilities {
+		if availabilities[availability.ID] {
+			return invalid("duplicate availability %s", availability.ID)
+		}
+		availabilities[availability.ID] = true
+		if !meetings[availability.MeetingID] {
+			return invalid("availability %s belongs to unknown meeting %s", availability.ID, availability.MeetingID)
+		}
+		if !users[availability.ParticipantID] {
+			return invalid("availability %s belongs to unknown user %s", availability.ID, availability.ParticipantID)
+		}
+	}
+	for _, event := range data.Meetings.Timelines {
+		if !meetings[event.MeetingID] {
+			return invalid("timeline event %s belongs to unknown meeting %s", event.ID, event.MeetingID)
+		}
+	}
+	for _, set := range data.Meetings.Recommendations {
+		if !meetings[set.MeetingID] {
+			return invalid("recommendations belong to unknown meeting %s", set.MeetingID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"meetsync/internal/models"
+	"meetsync/internal/sanitize"
+	"meetsync/pkg/errors"
+	"meetsync/pkg/logs"
+)
+
+// minBootstrapSecretLength is the shortest declared token secret accepted after its
+// prefix, so provisioning tools cannot declare guessable secrets
+const minBootstrapSecretLength = 32
+
+// LoadBootstrap reads a Bootstrap from a JSON file. Unknown fields are rejected so typos
+// do not silently declare nothing, and every token must declare its secret since a
+// generated one could not be read back.
+func LoadBootstrap(path string) (models.Bootstrap, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return models.Bootstrap{}, fmt.Errorf("failed to read bootstrap file: %w", err)
+	}
+
+	var bootstrap models.Bootstrap
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&bootstrap); err != nil {
+		return models.Bootstrap{}, fmt.Errorf("invalid bootstrap file %s: %w", path, err)
+	}
+	for _, token := range bootstrap.Tokens {
+		if token.Secret == "" {
+			return models.Bootstrap{}, fmt.Errorf("invalid bootstrap file %s: token %q must declare its secret", path, token.Name)
+		}
+	}
+	return bootstrap, nil
+}
+
+// Bootstrap ensures the administrator and tokens of bootstrap exist. The administrator is
+// looked up by email and created, with a verified email, if missing; existing users are
+// left as they are. Tokens are looked up by name among the administrator's tokens and
+// created if missing, or replaced when their scopes or declared secret changed. The whole
+// declaration is validated before anything is changed.
+func (s *UserServiceImpl) Bootstrap(bootstrap models.Bootstrap) (models.BootstrapResult, error) {
+	name, err := s.validateName(bootstrap.Admin.Name)
+	if err != nil {
+		return models.BootstrapResult{}, err
+	}
+	email := sanitize.Email(bootstrap.Admin.Email)
+	if err := validateEmail(email); err != nil {
+		return models.BootstrapResult{}, err
+	}
+	declared := make(map[string]bool, len(bootstrap.Tokens))
+	for _, token := range bootstrap.Tokens {
+		if err := validateAccessToken(token.Name, token.Scopes); err != nil {
+			return models.BootstrapResult{}, err
+		}
+		if declared[token.Name] {
+			return models.BootstrapResult{}, errors.NewValidationError("Token declared twice: "+token.Name, "")
+		}
+		declared[token.Name] = true
+		if token.Secret != "" && (!strings.HasPrefix(token.Secret, accessTokenPrefix) || len(token.Secret) < len(accessTokenPrefix)+minBootstrapSecretLength) {
+			return models.BootstrapResult{}, errors.NewValidationError("Invalid secret for token "+token.Name,
+				fmt.Sprintf("secrets start with %s followed by at least %d characters", accessTokenPrefix, minBootstrapSecretLength))
+		}
+	}
+
+	result := models.BootstrapResult{AdminStatus: models.BootstrapUnchanged, Tokens: []models.BootstrapTokenResult{}}
+	admin, found := s.repository.GetByEmail(email)
+	switch {
+	case !found:
+		if admin, err = s.repository.Create(models.User{Name: name, Email: email, EmailVerified: true}); err != nil {
+			return models.BootstrapResult{}, err
+		}
+		result.AdminStatus = models.BootstrapCreated
+		logs.Info("Bootstrapped admin user %s", admin.ID)
+	case admin.Guest:
+		return models.BootstrapResult{}, errors.NewConflictError("Admin email belongs to a guest")
+	case !admin.Active():
+		return models.BootstrapResult{}, errors.NewConflictError("Admin user is deactivated")
+	}
+	result.Admin = admin
+
+	existing, err := s.repository.ListAccessTokens(admin.ID)
+	if err != nil {
+		return models.BootstrapResult{}, err
+	}
+	for _, declaredToken := range bootstrap.Tokens {
+		tokenResult, err := s.bootstrapToken(admin.ID, declaredToken, existing)
+		if err != nil {
+			return models.BootstrapResult{}, err
+		}
+		result.Tokens = append(result.Tokens, tokenResult)
+	}
+	return result, nil
+}
+
+// bootstrapToken ensures a declared token of the administrator exists, given the tokens
+// they already have
+func (s *UserServiceImpl) bootstrapToken(adminID string, declared models.BootstrapToken, existing []models.PersonalAccessToken) (models.BootstrapTokenResult, error) {
+	status := models.BootstrapCreated
+	for _, token := range existing {
+		if token.Name != declared.Name {
+			continue
+		}
+		if sameScopes(token.Scopes, declared.Scopes) && (declared.Secret == "" || token.SecretHash == hashSecret(declared.Secret)) {
+			return models.BootstrapTokenResult{Token: token, Status: models.BootstrapUnchanged}, nil
+		}
+		if err := s.repository.DeleteAccessToken(adminID, token.ID); err != nil {
+			return models.BootstrapTokenResult{}, err
+		}
+		status = models.BootstrapReplaced
+		break
+	}
+
+	var token models.PersonalAccessToken
+	var secret string
+	var err error
+	if declared.Secret != "" {
+		token, _, err = s.createAccessToken(adminID, declared.Name, declared.Scopes, declared.Secret)
+	} else {
+		token, secret, err = s.CreateAccessToken(adminID, declared.Name, declared.Scopes)
+	}
+	if err != nil {
+		return models.BootstrapTokenResult{}, err
+	}
+	logs.Info("Bootstrapped access token %s (%s) for admin user %s", token.ID, status, adminID)
+	return models.BootstrapTokenResult{Token: token, Status: status, Secret: secret}, nil
+}
+
+// sameScopes reports whether two lists grant the same scopes, in any order
+func sameScopes(a, b []models.Scope) bool {
+	sorted := func(scopes []models.Scope) string {
+		names := make([]string, len(scopes))
+		for i, scope := range scopes {
+			names[i] = string(scope)
+		}
+		sort.Strings(names)
+		return strings.Join(names, ",")
+	}
+	return sorted(a) == sorted(b)
+}
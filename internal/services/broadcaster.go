@@ -0,0 +1,49 @@
+package services
+
+import "sync"
+
+// changeBroadcaster wakes up goroutines waiting for a keyed resource to change
+type changeBroadcaster struct {
+	mu      sync.Mutex
+	waiters map[string]chan struct{}
+}
+
+// newChangeBroadcaster creates a new changeBroadcaster
+func newChangeBroadcaster() *changeBroadcaster {
+	return &changeBroadcaster{waiters: make(map[string]chan struct{})}
+}
+
+// wait returns a channel that is closed on the next change of key
+func (b *changeBroadcaster) wait(key string) <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch, exists := b.waiters[key]
+	if !exists {
+		ch = make(chan struct{})
+		b.waiters[key] = ch
+	}
+	return ch
+}
+
+// notify wakes up everyone waiting for key
+func (b *changeBroadcaster) notify(key string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if ch, exists := b.waiters[key]; exists {
+		close(ch)
+		delete(b.waiters, key)
+	}
+}
+
+// notifyAll wakes up everyone waiting for any key
+func (b *changeBroadcaster) notifyAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for key, ch := range b.waiters {
+		close(ch)
+		delete(b.waiters, key)
+	}
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+	"meetsync/pkg/logs"
+)
+
+// ImportAvailability records a participant as available for the proposed slots of a
+// meeting that none of their busy times overlap, replacing what they submitted before.
+// When windows are split, the free parts of a window that still fit the meeting are
+// submitted as well.
+func (s *MeetingServiceImpl) ImportAvailability(userID, meetingID string, busy []models.TimeSlot, tentative bool) (models.Availability, error) {
+	meeting, err := s.repository.GetMeetingByID(meetingID)
+	if err != nil {
+		return models.Availability{}, err
+	}
+
+	duration := time.Duration(meeting.EstimatedDuration) * time.Minute
+	free := []models.TimeSlot{}
+	for _, slot := range meeting.ProposedSlots {
+		if !overlapsAny(busy, slot) {
+			free = append(free, slot)
+			continue
+		}
+		if s.slotGranularity <= 0 {
+			continue
+		}
+		for _, gap := range freeWindows(slot, busy) {
+			if gap.EndTime.Sub(gap.StartTime) >= duration {
+				free = append(free, gap)
+			}
+		}
+	}
+
+	availability, err := s.replaceAvailability(userID, meetingID, free, tentative)
+	if err != nil {
+		return models.Availability{}, err
+	}
+	logs.Info("Imported %d busy times of user %s for meeting %s: available for %d slots", len(busy), userID, meetingID, len(free))
+	return availability, nil
+}
+
+// ImportCalendarAvailability reads the busy times of a participant from a calendar
+// provider with their OAuth access token, over the span of the proposed slots of a
+// meeting, and imports them as ImportAvailability does. It also returns the number of
+// busy times read.
+func (s *MeetingServiceImpl) ImportCalendarAvailability(ctx context.Context, userID, meetingID, provider, accessToken string, tentative bool) (models.Availability, int, error) {
+	source, ok := s.calendars[provider]
+	if !ok {
+		return models.Availability{}, 0, errors.NewValidationError("Unknown calendar provider: "+provider, "supported providers: "+strings.Join(s.calendarProviderNames(), ", "))
+	}
+	if accessToken == "" {
+		return models.Availability{}, 0, errors.NewValidationError("Access token is required", "")
+	}
+	meeting, err := s.repository.GetMeetingByID(meetingID)
+	if err != nil {
+		return models.Availability{}, 0, err
+	}
+
+	var busy []models.TimeSlot
+	if len(meeting.ProposedSlots) > 0 {
+		from, to := meeting.ProposedSlots[0].StartTime, meeting.ProposedSlots[0].EndTime
+		for _, slot := range meeting.ProposedSlots[1:] {
+			if slot.StartTime.Before(from) {
+				from = slot.StartTime
+			}
+			if slot.EndTime.After(to) {
+				to = slot.EndTime
+			}
+		}
+		if busy, err = source.BusyTimes(ctx, accessToken, from, to); err != nil {
+			// Providers classify their failures, such as rejected tokens
+			if _, classified := errors.AsAppError(err); !classified {
+				err = errors.NewDependencyError("Failed to read busy times from "+provider, err)
+			}
+			return models.Availability{}, 0, err
+		}
+	}
+
+	availability, err := s.ImportAvailability(userID, meetingID, busy, tentative)
+	if err != nil {
+		return models.Availability{}, 0, err
+	}
+	return availability, len(busy), nil
+}
+
+// calendarProviderNames returns the names of the calendar providers, sorted
+func (s *MeetingServiceImpl) calendarProviderNames() []string {
+	names := make([]string, 0, len(s.calendars))
+	for name := range s.calendars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// replaceAvailability submits the availability of a participant for a meeting, or
+// updates the one they submitted before
+func (s *MeetingServiceImpl) replaceAvailability(userID, meetingID string, availableSlots []models.TimeSlot, tentative bool) (models.Availability, error) {
+	existing, err := s.repository.GetAvailability(userID, meetingID)
+	if errors.Is(err, errors.ErrNotFound) {
+		return s.AddAvailability(userID, meetingID, availableSlots, tentative)
+	}
+	if err != nil {
+		return models.Availability{}, err
+	}
+	return s.UpdateAvailability(existing.ID, availableSlots, tentative)
+}
+
+// freeWindows returns the parts of window that none of the busy times overlap
+func freeWindows(window models.TimeSlot, busy []models.TimeSlot) []models.TimeSlot {
+	free := []models.TimeSlot{{StartTime: window.StartTime, EndTime: window.EndTime}}
+	for _, taken := range busy {
+		var remaining []models.TimeSlot
+		for _, part := range free {
+			if !taken.StartTime.Before(part.EndTime) || !part.StartTime.Before(taken.EndTime) {
+				remaining = append(remaining, part)
+				continue
+			}
+			if part.StartTime.Before(taken.StartTime) {
+				remaining = append(remaining, models.TimeSlot{StartTime: part.StartTime, EndTime: taken.StartTime})
+			}
+			if taken.EndTime.Before(part.EndTime) {
+				remaining = append(remaining, models.TimeSlot{StartTime: taken.EndTime, EndTime: part.EndTime})
+			}
+		}
+		free = remaining
+	}
+	return free
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"meetsync/internal/integrations"
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+)
+
+// calendarFunc is a calendar provider answering with a function
+type calendarFunc func(accessToken string, from, to time.Time) ([]models.TimeSlot, error)
+
+func (f calendarFunc) BusyTimes(ctx context.Context, accessToken string, from, to time.Time) ([]models.TimeSlot, error) {
+	return f(accessToken, from, to)
+}
+
+func TestImportAvailability(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+
+	monday := time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC)
+	morning := models.TimeSlot{StartTime: monday.Add(9 * time.Hour), EndTime: monday.Add(12 * time.Hour)}
+	afternoon := models.TimeSlot{StartTime: monday.Add(14 * time.Hour), EndTime: monday.Add(15 * time.Hour)}
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Planning",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     []models.TimeSlot{morning, afternoon},
+		ParticipantIDs:    []string{participants[0].ID},
+	})
+	require.NoError(t, err)
+
+	// Busy from 10:00 to 10:30: only the afternoon is entirely free
+	busy := []models.TimeSlot{{StartTime: monday.Add(10 * time.Hour), EndTime: monday.Add(10*time.Hour + 30*time.Minute)}}
+	availability, err := service.ImportAvailability(participants[0].ID, meeting.ID, busy, false)
+	require.NoError(t, err)
+	require.Len(t, availability.AvailableSlots, 1)
+	assert.True(t, afternoon.StartTime.Equal(availability.AvailableSlots[0].StartTime))
+
+	// When windows are split, the free parts of the morning that fit the meeting count
+	// too, and importing again replaces the availability
+	service.slotGranularity = 15 * time.Minute
+	availability, err = service.ImportAvailability(participants[0].ID, meeting.ID, busy, true)
+	require.NoError(t, err)
+	require.Len(t, availability.AvailableSlots, 3)
+	assert.True(t, availability.Tentative)
+	assert.True(t, busy[0].StartTime.Equal(availability.AvailableSlots[0].EndTime))
+	assert.True(t, busy[0].EndTime.Equal(availability.AvailableSlots[1].StartTime))
+	assert.True(t, morning.EndTime.Equal(availability.AvailableSlots[1].EndTime))
+	availabilities, err := service.repository.GetMeetingAvailabilities(meeting.ID)
+	require.NoError(t, err)
+	assert.Len(t, availabilities, 1)
+}
+
+func TestImportCalendarAvailability(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+
+	monday := time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC)
+	morning := models.TimeSlot{StartTime: monday.Add(9 * time.Hour), EndTime: monday.Add(10 * time.Hour)}
+	afternoon := models.TimeSlot{StartTime: monday.Add(14 * time.Hour), EndTime: monday.Add(15 * time.Hour)}
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Planning",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     []models.TimeSlot{afternoon, morning},
+		ParticipantIDs:    []string{participants[0].ID},
+	})
+	require.NoError(t, err)
+
+	var queried models.TimeSlot
+	service.calendars = map[string]integrations.Provider{
+		integrations.ProviderGoogle: calendarFunc(func(accessToken string, from, to time.Time) ([]models.TimeSlot, error) {
+			if accessToken != "valid" {
+				return nil, errors.NewValidationError("Google rejected the access token", "")
+			}
+			queried = models.TimeSlot{StartTime: from, EndTime: to}
+			return []models.TimeSlot{{StartTime: monday.Add(9*time.Hour + 30*time.Minute), EndTime: monday.Add(11 * time.Hour)}}, nil
+		}),
+	}
+
+	// The calendar is read over the span of the proposed slots
+	availability, busyTimes, err := service.ImportCalendarAvailability(context.Background(), participants[0].ID, meeting.ID, integrations.ProviderGoogle, "valid", false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, busyTimes)
+	assert.True(t, morning.StartTime.Equal(queried.StartTime))
+	assert.True(t, afternoon.EndTime.Equal(queried.EndTime))
+	require.Len(t, availability.AvailableSlots, 1)
+	assert.True(t, afternoon.StartTime.Equal(availability.AvailableSlots[0].StartTime))
+
+	_, _, err = service.ImportCalendarAvailability(context.Background(), participants[0].ID, meeting.ID, integrations.ProviderGoogle, "expired", false)
+	assert.True(t, errors.Is(err, errors.ErrValidation), "rejected tokens stay validation errors")
+	_, _, err = service.ImportCalendarAvailability(context.Background(), participants[0].ID, meeting.ID, "outlook", "valid", false)
+	assert.True(t, errors.Is(err, errors.ErrValidation))
+	_, _, err = service.ImportCalendarAvailability(context.Background(), participants[0].ID, meeting.ID, integrations.ProviderGoogle, "", false)
+	assert.True(t, errors.Is(err, errors.ErrValidation))
+	_, _, err = service.ImportCalendarAvailability(context.Background(), participants[0].ID, "missing", integrations.ProviderGoogle, "valid", false)
+	assert.True(t, errors.Is(err, errors.ErrNotFound))
+}
+
+func TestFreeWindows(t *testing.T) {
+	start := time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+	window := models.TimeSlot{StartTime: at(0), EndTime: at(180)}
+
+	free := freeWindows(window, []models.TimeSlot{
+		{StartTime: at(-30), EndTime: at(15)},
+		{StartTime: at(60), EndTime: at(90)},
+		{StartTime: at(170), EndTime: at(240)},
+	})
+	require.Len(t, free, 2)
+	assert.Equal(t, models.TimeSlot{StartTime: at(15), EndTime: at(60)}, free[0])
+	assert.Equal(t, models.TimeSlot{StartTime: at(90), EndTime: at(170)}, free[1])
+
+	assert.Empty(t, freeWindows(window, []models.TimeSlot{{StartTime: at(-60), EndTime: at(240)}}))
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"meetsync/internal/ical"
+	"meetsync/internal/models"
+	"meetsync/internal/notifications"
+	"meetsync/pkg/errors"
+)
+
+// GetCalendarEvent returns a meeting as an event to export to calendars. Confirmed and
+// cancelled meetings are exported on their confirmed slot; other meetings on their best
+// recommended slot, shortened to the estimated duration, as a tentative event.
+func (s *MeetingServiceImpl) GetCalendarEvent(meetingID string) (models.CalendarEvent, error) {
+	meeting, err := s.repository.GetMeetingByID(meetingID)
+	if err != nil {
+		return models.CalendarEvent{}, err
+	}
+
+	event := models.CalendarEvent{
+		MeetingID: meeting.ID,
+		Title:     meeting.Title,
+		Status:    meeting.Status,
+		UpdatedAt: meeting.UpdatedAt,
+	}
+	if meeting.ConfirmedSlot != nil {
+		event.Slot = *meeting.ConfirmedSlot
+	} else {
+		set, err := s.GetRecommendations(context.Background(), meetingID, "")
+		if err != nil {
+			return models.CalendarEvent{}, err
+		}
+		if len(set.Slots) == 0 {
+			return models.CalendarEvent{}, errors.NewNotFoundError("Meeting has no slot to export")
+		}
+		event.Slot = set.Slots[0].TimeSlot
+		if end := event.Slot.StartTime.Add(time.Duration(meeting.EstimatedDuration) * time.Minute); end.Before(event.Slot.EndTime) {
+			event.Slot.EndTime = end
+		}
+		if meeting.Status != models.MeetingStatusCancelled {
+			event.Description = "Best recommended time so far; the meeting is not confirmed yet."
+		}
+	}
+
+	if err := s.addEventParties(&event, meeting); err != nil {
+		return models.CalendarEvent{}, err
+	}
+	return event, nil
+}
+
+// invitation returns the calendar invitation to a confirmed meeting, attached to the
+// emails telling participants when it takes place
+func (s *MeetingServiceImpl) invitation(meeting models.Meeting) (notifications.Attachment, error) {
+	event := models.CalendarEvent{
+		MeetingID: meeting.ID,
+		Title:     meeting.Title,
+		Slot:      *meeting.ConfirmedSlot,
+		Status:    meeting.Status,
+		UpdatedAt: meeting.UpdatedAt,
+	}
+	if err := s.addEventParties(&event, meeting); err != nil {
+		return notifications.Attachment{}, err
+	}
+	return notifications.Attachment{
+		Filename:    "invite.ics",
+		ContentType: ical.InvitationContentType,
+		Content:     ical.RenderInvitation(event, time.Now()),
+	}, nil
+}
+
+// addEventParties sets the organizer and the attendees of the event of meeting
+func (s *MeetingServiceImpl) addEventParties(event *models.CalendarEvent, meeting models.Meeting) error {
+	if meeting.Organizer != nil {
+		event.Organizer = *meeting.Organizer
+	} else {
+		organizer, err := s.userService.GetUserByID(meeting.OrganizerID)
+		if err != nil {
+			return err
+		}
+		event.Organizer = organizer
+	}
+	for _, participant := range meeting.Participants {
+		if participant.ID != meeting.OrganizerID {
+			event.Attendees = append(event.Attendees, participant)
+		}
+	}
+	return nil
+}
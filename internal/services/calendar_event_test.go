@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"meetsync/internal/models"
+)
+
+func TestMeetingService_GetCalendarEvent(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	window := models.TimeSlot{StartTime: start, EndTime: start.Add(3 * time.Hour)}
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Planning",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     []models.TimeSlot{window},
+		ParticipantIDs:    []string{participants[0].ID, participants[1].ID},
+	})
+	require.NoError(t, err)
+
+	// Pending meetings are exported on their best slot, as long as the meeting lasts
+	event, err := service.GetCalendarEvent(meeting.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Planning", event.Title)
+	assert.Equal(t, models.MeetingStatusPending, event.Status)
+	assert.Equal(t, organizer.Email, event.Organizer.Email)
+	assert.Len(t, event.Attendees, 2)
+	assert.True(t, start.Equal(event.Slot.StartTime))
+	assert.True(t, start.Add(time.Hour).Equal(event.Slot.EndTime))
+	assert.NotEmpty(t, event.Description)
+
+	// Confirmed meetings are exported on their confirmed slot
+	set, err := service.GetRecommendations(context.Background(), meeting.ID, "")
+	require.NoError(t, err)
+	_, err = service.ConfirmMeeting(meeting.ID, set.Slots[0].TimeSlot)
+	require.NoError(t, err)
+	event, err = service.GetCalendarEvent(meeting.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.MeetingStatusConfirmed, event.Status)
+	assert.True(t, set.Slots[0].TimeSlot.EndTime.Equal(event.Slot.EndTime))
+	assert.Empty(t, event.Description)
+
+	_, err = service.GetCalendarEvent("missing")
+	assert.Error(t, err)
+}
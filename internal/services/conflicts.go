@@ -0,0 +1,69 @@
+package services
+
+import (
+	"meetsync/internal/models"
+)
+
+// competingSlot is time a participant also offered to another open meeting
+type competingSlot struct {
+	slot      models.TimeSlot
+	meetingID string
+	priority  models.MeetingPriority
+}
+
+// competingSlots collects, per user, the availability they submitted to other pending meetings
+// of at least the priority of meeting. Lower-priority meetings yield their slots, so they
+// are left out.
+func (s *MeetingServiceImpl) competingSlots(meeting models.Meeting, users []models.User) (map[string][]competingSlot, error) {
+	others := make(map[string]models.Meeting)
+	competing := make(map[string][]competingSlot, len(users))
+	for _, user := range users {
+		availabilities, err := s.repository.GetParticipantAvailabilities(user.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, availability := range availabilities {
+			if availability.MeetingID == meeting.ID {
+				continue
+			}
+			other, seen := others[availability.MeetingID]
+			if !seen {
+				if other, err = s.repository.GetMeetingByID(availability.MeetingID); err != nil {
+					continue
+				}
+				others[availability.MeetingID] = other
+			}
+			if other.Status != models.MeetingStatusPending || other.Priority.Rank() < meeting.Priority.Rank() {
+				continue
+			}
+
+			for _, slot := range availability.AvailableSlots {
+				competing[user.ID] = append(competing[user.ID], competingSlot{
+					slot:      slot,
+					meetingID: other.ID,
+					priority:  other.Priority,
+				})
+			}
+		}
+	}
+	return competing, nil
+}
+
+// conflictsFor returns the meetings competing with candidate for a user, once per meeting
+func conflictsFor(userID string, competing []competingSlot, candidate models.TimeSlot) []models.MeetingConflict {
+	var conflicts []models.MeetingConflict
+	seen := make(map[string]bool)
+	for _, c := range competing {
+		if seen[c.meetingID] || !overlapsAny([]models.TimeSlot{c.slot}, candidate) {
+			continue
+		}
+		seen[c.meetingID] = true
+		conflicts = append(conflicts, models.MeetingConflict{
+			MeetingID: c.meetingID,
+			Priority:  c.priority,
+			UserID:    userID,
+		})
+	}
+	return conflicts
+}
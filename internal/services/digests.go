@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"meetsync/internal/models"
+	"meetsync/internal/notifications"
+	"meetsync/pkg/logs"
+)
+
+// digestTolerance lets a digest go out this much before it is due, so that a job running
+// every hour does not send each daily digest an hour later than the previous one
+const digestTolerance = time.Hour
+
+// digest is what a digest email reports to a user
+type digest struct {
+	pending  []models.Meeting  // meetings waiting for the user's availability
+	upcoming []upcomingMeeting // meetings whose best time falls before the next digest
+}
+
+// upcomingMeeting is a meeting with the best time recommended for it
+type upcomingMeeting struct {
+	meeting models.Meeting
+	slot    models.TimeSlot
+}
+
+// SendDigests emails every user whose digest is due at now a single email listing the
+// meetings waiting for their availability and the meetings whose best time falls before
+// their next digest. Users with nothing to report are not emailed. Failed deliveries are
+// logged and retried at the next run.
+func (s *MeetingServiceImpl) SendDigests(now time.Time) (models.DigestResult, error) {
+	users, err := s.userService.ListUsers()
+	if err != nil {
+		return models.DigestResult{}, err
+	}
+	var due []models.User
+	for _, user := range users {
+		if digestDue(user, now) {
+			due = append(due, user)
+		}
+	}
+
+	var result models.DigestResult
+	if len(due) == 0 {
+		return result, nil
+	}
+
+	meetings, err := s.repository.ListMeetings(nil, math.MaxInt, models.MeetingFilter{})
+	if err != nil {
+		return models.DigestResult{}, err
+	}
+	responses := make(map[string]models.Availability)
+	for _, availability := range s.repository.GetAllAvailabilities() {
+		responses[availability.MeetingID+"/"+availability.ParticipantID] = availability
+	}
+	bestSlots := make(map[string]*models.TimeSlot)
+
+	for _, user := range due {
+		d := s.collectDigest(user, meetings, responses, bestSlots, now)
+		if len(d.pending) == 0 && len(d.upcoming) == 0 {
+			result.Empty++
+		} else {
+			message, err := s.templates.Render(notifications.KindDigest, s.digestData(user, d))
+			if err != nil {
+				logs.Warn("Failed to render digest of user %s: %v", user.ID, err)
+				result.Failed++
+				continue
+			}
+			notification := notifications.Notification{
+				Kind:    notifications.KindDigest,
+				UserID:  user.ID,
+				To:      user.Email,
+				Subject: message.Subject,
+				Body:    message.Text,
+				HTML:    message.HTML,
+			}
+			if err := s.notifier.Send(notification); err != nil {
+				logs.Warn("Failed to send digest to user %s: %v", user.ID, err)
+				result.Failed++
+				continue
+			}
+			result.Sent++
+		}
+
+		if err := s.userService.RecordDigest(user.ID, now); err != nil {
+			logs.Warn("Failed to record digest of user %s: %v", user.ID, err)
+		}
+	}
+	return result, nil
+}
+
+// digestDue reports whether an active user on a digest schedule is due a digest at now
+func digestDue(user models.User, now time.Time) bool {
+	period := user.Digest.Period()
+	if period == 0 || !user.Active() {
+		return false
+	}
+	return user.DigestSentAt == nil || !now.Before(user.DigestSentAt.Add(period-digestTolerance))
+}
+
+// collectDigest gathers the open meetings user was asked to respond to and has not, or
+// whose response went stale, and the meetings they take part in whose best time starts
+// before their next digest. Best times are computed once per meeting into bestSlots.
+func (s *MeetingServiceImpl) collectDigest(user models.User, meetings []models.Meeting, responses map[string]models.Availability, bestSlots map[string]*models.TimeSlot, now time.Time) digest {
+	var d digest
+	horizon := now.Add(user.Digest.Period())
+	for _, meeting := range meetings {
+		if meeting.Status != models.MeetingStatusPending {
+			continue
+		}
+		participant := false
+		for _, p := range meeting.Participants {
+			participant = participant || p.ID == user.ID
+		}
+		if !participant && meeting.OrganizerID != user.ID {
+			continue
+		}
+
+		if participant && openSlots(meeting, now) {
+			response, responded := responses[meeting.ID+"/"+user.ID]
+			if !responded || response.IsStale(meeting.AvailabilityTTL, now) {
+				d.pending = append(d.pending, meeting)
+			}
+		}
+
+		best, computed := bestSlots[meeting.ID]
+		if !computed {
+			best = s.bestSlot(meeting.ID)
+			bestSlots[meeting.ID] = best
+		}
+		if best != nil && !best.StartTime.Before(now) && best.StartTime.Before(horizon) {
+			d.upcoming = append(d.upcoming, upcomingMeeting{meeting: meeting, slot: *best})
+		}
+	}
+	sort.SliceStable(d.upcoming, func(i, j int) bool {
+		return d.upcoming[i].slot.StartTime.Before(d.upcoming[j].slot.StartTime)
+	})
+	return d
+}
+
+// openSlots reports whether a meeting still has a proposed slot that has not ended
+func openSlots(meeting models.Meeting, now time.Time) bool {
+	for _, slot := range meeting.ProposedSlots {
+		if slot.EndTime.After(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// bestSlot returns the top recommended slot of a meeting, or nil when nobody is available
+// for any slot yet
+func (s *MeetingServiceImpl) bestSlot(meetingID string) *models.TimeSlot {
+	set, err := s.currentRecommendations(context.Background(), meetingID)
+	if err != nil {
+		logs.Warn("Failed to compute recommendations of meeting %s for digests: %v", meetingID, err)
+		return nil
+	}
+	if len(set.Slots) == 0 || set.Slots[0].AvailableCount == 0 {
+		return nil
+	}
+	return &set.Slots[0].TimeSlot
+}
+
+// digestData returns what the digest templates render for a digest of user
+func (s *MeetingServiceImpl) digestData(user models.User, d digest) notifications.DigestData {
+	data := notifications.DigestData{Recipient: user.Name, Period: string(user.Digest)}
+	for _, meeting := range d.pending {
+		pending := notifications.DigestMeeting{
+			Title:     meeting.Title,
+			Duration:  meeting.EstimatedDuration,
+			MeetingID: meeting.ID,
+		}
+		for _, slot := range meeting.ProposedSlots {
+			pending.Slots = append(pending.Slots, notifications.InvitationSlot{
+				Start:   slot.StartTime,
+				End:     slot.EndTime,
+				RSVPURL: s.rsvpURL(meeting.ID, user.ID, slot.ID),
+			})
+		}
+		data.Pending = append(data.Pending, pending)
+	}
+	for _, upcoming := range d.upcoming {
+		data.Upcoming = append(data.Upcoming, notifications.DigestUpcoming{
+			Title:     upcoming.meeting.Title,
+			MeetingID: upcoming.meeting.ID,
+			Start:     upcoming.slot.StartTime,
+			End:       upcoming.slot.EndTime,
+		})
+	}
+	return data
+}
+
+// SendDigests sends the digests due now, for the scheduled digest job and admins
+// triggering a run by hand
+func (s *AdminServiceImpl) SendDigests() (models.DigestResult, error) {
+	result, err := s.meetingService.SendDigests(time.Now())
+	if err != nil {
+		return models.DigestResult{}, err
+	}
+	if result.Sent > 0 || result.Failed > 0 {
+		logs.Info("Sent %d digests, %d failed, %d with nothing to report", result.Sent, result.Failed, result.Empty)
+	}
+	return result, nil
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"meetsync/internal/models"
+	"meetsync/internal/notifications"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeetingService_SendDigests(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+	notifier := &recordingNotifier{}
+	service.notifier = notifier
+	timeSlots := createTestTimeSlots()
+
+	_, err := service.userService.SetDigest(participants[0].ID, models.DigestDaily)
+	assert.NoError(t, err)
+	_, err = service.userService.SetDigest(organizer.ID, models.DigestWeekly)
+	assert.NoError(t, err)
+
+	// Invitations to users on a digest schedule wait for their digest
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Digest Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		ParticipantIDs:    []string{participants[0].ID, participants[1].ID},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, notifier.notifications, 1)
+	assert.Equal(t, participants[1].ID, notifier.notifications[0].UserID)
+
+	_, err = service.AddAvailability(participants[1].ID, meeting.ID, meeting.ProposedSlots[:1], false)
+	assert.NoError(t, err)
+
+	notifier.notifications = nil
+	now := time.Now()
+	result, err := service.SendDigests(now)
+	assert.NoError(t, err)
+	assert.Equal(t, models.DigestResult{Sent: 2}, result)
+	assert.Len(t, notifier.notifications, 2)
+	bodies := make(map[string]string)
+	for _, notification := range notifier.notifications {
+		assert.Equal(t, notifications.KindDigest, notification.Kind)
+		bodies[notification.UserID] = notification.Body
+	}
+	assert.Contains(t, bodies[participants[0].ID], "Waiting for your availability (1)")
+	assert.Contains(t, bodies[participants[0].ID], meeting.ID)
+	assert.Contains(t, bodies[organizer.ID], "Coming up before your next digest (1)")
+	assert.False(t, strings.Contains(bodies[organizer.ID], "Waiting for your availability"))
+
+	// Nobody is due again within the period
+	notifier.notifications = nil
+	result, err = service.SendDigests(now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, models.DigestResult{}, result)
+	assert.Empty(t, notifier.notifications)
+
+	// Once the meeting is past there is nothing to report
+	_, err = service.AddAvailability(participants[0].ID, meeting.ID, meeting.ProposedSlots, false)
+	assert.NoError(t, err)
+	result, err = service.SendDigests(now.Add(72 * time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, models.DigestResult{Empty: 1}, result)
+	assert.Empty(t, notifier.notifications)
+}
+
+func TestUserService_SetDigest(t *testing.T) {
+	service := NewUserService()
+	user, err := service.CreateUser("Digest User", "digest@example.com")
+	assert.NoError(t, err)
+
+	updated, err := service.SetDigest(user.ID, models.DigestWeekly)
+	assert.NoError(t, err)
+	assert.Equal(t, models.DigestWeekly, updated.Digest)
+
+	sentAt := time.Now()
+	assert.NoError(t, service.RecordDigest(user.ID, sentAt))
+	updated, err = service.GetUserByID(user.ID)
+	assert.NoError(t, err)
+	assert.True(t, updated.DigestSentAt.Equal(sentAt))
+
+	// Changing the frequency restarts the schedule
+	updated, err = service.SetDigest(user.ID, models.DigestDaily)
+	assert.NoError(t, err)
+	assert.Nil(t, updated.DigestSentAt)
+
+	updated, err = service.SetDigest(user.ID, models.DigestOff)
+	assert.NoError(t, err)
+	assert.Equal(t, models.DigestOff, updated.Digest)
+
+	_, err = service.SetDigest(user.ID, "hourly")
+	assert.Error(t, err)
+
+	guest, err := service.EnsureGuest("guest@example.org", "Guest")
+	assert.NoError(t, err)
+	_, err = service.SetDigest(guest.ID, models.DigestDaily)
+	assert.Error(t, err)
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"meetsync/internal/directory"
+	"meetsync/internal/models"
+	"meetsync/internal/sanitize"
+	"meetsync/pkg/errors"
+	"meetsync/pkg/logs"
+)
+
+// SyncDirectory reconciles users with the directory. Users are linked to directory
+// entries through their external ID: linked users are updated to match their entry,
+// new entries become users, and entries whose email belongs to an unlinked user are
+// handled by the conflict policy. Dry runs report the changes without applying them.
+func (s *AdminServiceImpl) SyncDirectory(dryRun bool) (models.DirectorySyncResult, error) {
+	if s.directory == nil {
+		return models.DirectorySyncResult{}, errors.NewValidationError("Directory sync is not configured", "set LDAP_URL")
+	}
+
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	entries, err := s.directory.Users()
+	if err != nil {
+		return models.DirectorySyncResult{}, errors.NewDependencyError("Failed to read directory", err)
+	}
+	users, err := s.userService.ListUsers()
+	if err != nil {
+		return models.DirectorySyncResult{}, err
+	}
+
+	result := models.DirectorySyncResult{DryRun: dryRun}
+	changes, unchanged := planDirectorySync(entries, users, s.syncOptions)
+	result.Unchanged = unchanged
+	for _, change := range changes {
+		if !dryRun && change.Action != models.DirectorySyncConflict {
+			if err := s.applyDirectoryChange(&change); err != nil {
+				change.Error = err.Error()
+				if appErr, ok := errors.AsAppError(err); ok {
+					change.Error = appErr.Message
+				}
+				result.Failed++
+				result.Changes = append(result.Changes, change)
+				continue
+			}
+		}
+
+		switch change.Action {
+		case models.DirectorySyncCreate:
+			result.Created++
+		case models.DirectorySyncUpdate:
+			result.Updated++
+		case models.DirectorySyncLink:
+			result.Linked++
+		case models.DirectorySyncDeactivate:
+			result.Deactivated++
+		case models.DirectorySyncConflict:
+			result.Conflicts++
+		}
+		result.Changes = append(result.Changes, change)
+	}
+	if result.Changes == nil {
+		result.Changes = []models.DirectorySyncChange{}
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	details := fmt.Sprintf("Synchronized %d directory users: %d created, %d updated, %d linked, %d deactivated, %d conflicts, %d failed",
+		len(entries), result.Created, result.Updated, result.Linked, result.Deactivated, result.Conflicts, result.Failed)
+	if _, err := s.auditLog.Append(models.AuditEntry{
+		Action:  models.AuditActionDirectorySynced,
+		Details: details,
+	}); err != nil {
+		return models.DirectorySyncResult{}, err
+	}
+
+	logs.Info("%s", details)
+	return result, nil
+}
+
+// applyDirectoryChange applies a planned change, recording the ID of created users
+func (s *AdminServiceImpl) applyDirectoryChange(change *models.DirectorySyncChange) error {
+	input := models.UserProvisioning{
+		ExternalID: change.ExternalID,
+		Name:       change.Name,
+		Email:      change.Email,
+		Active:     change.Action != models.DirectorySyncDeactivate,
+	}
+	if change.Action == models.DirectorySyncCreate {
+		user, err := s.userService.ProvisionUser(input)
+		if err != nil {
+			return err
+		}
+		change.UserID = user.ID
+		return nil
+	}
+	_, err := s.userService.UpdateProvisionedUser(change.UserID, input)
+	return err
+}
+
+// planDirectorySync returns the changes reconciling users with directory entries, in
+// the order of the entries, and the number of linked users already up to date
+func planDirectorySync(entries []directory.Entry, users []models.User, options models.DirectorySyncOptions) ([]models.DirectorySyncChange, int) {
+	byExternalID := make(map[string]models.User, len(users))
+	byEmail := make(map[string]models.User, len(users))
+	for _, user := range users {
+		if user.ExternalID != "" {
+			byExternalID[user.ExternalID] = user
+		}
+		byEmail[sanitize.Email(user.Email)] = user
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].DN < entries[j].DN })
+
+	var changes []models.DirectorySyncChange
+	unchanged := 0
+	seen := make(map[string]bool, len(entries))
+	claimed := make(map[string]bool, len(entries)) // emails taken by earlier entries
+	for _, entry := range entries {
+		change := models.DirectorySyncChange{ExternalID: entry.ID, DN: entry.DN, Email: entry.Email, Name: entry.Name}
+		conflict := func(reason string) {
+			change.Action, change.Reason = models.DirectorySyncConflict, reason
+			changes = append(changes, change)
+		}
+
+		email := sanitize.Email(entry.Email)
+		switch {
+		case entry.ID == "" || entry.Email == "":
+			conflict("Directory entry has no ID or email")
+			continue
+		case seen[entry.ID]:
+			conflict("Directory entry ID is not unique")
+			continue
+		case claimed[email]:
+			conflict("Email is used by another directory entry")
+			continue
+		}
+		seen[entry.ID] = true
+		claimed[email] = true
+
+		if user, linked := byExternalID[entry.ID]; linked {
+			change.UserID = user.ID
+			if user.Name == entry.Name && sanitize.SameEmail(user.Email, entry.Email) && user.Active() {
+				unchanged++
+				continue
+			}
+			if other, taken := byEmail[email]; taken && other.ID != user.ID {
+				conflict("Email belongs to another user")
+				continue
+			}
+			change.Action = models.DirectorySyncUpdate
+			changes = append(changes, change)
+			continue
+		}
+
+		user, exists := byEmail[email]
+		switch {
+		case !exists:
+			change.Action = models.DirectorySyncCreate
+		case user.ExternalID != "":
+			change.UserID = user.ID
+			conflict("Email belongs to a user linked to another directory entry")
+			continue
+		case options.OnConflict == models.DirectoryConflictSkip:
+			change.UserID = user.ID
+			conflict("Email belongs to an existing user")
+			continue
+		default:
+			change.UserID = user.ID
+			change.Action = models.DirectorySyncLink
+		}
+		changes = append(changes, change)
+	}
+
+	if options.OnMissing == models.DirectoryMissingDeactivate {
+		missing := make([]models.User, 0)
+		for externalID, user := range byExternalID {
+			if !seen[externalID] && user.Active() {
+				missing = append(missing, user)
+			}
+		}
+		sort.Slice(missing, func(i, j int) bool { return missing[i].Email < missing[j].Email })
+		for _, user := range missing {
+			changes = append(changes, models.DirectorySyncChange{
+				Action:     models.DirectorySyncDeactivate,
+				UserID:     user.ID,
+				ExternalID: user.ExternalID,
+				Email:      user.Email,
+				Name:       user.Name,
+				Reason:     "User is no longer in the directory",
+			})
+		}
+	}
+	return changes, unchanged
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"meetsync/internal/models"
+	"meetsync/pkg/logs"
+)
+
+// similarTitleThreshold is the share of words two titles must have in common to be
+// similar, so "Team sync" and "Team sync 2" are while "Q3 planning" and "Q4 planning" are not
+const similarTitleThreshold = 2.0 / 3
+
+// duplicateWarnings reports other meetings of the organizer that meeting seems to
+// duplicate: their titles are similar, they share a participant (or both have none) and
+// their proposed slots lie within the duplicate window of each other. Like scheduling
+// hints, the check is best effort and never fails the request.
+func (s *MeetingServiceImpl) duplicateWarnings(meeting models.Meeting) []models.Warning {
+	if s.duplicateWindow <= 0 || len(meeting.ProposedSlots) == 0 || len(titleWords(meeting.Title)) == 0 {
+		return nil
+	}
+
+	others, err := s.repository.ListMeetings(nil, math.MaxInt, models.MeetingFilter{OrganizerID: meeting.OrganizerID})
+	if err != nil {
+		logs.Warn("Failed to check duplicates of meeting %s: %v", meeting.ID, err)
+		return nil
+	}
+
+	var warnings []models.Warning
+	for _, other := range others {
+		if other.ID == meeting.ID || !similarTitles(meeting.Title, other.Title) ||
+			!shareParticipants(meeting.Participants, other.Participants) ||
+			!slotsWithin(meeting.ProposedSlots, other.ProposedSlots, s.duplicateWindow) {
+			continue
+		}
+		warnings = append(warnings, models.Warning{
+			Code:    models.WarningPossibleDuplicate,
+			Message: fmt.Sprintf("Meeting %s (%q) has a similar title, the same participants and slots within %s of this one", other.ID, other.Title, s.duplicateWindow),
+		})
+	}
+	return warnings
+}
+
+// titleWords returns the distinct lowercase words of a title, ignoring punctuation
+func titleWords(title string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		words[word] = true
+	}
+	return words
+}
+
+// similarTitles reports whether two titles share enough of their words
+func similarTitles(a, b string) bool {
+	wordsA, wordsB := titleWords(a), titleWords(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return false
+	}
+	common := 0
+	for word := range wordsA {
+		if wordsB[word] {
+			common++
+		}
+	}
+	union := len(wordsA) + len(wordsB) - common
+	return float64(common)/float64(union) >= similarTitleThreshold
+}
+
+// shareParticipants reports whether two meetings have a participant in common, or
+// neither has any
+func shareParticipants(a, b []models.User) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	for _, participant := range a {
+		for _, other := range b {
+			if participant.ID == other.ID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// slotsWithin reports whether the times spanned by two sets of slots come within window
+// of each other
+func slotsWithin(a, b []models.TimeSlot, window time.Duration) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return false
+	}
+	startA, endA := slotSpan(a)
+	startB, endB := slotSpan(b)
+	return startA.Before(endB.Add(window)) && startB.Before(endA.Add(window))
+}
+
+// slotSpan returns the start of the earliest slot and the end of the latest one
+func slotSpan(slots []models.TimeSlot) (time.Time, time.Time) {
+	start, end := slots[0].StartTime, slots[0].EndTime
+	for _, slot := range slots[1:] {
+		if slot.StartTime.Before(start) {
+			start = slot.StartTime
+		}
+		if slot.EndTime.After(end) {
+			end = slot.EndTime
+		}
+	}
+	return start, end
+}
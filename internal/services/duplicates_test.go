@@ -0,0 +1,68 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"meetsync/internal/models"
+)
+
+func TestSimilarTitles(t *testing.T) {
+	assert.True(t, similarTitles("Weekly sync", "weekly  SYNC!"))
+	assert.True(t, similarTitles("Team sync", "Team sync 2"))
+	assert.False(t, similarTitles("Q3 planning", "Q4 planning"))
+	assert.False(t, similarTitles("", ""))
+}
+
+func TestDuplicateWarnings(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+	service.duplicateWindow = 7 * 24 * time.Hour
+
+	monday := time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)
+	slot := func(day int) models.TimeSlot {
+		start := monday.Add(time.Duration(day) * 24 * time.Hour)
+		return models.TimeSlot{StartTime: start, EndTime: start.Add(time.Hour)}
+	}
+	create := func(title string, day int, participantIDs ...string) []models.Warning {
+		_, warnings, err := service.CreateMeeting(models.MeetingInput{
+			Title:             title,
+			OrganizerID:       organizer.ID,
+			EstimatedDuration: 60,
+			ProposedSlots:     []models.TimeSlot{slot(day)},
+			ParticipantIDs:    participantIDs,
+		})
+		require.NoError(t, err)
+		return warnings
+	}
+
+	assert.Empty(t, create("Roadmap review", 0, participants[0].ID))
+
+	// A similar meeting with a shared participant later the same week is created with a warning
+	warnings := create("roadmap review!", 3, participants[0].ID, participants[1].ID)
+	require.Equal(t, []models.WarningCode{models.WarningPossibleDuplicate}, warningCodes(warnings))
+	assert.Contains(t, warnings[0].Message, `"Roadmap review"`)
+
+	// Other participants, another title or slots more than a week away are not duplicates
+	assert.Empty(t, create("Budget", 1, participants[0].ID))
+	assert.Empty(t, create("Roadmap review", 30, participants[0].ID))
+
+	// Meetings of other organizers are not compared
+	other, err := service.userService.CreateUser("Other Organizer", "other@example.com")
+	require.NoError(t, err)
+	_, warnings, err = service.CreateMeeting(models.MeetingInput{
+		Title:             "Roadmap review",
+		OrganizerID:       other.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     []models.TimeSlot{slot(0)},
+		ParticipantIDs:    []string{participants[0].ID},
+	})
+	require.NoError(t, err)
+	assert.NotContains(t, warningCodes(warnings), models.WarningPossibleDuplicate)
+
+	// The check is disabled by default
+	service.duplicateWindow = 0
+	assert.Empty(t, create("Roadmap review", 0, participants[0].ID))
+}
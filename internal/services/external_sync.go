@@ -0,0 +1,59 @@
+package services
+
+import (
+	"strings"
+
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+)
+
+// UpsertExternalMeeting creates or updates the meeting synced from an external calendar
+// under externalID, reporting whether it was created. input.ExternalUpdatedAt orders the
+// changes of concurrent syncs: a change older than the one already applied is rejected with
+// a conflict, and the same change is only applied once, so syncs can safely retry.
+// Updates leave out fields the input leaves empty, as in UpdateMeeting, and cannot move the
+// meeting to another organizer.
+func (s *MeetingServiceImpl) UpsertExternalMeeting(externalID string, input models.MeetingInput) (models.Meeting, bool, []models.Warning, error) {
+	externalID = strings.TrimSpace(externalID)
+	if externalID == "" {
+		return models.Meeting{}, false, nil, errors.NewValidationError("External ID is required", "")
+	}
+	if err := validateExternalID(externalID); err != nil {
+		return models.Meeting{}, false, nil, err
+	}
+	if input.ExternalUpdatedAt.IsZero() {
+		return models.Meeting{}, false, nil, errors.NewValidationError("External update time is required", "syncs must send when the calendar last changed the meeting")
+	}
+
+	s.upsertMu.Lock()
+	defer s.upsertMu.Unlock()
+
+	existing, err := s.repository.GetMeetingByExternalID(externalID)
+	if errors.Is(err, errors.ErrNotFound) {
+		input.ExternalID = externalID
+		created, warnings, err := s.CreateMeeting(input)
+		if !errors.Is(err, errors.ErrConflict) {
+			return created, err == nil, warnings, err
+		}
+		// Another replica sharing the storage created it first
+		existing, err = s.repository.GetMeetingByExternalID(externalID)
+	}
+	if err != nil {
+		return models.Meeting{}, false, nil, err
+	}
+
+	if input.OrganizerID != existing.OrganizerID {
+		return models.Meeting{}, false, nil, errors.NewConflictError("The synced meeting has another organizer")
+	}
+	if existing.ExternalUpdatedAt != nil {
+		if input.ExternalUpdatedAt.Before(*existing.ExternalUpdatedAt) {
+			return models.Meeting{}, false, nil, errors.NewConflictError("A newer change of the meeting was already synced")
+		}
+		if input.ExternalUpdatedAt.Equal(*existing.ExternalUpdatedAt) {
+			return existing, false, nil, nil
+		}
+	}
+
+	updated, warnings, err := s.UpdateMeeting(existing.ID, input)
+	return updated, false, warnings, err
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+)
+
+func TestMeetingService_UpsertExternalMeeting(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+	changedAt := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	input := models.MeetingInput{
+		Title:             "Synced Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     createTestTimeSlots(),
+		ParticipantIDs:    []string{participants[0].ID},
+		ExternalUpdatedAt: changedAt,
+	}
+
+	created, isNew, _, err := service.UpsertExternalMeeting("event-1", input)
+	require.NoError(t, err)
+	assert.True(t, isNew)
+	assert.Equal(t, "event-1", created.ExternalID)
+	assert.True(t, created.ExternalUpdatedAt.Equal(changedAt))
+
+	// Retrying the same change is a no-op
+	retried, isNew, _, err := service.UpsertExternalMeeting("event-1", input)
+	require.NoError(t, err)
+	assert.False(t, isNew)
+	assert.Equal(t, created.ID, retried.ID)
+	assert.Equal(t, created.UpdatedAt, retried.UpdatedAt)
+
+	// Newer changes update the meeting
+	input.Title = "Renamed Meeting"
+	input.ExternalUpdatedAt = changedAt.Add(time.Hour)
+	updated, isNew, _, err := service.UpsertExternalMeeting("event-1", input)
+	require.NoError(t, err)
+	assert.False(t, isNew)
+	assert.Equal(t, created.ID, updated.ID)
+	assert.Equal(t, "Renamed Meeting", updated.Title)
+
+	// Changes older than the one applied lose
+	input.Title = "Stale Meeting"
+	input.ExternalUpdatedAt = changedAt.Add(30 * time.Minute)
+	_, _, _, err = service.UpsertExternalMeeting("event-1", input)
+	assert.True(t, errors.Is(err, errors.ErrConflict))
+
+	// Synced meetings cannot move to another organizer
+	input.OrganizerID = participants[1].ID
+	input.ExternalUpdatedAt = changedAt.Add(2 * time.Hour)
+	_, _, _, err = service.UpsertExternalMeeting("event-1", input)
+	assert.True(t, errors.Is(err, errors.ErrConflict))
+
+	input.OrganizerID = organizer.ID
+	input.ExternalUpdatedAt = time.Time{}
+	_, _, _, err = service.UpsertExternalMeeting("event-1", input)
+	assert.True(t, errors.Is(err, errors.ErrValidation))
+	input.ExternalUpdatedAt = changedAt
+	_, _, _, err = service.UpsertExternalMeeting(" ", input)
+	assert.True(t, errors.Is(err, errors.ErrValidation))
+}
+
+func TestMeetingService_UpsertExternalMeetingConcurrently(t *testing.T) {
+	service, organizer, _ := setupTestMeetingService(t)
+	input := models.MeetingInput{
+		Title:             "Synced Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     createTestTimeSlots(),
+		ExternalUpdatedAt: time.Now(),
+	}
+
+	var wg sync.WaitGroup
+	ids := make([]string, 10)
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			meeting, _, _, err := service.UpsertExternalMeeting("event-1", input)
+			assert.NoError(t, err)
+			ids[i] = meeting.ID
+		}(i)
+	}
+	wg.Wait()
+
+	for _, id := range ids {
+		assert.Equal(t, ids[0], id)
+	}
+	meetings, _, err := service.ListMeetings("", 100, models.MeetingFilter{})
+	require.NoError(t, err)
+	assert.Len(t, meetings, 1)
+}
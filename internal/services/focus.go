@@ -0,0 +1,78 @@
+package services
+
+import (
+	"time"
+
+	"meetsync/internal/models"
+	"meetsync/internal/policy"
+	"meetsync/pkg/errors"
+)
+
+// focusRule is a focus block resolved for evaluation
+type focusRule struct {
+	engine   *policy.Engine
+	location *time.Location
+}
+
+// compileFocusBlocks validates focus blocks and resolves them into rules, reusing the
+// blocked hours policy so both share the same day and time handling
+func compileFocusBlocks(blocks []models.FocusBlock) ([]focusRule, error) {
+	rules := make([]focusRule, 0, len(blocks))
+	for _, block := range blocks {
+		if block.Start == "" || block.End == "" {
+			return nil, errors.NewValidationError("Focus blocks need a start and an end", "")
+		}
+		location, err := loadTimeZone(block.TimeZone)
+		if err != nil {
+			return nil, err
+		}
+		engine, err := policy.New([]policy.Rule{{
+			Name:  "focus time",
+			Kind:  policy.KindBlockedHours,
+			Days:  block.Days,
+			Start: block.Start,
+			End:   block.End,
+		}})
+		if err != nil {
+			return nil, errors.NewValidationError("Invalid focus block", err.Error())
+		}
+		rules = append(rules, focusRule{engine: engine, location: location})
+	}
+	return rules, nil
+}
+
+// inFocusTime reports whether slot overlaps any of the rules
+func inFocusTime(rules []focusRule, slot models.TimeSlot) bool {
+	for _, rule := range rules {
+		if len(rule.engine.Evaluate(slot, rule.location, nil, nil)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// focusRules looks up the current focus blocks of users. Blocks are read fresh rather than
+// from the participants stored on a meeting, which are copies taken when they were added.
+func (s *MeetingServiceImpl) focusRules(users []models.User) (map[string][]focusRule, error) {
+	ids := make([]string, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+	current, err := s.userService.GetUsersByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make(map[string][]focusRule, len(current))
+	for _, user := range current {
+		if len(user.FocusBlocks) == 0 {
+			continue
+		}
+		compiled, err := compileFocusBlocks(user.FocusBlocks)
+		if err != nil {
+			return nil, err
+		}
+		rules[user.ID] = compiled
+	}
+	return rules, nil
+}
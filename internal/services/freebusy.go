@@ -0,0 +1,77 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"meetsync/internal/metering"
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+)
+
+// maxFreeBusyWindow caps the length of the window of a free/busy lookup
+const maxFreeBusyWindow = 62 * 24 * time.Hour
+
+// FreeBusy returns when a user is busy between from and to according to their linked
+// calendar, merging overlapping and adjacent busy times
+func (s *SchedulingServiceImpl) FreeBusy(userID string, from, to time.Time) (models.FreeBusy, error) {
+	if !to.After(from) {
+		return models.FreeBusy{}, errors.NewValidationError("Window end must be after its start", "")
+	}
+	if to.Sub(from) > maxFreeBusyWindow {
+		return models.FreeBusy{}, errors.NewValidationError("Window is too long", fmt.Sprintf("windows can span at most %s", maxFreeBusyWindow))
+	}
+	user, err := s.userService.GetUserByID(userID)
+	if err != nil {
+		return models.FreeBusy{}, err
+	}
+
+	times, err := s.busySource.BusyTimes(user.ID, from, to)
+	if err != nil {
+		return models.FreeBusy{}, errors.NewDependencyError("Failed to look up busy times", err)
+	}
+	if _, unlinked := s.busySource.(NoBusyTimes); !unlinked {
+		s.meter.Record(metering.KindCalendarSync, user.ID)
+	}
+
+	return models.FreeBusy{
+		UserID: user.ID,
+		From:   from,
+		To:     to,
+		Busy:   mergeBusyTimes(times, from, to),
+	}, nil
+}
+
+// mergeBusyTimes clips busy times to the window from to to and merges the ones that
+// overlap or touch, returning them sorted by start time
+func mergeBusyTimes(times []models.TimeSlot, from, to time.Time) []models.TimeSlot {
+	clipped := make([]models.TimeSlot, 0, len(times))
+	for _, slot := range times {
+		start, end := slot.StartTime, slot.EndTime
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			clipped = append(clipped, models.TimeSlot{StartTime: start.UTC(), EndTime: end.UTC()})
+		}
+	}
+	sort.Slice(clipped, func(i, j int) bool {
+		return clipped[i].StartTime.Before(clipped[j].StartTime)
+	})
+
+	merged := make([]models.TimeSlot, 0, len(clipped))
+	for _, slot := range clipped {
+		if last := len(merged) - 1; last >= 0 && !slot.StartTime.After(merged[last].EndTime) {
+			if slot.EndTime.After(merged[last].EndTime) {
+				merged[last].EndTime = slot.EndTime
+			}
+			continue
+		}
+		merged = append(merged, slot)
+	}
+	return merged
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+)
+
+func TestFreeBusy(t *testing.T) {
+	userService := NewUserService()
+	alice, err := userService.CreateUser("Alice", "alice@example.com")
+	require.NoError(t, err)
+
+	from := time.Date(2025, 1, 14, 8, 0, 0, 0, time.UTC)
+	to := from.Add(8 * time.Hour)
+	busy := staticBusySource{
+		alice.ID: {
+			{StartTime: from.Add(5 * time.Hour), EndTime: from.Add(6 * time.Hour)},
+			{StartTime: from.Add(-time.Hour), EndTime: from.Add(time.Hour)},
+			{StartTime: from.Add(30 * time.Minute), EndTime: from.Add(2 * time.Hour)},
+			{StartTime: from.Add(2 * time.Hour), EndTime: from.Add(3 * time.Hour)},
+			{StartTime: from.Add(7 * time.Hour), EndTime: from.Add(10 * time.Hour)},
+			{StartTime: to.Add(time.Hour), EndTime: to.Add(2 * time.Hour)},
+		},
+	}
+	service := NewSchedulingService(userService, WithBusySource(busy))
+
+	freeBusy, err := service.FreeBusy(alice.ID, from, to)
+	require.NoError(t, err)
+	assert.Equal(t, alice.ID, freeBusy.UserID)
+	assert.Equal(t, []models.TimeSlot{
+		{StartTime: from, EndTime: from.Add(3 * time.Hour)},
+		{StartTime: from.Add(5 * time.Hour), EndTime: from.Add(6 * time.Hour)},
+		{StartTime: from.Add(7 * time.Hour), EndTime: to},
+	}, freeBusy.Busy)
+
+	_, err = service.FreeBusy(alice.ID, to, from)
+	assert.Error(t, err)
+	_, err = service.FreeBusy(alice.ID, from, from.Add(maxFreeBusyWindow+time.Hour))
+	assert.Error(t, err)
+
+	_, err = service.FreeBusy("missing", from, to)
+	var appErr *errors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, errors.ErrorTypeNotFound, appErr.Type)
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+	"meetsync/pkg/logs"
+)
+
+// AddGuest invites a guest identified by email to a meeting and returns the token giving
+// them access to it. Guests see the proposed times of that meeting and submit
+// availability for it, and nothing else. Published meetings email the guest their link.
+func (s *MeetingServiceImpl) AddGuest(meetingID, email, name string) (models.GuestInvitation, error) {
+	if s.guestSigner == nil {
+		return models.GuestInvitation{}, errors.NewNotFoundError("Guest access is disabled")
+	}
+
+	meeting, err := s.repository.GetMeetingByID(meetingID)
+	if err != nil {
+		return models.GuestInvitation{}, err
+	}
+	user, err := s.userService.EnsureGuest(email, name)
+	if err != nil {
+		return models.GuestInvitation{}, err
+	}
+
+	invited := false
+	for _, participant := range meeting.Participants {
+		invited = invited || participant.ID == user.ID
+	}
+	if !invited {
+		if err := s.quotas.CheckParticipants(len(meeting.Participants) + 1); err != nil {
+			return models.GuestInvitation{}, err
+		}
+		meeting.Participants = append(meeting.Participants, user)
+		if meeting, err = s.repository.UpdateMeeting(meeting); err != nil {
+			return models.GuestInvitation{}, err
+		}
+
+		s.refreshRecommendations(meetingID)
+		s.recordTimeline(meetingID, models.TimelineParticipantAdded, user.ID, user.Name+" was added as a guest")
+		if meeting.Status == models.MeetingStatusPending {
+			s.sendInvitations(meeting, []models.User{user}, meeting.ProposedSlots, false)
+		}
+		logs.Info("Added guest %s to meeting %s", user.ID, meetingID)
+	}
+
+	token := s.guestSigner.Sign(meetingID, user.ID)
+	return models.GuestInvitation{
+		MeetingID: meetingID,
+		Guest:     user,
+		Token:     token,
+		URL:       s.guestURL + "/api/guest/" + token,
+	}, nil
+}
+
+// GetGuestMeeting returns what the guest holding token may see of their meeting
+func (s *MeetingServiceImpl) GetGuestMeeting(token string) (models.GuestMeeting, error) {
+	meeting, user, err := s.guestAccess(token)
+	if err != nil {
+		return models.GuestMeeting{}, err
+	}
+
+	view := models.GuestMeeting{
+		MeetingID:         meeting.ID,
+		Title:             meeting.Title,
+		EstimatedDuration: meeting.EstimatedDuration,
+		ProposedSlots:     meeting.ProposedSlots,
+		TimeZone:          meeting.TimeZone,
+		Status:            meeting.Status,
+	}
+	availability, err := s.GetAvailability(user.ID, meeting.ID)
+	if err == nil {
+		view.Availability = &availability
+	} else if !errors.Is(err, errors.ErrNotFound) {
+		return models.GuestMeeting{}, err
+	}
+	return view, nil
+}
+
+// SubmitGuestAvailability records the availability of the guest holding token for their
+// meeting, replacing what they submitted before
+func (s *MeetingServiceImpl) SubmitGuestAvailability(token string, availableSlots []models.TimeSlot, tentative bool) (models.Availability, error) {
+	meeting, user, err := s.guestAccess(token)
+	if err != nil {
+		return models.Availability{}, err
+	}
+
+	return s.replaceAvailability(user.ID, meeting.ID, availableSlots, tentative)
+}
+
+// guestAccess verifies a guest token and returns the meeting and guest it grants access
+// to. Guests removed from the meeting lose access even though their token is still valid.
+func (s *MeetingServiceImpl) guestAccess(token string) (models.Meeting, models.User, error) {
+	if s.guestSigner == nil {
+		return models.Meeting{}, models.User{}, errors.NewNotFoundError("Guest access is disabled")
+	}
+	access, err := s.guestSigner.Verify(token)
+	if err != nil {
+		return models.Meeting{}, models.User{}, err
+	}
+
+	meeting, err := s.repository.GetMeetingByID(access.MeetingID)
+	if errors.Is(err, errors.ErrNotFound) {
+		return models.Meeting{}, models.User{}, errors.NewUnauthorizedError("Guest link is no longer valid")
+	}
+	if err != nil {
+		return models.Meeting{}, models.User{}, err
+	}
+	for _, participant := range meeting.Participants {
+		if participant.ID == access.UserID && participant.Guest {
+			return meeting, participant, nil
+		}
+	}
+	return models.Meeting{}, models.User{}, errors.NewUnauthorizedError("Guest link is no longer valid")
+}
+
+// guestMeetingURL returns the link a guest follows to view the proposed times and submit
+// availability, or nothing for members or when guest access is disabled
+func (s *MeetingServiceImpl) guestMeetingURL(meeting models.Meeting, participant models.User) string {
+	if s.guestSigner == nil || !participant.Guest {
+		return ""
+	}
+	return s.guestURL + "/api/guest/" + s.guestSigner.Sign(meeting.ID, participant.ID)
+}
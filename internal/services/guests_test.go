@@ -0,0 +1,80 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"meetsync/internal/guest"
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+)
+
+func TestGuestParticipants(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+	service.guestSigner = guest.NewSigner([]byte("secret"), time.Hour)
+	service.guestURL = "https://meetsync.example.com"
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Vendor sync",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     createTestTimeSlots(),
+		ParticipantIDs:    []string{participants[0].ID},
+	})
+	require.NoError(t, err)
+
+	invitation, err := service.AddGuest(meeting.ID, "vendor@partner.com", "")
+	require.NoError(t, err)
+	assert.True(t, invitation.Guest.Guest)
+	assert.Equal(t, "vendor", invitation.Guest.Name)
+	assert.Equal(t, "https://meetsync.example.com/api/guest/"+invitation.Token, invitation.URL)
+
+	// Inviting the guest again hands out a new link without adding them twice
+	again, err := service.AddGuest(meeting.ID, "vendor@partner.com", "Vendor")
+	require.NoError(t, err)
+	assert.Equal(t, invitation.Guest.ID, again.Guest.ID)
+	stored, err := service.repository.GetMeetingByID(meeting.ID)
+	require.NoError(t, err)
+	assert.Len(t, stored.Participants, 2)
+
+	// Members cannot be added as guests
+	_, err = service.AddGuest(meeting.ID, participants[1].Email, "")
+	assert.True(t, errors.Is(err, errors.ErrConflict))
+
+	view, err := service.GetGuestMeeting(invitation.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "Vendor sync", view.Title)
+	assert.Nil(t, view.Availability)
+
+	availability, err := service.SubmitGuestAvailability(invitation.Token, meeting.ProposedSlots[:1], false)
+	require.NoError(t, err)
+	assert.Equal(t, invitation.Guest.ID, availability.ParticipantID)
+	availability, err = service.SubmitGuestAvailability(invitation.Token, meeting.ProposedSlots, true)
+	require.NoError(t, err)
+	assert.Len(t, availability.AvailableSlots, 2)
+	view, err = service.GetGuestMeeting(invitation.Token)
+	require.NoError(t, err)
+	require.NotNil(t, view.Availability)
+	assert.True(t, view.Availability.Tentative)
+
+	// Guests cannot organize meetings
+	_, _, err = service.CreateMeeting(models.MeetingInput{
+		Title:             "Guest meeting",
+		OrganizerID:       invitation.Guest.ID,
+		EstimatedDuration: 30,
+		ProposedSlots:     createTestTimeSlots(),
+		ParticipantIDs:    []string{participants[0].ID},
+	})
+	assert.True(t, errors.Is(err, errors.ErrValidation))
+
+	// Tampered tokens and tokens of guests removed from the meeting are rejected
+	_, err = service.GetGuestMeeting(invitation.Token + "x")
+	assert.True(t, errors.Is(err, errors.ErrUnauthorized))
+	_, _, err = service.UpdateMeeting(meeting.ID, models.MeetingInput{ParticipantIDs: []string{participants[0].ID}})
+	require.NoError(t, err)
+	_, err = service.GetGuestMeeting(invitation.Token)
+	assert.True(t, errors.Is(err, errors.ErrUnauthorized))
+}
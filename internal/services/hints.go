@@ -0,0 +1,75 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"meetsync/internal/models"
+	"meetsync/pkg/logs"
+)
+
+// schedulingHints reports issues with the proposed slots of a stored meeting that do not
+// prevent it from being scheduled: slots too short for the meeting, slots outside the
+// working hours of the organization and participants who offered a slot to another open
+// meeting. Hints are best effort; failing to compute one never fails the request.
+func (s *MeetingServiceImpl) schedulingHints(meeting models.Meeting, location *time.Location) []models.Warning {
+	warnings := shortSlotWarnings(meeting.ProposedSlots, meeting.EstimatedDuration)
+	if s.workingHours != nil {
+		warnings = append(warnings, workingHoursWarnings(meeting.ProposedSlots, s.workingHours, location)...)
+	}
+
+	competing, err := s.competingSlots(meeting, meeting.Participants)
+	if err != nil {
+		logs.Warn("Failed to check participant conflicts for meeting %s: %v", meeting.ID, err)
+		return warnings
+	}
+	for _, slot := range meeting.ProposedSlots {
+		for _, participant := range meeting.Participants {
+			for _, conflict := range conflictsFor(participant.ID, competing[participant.ID], slot) {
+				warnings = append(warnings, models.Warning{
+					Code:    models.WarningParticipantConflict,
+					Message: fmt.Sprintf("%s also offered slot %s to meeting %s", participant.Name, formatSlot(slot), conflict.MeetingID),
+				})
+			}
+		}
+	}
+	return warnings
+}
+
+// shortSlotWarnings reports slots shorter than duration, in minutes. They can never be
+// recommended, since the meeting does not fit in them.
+func shortSlotWarnings(slots []models.TimeSlot, duration int) []models.Warning {
+	var warnings []models.Warning
+	if duration <= 0 {
+		return warnings
+	}
+	for _, slot := range slots {
+		if length := slot.EndTime.Sub(slot.StartTime); length < time.Duration(duration)*time.Minute {
+			warnings = append(warnings, models.Warning{
+				Code:    models.WarningShortSlot,
+				Message: fmt.Sprintf("Slot %s lasts %s, shorter than the %d minutes of the meeting", formatSlot(slot), length, duration),
+			})
+		}
+	}
+	return warnings
+}
+
+// workingHoursWarnings reports slots that do not lie within working hours in location.
+// The working hours are validated when they are configured.
+func workingHoursWarnings(slots []models.TimeSlot, hours *models.WorkingHours, location *time.Location) []models.Warning {
+	inWorkingHours, err := workingHoursFilter(hours, location)
+	if err != nil {
+		return nil
+	}
+
+	var warnings []models.Warning
+	for _, slot := range slots {
+		if !inWorkingHours(slot) {
+			warnings = append(warnings, models.Warning{
+				Code:    models.WarningOutsideWorkingHours,
+				Message: fmt.Sprintf("Slot %s is outside working hours (%s to %s in %s)", formatSlot(slot), hours.Start, hours.End, location),
+			})
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"meetsync/internal/models"
+)
+
+// warningCodes returns the codes of warnings in order
+func warningCodes(warnings []models.Warning) []models.WarningCode {
+	codes := make([]models.WarningCode, 0, len(warnings))
+	for _, warning := range warnings {
+		codes = append(codes, warning.Code)
+	}
+	return codes
+}
+
+func TestSchedulingHints(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+	service.workingHours = &models.WorkingHours{Start: "09:00", End: "17:00", ExcludeWeekends: true}
+
+	// Monday 10:00 to 11:00 fits, Monday 20:00 is outside working hours and Tuesday
+	// 10:00 to 10:30 is too short for an hour-long meeting
+	monday := time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC)
+	fits := models.TimeSlot{StartTime: monday.Add(10 * time.Hour), EndTime: monday.Add(11 * time.Hour)}
+	evening := models.TimeSlot{StartTime: monday.Add(20 * time.Hour), EndTime: monday.Add(21 * time.Hour)}
+	short := models.TimeSlot{StartTime: monday.Add(34 * time.Hour), EndTime: monday.Add(34*time.Hour + 30*time.Minute)}
+
+	meeting, warnings, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Planning",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     []models.TimeSlot{fits, evening, short},
+		ParticipantIDs:    []string{participants[0].ID},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []models.WarningCode{models.WarningShortSlot, models.WarningOutsideWorkingHours}, warningCodes(warnings))
+
+	// A participant who offered a slot to this meeting makes it conflict for another one
+	_, err = service.AddAvailability(participants[0].ID, meeting.ID, []models.TimeSlot{meeting.ProposedSlots[0]}, false)
+	require.NoError(t, err)
+	other, warnings, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Review",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 30,
+		ProposedSlots:     []models.TimeSlot{fits},
+		ParticipantIDs:    []string{participants[0].ID, participants[1].ID},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []models.WarningCode{models.WarningParticipantConflict}, warningCodes(warnings))
+	assert.Contains(t, warnings[0].Message, participants[0].Name)
+	assert.Contains(t, warnings[0].Message, meeting.ID)
+
+	// Updates return the hints of the updated meeting; weekends are outside working hours
+	saturday := models.TimeSlot{StartTime: monday.Add(5*24*time.Hour + 10*time.Hour), EndTime: monday.Add(5*24*time.Hour + 11*time.Hour)}
+	_, warnings, err = service.UpdateMeeting(other.ID, models.MeetingInput{ProposedSlots: []models.TimeSlot{saturday}})
+	require.NoError(t, err)
+	assert.Equal(t, []models.WarningCode{models.WarningOutsideWorkingHours}, warningCodes(warnings))
+
+	// Working hours apply in the time zone of the meeting
+	_, warnings, err = service.UpdateMeeting(other.ID, models.MeetingInput{ProposedSlots: []models.TimeSlot{fits}, TimeZone: "Asia/Tokyo"})
+	require.NoError(t, err)
+	assert.Contains(t, warningCodes(warnings), models.WarningOutsideWorkingHours)
+}
+
+func TestWithWorkingHoursIgnoresInvalidHours(t *testing.T) {
+	service := NewMeetingService(NewUserService(), WithWorkingHours(&models.WorkingHours{Start: "17:00", End: "09:00"})).(*MeetingServiceImpl)
+	assert.Nil(t, service.workingHours)
+}
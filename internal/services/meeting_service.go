@@ -1,166 +1,1178 @@
 package services
 
 import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
 	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"meetsync/internal/events"
+	"meetsync/internal/guest"
+	"meetsync/internal/health"
+	"meetsync/internal/integrations"
 	"meetsync/internal/interfaces"
+	"meetsync/internal/metering"
+	"meetsync/internal/metrics"
 	"meetsync/internal/models"
+	"meetsync/internal/notifications"
+	"meetsync/internal/pagination"
+	"meetsync/internal/policy"
+	"meetsync/internal/precompute"
+	"meetsync/internal/quota"
 	"meetsync/internal/repositories"
+	"meetsync/internal/rsvp"
+	"meetsync/internal/sanitize"
 	"meetsync/pkg/errors"
+	"meetsync/pkg/logs"
 
 	"github.com/google/uuid"
 )
 
+// maxSearchQueryLength caps the length of free-text search queries
+const maxSearchQueryLength = 200
+
+// storagePollInterval is how often readers waiting for recommendations to change check
+// storage, when workers or other replicas change them
+const storagePollInterval = time.Second
+
 // MeetingServiceImpl implements the MeetingService interface
 type MeetingServiceImpl struct {
 	repository  repositories.MeetingRepository
 	userService interfaces.UserService
+	publisher   events.Publisher
+	sloTracker  *metrics.SLOTracker
+	notifier    notifications.Notifier
+	policies    *policy.Engine
+	changes     *changeBroadcaster
+	storage     *health.StorageMonitor
+	quotas      *quota.Tracker
+	meter       *metering.Meter
+	rsvpSigner  *rsvp.Signer
+	rsvpBaseURL string
+	pollURL     string
+	guestSigner *guest.Signer
+	guestURL    string
+	templates   *notifications.Templates
+	calendars   map[string]integrations.Provider
+	precompute  precompute.Queue
+
+	workingHours               *models.WorkingHours
+	duplicateWindow            time.Duration
+	requireVerifiedOrganizer   bool
+	textLimits                 sanitize.Limits
+	materializeRecommendations bool
+	readOnly                   bool
+	sharedStorage              bool
+	slotGranularity            time.Duration
+
+	upsertMu sync.Mutex // serializes upserts of meetings synced from external calendars
 }
 
 var _ interfaces.MeetingService = (*MeetingServiceImpl)(nil) // Verify MeetingServiceImpl implements MeetingService interface
 
+// MeetingServiceOption configures optional dependencies of a MeetingServiceImpl
+type MeetingServiceOption func(*MeetingServiceImpl)
+
+// WithEventPublisher sets the publisher used to emit scheduling events
+func WithEventPublisher(publisher events.Publisher) MeetingServiceOption {
+	return func(s *MeetingServiceImpl) {
+		s.publisher = publisher
+	}
+}
+
+// WithSLOTracker sets the tracker used to record scheduling SLO metrics
+func WithSLOTracker(tracker *metrics.SLOTracker) MeetingServiceOption {
+	return func(s *MeetingServiceImpl) {
+		s.sloTracker = tracker
+	}
+}
+
+// WithNotifier sets the notifier used to send invitations to participants
+func WithNotifier(notifier notifications.Notifier) MeetingServiceOption {
+	return func(s *MeetingServiceImpl) {
+		s.notifier = notifier
+	}
+}
+
+// WithNotificationTemplates sets the templates rendering notifications about meetings
+func WithNotificationTemplates(templates *notifications.Templates) MeetingServiceOption {
+	return func(s *MeetingServiceImpl) {
+		s.templates = templates
+	}
+}
+
+// WithPolicies sets the scheduling policies checked for every recommended slot
+func WithPolicies(policies *policy.Engine) MeetingServiceOption {
+	return func(s *MeetingServiceImpl) {
+		s.policies = policies
+	}
+}
+
+// WithEmailVerificationRequired makes unverified users unable to organize meetings.
+// Unverified users can still respond to meetings they were invited to.
+func WithEmailVerificationRequired(required bool) MeetingServiceOption {
+	return func(s *MeetingServiceImpl) {
+		s.requireVerifiedOrganizer = required
+	}
+}
+
+// WithTextLimits sets the maximum lengths of free-text meeting fields
+func WithTextLimits(limits sanitize.Limits) MeetingServiceOption {
+	return func(s *MeetingServiceImpl) {
+		s.textLimits = limits
+	}
+}
+
+// WithMaterializedRecommendations recomputes and stores recommendations whenever a
+// meeting or its availability changes, so reading them does not recompute anything
+func WithMaterializedRecommendations(enabled bool) MeetingServiceOption {
+	return func(s *MeetingServiceImpl) {
+		s.materializeRecommendations = enabled
+	}
+}
+
+// WithPrecomputeQueue hands off refreshing stored recommendations to scheduler workers
+// through queue, instead of computing them during the request that changed the meeting
+func WithPrecomputeQueue(queue precompute.Queue) MeetingServiceOption {
+	return func(s *MeetingServiceImpl) {
+		s.precompute = queue
+	}
+}
+
+// WithReadOnlyStorage marks the storage as a read replica: recommendations missing from
+// it are computed without being stored
+func WithReadOnlyStorage(readOnly bool) MeetingServiceOption {
+	return func(s *MeetingServiceImpl) {
+		s.readOnly = readOnly
+	}
+}
+
+// WithSharedStorage marks the storage as shared with other replicas, whose changes do not
+// wake up readers waiting here; these check storage periodically instead
+func WithSharedStorage(shared bool) MeetingServiceOption {
+	return func(s *MeetingServiceImpl) {
+		s.sharedStorage = shared
+	}
+}
+
+// WithSlotGranularity splits proposed windows longer than a meeting into candidate
+// sub-slots starting every granularity. Zero disables splitting.
+func WithSlotGranularity(granularity time.Duration) MeetingServiceOption {
+	return func(s *MeetingServiceImpl) {
+		s.slotGranularity = granularity
+	}
+}
+
+// WithStorageMonitor sets the monitor recording whether meeting storage is reachable
+func WithStorageMonitor(monitor *health.StorageMonitor) MeetingServiceOption {
+	return func(s *MeetingServiceImpl) {
+		s.storage = monitor
+	}
+}
+
+// WithQuotas sets the tracker enforcing the organization's usage quotas
+func WithQuotas(quotas *quota.Tracker) MeetingServiceOption {
+	return func(s *MeetingServiceImpl) {
+		s.quotas = quotas
+	}
+}
+
+// WithMeter sets the meter recording created meetings for usage-based billing
+func WithMeter(meter *metering.Meter) MeetingServiceOption {
+	return func(s *MeetingServiceImpl) {
+		s.meter = meter
+	}
+}
+
+// WithRSVPLinks adds signed one-click links to invitations, answering for a single
+// proposed slot, pointing at the API served from baseURL
+func WithRSVPLinks(signer *rsvp.Signer, baseURL string) MeetingServiceOption {
+	return func(s *MeetingServiceImpl) {
+		s.rsvpSigner = signer
+		s.rsvpBaseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithPollURL sets the URL of the page collecting availability for a meeting, with {id}
+// standing for the meeting ID
+func WithPollURL(template string) MeetingServiceOption {
+	return func(s *MeetingServiceImpl) {
+		s.pollURL = template
+	}
+}
+
+// WithGuestLinks lets organizers invite guests from outside the organization, who access
+// their meeting through tokens signed by signer on the API served from baseURL
+func WithGuestLinks(signer *guest.Signer, baseURL string) MeetingServiceOption {
+	return func(s *MeetingServiceImpl) {
+		s.guestSigner = signer
+		s.guestURL = baseURL
+	}
+}
+
+// WithWorkingHours warns about proposed slots outside hours, in the time zone of each
+// meeting; nil disables the check
+func WithWorkingHours(hours *models.WorkingHours) MeetingServiceOption {
+	return func(s *MeetingServiceImpl) {
+		s.workingHours = hours
+	}
+}
+
+// WithDuplicateDetection warns when a new meeting looks like one its organizer already
+// created: a similar title, a shared participant and proposed slots within window of each
+// other. Zero disables the check.
+func WithDuplicateDetection(window time.Duration) MeetingServiceOption {
+	return func(s *MeetingServiceImpl) {
+		s.duplicateWindow = window
+	}
+}
+
+// WithCalendarProviders sets the calendar providers availability can be imported from,
+// by name
+func WithCalendarProviders(providers map[string]integrations.Provider) MeetingServiceOption {
+	return func(s *MeetingServiceImpl) {
+		s.calendars = providers
+	}
+}
+
+// WithMeetingRepository sets the repository meetings and availability are stored in,
+// such as one opened by a registered storage driver
+func WithMeetingRepository(repository repositories.MeetingRepository) MeetingServiceOption {
+	return func(s *MeetingServiceImpl) {
+		s.repository = repository
+	}
+}
+
 // NewMeetingService creates a new MeetingService
-func NewMeetingService(userService interfaces.UserService) interfaces.MeetingService {
-	return &MeetingServiceImpl{
+func NewMeetingService(userService interfaces.UserService, opts ...MeetingServiceOption) interfaces.MeetingService {
+	s := &MeetingServiceImpl{
 		repository:  repositories.NewInMemoryMeetingRepository(),
 		userService: userService,
+		publisher:   events.NoopPublisher{},
+		sloTracker:  metrics.NewSLOTracker(),
+		notifier:    notifications.NoopNotifier{},
+		templates:   notifications.DefaultTemplates(),
+		textLimits:  sanitize.DefaultLimits,
+		changes:     newChangeBroadcaster(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.repository = guardedMeetingRepository{next: s.repository, guard: newStorageGuard(s.storage)}
+	if s.workingHours != nil {
+		if _, err := workingHoursFilter(s.workingHours, time.UTC); err != nil {
+			logs.Error("Ignoring invalid working hours: %v", err)
+			s.workingHours = nil
+		}
 	}
+	return s
 }
 
-// CreateMeeting creates a new meeting
-func (s *MeetingServiceImpl) CreateMeeting(title string, organizerID string, estimatedDuration int, proposedSlots []models.TimeSlot, participantIDs []string) (models.Meeting, error) {
-	// Validate input
-	if title == "" {
-		return models.Meeting{}, errors.NewValidationError("Title is required", "")
+// CreateMeeting creates a new meeting. Draft meetings may omit the title,
+// duration and proposed slots until they are published. Proposed slots are
+// normalized and the changes made to them are returned as warnings, together
+// with hints about slots that may not work out.
+func (s *MeetingServiceImpl) CreateMeeting(input models.MeetingInput) (models.Meeting, []models.Warning, error) {
+	// Sanitize and validate input
+	input.Title = sanitize.SingleLine(input.Title)
+	if err := sanitize.CheckLength("Title", input.Title, s.textLimits.Title); err != nil {
+		return models.Meeting{}, nil, err
 	}
-	if estimatedDuration <= 0 {
-		return models.Meeting{}, errors.NewValidationError("Estimated duration must be positive", "")
+	if !input.Draft {
+		if err := validateMeetingDetails(input.Title, input.EstimatedDuration, input.ProposedSlots); err != nil {
+			return models.Meeting{}, nil, err
+		}
+	} else if input.EstimatedDuration < 0 {
+		return models.Meeting{}, nil, errors.NewValidationError("Estimated duration must be positive", "")
 	}
-	if len(proposedSlots) == 0 {
-		return models.Meeting{}, errors.NewValidationError("At least one proposed time slot is required", "")
+	if err := validateDurationRange(input.EstimatedDuration, input.MaxDuration); err != nil {
+		return models.Meeting{}, nil, err
+	}
+	if input.AvailabilityTTL < 0 {
+		return models.Meeting{}, nil, errors.NewValidationError("Availability TTL must be positive", "")
+	}
+	if err := validateExternalID(input.ExternalID); err != nil {
+		return models.Meeting{}, nil, err
+	}
+	if err := validateMetadata(input.Metadata); err != nil {
+		return models.Meeting{}, nil, err
+	}
+	tags, err := normalizeTags(input.Tags)
+	if err != nil {
+		return models.Meeting{}, nil, err
+	}
+	if input.Priority == "" {
+		input.Priority = models.PriorityNormal
+	} else if !input.Priority.Valid() {
+		return models.Meeting{}, nil, errors.NewValidationError("Invalid priority", "use low, normal, high or urgent")
+	}
+
+	location, err := loadTimeZone(input.TimeZone)
+	if err != nil {
+		return models.Meeting{}, nil, err
+	}
+	proposedSlots, warnings := normalizeSlots(input.ProposedSlots, input.CoalesceAdjacentSlots)
+	if err := validateProposedSlots(proposedSlots); err != nil {
+		return models.Meeting{}, nil, err
 	}
+	warnings = append(warnings, dstWarnings(proposedSlots, location)...)
 
 	// Validate organizer exists
-	organizer, err := s.userService.GetUserByID(organizerID)
+	organizer, err := s.userService.GetUserByID(input.OrganizerID)
 	if err != nil {
-		return models.Meeting{}, errors.NewNotFoundError("Organizer not found")
+		return models.Meeting{}, nil, errors.NewNotFoundError("Organizer not found")
+	}
+	if s.requireVerifiedOrganizer && !organizer.EmailVerified {
+		return models.Meeting{}, nil, errors.NewValidationError("Organizer email is not verified", "Verify the email address before organizing meetings")
+	}
+	if !organizer.Active() {
+		return models.Meeting{}, nil, errors.NewValidationError("Organizer is deactivated", "")
+	}
+	if organizer.Guest {
+		return models.Meeting{}, nil, errors.NewValidationError("Guests cannot organize meetings", "")
 	}
 
 	// Validate participants exist
-	var participants []models.User
-	for _, participantID := range participantIDs {
-		participant, err := s.userService.GetUserByID(participantID)
-		if err != nil {
-			return models.Meeting{}, errors.NewNotFoundError("Participant not found: " + participantID)
-		}
-		participants = append(participants, participant)
+	participants, err := s.lookupParticipants(input.ParticipantIDs)
+	if err != nil {
+		return models.Meeting{}, nil, err
+	}
+
+	releaseQuota, err := s.quotas.ReserveMeeting(len(participants))
+	if err != nil {
+		return models.Meeting{}, nil, err
+	}
+
+	status := models.MeetingStatusPending
+	if input.Draft {
+		status = models.MeetingStatusDraft
 	}
 
 	// Create meeting
 	meeting := models.Meeting{
-		Title:             title,
-		OrganizerID:       organizerID,
+		Title:             input.Title,
+		OrganizerID:       input.OrganizerID,
 		Organizer:         &organizer,
-		EstimatedDuration: estimatedDuration,
+		EstimatedDuration: input.EstimatedDuration,
+		MaxDuration:       input.MaxDuration,
 		ProposedSlots:     proposedSlots,
 		Participants:      participants,
+		Status:            status,
+		TimeZone:          input.TimeZone,
+		OverrideFocusTime: input.OverrideFocusTime != nil && *input.OverrideFocusTime,
+		AvailabilityTTL:   input.AvailabilityTTL,
+		Tags:              tags,
+		Priority:          input.Priority,
+		ExternalID:        input.ExternalID,
+	}
+	if len(input.Metadata) > 0 {
+		meeting.Metadata = input.Metadata
+	}
+	if !input.ExternalUpdatedAt.IsZero() {
+		meeting.ExternalUpdatedAt = &input.ExternalUpdatedAt
+	}
+
+	createdMeeting, err := s.repository.CreateMeeting(meeting)
+	if err != nil {
+		releaseQuota()
+		return models.Meeting{}, nil, err
+	}
+
+	s.sloTracker.RecordMeetingCreated()
+	s.meter.Record(metering.KindMeetingCreated, createdMeeting.ID)
+	s.publish(events.MeetingCreated, createdMeeting.ID, createdMeeting)
+	s.recordTimeline(createdMeeting.ID, models.TimelineMeetingCreated, input.OrganizerID, "Meeting created by "+organizer.Name)
+	for _, participant := range participants {
+		s.recordTimeline(createdMeeting.ID, models.TimelineParticipantAdded, participant.ID, participant.Name+" was added as a participant")
+	}
+	if createdMeeting.Status == models.MeetingStatusPending {
+		s.sendInvitations(createdMeeting, createdMeeting.Participants, createdMeeting.ProposedSlots, false)
+	}
+	warnings = append(warnings, s.schedulingHints(createdMeeting, location)...)
+	warnings = append(warnings, s.duplicateWarnings(createdMeeting)...)
+	return createdMeeting, warnings, nil
+}
+
+// ListMeetings returns a page of meetings matching the filter ordered by creation time and the
+// cursor of the next page
+func (s *MeetingServiceImpl) ListMeetings(cursor string, limit int, filter models.MeetingFilter) ([]models.Meeting, string, error) {
+	after, err := pagination.Decode(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	limit = pagination.NormalizeLimit(limit)
+	if filter.Tags, err = normalizeTags(filter.Tags); err != nil {
+		return nil, "", err
+	}
+	if filter.Status != "" && !filter.Status.Valid() {
+		return nil, "", errors.NewValidationError("Invalid status", "use draft, pending, confirmed or cancelled")
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, "", errors.NewValidationError("Invalid date range", "from must be before to")
+	}
+
+	meetings, err := s.repository.ListMeetings(after, limit+1, filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(meetings) > limit {
+		meetings = meetings[:limit]
+		last := meetings[limit-1]
+		nextCursor = pagination.Cursor{Timestamp: last.CreatedAt, ID: last.ID}.Encode()
+	}
+	return meetings, nextCursor, nil
+}
+
+// SearchMeetings returns up to limit meetings matching a free-text query, most relevant first
+func (s *MeetingServiceImpl) SearchMeetings(query string, limit int) ([]models.SearchResult, error) {
+	query = sanitize.SingleLine(query)
+	if query == "" {
+		return nil, errors.NewValidationError("Search query is required", "")
+	}
+	if err := sanitize.CheckLength("Search query", query, maxSearchQueryLength); err != nil {
+		return nil, err
+	}
+	return s.repository.SearchMeetings(query, pagination.NormalizeLimit(limit))
+}
+
+// PublishMeeting validates a draft meeting and opens it for availability, inviting its participants
+func (s *MeetingServiceImpl) PublishMeeting(meetingID string) (models.Meeting, error) {
+	meeting, err := s.repository.GetMeetingByID(meetingID)
+	if err != nil {
+		return models.Meeting{}, err
+	}
+
+	if meeting.Status != models.MeetingStatusDraft {
+		return models.Meeting{}, errors.NewConflictError("Meeting is already published")
+	}
+	if err := validateMeetingDetails(meeting.Title, meeting.EstimatedDuration, meeting.ProposedSlots); err != nil {
+		return models.Meeting{}, err
+	}
+
+	meeting.Status = models.MeetingStatusPending
+	publishedMeeting, err := s.repository.UpdateMeeting(meeting)
+	if err != nil {
+		return models.Meeting{}, err
+	}
+
+	s.sendInvitations(publishedMeeting, publishedMeeting.Participants, publishedMeeting.ProposedSlots, false)
+	return publishedMeeting, nil
+}
+
+// ConfirmMeeting finalizes a pending meeting on one of its recommended slots. Confirmed
+// meetings no longer accept availability.
+func (s *MeetingServiceImpl) ConfirmMeeting(meetingID string, slot models.TimeSlot) (models.Meeting, error) {
+	meeting, err := s.repository.GetMeetingByID(meetingID)
+	if err != nil {
+		return models.Meeting{}, err
+	}
+
+	switch meeting.Status {
+	case models.MeetingStatusDraft:
+		return models.Meeting{}, errors.NewValidationError("Cannot confirm a draft meeting", "Publish the meeting first")
+	case models.MeetingStatusConfirmed:
+		return models.Meeting{}, errors.NewConflictError("Meeting is already confirmed")
+	case models.MeetingStatusCancelled:
+		return models.Meeting{}, errors.NewConflictError("Meeting is cancelled")
+	}
+
+	set, err := s.currentRecommendations(context.Background(), meetingID)
+	if err != nil {
+		return models.Meeting{}, err
+	}
+	rank := -1
+	for i, recommended := range set.Slots {
+		if recommended.TimeSlot.StartTime.Equal(slot.StartTime) && recommended.TimeSlot.EndTime.Equal(slot.EndTime) {
+			rank = i
+			break
+		}
+	}
+	if rank < 0 {
+		return models.Meeting{}, errors.NewValidationError("Slot is not a recommended slot", "Pick one of the slots returned by the recommendations")
+	}
+
+	confirmedSlot := set.Slots[rank].TimeSlot
+	meeting.Status = models.MeetingStatusConfirmed
+	meeting.ConfirmedSlot = &confirmedSlot
+	confirmedMeeting, err := s.repository.UpdateMeeting(meeting)
+	if err != nil {
+		return models.Meeting{}, err
+	}
+
+	s.sloTracker.RecordFinalization(rank == 0)
+	s.publish(events.MeetingFinalized, meetingID, confirmedMeeting)
+	s.recordTimeline(meetingID, models.TimelineMeetingFinalized, meeting.OrganizerID, "Meeting confirmed for "+formatSlot(confirmedSlot))
+	s.sendFinalization(confirmedMeeting, confirmedMeeting.Participants, "")
+	return confirmedMeeting, nil
+}
+
+// CancelMeeting cancels a meeting that is not cancelled yet. Cancelled meetings no longer
+// accept availability.
+func (s *MeetingServiceImpl) CancelMeeting(meetingID string) (models.Meeting, error) {
+	meeting, err := s.repository.GetMeetingByID(meetingID)
+	if err != nil {
+		return models.Meeting{}, err
+	}
+	if meeting.Status == models.MeetingStatusCancelled {
+		return models.Meeting{}, errors.NewConflictError("Meeting is already cancelled")
+	}
+
+	meeting.Status = models.MeetingStatusCancelled
+	cancelledMeeting, err := s.repository.UpdateMeeting(meeting)
+	if err != nil {
+		return models.Meeting{}, err
+	}
+
+	s.recordTimeline(meetingID, models.TimelineMeetingCancelled, meeting.OrganizerID, "Meeting cancelled")
+	return cancelledMeeting, nil
+}
+
+// checkCollectingAvailability returns an error unless meeting accepts availability:
+// drafts do not yet, and confirmed and cancelled meetings no longer do
+func checkCollectingAvailability(meeting models.Meeting) error {
+	switch meeting.Status {
+	case models.MeetingStatusDraft:
+		return errors.NewValidationError("Cannot submit availability for a draft meeting", "")
+	case models.MeetingStatusConfirmed:
+		return errors.NewConflictError("Meeting is confirmed and no longer accepts availability")
+	case models.MeetingStatusCancelled:
+		return errors.NewConflictError("Meeting is cancelled and no longer accepts availability")
+	}
+	return nil
+}
+
+// GetRecommendations gets meeting time recommendations based on participant availability.
+// With materialized recommendations the stored set is returned, computing it only if missing.
+// Computing them fails with a timeout error once ctx is done. The view is recorded on the
+// timeline and published with viewerID, empty when the viewer is unknown, and the top slot,
+// once per viewer until the meeting changes, so polling and streaming clients do not
+// flood the timeline.
+func (s *MeetingServiceImpl) GetRecommendations(ctx context.Context, meetingID, viewerID string) (models.RecommendationSet, error) {
+	set, err := s.currentRecommendations(ctx, meetingID)
+	if err != nil {
+		return models.RecommendationSet{}, err
+	}
+
+	s.recordRecommendationView(set, viewerID)
+	return set, nil
+}
+
+// recordRecommendationView records the recommendations of a meeting being viewed on its
+// timeline and publishes the view, unless the viewer already viewed them since the meeting
+// last changed. Read replicas cannot record views, so they skip them.
+func (s *MeetingServiceImpl) recordRecommendationView(set models.RecommendationSet, viewerID string) {
+	if s.readOnly || s.viewedSinceChange(set.MeetingID, viewerID) {
+		return
+	}
+
+	view := models.RecommendationView{ViewerID: viewerID, ComputedAt: set.ComputedAt}
+	description := "Recommendations were viewed"
+	if len(set.Slots) > 0 {
+		top := set.Slots[0]
+		view.TopSlot = &top.TimeSlot
+		view.AvailableCount = top.AvailableCount
+		view.TotalParticipants = top.TotalParticipants
+		description += fmt.Sprintf(" with %s as the best slot", formatSlot(top.TimeSlot))
+	}
+	if viewerID != "" {
+		if meeting, err := s.repository.GetMeetingByID(set.MeetingID); err == nil {
+			view.ByOrganizer = meeting.OrganizerID == viewerID
+		}
+	}
+
+	s.recordTimeline(set.MeetingID, models.TimelineRecommendationViewed, viewerID, description)
+	s.publish(events.RecommendationsViewed, set.MeetingID, view)
+}
+
+// viewedSinceChange reports whether viewerID viewed the recommendations of a meeting since
+// it last changed, that is whether its timeline holds a view by them after the latest
+// entry of another kind
+func (s *MeetingServiceImpl) viewedSinceChange(meetingID, viewerID string) bool {
+	timeline, err := s.repository.GetTimeline(meetingID)
+	if err != nil {
+		return false
 	}
+	for i := len(timeline) - 1; i >= 0 && timeline[i].Type == models.TimelineRecommendationViewed; i-- {
+		if timeline[i].UserID == viewerID {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAvailabilityGrid returns the recommended slots of a meeting with how each of its
+// organizer and participants answered for them
+func (s *MeetingServiceImpl) GetAvailabilityGrid(meetingID string) (models.AvailabilityGrid, error) {
+	meeting, err := s.repository.GetMeetingByID(meetingID)
+	if err != nil {
+		return models.AvailabilityGrid{}, err
+	}
+	set, err := s.GetRecommendations(context.Background(), meetingID, "")
+	if err != nil {
+		return models.AvailabilityGrid{}, err
+	}
+	availabilities, err := s.repository.GetMeetingAvailabilities(meetingID)
+	if err != nil {
+		return models.AvailabilityGrid{}, err
+	}
+	responded := make(map[string]bool, len(availabilities))
+	tentative := make(map[string]bool, len(availabilities))
+	for _, availability := range availabilities {
+		responded[availability.ParticipantID] = true
+		tentative[availability.ParticipantID] = tentative[availability.ParticipantID] || availability.Tentative
+	}
+
+	grid := models.AvailabilityGrid{
+		MeetingID:    meeting.ID,
+		Title:        meeting.Title,
+		TimeZone:     meeting.TimeZone,
+		Participants: meeting.Participants,
+		Rows:         make([]models.AvailabilityRow, 0, len(set.Slots)),
+		ComputedAt:   set.ComputedAt,
+	}
+	if meeting.Organizer != nil {
+		grid.Participants = append([]models.User{*meeting.Organizer}, meeting.Participants...)
+	}
+	for _, slot := range set.Slots {
+		unavailable := make(map[string]bool, len(slot.UnavailableParticipants))
+		for _, user := range slot.UnavailableParticipants {
+			unavailable[user.ID] = true
+		}
+		row := models.AvailabilityRow{Slot: slot, Marks: make([]models.AvailabilityMark, len(grid.Participants))}
+		for i, participant := range grid.Participants {
+			switch {
+			case !responded[participant.ID]:
+				row.Marks[i] = models.AvailabilityMarkNoResponse
+			case unavailable[participant.ID]:
+				row.Marks[i] = models.AvailabilityMarkUnavailable
+			case tentative[participant.ID]:
+				row.Marks[i] = models.AvailabilityMarkTentative
+			default:
+				row.Marks[i] = models.AvailabilityMarkAvailable
+			}
+		}
+		grid.Rows = append(grid.Rows, row)
+	}
+	return grid, nil
+}
+
+// GetMeetingSummary returns a meeting with its recommendations and which of its
+// participants responded, for its read-only summary page
+func (s *MeetingServiceImpl) GetMeetingSummary(meetingID string) (models.MeetingSummary, error) {
+	grid, err := s.GetAvailabilityGrid(meetingID)
+	if err != nil {
+		return models.MeetingSummary{}, err
+	}
+	meeting, err := s.repository.GetMeetingByID(meetingID)
+	if err != nil {
+		return models.MeetingSummary{}, err
+	}
+	availabilities, err := s.repository.GetMeetingAvailabilities(meetingID)
+	if err != nil {
+		return models.MeetingSummary{}, err
+	}
+	byParticipant := make(map[string]models.Availability, len(availabilities))
+	for _, availability := range availabilities {
+		byParticipant[availability.ParticipantID] = availability
+	}
+
+	summary := models.MeetingSummary{
+		Meeting:   meeting,
+		Grid:      grid,
+		Responses: make([]models.ParticipantResponse, 0, len(grid.Participants)),
+	}
+	now := time.Now()
+	for _, participant := range grid.Participants {
+		response := models.ParticipantResponse{Participant: participant}
+		if availability, ok := byParticipant[participant.ID]; ok {
+			response.Responded = true
+			response.Tentative = availability.Tentative
+			response.Stale = availability.IsStale(meeting.AvailabilityTTL, now)
+			response.ConfirmedAt = availability.ConfirmedAt
+		}
+		summary.Responses = append(summary.Responses, response)
+	}
+	return summary, nil
+}
+
+// GetSplitRecommendations suggests pairs of sessions that together reach every
+// participant when no single slot does
+func (s *MeetingServiceImpl) GetSplitRecommendations(meetingID string) ([]models.SplitRecommendation, error) {
+	meeting, err := s.repository.GetMeetingByID(meetingID)
+	if err != nil {
+		return nil, err
+	}
+	set, err := s.currentRecommendations(context.Background(), meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	return suggestSplits(meeting, set.Slots), nil
+}
+
+// SimulateRecommendations computes the recommendations a meeting would get under a
+// scenario of hypothetical changes and compares them with the current ones. Nothing is stored.
+func (s *MeetingServiceImpl) SimulateRecommendations(meetingID string, scenario models.RecommendationScenario) (models.RecommendationSimulation, error) {
+	meeting, err := s.repository.GetMeetingByID(meetingID)
+	if err != nil {
+		return models.RecommendationSimulation{}, err
+	}
+	current, err := s.currentRecommendations(context.Background(), meetingID)
+	if err != nil {
+		return models.RecommendationSimulation{}, err
+	}
+
+	hypothetical, err := applyScenario(meeting, scenario)
+	if err != nil {
+		return models.RecommendationSimulation{}, err
+	}
+	availabilities, err := s.repository.GetMeetingAvailabilities(meetingID)
+	if err != nil {
+		return models.RecommendationSimulation{}, err
+	}
+	simulated, err := s.calculateRecommendations(context.Background(), hypothetical, availabilities)
+	if err != nil {
+		return models.RecommendationSimulation{}, err
+	}
+
+	return models.RecommendationSimulation{
+		MeetingID: meetingID,
+		Scenario:  scenario,
+		Current:   current.Slots,
+		Simulated: simulated,
+		Changes:   diffRecommendations(current.Slots, simulated),
+	}, nil
+}
+
+// applyScenario returns a copy of meeting with the hypothetical changes of scenario applied
+func applyScenario(meeting models.Meeting, scenario models.RecommendationScenario) (models.Meeting, error) {
+	if scenario.ExtendWindow < 0 {
+		return models.Meeting{}, errors.NewValidationError("Window extension cannot be negative", "")
+	}
+	if scenario.Duration < 0 {
+		return models.Meeting{}, errors.NewValidationError("Duration cannot be negative", "")
+	}
+
+	if len(scenario.RemoveParticipantIDs) > 0 {
+		remove := make(map[string]bool, len(scenario.RemoveParticipantIDs))
+		for _, id := range scenario.RemoveParticipantIDs {
+			if id == meeting.OrganizerID {
+				return models.Meeting{}, errors.NewValidationError("The organizer cannot be removed", "")
+			}
+			remove[id] = true
+		}
+
+		participants := make([]models.User, 0, len(meeting.Participants))
+		for _, participant := range meeting.Participants {
+			if remove[participant.ID] {
+				delete(remove, participant.ID)
+				continue
+			}
+			participants = append(participants, participant)
+		}
+		for id := range remove {
+			return models.Meeting{}, errors.NewValidationError("Not a participant of the meeting", id)
+		}
+		meeting.Participants = participants
+	}
+
+	if scenario.ExtendWindow > 0 && len(meeting.ProposedSlots) > 0 {
+		slots := make([]models.TimeSlot, len(meeting.ProposedSlots))
+		copy(slots, meeting.ProposedSlots)
+		latest := 0
+		for i, slot := range slots {
+			if slot.EndTime.After(slots[latest].EndTime) {
+				latest = i
+			}
+		}
+		slots[latest].EndTime = slots[latest].EndTime.Add(time.Duration(scenario.ExtendWindow) * time.Minute)
+		if err := validateProposedSlots(slots); err != nil {
+			return models.Meeting{}, err
+		}
+		meeting.ProposedSlots = slots
+	}
+
+	if scenario.Duration > 0 {
+		if err := validateDurationRange(scenario.Duration, meeting.MaxDuration); err != nil {
+			return models.Meeting{}, err
+		}
+		meeting.EstimatedDuration = scenario.Duration
+	}
+	return meeting, nil
+}
+
+// WaitForRecommendationChange blocks until the recommendations of a meeting differ from
+// previous, the timeout elapses or ctx is done, and returns the latest recommendations
+func (s *MeetingServiceImpl) WaitForRecommendationChange(ctx context.Context, meetingID string, previous models.RecommendationSet, timeout time.Duration) (models.RecommendationSet, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	// Workers and other replicas change storage from other processes, without waking up
+	// readers here
+	var poll <-chan time.Time
+	if s.precompute != nil || s.sharedStorage {
+		ticker := time.NewTicker(storagePollInterval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+
+	for {
+		// Subscribe before reading so a change in between is not missed
+		changed := s.changes.wait(meetingID)
+		current, err := s.currentRecommendations(ctx, meetingID)
+		if err != nil && ctx.Err() != nil {
+			// The caller stopped waiting while the recommendations were computed
+			return previous, nil
+		}
+		if err != nil {
+			return models.RecommendationSet{}, err
+		}
+		if !sameRecommendations(previous.Slots, current.Slots) {
+			return current, nil
+		}
+
+		select {
+		case <-changed:
+		case <-poll:
+		case <-timer.C:
+			return current, nil
+		case <-ctx.Done():
+			return current, nil
+		}
+	}
+}
 
-	return s.repository.CreateMeeting(meeting)
+// currentRecommendations returns the up-to-date recommendations of a meeting, computing
+// them within the deadline of ctx when they are not stored
+func (s *MeetingServiceImpl) currentRecommendations(ctx context.Context, meetingID string) (models.RecommendationSet, error) {
+	meeting, err := s.repository.GetMeetingByID(meetingID)
+	if err != nil {
+		return models.RecommendationSet{}, err
+	}
+
+	if s.materializeRecommendations {
+		if set, err := s.repository.GetRecommendations(meetingID); err == nil {
+			return set, nil
+		}
+		if s.readOnly {
+			return s.computeRecommendations(ctx, meeting)
+		}
+		return s.materializeRecommendationSet(ctx, meeting)
+	}
+	return s.computeRecommendations(ctx, meeting)
+}
+
+// sameRecommendations reports whether two recommendation lists rank the same slots
+// with the same availability, ignoring the order of equally ranked slots
+func sameRecommendations(a, b []models.RecommendedSlot) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	fingerprint := func(slot models.RecommendedSlot) string {
+		unavailable := make([]string, 0, len(slot.UnavailableParticipants))
+		for _, user := range slot.UnavailableParticipants {
+			unavailable = append(unavailable, user.ID)
+		}
+		sort.Strings(unavailable)
+		return fmt.Sprintf("%s|%s|%s|%d/%d|%d|%d|%s", slot.TimeSlot.ID, slot.TimeSlot.StartTime, slot.TimeSlot.EndTime,
+			slot.AvailableCount, slot.TotalParticipants, slot.TentativeCount, slot.LongestFeasibleDuration, strings.Join(unavailable, ","))
+	}
+
+	counts := make(map[string]int, len(a))
+	for _, slot := range a {
+		counts[fingerprint(slot)]++
+	}
+	for _, slot := range b {
+		key := fingerprint(slot)
+		if counts[key] == 0 {
+			return false
+		}
+		counts[key]--
+	}
+	return true
+}
+
+// computeRecommendations calculates the current recommendations of a meeting
+func (s *MeetingServiceImpl) computeRecommendations(ctx context.Context, meeting models.Meeting) (models.RecommendationSet, error) {
+	availabilities, err := s.repository.GetMeetingAvailabilities(meeting.ID)
+	if err != nil {
+		return models.RecommendationSet{}, err
+	}
+
+	slots, err := s.calculateRecommendations(ctx, meeting, availabilities)
+	if err != nil {
+		return models.RecommendationSet{}, err
+	}
+
+	return models.RecommendationSet{
+		MeetingID:  meeting.ID,
+		Slots:      slots,
+		ComputedAt: time.Now(),
+	}, nil
+}
+
+// materializeRecommendationSet computes and stores the recommendations of a meeting
+func (s *MeetingServiceImpl) materializeRecommendationSet(ctx context.Context, meeting models.Meeting) (models.RecommendationSet, error) {
+	set, err := s.computeRecommendations(ctx, meeting)
+	if err != nil {
+		return models.RecommendationSet{}, err
+	}
+	if err := s.repository.SaveRecommendations(set); err != nil {
+		return models.RecommendationSet{}, err
+	}
+	return set, nil
+}
+
+// refreshRecommendations recomputes the stored recommendations after a meeting or its
+// availability changed and wakes up waiting readers; failures are logged and never fail the request.
+// With a precompute queue, a worker recomputes them instead, unless the job cannot be queued.
+func (s *MeetingServiceImpl) refreshRecommendations(meetingID string) {
+	defer s.changes.notify(meetingID)
+	if !s.materializeRecommendations {
+		return
+	}
+	if s.precompute != nil {
+		err := s.precompute.Enqueue(meetingID)
+		if err == nil {
+			return
+		}
+		logs.Warn("Failed to queue recommendations of meeting %s, computing them here: %v", meetingID, err)
+	}
+
+	meeting, err := s.repository.GetMeetingByID(meetingID)
+	if err == nil {
+		_, err = s.materializeRecommendationSet(context.Background(), meeting)
+	}
+	if err != nil {
+		logs.Warn("Failed to refresh recommendations for meeting %s: %v", meetingID, err)
+	}
+}
+
+// UpdateMeeting updates an existing meeting. Empty fields of the input are
+// left unchanged; the organizer and draft flag cannot be changed. New proposed
+// slots are normalized as in CreateMeeting, and hints are returned for the
+// updated meeting.
+func (s *MeetingServiceImpl) UpdateMeeting(meetingID string, input models.MeetingInput) (models.Meeting, []models.Warning, error) {
+	// Get existing meeting
+	meeting, err := s.repository.GetMeetingByID(meetingID)
+	if err != nil {
+		return models.Meeting{}, nil, err
+	}
+
+	// Update fields if provided
+	title, estimatedDuration, proposedSlots, participantIDs := sanitize.SingleLine(input.Title), input.EstimatedDuration, input.ProposedSlots, input.ParticipantIDs
+	if title != "" {
+		if err := sanitize.CheckLength("Title", title, s.textLimits.Title); err != nil {
+			return models.Meeting{}, nil, err
+		}
+		meeting.Title = title
+	}
+	if estimatedDuration > 0 {
+		meeting.EstimatedDuration = estimatedDuration
+	}
+	if input.MaxDuration > 0 {
+		meeting.MaxDuration = input.MaxDuration
+	}
+	if err := validateDurationRange(meeting.EstimatedDuration, meeting.MaxDuration); err != nil {
+		return models.Meeting{}, nil, err
+	}
+	if input.TimeZone != "" {
+		meeting.TimeZone = input.TimeZone
+	}
+	if input.OverrideFocusTime != nil {
+		meeting.OverrideFocusTime = *input.OverrideFocusTime
+	}
+	if input.AvailabilityTTL < 0 {
+		return models.Meeting{}, nil, errors.NewValidationError("Availability TTL must be positive", "")
+	}
+	if input.AvailabilityTTL > 0 {
+		meeting.AvailabilityTTL = input.AvailabilityTTL
+	}
+	if input.Tags != nil {
+		if meeting.Tags, err = normalizeTags(input.Tags); err != nil {
+			return models.Meeting{}, nil, err
+		}
+	}
+	if input.Priority != "" {
+		if !input.Priority.Valid() {
+			return models.Meeting{}, nil, errors.NewValidationError("Invalid priority", "use low, normal, high or urgent")
+		}
+		meeting.Priority = input.Priority
+	}
+	if input.Metadata != nil {
+		if err := validateMetadata(input.Metadata); err != nil {
+			return models.Meeting{}, nil, err
+		}
+		meeting.Metadata = input.Metadata
+		if len(input.Metadata) == 0 {
+			meeting.Metadata = nil
+		}
+	}
+	if !input.ExternalUpdatedAt.IsZero() {
+		meeting.ExternalUpdatedAt = &input.ExternalUpdatedAt
+	}
+	location, err := loadTimeZone(meeting.TimeZone)
+	if err != nil {
+		return models.Meeting{}, nil, err
+	}
+	rescheduled := false
+	var addedSlots []models.TimeSlot
+	var warnings []models.Warning
+	if len(proposedSlots) > 0 {
+		proposedSlots, warnings = normalizeSlots(proposedSlots, input.CoalesceAdjacentSlots)
+		if err := validateProposedSlots(proposedSlots); err != nil {
+			return models.Meeting{}, nil, err
+		}
+		warnings = append(warnings, dstWarnings(proposedSlots, location)...)
+		rescheduled = slotsChanged(meeting.ProposedSlots, proposedSlots)
+
+		assignSlotIDs(meeting.ProposedSlots, proposedSlots)
+		addedSlots = newSlots(meeting.ProposedSlots, proposedSlots)
+		meeting.ProposedSlots = proposedSlots
+	} else if input.TimeZone != "" {
+		warnings = dstWarnings(meeting.ProposedSlots, location)
+	}
+	var addedParticipants []models.User
+	if len(participantIDs) > 0 {
+		// Validate and update participants
+		existing := make(map[string]bool, len(meeting.Participants))
+		for _, participant := range meeting.Participants {
+			existing[participant.ID] = true
+		}
+
+		if err := s.quotas.CheckParticipants(len(participantIDs)); err != nil {
+			return models.Meeting{}, nil, err
+		}
+		participants, err := s.lookupParticipants(participantIDs)
+		if err != nil {
+			return models.Meeting{}, nil, err
+		}
+		for _, participant := range participants {
+			if !existing[participant.ID] {
+				addedParticipants = append(addedParticipants, participant)
+			}
+		}
+		meeting.Participants = participants
+	}
+
+	updatedMeeting, err := s.repository.UpdateMeeting(meeting)
+	if err != nil {
+		return models.Meeting{}, nil, err
+	}
+
+	if rescheduled {
+		s.carryOverAvailability(updatedMeeting)
+	}
+	s.refreshRecommendations(meetingID)
+	for _, participant := range addedParticipants {
+		s.recordTimeline(meetingID, models.TimelineParticipantAdded, participant.ID, participant.Name+" was added as a participant")
+	}
+	if updatedMeeting.Status == models.MeetingStatusPending {
+		s.sendInvitations(updatedMeeting, addedParticipants, updatedMeeting.ProposedSlots, false)
+		if len(addedSlots) > 0 {
+			// Participants who were already invited are only asked about the new slots
+			s.sendInvitations(updatedMeeting, invitedBefore(updatedMeeting.Participants, addedParticipants), addedSlots, true)
+		}
+	}
+	if rescheduled {
+		s.sloTracker.RecordReschedule()
+		s.recordTimeline(meetingID, models.TimelineMeetingRescheduled, "", "Proposed time slots were changed")
+	}
+	warnings = append(warnings, s.schedulingHints(updatedMeeting, location)...)
+	return updatedMeeting, warnings, nil
 }
 
-// GetRecommendations gets meeting time recommendations based on participant availability
-func (s *MeetingServiceImpl) GetRecommendations(meetingID string) ([]models.RecommendedSlot, error) {
-	// Get meeting
-	meeting, err := s.repository.GetMeetingByID(meetingID)
-	if err != nil {
-		return nil, err
+// AddMeetingTags adds tags to a meeting, keeping the ones it already carries
+func (s *MeetingServiceImpl) AddMeetingTags(meetingID string, tags []string) (models.Meeting, error) {
+	if len(tags) == 0 {
+		return models.Meeting{}, errors.NewValidationError("At least one tag is required", "")
 	}
 
-	// Get availabilities
-	availabilities, err := s.repository.GetMeetingAvailabilities(meetingID)
+	meeting, err := s.repository.GetMeetingByID(meetingID)
 	if err != nil {
-		return nil, err
+		return models.Meeting{}, err
 	}
-
-	return s.calculateRecommendations(meeting, availabilities), nil
+	if meeting.Tags, err = normalizeTags(append(meeting.Tags, tags...)); err != nil {
+		return models.Meeting{}, err
+	}
+	return s.repository.UpdateMeeting(meeting)
 }
 
-// UpdateMeeting updates an existing meeting
-func (s *MeetingServiceImpl) UpdateMeeting(meetingID string, title string, estimatedDuration int, proposedSlots []models.TimeSlot, participantIDs []string) (models.Meeting, error) {
-	// Get existing meeting
+// RemoveMeetingTag removes a tag from a meeting
+func (s *MeetingServiceImpl) RemoveMeetingTag(meetingID string, tag string) (models.Meeting, error) {
 	meeting, err := s.repository.GetMeetingByID(meetingID)
 	if err != nil {
 		return models.Meeting{}, err
 	}
 
-	// Update fields if provided
-	if title != "" {
-		meeting.Title = title
-	}
-	if estimatedDuration > 0 {
-		meeting.EstimatedDuration = estimatedDuration
-	}
-	if len(proposedSlots) > 0 {
-		// Assign IDs to new time slots
-		for i := range proposedSlots {
-			if proposedSlots[i].ID == "" {
-				proposedSlots[i].ID = uuid.New().String()
-			}
+	tag = strings.ToLower(sanitize.SingleLine(tag))
+	remaining := make([]string, 0, len(meeting.Tags))
+	for _, meetingTag := range meeting.Tags {
+		if meetingTag != tag {
+			remaining = append(remaining, meetingTag)
 		}
-		meeting.ProposedSlots = proposedSlots
 	}
-	if len(participantIDs) > 0 {
-		// Validate and update participants
-		var participants []models.User
-		for _, participantID := range participantIDs {
-			participant, err := s.userService.GetUserByID(participantID)
-			if err != nil {
-				return models.Meeting{}, errors.NewNotFoundError("Participant not found: " + participantID)
-			}
-			participants = append(participants, participant)
-		}
-		meeting.Participants = participants
+	if len(remaining) == len(meeting.Tags) {
+		return models.Meeting{}, errors.NewNotFoundError("Tag not found")
 	}
-
+	meeting.Tags = remaining
 	return s.repository.UpdateMeeting(meeting)
 }
 
+// GetTagStats summarizes meetings and participant responses by tag
+func (s *MeetingServiceImpl) GetTagStats() ([]models.TagStats, error) {
+	meetings, err := s.repository.ListMeetings(nil, math.MaxInt, models.MeetingFilter{})
+	if err != nil {
+		return nil, err
+	}
+	return tagStats(meetings, s.repository.GetAllAvailabilities()), nil
+}
+
 // DeleteMeeting deletes a meeting
 func (s *MeetingServiceImpl) DeleteMeeting(meetingID string) error {
-	return s.repository.DeleteMeeting(meetingID)
+	if err := s.repository.DeleteMeeting(meetingID); err != nil {
+		return err
+	}
+
+	s.changes.notify(meetingID)
+	s.publish(events.MeetingDeleted, meetingID, nil)
+	return nil
 }
 
 // AddAvailability adds a participant's availability for a meeting
-func (s *MeetingServiceImpl) AddAvailability(userID string, meetingID string, availableSlots []models.TimeSlot) (models.Availability, error) {
+func (s *MeetingServiceImpl) AddAvailability(userID string, meetingID string, availableSlots []models.TimeSlot, tentative bool) (models.Availability, error) {
 	// Validate user exists
 	user, err := s.userService.GetUserByID(userID)
 	if err != nil {
 		return models.Availability{}, err
 	}
+	if !user.Active() {
+		return models.Availability{}, errors.NewValidationError("User is deactivated", "")
+	}
 
 	// Validate meeting exists
 	meeting, err := s.repository.GetMeetingByID(meetingID)
 	if err != nil {
 		return models.Availability{}, err
 	}
+	if err := checkCollectingAvailability(meeting); err != nil {
+		return models.Availability{}, err
+	}
 
-	// Match available slots with proposed slots
 	var matchedSlots []models.TimeSlot
 	for _, availableSlot := range availableSlots {
-		matched := false
-		for _, proposedSlot := range meeting.ProposedSlots {
-			if availableSlot.StartTime.Equal(proposedSlot.StartTime) &&
-				availableSlot.EndTime.Equal(proposedSlot.EndTime) {
-				matchedSlots = append(matchedSlots, proposedSlot)
-				matched = true
-				break
-			}
-		}
+		matchedSlot, matched := s.matchProposedSlot(meeting, availableSlot)
 		if !matched {
 			return models.Availability{}, errors.NewValidationError("Available slot does not match any proposed slot", "")
 		}
+		matchedSlots = append(matchedSlots, matchedSlot)
 	}
 
 	// Create availability
@@ -169,130 +1181,583 @@ func (s *MeetingServiceImpl) AddAvailability(userID string, meetingID string, av
 		Participant:    &user,
 		MeetingID:      meetingID,
 		AvailableSlots: matchedSlots,
+		Tentative:      tentative,
+		ConfirmedAt:    time.Now(),
+	}
+
+	createdAvailability, err := s.repository.CreateAvailability(availability)
+	if err != nil {
+		return models.Availability{}, err
+	}
+
+	s.sloTracker.RecordAvailabilityResponse(createdAvailability.CreatedAt.Sub(meeting.CreatedAt))
+	s.refreshRecommendations(meetingID)
+	s.publish(events.AvailabilityAdded, meetingID, createdAvailability)
+	s.recordTimeline(meetingID, models.TimelineAvailabilitySubmitted, userID, user.Name+" submitted availability")
+	return createdAvailability, nil
+}
+
+// matchProposedSlot returns the proposed slot of meeting an available slot is for. When
+// windows are split, part of a window that still fits the meeting can be submitted as well.
+func (s *MeetingServiceImpl) matchProposedSlot(meeting models.Meeting, availableSlot models.TimeSlot) (models.TimeSlot, bool) {
+	duration := time.Duration(meeting.EstimatedDuration) * time.Minute
+	for _, proposedSlot := range meeting.ProposedSlots {
+		if availableSlot.Fingerprint() == proposedSlot.Fingerprint() {
+			return proposedSlot, true
+		}
+		if s.slotGranularity > 0 && covers(proposedSlot, availableSlot) &&
+			availableSlot.EndTime.Sub(availableSlot.StartTime) >= duration {
+			return models.TimeSlot{StartTime: availableSlot.StartTime, EndTime: availableSlot.EndTime}, true
+		}
+	}
+	return models.TimeSlot{}, false
+}
+
+// carryOverAvailability keeps the availability submitted for slots a rescheduled meeting
+// still proposes, recognizing them by their times as parts of split windows have no
+// IDs, and drops the rest. Responses left without slots are deleted, so their
+// participants are counted as not having answered. Failures are logged and never fail
+// the request.
+func (s *MeetingServiceImpl) carryOverAvailability(meeting models.Meeting) {
+	availabilities, err := s.repository.GetMeetingAvailabilities(meeting.ID)
+	if err != nil {
+		logs.Warn("Failed to carry over availability of meeting %s: %v", meeting.ID, err)
+		return
 	}
 
-	return s.repository.CreateAvailability(availability)
+	for _, availability := range availabilities {
+		var kept []models.TimeSlot
+		changed := false
+		for _, availableSlot := range availability.AvailableSlots {
+			matchedSlot, matched := s.matchProposedSlot(meeting, availableSlot)
+			if matched {
+				kept = append(kept, matchedSlot)
+			}
+			changed = changed || !matched || matchedSlot.ID != availableSlot.ID
+		}
+		if !changed {
+			continue
+		}
+
+		if len(kept) == 0 {
+			err = s.repository.DeleteAvailability(availability.ID)
+		} else {
+			availability.AvailableSlots = kept
+			availability.UpdatedAt = time.Now()
+			_, err = s.repository.UpdateAvailability(availability)
+		}
+		if err != nil {
+			logs.Warn("Failed to carry over availability %s of meeting %s: %v", availability.ID, meeting.ID, err)
+		}
+	}
 }
 
 // UpdateAvailability updates a participant's availability
-func (s *MeetingServiceImpl) UpdateAvailability(availabilityID string, availableSlots []models.TimeSlot) (models.Availability, error) {
+func (s *MeetingServiceImpl) UpdateAvailability(availabilityID string, availableSlots []models.TimeSlot, tentative bool) (models.Availability, error) {
 	if len(availableSlots) == 0 {
 		return models.Availability{}, errors.NewValidationError("At least one available time slot is required", "")
 	}
 
 	// Find the availability
-	availabilities := s.repository.GetAllAvailabilities()
-	var availability models.Availability
-	var found bool
-	for _, a := range availabilities {
-		if a.ID == availabilityID {
-			availability = a
-			found = true
-			break
-		}
+	availability, err := s.repository.GetAvailabilityByID(availabilityID)
+	if err != nil {
+		return models.Availability{}, err
+	}
+	meeting, err := s.repository.GetMeetingByID(availability.MeetingID)
+	if err != nil {
+		return models.Availability{}, err
 	}
-	if !found {
-		return models.Availability{}, errors.NewNotFoundError("Availability not found")
+	if err := checkCollectingAvailability(meeting); err != nil {
+		return models.Availability{}, err
 	}
 
 	// Update availability
 	availability.AvailableSlots = availableSlots
+	availability.Tentative = tentative
 	availability.UpdatedAt = time.Now()
+	availability.ConfirmedAt = availability.UpdatedAt
+
+	updatedAvailability, err := s.repository.UpdateAvailability(availability)
+	if err != nil {
+		return models.Availability{}, err
+	}
 
-	return s.repository.UpdateAvailability(availability)
+	s.refreshRecommendations(updatedAvailability.MeetingID)
+	s.recordTimeline(updatedAvailability.MeetingID, models.TimelineAvailabilitySubmitted, updatedAvailability.ParticipantID, "Availability was updated")
+	return updatedAvailability, nil
 }
 
 // DeleteAvailability deletes a participant's availability
 func (s *MeetingServiceImpl) DeleteAvailability(availabilityID string) error {
-	return s.repository.DeleteAvailability(availabilityID)
+	availability, err := s.repository.GetAvailabilityByID(availabilityID)
+	if err != nil {
+		return err
+	}
+	if err := s.repository.DeleteAvailability(availabilityID); err != nil {
+		return err
+	}
+
+	s.refreshRecommendations(availability.MeetingID)
+	return nil
 }
 
-// GetAvailability gets a participant's availability for a meeting
-func (s *MeetingServiceImpl) GetAvailability(userID string, meetingID string) (models.Availability, error) {
-	return s.repository.GetAvailability(userID, meetingID)
+// ConfirmAvailability reconfirms a participant's availability as it is, clearing its staleness
+func (s *MeetingServiceImpl) ConfirmAvailability(availabilityID string) (models.Availability, error) {
+	availability, err := s.repository.GetAvailabilityByID(availabilityID)
+	if err != nil {
+		return models.Availability{}, err
+	}
+	meeting, err := s.repository.GetMeetingByID(availability.MeetingID)
+	if err != nil {
+		return models.Availability{}, err
+	}
+	if err := checkCollectingAvailability(meeting); err != nil {
+		return models.Availability{}, err
+	}
+
+	availability.ConfirmedAt = time.Now()
+	confirmedAvailability, err := s.repository.UpdateAvailability(availability)
+	if err != nil {
+		return models.Availability{}, err
+	}
+
+	s.recordTimeline(confirmedAvailability.MeetingID, models.TimelineAvailabilitySubmitted, confirmedAvailability.ParticipantID, "Availability was reconfirmed")
+	return confirmedAvailability, nil
 }
 
-// calculateRecommendations calculates recommended time slots based on participant availability
-func (s *MeetingServiceImpl) calculateRecommendations(meeting models.Meeting, availabilities []models.Availability) []models.RecommendedSlot {
-	// Map to track the number of participants available for each proposed slot
-	slotAvailability := make(map[string]int)
-	slotMap := make(map[string]models.TimeSlot)
-	unavailableParticipants := make(map[string][]models.User)
+// RequestReconfirmation asks every participant whose availability for a meeting went stale
+// to reconfirm it and returns the stale availabilities
+func (s *MeetingServiceImpl) RequestReconfirmation(meetingID string) ([]models.Availability, error) {
+	meeting, err := s.repository.GetMeetingByID(meetingID)
+	if err != nil {
+		return nil, err
+	}
+	if meeting.AvailabilityTTL == 0 {
+		return nil, errors.NewValidationError("Meeting has no availability TTL", "Set availabilityTtl to let responses go stale")
+	}
 
-	// Initialize the maps with proposed slots
-	for _, slot := range meeting.ProposedSlots {
-		slotAvailability[slot.ID] = 0
-		slotMap[slot.ID] = slot
-		unavailableParticipants[slot.ID] = []models.User{}
+	availabilities, err := s.repository.GetMeetingAvailabilities(meetingID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Track which participants are available for each slot
-	participantAvailability := make(map[string]map[string]bool) // participantID -> slotID -> available
-	allParticipants := append([]models.User{*meeting.Organizer}, meeting.Participants...)
-	for _, participant := range allParticipants {
-		participantAvailability[participant.ID] = make(map[string]bool)
-		for _, slot := range meeting.ProposedSlots {
-			participantAvailability[participant.ID][slot.ID] = false
+	now := time.Now()
+	stale := []models.Availability{}
+	for _, availability := range availabilities {
+		if availability.IsStale(meeting.AvailabilityTTL, now) {
+			availability.Stale = true
+			stale = append(stale, availability)
 		}
 	}
 
-	// Process each availability entry
-	for _, availability := range availabilities {
-		// Skip if not a participant or organizer
-		isValid := false
-		if availability.ParticipantID == meeting.OrganizerID {
-			isValid = true
-		} else {
-			for _, participant := range meeting.Participants {
-				if participant.ID == availability.ParticipantID {
-					isValid = true
-					break
-				}
+	s.sendReconfirmationRequests(meeting, stale)
+	return stale, nil
+}
+
+// RespondToInvitation records the answer of a signed RSVP link: the participant it was
+// sent to is free for its slot. The slot is added to existing availability.
+func (s *MeetingServiceImpl) RespondToInvitation(token string) (models.Availability, error) {
+	if s.rsvpSigner == nil {
+		return models.Availability{}, errors.NewNotFoundError("RSVP links are disabled")
+	}
+
+	var availability models.Availability
+	err := s.rsvpSigner.Redeem(token, func(link rsvp.Link) error {
+		meeting, err := s.repository.GetMeetingByID(link.MeetingID)
+		if err != nil {
+			return err
+		}
+		var slot models.TimeSlot
+		for _, proposed := range meeting.ProposedSlots {
+			if proposed.ID == link.SlotID {
+				slot = proposed
 			}
 		}
-		if !isValid {
-			continue
+		if slot.ID == "" {
+			return errors.NewValidationError("Slot is no longer proposed", "The meeting was rescheduled; submit availability for the new times")
 		}
 
-		// For each available slot in the availability entry
-		for _, availableSlot := range availability.AvailableSlots {
-			// Match with proposed slots
-			for _, proposedSlot := range meeting.ProposedSlots {
-				if availableSlot.ID == proposedSlot.ID {
-					slotAvailability[proposedSlot.ID]++
-					participantAvailability[availability.ParticipantID][proposedSlot.ID] = true
-				}
+		existing, err := s.repository.GetAvailability(link.UserID, link.MeetingID)
+		if errors.Is(err, errors.ErrNotFound) {
+			availability, err = s.AddAvailability(link.UserID, link.MeetingID, []models.TimeSlot{slot}, false)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		for _, available := range existing.AvailableSlots {
+			if available.StartTime.Equal(slot.StartTime) && available.EndTime.Equal(slot.EndTime) {
+				availability = existing
+				return nil
 			}
 		}
+		availability, err = s.UpdateAvailability(existing.ID, append(existing.AvailableSlots, slot), existing.Tentative)
+		return err
+	})
+	if err != nil {
+		return models.Availability{}, err
 	}
+	return availability, nil
+}
 
-	// Build unavailable participants list for each slot
-	for _, participant := range allParticipants {
-		for slotID := range slotMap {
-			if !participantAvailability[participant.ID][slotID] {
-				unavailableParticipants[slotID] = append(unavailableParticipants[slotID], participant)
-			}
+// GetPollLink returns the public link participants follow to submit availability for a
+// meeting
+func (s *MeetingServiceImpl) GetPollLink(meetingID string) (string, error) {
+	if s.pollURL == "" {
+		return "", errors.NewNotFoundError("Poll links are disabled")
+	}
+	meeting, err := s.repository.GetMeetingByID(meetingID)
+	if err != nil {
+		return "", err
+	}
+	if meeting.Status == models.MeetingStatusDraft {
+		return "", errors.NewValidationError("Draft meetings have no poll", "Publish the meeting first")
+	}
+	return strings.ReplaceAll(s.pollURL, "{id}", url.PathEscape(meeting.ID)), nil
+}
+
+// GetAvailability gets a participant's availability for a meeting, flagging it when stale
+func (s *MeetingServiceImpl) GetAvailability(userID string, meetingID string) (models.Availability, error) {
+	availability, err := s.repository.GetAvailability(userID, meetingID)
+	if err != nil {
+		return models.Availability{}, err
+	}
+
+	meeting, err := s.repository.GetMeetingByID(meetingID)
+	if err != nil {
+		return models.Availability{}, err
+	}
+	availability.Stale = availability.IsStale(meeting.AvailabilityTTL, time.Now())
+	return availability, nil
+}
+
+// GetMeetingTimeline gets the ordered activity timeline of a meeting
+func (s *MeetingServiceImpl) GetMeetingTimeline(meetingID string) ([]models.TimelineEvent, error) {
+	return s.repository.GetTimeline(meetingID)
+}
+
+// recordTimeline appends an entry to a meeting's timeline; failures are logged and never fail the request
+func (s *MeetingServiceImpl) recordTimeline(meetingID string, eventType models.TimelineEventType, userID, description string) {
+	event := models.TimelineEvent{
+		MeetingID:   meetingID,
+		Type:        eventType,
+		UserID:      userID,
+		Description: description,
+	}
+	if _, err := s.repository.AddTimelineEvent(event); err != nil {
+		logs.Warn("Failed to record %s timeline event for meeting %s: %v", eventType, meetingID, err)
+	}
+}
+
+// ReassignUser moves all meetings, participations and availabilities of one user to another
+func (s *MeetingServiceImpl) ReassignUser(fromUserID string, toUserID string) (models.UserReassignment, error) {
+	target, err := s.userService.GetUserByID(toUserID)
+	if err != nil {
+		return models.UserReassignment{}, err
+	}
+
+	result, err := s.repository.ReassignUser(fromUserID, target)
+	if err != nil {
+		return models.UserReassignment{}, err
+	}
+
+	// The affected meetings are not known here, so wake up every waiting reader
+	s.changes.notifyAll()
+	return result, nil
+}
+
+// Snapshot returns a copy of the meeting store for backups
+func (s *MeetingServiceImpl) Snapshot() (models.MeetingStoreSnapshot, error) {
+	return s.repository.Snapshot()
+}
+
+// Restore replaces the meeting store with a snapshot taken from a backup
+func (s *MeetingServiceImpl) Restore(snapshot models.MeetingStoreSnapshot) error {
+	if err := s.repository.Restore(snapshot); err != nil {
+		return err
+	}
+
+	// Every meeting may have changed, so wake up every waiting reader
+	s.changes.notifyAll()
+	return nil
+}
+
+// lookupParticipants fetches participants with a single batch lookup, preserving the order of ids
+func (s *MeetingServiceImpl) lookupParticipants(participantIDs []string) ([]models.User, error) {
+	if len(participantIDs) == 0 {
+		return nil, nil
+	}
+
+	users, err := s.userService.GetUsersByIDs(participantIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]models.User, len(users))
+	for _, user := range users {
+		byID[user.ID] = user
+	}
+
+	participants := make([]models.User, 0, len(participantIDs))
+	for _, participantID := range participantIDs {
+		participant, found := byID[participantID]
+		if !found {
+			return nil, errors.NewNotFoundError("Participant not found: " + participantID)
+		}
+		participants = append(participants, participant)
+	}
+	return participants, nil
+}
+
+// validateMeetingDetails validates the fields required before a meeting can collect availability
+func validateMeetingDetails(title string, estimatedDuration int, proposedSlots []models.TimeSlot) error {
+	if title == "" {
+		return errors.NewValidationError("Title is required", "")
+	}
+	if estimatedDuration <= 0 {
+		return errors.NewValidationError("Estimated duration must be positive", "")
+	}
+	if len(proposedSlots) == 0 {
+		return errors.NewValidationError("At least one proposed time slot is required", "")
+	}
+	return nil
+}
+
+// validateDurationRange checks that an optional maximum duration is not below the estimated duration
+func validateDurationRange(estimatedDuration, maxDuration int) error {
+	if maxDuration < 0 {
+		return errors.NewValidationError("Maximum duration must be positive", "")
+	}
+	if maxDuration > 0 && maxDuration < estimatedDuration {
+		return errors.NewValidationError("Maximum duration must not be shorter than the estimated duration", "")
+	}
+	return nil
+}
+
+// sendInvitations asks participants to submit availability for slots of a meeting, which
+// were added by a reschedule when rescheduled is set; failures are logged and never fail the request
+func (s *MeetingServiceImpl) sendInvitations(meeting models.Meeting, participants []models.User, slots []models.TimeSlot, rescheduled bool) {
+	organizerName := "The organizer"
+	if meeting.Organizer != nil {
+		organizerName = meeting.Organizer.Name
+	}
+
+	importance := notifications.ImportanceNormal
+	switch meeting.Priority {
+	case models.PriorityLow:
+		importance = notifications.ImportanceLow
+	case models.PriorityHigh, models.PriorityUrgent:
+		importance = notifications.ImportanceHigh
+	}
+
+	for _, participant := range participants {
+		if participant.Digest != models.DigestOff {
+			continue // listed in their next digest instead
+		}
+
+		data := notifications.InvitationData{
+			Recipient:   participant.Name,
+			Organizer:   organizerName,
+			Title:       meeting.Title,
+			Duration:    meeting.EstimatedDuration,
+			MeetingID:   meeting.ID,
+			Urgent:      meeting.Priority == models.PriorityUrgent,
+			GuestURL:    s.guestMeetingURL(meeting, participant),
+			Rescheduled: rescheduled,
+		}
+		for _, slot := range slots {
+			data.Slots = append(data.Slots, notifications.InvitationSlot{
+				Start:   slot.StartTime,
+				End:     slot.EndTime,
+				RSVPURL: s.rsvpURL(meeting.ID, participant.ID, slot.ID),
+			})
+		}
+		message, err := s.templates.Render(notifications.KindInvitation, data)
+		if err != nil {
+			logs.Warn("Failed to render invitation for meeting %s to user %s: %v", meeting.ID, participant.ID, err)
+			continue
 		}
+
+		notification := notifications.Notification{
+			Kind:       notifications.KindInvitation,
+			UserID:     participant.ID,
+			To:         participant.Email,
+			Subject:    message.Subject,
+			Body:       message.Text,
+			HTML:       message.HTML,
+			Importance: importance,
+			Urgent:     data.Urgent,
+		}
+		if err := s.notifier.Send(notification); err != nil {
+			logs.Warn("Failed to send invitation for meeting %s to user %s: %v", meeting.ID, participant.ID, err)
+		}
+	}
+}
+
+// rsvpURL returns the one-click link a participant can follow to be marked free for a
+// proposed slot, or nothing when RSVP links are disabled
+func (s *MeetingServiceImpl) rsvpURL(meetingID, participantID, slotID string) string {
+	if s.rsvpSigner == nil {
+		return ""
 	}
+	return s.rsvpBaseURL + "/api/rsvp/" + s.rsvpSigner.Sign(meetingID, participantID, slotID)
+}
 
-	// Convert to recommended slots
-	recommendations := make([]models.RecommendedSlot, 0, len(meeting.ProposedSlots))
-	totalParticipants := len(allParticipants)
+// sendFinalization tells participants of a confirmed meeting when it takes place, with
+// the calendar invitation attached. When proposedBy is set, the meeting moved to the slot
+// that participant proposed. Failures are logged and never fail the request.
+func (s *MeetingServiceImpl) sendFinalization(meeting models.Meeting, participants []models.User, proposedBy string) {
+	organizerName := "The organizer"
+	if meeting.Organizer != nil {
+		organizerName = meeting.Organizer.Name
+	}
+	var attachments []notifications.Attachment
+	if invitation, err := s.invitation(meeting); err != nil {
+		logs.Warn("Failed to create calendar invitation to meeting %s, sending finalization without it: %v", meeting.ID, err)
+	} else {
+		attachments = append(attachments, invitation)
+	}
 
-	for slotID, count := range slotAvailability {
-		slot := slotMap[slotID]
-		recommendations = append(recommendations, models.RecommendedSlot{
-			TimeSlot:                slot,
-			AvailableCount:          count,
-			TotalParticipants:       totalParticipants,
-			UnavailableParticipants: unavailableParticipants[slotID],
+	for _, participant := range participants {
+		message, err := s.templates.Render(notifications.KindFinalization, notifications.FinalizationData{
+			Recipient:   participant.Name,
+			Organizer:   organizerName,
+			Title:       meeting.Title,
+			MeetingID:   meeting.ID,
+			Start:       meeting.ConfirmedSlot.StartTime,
+			End:         meeting.ConfirmedSlot.EndTime,
+			Rescheduled: proposedBy != "",
+			ProposedBy:  proposedBy,
 		})
+		if err != nil {
+			logs.Warn("Failed to render finalization of meeting %s for user %s: %v", meeting.ID, participant.ID, err)
+			continue
+		}
+
+		notification := notifications.Notification{
+			Kind:        notifications.KindFinalization,
+			UserID:      participant.ID,
+			To:          participant.Email,
+			Subject:     message.Subject,
+			Body:        message.Text,
+			HTML:        message.HTML,
+			Attachments: attachments,
+		}
+		if err := s.notifier.Send(notification); err != nil {
+			logs.Warn("Failed to send finalization of meeting %s to user %s: %v", meeting.ID, participant.ID, err)
+		}
 	}
+}
 
-	// Sort recommendations by available count in descending order
-	sort.Slice(recommendations, func(i, j int) bool {
-		return recommendations[i].AvailableCount > recommendations[j].AvailableCount
-	})
+// sendReconfirmationRequests asks the participants of stale availabilities to reconfirm them;
+// failures are logged and never fail the request
+func (s *MeetingServiceImpl) sendReconfirmationRequests(meeting models.Meeting, stale []models.Availability) {
+	for _, availability := range stale {
+		participant, err := s.userService.GetUserByID(availability.ParticipantID)
+		if err != nil {
+			logs.Warn("Failed to look up participant %s of meeting %s: %v", availability.ParticipantID, meeting.ID, err)
+			continue
+		}
+		if participant.Digest != models.DigestOff {
+			continue // listed in their next digest instead
+		}
+
+		message, err := s.templates.Render(notifications.KindReconfirmation, notifications.ReconfirmationData{
+			Recipient:      participant.Name,
+			Title:          meeting.Title,
+			MeetingID:      meeting.ID,
+			AvailabilityID: availability.ID,
+			ConfirmedAt:    availability.ConfirmedAt,
+		})
+		if err != nil {
+			logs.Warn("Failed to render reconfirmation request for meeting %s to user %s: %v", meeting.ID, participant.ID, err)
+			continue
+		}
+
+		notification := notifications.Notification{
+			Kind:    notifications.KindReconfirmation,
+			UserID:  participant.ID,
+			To:      participant.Email,
+			Subject: message.Subject,
+			Body:    message.Text,
+			HTML:    message.HTML,
+		}
+		if err := s.notifier.Send(notification); err != nil {
+			logs.Warn("Failed to send reconfirmation request for meeting %s to user %s: %v", meeting.ID, participant.ID, err)
+		}
+	}
+}
+
+// assignSlotIDs gives proposed slots whose times are unchanged the ID they already had,
+// so availability and RSVP links referring to them stay valid, and mints IDs for the
+// others. IDs sent for slots whose times changed are replaced, since they no longer
+// identify the same slot.
+func assignSlotIDs(current, proposed []models.TimeSlot) {
+	ids := make(map[string]string, len(current))
+	for _, slot := range current {
+		ids[slot.Fingerprint()] = slot.ID
+	}
+	for i := range proposed {
+		if id, ok := ids[proposed[i].Fingerprint()]; ok && id != "" {
+			proposed[i].ID = id
+		} else {
+			proposed[i].ID = uuid.New().String()
+		}
+	}
+}
+
+// newSlots returns the proposed slots whose times are not among the current slots
+func newSlots(current, proposed []models.TimeSlot) []models.TimeSlot {
+	known := make(map[string]bool, len(current))
+	for _, slot := range current {
+		known[slot.Fingerprint()] = true
+	}
+	var added []models.TimeSlot
+	for _, slot := range proposed {
+		if !known[slot.Fingerprint()] {
+			added = append(added, slot)
+		}
+	}
+	return added
+}
+
+// invitedBefore returns the participants who are not among the added ones
+func invitedBefore(participants, added []models.User) []models.User {
+	addedIDs := make(map[string]bool, len(added))
+	for _, participant := range added {
+		addedIDs[participant.ID] = true
+	}
+	var invited []models.User
+	for _, participant := range participants {
+		if !addedIDs[participant.ID] {
+			invited = append(invited, participant)
+		}
+	}
+	return invited
+}
+
+// slotsChanged reports whether the proposed slot times differ between two slot sets
+func slotsChanged(current, proposed []models.TimeSlot) bool {
+	if len(current) != len(proposed) {
+		return true
+	}
+	for _, p := range proposed {
+		found := false
+		for _, c := range current {
+			if c.StartTime.Equal(p.StartTime) && c.EndTime.Equal(p.EndTime) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return true
+		}
+	}
+	return false
+}
 
-	return recommendations
+// publish emits a scheduling event; failures are logged and never fail the request
+func (s *MeetingServiceImpl) publish(eventType events.Type, meetingID string, data interface{}) {
+	if err := s.publisher.Publish(events.New(eventType, meetingID, data)); err != nil {
+		logs.Warn("Failed to publish %s event for meeting %s: %v", eventType, meetingID, err)
+	}
 }
@@ -1,13 +1,28 @@
 package services
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"meetsync/internal/events"
+	"meetsync/internal/ical"
+	"meetsync/internal/interfaces"
+	"meetsync/internal/metering"
+	"meetsync/internal/metrics"
 	"meetsync/internal/models"
+	"meetsync/internal/notifications"
+	"meetsync/internal/quota"
+	"meetsync/internal/repositories"
+	"meetsync/internal/rsvp"
+	"meetsync/internal/sanitize"
 	"meetsync/pkg/errors"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func setupTestMeetingService(t *testing.T) (*MeetingServiceImpl, models.User, []models.User) {
@@ -50,6 +65,16 @@ func createTestTimeSlots() []models.TimeSlot {
 	}
 }
 
+// hourlySlots returns count one-hour slots starting at start, two hours apart
+func hourlySlots(start time.Time, count int) []models.TimeSlot {
+	slots := make([]models.TimeSlot, count)
+	for i := range slots {
+		slotStart := start.Add(time.Duration(2*i) * time.Hour)
+		slots[i] = models.TimeSlot{StartTime: slotStart, EndTime: slotStart.Add(time.Hour)}
+	}
+	return slots
+}
+
 func TestMeetingService_CreateMeeting(t *testing.T) {
 	service, organizer, participants := setupTestMeetingService(t)
 	timeSlots := createTestTimeSlots()
@@ -106,17 +131,37 @@ func TestMeetingService_CreateMeeting(t *testing.T) {
 			expectError:       true,
 			errorMessage:      "At least one proposed time slot is required",
 		},
+		{
+			name:              "Slot too long to search",
+			title:             "Team Meeting",
+			organizerID:       organizer.ID,
+			estimatedDuration: 60,
+			proposedSlots:     []models.TimeSlot{{StartTime: timeSlots[0].StartTime, EndTime: timeSlots[0].StartTime.AddDate(10, 0, 0)}},
+			participantIDs:    participantIDs,
+			expectError:       true,
+			errorMessage:      "Slot is too long",
+		},
+		{
+			name:              "Too many slots to search",
+			title:             "Team Meeting",
+			organizerID:       organizer.ID,
+			estimatedDuration: 60,
+			proposedSlots:     hourlySlots(timeSlots[0].StartTime, maxProposedSlots+1),
+			participantIDs:    participantIDs,
+			expectError:       true,
+			errorMessage:      "Too many proposed slots",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			meeting, err := service.CreateMeeting(
-				tt.title,
-				tt.organizerID,
-				tt.estimatedDuration,
-				tt.proposedSlots,
-				tt.participantIDs,
-			)
+			meeting, _, err := service.CreateMeeting(models.MeetingInput{
+				Title:             tt.title,
+				OrganizerID:       tt.organizerID,
+				EstimatedDuration: tt.estimatedDuration,
+				ProposedSlots:     tt.proposedSlots,
+				ParticipantIDs:    tt.participantIDs,
+			})
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -146,13 +191,13 @@ func TestMeetingService_AddAvailability(t *testing.T) {
 		participantIDs[i] = p.ID
 	}
 
-	meeting, err := service.CreateMeeting(
-		"Test Meeting",
-		organizer.ID,
-		60,
-		timeSlots,
-		participantIDs,
-	)
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		ParticipantIDs:    participantIDs,
+	})
 	assert.NoError(t, err)
 
 	tests := []struct {
@@ -190,6 +235,7 @@ func TestMeetingService_AddAvailability(t *testing.T) {
 				tt.userID,
 				tt.meetingID,
 				tt.availableSlots,
+				false,
 			)
 
 			if tt.expectError {
@@ -220,13 +266,13 @@ func TestMeetingService_GetRecommendations(t *testing.T) {
 		participantIDs[i] = p.ID
 	}
 
-	meeting, err := service.CreateMeeting(
-		"Test Meeting",
-		organizer.ID,
-		60,
-		timeSlots,
-		participantIDs,
-	)
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		ParticipantIDs:    participantIDs,
+	})
 	assert.NoError(t, err)
 
 	// Add availabilities for participants
@@ -235,13 +281,17 @@ func TestMeetingService_GetRecommendations(t *testing.T) {
 			participant.ID,
 			meeting.ID,
 			timeSlots,
+			false,
 		)
 		assert.NoError(t, err)
 	}
 
 	// Test getting recommendations
-	recommendations, err := service.GetRecommendations(meeting.ID)
+	set, err := service.GetRecommendations(context.Background(), meeting.ID, "")
 	assert.NoError(t, err)
+	assert.Equal(t, meeting.ID, set.MeetingID)
+	assert.False(t, set.ComputedAt.IsZero())
+	recommendations := set.Slots
 	assert.NotEmpty(t, recommendations)
 
 	// Verify recommendations are sorted by available count
@@ -254,6 +304,106 @@ func TestMeetingService_GetRecommendations(t *testing.T) {
 	}
 }
 
+func TestMeetingService_ConfirmMeeting(t *testing.T) {
+	userService := NewUserService()
+	organizer, err := userService.CreateUser("Organizer", "organizer@example.com")
+	require.NoError(t, err)
+	participant, err := userService.CreateUser("Participant", "participant@example.com")
+	require.NoError(t, err)
+	publisher := &recordingPublisher{}
+	tracker := metrics.NewSLOTracker()
+	notifier := &recordingNotifier{}
+	service := NewMeetingService(userService, WithEventPublisher(publisher), WithSLOTracker(tracker), WithNotifier(notifier))
+	timeSlots := createTestTimeSlots()
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		ParticipantIDs:    []string{participant.ID},
+	})
+	require.NoError(t, err)
+	availability, err := service.AddAvailability(participant.ID, meeting.ID, timeSlots[1:], false)
+	require.NoError(t, err)
+
+	// Only recommended slots can be confirmed
+	_, err = service.ConfirmMeeting(meeting.ID, models.TimeSlot{StartTime: timeSlots[0].StartTime, EndTime: timeSlots[1].EndTime})
+	assert.True(t, errors.Is(err, errors.ErrValidation))
+
+	confirmed, err := service.ConfirmMeeting(meeting.ID, timeSlots[1])
+	require.NoError(t, err)
+	assert.Equal(t, models.MeetingStatusConfirmed, confirmed.Status)
+	require.NotNil(t, confirmed.ConfirmedSlot)
+	assert.True(t, timeSlots[1].StartTime.Equal(confirmed.ConfirmedSlot.StartTime))
+	assert.Equal(t, events.MeetingFinalized, publisher.events[len(publisher.events)-1].Type)
+	// Participants are told when the meeting takes place
+	last := notifier.notifications[len(notifier.notifications)-1]
+	assert.Equal(t, notifications.KindFinalization, last.Kind)
+	assert.Equal(t, participant.ID, last.UserID)
+	assert.Equal(t, "Meeting confirmed: Test Meeting", last.Subject)
+	assert.Contains(t, last.Body, timeSlots[1].StartTime.UTC().Format(time.RFC1123))
+	// with the invitation for their calendar attached
+	require.Len(t, last.Attachments, 1)
+	assert.Equal(t, "invite.ics", last.Attachments[0].Filename)
+	assert.Equal(t, ical.InvitationContentType, last.Attachments[0].ContentType)
+	invite := string(last.Attachments[0].Content)
+	assert.Contains(t, invite, "\r\nMETHOD:REQUEST\r\n")
+	assert.Contains(t, invite, "\r\nUID:"+meeting.ID+"@meetsync\r\n")
+	assert.Contains(t, invite, "\r\nDTSTART:"+timeSlots[1].StartTime.UTC().Format("20060102T150405Z")+"\r\n")
+	assert.Contains(t, invite, "\r\nSTATUS:CONFIRMED\r\n")
+	assert.Contains(t, invite, "mailto:organizer@example.com")
+	report := tracker.Report()
+	assert.Equal(t, int64(1), report.MeetingsFinalized)
+	assert.Equal(t, int64(1), report.MeetingsFinalizedOnTopSlot)
+
+	_, err = service.ConfirmMeeting(meeting.ID, timeSlots[1])
+	assert.True(t, errors.Is(err, errors.ErrConflict))
+
+	// Confirmed meetings no longer accept availability
+	_, err = service.AddAvailability(organizer.ID, meeting.ID, timeSlots, false)
+	assert.True(t, errors.Is(err, errors.ErrConflict))
+	_, err = service.UpdateAvailability(availability.ID, timeSlots, false)
+	assert.True(t, errors.Is(err, errors.ErrConflict))
+	_, err = service.ConfirmAvailability(availability.ID)
+	assert.True(t, errors.Is(err, errors.ErrConflict))
+
+	timeline, err := service.GetMeetingTimeline(meeting.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.TimelineMeetingFinalized, timeline[len(timeline)-1].Type)
+
+	// Drafts must be published before they are confirmed
+	draft, _, err := service.CreateMeeting(models.MeetingInput{Title: "Draft", OrganizerID: organizer.ID, Draft: true})
+	require.NoError(t, err)
+	_, err = service.ConfirmMeeting(draft.ID, timeSlots[0])
+	assert.True(t, errors.Is(err, errors.ErrValidation))
+}
+
+func TestMeetingService_CancelMeeting(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+	timeSlots := createTestTimeSlots()
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		ParticipantIDs:    []string{participants[0].ID},
+	})
+	require.NoError(t, err)
+
+	cancelled, err := service.CancelMeeting(meeting.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.MeetingStatusCancelled, cancelled.Status)
+
+	_, err = service.CancelMeeting(meeting.ID)
+	assert.True(t, errors.Is(err, errors.ErrConflict))
+	_, err = service.ConfirmMeeting(meeting.ID, timeSlots[0])
+	assert.True(t, errors.Is(err, errors.ErrConflict))
+	_, err = service.AddAvailability(participants[0].ID, meeting.ID, timeSlots, false)
+	assert.True(t, errors.Is(err, errors.ErrConflict))
+}
+
 func TestMeetingService_UpdateMeeting(t *testing.T) {
 	service, organizer, participants := setupTestMeetingService(t)
 	timeSlots := createTestTimeSlots()
@@ -264,13 +414,13 @@ func TestMeetingService_UpdateMeeting(t *testing.T) {
 	}
 
 	// Create a test meeting first
-	meeting, err := service.CreateMeeting(
-		"Original Meeting",
-		organizer.ID,
-		60,
-		timeSlots,
-		participantIDs,
-	)
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Original Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		ParticipantIDs:    participantIDs,
+	})
 	assert.NoError(t, err)
 
 	// Create new time slots for update
@@ -313,13 +463,12 @@ func TestMeetingService_UpdateMeeting(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			updatedMeeting, err := service.UpdateMeeting(
-				tt.meetingID,
-				tt.title,
-				tt.estimatedDuration,
-				tt.proposedSlots,
-				tt.participantIDs,
-			)
+			updatedMeeting, _, err := service.UpdateMeeting(tt.meetingID, models.MeetingInput{
+				Title:             tt.title,
+				EstimatedDuration: tt.estimatedDuration,
+				ProposedSlots:     tt.proposedSlots,
+				ParticipantIDs:    tt.participantIDs,
+			})
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -337,6 +486,111 @@ func TestMeetingService_UpdateMeeting(t *testing.T) {
 	}
 }
 
+func TestMeetingService_UpdateMeetingKeepsSlotIDs(t *testing.T) {
+	service, organizer, _ := setupTestMeetingService(t)
+	timeSlots := createTestTimeSlots()
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+	})
+	require.NoError(t, err)
+	original := make(map[string]string)
+	for _, slot := range meeting.ProposedSlots {
+		original[slot.Fingerprint()] = slot.ID
+	}
+
+	// Resending the same slots without their IDs keeps the IDs
+	updated, _, err := service.UpdateMeeting(meeting.ID, models.MeetingInput{ProposedSlots: timeSlots})
+	require.NoError(t, err)
+	assert.Equal(t, meeting.ProposedSlots, updated.ProposedSlots)
+
+	// Mixed update: the first slot is unchanged, the second moves while keeping its ID in
+	// the request and a third one is added with an ID chosen by the client
+	moved := models.TimeSlot{ID: updated.ProposedSlots[1].ID, StartTime: timeSlots[1].StartTime.Add(time.Hour), EndTime: timeSlots[1].EndTime.Add(time.Hour)}
+	added := models.TimeSlot{ID: "client-id", StartTime: timeSlots[1].StartTime.Add(24 * time.Hour), EndTime: timeSlots[1].EndTime.Add(24 * time.Hour)}
+	updated, _, err = service.UpdateMeeting(meeting.ID, models.MeetingInput{
+		ProposedSlots: []models.TimeSlot{{StartTime: timeSlots[0].StartTime, EndTime: timeSlots[0].EndTime}, moved, added},
+	})
+	require.NoError(t, err)
+	require.Len(t, updated.ProposedSlots, 3)
+	assert.Equal(t, original[timeSlots[0].Fingerprint()], updated.ProposedSlots[0].ID)
+	ids := map[string]bool{}
+	for _, slot := range updated.ProposedSlots[1:] {
+		assert.NotEmpty(t, slot.ID)
+		assert.NotEqual(t, moved.ID, slot.ID)
+		assert.NotEqual(t, added.ID, slot.ID)
+		ids[slot.ID] = true
+	}
+	assert.Len(t, ids, 2)
+}
+
+func TestMeetingService_RescheduleCarriesOverAvailability(t *testing.T) {
+	userService := NewUserService()
+	organizer, err := userService.CreateUser("Organizer", "organizer@example.com")
+	require.NoError(t, err)
+	kept, err := userService.CreateUser("Participant 1", "participant1@example.com")
+	require.NoError(t, err)
+	dropped, err := userService.CreateUser("Participant 2", "participant2@example.com")
+	require.NoError(t, err)
+	notifier := &recordingNotifier{}
+	service := NewMeetingService(userService, WithNotifier(notifier))
+	timeSlots := createTestTimeSlots()
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		ParticipantIDs:    []string{kept.ID, dropped.ID},
+	})
+	require.NoError(t, err)
+	keptAvailability, err := service.AddAvailability(kept.ID, meeting.ID, timeSlots, false)
+	require.NoError(t, err)
+	_, err = service.AddAvailability(dropped.ID, meeting.ID, timeSlots[1:], false)
+	require.NoError(t, err)
+
+	// The first slot is sent again without its ID, the second is replaced by a new one
+	added := models.TimeSlot{StartTime: timeSlots[1].StartTime.Add(time.Hour), EndTime: timeSlots[1].EndTime.Add(time.Hour)}
+	notifier.notifications = nil
+	updated, _, err := service.UpdateMeeting(meeting.ID, models.MeetingInput{
+		ProposedSlots: []models.TimeSlot{{StartTime: timeSlots[0].StartTime.In(time.UTC), EndTime: timeSlots[0].EndTime.In(time.UTC)}, added},
+	})
+	require.NoError(t, err)
+	var unchanged models.TimeSlot
+	for _, slot := range updated.ProposedSlots {
+		if slot.Fingerprint() == timeSlots[0].Fingerprint() {
+			unchanged = slot
+		}
+	}
+	require.NotEmpty(t, unchanged.ID)
+
+	// Availability for the unchanged slot is carried over and points at its current ID,
+	// while responses only for removed slots are dropped
+	availabilities, err := service.ListAvailabilities(meeting.ID, models.AvailabilityFilter{})
+	require.NoError(t, err)
+	require.Len(t, availabilities, 1)
+	assert.Equal(t, keptAvailability.ID, availabilities[0].ID)
+	require.Len(t, availabilities[0].AvailableSlots, 1)
+	assert.Equal(t, unchanged.ID, availabilities[0].AvailableSlots[0].ID)
+	assert.True(t, keptAvailability.ConfirmedAt.Equal(availabilities[0].ConfirmedAt))
+
+	// Participants are only asked about the new slot
+	require.Len(t, notifier.notifications, 2)
+	for _, notification := range notifier.notifications {
+		assert.Equal(t, notifications.KindInvitation, notification.Kind)
+		assert.Equal(t, "New times proposed: Test Meeting", notification.Subject)
+		assert.Equal(t, 1, strings.Count(notification.Body, "\n- "))
+	}
+
+	recommendations, err := service.GetRecommendations(context.Background(), meeting.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, recommendations.Slots[0].AvailableCount)
+	assert.True(t, unchanged.StartTime.Equal(recommendations.Slots[0].TimeSlot.StartTime))
+}
+
 func TestMeetingService_DeleteMeeting(t *testing.T) {
 	service, organizer, participants := setupTestMeetingService(t)
 	timeSlots := createTestTimeSlots()
@@ -347,13 +601,13 @@ func TestMeetingService_DeleteMeeting(t *testing.T) {
 	}
 
 	// Create a test meeting first
-	meeting, err := service.CreateMeeting(
-		"Test Meeting",
-		organizer.ID,
-		60,
-		timeSlots,
-		participantIDs,
-	)
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		ParticipantIDs:    participantIDs,
+	})
 	assert.NoError(t, err)
 
 	tests := []struct {
@@ -385,7 +639,7 @@ func TestMeetingService_DeleteMeeting(t *testing.T) {
 				assert.NoError(t, err)
 
 				// Verify meeting is deleted
-				_, err := service.GetRecommendations(tt.meetingID)
+				_, err := service.GetRecommendations(context.Background(), tt.meetingID, "")
 				assert.Error(t, err)
 				appErr, ok := err.(*errors.AppError)
 				assert.True(t, ok)
@@ -400,19 +654,20 @@ func TestMeetingService_UpdateAvailability(t *testing.T) {
 	timeSlots := createTestTimeSlots()
 
 	// Create a test meeting and availability first
-	meeting, err := service.CreateMeeting(
-		"Test Meeting",
-		organizer.ID,
-		60,
-		timeSlots,
-		[]string{participants[0].ID},
-	)
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		ParticipantIDs:    []string{participants[0].ID},
+	})
 	assert.NoError(t, err)
 
 	availability, err := service.AddAvailability(
 		participants[0].ID,
 		meeting.ID,
 		timeSlots[:1], // Only add first time slot initially
+		false,
 	)
 	assert.NoError(t, err)
 
@@ -449,6 +704,7 @@ func TestMeetingService_UpdateAvailability(t *testing.T) {
 			updatedAvailability, err := service.UpdateAvailability(
 				tt.availabilityID,
 				tt.availableSlots,
+				false,
 			)
 
 			if tt.expectError {
@@ -470,19 +726,20 @@ func TestMeetingService_DeleteAvailability(t *testing.T) {
 	timeSlots := createTestTimeSlots()
 
 	// Create a test meeting and availability first
-	meeting, err := service.CreateMeeting(
-		"Test Meeting",
-		organizer.ID,
-		60,
-		timeSlots,
-		[]string{participants[0].ID},
-	)
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		ParticipantIDs:    []string{participants[0].ID},
+	})
 	assert.NoError(t, err)
 
 	availability, err := service.AddAvailability(
 		participants[0].ID,
 		meeting.ID,
 		timeSlots,
+		false,
 	)
 	assert.NoError(t, err)
 
@@ -524,3 +781,1184 @@ func TestMeetingService_DeleteAvailability(t *testing.T) {
 		})
 	}
 }
+
+// recordingPublisher records published events for assertions
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []events.Event
+}
+
+func (p *recordingPublisher) Publish(event events.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+	return nil
+}
+
+func (p *recordingPublisher) Close() error { return nil }
+
+func TestMeetingService_PublishesEvents(t *testing.T) {
+	userService := NewUserService()
+	organizer, err := userService.CreateUser("Organizer", "organizer@example.com")
+	assert.NoError(t, err)
+	participant, err := userService.CreateUser("Participant", "participant@example.com")
+	assert.NoError(t, err)
+
+	publisher := &recordingPublisher{}
+	service := NewMeetingService(userService, WithEventPublisher(publisher))
+	timeSlots := createTestTimeSlots()
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		ParticipantIDs:    []string{participant.ID},
+	})
+	assert.NoError(t, err)
+
+	_, err = service.AddAvailability(participant.ID, meeting.ID, timeSlots[:1], false)
+	assert.NoError(t, err)
+
+	// Failed operations must not emit events
+	_, err = service.AddAvailability("non-existing-user", meeting.ID, timeSlots[:1], false)
+	assert.Error(t, err)
+
+	set, err := service.GetRecommendations(context.Background(), meeting.ID, organizer.ID)
+	assert.NoError(t, err)
+
+	assert.NoError(t, service.DeleteMeeting(meeting.ID))
+	assert.Error(t, service.DeleteMeeting(meeting.ID))
+
+	assert.Len(t, publisher.events, 4)
+	assert.Equal(t, events.MeetingCreated, publisher.events[0].Type)
+	assert.Equal(t, meeting.ID, publisher.events[0].MeetingID)
+	assert.Equal(t, events.AvailabilityAdded, publisher.events[1].Type)
+	assert.Equal(t, meeting.ID, publisher.events[1].MeetingID)
+	assert.Equal(t, events.RecommendationsViewed, publisher.events[2].Type)
+	assert.Equal(t, models.RecommendationView{
+		ViewerID:          organizer.ID,
+		ByOrganizer:       true,
+		TopSlot:           &set.Slots[0].TimeSlot,
+		AvailableCount:    1,
+		TotalParticipants: 2,
+		ComputedAt:        set.ComputedAt,
+	}, publisher.events[2].Data)
+	assert.Equal(t, events.MeetingDeleted, publisher.events[3].Type)
+	assert.Equal(t, meeting.ID, publisher.events[3].MeetingID)
+}
+
+func TestMeetingService_RecordsSLOMetrics(t *testing.T) {
+	userService := NewUserService()
+	organizer, err := userService.CreateUser("Organizer", "organizer@example.com")
+	assert.NoError(t, err)
+
+	tracker := metrics.NewSLOTracker()
+	service := NewMeetingService(userService, WithSLOTracker(tracker))
+	timeSlots := createTestTimeSlots()
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+	})
+	assert.NoError(t, err)
+
+	_, err = service.AddAvailability(organizer.ID, meeting.ID, timeSlots, false)
+	assert.NoError(t, err)
+
+	// Updating only the title is not a reschedule
+	_, _, err = service.UpdateMeeting(meeting.ID, models.MeetingInput{
+		Title: "Renamed Meeting",
+	})
+	assert.NoError(t, err)
+
+	newTimeSlots := []models.TimeSlot{
+		{
+			StartTime: time.Now().Add(72 * time.Hour),
+			EndTime:   time.Now().Add(73 * time.Hour),
+		},
+	}
+	_, _, err = service.UpdateMeeting(meeting.ID, models.MeetingInput{
+		ProposedSlots: newTimeSlots,
+	})
+	assert.NoError(t, err)
+
+	report := tracker.Report()
+	assert.Equal(t, int64(1), report.MeetingsCreated)
+	assert.Equal(t, int64(1), report.AvailabilityResponses)
+	assert.Equal(t, int64(1), report.MeetingsRescheduled)
+	assert.InDelta(t, 1.0, report.RescheduleRate, 0.0001)
+}
+
+func TestMeetingService_GetMeetingTimeline(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+	timeSlots := createTestTimeSlots()
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		ParticipantIDs:    []string{participants[0].ID},
+	})
+	assert.NoError(t, err)
+
+	_, err = service.AddAvailability(participants[0].ID, meeting.ID, timeSlots, false)
+	assert.NoError(t, err)
+
+	_, err = service.GetRecommendations(context.Background(), meeting.ID, "")
+	assert.NoError(t, err)
+
+	newTimeSlots := []models.TimeSlot{
+		{
+			StartTime: time.Now().Add(72 * time.Hour),
+			EndTime:   time.Now().Add(73 * time.Hour),
+		},
+	}
+	_, _, err = service.UpdateMeeting(meeting.ID, models.MeetingInput{
+		ProposedSlots:  newTimeSlots,
+		ParticipantIDs: []string{participants[0].ID, participants[1].ID},
+	})
+	assert.NoError(t, err)
+
+	timeline, err := service.GetMeetingTimeline(meeting.ID)
+	assert.NoError(t, err)
+
+	var types []models.TimelineEventType
+	for _, event := range timeline {
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []models.TimelineEventType{
+		models.TimelineMeetingCreated,
+		models.TimelineParticipantAdded,
+		models.TimelineAvailabilitySubmitted,
+		models.TimelineRecommendationViewed,
+		models.TimelineParticipantAdded,
+		models.TimelineMeetingRescheduled,
+	}, types)
+	assert.Equal(t, participants[1].ID, timeline[4].UserID)
+	assert.Contains(t, timeline[3].Description, "as the best slot")
+
+	_, err = service.GetMeetingTimeline("non-existing-id")
+	assert.Error(t, err)
+	appErr, ok := err.(*errors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, errors.ErrorTypeNotFound, appErr.Type)
+}
+
+// recordingNotifier records sent notifications for assertions
+type recordingNotifier struct {
+	mu            sync.Mutex
+	notifications []notifications.Notification
+}
+
+func (n *recordingNotifier) Send(notification notifications.Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.notifications = append(n.notifications, notification)
+	return nil
+}
+
+func TestMeetingService_DraftMeetings(t *testing.T) {
+	userService := NewUserService()
+	organizer, err := userService.CreateUser("Organizer", "organizer@example.com")
+	assert.NoError(t, err)
+	participant, err := userService.CreateUser("Participant", "participant@example.com")
+	assert.NoError(t, err)
+
+	notifier := &recordingNotifier{}
+	service := NewMeetingService(userService, WithNotifier(notifier))
+
+	// Drafts can be saved without a title, duration or slots
+	draft, _, err := service.CreateMeeting(models.MeetingInput{
+		OrganizerID:    organizer.ID,
+		ParticipantIDs: []string{participant.ID},
+		Draft:          true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, models.MeetingStatusDraft, draft.Status)
+	assert.Empty(t, notifier.notifications)
+
+	// Drafts cannot collect availability
+	_, err = service.AddAvailability(participant.ID, draft.ID, createTestTimeSlots(), false)
+	assert.Error(t, err)
+	appErr, ok := err.(*errors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, errors.ErrorTypeValidation, appErr.Type)
+
+	// Publishing validates the meeting details
+	_, err = service.PublishMeeting(draft.ID)
+	assert.Error(t, err)
+	appErr, ok = err.(*errors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, errors.ErrorTypeValidation, appErr.Type)
+
+	// Autosaved changes complete the draft without inviting anyone
+	_, _, err = service.UpdateMeeting(draft.ID, models.MeetingInput{
+		Title:             "Planning",
+		EstimatedDuration: 30,
+		ProposedSlots:     createTestTimeSlots(),
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, notifier.notifications)
+
+	published, err := service.PublishMeeting(draft.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.MeetingStatusPending, published.Status)
+	if assert.Len(t, notifier.notifications, 1) {
+		assert.Equal(t, notifications.KindInvitation, notifier.notifications[0].Kind)
+		assert.Equal(t, participant.Email, notifier.notifications[0].To)
+		assert.Contains(t, notifier.notifications[0].Subject, "Planning")
+	}
+
+	_, err = service.AddAvailability(participant.ID, draft.ID, published.ProposedSlots, false)
+	assert.NoError(t, err)
+
+	// Publishing twice is a conflict
+	_, err = service.PublishMeeting(draft.ID)
+	assert.Error(t, err)
+	appErr, ok = err.(*errors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, errors.ErrorTypeConflict, appErr.Type)
+}
+
+func TestMeetingService_RespondToInvitation(t *testing.T) {
+	userService := NewUserService()
+	organizer, err := userService.CreateUser("Organizer", "organizer@example.com")
+	assert.NoError(t, err)
+	participant, err := userService.CreateUser("Participant", "participant@example.com")
+	assert.NoError(t, err)
+	notifier := &recordingNotifier{}
+	service := NewMeetingService(userService, WithNotifier(notifier),
+		WithRSVPLinks(rsvp.NewSigner([]byte("secret"), time.Hour), "https://meetsync.example.com/"))
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Planning",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     createTestTimeSlots(),
+		ParticipantIDs:    []string{participant.ID},
+	})
+	assert.NoError(t, err)
+
+	// The invitation links to an answer for every proposed slot
+	var tokens []string
+	if assert.Len(t, notifier.notifications, 1) {
+		for _, line := range strings.Split(notifier.notifications[0].Body, "\n") {
+			if _, link, found := strings.Cut(line, "https://meetsync.example.com/api/rsvp/"); found {
+				tokens = append(tokens, link)
+			}
+		}
+	}
+	if !assert.Len(t, tokens, 2) {
+		return
+	}
+
+	availability, err := service.RespondToInvitation(tokens[1])
+	assert.NoError(t, err)
+	assert.Equal(t, participant.ID, availability.ParticipantID)
+	if assert.Len(t, availability.AvailableSlots, 1) {
+		assert.Equal(t, meeting.ProposedSlots[1].ID, availability.AvailableSlots[0].ID)
+	}
+
+	// Following another link adds its slot to the same availability
+	availability, err = service.RespondToInvitation(tokens[0])
+	assert.NoError(t, err)
+	assert.Len(t, availability.AvailableSlots, 2)
+
+	_, err = service.RespondToInvitation(tokens[0])
+	assert.ErrorIs(t, err, errors.ErrConflict)
+	_, err = service.RespondToInvitation("forged")
+	assert.ErrorIs(t, err, errors.ErrUnauthorized)
+}
+
+func TestMeetingService_GetPollLink(t *testing.T) {
+	userService := NewUserService()
+	organizer, err := userService.CreateUser("Organizer", "organizer@example.com")
+	assert.NoError(t, err)
+	service := NewMeetingService(userService, WithPollURL("https://meetsync.example.com/poll/{id}"))
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Planning",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 30,
+		ProposedSlots:     createTestTimeSlots(),
+	})
+	assert.NoError(t, err)
+	link, err := service.GetPollLink(meeting.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://meetsync.example.com/poll/"+meeting.ID, link)
+
+	draft, _, err := service.CreateMeeting(models.MeetingInput{OrganizerID: organizer.ID, Draft: true})
+	assert.NoError(t, err)
+	_, err = service.GetPollLink(draft.ID)
+	assert.ErrorIs(t, err, errors.ErrValidation)
+
+	_, err = service.GetPollLink("missing")
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+	_, err = NewMeetingService(userService).GetPollLink(meeting.ID)
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+}
+
+func TestMeetingService_SanitizesTitle(t *testing.T) {
+	userService := NewUserService()
+	organizer, err := userService.CreateUser("Organizer", "organizer@example.com")
+	assert.NoError(t, err)
+	service := NewMeetingService(userService, WithTextLimits(sanitize.Limits{Title: 20}))
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "  <b>Weekly</b>\tsync\x00 ",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 30,
+		ProposedSlots:     createTestTimeSlots(),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Weekly sync", meeting.Title)
+
+	// Titles made only of markup are treated as missing
+	_, _, err = service.CreateMeeting(models.MeetingInput{
+		Title:             "<i></i>",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 30,
+		ProposedSlots:     createTestTimeSlots(),
+	})
+	assert.Error(t, err)
+
+	// Titles longer than the limit are rejected on create and update
+	_, _, err = service.UpdateMeeting(meeting.ID, models.MeetingInput{Title: "A title that is far too long"})
+	assert.Error(t, err)
+	appErr, ok := err.(*errors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, errors.ErrorTypeValidation, appErr.Type)
+	assert.Contains(t, appErr.Message, "at most 20 characters")
+}
+
+func TestMeetingService_EnforcesQuotas(t *testing.T) {
+	userService := NewUserService()
+	organizer, err := userService.CreateUser("Organizer", "organizer@example.com")
+	assert.NoError(t, err)
+	var participantIDs []string
+	for i := 0; i < 3; i++ {
+		participant, err := userService.CreateUser(fmt.Sprintf("Participant %d", i), fmt.Sprintf("participant%d@example.com", i))
+		assert.NoError(t, err)
+		participantIDs = append(participantIDs, participant.ID)
+	}
+	tracker := quota.NewTracker(quota.Limits{MeetingsPerMonth: 1, ParticipantsPerMeeting: 2})
+	meter := metering.NewMeter(metering.NoopSink{})
+	service := NewMeetingService(userService, WithQuotas(tracker), WithMeter(meter))
+
+	input := models.MeetingInput{
+		Title:             "Quarterly planning",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 30,
+		ProposedSlots:     createTestTimeSlots(),
+		ParticipantIDs:    participantIDs,
+	}
+	_, _, err = service.CreateMeeting(input)
+	assert.ErrorIs(t, err, errors.ErrQuotaExceeded)
+
+	input.ParticipantIDs = participantIDs[:2]
+	meeting, _, err := service.CreateMeeting(input)
+	assert.NoError(t, err)
+
+	_, _, err = service.UpdateMeeting(meeting.ID, models.MeetingInput{ParticipantIDs: participantIDs})
+	assert.ErrorIs(t, err, errors.ErrQuotaExceeded)
+
+	_, _, err = service.CreateMeeting(input)
+	assert.ErrorIs(t, err, errors.ErrQuotaExceeded)
+	assert.Equal(t, quota.Counter{Used: 1, Limit: 1}, tracker.Usage().Meetings)
+	assert.Equal(t, int64(1), meter.Summary(meter.CurrentPeriod()).MeetingsCreated)
+}
+
+func TestMeetingService_ListMeetings(t *testing.T) {
+	service, organizer, _ := setupTestMeetingService(t)
+
+	created := make(map[string]bool)
+	for i := 0; i < 5; i++ {
+		meeting, _, err := service.CreateMeeting(models.MeetingInput{
+			Title:             "Meeting",
+			OrganizerID:       organizer.ID,
+			EstimatedDuration: 30,
+			ProposedSlots:     createTestTimeSlots(),
+		})
+		assert.NoError(t, err)
+		created[meeting.ID] = true
+	}
+
+	// Walk all pages following the cursors
+	seen := make(map[string]bool)
+	cursor := ""
+	pages := 0
+	for {
+		meetings, next, err := service.ListMeetings(cursor, 2, models.MeetingFilter{})
+		assert.NoError(t, err)
+		pages++
+		for _, meeting := range meetings {
+			assert.False(t, seen[meeting.ID], "meeting listed twice")
+			seen[meeting.ID] = true
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, created, seen)
+
+	_, _, err := service.ListMeetings("not-a-cursor", 2, models.MeetingFilter{})
+	assert.Error(t, err)
+	appErr, ok := err.(*errors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, errors.ErrorTypeValidation, appErr.Type)
+}
+
+func TestMeetingService_ListMeetingsFilters(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+
+	monday := time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)
+	create := func(day int, draft bool, participantIDs ...string) models.Meeting {
+		start := monday.Add(time.Duration(day) * 24 * time.Hour)
+		meeting, _, err := service.CreateMeeting(models.MeetingInput{
+			Title:             fmt.Sprintf("Meeting on day %d", day),
+			OrganizerID:       organizer.ID,
+			EstimatedDuration: 30,
+			ProposedSlots:     []models.TimeSlot{{StartTime: start, EndTime: start.Add(time.Hour)}},
+			ParticipantIDs:    participantIDs,
+			Draft:             draft,
+		})
+		require.NoError(t, err)
+		return meeting
+	}
+	first := create(0, false, participants[0].ID)
+	second := create(7, false, participants[0].ID, participants[1].ID)
+	draft := create(14, true, participants[1].ID)
+
+	ids := func(filter models.MeetingFilter) []string {
+		meetings, _, err := service.ListMeetings("", 0, filter)
+		require.NoError(t, err)
+		ids := make([]string, 0, len(meetings))
+		for _, meeting := range meetings {
+			ids = append(ids, meeting.ID)
+		}
+		return ids
+	}
+	assert.Equal(t, []string{first.ID, second.ID, draft.ID}, ids(models.MeetingFilter{OrganizerID: organizer.ID}))
+	assert.Empty(t, ids(models.MeetingFilter{OrganizerID: participants[0].ID}))
+	assert.Equal(t, []string{second.ID, draft.ID}, ids(models.MeetingFilter{ParticipantID: participants[1].ID}))
+	assert.Equal(t, []string{first.ID, second.ID}, ids(models.MeetingFilter{Status: models.MeetingStatusPending}))
+
+	// Date ranges match meetings proposing a slot in the range
+	assert.Equal(t, []string{second.ID, draft.ID}, ids(models.MeetingFilter{From: monday.Add(24 * time.Hour)}))
+	assert.Equal(t, []string{first.ID, second.ID}, ids(models.MeetingFilter{To: monday.Add(8 * 24 * time.Hour)}))
+	assert.Equal(t, []string{second.ID}, ids(models.MeetingFilter{
+		ParticipantID: participants[0].ID,
+		From:          monday.Add(7*24*time.Hour + 30*time.Minute),
+		To:            monday.Add(8 * 24 * time.Hour),
+	}))
+
+	_, _, err := service.ListMeetings("", 0, models.MeetingFilter{Status: "done"})
+	assert.True(t, errors.Is(err, errors.ErrValidation))
+	_, _, err = service.ListMeetings("", 0, models.MeetingFilter{From: monday, To: monday})
+	assert.True(t, errors.Is(err, errors.ErrValidation))
+}
+
+func TestMeetingService_SearchMeetings(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Roadmap review",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 30,
+		ProposedSlots:     createTestTimeSlots(),
+		ParticipantIDs:    []string{participants[0].ID},
+	})
+	assert.NoError(t, err)
+
+	results, err := service.SearchMeetings("  roadmap\tparticipant ", 0)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, meeting.ID, results[0].Meeting.ID)
+	}
+
+	for _, query := range []string{"", "   ", strings.Repeat("a", 201)} {
+		_, err = service.SearchMeetings(query, 0)
+		assert.Error(t, err)
+		appErr, ok := err.(*errors.AppError)
+		assert.True(t, ok)
+		assert.Equal(t, errors.ErrorTypeValidation, appErr.Type)
+	}
+}
+
+func TestMeetingService_Tags(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+	timeSlots := createTestTimeSlots()
+
+	create := func(tags ...string) models.Meeting {
+		meeting, _, err := service.CreateMeeting(models.MeetingInput{
+			Title:             "Meeting",
+			OrganizerID:       organizer.ID,
+			EstimatedDuration: 30,
+			ProposedSlots:     timeSlots,
+			ParticipantIDs:    []string{participants[0].ID},
+			Tags:              tags,
+		})
+		assert.NoError(t, err)
+		return meeting
+	}
+
+	interview := create("Hiring", " 1on1 ", "hiring")
+	assert.Equal(t, []string{"1on1", "hiring"}, interview.Tags)
+	hiring := create("hiring")
+	create()
+
+	_, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 30,
+		ProposedSlots:     timeSlots,
+		Tags:              []string{"  "},
+	})
+	assert.Error(t, err)
+
+	t.Run("filtering", func(t *testing.T) {
+		meetings, _, err := service.ListMeetings("", 0, models.MeetingFilter{Tags: []string{"HIRING"}})
+		assert.NoError(t, err)
+		assert.Len(t, meetings, 2)
+
+		meetings, _, err = service.ListMeetings("", 0, models.MeetingFilter{Tags: []string{"hiring", "1on1"}})
+		assert.NoError(t, err)
+		if assert.Len(t, meetings, 1) {
+			assert.Equal(t, interview.ID, meetings[0].ID)
+		}
+	})
+
+	t.Run("adding and removing", func(t *testing.T) {
+		updated, err := service.AddMeetingTags(hiring.ID, []string{"Onsite", "hiring"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"hiring", "onsite"}, updated.Tags)
+
+		updated, err = service.RemoveMeetingTag(hiring.ID, "ONSITE")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"hiring"}, updated.Tags)
+
+		_, err = service.RemoveMeetingTag(hiring.ID, "onsite")
+		assert.Error(t, err)
+		appErr, ok := err.(*errors.AppError)
+		assert.True(t, ok)
+		assert.Equal(t, errors.ErrorTypeNotFound, appErr.Type)
+	})
+
+	t.Run("updating", func(t *testing.T) {
+		updated, _, err := service.UpdateMeeting(hiring.ID, models.MeetingInput{Title: "Renamed"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"hiring"}, updated.Tags)
+
+		updated, _, err = service.UpdateMeeting(hiring.ID, models.MeetingInput{Tags: []string{}})
+		assert.NoError(t, err)
+		assert.Empty(t, updated.Tags)
+
+		_, err = service.AddMeetingTags(hiring.ID, []string{"hiring"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("statistics", func(t *testing.T) {
+		_, err := service.AddAvailability(participants[0].ID, interview.ID, timeSlots, false)
+		assert.NoError(t, err)
+
+		stats, err := service.GetTagStats()
+		assert.NoError(t, err)
+		assert.Equal(t, []models.TagStats{
+			{Tag: "hiring", Meetings: 2, Participants: 2, AvailabilityResponses: 1, ResponseRate: 0.5},
+			{Tag: "1on1", Meetings: 1, Participants: 1, AvailabilityResponses: 1, ResponseRate: 1},
+		}, stats)
+	})
+}
+
+// countingUserService counts user lookups made by the meeting service
+type countingUserService struct {
+	interfaces.UserService
+	singleLookups int
+	batchLookups  int
+}
+
+func (s *countingUserService) GetUserByID(userID string) (models.User, error) {
+	s.singleLookups++
+	return s.UserService.GetUserByID(userID)
+}
+
+func (s *countingUserService) GetUsersByIDs(userIDs []string) ([]models.User, error) {
+	s.batchLookups++
+	return s.UserService.GetUsersByIDs(userIDs)
+}
+
+func TestMeetingService_BatchParticipantLookup(t *testing.T) {
+	userService := &countingUserService{UserService: NewUserService()}
+	organizer, err := userService.CreateUser("Organizer", "organizer@example.com")
+	assert.NoError(t, err)
+
+	var participantIDs []string
+	for i := 0; i < 25; i++ {
+		participant, err := userService.CreateUser("Participant", fmt.Sprintf("participant%d@example.com", i))
+		assert.NoError(t, err)
+		participantIDs = append(participantIDs, participant.ID)
+	}
+
+	service := NewMeetingService(userService)
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Town hall",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     createTestTimeSlots(),
+		ParticipantIDs:    participantIDs,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, meeting.Participants, 25)
+	assert.Equal(t, participantIDs[0], meeting.Participants[0].ID)
+
+	// Only the organizer is looked up individually
+	assert.Equal(t, 1, userService.singleLookups)
+	assert.Equal(t, 1, userService.batchLookups)
+
+	_, _, err = service.UpdateMeeting(meeting.ID, models.MeetingInput{ParticipantIDs: participantIDs[:10]})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, userService.singleLookups)
+	assert.Equal(t, 2, userService.batchLookups)
+
+	// Unknown participants are still reported by ID
+	_, _, err = service.UpdateMeeting(meeting.ID, models.MeetingInput{ParticipantIDs: []string{participantIDs[0], "missing-id"}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Participant not found: missing-id")
+}
+
+func TestMeetingService_MaterializedRecommendations(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+	WithMaterializedRecommendations(true)(service)
+	timeSlots := createTestTimeSlots()
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		ParticipantIDs:    []string{participants[0].ID, participants[1].ID},
+	})
+	assert.NoError(t, err)
+
+	// Missing recommendations are computed and stored on first read
+	first, err := service.GetRecommendations(context.Background(), meeting.ID, "")
+	assert.NoError(t, err)
+	stored, err := service.repository.GetRecommendations(meeting.ID)
+	assert.NoError(t, err)
+	assert.True(t, first.ComputedAt.Equal(stored.ComputedAt))
+
+	// Reads return the stored set unchanged
+	again, err := service.GetRecommendations(context.Background(), meeting.ID, "")
+	assert.NoError(t, err)
+	assert.True(t, first.ComputedAt.Equal(again.ComputedAt))
+
+	// Availability changes refresh the stored set
+	availability, err := service.AddAvailability(participants[0].ID, meeting.ID, timeSlots[:1], false)
+	assert.NoError(t, err)
+	refreshed, err := service.GetRecommendations(context.Background(), meeting.ID, "")
+	assert.NoError(t, err)
+	assert.False(t, refreshed.ComputedAt.Before(first.ComputedAt))
+	assert.Equal(t, 1, refreshed.Slots[0].AvailableCount)
+
+	assert.NoError(t, service.DeleteAvailability(availability.ID))
+	afterDelete, err := service.GetRecommendations(context.Background(), meeting.ID, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, afterDelete.Slots[0].AvailableCount)
+
+	// Read replicas compute missing recommendations without storing them
+	WithMaterializedRecommendations(false)(service)
+	unstored, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Replicated Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+	})
+	assert.NoError(t, err)
+	WithMaterializedRecommendations(true)(service)
+	WithReadOnlyStorage(true)(service)
+	computed, err := service.GetRecommendations(context.Background(), unstored.ID, "")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, computed.Slots)
+	_, err = service.repository.GetRecommendations(unstored.ID)
+	assert.True(t, errors.Is(err, errors.ErrNotFound))
+	// nor record views, which would write to them
+	timeline, err := service.GetMeetingTimeline(unstored.ID)
+	assert.NoError(t, err)
+	for _, event := range timeline {
+		assert.NotEqual(t, models.TimelineRecommendationViewed, event.Type)
+	}
+}
+
+func TestMeetingService_RecommendationViews(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+	publisher := &recordingPublisher{}
+	WithEventPublisher(publisher)(service)
+	timeSlots := createTestTimeSlots()
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		ParticipantIDs:    []string{participants[0].ID},
+	})
+	require.NoError(t, err)
+
+	views := func() (viewers []string) {
+		timeline, err := service.GetMeetingTimeline(meeting.ID)
+		require.NoError(t, err)
+		for _, event := range timeline {
+			if event.Type == models.TimelineRecommendationViewed {
+				viewers = append(viewers, event.UserID)
+			}
+		}
+		return viewers
+	}
+	view := func(viewerID string) {
+		_, err := service.GetRecommendations(context.Background(), meeting.ID, viewerID)
+		require.NoError(t, err)
+	}
+
+	// Polling records a view once per viewer until the meeting changes
+	view(organizer.ID)
+	view(organizer.ID)
+	view(participants[0].ID)
+	view(organizer.ID)
+	assert.Equal(t, []string{organizer.ID, participants[0].ID}, views())
+
+	_, err = service.AddAvailability(participants[0].ID, meeting.ID, timeSlots[:1], false)
+	require.NoError(t, err)
+	view(organizer.ID)
+	view(organizer.ID)
+	assert.Equal(t, []string{organizer.ID, participants[0].ID, organizer.ID}, views())
+
+	var published int
+	for _, event := range publisher.events {
+		if event.Type == events.RecommendationsViewed {
+			published++
+		}
+	}
+	assert.Equal(t, 3, published)
+}
+
+func TestMeetingService_WaitForRecommendationChange(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+	timeSlots := createTestTimeSlots()
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		ParticipantIDs:    []string{participants[0].ID},
+	})
+	assert.NoError(t, err)
+
+	initial, err := service.GetRecommendations(context.Background(), meeting.ID, "")
+	assert.NoError(t, err)
+
+	t.Run("returns after the timeout when nothing changes", func(t *testing.T) {
+		start := time.Now()
+		set, err := service.WaitForRecommendationChange(context.Background(), meeting.ID, initial, 50*time.Millisecond)
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+		assert.True(t, sameRecommendations(initial.Slots, set.Slots))
+	})
+
+	t.Run("returns early when availability changes", func(t *testing.T) {
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			_, err := service.AddAvailability(participants[0].ID, meeting.ID, timeSlots[:1], false)
+			assert.NoError(t, err)
+		}()
+
+		start := time.Now()
+		set, err := service.WaitForRecommendationChange(context.Background(), meeting.ID, initial, 5*time.Second)
+		assert.NoError(t, err)
+		assert.Less(t, time.Since(start), 5*time.Second)
+		assert.False(t, sameRecommendations(initial.Slots, set.Slots))
+	})
+
+	t.Run("returns when the context is cancelled", func(t *testing.T) {
+		current, err := service.GetRecommendations(context.Background(), meeting.ID, "")
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = service.WaitForRecommendationChange(ctx, meeting.ID, current, 5*time.Second)
+		assert.NoError(t, err)
+	})
+}
+
+func TestMeetingService_WaitForRecommendationChangeOnSharedStorage(t *testing.T) {
+	// Two replicas sharing storage; changes made on one do not wake up readers on the other
+	userService := NewUserService()
+	organizer, err := userService.CreateUser("Organizer", "organizer@example.com")
+	require.NoError(t, err)
+	participant, err := userService.CreateUser("Participant", "participant@example.com")
+	require.NoError(t, err)
+	repository := repositories.NewInMemoryMeetingRepository()
+	writer := NewMeetingService(userService, WithMeetingRepository(repository))
+	reader := NewMeetingService(userService, WithMeetingRepository(repository), WithSharedStorage(true))
+	timeSlots := createTestTimeSlots()
+
+	meeting, _, err := writer.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		ParticipantIDs:    []string{participant.ID},
+	})
+	require.NoError(t, err)
+	initial, err := reader.GetRecommendations(context.Background(), meeting.ID, "")
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_, err := writer.AddAvailability(participant.ID, meeting.ID, timeSlots[:1], false)
+		assert.NoError(t, err)
+	}()
+	set, err := reader.WaitForRecommendationChange(context.Background(), meeting.ID, initial, 5*time.Second)
+	require.NoError(t, err)
+	assert.False(t, sameRecommendations(initial.Slots, set.Slots))
+}
+
+func TestMeetingService_TentativeAvailability(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+	timeSlots := createTestTimeSlots()
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		ParticipantIDs:    []string{participants[0].ID, participants[1].ID},
+	})
+	assert.NoError(t, err)
+	_, err = service.AddAvailability(participants[0].ID, meeting.ID, timeSlots, false)
+	assert.NoError(t, err)
+	tentative, err := service.AddAvailability(participants[1].ID, meeting.ID, timeSlots[:1], true)
+	assert.NoError(t, err)
+	assert.True(t, tentative.Tentative)
+
+	set, err := service.GetRecommendations(context.Background(), meeting.ID, "")
+	assert.NoError(t, err)
+	assert.Len(t, set.Slots, 2)
+	assert.Equal(t, timeSlots[0].StartTime, set.Slots[0].TimeSlot.StartTime)
+	assert.Equal(t, 2, set.Slots[0].AvailableCount)
+	assert.Equal(t, 1, set.Slots[0].ConfirmedCount)
+	assert.Equal(t, 1, set.Slots[0].TentativeCount)
+	assert.Equal(t, 1, set.Slots[1].ConfirmedCount)
+	assert.Equal(t, 0, set.Slots[1].TentativeCount)
+
+	// Updating the slots replaces the status, so confirming takes a plain update
+	confirmed, err := service.UpdateAvailability(tentative.ID, timeSlots[:1], false)
+	assert.NoError(t, err)
+	assert.False(t, confirmed.Tentative)
+
+	set, err = service.GetRecommendations(context.Background(), meeting.ID, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, set.Slots[0].ConfirmedCount)
+	assert.Equal(t, 0, set.Slots[0].TentativeCount)
+}
+
+func TestMeetingService_AvailabilityExpiry(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+	notifier := &recordingNotifier{}
+	service.notifier = notifier
+	timeSlots := createTestTimeSlots()
+
+	_, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		AvailabilityTTL:   -1,
+	})
+	assert.Error(t, err)
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		ParticipantIDs:    []string{participants[0].ID, participants[1].ID},
+		AvailabilityTTL:   24,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 24, meeting.AvailabilityTTL)
+
+	old, err := service.AddAvailability(participants[0].ID, meeting.ID, timeSlots, false)
+	assert.NoError(t, err)
+	_, err = service.AddAvailability(participants[1].ID, meeting.ID, timeSlots, false)
+	assert.NoError(t, err)
+
+	// Backdate the first response past the TTL
+	old.ConfirmedAt = time.Now().Add(-48 * time.Hour)
+	_, err = service.repository.UpdateAvailability(old)
+	assert.NoError(t, err)
+
+	availability, err := service.GetAvailability(participants[0].ID, meeting.ID)
+	assert.NoError(t, err)
+	assert.True(t, availability.Stale)
+	availability, err = service.GetAvailability(participants[1].ID, meeting.ID)
+	assert.NoError(t, err)
+	assert.False(t, availability.Stale)
+
+	notifier.notifications = nil
+	stale, err := service.RequestReconfirmation(meeting.ID)
+	assert.NoError(t, err)
+	assert.Len(t, stale, 1)
+	assert.Equal(t, old.ID, stale[0].ID)
+	assert.Len(t, notifier.notifications, 1)
+	assert.Equal(t, notifications.KindReconfirmation, notifier.notifications[0].Kind)
+	assert.Equal(t, participants[0].ID, notifier.notifications[0].UserID)
+
+	confirmed, err := service.ConfirmAvailability(old.ID)
+	assert.NoError(t, err)
+	assert.Len(t, confirmed.AvailableSlots, 2)
+	availability, err = service.GetAvailability(participants[0].ID, meeting.ID)
+	assert.NoError(t, err)
+	assert.False(t, availability.Stale)
+
+	stale, err = service.RequestReconfirmation(meeting.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, stale)
+
+	// Meetings without a TTL never have stale responses
+	untimed, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Untimed Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+	})
+	assert.NoError(t, err)
+	_, err = service.RequestReconfirmation(untimed.ID)
+	assert.Error(t, err)
+}
+
+func TestMeetingService_GetAvailabilityGrid(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+	timeSlots := createTestTimeSlots()
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		ParticipantIDs:    []string{participants[0].ID, participants[1].ID},
+	})
+	assert.NoError(t, err)
+	_, err = service.AddAvailability(organizer.ID, meeting.ID, timeSlots[:1], false)
+	assert.NoError(t, err)
+	_, err = service.AddAvailability(participants[0].ID, meeting.ID, timeSlots, true)
+	assert.NoError(t, err)
+
+	grid, err := service.GetAvailabilityGrid(meeting.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Test Meeting", grid.Title)
+	assert.Equal(t, []string{organizer.ID, participants[0].ID, participants[1].ID}, []string{grid.Participants[0].ID, grid.Participants[1].ID, grid.Participants[2].ID})
+	if assert.Len(t, grid.Rows, 2) {
+		// The first slot suits the organizer too, so it ranks first
+		assert.True(t, timeSlots[0].StartTime.Equal(grid.Rows[0].Slot.TimeSlot.StartTime))
+		assert.Equal(t, []models.AvailabilityMark{models.AvailabilityMarkAvailable, models.AvailabilityMarkTentative, models.AvailabilityMarkNoResponse}, grid.Rows[0].Marks)
+		assert.Equal(t, []models.AvailabilityMark{models.AvailabilityMarkUnavailable, models.AvailabilityMarkTentative, models.AvailabilityMarkNoResponse}, grid.Rows[1].Marks)
+	}
+
+	_, err = service.GetAvailabilityGrid("missing")
+	assert.True(t, errors.Is(err, errors.ErrNotFound))
+}
+
+func TestMeetingService_GetMeetingSummary(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+	timeSlots := createTestTimeSlots()
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		ParticipantIDs:    []string{participants[0].ID, participants[1].ID},
+	})
+	assert.NoError(t, err)
+	_, err = service.AddAvailability(participants[0].ID, meeting.ID, timeSlots, true)
+	assert.NoError(t, err)
+
+	summary, err := service.GetMeetingSummary(meeting.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, meeting.ID, summary.Meeting.ID)
+	assert.Len(t, summary.Grid.Rows, 2)
+	if assert.Len(t, summary.Responses, 3) {
+		assert.Equal(t, organizer.ID, summary.Responses[0].Participant.ID)
+		assert.False(t, summary.Responses[0].Responded)
+		assert.True(t, summary.Responses[1].Responded)
+		assert.True(t, summary.Responses[1].Tentative)
+		assert.False(t, summary.Responses[1].ConfirmedAt.IsZero())
+		assert.False(t, summary.Responses[2].Responded)
+	}
+}
+
+func TestMeetingService_SimulateRecommendations(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+	timeSlots := createTestTimeSlots()
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		ParticipantIDs:    []string{participants[0].ID, participants[1].ID},
+	})
+	assert.NoError(t, err)
+	_, err = service.AddAvailability(participants[0].ID, meeting.ID, timeSlots, false)
+	assert.NoError(t, err)
+
+	t.Run("dropping a participant", func(t *testing.T) {
+		simulation, err := service.SimulateRecommendations(meeting.ID, models.RecommendationScenario{
+			RemoveParticipantIDs: []string{participants[1].ID},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, simulation.Current, 2)
+		assert.Len(t, simulation.Simulated, 2)
+		assert.Len(t, simulation.Changes, 2)
+		for _, change := range simulation.Changes {
+			assert.Equal(t, models.RecommendationChanged, change.Kind)
+			assert.Equal(t, 3, change.CurrentTotalParticipants)
+			assert.Equal(t, 2, change.SimulatedTotalParticipants)
+			assert.Equal(t, 1, change.SimulatedAvailableCount)
+		}
+	})
+
+	t.Run("extending the window", func(t *testing.T) {
+		simulation, err := service.SimulateRecommendations(meeting.ID, models.RecommendationScenario{
+			ExtendWindow: 24 * 60,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, simulation.Changes, 2)
+		assert.Equal(t, models.RecommendationAdded, simulation.Changes[0].Kind)
+		assert.Equal(t, timeSlots[1].EndTime.Add(24*time.Hour), simulation.Changes[0].TimeSlot.EndTime)
+		assert.Equal(t, models.RecommendationRemoved, simulation.Changes[1].Kind)
+		assert.Equal(t, timeSlots[1].EndTime, simulation.Changes[1].TimeSlot.EndTime)
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		simulation, err := service.SimulateRecommendations(meeting.ID, models.RecommendationScenario{})
+		assert.NoError(t, err)
+		assert.Empty(t, simulation.Changes)
+	})
+
+	t.Run("invalid scenarios", func(t *testing.T) {
+		scenarios := map[string]models.RecommendationScenario{
+			"removing the organizer":     {RemoveParticipantIDs: []string{organizer.ID}},
+			"removing a non-participant": {RemoveParticipantIDs: []string{"someone-else"}},
+			"negative extension":         {ExtendWindow: -60},
+			"negative duration":          {Duration: -30},
+		}
+		for name, scenario := range scenarios {
+			_, err := service.SimulateRecommendations(meeting.ID, scenario)
+			assert.Error(t, err, name)
+		}
+	})
+
+	t.Run("meeting not found", func(t *testing.T) {
+		_, err := service.SimulateRecommendations("missing", models.RecommendationScenario{})
+		assert.Error(t, err)
+	})
+
+	// Simulations never change the meeting
+	stored, err := service.repository.GetMeetingByID(meeting.ID)
+	assert.NoError(t, err)
+	assert.Len(t, stored.Participants, 2)
+	assert.Equal(t, 60, stored.EstimatedDuration)
+	assert.Equal(t, timeSlots[1].EndTime.Unix(), stored.ProposedSlots[1].EndTime.Unix())
+}
+
+func TestMeetingService_Priority(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+	notifier := &recordingNotifier{}
+	WithNotifier(notifier)(service)
+	timeSlots := createTestTimeSlots()
+
+	create := func(priority models.MeetingPriority) models.Meeting {
+		meeting, _, err := service.CreateMeeting(models.MeetingInput{
+			Title:             "Meeting",
+			OrganizerID:       organizer.ID,
+			EstimatedDuration: 60,
+			ProposedSlots:     timeSlots,
+			ParticipantIDs:    []string{participants[0].ID},
+			Priority:          priority,
+		})
+		assert.NoError(t, err)
+		return meeting
+	}
+
+	t.Run("defaults to normal and rejects unknown priorities", func(t *testing.T) {
+		assert.Equal(t, models.PriorityNormal, create("").Priority)
+
+		_, _, err := service.CreateMeeting(models.MeetingInput{
+			Title:             "Meeting",
+			OrganizerID:       organizer.ID,
+			EstimatedDuration: 60,
+			ProposedSlots:     timeSlots,
+			Priority:          "critical",
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("urgent invitations are flagged", func(t *testing.T) {
+		notifier.notifications = nil
+		create(models.PriorityUrgent)
+		assert.Len(t, notifier.notifications, 1)
+		assert.Equal(t, notifications.ImportanceHigh, notifier.notifications[0].Importance)
+		assert.Equal(t, "Urgent invitation: Meeting", notifier.notifications[0].Subject)
+	})
+
+	t.Run("conflicts with lower-priority meetings are ignored", func(t *testing.T) {
+		low := create(models.PriorityLow)
+		normal := create(models.PriorityNormal)
+		urgent := create(models.PriorityUrgent)
+		_, err := service.AddAvailability(participants[0].ID, low.ID, timeSlots[:1], false)
+		assert.NoError(t, err)
+		_, err = service.AddAvailability(participants[0].ID, normal.ID, timeSlots, false)
+		assert.NoError(t, err)
+		_, err = service.AddAvailability(participants[0].ID, urgent.ID, timeSlots, false)
+		assert.NoError(t, err)
+
+		// The normal meeting competes with the urgent one on both slots, but not with the low one
+		set, err := service.GetRecommendations(context.Background(), normal.ID, "")
+		assert.NoError(t, err)
+		for _, slot := range set.Slots {
+			assert.Len(t, slot.Conflicts, 1)
+			assert.Equal(t, urgent.ID, slot.Conflicts[0].MeetingID)
+		}
+
+		// The urgent meeting takes precedence over both
+		set, err = service.GetRecommendations(context.Background(), urgent.ID, "")
+		assert.NoError(t, err)
+		for _, slot := range set.Slots {
+			assert.Empty(t, slot.Conflicts)
+		}
+
+		// The low meeting competes with both others on the slot its participant offered
+		set, err = service.GetRecommendations(context.Background(), low.ID, "")
+		assert.NoError(t, err)
+		assert.Len(t, set.Slots[0].Conflicts, 2)
+		assert.Empty(t, set.Slots[1].Conflicts)
+	})
+
+	t.Run("updates change the priority", func(t *testing.T) {
+		meeting := create(models.PriorityLow)
+		updated, _, err := service.UpdateMeeting(meeting.ID, models.MeetingInput{Priority: models.PriorityHigh})
+		assert.NoError(t, err)
+		assert.Equal(t, models.PriorityHigh, updated.Priority)
+
+		_, _, err = service.UpdateMeeting(meeting.ID, models.MeetingInput{Priority: "critical"})
+		assert.Error(t, err)
+	})
+}
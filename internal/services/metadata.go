@@ -0,0 +1,118 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"meetsync/internal/models"
+	"meetsync/internal/pagination"
+	"meetsync/pkg/errors"
+)
+
+const (
+	// maxExternalIDLength caps the length of the identifiers integrators and calendar syncs
+	// correlate records with
+	maxExternalIDLength = 256
+	// maxMetadataKeys caps the number of metadata entries of a record
+	maxMetadataKeys = 32
+	// maxMetadataKeyLength caps the length of a metadata key
+	maxMetadataKeyLength = 64
+	// maxMetadataValueLength caps the length of a metadata value
+	maxMetadataValueLength = 512
+)
+
+// validateExternalID checks an external ID, which may be empty
+func validateExternalID(externalID string) error {
+	if len(externalID) > maxExternalIDLength {
+		return errors.NewValidationError("External ID is too long", fmt.Sprintf("external IDs can be at most %d characters", maxExternalIDLength))
+	}
+	if strings.TrimSpace(externalID) != externalID {
+		return errors.NewValidationError("External ID must not start or end with spaces", "")
+	}
+	return nil
+}
+
+// validateMetadata checks the size of metadata entries; keys cannot be blank
+func validateMetadata(metadata models.Metadata) error {
+	if len(metadata) > maxMetadataKeys {
+		return errors.NewValidationError("Too many metadata entries", fmt.Sprintf("records can carry at most %d entries", maxMetadataKeys))
+	}
+	for key, value := range metadata {
+		if strings.TrimSpace(key) == "" {
+			return errors.NewValidationError("Metadata keys must not be blank", "")
+		}
+		if len(key) > maxMetadataKeyLength {
+			return errors.NewValidationError("Metadata key is too long", fmt.Sprintf("keys can be at most %d characters", maxMetadataKeyLength))
+		}
+		if len(value) > maxMetadataValueLength {
+			return errors.NewValidationError("Metadata value is too long", fmt.Sprintf("the value of %s exceeds %d characters", key, maxMetadataValueLength))
+		}
+	}
+	return nil
+}
+
+// SetUserMetadata replaces the metadata of a user; nil or an empty map clears it
+func (s *UserServiceImpl) SetUserMetadata(userID string, metadata models.Metadata) (models.User, error) {
+	if err := validateMetadata(metadata); err != nil {
+		return models.User{}, err
+	}
+
+	user, err := s.repository.GetByID(userID)
+	if err != nil {
+		return models.User{}, err
+	}
+	user.Metadata = metadata
+	if len(metadata) == 0 {
+		user.Metadata = nil
+	}
+	return s.repository.Update(user)
+}
+
+// SetAvailabilityMetadata replaces the external ID and metadata of an availability; empty
+// values clear them
+func (s *MeetingServiceImpl) SetAvailabilityMetadata(availabilityID, externalID string, metadata models.Metadata) (models.Availability, error) {
+	if err := validateExternalID(externalID); err != nil {
+		return models.Availability{}, err
+	}
+	if err := validateMetadata(metadata); err != nil {
+		return models.Availability{}, err
+	}
+
+	availability, err := s.repository.GetAvailabilityByID(availabilityID)
+	if err != nil {
+		return models.Availability{}, err
+	}
+	availability.ExternalID = externalID
+	availability.Metadata = metadata
+	if len(metadata) == 0 {
+		availability.Metadata = nil
+	}
+	return s.repository.UpdateAvailability(availability)
+}
+
+// ListAvailabilities returns the availabilities submitted for a meeting that pass the filter
+func (s *MeetingServiceImpl) ListAvailabilities(meetingID string, filter models.AvailabilityFilter) ([]models.Availability, error) {
+	meeting, err := s.repository.GetMeetingByID(meetingID)
+	if err != nil {
+		return nil, err
+	}
+	availabilities, err := s.repository.GetMeetingAvailabilities(meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	matching := make([]models.Availability, 0, len(availabilities))
+	for _, availability := range availabilities {
+		if filter.Matches(availability) {
+			availability.Stale = availability.IsStale(meeting.AvailabilityTTL, now)
+			matching = append(matching, availability)
+		}
+	}
+	sort.Slice(matching, func(i, j int) bool {
+		return pagination.Less(matching[i].CreatedAt, matching[i].ID, matching[j].CreatedAt, matching[j].ID)
+	})
+	return matching, nil
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+)
+
+func TestValidateMetadata(t *testing.T) {
+	assert.NoError(t, validateMetadata(nil))
+	assert.NoError(t, validateMetadata(models.Metadata{"crmId": "42", "stage": ""}))
+
+	tooMany := make(models.Metadata)
+	for i := 0; i <= maxMetadataKeys; i++ {
+		tooMany[strings.Repeat("k", i+1)] = "v"
+	}
+	for name, metadata := range map[string]models.Metadata{
+		"too many entries": tooMany,
+		"blank key":        {" ": "v"},
+		"long key":         {strings.Repeat("k", maxMetadataKeyLength+1): "v"},
+		"long value":       {"k": strings.Repeat("v", maxMetadataValueLength+1)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.True(t, errors.Is(validateMetadata(metadata), errors.ErrValidation))
+		})
+	}
+}
+
+func TestMeetingService_Metadata(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+
+	tagged, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Candidate interview",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     createTestTimeSlots(),
+		ExternalID:        "ats-application-7",
+		Metadata:          models.Metadata{"candidateId": "c-1", "stage": "onsite"},
+	})
+	require.NoError(t, err)
+	_, _, err = service.CreateMeeting(models.MeetingInput{
+		Title:             "Other meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     createTestTimeSlots(),
+		Metadata:          models.Metadata{"candidateId": "c-2"},
+	})
+	require.NoError(t, err)
+
+	meetings, _, err := service.ListMeetings("", 10, models.MeetingFilter{Metadata: models.Metadata{"candidateId": "c-1"}})
+	require.NoError(t, err)
+	require.Len(t, meetings, 1)
+	assert.Equal(t, tagged.ID, meetings[0].ID)
+	meetings, _, err = service.ListMeetings("", 10, models.MeetingFilter{ExternalID: "ats-application-7"})
+	require.NoError(t, err)
+	require.Len(t, meetings, 1)
+	assert.Equal(t, tagged.ID, meetings[0].ID)
+
+	// Updates replace the metadata when given and keep it otherwise
+	updated, _, err := service.UpdateMeeting(tagged.ID, models.MeetingInput{Title: "Renamed"})
+	require.NoError(t, err)
+	assert.Equal(t, models.Metadata{"candidateId": "c-1", "stage": "onsite"}, updated.Metadata)
+	updated, _, err = service.UpdateMeeting(tagged.ID, models.MeetingInput{Metadata: models.Metadata{}})
+	require.NoError(t, err)
+	assert.Nil(t, updated.Metadata)
+
+	// Availabilities carry their own external ID and metadata
+	first, err := service.AddAvailability(participants[0].ID, tagged.ID, tagged.ProposedSlots[:1], false)
+	require.NoError(t, err)
+	_, err = service.AddAvailability(participants[1].ID, tagged.ID, tagged.ProposedSlots[:1], false)
+	require.NoError(t, err)
+	withMetadata, err := service.SetAvailabilityMetadata(first.ID, "ats-feedback-3", models.Metadata{"interviewer": "yes"})
+	require.NoError(t, err)
+	assert.Equal(t, "ats-feedback-3", withMetadata.ExternalID)
+
+	availabilities, err := service.ListAvailabilities(tagged.ID, models.AvailabilityFilter{})
+	require.NoError(t, err)
+	assert.Len(t, availabilities, 2)
+	availabilities, err = service.ListAvailabilities(tagged.ID, models.AvailabilityFilter{Metadata: models.Metadata{"interviewer": "yes"}})
+	require.NoError(t, err)
+	require.Len(t, availabilities, 1)
+	assert.Equal(t, first.ID, availabilities[0].ID)
+
+	_, err = service.ListAvailabilities("missing", models.AvailabilityFilter{})
+	assert.True(t, errors.Is(err, errors.ErrNotFound))
+	_, err = service.SetAvailabilityMetadata(first.ID, strings.Repeat("x", maxExternalIDLength+1), nil)
+	assert.True(t, errors.Is(err, errors.ErrValidation))
+}
+
+func TestUserService_SetUserMetadata(t *testing.T) {
+	service := NewUserService()
+	user, err := service.CreateUser("Jane Doe", "jane@example.com")
+	require.NoError(t, err)
+
+	updated, err := service.SetUserMetadata(user.ID, models.Metadata{"crmContactId": "0035e000"})
+	require.NoError(t, err)
+	assert.Equal(t, models.Metadata{"crmContactId": "0035e000"}, updated.Metadata)
+	assert.True(t, models.UserFilter{Metadata: models.Metadata{"crmContactId": "0035e000"}}.Matches(updated))
+
+	cleared, err := service.SetUserMetadata(user.ID, nil)
+	require.NoError(t, err)
+	assert.Nil(t, cleared.Metadata)
+
+	_, err = service.SetUserMetadata("missing", nil)
+	assert.True(t, errors.Is(err, errors.ErrNotFound))
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"meetsync/pkg/errors"
+)
+
+// PrecomputeRecommendations recomputes and stores the recommendations of a meeting for a
+// precompute job requested at requestedAt. Jobs for deleted meetings are skipped, and so
+// are jobs requested before the stored recommendations were computed, since these already
+// reflect the change.
+func (s *MeetingServiceImpl) PrecomputeRecommendations(meetingID string, requestedAt time.Time) error {
+	meeting, err := s.repository.GetMeetingByID(meetingID)
+	if errors.Is(err, errors.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if set, err := s.repository.GetRecommendations(meetingID); err == nil && set.ComputedAt.After(requestedAt) {
+		return nil
+	}
+
+	defer s.changes.notify(meetingID)
+	_, err = s.materializeRecommendationSet(context.Background(), meeting)
+	return err
+}
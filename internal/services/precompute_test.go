@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"meetsync/internal/models"
+)
+
+// recordingQueue records the meetings queued for precompute, failing while broken
+type recordingQueue struct {
+	mu       sync.Mutex
+	meetings []string
+	broken   bool
+}
+
+func (q *recordingQueue) Enqueue(meetingID string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.broken {
+		return fmt.Errorf("queue unreachable")
+	}
+	q.meetings = append(q.meetings, meetingID)
+	return nil
+}
+
+func TestMeetingService_PrecomputeRecommendations(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+	queue := &recordingQueue{}
+	WithMaterializedRecommendations(true)(service)
+	WithPrecomputeQueue(queue)(service)
+	timeSlots := createTestTimeSlots()
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		ParticipantIDs:    []string{participants[0].ID},
+	})
+	require.NoError(t, err)
+
+	initial, err := service.GetRecommendations(context.Background(), meeting.ID, "")
+	require.NoError(t, err)
+
+	// Changes queue a job instead of computing recommendations, so readers see the
+	// previous ones until a worker stores the new ones
+	requestedAt := time.Now()
+	_, err = service.AddAvailability(participants[0].ID, meeting.ID, timeSlots[:1], false)
+	require.NoError(t, err)
+	assert.Contains(t, queue.meetings, meeting.ID)
+	previous, err := service.GetRecommendations(context.Background(), meeting.ID, "")
+	require.NoError(t, err)
+	assert.True(t, initial.ComputedAt.Equal(previous.ComputedAt))
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		assert.NoError(t, service.PrecomputeRecommendations(meeting.ID, requestedAt))
+	}()
+	set, err := service.WaitForRecommendationChange(context.Background(), meeting.ID, previous, 2*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, 1, set.Slots[0].AvailableCount)
+	stored, err := service.repository.GetRecommendations(meeting.ID)
+	require.NoError(t, err)
+
+	// Jobs the stored recommendations already reflect are skipped, as are deleted meetings
+	require.NoError(t, service.PrecomputeRecommendations(meeting.ID, requestedAt))
+	again, err := service.repository.GetRecommendations(meeting.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.ComputedAt.Equal(again.ComputedAt))
+	assert.NoError(t, service.PrecomputeRecommendations("missing", time.Now()))
+
+	// When the queue is unreachable, recommendations are computed right away
+	queue.broken = true
+	_, err = service.AddAvailability(organizer.ID, meeting.ID, timeSlots[:1], false)
+	require.NoError(t, err)
+	refreshed, err := service.repository.GetRecommendations(meeting.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, refreshed.Slots[0].AvailableCount)
+}
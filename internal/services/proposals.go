@@ -0,0 +1,223 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"meetsync/internal/events"
+	"meetsync/internal/models"
+	"meetsync/internal/notifications"
+	"meetsync/internal/sanitize"
+	"meetsync/pkg/errors"
+	"meetsync/pkg/logs"
+)
+
+// maxProposalReasonLength is the maximum length, in characters, of the reason given with
+// an alternative proposal
+const maxProposalReasonLength = 500
+
+// ProposeAlternative records a participant of a confirmed meeting proposing another slot,
+// for instance because they have a conflict at the confirmed time, and notifies the
+// organizer. Each participant has at most one proposal waiting for a decision.
+func (s *MeetingServiceImpl) ProposeAlternative(meetingID, participantID string, slot models.TimeSlot, reason string) (models.Proposal, error) {
+	reason = sanitize.SingleLine(reason)
+	if err := sanitize.CheckLength("Reason", reason, maxProposalReasonLength); err != nil {
+		return models.Proposal{}, err
+	}
+
+	meeting, err := s.repository.GetMeetingByID(meetingID)
+	if err != nil {
+		return models.Proposal{}, err
+	}
+	if meeting.Status != models.MeetingStatusConfirmed || meeting.ConfirmedSlot == nil {
+		return models.Proposal{}, errors.NewValidationError("Alternatives can only be proposed for confirmed meetings", "")
+	}
+	if !meeting.HasParticipant(participantID) {
+		return models.Proposal{}, errors.NewValidationError("User is not a participant of the meeting", "")
+	}
+	if !slot.StartTime.Before(slot.EndTime) {
+		return models.Proposal{}, errors.NewValidationError("Slot must end after it starts", "")
+	}
+	if slot.EndTime.Sub(slot.StartTime) < time.Duration(meeting.EstimatedDuration)*time.Minute {
+		return models.Proposal{}, errors.NewValidationError("Slot is shorter than the meeting", fmt.Sprintf("the meeting lasts %d minutes", meeting.EstimatedDuration))
+	}
+	if slot.StartTime.Equal(meeting.ConfirmedSlot.StartTime) && slot.EndTime.Equal(meeting.ConfirmedSlot.EndTime) {
+		return models.Proposal{}, errors.NewValidationError("Slot is the confirmed slot", "")
+	}
+	for _, existing := range meeting.Proposals {
+		if existing.ParticipantID == participantID && existing.Status == models.ProposalPending {
+			return models.Proposal{}, errors.NewConflictError("Participant already has a proposal waiting for a decision")
+		}
+	}
+
+	proposal := models.Proposal{
+		ID:            uuid.New().String(),
+		ParticipantID: participantID,
+		Slot:          models.TimeSlot{StartTime: slot.StartTime, EndTime: slot.EndTime},
+		Reason:        reason,
+		Status:        models.ProposalPending,
+		CreatedAt:     time.Now(),
+	}
+	meeting.Proposals = append(append([]models.Proposal(nil), meeting.Proposals...), proposal)
+	updatedMeeting, err := s.repository.UpdateMeeting(meeting)
+	if err != nil {
+		return models.Proposal{}, err
+	}
+
+	participant := participantOf(updatedMeeting, participantID)
+	s.recordTimeline(meetingID, models.TimelineAlternativeProposed, participantID, participant.Name+" proposed "+formatSlot(proposal.Slot))
+	s.notifyProposal(updatedMeeting, participant, proposal)
+	return proposal, nil
+}
+
+// DecideProposal records the organizer's decision on a pending proposal and notifies the
+// participant who made it. Accepting reschedules the meeting to the proposed slot, which
+// is published as a meeting.rescheduled event and emailed to the other participants.
+func (s *MeetingServiceImpl) DecideProposal(meetingID, proposalID string, accept bool) (models.Meeting, models.Proposal, error) {
+	meeting, err := s.repository.GetMeetingByID(meetingID)
+	if err != nil {
+		return models.Meeting{}, models.Proposal{}, err
+	}
+
+	index := -1
+	for i, proposal := range meeting.Proposals {
+		if proposal.ID == proposalID {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return models.Meeting{}, models.Proposal{}, errors.NewNotFoundError("Proposal not found")
+	}
+	if meeting.Proposals[index].Status != models.ProposalPending {
+		return models.Meeting{}, models.Proposal{}, errors.NewConflictError("Proposal was already decided")
+	}
+	if meeting.Status != models.MeetingStatusConfirmed {
+		return models.Meeting{}, models.Proposal{}, errors.NewConflictError("Meeting is no longer confirmed")
+	}
+
+	// Copy the proposals so the stored meeting is only changed by the update
+	now := time.Now()
+	meeting.Proposals = append([]models.Proposal(nil), meeting.Proposals...)
+	proposal := &meeting.Proposals[index]
+	proposal.DecidedAt = &now
+	proposal.Status = models.ProposalDeclined
+	if accept {
+		proposal.Status = models.ProposalAccepted
+		slot := proposal.Slot
+		meeting.ConfirmedSlot = &slot
+	}
+	updatedMeeting, err := s.repository.UpdateMeeting(meeting)
+	if err != nil {
+		return models.Meeting{}, models.Proposal{}, err
+	}
+
+	decided := updatedMeeting.Proposals[index]
+	participant := participantOf(updatedMeeting, decided.ParticipantID)
+	if !accept {
+		s.recordTimeline(meetingID, models.TimelineAlternativeDeclined, meeting.OrganizerID, "Proposal of "+formatSlot(decided.Slot)+" by "+participant.Name+" was declined")
+		s.notifyProposalDecision(updatedMeeting, participant, decided)
+		return updatedMeeting, decided, nil
+	}
+
+	s.sloTracker.RecordReschedule()
+	s.refreshRecommendations(meetingID)
+	s.publish(events.MeetingRescheduled, meetingID, updatedMeeting)
+	s.recordTimeline(meetingID, models.TimelineMeetingRescheduled, meeting.OrganizerID, "Meeting moved to "+formatSlot(decided.Slot)+" as proposed by "+participant.Name)
+	s.notifyProposalDecision(updatedMeeting, participant, decided)
+
+	// Everyone else learns the meeting moved
+	var others []models.User
+	for _, other := range updatedMeeting.Participants {
+		if other.ID != participant.ID {
+			others = append(others, other)
+		}
+	}
+	s.sendFinalization(updatedMeeting, others, participant.Name)
+	return updatedMeeting, decided, nil
+}
+
+// participantOf returns the participant of meeting with userID, or a user with only the
+// ID if they are no longer a participant
+func participantOf(meeting models.Meeting, userID string) models.User {
+	for _, participant := range meeting.Participants {
+		if participant.ID == userID {
+			return participant
+		}
+	}
+	return models.User{ID: userID, Name: userID}
+}
+
+// notifyProposal tells the organizer of meeting about a proposal
+func (s *MeetingServiceImpl) notifyProposal(meeting models.Meeting, participant models.User, proposal models.Proposal) {
+	organizer, err := s.userService.GetUserByID(meeting.OrganizerID)
+	if err != nil {
+		logs.Warn("Failed to look up organizer %s of meeting %s: %v", meeting.OrganizerID, meeting.ID, err)
+		return
+	}
+
+	message, err := s.templates.Render(notifications.KindProposal, notifications.ProposalData{
+		Recipient:      organizer.Name,
+		Participant:    participant.Name,
+		Title:          meeting.Title,
+		MeetingID:      meeting.ID,
+		ProposalID:     proposal.ID,
+		Reason:         proposal.Reason,
+		ConfirmedStart: meeting.ConfirmedSlot.StartTime,
+		ConfirmedEnd:   meeting.ConfirmedSlot.EndTime,
+		ProposedStart:  proposal.Slot.StartTime,
+		ProposedEnd:    proposal.Slot.EndTime,
+	})
+	if err != nil {
+		logs.Warn("Failed to render proposal %s of meeting %s for user %s: %v", proposal.ID, meeting.ID, organizer.ID, err)
+		return
+	}
+
+	notification := notifications.Notification{
+		Kind:    notifications.KindProposal,
+		UserID:  organizer.ID,
+		To:      organizer.Email,
+		Subject: message.Subject,
+		Body:    message.Text,
+		HTML:    message.HTML,
+	}
+	if err := s.notifier.Send(notification); err != nil {
+		logs.Warn("Failed to send proposal %s of meeting %s to user %s: %v", proposal.ID, meeting.ID, organizer.ID, err)
+	}
+}
+
+// notifyProposalDecision tells the participant who made a proposal about the decision
+func (s *MeetingServiceImpl) notifyProposalDecision(meeting models.Meeting, participant models.User, proposal models.Proposal) {
+	if participant.Email == "" {
+		return
+	}
+
+	message, err := s.templates.Render(notifications.KindProposalDecision, notifications.ProposalDecisionData{
+		Recipient:      participant.Name,
+		Title:          meeting.Title,
+		MeetingID:      meeting.ID,
+		Accepted:       proposal.Status == models.ProposalAccepted,
+		ConfirmedStart: meeting.ConfirmedSlot.StartTime,
+		ConfirmedEnd:   meeting.ConfirmedSlot.EndTime,
+		ProposedStart:  proposal.Slot.StartTime,
+		ProposedEnd:    proposal.Slot.EndTime,
+	})
+	if err != nil {
+		logs.Warn("Failed to render decision on proposal %s of meeting %s for user %s: %v", proposal.ID, meeting.ID, participant.ID, err)
+		return
+	}
+
+	notification := notifications.Notification{
+		Kind:    notifications.KindProposalDecision,
+		UserID:  participant.ID,
+		To:      participant.Email,
+		Subject: message.Subject,
+		Body:    message.Text,
+		HTML:    message.HTML,
+	}
+	if err := s.notifier.Send(notification); err != nil {
+		logs.Warn("Failed to send decision on proposal %s of meeting %s to user %s: %v", proposal.ID, meeting.ID, participant.ID, err)
+	}
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"meetsync/internal/events"
+	"meetsync/internal/models"
+	"meetsync/internal/notifications"
+	"meetsync/pkg/errors"
+)
+
+func TestMeetingService_Proposals(t *testing.T) {
+	userService := NewUserService()
+	organizer, err := userService.CreateUser("Organizer", "organizer@example.com")
+	require.NoError(t, err)
+	participant, err := userService.CreateUser("Participant", "participant@example.com")
+	require.NoError(t, err)
+	other, err := userService.CreateUser("Other", "other@example.com")
+	require.NoError(t, err)
+	notifier := &recordingNotifier{}
+	publisher := &recordingPublisher{}
+	service := NewMeetingService(userService, WithNotifier(notifier), WithEventPublisher(publisher))
+	timeSlots := createTestTimeSlots()
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		ParticipantIDs:    []string{participant.ID, other.ID},
+	})
+	require.NoError(t, err)
+	_, err = service.AddAvailability(organizer.ID, meeting.ID, timeSlots[:1], false)
+	require.NoError(t, err)
+
+	alternative := models.TimeSlot{StartTime: timeSlots[0].StartTime.Add(2 * time.Hour), EndTime: timeSlots[0].EndTime.Add(2 * time.Hour)}
+
+	// Alternatives are only proposed once the meeting is confirmed
+	_, err = service.ProposeAlternative(meeting.ID, participant.ID, alternative, "")
+	assert.True(t, errors.Is(err, errors.ErrValidation))
+	_, err = service.ConfirmMeeting(meeting.ID, timeSlots[0])
+	require.NoError(t, err)
+
+	_, err = service.ProposeAlternative(meeting.ID, "someone-else", alternative, "")
+	assert.True(t, errors.Is(err, errors.ErrValidation))
+	_, err = service.ProposeAlternative(meeting.ID, participant.ID, models.TimeSlot{StartTime: alternative.StartTime, EndTime: alternative.StartTime.Add(30 * time.Minute)}, "")
+	assert.True(t, errors.Is(err, errors.ErrValidation), "shorter than the meeting")
+	_, err = service.ProposeAlternative(meeting.ID, participant.ID, timeSlots[0], "")
+	assert.True(t, errors.Is(err, errors.ErrValidation), "the confirmed slot")
+
+	notifier.notifications = nil
+	declined, err := service.ProposeAlternative(meeting.ID, participant.ID, alternative, "Dentist <b>appointment</b>")
+	require.NoError(t, err)
+	assert.Equal(t, models.ProposalPending, declined.Status)
+	assert.Equal(t, "Dentist appointment", declined.Reason)
+	_, err = service.ProposeAlternative(meeting.ID, participant.ID, alternative, "")
+	assert.True(t, errors.Is(err, errors.ErrConflict), "one pending proposal per participant")
+
+	// The organizer is told about the proposal
+	require.Len(t, notifier.notifications, 1)
+	assert.Equal(t, notifications.KindProposal, notifier.notifications[0].Kind)
+	assert.Equal(t, organizer.Email, notifier.notifications[0].To)
+	assert.Contains(t, notifier.notifications[0].Body, "Dentist appointment")
+
+	// Declining keeps the confirmed slot and tells the participant
+	updated, decided, err := service.DecideProposal(meeting.ID, declined.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, models.ProposalDeclined, decided.Status)
+	assert.NotNil(t, decided.DecidedAt)
+	assert.True(t, timeSlots[0].StartTime.Equal(updated.ConfirmedSlot.StartTime))
+	require.Len(t, notifier.notifications, 2)
+	assert.Equal(t, notifications.KindProposalDecision, notifier.notifications[1].Kind)
+	assert.Equal(t, participant.Email, notifier.notifications[1].To)
+	assert.Contains(t, notifier.notifications[1].Subject, "declined")
+
+	_, _, err = service.DecideProposal(meeting.ID, declined.ID, true)
+	assert.True(t, errors.Is(err, errors.ErrConflict), "already decided")
+	_, _, err = service.DecideProposal(meeting.ID, "missing", true)
+	assert.True(t, errors.Is(err, errors.ErrNotFound))
+
+	// Accepting reschedules the meeting to the proposed slot
+	accepted, err := service.ProposeAlternative(meeting.ID, participant.ID, alternative, "")
+	require.NoError(t, err)
+	notifier.notifications = nil
+	changed := service.(*MeetingServiceImpl).changes.wait(meeting.ID)
+	updated, decided, err = service.DecideProposal(meeting.ID, accepted.ID, true)
+	require.NoError(t, err)
+	assert.Equal(t, models.ProposalAccepted, decided.Status)
+	assert.Equal(t, models.MeetingStatusConfirmed, updated.Status)
+	assert.True(t, alternative.StartTime.Equal(updated.ConfirmedSlot.StartTime))
+	assert.Len(t, updated.Proposals, 2)
+
+	// The proposer learns the decision, everyone else that the meeting moved
+	require.Len(t, notifier.notifications, 2)
+	assert.Equal(t, participant.ID, notifier.notifications[0].UserID)
+	assert.Contains(t, notifier.notifications[0].Subject, "accepted")
+	assert.Equal(t, other.ID, notifier.notifications[1].UserID)
+	assert.Equal(t, notifications.KindFinalization, notifier.notifications[1].Kind)
+	assert.Equal(t, "Meeting moved: Test Meeting", notifier.notifications[1].Subject)
+	assert.Contains(t, notifier.notifications[1].Body, "proposed by Participant")
+
+	// Consumers and waiting readers learn about the change
+	last := publisher.events[len(publisher.events)-1]
+	assert.Equal(t, events.MeetingRescheduled, last.Type)
+	assert.Equal(t, meeting.ID, last.MeetingID)
+	select {
+	case <-changed:
+	default:
+		t.Error("readers waiting for the meeting to change were not woken up")
+	}
+
+	timeline, err := service.GetMeetingTimeline(meeting.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.TimelineMeetingRescheduled, timeline[len(timeline)-1].Type)
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"time"
+
+	"meetsync/internal/interfaces"
+	"meetsync/internal/models"
+	"meetsync/internal/notifications"
+	"meetsync/pkg/errors"
+)
+
+// SetDoNotDisturb sets the daily quiet hours of a user and snoozes their notifications
+// until snoozedUntil; nil clears either. Only urgent notifications reach the user during
+// quiet hours or while snoozed, the others are delivered when they end.
+func (s *UserServiceImpl) SetDoNotDisturb(userID string, quietHours *models.QuietHours, snoozedUntil *time.Time) (models.User, error) {
+	if quietHours != nil {
+		if _, _, _, err := compileQuietHours(*quietHours); err != nil {
+			return models.User{}, err
+		}
+	}
+	if snoozedUntil != nil && !snoozedUntil.After(time.Now()) {
+		return models.User{}, errors.NewValidationError("Snooze must end in the future", "")
+	}
+
+	user, err := s.repository.GetByID(userID)
+	if err != nil {
+		return models.User{}, err
+	}
+	user.QuietHours = quietHours
+	user.SnoozedUntil = snoozedUntil
+	return s.repository.Update(user)
+}
+
+// QuietHoursOf tells the notification dispatcher when users looked up in users do not
+// want to be disturbed
+func QuietHoursOf(users interfaces.UserService) notifications.QuietHoursFunc {
+	return func(userID string, t time.Time) (time.Time, bool) {
+		user, err := users.GetUserByID(userID)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return quietUntil(user, t)
+	}
+}
+
+// quietUntil reports whether user is snoozed or in their quiet hours at t, and if so when
+// they can be notified again. Quiet hours starting while snoozed extend the snooze.
+func quietUntil(user models.User, t time.Time) (time.Time, bool) {
+	until, quiet := t, false
+	if user.SnoozedUntil != nil && t.Before(*user.SnoozedUntil) {
+		until, quiet = *user.SnoozedUntil, true
+	}
+	if user.QuietHours != nil {
+		if end, inHours := quietHoursEnd(*user.QuietHours, until); inHours {
+			until, quiet = end, true
+		}
+	}
+	if !quiet {
+		return time.Time{}, false
+	}
+	return until, true
+}
+
+// quietHoursEnd reports whether t falls in the quiet hours, and if so when they end
+func quietHoursEnd(hours models.QuietHours, t time.Time) (time.Time, bool) {
+	start, end, location, err := compileQuietHours(hours)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	local := t.In(location)
+	clock := local.Hour()*60 + local.Minute()
+	endOn := func(days int) time.Time {
+		return time.Date(local.Year(), local.Month(), local.Day()+days, end/60, end%60, 0, 0, location)
+	}
+	switch {
+	case start < end && clock >= start && clock < end:
+		return endOn(0), true
+	case start > end && clock >= start:
+		return endOn(1), true
+	case start > end && clock < end:
+		return endOn(0), true
+	}
+	return time.Time{}, false
+}
+
+// compileQuietHours validates quiet hours, returning their start and end in minutes after
+// midnight and their time zone
+func compileQuietHours(hours models.QuietHours) (int, int, *time.Location, error) {
+	start, err := time.Parse("15:04", hours.Start)
+	if err != nil {
+		return 0, 0, nil, errors.NewValidationError("Invalid quiet hours start", "use HH:MM")
+	}
+	end, err := time.Parse("15:04", hours.End)
+	if err != nil {
+		return 0, 0, nil, errors.NewValidationError("Invalid quiet hours end", "use HH:MM")
+	}
+	if start.Equal(end) {
+		return 0, 0, nil, errors.NewValidationError("Quiet hours must not start and end at the same time", "")
+	}
+	location, err := loadTimeZone(hours.TimeZone)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	return start.Hour()*60 + start.Minute(), end.Hour()*60 + end.Minute(), location, nil
+}
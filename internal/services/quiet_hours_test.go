@@ -0,0 +1,86 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"meetsync/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuietUntil(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	assert.NoError(t, err)
+	night := &models.QuietHours{Start: "22:00", End: "07:00", TimeZone: "Europe/Paris"}
+	lunch := &models.QuietHours{Start: "12:00", End: "13:30"}
+	snooze := time.Date(2026, 3, 10, 21, 30, 0, 0, paris)
+
+	tests := []struct {
+		name      string
+		user      models.User
+		at        time.Time
+		wantQuiet bool
+		wantUntil time.Time
+	}{
+		{name: "no quiet hours", user: models.User{}, at: time.Date(2026, 3, 10, 23, 0, 0, 0, paris)},
+		{name: "before overnight hours", user: models.User{QuietHours: night}, at: time.Date(2026, 3, 10, 21, 59, 0, 0, paris)},
+		{name: "evening", user: models.User{QuietHours: night}, at: time.Date(2026, 3, 10, 23, 0, 0, 0, paris),
+			wantQuiet: true, wantUntil: time.Date(2026, 3, 11, 7, 0, 0, 0, paris)},
+		{name: "early morning", user: models.User{QuietHours: night}, at: time.Date(2026, 3, 11, 6, 59, 0, 0, paris),
+			wantQuiet: true, wantUntil: time.Date(2026, 3, 11, 7, 0, 0, 0, paris)},
+		{name: "end of overnight hours", user: models.User{QuietHours: night}, at: time.Date(2026, 3, 11, 7, 0, 0, 0, paris)},
+		{name: "daytime hours in UTC", user: models.User{QuietHours: lunch}, at: time.Date(2026, 3, 10, 12, 15, 0, 0, time.UTC),
+			wantQuiet: true, wantUntil: time.Date(2026, 3, 10, 13, 30, 0, 0, time.UTC)},
+		{name: "snoozed", user: models.User{SnoozedUntil: &snooze}, at: time.Date(2026, 3, 10, 20, 0, 0, 0, paris),
+			wantQuiet: true, wantUntil: snooze},
+		{name: "snooze ending in quiet hours", user: models.User{SnoozedUntil: &snooze, QuietHours: &models.QuietHours{Start: "21:00", End: "22:00", TimeZone: "Europe/Paris"}},
+			at: time.Date(2026, 3, 10, 20, 0, 0, 0, paris), wantQuiet: true, wantUntil: time.Date(2026, 3, 10, 22, 0, 0, 0, paris)},
+		{name: "snooze over", user: models.User{SnoozedUntil: &snooze}, at: snooze},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			until, quiet := quietUntil(tt.user, tt.at)
+			assert.Equal(t, tt.wantQuiet, quiet)
+			if tt.wantQuiet {
+				assert.True(t, until.Equal(tt.wantUntil), "expected quiet until %s, got %s", tt.wantUntil, until)
+			}
+		})
+	}
+}
+
+func TestUserService_SetDoNotDisturb(t *testing.T) {
+	service := NewUserService()
+	user, err := service.CreateUser("Night Owl", "owl@example.com")
+	assert.NoError(t, err)
+
+	hours := &models.QuietHours{Start: "22:00", End: "07:00", TimeZone: "Europe/Paris"}
+	snoozedUntil := time.Now().Add(time.Hour)
+	updated, err := service.SetDoNotDisturb(user.ID, hours, &snoozedUntil)
+	assert.NoError(t, err)
+	assert.Equal(t, hours, updated.QuietHours)
+	assert.True(t, updated.SnoozedUntil.Equal(snoozedUntil))
+
+	_, quiet := QuietHoursOf(service)(user.ID, time.Now())
+	assert.True(t, quiet)
+
+	cleared, err := service.SetDoNotDisturb(user.ID, nil, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, cleared.QuietHours)
+	assert.Nil(t, cleared.SnoozedUntil)
+
+	for _, invalid := range []models.QuietHours{
+		{Start: "22:00", End: "22:00"},
+		{Start: "10pm", End: "07:00"},
+		{Start: "22:00", End: "07:00", TimeZone: "Mars/Olympus"},
+	} {
+		_, err := service.SetDoNotDisturb(user.ID, &invalid, nil)
+		assert.Error(t, err)
+	}
+	past := time.Now().Add(-time.Minute)
+	_, err = service.SetDoNotDisturb(user.ID, nil, &past)
+	assert.Error(t, err)
+	_, err = service.SetDoNotDisturb("missing", nil, nil)
+	assert.Error(t, err)
+}
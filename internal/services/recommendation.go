@@ -0,0 +1,363 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+	"meetsync/pkg/logs"
+)
+
+// maxWindowCandidates caps the sub-slots a proposed window is split into, bounding the
+// work of recommendations for windows stored before their length was validated and for
+// fine granularities
+const maxWindowCandidates = 2000
+
+// candidateSlots returns the slots a meeting can be scheduled in. Proposed windows longer
+// than the meeting are split into sub-slots of the meeting's duration starting at every
+// multiple of granularity on the wall clock of the meeting's time zone, up to
+// maxWindowCandidates per window; a granularity of zero keeps every window as a single slot.
+func candidateSlots(meeting models.Meeting, granularity time.Duration) []models.TimeSlot {
+	duration := time.Duration(meeting.EstimatedDuration) * time.Minute
+	location, err := loadTimeZone(meeting.TimeZone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	candidates := make([]models.TimeSlot, 0, len(meeting.ProposedSlots))
+	for _, window := range meeting.ProposedSlots {
+		if granularity <= 0 || duration <= 0 || window.EndTime.Sub(window.StartTime) <= duration {
+			candidates = append(candidates, window)
+			continue
+		}
+
+		start := alignToWallClock(window.StartTime, granularity, location)
+
+		split := 0
+		for ; split < maxWindowCandidates && !start.Add(duration).After(window.EndTime); start = start.Add(granularity) {
+			candidates = append(candidates, models.TimeSlot{
+				ID:        fmt.Sprintf("%s+%dm", window.ID, int(start.Sub(window.StartTime).Minutes())),
+				StartTime: start,
+				EndTime:   start.Add(duration),
+			})
+			split++
+		}
+
+		// A window too short to hold an aligned sub-slot is offered as proposed
+		if split == 0 {
+			candidates = append(candidates, window)
+		}
+	}
+	return candidates
+}
+
+// alignToWallClock returns the first instant at or after t whose wall-clock time in
+// location is a multiple of granularity, so sub-slots start at 9:00, 9:15, ... even in
+// zones with a fractional UTC offset
+func alignToWallClock(t time.Time, granularity time.Duration, location *time.Location) time.Time {
+	_, offset := t.In(location).Zone()
+	shift := time.Duration(offset) * time.Second
+
+	aligned := t.Add(shift).Truncate(granularity).Add(-shift)
+	if aligned.Before(t) {
+		aligned = aligned.Add(granularity)
+	}
+	return aligned
+}
+
+// longestFeasibleDuration returns how many minutes, up to the meeting's maximum duration,
+// a meeting starting at candidate can last within its proposed window while every one
+// of the available participants stays available
+func longestFeasibleDuration(meeting models.Meeting, candidate models.TimeSlot, available []models.User, availableSlots map[string][]models.TimeSlot) int {
+	end := candidate.StartTime.Add(time.Duration(meeting.MaxDuration) * time.Minute)
+	for _, window := range meeting.ProposedSlots {
+		if covers(window, candidate) {
+			if window.EndTime.Before(end) {
+				end = window.EndTime
+			}
+			break
+		}
+	}
+	for _, participant := range available {
+		if until := availableUntil(availableSlots[participant.ID], candidate.StartTime); until.Before(end) {
+			end = until
+		}
+	}
+	return int(end.Sub(candidate.StartTime) / time.Minute)
+}
+
+// availableUntil returns when the run of back-to-back or overlapping slots that
+// includes start ends
+func availableUntil(slots []models.TimeSlot, start time.Time) time.Time {
+	until := start
+	for extended := true; extended; {
+		extended = false
+		for _, slot := range slots {
+			if !slot.StartTime.After(until) && slot.EndTime.After(until) {
+				until = slot.EndTime
+				extended = true
+			}
+		}
+	}
+	return until
+}
+
+// covers reports whether slot spans the whole of candidate
+func covers(slot, candidate models.TimeSlot) bool {
+	return !slot.StartTime.After(candidate.StartTime) && !slot.EndTime.Before(candidate.EndTime)
+}
+
+// calculateRecommendations ranks the candidate slots of a meeting by how many of its
+// organizer and participants are available for them. It gives up with a timeout error
+// once ctx is done, so callers can bound computations for meetings with many slots.
+func (s *MeetingServiceImpl) calculateRecommendations(ctx context.Context, meeting models.Meeting, availabilities []models.Availability) ([]models.RecommendedSlot, error) {
+	candidates := candidateSlots(meeting, s.slotGranularity)
+	location, err := loadTimeZone(meeting.TimeZone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	// Only availability of the organizer and participants counts
+	allParticipants := append([]models.User{*meeting.Organizer}, meeting.Participants...)
+	focus, err := s.focusRules(allParticipants)
+	if err != nil {
+		logs.Warn("Failed to look up focus time for meeting %s: %v", meeting.ID, err)
+	}
+	competing, err := s.competingSlots(meeting, allParticipants)
+	if err != nil {
+		logs.Warn("Failed to look up competing meetings for meeting %s: %v", meeting.ID, err)
+	}
+	availableSlots := make(map[string][]models.TimeSlot, len(allParticipants))
+	for _, participant := range allParticipants {
+		availableSlots[participant.ID] = nil
+	}
+	tentative := make(map[string]bool)
+	for _, availability := range availabilities {
+		if _, isParticipant := availableSlots[availability.ParticipantID]; isParticipant {
+			availableSlots[availability.ParticipantID] = append(availableSlots[availability.ParticipantID], availability.AvailableSlots...)
+			if availability.Tentative {
+				tentative[availability.ParticipantID] = true
+			}
+		}
+	}
+
+	recommendations := make([]models.RecommendedSlot, 0, len(candidates))
+	for _, candidate := range candidates {
+		if err := ctx.Err(); err != nil {
+			return nil, errors.NewTimeoutError("Recommendations could not be computed before the request deadline", err)
+		}
+
+		recommendation := models.RecommendedSlot{
+			TimeSlot:                candidate,
+			TotalParticipants:       len(allParticipants),
+			UnavailableParticipants: []models.User{},
+		}
+
+		var available []models.User
+		var attendeeIDs []string
+		for _, participant := range allParticipants {
+			isAvailable := false
+			for _, slot := range availableSlots[participant.ID] {
+				if covers(slot, candidate) {
+					isAvailable = true
+					break
+				}
+			}
+			if isAvailable && inFocusTime(focus[participant.ID], candidate) {
+				recommendation.FocusTimeConflicts = append(recommendation.FocusTimeConflicts, participant)
+				isAvailable = meeting.OverrideFocusTime
+			}
+			if isAvailable {
+				recommendation.Conflicts = append(recommendation.Conflicts, conflictsFor(participant.ID, competing[participant.ID], candidate)...)
+				recommendation.AvailableCount++
+				if tentative[participant.ID] {
+					recommendation.TentativeCount++
+				} else {
+					recommendation.ConfirmedCount++
+				}
+				available = append(available, participant)
+				attendeeIDs = append(attendeeIDs, participant.ID)
+			} else {
+				recommendation.UnavailableParticipants = append(recommendation.UnavailableParticipants, participant)
+			}
+		}
+		if meeting.MaxDuration > meeting.EstimatedDuration {
+			recommendation.LongestFeasibleDuration = longestFeasibleDuration(meeting, candidate, available, availableSlots)
+		}
+		recommendation.PolicyViolations = s.policies.Evaluate(candidate, location, attendeeIDs, nil)
+		recommendations = append(recommendations, recommendation)
+	}
+
+	sortRecommendations(recommendations)
+	return recommendations, nil
+}
+
+// sortRecommendations orders recommendations by available count in descending order,
+// then by fewest conflicting meetings, fewest policy violations and by how long the
+// meeting can run, earliest first on remaining ties
+func sortRecommendations(recommendations []models.RecommendedSlot) {
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		if recommendations[i].AvailableCount != recommendations[j].AvailableCount {
+			return recommendations[i].AvailableCount > recommendations[j].AvailableCount
+		}
+		if len(recommendations[i].Conflicts) != len(recommendations[j].Conflicts) {
+			return len(recommendations[i].Conflicts) < len(recommendations[j].Conflicts)
+		}
+		if len(recommendations[i].PolicyViolations) != len(recommendations[j].PolicyViolations) {
+			return len(recommendations[i].PolicyViolations) < len(recommendations[j].PolicyViolations)
+		}
+		if recommendations[i].LongestFeasibleDuration != recommendations[j].LongestFeasibleDuration {
+			return recommendations[i].LongestFeasibleDuration > recommendations[j].LongestFeasibleDuration
+		}
+		return recommendations[i].TimeSlot.StartTime.Before(recommendations[j].TimeSlot.StartTime)
+	})
+}
+
+// diffRecommendations lists the slots whose recommendation differs between current and
+// simulated, matching slots by their times since sub-slot IDs depend on the proposed windows.
+// Changed and added slots come in simulated order, followed by removed slots in current order.
+func diffRecommendations(current, simulated []models.RecommendedSlot) []models.RecommendationChange {
+	type slotTimes struct{ start, end int64 }
+	key := func(slot models.TimeSlot) slotTimes {
+		return slotTimes{slot.StartTime.UnixNano(), slot.EndTime.UnixNano()}
+	}
+
+	before := make(map[slotTimes]models.RecommendedSlot, len(current))
+	for _, recommendation := range current {
+		before[key(recommendation.TimeSlot)] = recommendation
+	}
+
+	changes := []models.RecommendationChange{}
+	seen := make(map[slotTimes]bool, len(simulated))
+	for _, after := range simulated {
+		k := key(after.TimeSlot)
+		seen[k] = true
+		change := models.RecommendationChange{
+			TimeSlot:                   after.TimeSlot,
+			SimulatedAvailableCount:    after.AvailableCount,
+			SimulatedTotalParticipants: after.TotalParticipants,
+		}
+
+		previous, existed := before[k]
+		switch {
+		case !existed:
+			change.Kind = models.RecommendationAdded
+		case previous.AvailableCount != after.AvailableCount ||
+			previous.TotalParticipants != after.TotalParticipants ||
+			previous.LongestFeasibleDuration != after.LongestFeasibleDuration:
+			change.Kind = models.RecommendationChanged
+			change.CurrentAvailableCount = previous.AvailableCount
+			change.CurrentTotalParticipants = previous.TotalParticipants
+		default:
+			continue
+		}
+		changes = append(changes, change)
+	}
+
+	for _, previous := range current {
+		if seen[key(previous.TimeSlot)] {
+			continue
+		}
+		changes = append(changes, models.RecommendationChange{
+			TimeSlot:                 previous.TimeSlot,
+			Kind:                     models.RecommendationRemoved,
+			CurrentAvailableCount:    previous.AvailableCount,
+			CurrentTotalParticipants: previous.TotalParticipants,
+		})
+	}
+	return changes
+}
+
+// maxSplitRecommendations caps the number of session pairs suggested for a meeting
+const maxSplitRecommendations = 5
+
+// maxSplitCandidates caps the recommended slots paired into sessions, keeping those the
+// most participants are available for, since every pair of them is considered
+const maxSplitCandidates = 200
+
+// suggestSplits pairs non-overlapping recommended slots into two sessions that together
+// reach every participant, for meetings where no single slot does. The organizer must be
+// available for both sessions. Pairs are ranked by how many participants can attend both.
+func suggestSplits(meeting models.Meeting, recommendations []models.RecommendedSlot) []models.SplitRecommendation {
+	total := len(meeting.Participants) + 1
+	for _, recommendation := range recommendations {
+		if recommendation.AvailableCount == total {
+			return nil
+		}
+	}
+
+	unavailable := make([]map[string]bool, len(recommendations))
+	candidates := make([]int, 0, len(recommendations))
+	for i, recommendation := range recommendations {
+		unavailable[i] = make(map[string]bool, len(recommendation.UnavailableParticipants))
+		for _, user := range recommendation.UnavailableParticipants {
+			unavailable[i][user.ID] = true
+		}
+		if !unavailable[i][meeting.OrganizerID] {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) > maxSplitCandidates {
+		sort.SliceStable(candidates, func(a, b int) bool {
+			return recommendations[candidates[a]].AvailableCount > recommendations[candidates[b]].AvailableCount
+		})
+		candidates = candidates[:maxSplitCandidates]
+		sort.Ints(candidates)
+	}
+
+	// ranksBefore reports whether split a ranks before split b
+	ranksBefore := func(a, b models.SplitRecommendation) bool {
+		if a.AttendingBoth != b.AttendingBoth {
+			return a.AttendingBoth > b.AttendingBoth
+		}
+		return a.Sessions[0].StartTime.Before(b.Sessions[0].StartTime)
+	}
+
+	// Only the best pairs are kept while scanning, in rank order; pairs ranking the same
+	// stay in the order they were found
+	allParticipants := append([]models.User{*meeting.Organizer}, meeting.Participants...)
+	splits := make([]models.SplitRecommendation, 0, maxSplitRecommendations+1)
+	for _, i := range candidates {
+		for _, j := range candidates {
+			first, second := recommendations[i].TimeSlot, recommendations[j].TimeSlot
+			if !first.StartTime.Before(second.StartTime) || second.StartTime.Before(first.EndTime) {
+				continue
+			}
+
+			split := models.SplitRecommendation{
+				Sessions:          []models.TimeSlot{first, second},
+				TotalParticipants: total,
+			}
+			for _, participant := range allParticipants {
+				inFirst, inSecond := !unavailable[i][participant.ID], !unavailable[j][participant.ID]
+				if inFirst || inSecond {
+					split.CoveredCount++
+				}
+				if inFirst && inSecond {
+					split.AttendingBoth++
+				}
+			}
+			if split.CoveredCount != total {
+				continue
+			}
+
+			at := sort.Search(len(splits), func(k int) bool { return ranksBefore(split, splits[k]) })
+			if at == maxSplitRecommendations {
+				continue
+			}
+			splits = append(splits, models.SplitRecommendation{})
+			copy(splits[at+1:], splits[at:])
+			splits[at] = split
+			if len(splits) > maxSplitRecommendations {
+				splits = splits[:maxSplitRecommendations]
+			}
+		}
+	}
+	if len(splits) == 0 {
+		return nil
+	}
+	return splits
+}
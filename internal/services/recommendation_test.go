@@ -0,0 +1,440 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"meetsync/internal/models"
+	"meetsync/internal/policy"
+	"meetsync/pkg/errors"
+)
+
+func TestCandidateSlots(t *testing.T) {
+	start := time.Date(2025, 1, 14, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		window         models.TimeSlot
+		duration       int
+		granularity    time.Duration
+		expectedStarts []time.Time
+	}{
+		{
+			name:           "window matching the duration is kept",
+			window:         models.TimeSlot{ID: "w", StartTime: start, EndTime: start.Add(time.Hour)},
+			duration:       60,
+			granularity:    15 * time.Minute,
+			expectedStarts: []time.Time{start},
+		},
+		{
+			name:        "long window is split at the granularity",
+			window:      models.TimeSlot{ID: "w", StartTime: start, EndTime: start.Add(time.Hour)},
+			duration:    30,
+			granularity: 15 * time.Minute,
+			expectedStarts: []time.Time{
+				start,
+				start.Add(15 * time.Minute),
+				start.Add(30 * time.Minute),
+			},
+		},
+		{
+			name:        "sub-slots are aligned to the granularity",
+			window:      models.TimeSlot{ID: "w", StartTime: start.Add(5 * time.Minute), EndTime: start.Add(time.Hour)},
+			duration:    30,
+			granularity: 15 * time.Minute,
+			expectedStarts: []time.Time{
+				start.Add(15 * time.Minute),
+				start.Add(30 * time.Minute),
+			},
+		},
+		{
+			name:           "window without an aligned sub-slot is kept",
+			window:         models.TimeSlot{ID: "w", StartTime: start.Add(5 * time.Minute), EndTime: start.Add(40 * time.Minute)},
+			duration:       30,
+			granularity:    15 * time.Minute,
+			expectedStarts: []time.Time{start.Add(5 * time.Minute)},
+		},
+		{
+			name:           "zero granularity disables splitting",
+			window:         models.TimeSlot{ID: "w", StartTime: start, EndTime: start.Add(4 * time.Hour)},
+			duration:       30,
+			granularity:    0,
+			expectedStarts: []time.Time{start},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meeting := models.Meeting{EstimatedDuration: tt.duration, ProposedSlots: []models.TimeSlot{tt.window}}
+			candidates := candidateSlots(meeting, tt.granularity)
+
+			require.Len(t, candidates, len(tt.expectedStarts))
+			for i, candidate := range candidates {
+				assert.True(t, tt.expectedStarts[i].Equal(candidate.StartTime))
+				assert.NotEmpty(t, candidate.ID)
+				if len(candidates) > 1 {
+					assert.Equal(t, time.Duration(tt.duration)*time.Minute, candidate.EndTime.Sub(candidate.StartTime))
+				}
+			}
+		})
+	}
+}
+
+func TestMeetingService_RecommendationsForSplitWindow(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+	WithSlotGranularity(15 * time.Minute)(service)
+
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	window := models.TimeSlot{StartTime: start, EndTime: start.Add(4 * time.Hour)}
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Planning",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 30,
+		ProposedSlots:     []models.TimeSlot{window},
+		ParticipantIDs:    []string{participants[0].ID, participants[1].ID},
+	})
+	require.NoError(t, err)
+
+	// Everyone is free for the first hour; one participant only for its second half
+	_, err = service.AddAvailability(organizer.ID, meeting.ID, []models.TimeSlot{window}, false)
+	require.NoError(t, err)
+	_, err = service.AddAvailability(participants[0].ID, meeting.ID, []models.TimeSlot{{StartTime: start, EndTime: start.Add(time.Hour)}}, false)
+	require.NoError(t, err)
+	_, err = service.AddAvailability(participants[1].ID, meeting.ID, []models.TimeSlot{{StartTime: start.Add(30 * time.Minute), EndTime: start.Add(2 * time.Hour)}}, false)
+	require.NoError(t, err)
+
+	// Parts of a window shorter than the meeting are rejected
+	_, err = service.AddAvailability(participants[1].ID, meeting.ID, []models.TimeSlot{{StartTime: start, EndTime: start.Add(15 * time.Minute)}}, false)
+	assert.Error(t, err)
+
+	set, err := service.GetRecommendations(context.Background(), meeting.ID, "")
+	require.NoError(t, err)
+
+	// 4h window, 30 minute meeting, a candidate every 15 minutes
+	assert.Len(t, set.Slots, 15)
+	best := set.Slots[0]
+	assert.Equal(t, 3, best.AvailableCount)
+	assert.True(t, start.Add(30*time.Minute).Equal(best.TimeSlot.StartTime))
+	assert.True(t, start.Add(time.Hour).Equal(best.TimeSlot.EndTime))
+
+	// Computations are abandoned once the caller's deadline passes
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	_, err = service.GetRecommendations(ctx, meeting.ID, "")
+	assert.True(t, errors.IsTimeout(err))
+}
+
+func TestCandidateSlots_DaylightSavingTransition(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	// 01:00 to 04:00 on the spring-forward night is only two hours long
+	window := models.TimeSlot{
+		ID:        "w",
+		StartTime: time.Date(2025, 3, 30, 1, 0, 0, 0, paris),
+		EndTime:   time.Date(2025, 3, 30, 4, 0, 0, 0, paris),
+	}
+	meeting := models.Meeting{EstimatedDuration: 60, ProposedSlots: []models.TimeSlot{window}, TimeZone: "Europe/Paris"}
+
+	candidates := candidateSlots(meeting, 30*time.Minute)
+	require.Len(t, candidates, 3)
+	for _, candidate := range candidates {
+		// Meetings last their real duration, whatever the wall clock shows
+		assert.Equal(t, time.Hour, candidate.EndTime.Sub(candidate.StartTime))
+		assert.Equal(t, 0, candidate.StartTime.In(paris).Minute()%30)
+	}
+	assert.Equal(t, "01:00", candidates[0].StartTime.In(paris).Format("15:04"))
+	assert.Equal(t, "01:30", candidates[1].StartTime.In(paris).Format("15:04"))
+	assert.Equal(t, "03:00", candidates[2].StartTime.In(paris).Format("15:04"))
+}
+
+func TestCandidateSlots_LongWindow(t *testing.T) {
+	start := time.Date(2025, 1, 14, 9, 0, 0, 0, time.UTC)
+	window := models.TimeSlot{ID: "w", StartTime: start, EndTime: start.AddDate(10, 0, 0)}
+	meeting := models.Meeting{EstimatedDuration: 30, ProposedSlots: []models.TimeSlot{window, window}}
+
+	// Windows stored before their length was validated are split into a bounded number of candidates
+	candidates := candidateSlots(meeting, 15*time.Minute)
+	assert.Len(t, candidates, 2*maxWindowCandidates)
+	assert.True(t, start.Equal(candidates[maxWindowCandidates].StartTime))
+}
+
+func TestCandidateSlots_FractionalOffset(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	require.NoError(t, err)
+
+	window := models.TimeSlot{
+		ID:        "w",
+		StartTime: time.Date(2025, 1, 14, 9, 10, 0, 0, kolkata),
+		EndTime:   time.Date(2025, 1, 14, 11, 0, 0, 0, kolkata),
+	}
+	meeting := models.Meeting{EstimatedDuration: 60, ProposedSlots: []models.TimeSlot{window}, TimeZone: "Asia/Kolkata"}
+
+	// Sub-slots start on the hour in the meeting's zone, not in UTC
+	candidates := candidateSlots(meeting, time.Hour)
+	require.Len(t, candidates, 1)
+	assert.Equal(t, "10:00", candidates[0].StartTime.In(kolkata).Format("15:04"))
+}
+
+func TestMeetingService_RecommendationsAcrossDaylightSavingTransition(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+	WithSlotGranularity(30 * time.Minute)(service)
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	// 01:00 to 05:00 on the fall-back night lasts five hours
+	window := models.TimeSlot{
+		StartTime: time.Date(2030, 10, 27, 1, 0, 0, 0, paris),
+		EndTime:   time.Date(2030, 10, 27, 5, 0, 0, 0, paris),
+	}
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Maintenance window",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     []models.TimeSlot{window},
+		ParticipantIDs:    []string{participants[0].ID},
+		TimeZone:          "Europe/Paris",
+	})
+	require.NoError(t, err)
+
+	// Both are free during the repeated hour, the second 02:00 to 03:00
+	repeated := models.TimeSlot{StartTime: time.Date(2030, 10, 27, 1, 0, 0, 0, time.UTC), EndTime: time.Date(2030, 10, 27, 2, 0, 0, 0, time.UTC)}
+	_, err = service.AddAvailability(organizer.ID, meeting.ID, []models.TimeSlot{repeated}, false)
+	require.NoError(t, err)
+	_, err = service.AddAvailability(participants[0].ID, meeting.ID, []models.TimeSlot{repeated}, false)
+	require.NoError(t, err)
+
+	set, err := service.GetRecommendations(context.Background(), meeting.ID, "")
+	require.NoError(t, err)
+	assert.Len(t, set.Slots, 9)
+	best := set.Slots[0]
+	assert.Equal(t, 2, best.AvailableCount)
+	assert.True(t, repeated.StartTime.Equal(best.TimeSlot.StartTime))
+	assert.Equal(t, time.Hour, best.TimeSlot.EndTime.Sub(best.TimeSlot.StartTime))
+}
+
+func TestMeetingService_RecommendationsWithDurationRange(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+	WithSlotGranularity(30 * time.Minute)(service)
+
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	window := models.TimeSlot{StartTime: start, EndTime: start.Add(3 * time.Hour)}
+
+	_, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Workshop",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		MaxDuration:       30,
+		ProposedSlots:     []models.TimeSlot{window},
+	})
+	assert.Error(t, err, "maximum below the estimated duration")
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Workshop",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		MaxDuration:       120,
+		ProposedSlots:     []models.TimeSlot{window},
+		ParticipantIDs:    []string{participants[0].ID},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 120, meeting.MaxDuration)
+
+	// The organizer is free all window, in two back-to-back submissions; the participant for 90 minutes
+	_, err = service.AddAvailability(organizer.ID, meeting.ID, []models.TimeSlot{
+		{StartTime: start, EndTime: start.Add(90 * time.Minute)},
+		{StartTime: start.Add(90 * time.Minute), EndTime: start.Add(3 * time.Hour)},
+	}, false)
+	require.NoError(t, err)
+	_, err = service.AddAvailability(participants[0].ID, meeting.ID, []models.TimeSlot{{StartTime: start, EndTime: start.Add(90 * time.Minute)}}, false)
+	require.NoError(t, err)
+
+	set, err := service.GetRecommendations(context.Background(), meeting.ID, "")
+	require.NoError(t, err)
+
+	durations := make(map[time.Time]int)
+	for _, slot := range set.Slots {
+		durations[slot.TimeSlot.StartTime.UTC()] = slot.LongestFeasibleDuration
+	}
+	// Both attend from the start, but only for 90 minutes
+	assert.Equal(t, 90, durations[start.UTC()])
+	// Half an hour in, both attend for an hour
+	assert.Equal(t, 60, durations[start.Add(30*time.Minute).UTC()])
+	// Later only the organizer is free, up to the maximum and then the window end
+	assert.Equal(t, 120, durations[start.Add(time.Hour).UTC()])
+	assert.Equal(t, 60, durations[start.Add(2*time.Hour).UTC()])
+
+	// Among fully attended slots, the longer one ranks first
+	assert.Equal(t, 2, set.Slots[0].AvailableCount)
+	assert.Equal(t, 90, set.Slots[0].LongestFeasibleDuration)
+}
+
+func TestMeetingService_GetSplitRecommendations(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+	timeSlots := createTestTimeSlots()
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "All hands",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     timeSlots,
+		ParticipantIDs:    []string{participants[0].ID, participants[1].ID},
+	})
+	require.NoError(t, err)
+
+	// Everyone is free for the first slot: no split needed
+	_, err = service.AddAvailability(organizer.ID, meeting.ID, timeSlots, false)
+	require.NoError(t, err)
+	_, err = service.AddAvailability(participants[0].ID, meeting.ID, timeSlots[:1], false)
+	require.NoError(t, err)
+	availability, err := service.AddAvailability(participants[1].ID, meeting.ID, timeSlots[:1], false)
+	require.NoError(t, err)
+
+	splits, err := service.GetSplitRecommendations(meeting.ID)
+	require.NoError(t, err)
+	assert.Empty(t, splits)
+
+	// The second participant can now only make the second slot
+	_, err = service.UpdateAvailability(availability.ID, timeSlots[1:], false)
+	require.NoError(t, err)
+
+	splits, err = service.GetSplitRecommendations(meeting.ID)
+	require.NoError(t, err)
+	require.Len(t, splits, 1)
+	split := splits[0]
+	require.Len(t, split.Sessions, 2)
+	assert.True(t, timeSlots[0].StartTime.Equal(split.Sessions[0].StartTime))
+	assert.True(t, timeSlots[1].StartTime.Equal(split.Sessions[1].StartTime))
+	assert.Equal(t, 3, split.CoveredCount)
+	assert.Equal(t, 1, split.AttendingBoth)
+	assert.Equal(t, 3, split.TotalParticipants)
+
+	_, err = service.GetSplitRecommendations("non-existent")
+	assert.Error(t, err)
+}
+
+func TestSuggestSplits_RequiresOrganizerInBothSessions(t *testing.T) {
+	start := time.Date(2025, 1, 14, 9, 0, 0, 0, time.UTC)
+	organizer := models.User{ID: "organizer"}
+	participant := models.User{ID: "participant"}
+	meeting := models.Meeting{OrganizerID: organizer.ID, Organizer: &organizer, Participants: []models.User{participant}}
+
+	first := models.TimeSlot{StartTime: start, EndTime: start.Add(time.Hour)}
+	second := models.TimeSlot{StartTime: start.Add(2 * time.Hour), EndTime: start.Add(3 * time.Hour)}
+	recommendations := []models.RecommendedSlot{
+		{TimeSlot: first, AvailableCount: 1, TotalParticipants: 2, UnavailableParticipants: []models.User{participant}},
+		{TimeSlot: second, AvailableCount: 1, TotalParticipants: 2, UnavailableParticipants: []models.User{organizer}},
+	}
+
+	assert.Empty(t, suggestSplits(meeting, recommendations))
+}
+
+func TestSuggestSplits_ManyRecommendations(t *testing.T) {
+	start := time.Date(2025, 1, 14, 9, 0, 0, 0, time.UTC)
+	organizer := models.User{ID: "organizer"}
+	alice, bob := models.User{ID: "alice"}, models.User{ID: "bob"}
+	meeting := models.Meeting{OrganizerID: organizer.ID, Organizer: &organizer, Participants: []models.User{alice, bob}}
+
+	// Alice is only available every other hour, Bob in the hours between
+	recommendations := make([]models.RecommendedSlot, 1000)
+	for i := range recommendations {
+		absent := alice
+		if i%2 == 1 {
+			absent = bob
+		}
+		slot := models.TimeSlot{StartTime: start.Add(time.Duration(i) * time.Hour), EndTime: start.Add(time.Duration(i+1) * time.Hour)}
+		recommendations[i] = models.RecommendedSlot{TimeSlot: slot, AvailableCount: 2, TotalParticipants: 3, UnavailableParticipants: []models.User{absent}}
+	}
+
+	splits := suggestSplits(meeting, recommendations)
+	require.Len(t, splits, maxSplitRecommendations)
+	for k, split := range splits {
+		assert.True(t, start.Equal(split.Sessions[0].StartTime))
+		assert.True(t, start.Add(time.Duration(2*k+1)*time.Hour).Equal(split.Sessions[1].StartTime))
+		assert.Equal(t, 3, split.CoveredCount)
+		assert.Equal(t, 1, split.AttendingBoth)
+	}
+}
+
+func TestCalculateRecommendations_PolicyViolations(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+	policies, err := policy.New([]policy.Rule{
+		{Name: "lunch", Kind: policy.KindBlockedHours, Start: "12:00", End: "13:00"},
+	})
+	require.NoError(t, err)
+	WithPolicies(policies)(service)
+
+	day := time.Date(2030, 1, 15, 0, 0, 0, 0, time.UTC)
+	lunch := models.TimeSlot{StartTime: day.Add(12 * time.Hour), EndTime: day.Add(13 * time.Hour)}
+	morning := models.TimeSlot{StartTime: day.Add(14 * time.Hour), EndTime: day.Add(15 * time.Hour)}
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     []models.TimeSlot{lunch, morning},
+		ParticipantIDs:    []string{participants[0].ID},
+	})
+	require.NoError(t, err)
+
+	// Policy violations are reported and rank a slot below equally available ones
+	set, err := service.GetRecommendations(context.Background(), meeting.ID, "")
+	require.NoError(t, err)
+	require.Len(t, set.Slots, 2)
+	assert.True(t, set.Slots[0].TimeSlot.StartTime.Equal(morning.StartTime))
+	assert.Empty(t, set.Slots[0].PolicyViolations)
+	require.Len(t, set.Slots[1].PolicyViolations, 1)
+	assert.Equal(t, "lunch", set.Slots[1].PolicyViolations[0].Policy)
+}
+
+func TestCalculateRecommendations_FocusTime(t *testing.T) {
+	service, organizer, participants := setupTestMeetingService(t)
+
+	// Tuesday 2030-01-15
+	day := time.Date(2030, 1, 15, 0, 0, 0, 0, time.UTC)
+	focusSlot := models.TimeSlot{StartTime: day.Add(9 * time.Hour), EndTime: day.Add(10 * time.Hour)}
+	otherSlot := models.TimeSlot{StartTime: day.Add(14 * time.Hour), EndTime: day.Add(15 * time.Hour)}
+
+	_, err := service.userService.SetFocusBlocks(participants[0].ID, []models.FocusBlock{{Days: []string{"tuesday"}, Start: "09:00", End: "12:00"}})
+	require.NoError(t, err)
+
+	meeting, _, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     []models.TimeSlot{focusSlot, otherSlot},
+		ParticipantIDs:    []string{participants[0].ID},
+	})
+	require.NoError(t, err)
+	_, err = service.AddAvailability(participants[0].ID, meeting.ID, []models.TimeSlot{focusSlot, otherSlot}, false)
+	require.NoError(t, err)
+
+	// Focus time makes the participant unavailable, and the slot reports it
+	set, err := service.GetRecommendations(context.Background(), meeting.ID, "")
+	require.NoError(t, err)
+	require.Len(t, set.Slots, 2)
+	assert.True(t, set.Slots[0].TimeSlot.StartTime.Equal(otherSlot.StartTime))
+	assert.Equal(t, 1, set.Slots[0].AvailableCount)
+	assert.Empty(t, set.Slots[0].FocusTimeConflicts)
+	assert.Equal(t, 0, set.Slots[1].AvailableCount)
+	require.Len(t, set.Slots[1].FocusTimeConflicts, 1)
+	assert.Equal(t, participants[0].ID, set.Slots[1].FocusTimeConflicts[0].ID)
+
+	// With the override the participant counts as available, and the conflict is still reported
+	override := true
+	_, _, err = service.UpdateMeeting(meeting.ID, models.MeetingInput{OverrideFocusTime: &override})
+	require.NoError(t, err)
+	set, err = service.GetRecommendations(context.Background(), meeting.ID, "")
+	require.NoError(t, err)
+	for _, slot := range set.Slots {
+		assert.Equal(t, 1, slot.AvailableCount)
+	}
+	assert.True(t, set.Slots[0].TimeSlot.StartTime.Equal(focusSlot.StartTime))
+	require.Len(t, set.Slots[0].FocusTimeConflicts, 1)
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"meetsync/internal/interfaces"
+	"meetsync/internal/metering"
+	"meetsync/internal/models"
+	"meetsync/internal/policy"
+	"meetsync/pkg/errors"
+)
+
+const (
+	// maxQueryWindow caps the length of the window searched by a scheduling query
+	maxQueryWindow = 14 * 24 * time.Hour
+	// maxQueryResults caps the number of candidate slots returned by a scheduling query
+	maxQueryResults = 20
+	// defaultQueryGranularity is used when slot splitting is disabled, since a query always needs candidates
+	defaultQueryGranularity = 15 * time.Minute
+)
+
+// BusySource reports when users are already busy, for example from a linked calendar
+type BusySource interface {
+	BusyTimes(userID string, from, to time.Time) ([]models.TimeSlot, error)
+}
+
+// NoBusyTimes is a BusySource that considers every user free at all times
+type NoBusyTimes struct{}
+
+// BusyTimes implements BusySource
+func (NoBusyTimes) BusyTimes(userID string, from, to time.Time) ([]models.TimeSlot, error) {
+	return nil, nil
+}
+
+// SchedulingServiceImpl implements the SchedulingService interface
+type SchedulingServiceImpl struct {
+	userService interfaces.UserService
+	busySource  BusySource
+	policies    *policy.Engine
+	granularity time.Duration
+	meter       *metering.Meter
+}
+
+var _ interfaces.SchedulingService = (*SchedulingServiceImpl)(nil) // Verify SchedulingServiceImpl implements SchedulingService interface
+
+// SchedulingServiceOption configures optional dependencies of a SchedulingServiceImpl
+type SchedulingServiceOption func(*SchedulingServiceImpl)
+
+// WithBusySource sets where the busy times of users come from
+func WithBusySource(source BusySource) SchedulingServiceOption {
+	return func(s *SchedulingServiceImpl) {
+		s.busySource = source
+	}
+}
+
+// WithQueryGranularity sets the step between candidate start times
+func WithQueryGranularity(granularity time.Duration) SchedulingServiceOption {
+	return func(s *SchedulingServiceImpl) {
+		s.granularity = granularity
+	}
+}
+
+// WithQueryPolicies sets the scheduling policies checked for every candidate slot
+func WithQueryPolicies(policies *policy.Engine) SchedulingServiceOption {
+	return func(s *SchedulingServiceImpl) {
+		s.policies = policies
+	}
+}
+
+// WithQueryMeter sets the meter counting calls to the busy source of a linked calendar
+func WithQueryMeter(meter *metering.Meter) SchedulingServiceOption {
+	return func(s *SchedulingServiceImpl) {
+		s.meter = meter
+	}
+}
+
+// NewSchedulingService creates a new SchedulingService
+func NewSchedulingService(userService interfaces.UserService, opts ...SchedulingServiceOption) interfaces.SchedulingService {
+	s := &SchedulingServiceImpl{
+		userService: userService,
+		busySource:  NoBusyTimes{},
+		granularity: defaultQueryGranularity,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.granularity <= 0 {
+		s.granularity = defaultQueryGranularity
+	}
+	return s
+}
+
+// FindTimes ranks the slots of a window by how many of the given users are free,
+// without creating a meeting
+func (s *SchedulingServiceImpl) FindTimes(query models.SchedulingQuery) ([]models.RecommendedSlot, error) {
+	if len(query.ParticipantIDs) == 0 {
+		return nil, errors.NewValidationError("At least one participant is required", "")
+	}
+	if query.Duration <= 0 {
+		return nil, errors.NewValidationError("Duration must be positive", "")
+	}
+	window := query.Window
+	if !window.EndTime.After(window.StartTime) {
+		return nil, errors.NewValidationError("Window end must be after its start", "")
+	}
+	if window.EndTime.Sub(window.StartTime) < time.Duration(query.Duration)*time.Minute {
+		return nil, errors.NewValidationError("Window is shorter than the duration", "")
+	}
+	if window.EndTime.Sub(window.StartTime) > maxQueryWindow {
+		return nil, errors.NewValidationError("Window is too long", fmt.Sprintf("windows can span at most %s", maxQueryWindow))
+	}
+	location, err := loadTimeZone(query.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+	inWorkingHours, err := workingHoursFilter(query.WorkingHours, location)
+	if err != nil {
+		return nil, err
+	}
+
+	participants, err := s.userService.GetUsersByIDs(query.ParticipantIDs)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]bool, len(participants))
+	for _, participant := range participants {
+		found[participant.ID] = true
+	}
+	for _, id := range query.ParticipantIDs {
+		if !found[id] {
+			return nil, errors.NewNotFoundError(fmt.Sprintf("Participant not found: %s", id))
+		}
+	}
+
+	// Busy times are read for whole days so they can count towards daily meeting limits
+	year, month, day := window.StartTime.In(location).Date()
+	from := time.Date(year, month, day, 0, 0, 0, 0, location)
+	year, month, day = window.EndTime.In(location).Date()
+	to := time.Date(year, month, day+1, 0, 0, 0, 0, location)
+
+	busy := make(map[string][]models.TimeSlot, len(participants))
+	for _, participant := range participants {
+		times, err := s.busySource.BusyTimes(participant.ID, from, to)
+		if err != nil {
+			return nil, errors.NewDependencyError("Failed to look up busy times", err)
+		}
+		if _, unlinked := s.busySource.(NoBusyTimes); !unlinked {
+			s.meter.Record(metering.KindCalendarSync, participant.ID)
+		}
+		busy[participant.ID] = times
+	}
+
+	booked := func(userID string, day time.Time) time.Duration {
+		year, month, date := day.Date()
+		dayStart := time.Date(year, month, date, 0, 0, 0, 0, day.Location())
+		dayEnd := dayStart.AddDate(0, 0, 1)
+
+		var total time.Duration
+		for _, slot := range busy[userID] {
+			start, end := slot.StartTime, slot.EndTime
+			if start.Before(dayStart) {
+				start = dayStart
+			}
+			if end.After(dayEnd) {
+				end = dayEnd
+			}
+			if end.After(start) {
+				total += end.Sub(start)
+			}
+		}
+		return total
+	}
+
+	window.ID = "window"
+	candidates := candidateSlots(models.Meeting{
+		EstimatedDuration: query.Duration,
+		ProposedSlots:     []models.TimeSlot{window},
+		TimeZone:          query.TimeZone,
+	}, s.granularity)
+
+	results := make([]models.RecommendedSlot, 0, len(candidates))
+	for _, candidate := range candidates {
+		if !inWorkingHours(candidate) {
+			continue
+		}
+		candidate.ID = ""
+
+		result := models.RecommendedSlot{
+			TimeSlot:                candidate,
+			TotalParticipants:       len(participants),
+			UnavailableParticipants: []models.User{},
+		}
+		var attendeeIDs []string
+		for _, participant := range participants {
+			if overlapsAny(busy[participant.ID], candidate) {
+				result.UnavailableParticipants = append(result.UnavailableParticipants, participant)
+			} else {
+				result.AvailableCount++
+				attendeeIDs = append(attendeeIDs, participant.ID)
+			}
+		}
+		result.PolicyViolations = s.policies.Evaluate(candidate, location, attendeeIDs, booked)
+		results = append(results, result)
+	}
+
+	sortRecommendations(results)
+	if len(results) > maxQueryResults {
+		results = results[:maxQueryResults]
+	}
+	return results, nil
+}
+
+// workingHoursFilter returns a function reporting whether a slot lies within the working
+// hours of a single day in location; without working hours every slot is accepted
+func workingHoursFilter(hours *models.WorkingHours, location *time.Location) (func(models.TimeSlot) bool, error) {
+	if hours == nil {
+		return func(models.TimeSlot) bool { return true }, nil
+	}
+
+	start, err := time.Parse("15:04", hours.Start)
+	if err != nil {
+		return nil, errors.NewValidationError("Invalid working hours start", "use the HH:MM format")
+	}
+	end, err := time.Parse("15:04", hours.End)
+	if err != nil {
+		return nil, errors.NewValidationError("Invalid working hours end", "use the HH:MM format")
+	}
+	if !end.After(start) {
+		return nil, errors.NewValidationError("Working hours must end after they start", "")
+	}
+
+	return func(slot models.TimeSlot) bool {
+		slotStart, slotEnd := slot.StartTime.In(location), slot.EndTime.In(location)
+		if hours.ExcludeWeekends && (slotStart.Weekday() == time.Saturday || slotStart.Weekday() == time.Sunday) {
+			return false
+		}
+
+		year, month, day := slotStart.Date()
+		dayStart := time.Date(year, month, day, start.Hour(), start.Minute(), 0, 0, location)
+		dayEnd := time.Date(year, month, day, end.Hour(), end.Minute(), 0, 0, location)
+		return !slotStart.Before(dayStart) && !slotEnd.After(dayEnd)
+	}, nil
+}
+
+// overlapsAny reports whether slot overlaps any of the given slots
+func overlapsAny(slots []models.TimeSlot, slot models.TimeSlot) bool {
+	for _, other := range slots {
+		if other.StartTime.Before(slot.EndTime) && slot.StartTime.Before(other.EndTime) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"meetsync/internal/metering"
+	"meetsync/internal/models"
+	"meetsync/internal/policy"
+	"meetsync/pkg/errors"
+)
+
+// staticBusySource reports fixed busy times per user
+type staticBusySource map[string][]models.TimeSlot
+
+func (s staticBusySource) BusyTimes(userID string, from, to time.Time) ([]models.TimeSlot, error) {
+	return s[userID], nil
+}
+
+func TestFindTimes(t *testing.T) {
+	userService := NewUserService()
+	alice, err := userService.CreateUser("Alice", "alice@example.com")
+	require.NoError(t, err)
+	bob, err := userService.CreateUser("Bob", "bob@example.com")
+	require.NoError(t, err)
+
+	// Tuesday 2025-01-14, 08:00 to 12:00 UTC
+	start := time.Date(2025, 1, 14, 8, 0, 0, 0, time.UTC)
+	window := models.TimeSlot{StartTime: start, EndTime: start.Add(4 * time.Hour)}
+
+	busy := staticBusySource{
+		bob.ID: {{StartTime: start, EndTime: start.Add(2 * time.Hour)}},
+	}
+	service := NewSchedulingService(userService, WithBusySource(busy), WithQueryGranularity(time.Hour))
+
+	t.Run("ranks slots where everyone is free first", func(t *testing.T) {
+		candidates, err := service.FindTimes(models.SchedulingQuery{
+			ParticipantIDs: []string{alice.ID, bob.ID},
+			Duration:       60,
+			Window:         window,
+		})
+		require.NoError(t, err)
+		require.Len(t, candidates, 4)
+
+		assert.Equal(t, start.Add(2*time.Hour), candidates[0].TimeSlot.StartTime)
+		assert.Equal(t, 2, candidates[0].AvailableCount)
+		assert.Equal(t, start.Add(3*time.Hour), candidates[1].TimeSlot.StartTime)
+		assert.Equal(t, 2, candidates[1].AvailableCount)
+		assert.Equal(t, 1, candidates[2].AvailableCount)
+		require.Len(t, candidates[2].UnavailableParticipants, 1)
+		assert.Equal(t, bob.ID, candidates[2].UnavailableParticipants[0].ID)
+	})
+
+	t.Run("working hours restrict candidates", func(t *testing.T) {
+		candidates, err := service.FindTimes(models.SchedulingQuery{
+			ParticipantIDs: []string{alice.ID},
+			Duration:       60,
+			Window:         window,
+			TimeZone:       "Europe/Paris",
+			WorkingHours:   &models.WorkingHours{Start: "10:00", End: "12:00"},
+		})
+		require.NoError(t, err)
+		require.Len(t, candidates, 2)
+		assert.Equal(t, start.Add(time.Hour), candidates[0].TimeSlot.StartTime) // 10:00 in Paris
+		assert.Equal(t, start.Add(2*time.Hour), candidates[1].TimeSlot.StartTime)
+	})
+
+	t.Run("weekends can be excluded", func(t *testing.T) {
+		saturday := time.Date(2025, 1, 18, 9, 0, 0, 0, time.UTC)
+		candidates, err := service.FindTimes(models.SchedulingQuery{
+			ParticipantIDs: []string{alice.ID},
+			Duration:       60,
+			Window:         models.TimeSlot{StartTime: saturday, EndTime: saturday.Add(3 * time.Hour)},
+			WorkingHours:   &models.WorkingHours{Start: "09:00", End: "17:00", ExcludeWeekends: true},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, candidates)
+	})
+
+	t.Run("busy times count towards daily limits", func(t *testing.T) {
+		policies, err := policy.New([]policy.Rule{{Name: "two hours a day", Kind: policy.KindDailyLimit, MaxMinutes: 120}})
+		require.NoError(t, err)
+		limited := NewSchedulingService(userService, WithBusySource(busy), WithQueryGranularity(time.Hour), WithQueryPolicies(policies))
+
+		candidates, err := limited.FindTimes(models.SchedulingQuery{
+			ParticipantIDs: []string{alice.ID, bob.ID},
+			Duration:       60,
+			Window:         window,
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, candidates)
+		require.Len(t, candidates[0].PolicyViolations, 1)
+		assert.Equal(t, []string{bob.ID}, candidates[0].PolicyViolations[0].UserIDs)
+	})
+
+	t.Run("unknown participant", func(t *testing.T) {
+		_, err := service.FindTimes(models.SchedulingQuery{
+			ParticipantIDs: []string{alice.ID, "missing"},
+			Duration:       60,
+			Window:         window,
+		})
+		require.Error(t, err)
+		assert.Equal(t, errors.ErrorTypeNotFound, err.(*errors.AppError).Type)
+	})
+
+	t.Run("calendar lookups are metered", func(t *testing.T) {
+		meter := metering.NewMeter(metering.NoopSink{})
+		metered := NewSchedulingService(userService, WithBusySource(busy), WithQueryMeter(meter))
+		_, err := metered.FindTimes(models.SchedulingQuery{
+			ParticipantIDs: []string{alice.ID, bob.ID},
+			Duration:       60,
+			Window:         window,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), meter.Summary(meter.CurrentPeriod()).CalendarSyncCalls)
+	})
+
+	t.Run("invalid queries", func(t *testing.T) {
+		queries := map[string]models.SchedulingQuery{
+			"no participants":     {Duration: 60, Window: window},
+			"no duration":         {ParticipantIDs: []string{alice.ID}, Window: window},
+			"window too short":    {ParticipantIDs: []string{alice.ID}, Duration: 300, Window: window},
+			"window too long":     {ParticipantIDs: []string{alice.ID}, Duration: 60, Window: models.TimeSlot{StartTime: start, EndTime: start.Add(15 * 24 * time.Hour)}},
+			"bad time zone":       {ParticipantIDs: []string{alice.ID}, Duration: 60, Window: window, TimeZone: "Mars/Olympus"},
+			"bad working hours":   {ParticipantIDs: []string{alice.ID}, Duration: 60, Window: window, WorkingHours: &models.WorkingHours{Start: "9am", End: "17:00"}},
+			"empty working hours": {ParticipantIDs: []string{alice.ID}, Duration: 60, Window: window, WorkingHours: &models.WorkingHours{Start: "17:00", End: "09:00"}},
+		}
+		for name, query := range queries {
+			_, err := service.FindTimes(query)
+			require.Error(t, err, name)
+			assert.Equal(t, errors.ErrorTypeValidation, err.(*errors.AppError).Type, name)
+		}
+	})
+}
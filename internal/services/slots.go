@@ -0,0 +1,123 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+)
+
+// normalizeSlots sorts proposed slots by start time, drops slots that repeat or fall
+// within another slot and, if coalesce is set, merges slots that touch end to start.
+// Every change and every remaining partial overlap is reported as a warning.
+func normalizeSlots(slots []models.TimeSlot, coalesce bool) ([]models.TimeSlot, []models.Warning) {
+	sorted := make([]models.TimeSlot, len(slots))
+	copy(sorted, slots)
+	// Longer slots first on equal starts, so a slot is always compared with any slot containing it
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].StartTime.Equal(sorted[j].StartTime) {
+			return sorted[i].StartTime.Before(sorted[j].StartTime)
+		}
+		return sorted[i].EndTime.After(sorted[j].EndTime)
+	})
+
+	var warnings []models.Warning
+	normalized := make([]models.TimeSlot, 0, len(sorted))
+	for _, slot := range sorted {
+		if len(normalized) == 0 {
+			normalized = append(normalized, slot)
+			continue
+		}
+
+		last := &normalized[len(normalized)-1]
+		switch {
+		case slot.StartTime.Equal(last.StartTime) && slot.EndTime.Equal(last.EndTime):
+			warnings = append(warnings, models.Warning{
+				Code:    models.WarningDuplicateSlot,
+				Message: fmt.Sprintf("Duplicate slot %s was removed", formatSlot(slot)),
+			})
+		case !slot.EndTime.After(last.EndTime):
+			warnings = append(warnings, models.Warning{
+				Code:    models.WarningContainedSlot,
+				Message: fmt.Sprintf("Slot %s was removed because it is within slot %s", formatSlot(slot), formatSlot(*last)),
+			})
+		case coalesce && slot.StartTime.Equal(last.EndTime):
+			warnings = append(warnings, models.Warning{
+				Code:    models.WarningCoalescedSlots,
+				Message: fmt.Sprintf("Adjacent slots %s and %s were merged", formatSlot(*last), formatSlot(slot)),
+			})
+			last.EndTime = slot.EndTime
+		case slot.StartTime.Before(last.EndTime):
+			warnings = append(warnings, models.Warning{
+				Code:    models.WarningOverlappingSlots,
+				Message: fmt.Sprintf("Slots %s and %s overlap", formatSlot(*last), formatSlot(slot)),
+			})
+			normalized = append(normalized, slot)
+		default:
+			normalized = append(normalized, slot)
+		}
+	}
+	return normalized, warnings
+}
+
+const (
+	// maxSlotLength caps the length of a proposed slot, which recommendations split into
+	// candidate slots of the meeting's duration
+	maxSlotLength = 14 * 24 * time.Hour
+	// maxProposedSlots caps the number of slots proposed for a meeting, once normalized
+	maxProposedSlots = 100
+)
+
+// validateProposedSlots rejects proposed slots too many or too long to search for
+// recommendations
+func validateProposedSlots(slots []models.TimeSlot) error {
+	if len(slots) > maxProposedSlots {
+		return errors.NewValidationError("Too many proposed slots", fmt.Sprintf("at most %d slots can be proposed", maxProposedSlots))
+	}
+	for _, slot := range slots {
+		if slot.EndTime.Sub(slot.StartTime) > maxSlotLength {
+			return errors.NewValidationError("Slot is too long", fmt.Sprintf("slot %s is longer than %s", formatSlot(slot), maxSlotLength))
+		}
+	}
+	return nil
+}
+
+// formatSlot renders a slot for use in messages
+func formatSlot(slot models.TimeSlot) string {
+	return slot.StartTime.UTC().Format(time.RFC3339) + "/" + slot.EndTime.UTC().Format(time.RFC3339)
+}
+
+// loadTimeZone resolves an IANA time zone name; an empty name means UTC
+func loadTimeZone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errors.NewValidationError("Invalid time zone", err.Error())
+	}
+	return location, nil
+}
+
+// dstWarnings reports slots whose start and end fall on different UTC offsets in location.
+// Such slots last an hour more or less than their wall-clock times suggest.
+func dstWarnings(slots []models.TimeSlot, location *time.Location) []models.Warning {
+	var warnings []models.Warning
+	for _, slot := range slots {
+		start, end := slot.StartTime.In(location), slot.EndTime.In(location)
+		_, startOffset := start.Zone()
+		_, endOffset := end.Zone()
+		if startOffset == endOffset {
+			continue
+		}
+
+		warnings = append(warnings, models.Warning{
+			Code: models.WarningDSTTransition,
+			Message: fmt.Sprintf("Slot %s spans a daylight saving time change in %s: it lasts %s but the clock shows %s to %s",
+				formatSlot(slot), location, end.Sub(start), start.Format("15:04"), end.Format("15:04")),
+		})
+	}
+	return warnings
+}
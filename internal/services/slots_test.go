@@ -0,0 +1,188 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"meetsync/internal/models"
+)
+
+func TestNormalizeSlots(t *testing.T) {
+	base := time.Date(2025, 1, 14, 9, 0, 0, 0, time.UTC)
+	slot := func(start, end time.Duration) models.TimeSlot {
+		return models.TimeSlot{StartTime: base.Add(start), EndTime: base.Add(end)}
+	}
+
+	tests := []struct {
+		name          string
+		slots         []models.TimeSlot
+		coalesce      bool
+		expectedSlots []models.TimeSlot
+		expectedCodes []models.WarningCode
+	}{
+		{
+			name:          "distinct slots are sorted and kept",
+			slots:         []models.TimeSlot{slot(3*time.Hour, 4*time.Hour), slot(0, time.Hour)},
+			expectedSlots: []models.TimeSlot{slot(0, time.Hour), slot(3*time.Hour, 4*time.Hour)},
+		},
+		{
+			name:          "identical slots are merged",
+			slots:         []models.TimeSlot{slot(0, time.Hour), slot(0, time.Hour)},
+			expectedSlots: []models.TimeSlot{slot(0, time.Hour)},
+			expectedCodes: []models.WarningCode{models.WarningDuplicateSlot},
+		},
+		{
+			name:          "contained slots are dropped",
+			slots:         []models.TimeSlot{slot(30*time.Minute, time.Hour), slot(0, 2*time.Hour), slot(0, time.Hour)},
+			expectedSlots: []models.TimeSlot{slot(0, 2*time.Hour)},
+			expectedCodes: []models.WarningCode{models.WarningContainedSlot, models.WarningContainedSlot},
+		},
+		{
+			name:          "partial overlaps are kept with a warning",
+			slots:         []models.TimeSlot{slot(0, time.Hour), slot(30*time.Minute, 90*time.Minute)},
+			expectedSlots: []models.TimeSlot{slot(0, time.Hour), slot(30*time.Minute, 90*time.Minute)},
+			expectedCodes: []models.WarningCode{models.WarningOverlappingSlots},
+		},
+		{
+			name:          "adjacent slots are kept without coalescing",
+			slots:         []models.TimeSlot{slot(0, time.Hour), slot(time.Hour, 2*time.Hour)},
+			expectedSlots: []models.TimeSlot{slot(0, time.Hour), slot(time.Hour, 2*time.Hour)},
+		},
+		{
+			name:          "adjacent slots are coalesced on request",
+			slots:         []models.TimeSlot{slot(time.Hour, 2*time.Hour), slot(0, time.Hour), slot(2*time.Hour, 3*time.Hour)},
+			coalesce:      true,
+			expectedSlots: []models.TimeSlot{slot(0, 3*time.Hour)},
+			expectedCodes: []models.WarningCode{models.WarningCoalescedSlots, models.WarningCoalescedSlots},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normalized, warnings := normalizeSlots(tt.slots, tt.coalesce)
+
+			require.Len(t, normalized, len(tt.expectedSlots))
+			for i, expected := range tt.expectedSlots {
+				assert.True(t, expected.StartTime.Equal(normalized[i].StartTime))
+				assert.True(t, expected.EndTime.Equal(normalized[i].EndTime))
+			}
+
+			codes := make([]models.WarningCode, 0, len(warnings))
+			for _, warning := range warnings {
+				codes = append(codes, warning.Code)
+				assert.NotEmpty(t, warning.Message)
+			}
+			assert.ElementsMatch(t, tt.expectedCodes, codes)
+		})
+	}
+}
+
+func TestMeetingService_CreateMeetingNormalizesSlots(t *testing.T) {
+	service, organizer, _ := setupTestMeetingService(t)
+	timeSlots := createTestTimeSlots()
+
+	meeting, warnings, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     []models.TimeSlot{timeSlots[1], timeSlots[0], timeSlots[0]},
+	})
+	require.NoError(t, err)
+	require.Len(t, meeting.ProposedSlots, 2)
+	assert.True(t, timeSlots[0].StartTime.Equal(meeting.ProposedSlots[0].StartTime))
+	require.Len(t, warnings, 1)
+	assert.Equal(t, models.WarningDuplicateSlot, warnings[0].Code)
+}
+
+func TestDSTWarnings(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		slot          models.TimeSlot
+		expectWarning bool
+	}{
+		{
+			// Clocks go from 02:00 to 03:00 on 30 March 2025
+			name: "spring forward",
+			slot: models.TimeSlot{
+				StartTime: time.Date(2025, 3, 30, 1, 0, 0, 0, paris),
+				EndTime:   time.Date(2025, 3, 30, 4, 0, 0, 0, paris),
+			},
+			expectWarning: true,
+		},
+		{
+			// Clocks go from 03:00 back to 02:00 on 26 October 2025
+			name: "fall back",
+			slot: models.TimeSlot{
+				StartTime: time.Date(2025, 10, 26, 1, 0, 0, 0, paris),
+				EndTime:   time.Date(2025, 10, 26, 4, 0, 0, 0, paris),
+			},
+			expectWarning: true,
+		},
+		{
+			name: "no transition",
+			slot: models.TimeSlot{
+				StartTime: time.Date(2025, 3, 29, 1, 0, 0, 0, paris),
+				EndTime:   time.Date(2025, 3, 29, 4, 0, 0, 0, paris),
+			},
+			expectWarning: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings := dstWarnings([]models.TimeSlot{tt.slot}, paris)
+			if !tt.expectWarning {
+				assert.Empty(t, warnings)
+				return
+			}
+			require.Len(t, warnings, 1)
+			assert.Equal(t, models.WarningDSTTransition, warnings[0].Code)
+			assert.Contains(t, warnings[0].Message, "Europe/Paris")
+		})
+	}
+
+	// Slots are not checked against a zone without daylight saving time
+	assert.Empty(t, dstWarnings([]models.TimeSlot{tests[0].slot}, time.UTC))
+}
+
+func TestMeetingService_TimeZone(t *testing.T) {
+	service, organizer, _ := setupTestMeetingService(t)
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	slot := models.TimeSlot{
+		StartTime: time.Date(2030, 3, 31, 1, 0, 0, 0, paris),
+		EndTime:   time.Date(2030, 3, 31, 4, 0, 0, 0, paris),
+	}
+
+	_, _, err = service.CreateMeeting(models.MeetingInput{
+		Title:             "Night shift handover",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     []models.TimeSlot{slot},
+		TimeZone:          "Mars/Olympus_Mons",
+	})
+	assert.Error(t, err)
+
+	meeting, warnings, err := service.CreateMeeting(models.MeetingInput{
+		Title:             "Night shift handover",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     []models.TimeSlot{slot},
+		TimeZone:          "Europe/Paris",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Paris", meeting.TimeZone)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, models.WarningDSTTransition, warnings[0].Code)
+
+	// Changing the zone re-checks the stored slots
+	_, warnings, err = service.UpdateMeeting(meeting.ID, models.MeetingInput{TimeZone: "Asia/Tokyo"})
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"time"
+
+	"meetsync/internal/health"
+	"meetsync/internal/models"
+	"meetsync/internal/pagination"
+	"meetsync/internal/repositories"
+	"meetsync/pkg/errors"
+)
+
+// storageRetry configures how operations are retried while storage is unreachable.
+// Storage backends report an unavailable error only when an operation did not reach
+// the store, so retrying writes cannot apply them twice.
+type storageRetry struct {
+	attempts   int
+	backoff    time.Duration // delay before the first retry, doubled for every further retry
+	retryAfter time.Duration // how long clients are asked to wait once retries are exhausted
+}
+
+// defaultStorageRetry rides out short blips, such as a database failover, without
+// holding requests for long
+var defaultStorageRetry = storageRetry{
+	attempts:   3,
+	backoff:    50 * time.Millisecond,
+	retryAfter: 5 * time.Second,
+}
+
+// storageGuard retries storage operations with backoff and records whether storage is
+// reachable. Once retries are exhausted it reports storage as unavailable and, until an
+// operation succeeds again, tries every operation only once so that requests fail fast
+// with a 503 instead of piling up behind retries. Timeouts, including unavailable errors
+// caused by one, also mark storage as unavailable but are never retried, since the timed
+// out operation may have applied.
+type storageGuard struct {
+	monitor *health.StorageMonitor
+	retry   storageRetry
+	sleep   func(time.Duration)
+}
+
+func newStorageGuard(monitor *health.StorageMonitor) *storageGuard {
+	if monitor == nil {
+		monitor = health.NewStorageMonitor()
+	}
+	return &storageGuard{
+		monitor: monitor,
+		retry:   defaultStorageRetry,
+		sleep:   time.Sleep,
+	}
+}
+
+// guarded runs op, retrying it while storage is unavailable
+func guarded[T any](g *storageGuard, op func() (T, error)) (T, error) {
+	attempts := g.retry.attempts
+	if !g.monitor.Available() {
+		attempts = 1
+	}
+
+	backoff := g.retry.backoff
+	for attempt := 1; ; attempt++ {
+		result, err := op()
+		if errors.Is(err, errors.ErrTimeout) {
+			g.monitor.MarkUnavailable(err, time.Now())
+			return result, err
+		}
+		if !errors.IsUnavailable(err) {
+			g.monitor.MarkAvailable()
+			return result, err
+		}
+		if attempt >= attempts {
+			g.monitor.MarkUnavailable(err, time.Now())
+			return result, errors.NewUnavailableError("Storage is temporarily unavailable", g.retry.retryAfter, err)
+		}
+		g.sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// guardedErr runs op, retrying it while storage is unavailable
+func guardedErr(g *storageGuard, op func() error) error {
+	_, err := guarded(g, func() (struct{}, error) {
+		return struct{}{}, op()
+	})
+	return err
+}
+
+// guardedMeetingRepository runs every operation of a MeetingRepository through a storageGuard
+type guardedMeetingRepository struct {
+	next  repositories.MeetingRepository
+	guard *storageGuard
+}
+
+func (r guardedMeetingRepository) CreateMeeting(meeting models.Meeting) (models.Meeting, error) {
+	return guarded(r.guard, func() (models.Meeting, error) { return r.next.CreateMeeting(meeting) })
+}
+
+func (r guardedMeetingRepository) GetMeetingByID(id string) (models.Meeting, error) {
+	return guarded(r.guard, func() (models.Meeting, error) { return r.next.GetMeetingByID(id) })
+}
+
+func (r guardedMeetingRepository) GetMeetingByExternalID(externalID string) (models.Meeting, error) {
+	return guarded(r.guard, func() (models.Meeting, error) { return r.next.GetMeetingByExternalID(externalID) })
+}
+
+func (r guardedMeetingRepository) ListMeetings(after *pagination.Cursor, limit int, filter models.MeetingFilter) ([]models.Meeting, error) {
+	return guarded(r.guard, func() ([]models.Meeting, error) { return r.next.ListMeetings(after, limit, filter) })
+}
+
+func (r guardedMeetingRepository) UpdateMeeting(meeting models.Meeting) (models.Meeting, error) {
+	return guarded(r.guard, func() (models.Meeting, error) { return r.next.UpdateMeeting(meeting) })
+}
+
+func (r guardedMeetingRepository) DeleteMeeting(id string) error {
+	return guardedErr(r.guard, func() error { return r.next.DeleteMeeting(id) })
+}
+
+func (r guardedMeetingRepository) SearchMeetings(query string, limit int) ([]models.SearchResult, error) {
+	return guarded(r.guard, func() ([]models.SearchResult, error) { return r.next.SearchMeetings(query, limit) })
+}
+
+func (r guardedMeetingRepository) CreateAvailability(availability models.Availability) (models.Availability, error) {
+	return guarded(r.guard, func() (models.Availability, error) { return r.next.CreateAvailability(availability) })
+}
+
+func (r guardedMeetingRepository) GetAvailability(userID, meetingID string) (models.Availability, error) {
+	return guarded(r.guard, func() (models.Availability, error) { return r.next.GetAvailability(userID, meetingID) })
+}
+
+func (r guardedMeetingRepository) GetAvailabilityByID(id string) (models.Availability, error) {
+	return guarded(r.guard, func() (models.Availability, error) { return r.next.GetAvailabilityByID(id) })
+}
+
+func (r guardedMeetingRepository) UpdateAvailability(availability models.Availability) (models.Availability, error) {
+	return guarded(r.guard, func() (models.Availability, error) { return r.next.UpdateAvailability(availability) })
+}
+
+func (r guardedMeetingRepository) DeleteAvailability(id string) error {
+	return guardedErr(r.guard, func() error { return r.next.DeleteAvailability(id) })
+}
+
+func (r guardedMeetingRepository) GetMeetingAvailabilities(meetingID string) ([]models.Availability, error) {
+	return guarded(r.guard, func() ([]models.Availability, error) { return r.next.GetMeetingAvailabilities(meetingID) })
+}
+
+func (r guardedMeetingRepository) GetParticipantAvailabilities(participantID string) ([]models.Availability, error) {
+	return guarded(r.guard, func() ([]models.Availability, error) { return r.next.GetParticipantAvailabilities(participantID) })
+}
+
+func (r guardedMeetingRepository) GetAllAvailabilities() []models.Availability {
+	return r.next.GetAllAvailabilities()
+}
+
+func (r guardedMeetingRepository) AddTimelineEvent(event models.TimelineEvent) (models.TimelineEvent, error) {
+	return guarded(r.guard, func() (models.TimelineEvent, error) { return r.next.AddTimelineEvent(event) })
+}
+
+func (r guardedMeetingRepository) GetTimeline(meetingID string) ([]models.TimelineEvent, error) {
+	return guarded(r.guard, func() ([]models.TimelineEvent, error) { return r.next.GetTimeline(meetingID) })
+}
+
+func (r guardedMeetingRepository) ReassignUser(fromUserID string, to models.User) (models.UserReassignment, error) {
+	return guarded(r.guard, func() (models.UserReassignment, error) { return r.next.ReassignUser(fromUserID, to) })
+}
+
+func (r guardedMeetingRepository) SaveRecommendations(set models.RecommendationSet) error {
+	return guardedErr(r.guard, func() error { return r.next.SaveRecommendations(set) })
+}
+
+func (r guardedMeetingRepository) GetRecommendations(meetingID string) (models.RecommendationSet, error) {
+	return guarded(r.guard, func() (models.RecommendationSet, error) { return r.next.GetRecommendations(meetingID) })
+}
+
+func (r guardedMeetingRepository) Snapshot() (models.MeetingStoreSnapshot, error) {
+	return guarded(r.guard, r.next.Snapshot)
+}
+
+func (r guardedMeetingRepository) Restore(snapshot models.MeetingStoreSnapshot) error {
+	return guardedErr(r.guard, func() error { return r.next.Restore(snapshot) })
+}
+
+// guardedUserRepository runs every operation of a UserRepository through a storageGuard
+type guardedUserRepository struct {
+	next  repositories.UserRepository
+	guard *storageGuard
+}
+
+func (r guardedUserRepository) Create(user models.User) (models.User, error) {
+	return guarded(r.guard, func() (models.User, error) { return r.next.Create(user) })
+}
+
+func (r guardedUserRepository) GetByID(id string) (models.User, error) {
+	return guarded(r.guard, func() (models.User, error) { return r.next.GetByID(id) })
+}
+
+func (r guardedUserRepository) GetByIDs(ids []string) ([]models.User, error) {
+	return guarded(r.guard, func() ([]models.User, error) { return r.next.GetByIDs(ids) })
+}
+
+func (r guardedUserRepository) GetAll() ([]models.User, error) {
+	return guarded(r.guard, r.next.GetAll)
+}
+
+func (r guardedUserRepository) GetByEmail(email string) (models.User, bool) {
+	return r.next.GetByEmail(email)
+}
+
+func (r guardedUserRepository) Update(user models.User) (models.User, error) {
+	return guarded(r.guard, func() (models.User, error) { return r.next.Update(user) })
+}
+
+func (r guardedUserRepository) Delete(id string) error {
+	return guardedErr(r.guard, func() error { return r.next.Delete(id) })
+}
+
+func (r guardedUserRepository) CreateToken(token models.UserToken) (models.UserToken, error) {
+	return guarded(r.guard, func() (models.UserToken, error) { return r.next.CreateToken(token) })
+}
+
+func (r guardedUserRepository) ConsumeToken(token string, purpose models.TokenPurpose) (models.UserToken, error) {
+	return guarded(r.guard, func() (models.UserToken, error) { return r.next.ConsumeToken(token, purpose) })
+}
+
+func (r guardedUserRepository) CreateAccessToken(token models.PersonalAccessToken) (models.PersonalAccessToken, error) {
+	return guarded(r.guard, func() (models.PersonalAccessToken, error) { return r.next.CreateAccessToken(token) })
+}
+
+func (r guardedUserRepository) ListAccessTokens(userID string) ([]models.PersonalAccessToken, error) {
+	return guarded(r.guard, func() ([]models.PersonalAccessToken, error) { return r.next.ListAccessTokens(userID) })
+}
+
+func (r guardedUserRepository) GetAccessTokenByHash(secretHash string) (models.PersonalAccessToken, error) {
+	return guarded(r.guard, func() (models.PersonalAccessToken, error) { return r.next.GetAccessTokenByHash(secretHash) })
+}
+
+func (r guardedUserRepository) TouchAccessToken(id string, usedAt time.Time, client models.TokenClient) error {
+	return guardedErr(r.guard, func() error { return r.next.TouchAccessToken(id, usedAt, client) })
+}
+
+func (r guardedUserRepository) DeleteAccessToken(userID, id string) error {
+	return guardedErr(r.guard, func() error { return r.next.DeleteAccessToken(userID, id) })
+}
+
+func (r guardedUserRepository) DeleteAccessTokens(userID string) (int, error) {
+	return guarded(r.guard, func() (int, error) { return r.next.DeleteAccessTokens(userID) })
+}
+
+func (r guardedUserRepository) Snapshot() (models.UserStoreSnapshot, error) {
+	return guarded(r.guard, r.next.Snapshot)
+}
+
+func (r guardedUserRepository) Restore(snapshot models.UserStoreSnapshot) error {
+	return guardedErr(r.guard, func() error { return r.next.Restore(snapshot) })
+}
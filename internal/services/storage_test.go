@@ -0,0 +1,108 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"meetsync/internal/health"
+	"meetsync/internal/models"
+	"meetsync/internal/repositories"
+	"meetsync/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// unreachableMeetingRepository fails the next failures meeting lookups as if storage
+// could not be reached, or did not answer in time
+type unreachableMeetingRepository struct {
+	repositories.MeetingRepository
+	failures int
+	timeout  bool
+	wrapped  bool // timeouts are reported as unavailable, as the Postgres repositories do
+	calls    int
+}
+
+func (r *unreachableMeetingRepository) GetMeetingByID(id string) (models.Meeting, error) {
+	r.calls++
+	if r.failures > 0 {
+		r.failures--
+		if r.timeout && r.wrapped {
+			return models.Meeting{}, errors.NewUnavailableError("query timed out", time.Second, errors.NewTimeoutError("query timed out", nil))
+		}
+		if r.timeout {
+			return models.Meeting{}, errors.NewTimeoutError("query timed out", nil)
+		}
+		return models.Meeting{}, errors.NewUnavailableError("connection refused", 0, nil)
+	}
+	return r.MeetingRepository.GetMeetingByID(id)
+}
+
+func TestStorageGuard(t *testing.T) {
+	monitor := health.NewStorageMonitor()
+	guard := newStorageGuard(monitor)
+	var slept []time.Duration
+	guard.sleep = func(d time.Duration) { slept = append(slept, d) }
+
+	store := repositories.NewInMemoryMeetingRepository()
+	meeting, err := store.CreateMeeting(models.Meeting{Title: "Standup"})
+	assert.NoError(t, err)
+	unreachable := &unreachableMeetingRepository{MeetingRepository: store}
+	repo := guardedMeetingRepository{next: unreachable, guard: guard}
+
+	// A short blip is retried with backoff
+	unreachable.failures = 2
+	found, err := repo.GetMeetingByID(meeting.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, meeting.ID, found.ID)
+	assert.Equal(t, 3, unreachable.calls)
+	assert.Equal(t, []time.Duration{50 * time.Millisecond, 100 * time.Millisecond}, slept)
+	assert.True(t, monitor.Available())
+
+	// Exhausted retries surface as a 503 with Retry-After and mark storage unavailable
+	unreachable.failures, unreachable.calls = 10, 0
+	_, err = repo.GetMeetingByID(meeting.ID)
+	appErr, ok := err.(*errors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusServiceUnavailable, appErr.HTTPStatusCode())
+	assert.Equal(t, defaultStorageRetry.retryAfter, appErr.RetryAfter)
+	assert.Equal(t, 3, unreachable.calls)
+	assert.False(t, monitor.Available())
+	assert.NotNil(t, monitor.Status().UnavailableSince)
+	w := httptest.NewRecorder()
+	errors.WriteError(w, err)
+	assert.Equal(t, "5", w.Header().Get("Retry-After"))
+
+	// While degraded, operations fail fast without retrying
+	unreachable.calls = 0
+	_, err = repo.GetMeetingByID(meeting.ID)
+	assert.True(t, errors.IsUnavailable(err))
+	assert.Equal(t, 1, unreachable.calls)
+
+	// Other errors are returned unchanged and show storage is reachable again
+	unreachable.failures = 0
+	_, err = repo.GetMeetingByID("missing")
+	appErr, ok = err.(*errors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, errors.ErrorTypeNotFound, appErr.Type)
+	assert.True(t, monitor.Available())
+
+	// Timeouts are not retried, since the operation may have applied
+	unreachable.failures, unreachable.timeout, unreachable.calls = 1, true, 0
+	_, err = repo.GetMeetingByID(meeting.ID)
+	assert.True(t, errors.IsTimeout(err))
+	assert.Equal(t, 1, unreachable.calls)
+	assert.False(t, monitor.Available())
+
+	// nor are unavailable errors caused by a timeout, which keep their Retry-After
+	monitor.MarkAvailable()
+	unreachable.failures, unreachable.wrapped, unreachable.calls = 1, true, 0
+	_, err = repo.GetMeetingByID(meeting.ID)
+	appErr, ok = errors.AsAppError(err)
+	assert.True(t, ok)
+	assert.Equal(t, errors.ErrorTypeUnavailable, appErr.Type)
+	assert.Equal(t, time.Second, appErr.RetryAfter)
+	assert.Equal(t, 1, unreachable.calls)
+	assert.False(t, monitor.Available())
+}
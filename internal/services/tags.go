@@ -0,0 +1,87 @@
+package services
+
+import (
+	"sort"
+	"strings"
+
+	"meetsync/internal/models"
+	"meetsync/internal/sanitize"
+	"meetsync/pkg/errors"
+)
+
+const (
+	// maxTagLength caps the length of a single meeting tag
+	maxTagLength = 50
+	// maxTagsPerMeeting caps the number of tags on a meeting
+	maxTagsPerMeeting = 20
+)
+
+// normalizeTags lowercases, deduplicates and sorts tags so that filtering is case-insensitive
+func normalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(sanitize.SingleLine(tag))
+		if tag == "" {
+			return nil, errors.NewValidationError("Tags must not be empty", "")
+		}
+		if err := sanitize.CheckLength("Tag", tag, maxTagLength); err != nil {
+			return nil, err
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	if len(normalized) > maxTagsPerMeeting {
+		return nil, errors.NewValidationError("Too many tags", "a meeting can carry at most 20 tags")
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// tagStats summarizes meetings and their availability by tag, busiest tags first
+func tagStats(meetings []models.Meeting, availabilities []models.Availability) []models.TagStats {
+	responded := make(map[string]map[string]bool)
+	for _, availability := range availabilities {
+		if responded[availability.MeetingID] == nil {
+			responded[availability.MeetingID] = make(map[string]bool)
+		}
+		responded[availability.MeetingID][availability.ParticipantID] = true
+	}
+
+	byTag := make(map[string]*models.TagStats)
+	for _, meeting := range meetings {
+		responses := 0
+		for _, participant := range meeting.Participants {
+			if responded[meeting.ID][participant.ID] {
+				responses++
+			}
+		}
+		for _, tag := range meeting.Tags {
+			stats, found := byTag[tag]
+			if !found {
+				stats = &models.TagStats{Tag: tag}
+				byTag[tag] = stats
+			}
+			stats.Meetings++
+			stats.Participants += len(meeting.Participants)
+			stats.AvailabilityResponses += responses
+		}
+	}
+
+	result := make([]models.TagStats, 0, len(byTag))
+	for _, stats := range byTag {
+		if stats.Participants > 0 {
+			stats.ResponseRate = float64(stats.AvailabilityResponses) / float64(stats.Participants)
+		}
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Meetings != result[j].Meetings {
+			return result[i].Meetings > result[j].Meetings
+		}
+		return result[i].Tag < result[j].Tag
+	})
+	return result
+}
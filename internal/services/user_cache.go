@@ -0,0 +1,147 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"meetsync/internal/models"
+	"meetsync/internal/repositories"
+)
+
+// maxCachedUsers caps the number of users kept by a userCache
+const maxCachedUsers = 10000
+
+// userCache keeps users read by ID for a short time so that validating large
+// participant lists does not look every participant up in storage again
+type userCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cachedUser
+}
+
+// cachedUser is a user kept by a userCache until it expires
+type cachedUser struct {
+	user      models.User
+	expiresAt time.Time
+}
+
+func newUserCache(ttl time.Duration) *userCache {
+	return &userCache{ttl: ttl, now: time.Now, entries: make(map[string]cachedUser)}
+}
+
+// get returns the cached user with the ID, if it has not expired
+func (c *userCache) get(id string) (models.User, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, found := c.entries[id]
+	if !found || !c.now().Before(entry.expiresAt) {
+		return models.User{}, false
+	}
+	return entry.user, true
+}
+
+// put caches users, dropping expired users first when the cache is full
+func (c *userCache) put(users ...models.User) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if len(c.entries)+len(users) > maxCachedUsers {
+		for id, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, id)
+			}
+		}
+		if len(c.entries)+len(users) > maxCachedUsers {
+			clear(c.entries)
+		}
+	}
+	for _, user := range users {
+		c.entries[user.ID] = cachedUser{user: user, expiresAt: now.Add(c.ttl)}
+	}
+}
+
+// invalidate drops the users with the IDs, or every user when none is given
+func (c *userCache) invalidate(ids ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(ids) == 0 {
+		clear(c.entries)
+		return
+	}
+	for _, id := range ids {
+		delete(c.entries, id)
+	}
+}
+
+// cachedUserRepository serves user lookups by ID from a userCache, invalidating
+// cached users once they are written through it. A lookup racing a write may cache
+// the previous version of a user, which the TTL bounds.
+type cachedUserRepository struct {
+	repositories.UserRepository
+	cache *userCache
+}
+
+func (r cachedUserRepository) GetByID(id string) (models.User, error) {
+	if user, found := r.cache.get(id); found {
+		return user, nil
+	}
+	user, err := r.UserRepository.GetByID(id)
+	if err != nil {
+		return models.User{}, err
+	}
+	r.cache.put(user)
+	return user, nil
+}
+
+// GetByIDs looks up only the users missing from the cache, keeping the order of ids
+func (r cachedUserRepository) GetByIDs(ids []string) ([]models.User, error) {
+	found := make(map[string]models.User, len(ids))
+	var missing []string
+	for _, id := range ids {
+		if user, ok := r.cache.get(id); ok {
+			found[id] = user
+		} else {
+			missing = append(missing, id)
+		}
+	}
+
+	if len(missing) > 0 {
+		fetched, err := r.UserRepository.GetByIDs(missing)
+		if err != nil {
+			return nil, err
+		}
+		r.cache.put(fetched...)
+		for _, user := range fetched {
+			found[user.ID] = user
+		}
+	}
+
+	users := make([]models.User, 0, len(found))
+	for _, id := range ids {
+		if user, ok := found[id]; ok {
+			users = append(users, user)
+			delete(found, id)
+		}
+	}
+	return users, nil
+}
+
+func (r cachedUserRepository) Update(user models.User) (models.User, error) {
+	defer r.cache.invalidate(user.ID)
+	return r.UserRepository.Update(user)
+}
+
+func (r cachedUserRepository) Delete(id string) error {
+	defer r.cache.invalidate(id)
+	return r.UserRepository.Delete(id)
+}
+
+func (r cachedUserRepository) Restore(snapshot models.UserStoreSnapshot) error {
+	defer r.cache.invalidate()
+	return r.UserRepository.Restore(snapshot)
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"meetsync/internal/models"
+	"meetsync/internal/repositories"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingUserRepository counts the users looked up in the underlying repository
+type countingUserRepository struct {
+	repositories.UserRepository
+	lookups int
+}
+
+func (r *countingUserRepository) GetByID(id string) (models.User, error) {
+	r.lookups++
+	return r.UserRepository.GetByID(id)
+}
+
+func (r *countingUserRepository) GetByIDs(ids []string) ([]models.User, error) {
+	r.lookups += len(ids)
+	return r.UserRepository.GetByIDs(ids)
+}
+
+func TestCachedUserRepository(t *testing.T) {
+	store := repositories.NewInMemoryUserRepository()
+	jane, err := store.Create(models.User{Name: "Jane", Email: "jane@example.com"})
+	assert.NoError(t, err)
+	john, err := store.Create(models.User{Name: "John", Email: "john@example.com"})
+	assert.NoError(t, err)
+
+	counting := &countingUserRepository{UserRepository: store}
+	cache := newUserCache(time.Minute)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	repo := cachedUserRepository{UserRepository: counting, cache: cache}
+
+	// Repeated lookups are served from the cache
+	for i := 0; i < 3; i++ {
+		user, err := repo.GetByID(jane.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Jane", user.Name)
+	}
+	assert.Equal(t, 1, counting.lookups)
+
+	// Batch lookups only fetch the users missing from the cache, in the order asked for
+	users, err := repo.GetByIDs([]string{john.ID, "missing", jane.ID, john.ID})
+	assert.NoError(t, err)
+	if assert.Len(t, users, 2) {
+		assert.Equal(t, john.ID, users[0].ID)
+		assert.Equal(t, jane.ID, users[1].ID)
+	}
+	assert.Equal(t, 4, counting.lookups)
+
+	// Writes invalidate the user at once
+	jane.Name = "Jane Doe"
+	_, err = repo.Update(jane)
+	assert.NoError(t, err)
+	user, err := repo.GetByID(jane.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Jane Doe", user.Name)
+	assert.Equal(t, 5, counting.lookups)
+
+	assert.NoError(t, repo.Delete(john.ID))
+	_, err = repo.GetByID(john.ID)
+	assert.Error(t, err)
+
+	// Users expire after the TTL
+	now = now.Add(time.Minute)
+	_, err = repo.GetByID(jane.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, counting.lookups)
+}
@@ -1,32 +1,122 @@
 package services
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/mail"
+	"sort"
+	"strings"
+	"time"
+
+	"meetsync/internal/health"
 	"meetsync/internal/interfaces"
 	"meetsync/internal/models"
+	"meetsync/internal/notifications"
 	"meetsync/internal/repositories"
+	"meetsync/internal/sanitize"
 	"meetsync/pkg/errors"
+	"meetsync/pkg/logs"
+)
+
+const (
+	// emailChangeTokenTTL is how long an email change confirmation token stays valid
+	emailChangeTokenTTL = 24 * time.Hour
+	// emailVerificationTokenTTL is how long a signup verification token stays valid
+	emailVerificationTokenTTL = 72 * time.Hour
 )
 
 // UserServiceImpl implements the UserService interface
 type UserServiceImpl struct {
 	repository repositories.UserRepository
+	notifier   notifications.Notifier
+	templates  *notifications.Templates
+	textLimits sanitize.Limits
+	storage    *health.StorageMonitor
+	cacheTTL   time.Duration
 }
 
 var _ interfaces.UserService = (*UserServiceImpl)(nil) // Verify UserServiceImpl implements UserService interface
 
+// UserServiceOption configures optional dependencies of a UserServiceImpl
+type UserServiceOption func(*UserServiceImpl)
+
+// WithUserNotifier sets the notifier used to send account emails to users
+func WithUserNotifier(notifier notifications.Notifier) UserServiceOption {
+	return func(s *UserServiceImpl) {
+		s.notifier = notifier
+	}
+}
+
+// WithUserNotificationTemplates sets the templates rendering account emails
+func WithUserNotificationTemplates(templates *notifications.Templates) UserServiceOption {
+	return func(s *UserServiceImpl) {
+		s.templates = templates
+	}
+}
+
+// WithUserTextLimits sets the maximum lengths of free-text user fields
+func WithUserTextLimits(limits sanitize.Limits) UserServiceOption {
+	return func(s *UserServiceImpl) {
+		s.textLimits = limits
+	}
+}
+
+// WithUserStorageMonitor sets the monitor recording whether user storage is reachable
+func WithUserStorageMonitor(monitor *health.StorageMonitor) UserServiceOption {
+	return func(s *UserServiceImpl) {
+		s.storage = monitor
+	}
+}
+
+// WithUserCacheTTL caches users looked up by ID, such as meeting participants, for ttl;
+// zero disables the cache. Users written through the service are invalidated at once.
+func WithUserCacheTTL(ttl time.Duration) UserServiceOption {
+	return func(s *UserServiceImpl) {
+		s.cacheTTL = ttl
+	}
+}
+
+// WithUserRepository sets the repository users are stored in, such as one opened by a
+// registered storage driver
+func WithUserRepository(repository repositories.UserRepository) UserServiceOption {
+	return func(s *UserServiceImpl) {
+		s.repository = repository
+	}
+}
+
 // NewUserService creates a new UserService
-func NewUserService() interfaces.UserService {
-	return &UserServiceImpl{
+func NewUserService(opts ...UserServiceOption) interfaces.UserService {
+	s := &UserServiceImpl{
 		repository: repositories.NewInMemoryUserRepository(),
+		notifier:   notifications.NoopNotifier{},
+		templates:  notifications.DefaultTemplates(),
+		textLimits: sanitize.DefaultLimits,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.repository = guardedUserRepository{next: s.repository, guard: newStorageGuard(s.storage)}
+	if s.cacheTTL > 0 {
+		s.repository = cachedUserRepository{UserRepository: s.repository, cache: newUserCache(s.cacheTTL)}
 	}
+	return s
 }
 
 // CreateUser creates a new user
 func (s *UserServiceImpl) CreateUser(name, email string) (models.User, error) {
-	// Validate input
-	if name == "" {
-		return models.User{}, errors.NewValidationError("Name is required", "")
+	return s.createUser(name, email, true)
+}
+
+// createUser creates a new user, sending the welcome email asking them to verify their
+// address when welcome is set
+func (s *UserServiceImpl) createUser(name, email string, welcome bool) (models.User, error) {
+	// Sanitize and validate input
+	name, err := s.validateName(name)
+	if err != nil {
+		return models.User{}, err
 	}
+	email = sanitize.Email(email)
 	if email == "" {
 		return models.User{}, errors.NewValidationError("Email is required", "")
 	}
@@ -38,15 +128,578 @@ func (s *UserServiceImpl) CreateUser(name, email string) (models.User, error) {
 	}
 
 	// Create user using repository
+	createdUser, err := s.repository.Create(user)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	// A failed verification email must not fail signup; the user can ask for a new one
+	if welcome {
+		if _, err := s.sendVerification(createdUser); err != nil {
+			logs.Warn("Failed to send verification email to user %s: %v", createdUser.ID, err)
+		}
+	}
+	return createdUser, nil
+}
+
+// EnsureGuest returns the guest user with email, creating one named name, or after the
+// email when name is empty, if the email is not in use yet. Members of the organization
+// cannot be invited as guests.
+func (s *UserServiceImpl) EnsureGuest(email, name string) (models.User, error) {
+	email = sanitize.Email(email)
+	if err := validateEmail(email); err != nil {
+		return models.User{}, err
+	}
+	if existing, found := s.repository.GetByEmail(email); found {
+		if !existing.Guest {
+			return models.User{}, errors.NewConflictError("Email belongs to a member of the organization; add them as a participant")
+		}
+		return existing, nil
+	}
+
+	if strings.TrimSpace(name) == "" {
+		name, _, _ = strings.Cut(email, "@")
+	}
+	name, err := s.validateName(name)
+	if err != nil {
+		return models.User{}, err
+	}
+	return s.repository.Create(models.User{Name: name, Email: email, Guest: true})
+}
+
+// validateName sanitizes a user name and checks it is present and within limits
+func (s *UserServiceImpl) validateName(name string) (string, error) {
+	name = sanitize.SingleLine(name)
+	if name == "" {
+		return "", errors.NewValidationError("Name is required", "")
+	}
+	if err := sanitize.CheckLength("Name", name, s.textLimits.Name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// validateEmail checks an email is a bare address such as jane@example.com
+func validateEmail(email string) error {
+	if address, err := mail.ParseAddress(email); err != nil || address.Address != email {
+		return errors.NewValidationError("Invalid email", "")
+	}
+	return nil
+}
+
+// ImportUsers creates a user per row. Rows are imported independently: invalid rows
+// are reported as failed without stopping the import, and rows whose email is already
+// in use, by an existing user or an earlier row, are handled by options.OnDuplicate.
+func (s *UserServiceImpl) ImportUsers(rows []models.UserImportRow, options models.UserImportOptions) (models.UserImportResult, error) {
+	if options.OnDuplicate == "" {
+		options.OnDuplicate = models.DuplicateSkip
+	}
+	if !options.OnDuplicate.Valid() {
+		return models.UserImportResult{}, errors.NewValidationError("Invalid duplicate strategy", "use skip, update or fail")
+	}
+
+	result := models.UserImportResult{Rows: make([]models.UserImportRowResult, 0, len(rows))}
+	for _, row := range rows {
+		rowResult := s.importUser(row, options)
+		switch rowResult.Status {
+		case models.UserImportCreated:
+			result.Created++
+		case models.UserImportUpdated:
+			result.Updated++
+		case models.UserImportSkipped:
+			result.Skipped++
+		case models.UserImportFailed:
+			result.Failed++
+		}
+		result.Rows = append(result.Rows, rowResult)
+	}
+	return result, nil
+}
+
+// importUser imports a single row
+func (s *UserServiceImpl) importUser(row models.UserImportRow, options models.UserImportOptions) models.UserImportRowResult {
+	email := sanitize.Email(row.Email)
+	result := models.UserImportRowResult{Line: row.Line, Email: email}
+	fail := func(err error) models.UserImportRowResult {
+		result.Status = models.UserImportFailed
+		if appErr, ok := errors.AsAppError(err); ok {
+			result.Error = appErr.Message
+			if appErr.Details != "" {
+				result.Error += ": " + appErr.Details
+			}
+		} else {
+			result.Error = err.Error()
+		}
+		return result
+	}
+
+	if err := validateEmail(email); err != nil {
+		return fail(err)
+	}
+	name, err := s.validateName(row.Name)
+	if err != nil {
+		return fail(err)
+	}
+
+	existing, found := s.repository.GetByEmail(email)
+	if !found {
+		user, err := s.createUser(name, email, options.Welcome)
+		if err != nil {
+			return fail(err)
+		}
+		result.Status, result.UserID = models.UserImportCreated, user.ID
+		return result
+	}
+
+	result.UserID = existing.ID
+	switch options.OnDuplicate {
+	case models.DuplicateUpdate:
+		if existing.Name == name {
+			result.Status = models.UserImportSkipped
+			return result
+		}
+		existing.Name = name
+		if _, err := s.repository.Update(existing); err != nil {
+			return fail(err)
+		}
+		result.Status = models.UserImportUpdated
+	case models.DuplicateFail:
+		return fail(errors.NewConflictError("Email is already in use"))
+	default:
+		result.Status = models.UserImportSkipped
+	}
+	return result
+}
+
+// ProvisionUser creates a user asserted by an identity provider. The provider vouches
+// for the email address, so it is marked as verified and no welcome email is sent.
+func (s *UserServiceImpl) ProvisionUser(input models.UserProvisioning) (models.User, error) {
+	user, err := s.applyProvisioning(models.User{}, input)
+	if err != nil {
+		return models.User{}, err
+	}
 	return s.repository.Create(user)
 }
 
+// UpdateProvisionedUser replaces the attributes of a user managed by an identity
+// provider, deactivating or reactivating them to match input.Active
+func (s *UserServiceImpl) UpdateProvisionedUser(userID string, input models.UserProvisioning) (models.User, error) {
+	user, err := s.repository.GetByID(userID)
+	if err != nil {
+		return models.User{}, err
+	}
+	if user, err = s.applyProvisioning(user, input); err != nil {
+		return models.User{}, err
+	}
+	// The repository checks the new email is not in use
+	return s.repository.Update(user)
+}
+
+// applyProvisioning validates input and applies it to user
+func (s *UserServiceImpl) applyProvisioning(user models.User, input models.UserProvisioning) (models.User, error) {
+	name, err := s.validateName(input.Name)
+	if err != nil {
+		return models.User{}, err
+	}
+	email := sanitize.Email(input.Email)
+	if err := validateEmail(email); err != nil {
+		return models.User{}, err
+	}
+
+	user.Name = name
+	user.Email = email
+	user.EmailVerified = true
+	user.ExternalID = strings.TrimSpace(input.ExternalID)
+	switch {
+	case input.Active:
+		user.DeactivatedAt = nil
+	case user.Active():
+		now := time.Now()
+		user.DeactivatedAt = &now
+	}
+	return user, nil
+}
+
+// VerifyEmail redeems a signup verification token and marks the user's email as verified
+func (s *UserServiceImpl) VerifyEmail(token string) (models.User, error) {
+	if token == "" {
+		return models.User{}, errors.NewValidationError("Token is required", "")
+	}
+
+	stored, err := s.repository.ConsumeToken(token, models.TokenPurposeEmailVerification)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	user, err := s.repository.GetByID(stored.UserID)
+	if err != nil {
+		return models.User{}, err
+	}
+	if !sanitize.SameEmail(user.Email, stored.Email) {
+		return models.User{}, errors.NewValidationError("Token was issued for a different email address", "")
+	}
+
+	user.EmailVerified = true
+	return s.repository.Update(user)
+}
+
+// SetFocusBlocks replaces the recurring focus blocks of a user
+func (s *UserServiceImpl) SetFocusBlocks(userID string, blocks []models.FocusBlock) (models.User, error) {
+	if _, err := compileFocusBlocks(blocks); err != nil {
+		return models.User{}, err
+	}
+
+	user, err := s.repository.GetByID(userID)
+	if err != nil {
+		return models.User{}, err
+	}
+	user.FocusBlocks = blocks
+	return s.repository.Update(user)
+}
+
+// SetDigest sets how often a user receives a digest instead of an email per invitation
+// or reconfirmation request; DigestOff sends them as they happen again
+func (s *UserServiceImpl) SetDigest(userID string, frequency models.DigestFrequency) (models.User, error) {
+	if !frequency.Valid() {
+		return models.User{}, errors.NewValidationError("Invalid digest frequency", "use daily, weekly or off")
+	}
+
+	user, err := s.repository.GetByID(userID)
+	if err != nil {
+		return models.User{}, err
+	}
+	if user.Guest && frequency != models.DigestOff {
+		return models.User{}, errors.NewValidationError("Guests cannot receive digests", "guests are emailed the link to their meeting when invited")
+	}
+	if user.Digest != frequency {
+		// The first digest of the new schedule goes out with the next run
+		user.DigestSentAt = nil
+	}
+	user.Digest = frequency
+	return s.repository.Update(user)
+}
+
+// RecordDigest records that a digest covered a user at sentAt
+func (s *UserServiceImpl) RecordDigest(userID string, sentAt time.Time) error {
+	user, err := s.repository.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	user.DigestSentAt = &sentAt
+	_, err = s.repository.Update(user)
+	return err
+}
+
+// ResendVerification issues a new verification token, invalidating the previous one
+func (s *UserServiceImpl) ResendVerification(userID string) error {
+	user, err := s.repository.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified {
+		return errors.NewConflictError("Email is already verified")
+	}
+
+	if _, err := s.sendVerification(user); err != nil {
+		return errors.NewDependencyError("Failed to send verification email", err)
+	}
+	return nil
+}
+
+// sendVerification issues a verification token for the user's current email and sends it
+func (s *UserServiceImpl) sendVerification(user models.User) (models.UserToken, error) {
+	value, err := newToken()
+	if err != nil {
+		return models.UserToken{}, err
+	}
+
+	token, err := s.repository.CreateToken(models.UserToken{
+		Token:     value,
+		UserID:    user.ID,
+		Purpose:   models.TokenPurposeEmailVerification,
+		Email:     user.Email,
+		ExpiresAt: time.Now().Add(emailVerificationTokenTTL),
+	})
+	if err != nil {
+		return models.UserToken{}, err
+	}
+
+	message, err := s.templates.Render(notifications.KindEmailVerification, notifications.EmailVerificationData{
+		Recipient: user.Name,
+		Token:     token.Token,
+		ExpiresAt: token.ExpiresAt,
+	})
+	if err != nil {
+		return models.UserToken{}, err
+	}
+
+	notification := notifications.Notification{
+		Kind:    notifications.KindEmailVerification,
+		UserID:  user.ID,
+		To:      user.Email,
+		Subject: message.Subject,
+		Body:    message.Text,
+		HTML:    message.HTML,
+	}
+	if err := s.notifier.Send(notification); err != nil {
+		return models.UserToken{}, err
+	}
+	return token, nil
+}
+
 // GetUserByID retrieves a user by their ID
 func (s *UserServiceImpl) GetUserByID(userID string) (models.User, error) {
 	return s.repository.GetByID(userID)
 }
 
+// GetUsersByIDs retrieves the users matching ids in a single lookup; unknown IDs are skipped
+func (s *UserServiceImpl) GetUsersByIDs(userIDs []string) ([]models.User, error) {
+	return s.repository.GetByIDs(userIDs)
+}
+
 // ListUsers retrieves all users
 func (s *UserServiceImpl) ListUsers() ([]models.User, error) {
 	return s.repository.GetAll()
 }
+
+// Snapshot returns a copy of the user store for backups
+func (s *UserServiceImpl) Snapshot() (models.UserStoreSnapshot, error) {
+	return s.repository.Snapshot()
+}
+
+// Restore replaces the user store with a snapshot taken from a backup
+func (s *UserServiceImpl) Restore(snapshot models.UserStoreSnapshot) error {
+	return s.repository.Restore(snapshot)
+}
+
+// DeleteUser removes a user
+func (s *UserServiceImpl) DeleteUser(userID string) error {
+	return s.repository.Delete(userID)
+}
+
+// RequestEmailChange sends a confirmation token to the new address. The
+// user's email is only changed once the token is confirmed.
+func (s *UserServiceImpl) RequestEmailChange(userID, newEmail string) (models.UserToken, error) {
+	newEmail = sanitize.Email(newEmail)
+	if newEmail == "" {
+		return models.UserToken{}, errors.NewValidationError("Email is required", "")
+	}
+
+	user, err := s.repository.GetByID(userID)
+	if err != nil {
+		return models.UserToken{}, err
+	}
+	if existing, found := s.repository.GetByEmail(newEmail); found {
+		if existing.ID == user.ID {
+			return models.UserToken{}, errors.NewValidationError("New email must differ from the current email", "")
+		}
+		return models.UserToken{}, errors.NewConflictError("Email is already in use")
+	}
+
+	value, err := newToken()
+	if err != nil {
+		return models.UserToken{}, errors.NewInternalError("Failed to generate token", err)
+	}
+
+	token, err := s.repository.CreateToken(models.UserToken{
+		Token:     value,
+		UserID:    user.ID,
+		Purpose:   models.TokenPurposeEmailChange,
+		Email:     newEmail,
+		ExpiresAt: time.Now().Add(emailChangeTokenTTL),
+	})
+	if err != nil {
+		return models.UserToken{}, err
+	}
+
+	message, err := s.templates.Render(notifications.KindEmailChange, notifications.EmailChangeData{
+		Recipient: user.Name,
+		Email:     newEmail,
+		Token:     token.Token,
+		ExpiresAt: token.ExpiresAt,
+	})
+	if err != nil {
+		return models.UserToken{}, errors.NewInternalError("Failed to render confirmation email", err)
+	}
+
+	notification := notifications.Notification{
+		Kind:    notifications.KindEmailChange,
+		UserID:  user.ID,
+		To:      newEmail,
+		Subject: message.Subject,
+		Body:    message.Text,
+		HTML:    message.HTML,
+	}
+	if err := s.notifier.Send(notification); err != nil {
+		return models.UserToken{}, errors.NewDependencyError("Failed to send confirmation email", err)
+	}
+
+	return token, nil
+}
+
+// ConfirmEmailChange redeems an email change token and updates the user's email
+func (s *UserServiceImpl) ConfirmEmailChange(token string) (models.User, error) {
+	if token == "" {
+		return models.User{}, errors.NewValidationError("Token is required", "")
+	}
+
+	stored, err := s.repository.ConsumeToken(token, models.TokenPurposeEmailChange)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	user, err := s.repository.GetByID(stored.UserID)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	// The repository re-checks uniqueness in case the address was taken meanwhile.
+	// Redeeming the token proves ownership of the new address.
+	user.Email = stored.Email
+	user.EmailVerified = true
+	return s.repository.Update(user)
+}
+
+// accessTokenPrefix marks personal access token secrets so they are easy to recognize
+const accessTokenPrefix = "msp_"
+
+// CreateAccessToken mints a personal access token with the given scopes. The
+// returned secret is not stored and cannot be retrieved again.
+func (s *UserServiceImpl) CreateAccessToken(userID, name string, scopes []models.Scope) (models.PersonalAccessToken, string, error) {
+	if err := validateAccessToken(name, scopes); err != nil {
+		return models.PersonalAccessToken{}, "", err
+	}
+
+	value, err := newToken()
+	if err != nil {
+		return models.PersonalAccessToken{}, "", errors.NewInternalError("Failed to generate token", err)
+	}
+	return s.createAccessToken(userID, name, scopes, accessTokenPrefix+value)
+}
+
+// createAccessToken stores a personal access token authenticating with secret
+func (s *UserServiceImpl) createAccessToken(userID, name string, scopes []models.Scope, secret string) (models.PersonalAccessToken, string, error) {
+	token, err := s.repository.CreateAccessToken(models.PersonalAccessToken{
+		UserID:     userID,
+		Name:       name,
+		Scopes:     scopes,
+		SecretHash: hashSecret(secret),
+	})
+	if err != nil {
+		return models.PersonalAccessToken{}, "", err
+	}
+	return token, secret, nil
+}
+
+// ListAccessTokens lists a user's personal access tokens
+func (s *UserServiceImpl) ListAccessTokens(userID string) ([]models.PersonalAccessToken, error) {
+	return s.repository.ListAccessTokens(userID)
+}
+
+// RevokeAccessToken deletes one of a user's personal access tokens
+func (s *UserServiceImpl) RevokeAccessToken(userID, tokenID string) error {
+	return s.repository.DeleteAccessToken(userID, tokenID)
+}
+
+// RevokeAccessTokens deletes every personal access token of a user, signing them out
+// of every script and tool at once, and returns how many were revoked
+func (s *UserServiceImpl) RevokeAccessTokens(userID string) (int, error) {
+	return s.repository.DeleteAccessTokens(userID)
+}
+
+// ListSessions lists the clients a user is signed in on with personal access tokens,
+// most recently used first. Tokens that never authenticated a request are left out.
+func (s *UserServiceImpl) ListSessions(userID string) ([]models.Session, error) {
+	tokens, err := s.repository.ListAccessTokens(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]models.Session, 0, len(tokens))
+	for _, token := range tokens {
+		if token.LastUsedAt == nil {
+			continue
+		}
+		session := models.Session{
+			TokenID:    token.ID,
+			TokenName:  token.Name,
+			Scopes:     token.Scopes,
+			LastUsedAt: *token.LastUsedAt,
+			CreatedAt:  token.CreatedAt,
+		}
+		if token.LastUsedFrom != nil {
+			session.Client = *token.LastUsedFrom
+		}
+		sessions = append(sessions, session)
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt)
+	})
+	return sessions, nil
+}
+
+// AuthenticateAccessToken resolves a personal access token secret and records its use
+// by client
+func (s *UserServiceImpl) AuthenticateAccessToken(secret string, client models.TokenClient) (models.PersonalAccessToken, error) {
+	if !strings.HasPrefix(secret, accessTokenPrefix) {
+		return models.PersonalAccessToken{}, errors.NewUnauthorizedError("Invalid access token")
+	}
+
+	token, err := s.repository.GetAccessTokenByHash(hashSecret(secret))
+	if err != nil {
+		return models.PersonalAccessToken{}, errors.NewUnauthorizedError("Invalid access token")
+	}
+	user, err := s.repository.GetByID(token.UserID)
+	if err != nil || !user.Active() {
+		return models.PersonalAccessToken{}, errors.NewUnauthorizedError("Invalid access token")
+	}
+
+	now := time.Now()
+	if err := s.repository.TouchAccessToken(token.ID, now, client); err != nil {
+		return models.PersonalAccessToken{}, err
+	}
+	token.LastUsedAt = &now
+	token.LastUsedFrom = &client
+	return token, nil
+}
+
+// validateAccessToken checks the name and scopes of a personal access token
+func validateAccessToken(name string, scopes []models.Scope) error {
+	if name == "" {
+		return errors.NewValidationError("Name is required", "")
+	}
+	if len(scopes) == 0 {
+		return errors.NewValidationError("At least one scope is required", "")
+	}
+	for _, scope := range scopes {
+		if !isValidScope(scope) {
+			return errors.NewValidationError("Invalid scope", string(scope))
+		}
+	}
+	return nil
+}
+
+// isValidScope reports whether the scope is a known scope
+func isValidScope(scope models.Scope) bool {
+	for _, valid := range models.ValidScopes {
+		if scope == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// hashSecret returns the hex-encoded SHA-256 hash of a secret
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// newToken generates a random, URL-safe token
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -1,8 +1,14 @@
 package services
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"meetsync/internal/models"
+	"meetsync/internal/notifications"
 	"meetsync/pkg/errors"
 
 	"github.com/stretchr/testify/assert"
@@ -60,6 +66,29 @@ func TestUserService_CreateUser(t *testing.T) {
 	}
 }
 
+func TestUserService_NormalizesEmail(t *testing.T) {
+	service := NewUserService()
+
+	user, err := service.CreateUser("John Doe", "John@X.com ")
+	assert.NoError(t, err)
+	assert.Equal(t, "john@x.com", user.Email)
+
+	// Whichever code path handles the address, it is the same one
+	_, err = service.CreateUser("John Again", "john@x.com")
+	assert.True(t, errors.Is(err, errors.ErrConflict))
+	_, err = service.EnsureGuest(" JOHN@x.com", "")
+	assert.True(t, errors.Is(err, errors.ErrConflict))
+	imported, err := service.ImportUsers([]models.UserImportRow{{Line: 2, Name: "John", Email: "john@X.COM"}}, models.UserImportOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, imported.Skipped)
+
+	other, err := service.CreateUser("Jose", "Jose\u0301@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "jos\u00e9@example.com", other.Email)
+	_, err = service.RequestEmailChange(user.ID, "JOS\u00c9@example.com")
+	assert.True(t, errors.Is(err, errors.ErrConflict))
+}
+
 func TestUserService_GetUserByID(t *testing.T) {
 	service := NewUserService()
 
@@ -132,3 +161,417 @@ func TestUserService_ListUsers(t *testing.T) {
 		assert.NotEmpty(t, user.Email)
 	}
 }
+
+func TestUserService_EmailChange(t *testing.T) {
+	notifier := &recordingNotifier{}
+	service := NewUserService(WithUserNotifier(notifier))
+
+	user, err := service.CreateUser("John Doe", "john@example.com")
+	assert.NoError(t, err)
+	_, err = service.CreateUser("Jane Doe", "jane@example.com")
+	assert.NoError(t, err)
+
+	// Addresses already in use are rejected up front
+	_, err = service.RequestEmailChange(user.ID, "JANE@example.com")
+	assert.Error(t, err)
+	appErr, ok := err.(*errors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, errors.ErrorTypeConflict, appErr.Type)
+
+	notifier.notifications = nil
+	token, err := service.RequestEmailChange(user.ID, "john@new.example.com")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token.Token)
+	if assert.Len(t, notifier.notifications, 1) {
+		assert.Equal(t, notifications.KindEmailChange, notifier.notifications[0].Kind)
+		assert.Equal(t, "john@new.example.com", notifier.notifications[0].To)
+		assert.Contains(t, notifier.notifications[0].Body, token.Token)
+	}
+
+	// The email is unchanged until the token is confirmed
+	current, err := service.GetUserByID(user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "john@example.com", current.Email)
+
+	updated, err := service.ConfirmEmailChange(token.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, "john@new.example.com", updated.Email)
+	assert.True(t, updated.EmailVerified)
+
+	// Tokens are single-use
+	_, err = service.ConfirmEmailChange(token.Token)
+	assert.Error(t, err)
+	appErr, ok = err.(*errors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, errors.ErrorTypeNotFound, appErr.Type)
+}
+
+func TestUserService_EmailChangeTakenBeforeConfirmation(t *testing.T) {
+	service := NewUserService()
+
+	user, err := service.CreateUser("John Doe", "john@example.com")
+	assert.NoError(t, err)
+
+	token, err := service.RequestEmailChange(user.ID, "shared@example.com")
+	assert.NoError(t, err)
+
+	// Another user registers the address while the change is pending
+	_, err = service.CreateUser("Someone Else", "shared@example.com")
+	assert.NoError(t, err)
+
+	_, err = service.ConfirmEmailChange(token.Token)
+	assert.Error(t, err)
+	appErr, ok := err.(*errors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, errors.ErrorTypeConflict, appErr.Type)
+}
+
+func TestUserService_EmailVerification(t *testing.T) {
+	notifier := &recordingNotifier{}
+	service := NewUserService(WithUserNotifier(notifier))
+
+	user, err := service.CreateUser("John Doe", "john@example.com")
+	assert.NoError(t, err)
+	assert.False(t, user.EmailVerified)
+
+	// A verification token is sent on signup
+	if !assert.Len(t, notifier.notifications, 1) {
+		return
+	}
+	assert.Equal(t, notifications.KindEmailVerification, notifier.notifications[0].Kind)
+	assert.Equal(t, "john@example.com", notifier.notifications[0].To)
+	firstToken := tokenFromBody(notifier.notifications[0].Body)
+
+	// Resending invalidates the previous token
+	assert.NoError(t, service.ResendVerification(user.ID))
+	if !assert.Len(t, notifier.notifications, 2) {
+		return
+	}
+	secondToken := tokenFromBody(notifier.notifications[1].Body)
+
+	_, err = service.VerifyEmail(firstToken)
+	assert.Error(t, err)
+
+	verified, err := service.VerifyEmail(secondToken)
+	assert.NoError(t, err)
+	assert.True(t, verified.EmailVerified)
+
+	// Verified users cannot request another token
+	err = service.ResendVerification(user.ID)
+	assert.Error(t, err)
+	appErr, ok := err.(*errors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, errors.ErrorTypeConflict, appErr.Type)
+
+	_, err = service.VerifyEmail("unknown")
+	assert.Error(t, err)
+}
+
+func TestMeetingService_RequiresVerifiedOrganizer(t *testing.T) {
+	notifier := &recordingNotifier{}
+	userService := NewUserService(WithUserNotifier(notifier))
+	meetingService := NewMeetingService(userService, WithEmailVerificationRequired(true))
+
+	organizer, err := userService.CreateUser("Organizer", "organizer@example.com")
+	assert.NoError(t, err)
+	participant, err := userService.CreateUser("Participant", "participant@example.com")
+	assert.NoError(t, err)
+
+	input := models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       organizer.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     createTestTimeSlots(),
+		ParticipantIDs:    []string{participant.ID},
+	}
+
+	// Unverified users cannot organize meetings
+	_, _, err = meetingService.CreateMeeting(input)
+	assert.Error(t, err)
+	appErr, ok := err.(*errors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, errors.ErrorTypeValidation, appErr.Type)
+
+	_, err = userService.VerifyEmail(tokenFromBody(notifier.notifications[0].Body))
+	assert.NoError(t, err)
+
+	meeting, _, err := meetingService.CreateMeeting(input)
+	assert.NoError(t, err)
+
+	// Unverified participants can still respond
+	_, err = meetingService.AddAvailability(participant.ID, meeting.ID, meeting.ProposedSlots, false)
+	assert.NoError(t, err)
+}
+
+func TestUserService_EmailTemplates(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "email_verification.subject.tmpl"), []byte("[Acme] Welcome {{.Recipient}}"), 0o600))
+	templates, err := notifications.LoadTemplates(dir)
+	assert.NoError(t, err)
+	notifier := &recordingNotifier{}
+	service := NewUserService(WithUserNotifier(notifier), WithUserNotificationTemplates(templates))
+
+	_, err = service.CreateUser("John Doe", "john@example.com")
+	assert.NoError(t, err)
+	if assert.Len(t, notifier.notifications, 1) {
+		assert.Equal(t, "[Acme] Welcome John Doe", notifier.notifications[0].Subject)
+		assert.NotEmpty(t, tokenFromBody(notifier.notifications[0].Body), "the built-in body is kept")
+	}
+}
+
+// tokenFromBody extracts the token from a notification body
+func tokenFromBody(body string) string {
+	for _, field := range strings.Fields(body) {
+		if len(field) == 64 {
+			return field
+		}
+	}
+	return ""
+}
+
+func TestUserService_AccessTokens(t *testing.T) {
+	service := NewUserService()
+
+	user, err := service.CreateUser("John Doe", "john@example.com")
+	assert.NoError(t, err)
+
+	// Scopes are validated
+	_, _, err = service.CreateAccessToken(user.ID, "sync", []models.Scope{"admin:everything"})
+	assert.Error(t, err)
+	_, _, err = service.CreateAccessToken(user.ID, "sync", nil)
+	assert.Error(t, err)
+
+	token, secret, err := service.CreateAccessToken(user.ID, "sync", []models.Scope{models.ScopeReadMeetings})
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(secret, "msp_"))
+	assert.NotContains(t, token.SecretHash, secret)
+	assert.Nil(t, token.LastUsedAt)
+
+	// Authenticating records when the token was last used
+	authenticated, err := service.AuthenticateAccessToken(secret, models.TokenClient{})
+	assert.NoError(t, err)
+	assert.Equal(t, token.ID, authenticated.ID)
+	assert.True(t, authenticated.HasScope(models.ScopeReadMeetings))
+	assert.False(t, authenticated.HasScope(models.ScopeWriteAvailability))
+
+	tokens, err := service.ListAccessTokens(user.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, tokens, 1) {
+		assert.NotNil(t, tokens[0].LastUsedAt)
+	}
+
+	// Revoked tokens no longer authenticate
+	assert.NoError(t, service.RevokeAccessToken(user.ID, token.ID))
+	_, err = service.AuthenticateAccessToken(secret, models.TokenClient{})
+	assert.Error(t, err)
+	appErr, ok := err.(*errors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, errors.ErrorTypeUnauthorized, appErr.Type)
+
+	err = service.RevokeAccessToken(user.ID, token.ID)
+	assert.Error(t, err)
+
+	// Sessions show the tokens in use and where from, most recently used first
+	_, first, err := service.CreateAccessToken(user.ID, "laptop", []models.Scope{models.ScopeReadMeetings})
+	assert.NoError(t, err)
+	_, second, err := service.CreateAccessToken(user.ID, "phone", []models.Scope{models.ScopeReadMeetings})
+	assert.NoError(t, err)
+	_, _, err = service.CreateAccessToken(user.ID, "unused", []models.Scope{models.ScopeReadMeetings})
+	assert.NoError(t, err)
+	laptop := models.TokenClient{IP: "192.0.2.1", UserAgent: "curl/8.0"}
+	phone := models.TokenClient{IP: "198.51.100.7", UserAgent: "MeetSync/2.1 (iOS)"}
+	_, err = service.AuthenticateAccessToken(first, laptop)
+	assert.NoError(t, err)
+	time.Sleep(time.Millisecond)
+	_, err = service.AuthenticateAccessToken(second, phone)
+	assert.NoError(t, err)
+	sessions, err := service.ListSessions(user.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, sessions, 2) {
+		assert.Equal(t, "phone", sessions[0].TokenName)
+		assert.Equal(t, phone, sessions[0].Client)
+		assert.Equal(t, "laptop", sessions[1].TokenName)
+		assert.Equal(t, laptop, sessions[1].Client)
+	}
+
+	// Revoking every token signs the user out everywhere
+	revoked, err := service.RevokeAccessTokens(user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, revoked)
+	for _, secret := range []string{first, second} {
+		_, err = service.AuthenticateAccessToken(secret, models.TokenClient{})
+		assert.Error(t, err)
+	}
+	tokens, err = service.ListAccessTokens(user.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, tokens)
+	sessions, err = service.ListSessions(user.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, sessions)
+
+	_, err = service.RevokeAccessTokens("missing")
+	assert.Error(t, err)
+}
+
+func TestUserService_SetFocusBlocks(t *testing.T) {
+	service := NewUserService()
+	user, err := service.CreateUser("Focused", "focused@example.com")
+	assert.NoError(t, err)
+
+	blocks := []models.FocusBlock{{Days: []string{"mon", "wed"}, Start: "09:00", End: "11:00", TimeZone: "Europe/Paris"}}
+	updated, err := service.SetFocusBlocks(user.ID, blocks)
+	assert.NoError(t, err)
+	assert.Equal(t, blocks, updated.FocusBlocks)
+
+	stored, err := service.GetUserByID(user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, blocks, stored.FocusBlocks)
+
+	invalid := map[string]models.FocusBlock{
+		"missing end":       {Start: "09:00"},
+		"ends before start": {Start: "11:00", End: "09:00"},
+		"unknown day":       {Days: []string{"someday"}, Start: "09:00", End: "11:00"},
+		"unknown time zone": {Start: "09:00", End: "11:00", TimeZone: "Mars/Olympus"},
+	}
+	for name, block := range invalid {
+		_, err := service.SetFocusBlocks(user.ID, []models.FocusBlock{block})
+		assert.Error(t, err, name)
+	}
+
+	// Clearing removes every block
+	cleared, err := service.SetFocusBlocks(user.ID, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, cleared.FocusBlocks)
+
+	_, err = service.SetFocusBlocks("missing", blocks)
+	assert.Error(t, err)
+}
+
+func TestUserService_ImportUsers(t *testing.T) {
+	notifier := &recordingNotifier{}
+	service := NewUserService(WithUserNotifier(notifier))
+	existing, err := service.CreateUser("Jane Doe", "jane@example.com")
+	assert.NoError(t, err)
+	notifier.notifications = nil
+
+	rows := []models.UserImportRow{
+		{Line: 2, Name: "John Roe", Email: "john@example.com"},
+		{Line: 3, Name: "Jane Smith", Email: "JANE@example.com"},
+		{Line: 4, Name: "John Again", Email: "john@example.com"},
+		{Line: 5, Name: "No Email", Email: ""},
+		{Line: 6, Name: "Bad Email", Email: "not an email"},
+		{Line: 7, Name: "<b></b>", Email: "nameless@example.com"},
+	}
+
+	t.Run("skip duplicates", func(t *testing.T) {
+		result, err := service.ImportUsers(rows, models.UserImportOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Created)
+		assert.Equal(t, 2, result.Skipped)
+		assert.Equal(t, 3, result.Failed)
+		if assert.Len(t, result.Rows, len(rows)) {
+			assert.Equal(t, models.UserImportCreated, result.Rows[0].Status)
+			assert.Equal(t, models.UserImportSkipped, result.Rows[1].Status)
+			assert.Equal(t, existing.ID, result.Rows[1].UserID)
+			assert.Equal(t, result.Rows[0].UserID, result.Rows[2].UserID)
+			assert.Equal(t, 5, result.Rows[3].Line)
+			assert.Equal(t, "Invalid email", result.Rows[3].Error)
+			assert.Equal(t, "Name is required", result.Rows[5].Error)
+		}
+		// Welcome emails are opt-in
+		assert.Empty(t, notifier.notifications)
+	})
+
+	t.Run("update duplicates", func(t *testing.T) {
+		result, err := service.ImportUsers(rows[1:2], models.UserImportOptions{OnDuplicate: models.DuplicateUpdate})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Updated)
+		user, err := service.GetUserByID(existing.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Jane Smith", user.Name)
+	})
+
+	t.Run("fail duplicates", func(t *testing.T) {
+		result, err := service.ImportUsers(rows[:1], models.UserImportOptions{OnDuplicate: models.DuplicateFail})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Failed)
+		assert.Equal(t, "Email is already in use", result.Rows[0].Error)
+	})
+
+	t.Run("welcome created users", func(t *testing.T) {
+		result, err := service.ImportUsers([]models.UserImportRow{{Line: 2, Name: "New Hire", Email: "new@example.com"}},
+			models.UserImportOptions{Welcome: true})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Created)
+		if assert.Len(t, notifier.notifications, 1) {
+			assert.Equal(t, notifications.KindEmailVerification, notifier.notifications[0].Kind)
+			assert.Equal(t, "new@example.com", notifier.notifications[0].To)
+		}
+	})
+
+	_, err = service.ImportUsers(rows, models.UserImportOptions{OnDuplicate: "merge"})
+	assert.ErrorIs(t, err, errors.ErrValidation)
+}
+
+func TestUserService_ProvisionUser(t *testing.T) {
+	notifier := &recordingNotifier{}
+	userService := NewUserService(WithUserNotifier(notifier))
+	meetingService := NewMeetingService(userService)
+
+	// Identity providers vouch for the email, so no verification email is sent
+	user, err := userService.ProvisionUser(models.UserProvisioning{ExternalID: "00u1", Name: "Jane Doe", Email: "jane@example.com", Active: true})
+	assert.NoError(t, err)
+	assert.True(t, user.EmailVerified)
+	assert.True(t, user.Active())
+	assert.Equal(t, "00u1", user.ExternalID)
+	assert.Empty(t, notifier.notifications)
+
+	_, err = userService.ProvisionUser(models.UserProvisioning{Name: "Jane Again", Email: "JANE@example.com", Active: true})
+	assert.True(t, errors.Is(err, errors.ErrConflict))
+	_, err = userService.ProvisionUser(models.UserProvisioning{Name: "Jane Doe", Email: "jane", Active: true})
+	assert.True(t, errors.Is(err, errors.ErrValidation))
+
+	participant, err := userService.CreateUser("Participant", "participant@example.com")
+	assert.NoError(t, err)
+	meeting, _, err := meetingService.CreateMeeting(models.MeetingInput{
+		Title:             "Test Meeting",
+		OrganizerID:       user.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     createTestTimeSlots(),
+		ParticipantIDs:    []string{participant.ID},
+	})
+	assert.NoError(t, err)
+	_, secret, err := userService.CreateAccessToken(user.ID, "sync", []models.Scope{models.ScopeReadMeetings})
+	assert.NoError(t, err)
+
+	// Deactivated users cannot authenticate, organize meetings or respond to them
+	user, err = userService.UpdateProvisionedUser(user.ID, models.UserProvisioning{ExternalID: "00u1", Name: "Jane Smith", Email: "jane.smith@example.com"})
+	assert.NoError(t, err)
+	assert.False(t, user.Active())
+	assert.Equal(t, "Jane Smith", user.Name)
+	assert.Equal(t, "jane.smith@example.com", user.Email)
+
+	_, err = userService.AuthenticateAccessToken(secret, models.TokenClient{})
+	assert.True(t, errors.Is(err, errors.ErrUnauthorized))
+	_, _, err = meetingService.CreateMeeting(models.MeetingInput{
+		Title:             "Another Meeting",
+		OrganizerID:       user.ID,
+		EstimatedDuration: 60,
+		ProposedSlots:     createTestTimeSlots(),
+	})
+	assert.True(t, errors.Is(err, errors.ErrValidation))
+	_, err = meetingService.AddAvailability(user.ID, meeting.ID, meeting.ProposedSlots, false)
+	assert.True(t, errors.Is(err, errors.ErrValidation))
+
+	// Reactivating restores access
+	user, err = userService.UpdateProvisionedUser(user.ID, models.UserProvisioning{Name: "Jane Smith", Email: "jane.smith@example.com", Active: true})
+	assert.NoError(t, err)
+	assert.True(t, user.Active())
+	_, err = userService.AuthenticateAccessToken(secret, models.TokenClient{})
+	assert.NoError(t, err)
+
+	_, err = userService.UpdateProvisionedUser(user.ID, models.UserProvisioning{Name: "Jane Smith", Email: "participant@example.com", Active: true})
+	assert.True(t, errors.Is(err, errors.ErrConflict))
+	_, err = userService.UpdateProvisionedUser("missing", models.UserProvisioning{Name: "Jane Smith", Email: "jane@example.com", Active: true})
+	assert.True(t, errors.Is(err, errors.ErrNotFound))
+}
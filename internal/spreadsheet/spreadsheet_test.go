@@ -0,0 +1,63 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	table := Table{
+		Name: "Q3 planning: slots",
+		Rows: [][]any{
+			{"Slot", "Available", "Ada <ada@example.com>"},
+			{"2030-01-07 09:00", 3, "yes, tentative"},
+		},
+	}
+
+	file, err := Render(table, FormatCSV)
+	require.NoError(t, err)
+	assert.Equal(t, "Slot,Available,Ada <ada@example.com>\n2030-01-07 09:00,3,\"yes, tentative\"\n", string(file))
+	assert.Equal(t, "text/csv; charset=utf-8", ContentType(FormatCSV))
+
+	file, err = Render(table, FormatXLSX)
+	require.NoError(t, err)
+	archive, err := zip.NewReader(bytes.NewReader(file), int64(len(file)))
+	require.NoError(t, err)
+	parts := map[string]string{}
+	for _, part := range archive.File {
+		r, err := part.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		parts[part.Name] = string(content)
+
+		// Every part is well-formed XML
+		decoder := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			if _, err := decoder.Token(); err == io.EOF {
+				break
+			} else {
+				require.NoError(t, err, part.Name)
+			}
+		}
+	}
+	assert.Contains(t, parts, "[Content_Types].xml")
+	assert.Contains(t, parts["xl/workbook.xml"], `name="Q3 planning- slots"`)
+	assert.Contains(t, parts["xl/worksheets/sheet1.xml"], `<c r="B2"><v>3</v></c>`)
+	assert.Contains(t, parts["xl/worksheets/sheet1.xml"], `Ada &lt;ada@example.com&gt;`)
+
+	_, err = Render(table, "ods")
+	assert.Error(t, err)
+}
+
+func TestColumnName(t *testing.T) {
+	for index, name := range map[int]string{0: "A", 25: "Z", 26: "AA", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"} {
+		assert.Equal(t, name, columnName(index), index)
+	}
+}
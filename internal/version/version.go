@@ -0,0 +1,94 @@
+// Package version reports which build of the service is running.
+//
+// Release builds set the version, commit and build date with the linker:
+//
+//	go build -ldflags "-X meetsync/internal/version.version=v1.4.0 \
+//		-X meetsync/internal/version.commit=$(git rev-parse HEAD) \
+//		-X meetsync/internal/version.buildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/meetsync
+//
+// Without them, the version and commit recorded by the Go toolchain are reported.
+package version
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// devVersion is reported for binaries built from a source tree rather than a tagged module
+const devVersion = "dev"
+
+// Set with -ldflags "-X meetsync/internal/version.<name>=<value>"
+var (
+	version   string
+	commit    string
+	buildDate string
+)
+
+// Info identifies a build of the service
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`    // VCS revision, suffixed with "-dirty" for uncommitted changes
+	BuildDate string `json:"buildDate,omitempty"` // RFC 3339, when set at build time
+}
+
+// String describes the build on one line, as printed by the --version flag
+func (i Info) String() string {
+	s := i.Version
+	if i.Commit != "" {
+		s += fmt.Sprintf(" (commit %s", i.Commit)
+		if i.BuildDate != "" {
+			s += ", built " + i.BuildDate
+		}
+		s += ")"
+	} else if i.BuildDate != "" {
+		s += fmt.Sprintf(" (built %s)", i.BuildDate)
+	}
+	return s
+}
+
+var (
+	once sync.Once
+	info Info
+)
+
+// Get returns the version, commit and build date of the running binary, preferring
+// the values set with the linker over those recorded by the Go toolchain
+func Get() Info {
+	once.Do(func() {
+		info = fromBuildInfo(debug.ReadBuildInfo())
+		if version != "" {
+			info.Version = version
+		}
+		if commit != "" {
+			info.Commit = commit
+		}
+		info.BuildDate = buildDate
+	})
+	return info
+}
+
+// fromBuildInfo reads the module version and VCS revision of a build
+func fromBuildInfo(build *debug.BuildInfo, ok bool) Info {
+	result := Info{Version: devVersion}
+	if !ok {
+		return result
+	}
+	if v := build.Main.Version; v != "" && v != "(devel)" {
+		result.Version = v
+	}
+
+	var modified bool
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			result.Commit = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if result.Commit != "" && modified {
+		result.Commit += "-dirty"
+	}
+	return result
+}
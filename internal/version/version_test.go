@@ -0,0 +1,50 @@
+package version
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromBuildInfo(t *testing.T) {
+	tests := []struct {
+		name  string
+		build *debug.BuildInfo
+		ok    bool
+		want  Info
+	}{
+		{name: "no build info", want: Info{Version: devVersion}},
+		{
+			name:  "source tree",
+			build: &debug.BuildInfo{Main: debug.Module{Version: "(devel)"}},
+			ok:    true,
+			want:  Info{Version: devVersion},
+		},
+		{
+			name: "tagged module with uncommitted changes",
+			build: &debug.BuildInfo{
+				Main: debug.Module{Version: "v1.4.0"},
+				Settings: []debug.BuildSetting{
+					{Key: "vcs.revision", Value: "9f2c1e7"},
+					{Key: "vcs.modified", Value: "true"},
+				},
+			},
+			ok:   true,
+			want: Info{Version: "v1.4.0", Commit: "9f2c1e7-dirty"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, fromBuildInfo(tt.build, tt.ok))
+		})
+	}
+}
+
+func TestInfoString(t *testing.T) {
+	assert.Equal(t, "dev", Info{Version: "dev"}.String())
+	assert.Equal(t, "v1.4.0 (commit 9f2c1e7, built 2025-01-10T08:00:00Z)",
+		Info{Version: "v1.4.0", Commit: "9f2c1e7", BuildDate: "2025-01-10T08:00:00Z"}.String())
+	assert.Equal(t, "v1.4.0 (built 2025-01-10T08:00:00Z)", Info{Version: "v1.4.0", BuildDate: "2025-01-10T08:00:00Z"}.String())
+}
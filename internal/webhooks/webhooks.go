@@ -0,0 +1,399 @@
+// Package webhooks delivers scheduling events to endpoints registered by integrators,
+// keeping the history of every delivery so failed ones can be inspected and replayed
+// once the receiver is fixed.
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"meetsync/internal/events"
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+	"meetsync/pkg/logs"
+)
+
+const (
+	// SignatureHeader carries the hex HMAC-SHA256 of the body, keyed by the webhook secret
+	SignatureHeader = "X-MeetSync-Signature"
+	// EventHeader carries the type of the delivered event
+	EventHeader = "X-MeetSync-Event"
+	// DeliveryHeader carries the ID of the delivery, the same across retries and replays
+	DeliveryHeader = "X-MeetSync-Delivery"
+
+	// maxAttempts caps the attempts made for a delivery, and again for each replay
+	maxAttempts = 3
+	// maxDeliveries caps the deliveries kept per webhook; the oldest are forgotten first
+	maxDeliveries = 100
+	// queueSize caps the number of attempts waiting for the worker
+	queueSize = 1024
+	// defaultRetryDelay is the delay before the first retry; it doubles with every retry
+	defaultRetryDelay = 5 * time.Second
+	// defaultTimeout caps how long a receiver can take to answer
+	defaultTimeout = 5 * time.Second
+)
+
+// Dispatcher is an events.Publisher delivering events to registered webhooks. Webhooks
+// and deliveries are kept in memory, so they are lost when the process exits.
+type Dispatcher struct {
+	mu         sync.Mutex
+	webhooks   map[string]models.Webhook
+	deliveries map[string][]*delivery // by webhook, oldest first
+	queue      chan *delivery
+	done       chan struct{}
+	once       sync.Once
+	closed     bool
+	client     *http.Client
+	retryDelay time.Duration
+	now        func() time.Time
+}
+
+var _ events.Publisher = (*Dispatcher)(nil) // Verify Dispatcher implements Publisher
+
+// delivery is the delivery of an event to a webhook along with the body sent
+type delivery struct {
+	record    models.WebhookDelivery
+	body      []byte
+	remaining int  // attempts left before the delivery fails
+	replay    bool // attempts are made because the delivery was replayed
+}
+
+// NewDispatcher creates a Dispatcher and starts its worker
+func NewDispatcher() *Dispatcher {
+	d := &Dispatcher{
+		webhooks:   make(map[string]models.Webhook),
+		deliveries: make(map[string][]*delivery),
+		queue:      make(chan *delivery, queueSize),
+		done:       make(chan struct{}),
+		client:     &http.Client{Timeout: defaultTimeout},
+		retryDelay: defaultRetryDelay,
+		now:        time.Now,
+	}
+	go d.work()
+	return d
+}
+
+// Register adds a webhook receiving events of the given types, or of every type when
+// none is given. The returned webhook holds the secret signing its deliveries.
+func (d *Dispatcher) Register(rawURL string, types []string) (models.Webhook, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return models.Webhook{}, errors.NewValidationError("Invalid webhook URL", "use an absolute http or https URL")
+	}
+	// Aliases are stored as the type emitted so deliveries match them
+	var subscribedTypes []string
+	for _, name := range types {
+		eventType, ok := events.Lookup(name)
+		if !ok {
+			return models.Webhook{}, errors.NewValidationError("Unknown event type: "+name, "")
+		}
+		subscribedTypes = append(subscribedTypes, string(eventType))
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return models.Webhook{}, errors.NewInternalError("Failed to generate webhook secret", err)
+	}
+
+	webhook := models.Webhook{
+		ID:        uuid.New().String(),
+		URL:       target.String(),
+		Events:    subscribedTypes,
+		Secret:    hex.EncodeToString(secret),
+		CreatedAt: d.now(),
+	}
+	d.mu.Lock()
+	d.webhooks[webhook.ID] = webhook
+	d.mu.Unlock()
+
+	logs.Info("Registered webhook %s for %s", webhook.ID, target.Host)
+	return webhook, nil
+}
+
+// List returns the registered webhooks, oldest first, without their secrets
+func (d *Dispatcher) List() []models.Webhook {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	webhooks := make([]models.Webhook, 0, len(d.webhooks))
+	for _, webhook := range d.webhooks {
+		webhook.Secret = ""
+		webhooks = append(webhooks, webhook)
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].CreatedAt.Before(webhooks[j].CreatedAt) })
+	return webhooks
+}
+
+// Delete removes a webhook and its deliveries; pending deliveries are not attempted
+func (d *Dispatcher) Delete(id string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.webhooks[id]; !ok {
+		return errors.NewNotFoundError("Webhook not found")
+	}
+	delete(d.webhooks, id)
+	delete(d.deliveries, id)
+	logs.Info("Deleted webhook %s", id)
+	return nil
+}
+
+// Deliveries returns the deliveries of a webhook, newest first, optionally only those
+// with the given status
+func (d *Dispatcher) Deliveries(webhookID string, status models.WebhookDeliveryStatus) ([]models.WebhookDelivery, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.webhooks[webhookID]; !ok {
+		return nil, errors.NewNotFoundError("Webhook not found")
+	}
+	deliveries := d.deliveries[webhookID]
+	records := make([]models.WebhookDelivery, 0, len(deliveries))
+	for i := len(deliveries) - 1; i >= 0; i-- {
+		if status == "" || deliveries[i].record.Status == status {
+			records = append(records, deliveries[i].snapshot())
+		}
+	}
+	return records, nil
+}
+
+// Replay delivers events of a webhook again, with the same delivery and event IDs so
+// receivers can recognize duplicates. Without IDs, every failed delivery is replayed;
+// deliveries given by ID are replayed even if they succeeded.
+func (d *Dispatcher) Replay(webhookID string, deliveryIDs []string) ([]models.WebhookDelivery, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return nil, errors.NewUnavailableError("Webhook deliveries are shutting down", 10*time.Second, nil)
+	}
+	if _, ok := d.webhooks[webhookID]; !ok {
+		return nil, errors.NewNotFoundError("Webhook not found")
+	}
+
+	byID := make(map[string]*delivery, len(d.deliveries[webhookID]))
+	for _, delivery := range d.deliveries[webhookID] {
+		byID[delivery.record.ID] = delivery
+	}
+	var selected []*delivery
+	if len(deliveryIDs) == 0 {
+		for _, delivery := range d.deliveries[webhookID] {
+			if delivery.record.Status == models.WebhookDeliveryFailed {
+				selected = append(selected, delivery)
+			}
+		}
+	}
+	for _, id := range deliveryIDs {
+		delivery, ok := byID[id]
+		if !ok {
+			return nil, errors.NewNotFoundError("Delivery not found: " + id)
+		}
+		if delivery.record.Status == models.WebhookDeliveryPending {
+			return nil, errors.NewConflictError("Delivery is still pending: " + id)
+		}
+		selected = append(selected, delivery)
+	}
+
+	replayed := make([]models.WebhookDelivery, 0, len(selected))
+	for _, delivery := range selected {
+		delivery.remaining = maxAttempts
+		delivery.replay = true
+		delivery.record.Status = models.WebhookDeliveryPending
+		delivery.record.UpdatedAt = d.now()
+		d.enqueueLocked(delivery)
+		replayed = append(replayed, delivery.snapshot())
+	}
+	logs.Info("Replaying %d deliveries of webhook %s", len(replayed), webhookID)
+	return replayed, nil
+}
+
+// Publish implements events.Publisher, queuing a delivery of the event to every webhook
+// subscribed to its type. It never blocks.
+func (d *Dispatcher) Publish(event events.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("webhooks: failed to encode event: %w", err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return fmt.Errorf("webhooks: dispatcher is closed")
+	}
+
+	now := d.now()
+	for id, webhook := range d.webhooks {
+		if !subscribed(webhook, event.Type) {
+			continue
+		}
+		delivery := &delivery{
+			record: models.WebhookDelivery{
+				ID:        uuid.New().String(),
+				WebhookID: id,
+				EventID:   event.ID,
+				EventType: string(event.Type),
+				Status:    models.WebhookDeliveryPending,
+				Attempts:  []models.WebhookAttempt{},
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			body:      body,
+			remaining: maxAttempts,
+		}
+		deliveries := append(d.deliveries[id], delivery)
+		if len(deliveries) > maxDeliveries {
+			deliveries = deliveries[len(deliveries)-maxDeliveries:]
+		}
+		d.deliveries[id] = deliveries
+		d.enqueueLocked(delivery)
+	}
+	return nil
+}
+
+// Close stops accepting events and waits for queued attempts; retries scheduled for
+// later are abandoned and their deliveries marked failed
+func (d *Dispatcher) Close() error {
+	d.once.Do(func() {
+		d.mu.Lock()
+		d.closed = true
+		close(d.queue)
+		d.mu.Unlock()
+	})
+	<-d.done
+	return nil
+}
+
+// enqueueLocked queues the next attempt of a delivery, failing it when the queue is
+// full; d.mu must be held
+func (d *Dispatcher) enqueueLocked(delivery *delivery) {
+	if d.closed {
+		d.failLocked(delivery, "webhook deliveries are shutting down")
+		return
+	}
+	select {
+	case d.queue <- delivery:
+	default:
+		d.failLocked(delivery, "delivery queue is full")
+	}
+}
+
+// failLocked records an attempt that could not be made and fails the delivery; d.mu
+// must be held
+func (d *Dispatcher) failLocked(delivery *delivery, reason string) {
+	now := d.now()
+	delivery.record.Attempts = append(delivery.record.Attempts, models.WebhookAttempt{At: now, Error: reason, Replay: delivery.replay})
+	delivery.record.Status = models.WebhookDeliveryFailed
+	delivery.record.UpdatedAt = now
+	logs.Warn("Failed to deliver %s event to webhook %s: %s", delivery.record.EventType, delivery.record.WebhookID, reason)
+}
+
+func (d *Dispatcher) work() {
+	defer close(d.done)
+	for delivery := range d.queue {
+		d.attempt(delivery)
+	}
+}
+
+// attempt makes one attempt at a delivery and schedules a retry if it fails
+func (d *Dispatcher) attempt(delivery *delivery) {
+	d.mu.Lock()
+	webhook, ok := d.webhooks[delivery.record.WebhookID]
+	d.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	start := d.now()
+	statusCode, err := d.send(webhook, delivery)
+	attempt := models.WebhookAttempt{
+		At:         start,
+		StatusCode: statusCode,
+		DurationMs: d.now().Sub(start).Milliseconds(),
+		Replay:     delivery.replay,
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delivery.record.Attempts = append(delivery.record.Attempts, attempt)
+	delivery.record.UpdatedAt = d.now()
+	delivery.remaining--
+	switch {
+	case err == nil:
+		delivery.record.Status = models.WebhookDeliverySucceeded
+	case delivery.remaining > 0 && !d.closed:
+		// Back off exponentially between the attempts of a round
+		delay := d.retryDelay << (maxAttempts - delivery.remaining - 1)
+		time.AfterFunc(delay, func() {
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			d.enqueueLocked(delivery)
+		})
+	default:
+		delivery.record.Status = models.WebhookDeliveryFailed
+		logs.Warn("Failed to deliver %s event to webhook %s after %d attempts: %v", delivery.record.EventType, webhook.ID, maxAttempts, err)
+	}
+}
+
+// send posts the body of a delivery to its webhook, signed with the webhook secret
+func (d *Dispatcher) send(webhook models.Webhook, delivery *delivery) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(delivery.body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.record.EventType)
+	req.Header.Set(DeliveryHeader, delivery.record.ID)
+	req.Header.Set(SignatureHeader, "sha256="+Sign(webhook.Secret, delivery.body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("receiver returned status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// snapshot returns a copy of the delivery record that is safe to use without the lock
+func (delivery *delivery) snapshot() models.WebhookDelivery {
+	record := delivery.record
+	record.Attempts = append([]models.WebhookAttempt(nil), delivery.record.Attempts...)
+	return record
+}
+
+// Sign returns the hex HMAC-SHA256 of body keyed by secret, as sent in SignatureHeader
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// subscribed reports whether a webhook receives events of eventType
+func subscribed(webhook models.Webhook, eventType events.Type) bool {
+	if len(webhook.Events) == 0 {
+		return true
+	}
+	for _, subscribedType := range webhook.Events {
+		if strings.EqualFold(subscribedType, string(eventType)) {
+			return true
+		}
+	}
+	return false
+}
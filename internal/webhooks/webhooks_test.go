@@ -0,0 +1,144 @@
+package webhooks
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"meetsync/internal/events"
+	"meetsync/internal/models"
+	"meetsync/pkg/errors"
+)
+
+// receiver is a webhook endpoint that fails until it is fixed
+type receiver struct {
+	mu       sync.Mutex
+	fixed    bool
+	received []*http.Request
+	bodies   [][]byte
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.received = append(rc.received, r)
+	rc.bodies = append(rc.bodies, body)
+	if !rc.fixed {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func (rc *receiver) count() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return len(rc.received)
+}
+
+func newTestDispatcher(t *testing.T) *Dispatcher {
+	d := NewDispatcher()
+	d.retryDelay = time.Millisecond
+	t.Cleanup(func() { _ = d.Close() })
+	return d
+}
+
+func waitForStatus(t *testing.T, d *Dispatcher, webhookID string, status models.WebhookDeliveryStatus) models.WebhookDelivery {
+	var found models.WebhookDelivery
+	require.Eventually(t, func() bool {
+		deliveries, err := d.Deliveries(webhookID, status)
+		if err != nil || len(deliveries) == 0 {
+			return false
+		}
+		found = deliveries[0]
+		return true
+	}, 2*time.Second, 5*time.Millisecond)
+	return found
+}
+
+func TestDispatcher_DeliverAndReplay(t *testing.T) {
+	rc := &receiver{}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+	d := newTestDispatcher(t)
+
+	webhook, err := d.Register(server.URL+"/hooks", []string{string(events.MeetingCreated)})
+	require.NoError(t, err)
+	assert.NotEmpty(t, webhook.Secret)
+	assert.Empty(t, d.List()[0].Secret)
+
+	// Events of other types are not delivered
+	require.NoError(t, d.Publish(events.New(events.AvailabilityAdded, "meeting-1", nil)))
+	event := events.New(events.MeetingCreated, "meeting-1", nil)
+	require.NoError(t, d.Publish(event))
+
+	failed := waitForStatus(t, d, webhook.ID, models.WebhookDeliveryFailed)
+	assert.Equal(t, event.ID, failed.EventID)
+	assert.Len(t, failed.Attempts, maxAttempts)
+	assert.Equal(t, http.StatusInternalServerError, failed.Attempts[0].StatusCode)
+	assert.Equal(t, maxAttempts, rc.count())
+
+	// Deliveries are signed with the webhook secret
+	rc.mu.Lock()
+	first := rc.received[0]
+	assert.Equal(t, "sha256="+Sign(webhook.Secret, rc.bodies[0]), first.Header.Get(SignatureHeader))
+	assert.Equal(t, failed.ID, first.Header.Get(DeliveryHeader))
+	assert.Equal(t, string(events.MeetingCreated), first.Header.Get(EventHeader))
+	rc.fixed = true
+	rc.mu.Unlock()
+
+	// Once the receiver is fixed, failed deliveries are replayed with the same IDs
+	replayed, err := d.Replay(webhook.ID, nil)
+	require.NoError(t, err)
+	require.Len(t, replayed, 1)
+	assert.Equal(t, failed.ID, replayed[0].ID)
+	succeeded := waitForStatus(t, d, webhook.ID, models.WebhookDeliverySucceeded)
+	require.Len(t, succeeded.Attempts, maxAttempts+1)
+	assert.True(t, succeeded.Attempts[maxAttempts].Replay)
+	assert.Equal(t, http.StatusOK, succeeded.Attempts[maxAttempts].StatusCode)
+
+	// Nothing failed is left to replay, but deliveries can be replayed by ID
+	replayed, err = d.Replay(webhook.ID, nil)
+	require.NoError(t, err)
+	assert.Empty(t, replayed)
+	replayed, err = d.Replay(webhook.ID, []string{succeeded.ID})
+	require.NoError(t, err)
+	assert.Len(t, replayed, 1)
+	_, err = d.Replay(webhook.ID, []string{"missing"})
+	assert.True(t, errors.Is(err, errors.ErrNotFound))
+}
+
+func TestDispatcher_Webhooks(t *testing.T) {
+	d := newTestDispatcher(t)
+
+	for _, url := range []string{"", "ftp://example.com", "/hooks", "http://"} {
+		_, err := d.Register(url, nil)
+		assert.True(t, errors.Is(err, errors.ErrValidation), url)
+	}
+	_, err := d.Register("https://example.com/hooks", []string{"meeting.exploded"})
+	assert.True(t, errors.Is(err, errors.ErrValidation))
+
+	// Subscriptions to an alias receive the events emitted under that type
+	confirmed, err := d.Register("https://example.com/confirmed", []string{"meeting.confirmed"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{string(events.MeetingFinalized)}, confirmed.Events)
+	assert.True(t, subscribed(confirmed, events.MeetingFinalized))
+	require.NoError(t, d.Delete(confirmed.ID))
+
+	webhook, err := d.Register("https://example.com/hooks", nil)
+	require.NoError(t, err)
+	assert.Len(t, d.List(), 1)
+
+	require.NoError(t, d.Delete(webhook.ID))
+	assert.Empty(t, d.List())
+	assert.True(t, errors.Is(d.Delete(webhook.ID), errors.ErrNotFound))
+	_, err = d.Deliveries(webhook.ID, "")
+	assert.True(t, errors.Is(err, errors.ErrNotFound))
+	_, err = d.Replay(webhook.ID, nil)
+	assert.True(t, errors.Is(err, errors.ErrNotFound))
+}
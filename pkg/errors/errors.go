@@ -1,8 +1,15 @@
 package errors
 
 import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
 	"fmt"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 // ErrorType represents the type of error
@@ -19,14 +26,60 @@ const (
 	ErrorTypeInternal ErrorType = "INTERNAL"
 	// ErrorTypeUnauthorized represents unauthorized errors
 	ErrorTypeUnauthorized ErrorType = "UNAUTHORIZED"
+	// ErrorTypeForbidden represents authenticated callers acting on a resource their role
+	// does not allow them to change, such as a meeting they do not organize
+	ErrorTypeForbidden ErrorType = "FORBIDDEN"
+	// ErrorTypeUnavailable represents temporary failures of a dependency, such as storage
+	// being unreachable; the request can be retried
+	ErrorTypeUnavailable ErrorType = "UNAVAILABLE"
+	// ErrorTypeTimeout represents a dependency not answering in time; the request can be
+	// retried, but it may have taken effect
+	ErrorTypeTimeout ErrorType = "TIMEOUT"
+	// ErrorTypeQuotaExceeded represents requests exceeding a usage quota of the organization
+	ErrorTypeQuotaExceeded ErrorType = "QUOTA_EXCEEDED"
+	// ErrorTypeRateLimited represents clients sending requests faster than allowed; the
+	// request can be retried after a while
+	ErrorTypeRateLimited ErrorType = "RATE_LIMITED"
 )
 
+// Sentinel errors matching AppErrors of the corresponding type anywhere in an error
+// chain, e.g. errors.Is(err, ErrNotFound) for an error created by NewNotFoundError and
+// wrapped with fmt.Errorf("...: %w", err)
+var (
+	ErrValidation    = stderrors.New("validation error")
+	ErrNotFound      = stderrors.New("not found")
+	ErrConflict      = stderrors.New("conflict")
+	ErrUnauthorized  = stderrors.New("unauthorized")
+	ErrForbidden     = stderrors.New("forbidden")
+	ErrUnavailable   = stderrors.New("unavailable")
+	ErrTimeout       = stderrors.New("timeout")
+	ErrQuotaExceeded = stderrors.New("quota exceeded")
+	ErrRateLimited   = stderrors.New("rate limited")
+)
+
+// sentinels maps error types to the sentinel errors matching them
+var sentinels = map[ErrorType]error{
+	ErrorTypeValidation:    ErrValidation,
+	ErrorTypeNotFound:      ErrNotFound,
+	ErrorTypeConflict:      ErrConflict,
+	ErrorTypeUnauthorized:  ErrUnauthorized,
+	ErrorTypeForbidden:     ErrForbidden,
+	ErrorTypeUnavailable:   ErrUnavailable,
+	ErrorTypeTimeout:       ErrTimeout,
+	ErrorTypeQuotaExceeded: ErrQuotaExceeded,
+	ErrorTypeRateLimited:   ErrRateLimited,
+}
+
 // AppError represents an application error
 type AppError struct {
 	Type    ErrorType `json:"type"`
 	Message string    `json:"message"`
 	Details string    `json:"details,omitempty"`
 	Err     error     `json:"-"` // Internal error, not exposed in JSON
+
+	// RetryAfter is how long clients should wait before retrying, sent as the
+	// Retry-After header when set
+	RetryAfter time.Duration `json:"-"`
 }
 
 // Error implements the error interface
@@ -37,6 +90,17 @@ func (e *AppError) Error() string {
 	return fmt.Sprintf("%s: %s", e.Type, e.Message)
 }
 
+// Unwrap returns the underlying error, so errors.Is and errors.As see the cause
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the sentinel error for the type of e
+func (e *AppError) Is(target error) bool {
+	sentinel, ok := sentinels[e.Type]
+	return ok && sentinel == target
+}
+
 // NewValidationError creates a new validation error
 func NewValidationError(message string, details string) *AppError {
 	return &AppError{
@@ -79,6 +143,120 @@ func NewUnauthorizedError(message string) *AppError {
 	}
 }
 
+// NewForbiddenError creates a new error for a caller not allowed to act on a resource
+func NewForbiddenError(message string) *AppError {
+	return &AppError{
+		Type:    ErrorTypeForbidden,
+		Message: message,
+	}
+}
+
+// NewUnavailableError creates a new error for a temporarily unavailable dependency
+func NewUnavailableError(message string, retryAfter time.Duration, err error) *AppError {
+	return &AppError{
+		Type:       ErrorTypeUnavailable,
+		Message:    message,
+		Err:        err,
+		RetryAfter: retryAfter,
+	}
+}
+
+// NewTimeoutError creates a new error for a dependency that did not answer in time
+func NewTimeoutError(message string, err error) *AppError {
+	return &AppError{
+		Type:    ErrorTypeTimeout,
+		Message: message,
+		Err:     err,
+	}
+}
+
+// NewQuotaExceededError creates a new error for a request exceeding a usage quota
+func NewQuotaExceededError(message string, details string) *AppError {
+	return &AppError{
+		Type:    ErrorTypeQuotaExceeded,
+		Message: message,
+		Details: details,
+	}
+}
+
+// NewRateLimitedError creates a new error for a client sending requests faster than
+// allowed, who can retry after retryAfter
+func NewRateLimitedError(message string, retryAfter time.Duration) *AppError {
+	return &AppError{
+		Type:       ErrorTypeRateLimited,
+		Message:    message,
+		RetryAfter: retryAfter,
+	}
+}
+
+// NewDependencyError creates an error for a failed call to a dependency such as an
+// SMTP server, a calendar or storage, classified by its cause: timeouts become timeout
+// errors, network failures unavailable errors and anything else an internal error.
+func NewDependencyError(message string, err error) *AppError {
+	if cause, ok := AsAppError(err); ok && IsRetryable(cause) {
+		return &AppError{Type: cause.Type, Message: message, Err: err, RetryAfter: cause.RetryAfter}
+	}
+	var netErr net.Error
+	if stderrors.Is(err, context.DeadlineExceeded) || (stderrors.As(err, &netErr) && netErr.Timeout()) {
+		return NewTimeoutError(message, err)
+	}
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	if stderrors.As(err, &opErr) || stderrors.As(err, &dnsErr) {
+		return NewUnavailableError(message, 0, err)
+	}
+	return NewInternalError(message, err)
+}
+
+// Is reports whether any error in err's chain matches target; it is errors.Is from the
+// standard library, for callers importing this package as errors
+func Is(err, target error) bool {
+	return stderrors.Is(err, target)
+}
+
+// As finds the first error in err's chain that matches target; it is errors.As from the
+// standard library, for callers importing this package as errors
+func As(err error, target any) bool {
+	return stderrors.As(err, target)
+}
+
+// AsAppError returns the first AppError in err's chain
+func AsAppError(err error) (*AppError, bool) {
+	var appErr *AppError
+	if stderrors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
+
+// TypeOf classifies err by the first AppError in its chain. Other errors are internal
+// errors, and nil has no type.
+func TypeOf(err error) ErrorType {
+	if err == nil {
+		return ""
+	}
+	if appErr, ok := AsAppError(err); ok {
+		return appErr.Type
+	}
+	return ErrorTypeInternal
+}
+
+// IsUnavailable reports whether err is classified as an unavailable error
+func IsUnavailable(err error) bool {
+	return TypeOf(err) == ErrorTypeUnavailable
+}
+
+// IsTimeout reports whether err is classified as a timeout error
+func IsTimeout(err error) bool {
+	return TypeOf(err) == ErrorTypeTimeout
+}
+
+// IsRetryable reports whether err is a temporary dependency failure that clients may
+// retry, as opposed to a problem with the request itself
+func IsRetryable(err error) bool {
+	return IsUnavailable(err) || IsTimeout(err)
+}
+
 // HTTPStatusCode returns the appropriate HTTP status code for the error type
 func (e *AppError) HTTPStatusCode() int {
 	switch e.Type {
@@ -90,23 +268,63 @@ func (e *AppError) HTTPStatusCode() int {
 		return http.StatusConflict
 	case ErrorTypeUnauthorized:
 		return http.StatusUnauthorized
+	case ErrorTypeForbidden:
+		return http.StatusForbidden
+	case ErrorTypeUnavailable:
+		return http.StatusServiceUnavailable
+	case ErrorTypeTimeout:
+		return http.StatusGatewayTimeout
+	case ErrorTypeQuotaExceeded:
+		return http.StatusForbidden
+	case ErrorTypeRateLimited:
+		return http.StatusTooManyRequests
 	default:
 		return http.StatusInternalServerError
 	}
 }
 
+// errorResponse is the JSON body of an error response
+type errorResponse struct {
+	Error errorBody `json:"error"`
+}
+
+type errorBody struct {
+	Type      ErrorType `json:"type"`
+	Message   string    `json:"message"`
+	Details   string    `json:"details"`
+	RequestID string    `json:"requestId,omitempty"`
+}
+
 // WriteError writes the error response to the HTTP response writer
 func WriteError(w http.ResponseWriter, err error) {
-	var appErr *AppError
-	if e, ok := err.(*AppError); ok {
-		appErr = e
-	} else {
+	WriteErrorWithRequestID(w, err, "")
+}
+
+// WriteErrorWithRequestID writes the error response to the HTTP response writer,
+// including the ID of the failed request so clients can quote it when reporting issues
+func WriteErrorWithRequestID(w http.ResponseWriter, err error, requestID string) {
+	appErr, ok := AsAppError(err)
+	if !ok {
 		// Convert unknown errors to internal errors
 		appErr = NewInternalError("An unexpected error occurred", err)
 	}
 
+	body, marshalErr := json.Marshal(errorResponse{Error: errorBody{
+		Type:      appErr.Type,
+		Message:   appErr.Message,
+		Details:   appErr.Details,
+		RequestID: requestID,
+	}})
+	if marshalErr != nil {
+		// Strings always marshal; this only guards against future changes to errorBody
+		body = []byte(`{"error":{"type":"INTERNAL","message":"An unexpected error occurred","details":""}}`)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
+	if appErr.RetryAfter > 0 {
+		seconds := int(math.Ceil(appErr.RetryAfter.Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	}
 	w.WriteHeader(appErr.HTTPStatusCode())
-	fmt.Fprintf(w, `{"error":{"type":"%s","message":"%s","details":"%s"}}`,
-		appErr.Type, appErr.Message, appErr.Details)
+	w.Write(append(body, '\n'))
 }
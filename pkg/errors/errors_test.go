@@ -0,0 +1,155 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDependencyError(t *testing.T) {
+	tests := []struct {
+		name       string
+		cause      error
+		wantType   ErrorType
+		wantStatus int
+		retryable  bool
+	}{
+		{
+			name:       "deadline exceeded",
+			cause:      fmt.Errorf("calendar: %w", context.DeadlineExceeded),
+			wantType:   ErrorTypeTimeout,
+			wantStatus: http.StatusGatewayTimeout,
+			retryable:  true,
+		},
+		{
+			name:       "network timeout",
+			cause:      &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "i/o timeout", IsTimeout: true}},
+			wantType:   ErrorTypeTimeout,
+			wantStatus: http.StatusGatewayTimeout,
+			retryable:  true,
+		},
+		{
+			name:       "connection refused",
+			cause:      fmt.Errorf("smtp: %w", &net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("connection refused")}),
+			wantType:   ErrorTypeUnavailable,
+			wantStatus: http.StatusServiceUnavailable,
+			retryable:  true,
+		},
+		{
+			name:       "unavailable storage",
+			cause:      NewUnavailableError("Storage is temporarily unavailable", 0, nil),
+			wantType:   ErrorTypeUnavailable,
+			wantStatus: http.StatusServiceUnavailable,
+			retryable:  true,
+		},
+		{
+			name:       "other failure",
+			cause:      fmt.Errorf("smtp: 550 mailbox unavailable"),
+			wantType:   ErrorTypeInternal,
+			wantStatus: http.StatusInternalServerError,
+			retryable:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewDependencyError("Failed to send email", tt.cause)
+			assert.Equal(t, tt.wantType, err.Type)
+			assert.Equal(t, tt.wantStatus, err.HTTPStatusCode())
+			assert.Equal(t, tt.retryable, IsRetryable(err))
+			assert.Equal(t, tt.cause, err.Err)
+		})
+	}
+}
+
+func TestAppErrorWrapping(t *testing.T) {
+	cause := fmt.Errorf("connection reset")
+	notFound := NewNotFoundError("Meeting not found")
+	wrapped := fmt.Errorf("loading recommendations: %w", notFound)
+
+	// Sentinels match AppErrors of their type anywhere in the chain
+	assert.True(t, Is(wrapped, ErrNotFound))
+	assert.False(t, Is(wrapped, ErrConflict))
+	assert.True(t, Is(NewConflictError("Slot taken"), ErrConflict))
+	assert.False(t, Is(NewInternalError("Failed", cause), ErrNotFound))
+
+	// The cause of an AppError is reachable through Unwrap
+	internal := NewInternalError("Failed to save", cause)
+	assert.True(t, Is(internal, cause))
+	var opErr *net.OpError
+	assert.True(t, As(NewUnavailableError("Storage down", 0, &net.OpError{Op: "dial"}), &opErr))
+
+	// Classification uses the outermost AppError
+	appErr, ok := AsAppError(wrapped)
+	assert.True(t, ok)
+	assert.Equal(t, notFound, appErr)
+	assert.Equal(t, ErrorTypeNotFound, TypeOf(wrapped))
+	assert.Equal(t, ErrorTypeInternal, TypeOf(cause))
+	assert.Equal(t, ErrorType(""), TypeOf(nil))
+	assert.False(t, IsRetryable(NewInternalError("Failed", NewUnavailableError("Storage down", 0, nil))))
+	assert.True(t, IsRetryable(fmt.Errorf("retrying: %w", NewTimeoutError("Too slow", nil))))
+	assert.True(t, Is(NewQuotaExceededError("Meeting quota exceeded", ""), ErrQuotaExceeded))
+	assert.Equal(t, http.StatusForbidden, NewQuotaExceededError("Meeting quota exceeded", "").HTTPStatusCode())
+	assert.True(t, Is(NewForbiddenError("Only the organizer can change the meeting"), ErrForbidden))
+	assert.Equal(t, http.StatusForbidden, NewForbiddenError("Only the organizer can change the meeting").HTTPStatusCode())
+
+	// Wrapped AppErrors keep their status code
+	w := httptest.NewRecorder()
+	WriteError(w, wrapped)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		requestID string
+		want      errorBody
+	}{
+		{
+			name: "details with quotes, newlines and backslashes",
+			err:  NewValidationError(`Invalid "title"`, "line 1\nline 2\t\\ <b>&</b>"),
+			want: errorBody{
+				Type:    ErrorTypeValidation,
+				Message: `Invalid "title"`,
+				Details: "line 1\nline 2\t\\ <b>&</b>",
+			},
+		},
+		{
+			name:      "request ID",
+			err:       NewNotFoundError("User not found"),
+			requestID: "req-123",
+			want: errorBody{
+				Type:      ErrorTypeNotFound,
+				Message:   "User not found",
+				RequestID: "req-123",
+			},
+		},
+		{
+			name: "unknown error",
+			err:  fmt.Errorf(`unexpected "quote"`),
+			want: errorBody{
+				Type:    ErrorTypeInternal,
+				Message: "An unexpected error occurred",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			WriteErrorWithRequestID(w, tt.err, tt.requestID)
+
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			var resp errorResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.want, resp.Error)
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package logs
 
 import (
-	"fmt"
 	"io"
 	"log"
 	"os"
@@ -43,8 +42,9 @@ var levelValues = map[string]Level{
 
 // Logger is a simple wrapper around Go's log package
 type Logger struct {
-	level  Level
-	logger *log.Logger
+	level     Level
+	redaction Redaction
+	logger    *log.Logger
 }
 
 // New creates a new Logger with the specified minimum level and output
@@ -64,13 +64,20 @@ func New(level string, output io.Writer) *Logger {
 	}
 }
 
+// WithRedaction returns a copy of the logger redacting personal data with mode
+func (l *Logger) WithRedaction(mode Redaction) *Logger {
+	redacting := *l
+	redacting.redaction = mode
+	return &redacting
+}
+
 // log logs a message at the specified level if it's greater than or equal to the logger's level
 func (l *Logger) log(level Level, format string, v ...interface{}) {
 	if level < l.level {
 		return
 	}
 
-	msg := fmt.Sprintf(format, v...)
+	msg := redactMessage(l.redaction, format, v)
 	timestamp := time.Now().Format("2006-01-02 15:04:05")
 	l.logger.Printf("[%s] %s: %s", timestamp, levelNames[level], msg)
 
@@ -0,0 +1,95 @@
+package logs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Redaction selects how personal data is written to logs
+type Redaction string
+
+const (
+	// RedactNone logs personal data as is
+	RedactNone Redaction = "none"
+	// RedactMask keeps only the first character of personal data, and the domain of
+	// email addresses
+	RedactMask Redaction = "mask"
+	// RedactHash replaces personal data with a short hash, so log lines about the same
+	// person can still be correlated
+	RedactHash Redaction = "hash"
+)
+
+// ParseRedaction parses a redaction mode, accepting an empty string for RedactNone
+func ParseRedaction(mode string) (Redaction, error) {
+	switch r := Redaction(strings.ToLower(mode)); r {
+	case "", RedactNone:
+		return RedactNone, nil
+	case RedactMask, RedactHash:
+		return r, nil
+	default:
+		return "", fmt.Errorf("unknown PII redaction mode %q", mode)
+	}
+}
+
+// PII marks a log argument as personal data, such as a name or an email address, so it
+// is redacted when the logger redacts personal data:
+//
+//	logs.Info("Created user: %s (%s)", logs.PII(user.Name), user.ID)
+type PII string
+
+// emailPattern matches email addresses anywhere in a log message
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// redact returns value redacted with mode
+func redact(value string, mode Redaction) string {
+	switch mode {
+	case RedactMask:
+		if strings.Contains(value, "@") {
+			return MaskEmail(value)
+		}
+		if value == "" {
+			return value
+		}
+		_, size := utf8.DecodeRuneInString(value)
+		return value[:size] + "***"
+	case RedactHash:
+		sum := sha256.Sum256([]byte(strings.ToLower(value)))
+		return "pii_" + hex.EncodeToString(sum[:6])
+	default:
+		return value
+	}
+}
+
+// redactMessage redacts PII arguments and, as a safety net, email addresses appearing
+// anywhere in the formatted message, such as in error messages
+func redactMessage(mode Redaction, format string, v []interface{}) string {
+	if mode == "" || mode == RedactNone {
+		return fmt.Sprintf(format, v...)
+	}
+
+	args := make([]interface{}, len(v))
+	for i, arg := range v {
+		if pii, ok := arg.(PII); ok {
+			arg = redact(string(pii), mode)
+		}
+		args[i] = arg
+	}
+	return emailPattern.ReplaceAllStringFunc(fmt.Sprintf(format, args...), func(email string) string {
+		return redact(email, mode)
+	})
+}
+
+// MaskEmail keeps the first character of the local part and the domain of an email
+// address, so the affected user can be narrowed down without logging the address
+func MaskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return "***"
+	}
+	_, size := utf8.DecodeRuneInString(email)
+	return email[:size] + "***" + email[at:]
+}
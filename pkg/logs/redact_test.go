@@ -0,0 +1,50 @@
+package logs
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggerRedaction(t *testing.T) {
+	tests := []struct {
+		mode Redaction
+		want string
+	}{
+		{mode: RedactNone, want: "Created user Jane Doe (jane@example.com) failed: invalid address jane@example.com"},
+		{mode: RedactMask, want: "Created user J*** (j***@example.com) failed: invalid address j***@example.com"},
+		{mode: RedactHash, want: "Created user " + redact("Jane Doe", RedactHash) + " (" + redact("jane@example.com", RedactHash) + ") failed: invalid address " + redact("jane@example.com", RedactHash)},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := New("info", &buf).WithRedaction(tt.mode)
+
+			logger.Info("Created user %s (%s) failed: %v", PII("Jane Doe"), PII("jane@example.com"), "invalid address jane@example.com")
+
+			assert.Contains(t, buf.String(), tt.want)
+		})
+	}
+}
+
+func TestParseRedaction(t *testing.T) {
+	mode, err := ParseRedaction("")
+	assert.NoError(t, err)
+	assert.Equal(t, RedactNone, mode)
+
+	mode, err = ParseRedaction("HASH")
+	assert.NoError(t, err)
+	assert.Equal(t, RedactHash, mode)
+
+	_, err = ParseRedaction("encrypt")
+	assert.Error(t, err)
+}
+
+func TestRedactHash(t *testing.T) {
+	// Hashes are stable and ignore case, so lines about the same person correlate
+	assert.Regexp(t, "^pii_[0-9a-f]{12}$", redact("jane@example.com", RedactHash))
+	assert.Equal(t, redact("jane@example.com", RedactHash), redact("Jane@Example.com", RedactHash))
+	assert.NotEqual(t, redact("jane@example.com", RedactHash), redact("john@example.com", RedactHash))
+}